@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,8 +15,12 @@ import (
 	"reverse-challenge-system/pkg/auth"
 	"reverse-challenge-system/pkg/config"
 	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/events"
 	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/metrics"
+	"reverse-challenge-system/pkg/nonce"
 	"reverse-challenge-system/pkg/sui"
+	"reverse-challenge-system/pkg/version"
 
 	"github.com/gorilla/mux"
 	"github.com/pattonkan/sui-go/suiclient"
@@ -24,47 +30,97 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+var importFile = flag.String("import-file", "", "Import challenges from a JSON array or newline-delimited JSON file, then exit")
+
 func main() {
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
+	// Write service log files to LOG_DIR, rotating them by size, and stdout in LOG_FORMAT
+	logger.SetLogDir(cfg.LogDir)
+	logger.SetFormat(cfg.LogFormat)
+	logger.SetRotation(logger.RotationConfig{
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		MaxAgeDays: cfg.LogMaxAgeDays,
+	})
+
 	// Initialize logger with file output for challenger service
 	logger.InitWithFileLogging(cfg.LogLevel, logger.Challenger)
 
 	// Create startup logger
 	startupLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Challenger, logger.Startup)
 	startupLogger.Info().Msg("Starting Reverse Challenge System - Challenger")
+	startupLogger.Debug().Object("config", cfg.LogSafe()).Msg("Configuration loaded")
 
 	// Initialize database
-	database, err := db.NewChallengerDB(cfg.ChallengerDBPath)
+	sqliteOpts := db.SQLiteOptions{BusyTimeout: cfg.GetSQLiteBusyTimeout(), MaxOpenConns: cfg.SQLiteMaxOpenConns}
+	database, err := db.OpenChallengerStore(cfg.DBDriver, cfg.GetChallengerDSN(), sqliteOpts)
 	if err != nil {
 		startupLogger.Fatal().Err(err).Msg("Failed to initialize database")
 	}
 	defer database.Close()
-	startupLogger.Info().Str("db_path", cfg.ChallengerDBPath).Msg("Database initialized successfully")
+	startupLogger.Info().Str("db_driver", cfg.DBDriver).Msg("Database initialized successfully")
 
 	// Initialize HMAC authentication
 	secrets := cfg.GetChallengerSecrets()
 	hmacAuth := auth.NewHMACAuth(secrets, cfg.GetClockSkew())
-	startupLogger.Info().Int("secret_count", len(secrets)).Msg("HMAC authentication initialized")
+	hmacAuth.SetClockSkew(cfg.GetClockSkewPast(), cfg.GetClockSkewFuture())
+	for _, key := range cfg.ChalHMACKeys {
+		hmacAuth.AddSecretWithValidity(key.KeyID, key.Secret, key.NotBefore, key.NotAfter)
+	}
+	// Keys added or revoked through /admin/keys take precedence over the environment
+	persistedKeys, err := api.LoadHMACKeys(context.Background(), hmacAuth, database)
+	if err != nil {
+		startupLogger.Fatal().Err(err).Msg("Failed to load persisted HMAC keys")
+	}
+	startupLogger.Info().
+		Int("secret_count", len(secrets)).
+		Int("rotation_key_count", len(cfg.ChalHMACKeys)).
+		Int("persisted_key_count", persistedKeys).
+		Msg("HMAC authentication initialized")
+
+	// Import mode only needs the database; it never touches Sui or starts the server
+	if *importFile != "" {
+		code := importChallenges(challenger.NewService(cfg, database, hmacAuth, nil), *importFile)
+		database.Close()
+		logger.CloseLoggers()
+		os.Exit(code)
+	}
 
 	singer, err := suisigner.NewSignerWithMnemonic(cfg.SUI.ChallengerMnemonic, suicrypto.KeySchemeFlagEd25519)
 	if err != nil {
 		panic(err)
 	}
 
-	err = suiclient.RequestFundFromFaucet(singer.Address, cfg.SUI.FaucetRPCUrl)
+	suiRPCURL := conn.LocalnetEndpointUrl
+
+	// Commitment uploads pay gas from this address, so starting unfunded only fails later
+	if cfg.SUI.FaucetRPCUrl != "" {
+		fundCtx, cancelFund := context.WithTimeout(context.Background(), 2*time.Minute)
+		err := sui.FundFromFaucetWithRetry(fundCtx, singer.Address, cfg.SUI.FaucetRPCUrl, sui.DefaultFaucetMinBalance,
+			sui.NewClientBalanceChecker(suiclient.NewClient(suiRPCURL)), startupLogger)
+		cancelFund()
+		if err != nil {
+			startupLogger.Fatal().Err(err).
+				Str("address", singer.Address.String()).
+				Str("faucet_url", cfg.SUI.FaucetRPCUrl).
+				Msg("Failed to fund challenger address from faucet")
+		}
+	}
 
 	// Initialize Sui TransactionBuilder if mnemonic is provided
 	var suiTxBuilder *sui.TransactionBuilder
-	suiRPCURL := conn.LocalnetEndpointUrl
 	suiTxBuilder, err = sui.NewTransactionBuilder(context.Background(), startupLogger, suiRPCURL, cfg.SUI.PackageID, cfg.SUI.ChallengerMnemonic)
 	if err != nil {
 		startupLogger.Error().Err(err).Msg("Failed to initialize Sui TransactionBuilder, continuing without Sui integration")
 	} else {
+		suiTxBuilder.SetRPCMaxRetries(cfg.SUI.RPCMaxRetries)
 		startupLogger.Info().
 			Str("rpc_url", suiRPCURL).
 			Str("package_id", cfg.SUI.PackageID).
@@ -73,32 +129,116 @@ func main() {
 
 	// Initialize service
 	service := challenger.NewService(cfg, database, hmacAuth, suiTxBuilder)
+
+	// Serve reporting endpoints from a read-only handle when configured
+	if readDSN := cfg.GetChallengerReadDSN(); readDSN != "" {
+		readDB, err := db.OpenChallengerReadStore(cfg.DBDriver, readDSN, sqliteOpts)
+		if err != nil {
+			startupLogger.Fatal().Err(err).Msg("Failed to open read-only database")
+		}
+		defer readDB.Close()
+		service.SetReadStore(readDB)
+		startupLogger.Info().Msg("Read-only database handle initialized")
+	}
+
+	// Publish lifecycle events when an event bus is configured
+	publisher, err := events.OpenPublisher(cfg.EventBusDriver, cfg.EventBusURL, cfg.EventBusSubjectPrefix)
+	if err != nil {
+		startupLogger.Fatal().Err(err).Msg("Failed to connect to event bus")
+	}
+	defer publisher.Close()
+	service.SetEventPublisher(publisher)
 	startupLogger.Info().Msg("Challenger service initialized")
 
 	// Initialize middleware
 	middleware := api.NewMiddleware(hmacAuth, database)
+	nonceStore, err := nonce.Open(cfg.NonceStore, cfg.NonceRedisURL, database)
+	if err != nil {
+		startupLogger.Fatal().Err(err).Msg("Failed to open nonce store")
+	}
+	defer nonceStore.Close()
+	middleware.SetNonceStore(nonceStore, cfg.GetNonceTTL())
+	middleware.SetRateLimit(cfg.RateLimitRPS, cfg.RateLimitBurst)
+	if err := middleware.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		startupLogger.Fatal().Err(err).Msg("Invalid trusted proxies")
+	}
+	middleware.SetCORS(cfg.CORSAllowedOrigins, cfg.CORSAllowedMethods, cfg.CORSAllowedHeaders)
+	middleware.SetAdminKey(cfg.AdminAPIKey)
+	middleware.SetBackupDir(cfg.BackupDir, "challenger")
+	middleware.SetKeyRoles(cfg.GetKeyRoles())
 
 	// Create router
 	router := mux.NewRouter()
 
-	// Add middleware
+	// Add middleware (Recover first so panics anywhere in the chain become a 500)
+	router.Use(middleware.Recover)
 	router.Use(middleware.RequestLogging)
-	router.Use(middleware.SizeLimit)
+	router.Use(middleware.Compression(cfg.CompressionMinBytes))
+	router.Use(middleware.Timeout(cfg.GetRequestTimeout()))
+	router.Use(middleware.SizeLimitFor(int64(cfg.MaxRequestBytes), map[string]int64{"/callback": int64(cfg.MaxCallbackBytes)}))
 	router.Use(middleware.CORS)
 
 	// Health endpoints (no auth required)
 	router.HandleFunc("/healthz", api.HealthCheck).Methods("GET")
-	router.HandleFunc("/readyz", api.ReadinessCheck(database)).Methods("GET")
+	router.HandleFunc("/version", version.Handler).Methods("GET")
+	if cfg.SUI.ReadinessProbe && suiTxBuilder != nil {
+		router.HandleFunc("/readyz", api.ReadinessCheckWithSui(database, suiTxBuilder, cfg.GetSuiReadinessTimeout())).Methods("GET")
+	} else {
+		router.HandleFunc("/readyz", api.ReadinessCheck(database)).Methods("GET")
+	}
+
+	// Prometheus metrics (no auth required)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	// Stats endpoint (no auth required for development)
+	router.HandleFunc("/stats", service.HandleStats).Methods("GET")
+
+	// Local copy of callback logs for verifiers (authenticated with LOGS_API_KEY)
+	router.HandleFunc("/api/logs/{id}", service.HandleGetLogEntry).Methods("GET")
 
-	// Callback endpoint (requires HMAC auth)
+	// HMAC key management (authenticated with ADMIN_API_KEY)
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(middleware.RateLimit)
+	adminRouter.HandleFunc("/keys", middleware.HandleListKeys).Methods("GET")
+	adminRouter.HandleFunc("/keys", middleware.HandleUpdateKeys).Methods("POST")
+	adminRouter.HandleFunc("/backup", middleware.HandleBackup).Methods("POST")
+	adminRouter.HandleFunc("/vacuum", middleware.HandleVacuum).Methods("POST")
+
+	// Callback endpoint (requires HMAC auth with the key the solver signs callbacks with)
 	callbackRouter := router.PathPrefix("/callback").Subrouter()
-	callbackRouter.Use(middleware.HMACAuth)
+	callbackRouter.Use(middleware.RateLimit)
+	callbackRouter.Use(middleware.HMACAuthForRole(config.KeyRoleSolver))
 	callbackRouter.HandleFunc("/{challenge_id}", service.HandleCallback).Methods("POST")
 
+	// Challenge inspection endpoints (requires HMAC auth with the challenger's or an operator's key)
+	challengesRouter := router.PathPrefix("/challenges").Subrouter()
+	challengesRouter.Use(middleware.HMACAuthForKeys(cfg.GetInspectionKeyIDs()...))
+	challengesRouter.HandleFunc("/{challenge_id}", service.HandleGetChallenge).Methods("GET")
+	challengesRouter.HandleFunc("/{challenge_id}/results", service.HandleListResults).Methods("GET")
+
+	// Solver commitment history (requires HMAC auth)
+	solversRouter := router.PathPrefix("/solvers").Subrouter()
+	solversRouter.Use(middleware.HMACAuth)
+	solversRouter.HandleFunc("/{address}/commitments", service.HandleListSolverCommitments).Methods("GET")
+
+	// Solver leaderboard (requires HMAC auth)
+	router.Handle("/leaderboard", middleware.HMACAuth(http.HandlerFunc(service.HandleLeaderboard))).Methods("GET")
+
+	// Callback audit trail (requires HMAC auth)
+	router.Handle("/audits", middleware.HMACAuth(http.HandlerFunc(service.HandleListAudits))).Methods("GET")
+
+	// Audit export (authenticated with ADMIN_API_KEY). It streams for as long as the range takes,
+	// so it is served outside the router's Timeout middleware, which buffers whole responses.
+	exportHandler := middleware.Recover(middleware.RequestLogging(middleware.Compression(cfg.CompressionMinBytes)(
+		middleware.SizeLimitN(int64(cfg.MaxRequestBytes))(middleware.RateLimit(http.HandlerFunc(middleware.HandleExport))))))
+	rootMux := http.NewServeMux()
+	rootMux.Handle("POST /admin/export", exportHandler)
+	rootMux.Handle("/", router)
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         cfg.GetChallengerAddr(),
-		Handler:      router,
+		Handler:      rootMux,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -116,9 +256,60 @@ func main() {
 		}
 	}()
 
-	// Start background cleanup goroutine
-	go cleanupNonces(database, cfg)
-	startupLogger.Info().Msg("Background nonce cleanup routine started")
+	// Start background cleanup of nonces and callback audits; memory and Redis stores expire nonces themselves
+	if cfg.NonceStore == nonce.DriverDB || cfg.WebhookAuditRetentionDays > 0 {
+		go cleanupOldRecords(database, cfg)
+		startupLogger.Info().
+			Int("webhook_audit_retention_days", cfg.WebhookAuditRetentionDays).
+			Msg("Background cleanup routine started")
+	}
+
+	// Upload commitments to Sui off the callback path, resuming jobs left from a previous run
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	if suiTxBuilder != nil {
+		// Store log entries once the chain confirms each commitment with its event
+		if cfg.SUI.WSUrl != "" {
+			if err := startCommitmentConfirmations(workerCtx, service, suiTxBuilder, cfg); err != nil {
+				startupLogger.Warn().Err(err).Msg("Failed to subscribe to commitment events, storing log entries without confirmation")
+			} else {
+				startupLogger.Info().Str("ws_url", cfg.SUI.WSUrl).Msg("Commitment event subscription started")
+			}
+		}
+		go service.RunCommitmentWorker(workerCtx)
+		startupLogger.Info().Msg("Sui commitment worker started")
+	}
+
+	// Retry log uploads the log service did not accept when they were recorded
+	if cfg.LogServiceURL != "" && cfg.LogServiceAPIKey != "" {
+		go service.RunLogUploadFlusher(workerCtx)
+		startupLogger.Info().
+			Int("flush_interval_seconds", cfg.LogUploadFlushIntervalSecs).
+			Msg("Log upload flusher started")
+	}
+
+	// Redispatch challenges the solver did not accept when they were sent
+	go service.RunDispatchRetrier(workerCtx)
+	startupLogger.Info().
+		Int("retry_interval_seconds", cfg.DispatchRetryIntervalSecs).
+		Msg("Dispatch retrier started")
+
+	// Commit the best answer of each challenge once its submission window closes
+	if cfg.AnswerSubmissionMode == "best" {
+		go settleSubmissionWindows(service, cfg)
+		startupLogger.Info().
+			Int("submission_window_seconds", cfg.SubmissionWindowSecs).
+			Msg("Submission window settlement routine started")
+	}
+
+	// Mark challenges expired once the deadline sent to solvers has passed
+	go expireChallenges(service, cfg)
+	startupLogger.Info().Msg("Challenge expiry routine started")
+
+	// Reload the log level and HMAC secrets on SIGHUP, without a restart
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go reloadOnHangup(hangup, hmacAuth, middleware, database, cfg)
 
 	// Wait for interrupt signal
 	interrupt := make(chan os.Signal, 1)
@@ -126,6 +317,7 @@ func main() {
 
 	<-interrupt
 	startupLogger.Info().Msg("Shutdown signal received")
+	stopWorkers()
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -137,13 +329,36 @@ func main() {
 
 	startupLogger.Info().Msg("Challenger server stopped")
 
-	// Clean up old log files (keep last 7 days)
-	if err := logger.CleanupOldLogs(7); err != nil {
-		startupLogger.Warn().Err(err).Msg("Failed to cleanup old log files")
+	// Clean up log files older than LOG_MAX_AGE_DAYS (0 keeps them)
+	if cfg.LogMaxAgeDays > 0 {
+		if err := logger.CleanupOldLogs(cfg.LogMaxAgeDays); err != nil {
+			startupLogger.Warn().Err(err).Msg("Failed to cleanup old log files")
+		}
+	}
+
+	// Flush and close the service log file last; nothing is logged to it afterwards
+	if err := logger.CloseLoggers(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to close log files: %v\n", err)
 	}
 }
 
-func cleanupNonces(database *db.ChallengerDB, cfg *config.Config) {
+// startCommitmentConfirmations dials the Sui websocket endpoint and subscribes the service
+// to the commitment events of the configured registry
+func startCommitmentConfirmations(ctx context.Context, service *challenger.Service, suiTxBuilder *sui.TransactionBuilder, cfg *config.Config) error {
+	subscriber, err := sui.DialEventSubscriber(ctx, cfg.SUI.WSUrl)
+	if err != nil {
+		return err
+	}
+	suiTxBuilder.SetEventSubscriber(subscriber)
+
+	if err := service.StartCommitmentConfirmations(ctx); err != nil {
+		subscriber.Close()
+		return err
+	}
+	return nil
+}
+
+func cleanupOldRecords(database db.ChallengerStore, cfg *config.Config) {
 	// Create a general category logger for background tasks
 	cleanupLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Challenger, logger.General)
 
@@ -153,13 +368,127 @@ func cleanupNonces(database *db.ChallengerDB, cfg *config.Config) {
 	for {
 		select {
 		case <-ticker.C:
-			// Clean up nonces older than 2x clock skew
-			olderThan := time.Now().Add(-2 * cfg.GetClockSkew())
-			if err := database.CleanupOldNonces(olderThan); err != nil {
-				cleanupLogger.Error().Err(err).Msg("Failed to cleanup old nonces")
-			} else {
-				cleanupLogger.Debug().Msg("Cleaned up old nonces")
+			// Clean up nonces older than any request that could still be accepted
+			if cfg.NonceStore == nonce.DriverDB {
+				olderThan := time.Now().Add(-cfg.GetNonceTTL())
+				if err := database.CleanupOldNonces(context.Background(), olderThan); err != nil {
+					cleanupLogger.Error().Err(err).Msg("Failed to cleanup old nonces")
+				} else {
+					cleanupLogger.Debug().Msg("Cleaned up old nonces")
+				}
+			}
+
+			// Clean up callback audits past the retention window
+			if retention := cfg.GetWebhookAuditRetention(); retention > 0 {
+				olderThan := time.Now().Add(-retention)
+				if err := database.CleanupOldWebhookAudits(context.Background(), olderThan); err != nil {
+					cleanupLogger.Error().Err(err).Msg("Failed to cleanup old webhook audits")
+				} else {
+					cleanupLogger.Debug().Msg("Cleaned up old webhook audits")
+				}
+			}
+		}
+	}
+}
+
+func settleSubmissionWindows(service *challenger.Service, cfg *config.Config) {
+	settleLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Challenger, logger.General)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := service.SettleClosedWindows(context.Background(), time.Now()); err != nil {
+				settleLogger.Error().Err(err).Msg("Failed to settle submission windows")
+			}
+		}
+	}
+}
+
+func expireChallenges(service *challenger.Service, cfg *config.Config) {
+	expireLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Challenger, logger.General)
+
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := service.ExpireChallenges(context.Background(), time.Now()); err != nil {
+				expireLogger.Error().Err(err).Msg("Failed to expire challenges")
 			}
 		}
 	}
 }
+
+// reloadOnHangup re-reads the configuration on every SIGHUP and applies the new log level
+// and HMAC secrets and key roles. The listener and in-flight requests are left alone; other settings
+// still need a restart.
+func reloadOnHangup(hangup <-chan os.Signal, hmacAuth *auth.HMACAuth, middleware *api.Middleware, database db.HMACKeyStore, cfg *config.Config) {
+	reloadLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Challenger, logger.General)
+
+	previous := api.ConfigHMACKeys{Secrets: cfg.GetChallengerSecrets(), Rotation: cfg.ChalHMACKeys}
+	for range hangup {
+		reloaded, err := config.Load()
+		if err != nil {
+			reloadLogger.Error().Err(err).Msg("Failed to reload configuration, keeping the current one")
+			continue
+		}
+
+		logger.SetLevel(reloaded.LogLevel)
+
+		current := api.ConfigHMACKeys{Secrets: reloaded.GetChallengerSecrets(), Rotation: reloaded.ChalHMACKeys}
+		revoked, err := api.ReloadHMACKeys(context.Background(), hmacAuth, database, previous, current)
+		if err != nil {
+			reloadLogger.Error().Err(err).Msg("Failed to reload HMAC keys")
+			continue
+		}
+		previous = current
+		middleware.SetKeyRoles(reloaded.GetKeyRoles())
+
+		reloadLogger.Info().
+			Str("log_level", reloaded.LogLevel).
+			Int("key_count", len(hmacAuth.ListKeyIDs())).
+			Int("revoked_key_count", revoked).
+			Msg("Configuration reloaded")
+	}
+}
+
+// importChallenges loads the challenge definitions in path, printing one line per record and
+// a summary. Duplicates are skipped, not failures; returns 1 when a record was invalid or
+// could not be stored.
+func importChallenges(service *challenger.Service, path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening import file: %v\n", err)
+		return 1
+	}
+	defer file.Close()
+
+	report, err := service.ImportChallenges(context.Background(), file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error importing challenges: %v\n", err)
+		return 1
+	}
+
+	for _, record := range report.Records {
+		line := fmt.Sprintf("record %d", record.Record)
+		if record.ChallengeID != "" {
+			line += fmt.Sprintf(" (%s)", record.ChallengeID)
+		}
+		line += ": " + record.Status
+		if record.Error != "" {
+			line += ": " + record.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("Imported %d challenges: %d created, %d duplicates skipped, %d invalid, %d failed\n",
+		len(report.Records), report.Created, report.Duplicates, report.Invalid, report.Failed)
+
+	if report.Invalid > 0 || report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -4,12 +4,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/suicrypto"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
@@ -26,11 +32,17 @@ var (
 	network        = flag.String("network", "", "Override default network/chain ID")
 	dryRun         = flag.Bool("dry-run", false, "Perform a dry run without deploying")
 	fundFromFaucet = flag.Bool("fund", true, "Request funds from faucet before deployment")
+	outputFormat   = flag.String("format", "text", "Output format for the deployment summary: text or json")
 )
 
 func main() {
 	flag.Parse()
 
+	if *outputFormat != "text" && *outputFormat != "json" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (use text or json)\n", *outputFormat)
+		os.Exit(2)
+	}
+
 	// Initialize logging
 	if err := initLogging(); err != nil {
 		fmt.Printf("Failed to initialize logging: %v\n", err)
@@ -42,6 +54,7 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
+	log.Debug().Object("config", cfg.LogSafe()).Msg("Configuration loaded")
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
@@ -51,23 +64,80 @@ func main() {
 	options := buildOptions(cfg)
 
 	if *dryRun {
-		log.Info().Msg("Dry run mode - contract deployment will be simulated")
-		// In a real implementation, you might want to validate contracts and configuration
-		log.Info().
-			Str("contracts_path", cfg.ContractsPath).
-			Str("network", cfg.SUI.ChainID).
-			Str("rpc_url", cfg.SUI.RPCUrl).
-			Msg("Configuration validated successfully")
+		log.Info().Msg("Dry run mode - contracts will be built and the publish transaction simulated")
+		if cfg.DeployTarget == "ethereum" {
+			log.Info().
+				Str("bytecode_path", cfg.ETH.BytecodePath).
+				Int64("chain_id", cfg.ETH.ChainID).
+				Str("rpc_url", cfg.ETH.RPCUrl).
+				Msg("Configuration validated successfully")
+			return
+		}
+
+		// The signer only supplies the sender address; nothing is signed or executed
+		signer, err := suisigner.NewSignerWithMnemonic(cfg.SUI.InitializerMnemonic, suicrypto.KeySchemeFlagEd25519)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create signer")
+		}
+
+		path := filepath.Join(cfg.ContractsPath, "sources")
+		if *contractPath != "" {
+			path = *contractPath
+		}
+
+		client := suiclient.NewClient(cfg.SUI.RPCUrl)
+		if err := runDryRun(ctx, os.Stdout, client, signer.Address, path, nil); err != nil {
+			log.Fatal().Err(err).Msg("Dry run failed")
+		}
 		return
 	}
 
 	// Run the initializer
 	log.Info().Msg("Starting contract deployment initialization")
-	if err := initializer.Run(ctx, cfg, options...); err != nil {
+	result, err := initializer.Run(ctx, cfg, options...)
+	if err != nil {
 		log.Fatal().Err(err).Msg("Contract deployment failed")
 	}
 
 	log.Info().Msg("Contract deployment completed successfully")
+
+	if *outputFormat == "json" {
+		if err := writeSummary(os.Stdout, result); err != nil {
+			log.Fatal().Err(err).Msg("Failed to write deployment summary")
+		}
+	}
+}
+
+// writeSummary prints the deployment result as a single JSON object.
+// Logs go to stderr, so stdout carries only the summary for scripting.
+func writeSummary(w io.Writer, result *initializer.Result) error {
+	return json.NewEncoder(w).Encode(initializer.NewSummary(result))
+}
+
+// runDryRun builds the Move package and dry-runs its publish transaction, then prints
+// the report in the selected output format. A nil build uses utils.MoveBuild.
+func runDryRun(ctx context.Context, w io.Writer, client initializer.PublishDryRunner, sender *sui.Address, contractPath string, build initializer.MoveBuilder) error {
+	report, err := initializer.DryRun(ctx, client, sender, contractPath, build)
+	if err != nil {
+		return err
+	}
+
+	if *outputFormat == "json" {
+		return json.NewEncoder(w).Encode(report)
+	}
+
+	_, err = fmt.Fprintf(w, `Dry run succeeded
+  Package root:     %s
+  Modules:          %d
+  Dependencies:     %d
+  Gas budget:       %d MIST
+  Computation cost: %d MIST
+  Storage cost:     %d MIST
+  Storage rebate:   %d MIST
+  Estimated gas:    %d MIST
+`, report.PackageRoot, report.ModuleCount, report.DependencyCount, report.GasBudget,
+		report.ComputationCost, report.StorageCost, report.StorageRebate, report.EstimatedGas)
+	return err
 }
 
 // initLogging sets up structured logging with appropriate level
@@ -116,6 +186,22 @@ func loadConfig() (*config.Config, error) {
 
 // validateDeploymentConfig ensures all required deployment settings are present
 func validateDeploymentConfig(cfg *config.Config) error {
+	if cfg.DeployTarget == "ethereum" {
+		if cfg.ETH.RPCUrl == "" {
+			return fmt.Errorf("ETH_RPC_URL is required for deployment")
+		}
+
+		if cfg.ETH.PrivateKey == "" {
+			return fmt.Errorf("ETH_PRIVATE_KEY is required for deployment")
+		}
+
+		if _, err := os.Stat(cfg.ETH.BytecodePath); err != nil {
+			return fmt.Errorf("contract bytecode not found (ETH_CONTRACT_BYTECODE_PATH=%q): %w", cfg.ETH.BytecodePath, err)
+		}
+
+		return nil
+	}
+
 	if cfg.SUI.RPCUrl == "" {
 		return fmt.Errorf("SUI_RPC_URL is required for deployment")
 	}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"reverse-challenge-system/internal/initializer"
+	"reverse-challenge-system/pkg/config"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/utils"
+)
+
+// mockDeployer returns a fixed deployment result without touching the network
+type mockDeployer struct{}
+
+func (d *mockDeployer) Deploy(ctx context.Context, cfg *config.Config) (*initializer.Result, error) {
+	return &initializer.Result{
+		PackageId:     sui.MustPackageIdFromHex("0x1"),
+		RegistryId:    sui.MustObjectIdFromHex("0x2"),
+		TransactionId: "mock_tx_digest",
+		Network:       cfg.SUI.ChainID,
+		Metadata: map[string]interface{}{
+			"pos_package_id":     "0x3",
+			"neg_package_id":     "0x4",
+			"vault_id":           "0x5",
+			"vault_admin_cap_id": "0x6",
+		},
+	}, nil
+}
+
+func TestWriteSummaryJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	if err := os.WriteFile(".env", nil, 0644); err != nil {
+		t.Fatalf("Failed to create .env: %v", err)
+	}
+
+	cfg := &config.Config{
+		DatabasePath: filepath.Join(tempDir, "initializer.db"),
+	}
+	cfg.SUI.RPCUrl = "http://localhost:9000"
+	cfg.SUI.ChainID = "localnet"
+	cfg.SUI.InitializerMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	result, err := initializer.Run(context.Background(), cfg,
+		initializer.WithFundFromFaucet(false),
+		initializer.WithDeployer(&mockDeployer{}),
+	)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeSummary(&buf, result); err != nil {
+		t.Fatalf("writeSummary() unexpected error: %v", err)
+	}
+
+	var summary map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatalf("Summary is not a JSON object: %v (%s)", err, buf.String())
+	}
+
+	expected := map[string]string{
+		"package_id":         sui.MustPackageIdFromHex("0x1").String(),
+		"registry_id":        sui.MustObjectIdFromHex("0x2").String(),
+		"pos_package_id":     "0x3",
+		"neg_package_id":     "0x4",
+		"vault_id":           "0x5",
+		"vault_admin_cap_id": "0x6",
+		"tx_digest":          "mock_tx_digest",
+		"network":            "localnet",
+	}
+
+	if len(summary) != len(expected) {
+		t.Errorf("Expected %d summary fields, got %d: %v", len(expected), len(summary), summary)
+	}
+	for key, want := range expected {
+		if got := summary[key]; got != want {
+			t.Errorf("Expected %s=%q, got %q", key, want, got)
+		}
+	}
+}
+
+// mockDryRunClient returns canned publish bytes and dry-run effects; it has no signing path
+type mockDryRunClient struct {
+	status     string
+	statusErr  string
+	publishErr error
+
+	publishReq *suiclient.PublishRequest
+	dryRunTx   sui.Base64
+}
+
+func (c *mockDryRunClient) Publish(ctx context.Context, req *suiclient.PublishRequest) (*suiclient.TransactionBytes, error) {
+	c.publishReq = req
+	if c.publishErr != nil {
+		return nil, c.publishErr
+	}
+	return &suiclient.TransactionBytes{TxBytes: sui.Base64{0xca, 0xfe}}, nil
+}
+
+func (c *mockDryRunClient) DryRunTransaction(ctx context.Context, txDataBytes sui.Base64) (*suiclient.DryRunTransactionBlockResponse, error) {
+	c.dryRunTx = txDataBytes
+	res := &suiclient.DryRunTransactionBlockResponse{}
+	res.Effects.Data.V1 = &suiclient.SuiTransactionBlockEffectsV1{
+		Status: suiclient.ExecutionStatus{Status: c.status, Error: c.statusErr},
+		GasUsed: suiclient.GasCostSummary{
+			ComputationCost:         sui.NewBigInt(1000),
+			StorageCost:             sui.NewBigInt(5000),
+			StorageRebate:           sui.NewBigInt(800),
+			NonRefundableStorageFee: sui.NewBigInt(0),
+		},
+	}
+	return res, nil
+}
+
+// newMovePackage creates a package root with Move.toml and a sources directory
+func newMovePackage(t *testing.T) (string, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	sources := filepath.Join(root, "sources")
+	if err := os.Mkdir(sources, 0755); err != nil {
+		t.Fatalf("Failed to create sources dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Move.toml"), []byte("[package]\nname = \"test\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write Move.toml: %v", err)
+	}
+	return root, sources
+}
+
+// stubBuild stands in for utils.MoveBuild so the test does not need the sui CLI
+func stubBuild(builtRoot *string) initializer.MoveBuilder {
+	return func(packageRoot string) (*utils.CompiledMoveModules, error) {
+		*builtRoot = packageRoot
+		return &utils.CompiledMoveModules{
+			Modules:      []*sui.Base64{{0x01}, {0x02}},
+			Dependencies: []*sui.Address{sui.MustAddressFromHex("0x1"), sui.MustAddressFromHex("0x2"), sui.MustAddressFromHex("0x3")},
+		}, nil
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	root, sources := newMovePackage(t)
+	sender := sui.MustAddressFromHex("0xa11ce")
+	client := &mockDryRunClient{status: suiclient.ExecutionStatusSuccess}
+
+	var builtRoot string
+	var buf bytes.Buffer
+	if err := runDryRun(context.Background(), &buf, client, sender, sources, stubBuild(&builtRoot)); err != nil {
+		t.Fatalf("runDryRun() unexpected error: %v", err)
+	}
+
+	if builtRoot != root {
+		t.Errorf("Expected build of package root %s, got %s", root, builtRoot)
+	}
+	if client.publishReq == nil || client.publishReq.Sender.String() != sender.String() {
+		t.Fatalf("Expected publish request from %s, got %+v", sender, client.publishReq)
+	}
+	if len(client.publishReq.CompiledModules) != 2 || len(client.publishReq.Dependencies) != 3 {
+		t.Errorf("Expected publish request with 2 modules and 3 dependencies, got %+v", client.publishReq)
+	}
+	if !bytes.Equal(client.dryRunTx, sui.Base64{0xca, 0xfe}) {
+		t.Errorf("Expected the publish bytes to be dry-run, got %x", client.dryRunTx)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Modules:          2", "Dependencies:     3", "Estimated gas:    5200 MIST"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDryRunJSON(t *testing.T) {
+	_, sources := newMovePackage(t)
+	t.Cleanup(func() { *outputFormat = "text" })
+	*outputFormat = "json"
+
+	var builtRoot string
+	var buf bytes.Buffer
+	client := &mockDryRunClient{status: suiclient.ExecutionStatusSuccess}
+	if err := runDryRun(context.Background(), &buf, client, sui.MustAddressFromHex("0xa11ce"), sources, stubBuild(&builtRoot)); err != nil {
+		t.Fatalf("runDryRun() unexpected error: %v", err)
+	}
+
+	var report initializer.DryRunReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Report is not JSON: %v (%s)", err, buf.String())
+	}
+	if report.ModuleCount != 2 || report.DependencyCount != 3 || report.EstimatedGas != 5200 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestRunDryRunFailures(t *testing.T) {
+	_, sources := newMovePackage(t)
+	sender := sui.MustAddressFromHex("0xa11ce")
+
+	tests := []struct {
+		name         string
+		contractPath string
+		client       *mockDryRunClient
+		build        initializer.MoveBuilder
+		wantErr      string
+	}{
+		{
+			name:         "missing Move.toml",
+			contractPath: t.TempDir(),
+			client:       &mockDryRunClient{status: suiclient.ExecutionStatusSuccess},
+			wantErr:      "Move.toml not found",
+		},
+		{
+			name:         "build failure",
+			contractPath: sources,
+			client:       &mockDryRunClient{status: suiclient.ExecutionStatusSuccess},
+			build: func(string) (*utils.CompiledMoveModules, error) {
+				return nil, errors.New("unbound module")
+			},
+			wantErr: "failed to build Move package: unbound module",
+		},
+		{
+			name:         "publish rejected",
+			contractPath: sources,
+			client:       &mockDryRunClient{publishErr: errors.New("insufficient gas")},
+			wantErr:      "failed to build publish transaction: insufficient gas",
+		},
+		{
+			name:         "dry run fails",
+			contractPath: sources,
+			client:       &mockDryRunClient{status: suiclient.ExecutionStatusFailure, statusErr: "InsufficientGas"},
+			wantErr:      "publish dry run failed: InsufficientGas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := tt.build
+			if build == nil {
+				var builtRoot string
+				build = stubBuild(&builtRoot)
+			}
+
+			var buf bytes.Buffer
+			err := runDryRun(context.Background(), &buf, tt.client, sender, tt.contractPath, build)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("runDryRun() expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("Expected no report on failure, got %q", buf.String())
+			}
+		})
+	}
+}
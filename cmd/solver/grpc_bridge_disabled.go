@@ -5,10 +5,11 @@ package main
 import (
 	"context"
 	"reverse-challenge-system/internal/solver"
+	"reverse-challenge-system/pkg/nonce"
 )
 
 // startBridgeIfEnabled is a no-op when the grpcbridge build tag is not set.
 // It returns nil to indicate the bridge is not running.
-func startBridgeIfEnabled(_ *solver.Service) func(context.Context) error {
+func startBridgeIfEnabled(_ *solver.Service, _ nonce.Store) func(context.Context) error {
 	return nil
 }
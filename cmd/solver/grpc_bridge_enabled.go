@@ -7,18 +7,20 @@ import (
 	"os"
 
 	gbridge "reverse-challenge-system/internal/solver"
+	"reverse-challenge-system/pkg/nonce"
 
 	"github.com/rs/zerolog/log"
 )
 
 // startBridgeIfEnabled starts the gRPC bridge when built with -tags=grpcbridge.
-// Address is controlled via SOLVER_GRPC_BRIDGE_ADDR (default ":9090").
-func startBridgeIfEnabled(svc *gbridge.Service) func(context.Context) error {
+// Address is controlled via SOLVER_GRPC_BRIDGE_ADDR (default ":9090"). Signed solve API
+// calls claim their nonces in nonces, shared with the HTTP API.
+func startBridgeIfEnabled(svc *gbridge.Service, nonces nonce.Store) func(context.Context) error {
 	addr := os.Getenv("SOLVER_GRPC_BRIDGE_ADDR")
 	if addr == "" {
 		addr = ":9090"
 	}
-	stop, err := gbridge.StartGRPCBridge(svc, addr)
+	stop, err := gbridge.StartGRPCBridge(svc, addr, nonces)
 	if err != nil {
 		log.Error().Err(err).Str("addr", addr).Msg("Failed to start gRPC bridge")
 		return nil
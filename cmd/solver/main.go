@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,6 +16,9 @@ import (
 	"reverse-challenge-system/pkg/config"
 	"reverse-challenge-system/pkg/db"
 	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/metrics"
+	"reverse-challenge-system/pkg/nonce"
+	"reverse-challenge-system/pkg/version"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
@@ -27,28 +31,63 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
+	// Write service log files to LOG_DIR, rotating them by size, and stdout in LOG_FORMAT
+	logger.SetLogDir(cfg.LogDir)
+	logger.SetFormat(cfg.LogFormat)
+	logger.SetRotation(logger.RotationConfig{
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		MaxAgeDays: cfg.LogMaxAgeDays,
+	})
+
 	// Initialize logger with file output for solver service
 	logger.InitWithFileLogging(cfg.LogLevel, logger.Solver)
 
 	// Create startup logger
 	startupLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Solver, logger.Startup)
 	startupLogger.Info().Msg("Starting Reverse Challenge System - Solver")
+	startupLogger.Debug().Object("config", cfg.LogSafe()).Msg("Configuration loaded")
 
 	// Initialize database
-	database, err := db.NewSolverDB(cfg.SolverDBPath)
+	sqliteOpts := db.SQLiteOptions{BusyTimeout: cfg.GetSQLiteBusyTimeout(), MaxOpenConns: cfg.SQLiteMaxOpenConns}
+	database, err := db.OpenSolverStore(cfg.DBDriver, cfg.GetSolverDSN(), sqliteOpts)
 	if err != nil {
 		startupLogger.Fatal().Err(err).Msg("Failed to initialize database")
 	}
 	defer database.Close()
-	startupLogger.Info().Str("db_path", cfg.SolverDBPath).Msg("Database initialized successfully")
+	startupLogger.Info().Str("db_driver", cfg.DBDriver).Msg("Database initialized successfully")
 
 	// Initialize HMAC authentication
 	secrets := cfg.GetSolverSecrets()
 	hmacAuth := auth.NewHMACAuth(secrets, cfg.GetClockSkew())
-	startupLogger.Info().Int("secret_count", len(secrets)).Msg("HMAC authentication initialized")
+	hmacAuth.SetClockSkew(cfg.GetClockSkewPast(), cfg.GetClockSkewFuture())
+	for _, key := range cfg.ChalHMACKeys {
+		hmacAuth.AddSecretWithValidity(key.KeyID, key.Secret, key.NotBefore, key.NotAfter)
+	}
+	// Keys added or revoked through /admin/keys take precedence over the environment
+	persistedKeys, err := api.LoadHMACKeys(context.Background(), hmacAuth, database)
+	if err != nil {
+		startupLogger.Fatal().Err(err).Msg("Failed to load persisted HMAC keys")
+	}
+	startupLogger.Info().
+		Int("secret_count", len(secrets)).
+		Int("rotation_key_count", len(cfg.ChalHMACKeys)).
+		Int("persisted_key_count", persistedKeys).
+		Msg("HMAC authentication initialized")
 
 	// Initialize service
 	service := solver.NewService(cfg, database, hmacAuth)
+
+	// Serve stats from a read-only handle when configured
+	if readDSN := cfg.GetSolverReadDSN(); readDSN != "" {
+		readDB, err := db.OpenSolverReadStore(cfg.DBDriver, readDSN, sqliteOpts)
+		if err != nil {
+			startupLogger.Fatal().Err(err).Msg("Failed to open read-only database")
+		}
+		defer readDB.Close()
+		service.SetReadStore(readDB)
+		startupLogger.Info().Msg("Read-only database handle initialized")
+	}
 	startupLogger.Info().Msg("Solver service initialized")
 
 	// Start the worker pool
@@ -56,38 +95,71 @@ func main() {
 	defer service.Stop()
 	startupLogger.Info().Int("worker_count", cfg.SolverWorkerCount).Msg("Worker pool started")
 
+	// Replay protection shared by the HTTP API and the gRPC solve API
+	nonceStore, err := nonce.Open(cfg.NonceStore, cfg.NonceRedisURL, database)
+	if err != nil {
+		startupLogger.Fatal().Err(err).Msg("Failed to open nonce store")
+	}
+	defer nonceStore.Close()
+
 	// Optionally start gRPC bridge (only active when built with -tags=grpcbridge)
-	if stopBridge := startBridgeIfEnabled(service); stopBridge != nil {
+	if stopBridge := startBridgeIfEnabled(service, nonceStore); stopBridge != nil {
 		defer stopBridge(context.Background())
 		startupLogger.Info().Msg("gRPC bridge started")
 	}
 
 	// Initialize middleware
 	middleware := api.NewMiddleware(hmacAuth, database)
+	middleware.SetNonceStore(nonceStore, cfg.GetNonceTTL())
+	middleware.SetRateLimit(cfg.RateLimitRPS, cfg.RateLimitBurst)
+	if err := middleware.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		startupLogger.Fatal().Err(err).Msg("Invalid trusted proxies")
+	}
+	middleware.SetCORS(cfg.CORSAllowedOrigins, cfg.CORSAllowedMethods, cfg.CORSAllowedHeaders)
+	middleware.SetAdminKey(cfg.AdminAPIKey)
+	middleware.SetBackupDir(cfg.BackupDir, "solver")
+	middleware.SetKeyRoles(cfg.GetKeyRoles())
 
 	// Create router
 	router := mux.NewRouter()
 
-	// Add middleware
+	// Add middleware (Recover first so panics anywhere in the chain become a 500)
+	router.Use(middleware.Recover)
 	router.Use(middleware.RequestLogging)
-	router.Use(middleware.SizeLimit)
+	router.Use(middleware.Compression(cfg.CompressionMinBytes))
+	router.Use(middleware.Timeout(cfg.GetRequestTimeout()))
+	router.Use(middleware.SizeLimitFor(int64(cfg.MaxRequestBytes), map[string]int64{"/solve": int64(cfg.MaxSolveBytes)}))
 	router.Use(middleware.CORS)
 
 	// Health endpoints (no auth required)
 	router.HandleFunc("/healthz", api.HealthCheck).Methods("GET")
+	router.HandleFunc("/version", version.Handler).Methods("GET")
 	router.HandleFunc("/readyz", api.ReadinessCheck(database)).Methods("GET")
 
+	// Prometheus metrics (no auth required)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
 	// Stats endpoint (no auth required for development)
 	router.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
-		stats := service.GetStats()
+		stats := service.GetStats(r.Context())
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(stats)
 	}).Methods("GET")
 
-	// Solve endpoint (requires HMAC auth)
+	// HMAC key management (authenticated with ADMIN_API_KEY)
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(middleware.RateLimit)
+	adminRouter.HandleFunc("/keys", middleware.HandleListKeys).Methods("GET")
+	adminRouter.HandleFunc("/keys", middleware.HandleUpdateKeys).Methods("POST")
+	adminRouter.HandleFunc("/backup", middleware.HandleBackup).Methods("POST")
+	adminRouter.HandleFunc("/vacuum", middleware.HandleVacuum).Methods("POST")
+
+	// Solve endpoint (requires HMAC auth with the key the challenger signs solve requests with)
 	solveRouter := router.PathPrefix("/solve").Subrouter()
-	solveRouter.Use(middleware.HMACAuth)
+	solveRouter.Use(middleware.RateLimit)
+	solveRouter.Use(middleware.HMACAuthForRole(config.KeyRoleChallenger))
 	solveRouter.HandleFunc("", service.HandleSolve).Methods("POST")
+	solveRouter.HandleFunc("/{challenge_id}", service.HandleGetStatus).Methods("GET")
 
 	// Create HTTP server
 	server := &http.Server{
@@ -110,9 +182,16 @@ func main() {
 		}
 	}()
 
-	// Start background cleanup goroutine
-	go cleanupNonces(database, cfg)
-	startupLogger.Info().Msg("Background nonce cleanup routine started")
+	// Start background nonce cleanup; memory and Redis stores expire nonces themselves
+	if cfg.NonceStore == nonce.DriverDB {
+		go cleanupNonces(database, cfg)
+		startupLogger.Info().Msg("Background nonce cleanup routine started")
+	}
+
+	// Reload the log level and HMAC secrets on SIGHUP, without a restart
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go reloadOnHangup(hangup, hmacAuth, middleware, database, cfg)
 
 	// Wait for interrupt signal
 	interrupt := make(chan os.Signal, 1)
@@ -125,19 +204,31 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Let workers finish the challenges they are on before the server goes away
+	if err := service.Drain(ctx); err != nil {
+		startupLogger.Warn().Err(err).Msg("Worker pool did not drain before the shutdown deadline")
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		startupLogger.Error().Err(err).Msg("Server shutdown error")
 	}
 
 	startupLogger.Info().Msg("Solver server stopped")
 
-	// Clean up old log files (keep last 7 days)
-	if err := logger.CleanupOldLogs(7); err != nil {
-		startupLogger.Warn().Err(err).Msg("Failed to cleanup old log files")
+	// Clean up log files older than LOG_MAX_AGE_DAYS (0 keeps them)
+	if cfg.LogMaxAgeDays > 0 {
+		if err := logger.CleanupOldLogs(cfg.LogMaxAgeDays); err != nil {
+			startupLogger.Warn().Err(err).Msg("Failed to cleanup old log files")
+		}
+	}
+
+	// Flush and close the service log file last; nothing is logged to it afterwards
+	if err := logger.CloseLoggers(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to close log files: %v\n", err)
 	}
 }
 
-func cleanupNonces(database *db.SolverDB, cfg *config.Config) {
+func cleanupNonces(database db.NonceStore, cfg *config.Config) {
 	// Create a general category logger for background tasks
 	cleanupLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Solver, logger.General)
 
@@ -147,9 +238,9 @@ func cleanupNonces(database *db.SolverDB, cfg *config.Config) {
 	for {
 		select {
 		case <-ticker.C:
-			// Clean up nonces older than 2x clock skew
-			olderThan := time.Now().Add(-2 * cfg.GetClockSkew())
-			if err := database.CleanupOldNonces(olderThan); err != nil {
+			// Clean up nonces older than any request that could still be accepted
+			olderThan := time.Now().Add(-cfg.GetNonceTTL())
+			if err := database.CleanupOldNonces(context.Background(), olderThan); err != nil {
 				cleanupLogger.Error().Err(err).Msg("Failed to cleanup old nonces")
 			} else {
 				cleanupLogger.Debug().Msg("Cleaned up old nonces")
@@ -157,3 +248,36 @@ func cleanupNonces(database *db.SolverDB, cfg *config.Config) {
 		}
 	}
 }
+
+// reloadOnHangup re-reads the configuration on every SIGHUP and applies the new log level
+// and HMAC secrets and key roles. The listener and in-flight requests are left alone; other settings
+// still need a restart.
+func reloadOnHangup(hangup <-chan os.Signal, hmacAuth *auth.HMACAuth, middleware *api.Middleware, database db.HMACKeyStore, cfg *config.Config) {
+	reloadLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Solver, logger.General)
+
+	previous := api.ConfigHMACKeys{Secrets: cfg.GetSolverSecrets(), Rotation: cfg.ChalHMACKeys}
+	for range hangup {
+		reloaded, err := config.Load()
+		if err != nil {
+			reloadLogger.Error().Err(err).Msg("Failed to reload configuration, keeping the current one")
+			continue
+		}
+
+		logger.SetLevel(reloaded.LogLevel)
+
+		current := api.ConfigHMACKeys{Secrets: reloaded.GetSolverSecrets(), Rotation: reloaded.ChalHMACKeys}
+		revoked, err := api.ReloadHMACKeys(context.Background(), hmacAuth, database, previous, current)
+		if err != nil {
+			reloadLogger.Error().Err(err).Msg("Failed to reload HMAC keys")
+			continue
+		}
+		previous = current
+		middleware.SetKeyRoles(reloaded.GetKeyRoles())
+
+		reloadLogger.Info().
+			Str("log_level", reloaded.LogLevel).
+			Int("key_count", len(hmacAuth.ListKeyIDs())).
+			Int("revoked_key_count", revoked).
+			Msg("Configuration reloaded")
+	}
+}
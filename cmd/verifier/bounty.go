@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/rs/zerolog"
+)
+
+// Bounty outcomes reported per digest
+const (
+	bountyNotRequested = "not_requested" // Verified, but --transfer-bounty was not set
+	bountyTransferred  = "transferred"   // Paid by this run
+	bountyAlreadyPaid  = "already_paid"  // The ledger shows an earlier run paid it
+)
+
+// bountyPayer pays the vault bounty for verified commitments at most once, using the
+// bounty_transfers ledger to remember payments across runs
+type bountyPayer struct {
+	ledger   db.BountyLedger
+	vaultID  string
+	transfer func(ctx context.Context, vaultID, solverAddr string) error // Issues the on-chain transfer
+	logger   zerolog.Logger
+}
+
+// pay transfers the bounty for a verified result to the solver named in its commitment unless
+// the ledger already records it, returning bountyTransferred or bountyAlreadyPaid
+func (p *bountyPayer) pay(result *VerificationResult) (string, error) {
+	if result.Commitment == nil || result.Commitment.ID == "" {
+		return "", fmt.Errorf("commitment has no ID to record the bounty against")
+	}
+	if result.Commitment.SolverAddr == "" {
+		return "", fmt.Errorf("commitment %s has no solver address to pay the bounty to", result.Commitment.ID)
+	}
+	commitmentID := result.Commitment.ID
+	solverAddr := result.Commitment.SolverAddr
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	paid, err := p.ledger.HasBountyTransfer(ctx, commitmentID, p.vaultID)
+	if err != nil {
+		return "", err
+	}
+	if paid {
+		p.logger.Info().
+			Str("digest", result.Digest).
+			Str("commitment_id", commitmentID).
+			Msg("Bounty already transferred, skipping")
+		return bountyAlreadyPaid, nil
+	}
+
+	if err := p.transfer(ctx, p.vaultID, solverAddr); err != nil {
+		p.logger.Error().Err(err).
+			Str("digest", result.Digest).
+			Str("VaultID", p.vaultID).
+			Str("solver address", solverAddr).
+			Msg("Failed to transfer bounty to solver")
+		return "", fmt.Errorf("failed to transfer bounty to solver: %w", err)
+	}
+
+	// The transfer went through; a failed record would let a later run pay again, so report it
+	if err := p.ledger.RecordBountyTransfer(ctx, &models.BountyTransfer{
+		CommitmentID:  commitmentID,
+		VaultID:       p.vaultID,
+		Digest:        result.Digest,
+		SolverAddress: solverAddr,
+	}); err != nil {
+		return bountyTransferred, fmt.Errorf("bounty transferred but not recorded, do not re-run before recording it: %w", err)
+	}
+	return bountyTransferred, nil
+}
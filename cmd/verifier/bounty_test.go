@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/rs/zerolog"
+)
+
+func TestBountyPaidOnceAcrossRuns(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "verifier.db")
+	transfers := 0
+
+	run := func() *VerificationReport {
+		ledger, err := db.NewChallengerDB(dbPath)
+		if err != nil {
+			t.Fatalf("failed to open ledger: %v", err)
+		}
+		defer ledger.Close()
+
+		payer := &bountyPayer{
+			ledger:  ledger,
+			vaultID: "0xvault",
+			transfer: func(ctx context.Context, vaultID, solverAddr string) error {
+				transfers++
+				return nil
+			},
+			logger: zerolog.Nop(),
+		}
+		verify := func(digest string) (*VerificationResult, error) {
+			return &VerificationResult{Digest: digest, Commitment: &CommitmentInfo{ID: "0xc1", SolverAddr: "0xsolver"}, Verified: true}, nil
+		}
+		return verifyDigests([]string{"digest_1"}, verify, payer.pay, zerolog.Nop())
+	}
+
+	first := run()
+	if !first.OK() || first.Results[0].Bounty != bountyTransferred {
+		t.Fatalf("expected the first run to pay the bounty, got %+v", first.Results[0])
+	}
+
+	second := run()
+	if !second.OK() || second.Results[0].Bounty != bountyAlreadyPaid {
+		t.Errorf("expected the second run to find the bounty paid, got %+v", second.Results[0])
+	}
+	if transfers != 1 {
+		t.Errorf("expected exactly one transfer across both runs, got %d", transfers)
+	}
+}
+
+// recordingLedger keeps bounty transfers in memory so tests can inspect what was recorded
+type recordingLedger struct {
+	transfers []*models.BountyTransfer
+}
+
+func (l *recordingLedger) HasBountyTransfer(ctx context.Context, commitmentID, vaultID string) (bool, error) {
+	for _, transfer := range l.transfers {
+		if transfer.CommitmentID == commitmentID && transfer.VaultID == vaultID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *recordingLedger) RecordBountyTransfer(ctx context.Context, transfer *models.BountyTransfer) error {
+	l.transfers = append(l.transfers, transfer)
+	return nil
+}
+
+func TestBountyPaidToCommitmentSolver(t *testing.T) {
+	// The solver that committed wins the bounty, not the verifier's own SOLVER_MNEMONIC address
+	ledger := &recordingLedger{}
+	var paidTo string
+	payer := &bountyPayer{
+		ledger:  ledger,
+		vaultID: "0xvault",
+		transfer: func(ctx context.Context, vaultID, solverAddr string) error {
+			paidTo = solverAddr
+			return nil
+		},
+		logger: zerolog.Nop(),
+	}
+
+	result := &VerificationResult{Digest: "digest_1", Commitment: &CommitmentInfo{ID: "0xc1", SolverAddr: "0xwinner"}, Verified: true}
+	if _, err := payer.pay(result); err != nil {
+		t.Fatalf("pay failed: %v", err)
+	}
+	if paidTo != "0xwinner" {
+		t.Errorf("expected the bounty to go to 0xwinner, got %q", paidTo)
+	}
+	if len(ledger.transfers) != 1 || ledger.transfers[0].SolverAddress != "0xwinner" {
+		t.Errorf("expected the ledger to record 0xwinner, got %+v", ledger.transfers)
+	}
+
+	result = &VerificationResult{Digest: "digest_2", Commitment: &CommitmentInfo{ID: "0xc2"}, Verified: true}
+	if _, err := payer.pay(result); err == nil {
+		t.Error("expected an error for a commitment without a solver address")
+	}
+	if len(ledger.transfers) != 1 {
+		t.Errorf("expected nothing recorded for the unpaid commitment, got %d transfers", len(ledger.transfers))
+	}
+}
+
+func TestVerifyDigestsWithoutBountyTransfer(t *testing.T) {
+	verify := func(digest string) (*VerificationResult, error) {
+		return &VerificationResult{Digest: digest, Verified: true}, nil
+	}
+
+	report := verifyDigests([]string{"digest_1"}, verify, nil, zerolog.Nop())
+
+	if !report.OK() || report.Results[0].Bounty != bountyNotRequested {
+		t.Errorf("expected a verified digest with no bounty requested, got %+v", report.Results[0])
+	}
+}
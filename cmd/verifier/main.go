@@ -4,33 +4,38 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/httpclient"
 	"reverse-challenge-system/pkg/logger"
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/scoring"
 	localsui "reverse-challenge-system/pkg/sui"
 
-	"github.com/fardream/go-bcs/bcs"
-	"github.com/pattonkan/sui-go/sui"
-	"github.com/pattonkan/sui-go/suiclient"
-	"github.com/pattonkan/sui-go/suisigner"
-	"github.com/pattonkan/sui-go/suisigner/suicrypto"
 	"github.com/rs/zerolog"
 )
 
 func main() {
 	var (
-		rpcURL     = flag.String("rpc-url", "", "Sui RPC URL (overrides config)")
-		digestFile = flag.String("digest-file", "", "Path to digest file (overrides config)")
-		help       = flag.Bool("help", false, "Show help")
+		rpcURL         = flag.String("rpc-url", "", "Sui RPC URL (overrides config)")
+		digestFile     = flag.String("digest-file", "", "Path to file with newline-separated digests (overrides config)")
+		output         = flag.String("output", outputText, "Output format: text or json")
+		transferBounty = flag.Bool("transfer-bounty", false, "Pay the vault bounty for verified commitments not paid before")
+		help           = flag.Bool("help", false, "Show help")
 	)
+	var digestFlags digestList
+	flag.Var(&digestFlags, "digests", "Digest to verify; repeat the flag to verify several")
 	flag.Parse()
 
 	if *help {
@@ -38,6 +43,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *output != outputText && *output != outputJSON {
+		fmt.Fprintf(os.Stderr, "Error: unsupported --output %q (use text or json)\n", *output)
+		os.Exit(1)
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -45,8 +55,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize logger
+	// Initialize logger in LOG_DIR, rotating its file by size, and stdout in LOG_FORMAT.
+	// In json mode stdout carries only the result, so console logs go to stderr.
+	if *output == outputJSON {
+		logger.SetConsoleOutput(os.Stderr)
+	}
+	logger.SetLogDir(cfg.LogDir)
+	logger.SetFormat(cfg.LogFormat)
+	logger.SetRotation(logger.RotationConfig{
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		MaxAgeDays: cfg.LogMaxAgeDays,
+	})
 	appLogger := logger.NewCategoryLogger(cfg.LogLevel, logger.Challenger, logger.General)
+	appLogger.Debug().Object("config", cfg.LogSafe()).Msg("Configuration loaded")
 
 	// Override config with command line flags if provided
 	if *rpcURL != "" {
@@ -74,94 +96,164 @@ func main() {
 	if err != nil {
 		appLogger.Error().Err(err).Msg("Failed to initialize Sui TransactionBuilder, continuing without Sui integration")
 	} else {
+		suiTxBuilder.SetRPCMaxRetries(cfg.SUI.RPCMaxRetries)
 		appLogger.Info().
 			Str("rpc_url", cfg.SUI.RPCUrl).
 			Str("package_id", cfg.SUI.PackageID).
 			Msg("Sui TransactionBuilder initialized successfully")
 	}
 
-	if cfg.TxDigestFile == "" {
-		fmt.Fprintf(os.Stderr, "Error: TX_DIGEST_FILE not configured and --digest-file not provided\n")
-		os.Exit(1)
-	}
+	// Digests from --digests, plus those in --digest-file when it is given explicitly;
+	// TX_DIGEST_FILE is only read when no --digests are passed
+	var digests []string
+	digests = append(digests, digestFlags...)
+	if len(digestFlags) == 0 || *digestFile != "" {
+		if cfg.TxDigestFile == "" {
+			fmt.Fprintf(os.Stderr, "Error: TX_DIGEST_FILE not configured and neither --digest-file nor --digests provided\n")
+			os.Exit(1)
+		}
 
-	// Read digest from file
-	digest, err := readDigestFromFile(cfg.TxDigestFile)
-	if err != nil {
-		appLogger.Error().Err(err).
-			Str("digest_file", cfg.TxDigestFile).
-			Msg("Failed to read digest from file")
-		fmt.Fprintf(os.Stderr, "Error reading digest file: %v\n", err)
-		os.Exit(1)
+		fileDigests, err := readDigestsFromFile(cfg.TxDigestFile)
+		if err != nil {
+			appLogger.Error().Err(err).
+				Str("digest_file", cfg.TxDigestFile).
+				Msg("Failed to read digests from file")
+			fmt.Fprintf(os.Stderr, "Error reading digest file: %v\n", err)
+			os.Exit(1)
+		}
+		if len(fileDigests) == 0 {
+			appLogger.Error().
+				Str("digest_file", cfg.TxDigestFile).
+				Msg("Digest file is empty")
+			fmt.Fprintf(os.Stderr, "Error: Digest file is empty: %s\n", cfg.TxDigestFile)
+			os.Exit(1)
+		}
+		digests = append(digests, fileDigests...)
 	}
 
-	if digest == "" {
-		appLogger.Error().
-			Str("digest_file", cfg.TxDigestFile).
-			Msg("Digest file is empty")
-		fmt.Fprintf(os.Stderr, "Error: Digest file is empty: %s\n", cfg.TxDigestFile)
-		os.Exit(1)
+	verify := func(digest string) (*VerificationResult, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return verifyDigest(ctx, digest, cfg, appLogger)
 	}
 
-	// Fetch transaction using the official Sui client
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Bounties are only paid on request, and at most once per commitment and vault
+	var payBounty func(result *VerificationResult) (string, error)
+	var ledger *db.ChallengerDB
+	if *transferBounty {
+		if suiTxBuilder == nil {
+			fmt.Fprintf(os.Stderr, "Error: --transfer-bounty requires the Sui TransactionBuilder (check SUI_INITIALIZER_MNEMONIC)\n")
+			os.Exit(1)
+		}
+		var err error
+		ledger, err = db.NewChallengerDB(cfg.DatabasePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening bounty ledger %s: %v\n", cfg.DatabasePath, err)
+			os.Exit(1)
+		}
 
-	appLogger.Info().
-		Str("digest", digest).
-		Str("rpc_url", cfg.SUI.RPCUrl).
-		Msg("Fetching transaction from Sui")
+		payer := &bountyPayer{
+			ledger:  ledger,
+			vaultID: cfg.SUI.VaultID,
+			transfer: func(ctx context.Context, vaultID, solverAddr string) error {
+				return suiTxBuilder.VaultTransferBounty(ctx, vaultID, cfg.SUI.VaultAdminCapID, solverAddr)
+			},
+			logger: appLogger,
+		}
+		payBounty = payer.pay
+	}
 
-	objRes, err := localsui.GetObject(ctx, cfg.SUI.RPCUrl, digest, appLogger)
-	if err != nil {
-		appLogger.Error().Err(err).
-			Str("digest", digest).
-			Str("rpc_url", cfg.SUI.RPCUrl).
-			Msg("Failed to fetch transaction")
-		fmt.Fprintf(os.Stderr, "Error fetching transaction: %v\n", err)
+	report := verifyDigests(digests, verify, payBounty, appLogger)
+	if ledger != nil {
+		ledger.Close()
+	}
+	if err := writeReport(os.Stdout, *output, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+		os.Exit(1)
+	}
+	if !report.OK() {
 		os.Exit(1)
 	}
+}
 
-	// Parse and extract commitment payload
-	commitmentPayload, err := extractCommitmentPayload(objRes)
-	if err != nil {
-		appLogger.Warn().Err(err).
-			Str("digest", digest).
-			Msg("Failed to extract commitment payload, printing raw transaction")
+// Formats accepted by --output
+const (
+	outputText = "text" // Human-readable lines
+	outputJSON = "json" // A single VerificationReport object, for CI
+)
 
-		// Print raw JSON if parsing fails
-		fmt.Printf("Transaction Digest: %s\n", digest)
-		// fmt.Printf("Raw Transaction Data:\n%s\n", string(txData))
-	} else {
-		// Print structured output
-		fmt.Printf("Transaction Digest: %s\n", digest)
-		fmt.Printf("Commitment Payload: %v\n", commitmentPayload)
+// ErrScoreMismatch reports an on-chain score that differs from the locally recomputed one
+var ErrScoreMismatch = errors.New("on-chain score does not match recomputed score")
+
+// ErrCommitmentMismatch reports an on-chain commitment that differs from the hash of the logged answer
+var ErrCommitmentMismatch = errors.New("on-chain commitment does not match recomputed commitment")
+
+// VerificationResult is what the verifier found for one commitment, as printed by --output
+type VerificationResult struct {
+	Digest               string          `json:"digest"`
+	Commitment           *CommitmentInfo `json:"commitment,omitempty"`
+	LogEntry             *LogEntry       `json:"log_entry,omitempty"`
+	RecomputedCommitment string          `json:"recomputed_commitment,omitempty"` // Hex SHA-256 of "<registry id>:<answer>"
+	Verified             bool            `json:"verified"`
+	Bounty               string          `json:"bounty,omitempty"` // Set for verified digests: not_requested, transferred or already_paid
+	Error                string          `json:"error,omitempty"`
+}
+
+// CommitmentInfo holds the decoded on-chain commitment fields
+type CommitmentInfo struct {
+	ID             string `json:"id"`
+	RegistryID     string `json:"registry_id"`
+	ChallengerAddr string `json:"challenger_addr"`
+	SolverAddr     string `json:"solver_addr"`
+	Score          uint64 `json:"score"`
+	Timestamp      uint64 `json:"timestamp"`
+	Commitment     string `json:"commitment"` // Hex
+}
+
+// newCommitmentInfo converts a decoded payload, leaving missing fields empty
+func newCommitmentInfo(payload *localsui.CommitmentPayload) *CommitmentInfo {
+	info := &CommitmentInfo{
+		Score:      payload.Score,
+		Timestamp:  payload.Timestamp,
+		Commitment: hex.EncodeToString(payload.Commitment),
+	}
+	if payload.Id != nil {
+		info.ID = payload.Id.String()
+	}
+	if payload.RegistryId != nil {
+		info.RegistryID = payload.RegistryId.String()
 	}
+	if payload.ChallengerAddr != nil {
+		info.ChallengerAddr = payload.ChallengerAddr.String()
+	}
+	if payload.SolverAddr != nil {
+		info.SolverAddr = payload.SolverAddr.String()
+	}
+	return info
+}
+
+// verifyCommitment fetches the log entry behind a decoded commitment and checks that the logged
+// answer hashes to the on-chain commitment and the on-chain score matches the recomputed one.
+// The returned result is filled in as far as verification got, even when an error is returned.
+func verifyCommitment(digest string, payload *localsui.CommitmentPayload, cfg *config.Config, appLogger zerolog.Logger) (*VerificationResult, error) {
+	res := &VerificationResult{Digest: digest, Commitment: newCommitmentInfo(payload)}
 
 	var result models.Result
 	// Fetch log entry using commitment payload ID
-	if commitmentPayload != nil && commitmentPayload.Id != nil {
-		logID := commitmentPayload.Id.String()
+	if payload.Id != nil {
+		logID := payload.Id.String()
 		appLogger.Info().
 			Str("logID", logID).
 			Msg("Fetching log entry from API")
 
 		entry, err := fetchLogEntry(logID, cfg, appLogger)
 		if err != nil {
-			appLogger.Error().Err(err).
-				Str("logID", logID).
-				Msg("Failed to fetch log entry")
-			fmt.Fprintf(os.Stderr, "Error fetching log entry: %v\n", err)
-			os.Exit(1)
+			return res, fmt.Errorf("failed to fetch log entry: %w", err)
 		}
+		res.LogEntry = entry
 
 		if err := json.Unmarshal([]byte(entry.Log), &result); err != nil {
-			appLogger.Error().Err(err).
-				Str("logID", logID).
-				Str("log", entry.Log).
-				Msg("Failed to unmarshal log entry")
-			fmt.Fprintf(os.Stderr, "Error unmarshaling log entry: %v\n", err)
-			os.Exit(1)
+			return res, fmt.Errorf("failed to unmarshal log entry: %w", err)
 		}
 
 		appLogger.Info().
@@ -174,67 +266,193 @@ func main() {
 	}
 
 	commitment := sha256.Sum256([]byte(fmt.Sprintf("%s:%s", cfg.SUI.RegistryID, result.ReceivedAnswer)))
+	res.RecomputedCommitment = hex.EncodeToString(commitment[:])
+	if !bytes.Equal(commitment[:], payload.Commitment) {
+		return res, ErrCommitmentMismatch
+	}
 
-	if !bytes.Equal(commitment[:], commitmentPayload.Commitment) {
-		appLogger.Error().
-			Str("registry id", cfg.SUI.RegistryID).
-			Msg("Challenge verification failed")
-		fmt.Fprintf(os.Stderr, "Error: Challenge verification failed\n")
-		os.Exit(1)
+	if err := verifyScore(scoring.Default, &result, payload); err != nil {
+		return res, err
 	}
 
+	res.Verified = true
+	return res, nil
+}
+
+// writeResult prints res to w in the given --output format
+func writeResult(w io.Writer, format string, res *VerificationResult) error {
+	if format == outputJSON {
+		return json.NewEncoder(w).Encode(res)
+	}
+
+	fmt.Fprintf(w, "Transaction Digest: %s\n", res.Digest)
+	if res.Commitment != nil {
+		fmt.Fprintf(w, "Commitment ID: %s\n", res.Commitment.ID)
+		fmt.Fprintf(w, "Registry ID: %s\n", res.Commitment.RegistryID)
+		fmt.Fprintf(w, "Challenger: %s\n", res.Commitment.ChallengerAddr)
+		fmt.Fprintf(w, "Solver: %s\n", res.Commitment.SolverAddr)
+		fmt.Fprintf(w, "Score: %d\n", res.Commitment.Score)
+		fmt.Fprintf(w, "Commitment: %s\n", res.Commitment.Commitment)
+	}
+	if res.RecomputedCommitment != "" {
+		fmt.Fprintf(w, "Recomputed Commitment: %s\n", res.RecomputedCommitment)
+	}
+	fmt.Fprintf(w, "Verified: %t\n", res.Verified)
+	if res.Bounty != "" {
+		fmt.Fprintf(w, "Bounty: %s\n", res.Bounty)
+	}
+	if res.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", res.Error)
+	}
+	return nil
+}
+
+// verifyDigest fetches and decodes the commitment object behind digest, then verifies it
+func verifyDigest(ctx context.Context, digest string, cfg *config.Config, appLogger zerolog.Logger) (*VerificationResult, error) {
 	appLogger.Info().
 		Str("digest", digest).
-		Msg("Challenge verification completed successfully")
+		Str("rpc_url", cfg.SUI.RPCUrl).
+		Msg("Fetching transaction from Sui")
 
-	solverSinger, err := suisigner.NewSignerWithMnemonic(cfg.SUI.SolverMnemonic, suicrypto.KeySchemeFlagEd25519)
+	objRes, err := localsui.GetObject(ctx, cfg.SUI.RPCUrl, digest, appLogger)
 	if err != nil {
-		appLogger.Error().Err(err).Msg("Failed to get solver address")
-		os.Exit(1)
+		return &VerificationResult{Digest: digest}, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
-	err = suiTxBuilder.VaultTransferBounty(
-		ctx,
-		cfg.SUI.VaultID,
-		cfg.SUI.VaultAdminCapID,
-		solverSinger.Address.String(),
-	)
+
+	// Parse and extract commitment payload
+	commitmentPayload, err := localsui.DecodeCommitmentPayload(objRes)
 	if err != nil {
-		appLogger.Error().Err(err).
-			Str("VaultID", cfg.SUI.VaultID).
-			Str("VaultAdminCapID,", cfg.SUI.VaultAdminCapID).
-			Str("solver address", solverSinger.Address.String()).
-			Msg("Failed to transfer bounty to solver")
-		fmt.Fprintf(os.Stderr, "Error transfer bounty to solver: %v\n", err)
-		os.Exit(1)
+		return &VerificationResult{Digest: digest}, fmt.Errorf("failed to decode commitment payload: %w", err)
+	}
+
+	return verifyCommitment(digest, commitmentPayload, cfg, appLogger)
+}
+
+// VerificationReport holds the per-digest results of a run and how many passed
+type VerificationReport struct {
+	Results []*VerificationResult `json:"results"`
+	Passed  int                   `json:"passed"`
+	Failed  int                   `json:"failed"`
+}
+
+// OK reports whether every digest verified and, when requested, had its bounty paid
+func (r *VerificationReport) OK() bool {
+	for _, result := range r.Results {
+		if result.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyDigests verifies each digest in turn, calling payBounty only for those that pass;
+// a nil payBounty leaves bounties alone. A failing digest does not stop the others.
+func verifyDigests(digests []string, verify func(digest string) (*VerificationResult, error),
+	payBounty func(result *VerificationResult) (string, error), appLogger zerolog.Logger) *VerificationReport {
+	report := &VerificationReport{Results: make([]*VerificationResult, 0, len(digests))}
+
+	for _, digest := range digests {
+		result, err := verify(digest)
+		if result == nil {
+			result = &VerificationResult{Digest: digest}
+		}
+		report.Results = append(report.Results, result)
+
+		if err != nil {
+			appLogger.Error().Err(err).
+				Str("digest", digest).
+				Msg("Challenge verification failed")
+			result.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		report.Passed++
+
+		appLogger.Info().
+			Str("digest", digest).
+			Msg("Challenge verification completed successfully")
+
+		if payBounty == nil {
+			result.Bounty = bountyNotRequested
+			continue
+		}
+		bounty, err := payBounty(result)
+		result.Bounty = bounty
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	return report
+}
+
+// writeReport prints report to w in the given --output format: one JSON object, or each
+// result followed by a summary line
+func writeReport(w io.Writer, format string, report *VerificationReport) error {
+	if format == outputJSON {
+		return json.NewEncoder(w).Encode(report)
 	}
+
+	for i, result := range report.Results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := writeResult(w, format, result); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "\nSummary: %d passed, %d failed\n", report.Passed, report.Failed)
+	return nil
+}
+
+// digestList collects the repeatable --digests flag
+type digestList []string
+
+func (d *digestList) String() string {
+	return strings.Join(*d, ",")
+}
+
+func (d *digestList) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("digest must not be empty")
+	}
+	*d = append(*d, value)
+	return nil
 }
 
-// readDigestFromFile reads the transaction digest from the specified file
-func readDigestFromFile(path string) (string, error) {
+// verifyScore recomputes the expected score from the logged result and compares it
+// with the score the challenger committed on-chain.
+func verifyScore(scorer scoring.Scorer, result *models.Result, payload *localsui.CommitmentPayload) error {
+	expected := scorer.Score(result)
+	if payload.Score != expected {
+		return fmt.Errorf("%w: on-chain %d, expected %d", ErrScoreMismatch, payload.Score, expected)
+	}
+	return nil
+}
+
+// readDigestsFromFile reads the newline-separated transaction digests in the specified file,
+// skipping blank lines
+func readDigestsFromFile(path string) ([]string, error) {
 	if path == "" {
-		return "", fmt.Errorf("digest file path is empty")
+		return nil, fmt.Errorf("digest file path is empty")
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("digest file does not exist: %s", path)
+			return nil, fmt.Errorf("digest file does not exist: %s", path)
 		}
-		return "", fmt.Errorf("failed to read digest file: %w", err)
+		return nil, fmt.Errorf("failed to read digest file: %w", err)
 	}
 
-	digest := strings.TrimSpace(string(data))
-	return digest, nil
-}
-
-type MoveCommitmentPayload struct {
-	Id             *sui.ObjectId
-	RegistryId     *sui.ObjectId
-	ChallengerAddr *sui.Address
-	SolverAddr     *sui.Address
-	Score          uint64
-	Timestamp      uint64
-	Commitment     []byte
+	var digests []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if digest := strings.TrimSpace(line); digest != "" {
+			digests = append(digests, digest)
+		}
+	}
+	return digests, nil
 }
 
 // LogEntry represents the response from the logs API
@@ -252,25 +470,51 @@ type Result struct {
 	Status         string
 }
 
-// fetchLogEntry fetches log entry from the logs API by ID
+// fetchLogEntry fetches log entry from the logs API by ID.
+// When the logs API fails and LOGS_API_FALLBACK_URL is set, the challenger's
+// local copy is fetched instead.
 func fetchLogEntry(logID string, cfg *config.Config, appLogger zerolog.Logger) (*LogEntry, error) {
-	if cfg.LogsAPIBaseURL == "" {
+	if cfg.LogsAPIBaseURL == "" && cfg.LogsAPIFallbackURL == "" {
 		return nil, fmt.Errorf("LOGS_API_BASE_URL not configured")
 	}
 	if cfg.LogsAPIKey == "" {
 		return nil, fmt.Errorf("LOGS_API_KEY not configured")
 	}
 
-	url := fmt.Sprintf("%s/api/logs/%s", cfg.LogsAPIBaseURL, logID)
+	client := httpclient.New(cfg.HTTPClient)
+
+	var lastErr error
+	for _, baseURL := range []string{cfg.LogsAPIBaseURL, cfg.LogsAPIFallbackURL} {
+		if baseURL == "" {
+			continue
+		}
+
+		entry, err := fetchLogEntryFrom(client, baseURL, logID, cfg.LogsAPIKey, appLogger)
+		if err == nil {
+			return entry, nil
+		}
+
+		appLogger.Warn().Err(err).
+			Str("logID", logID).
+			Str("baseURL", baseURL).
+			Msg("Failed to fetch log entry from source")
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// fetchLogEntryFrom fetches a log entry from a single logs API base URL
+func fetchLogEntryFrom(client *http.Client, baseURL, logID, apiKey string, appLogger zerolog.Logger) (*LogEntry, error) {
+	url := fmt.Sprintf("%s/api/logs/%s", baseURL, logID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("X-API-Key", cfg.LogsAPIKey)
+	req.Header.Set("X-API-Key", apiKey)
 
-	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -299,16 +543,6 @@ func fetchLogEntry(logID string, cfg *config.Config, appLogger zerolog.Logger) (
 	return &entry, nil
 }
 
-// extractCommitmentPayload attempts to extract commitment-related data from the transaction
-func extractCommitmentPayload(objRes *suiclient.SuiObjectResponse) (*MoveCommitmentPayload, error) {
-	var moveCommitmentPayload MoveCommitmentPayload
-	_, err := bcs.Unmarshal(objRes.Data.Bcs.Data.MoveObject.BcsBytes, &moveCommitmentPayload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal to MoveCommitmentPayload: %w", err)
-	}
-	return &moveCommitmentPayload, nil
-}
-
 // showUsage displays help information
 func showUsage() {
 	fmt.Printf(`verifier - Sui transaction verifier for challenge commitments
@@ -318,15 +552,23 @@ Usage:
 
 Options:
   --rpc-url string      Sui RPC URL (overrides SUI_RPC_URL config)
-  --digest-file string  Path to digest file (overrides TX_DIGEST_FILE config)
+  --digest-file string  Path to file with newline-separated digests (overrides TX_DIGEST_FILE config)
+  --digests string      Digest to verify; repeatable. TX_DIGEST_FILE is then only read if --digest-file is also given
+  --output string       Output format: text (default) or json, a single report object for CI
+  --transfer-bounty     Pay the vault bounty for each verified commitment (default false). Payments
+                        are recorded in DATABASE_PATH so a commitment is never paid twice
   --help               Show this help message
 
 Environment Variables:
   SUI_RPC_URL              Sui RPC endpoint URL
-  TX_DIGEST_FILE           Path to file containing transaction digest
+  TX_DIGEST_FILE           Path to file containing transaction digests, one per line
   SUI_INITIALIZER_MNEMONIC Mnemonic for transaction signing (enables TransactionBuilder)
   SUI_PACKAGE_ID           Package ID (required for TransactionBuilder)
   SUI_REGISTRY_ID          Registry ID (required for verification)
+  LOGS_API_BASE_URL        Logs API base URL for fetching callback logs
+  LOGS_API_KEY             API key for the logs API (also accepted by the challenger)
+  LOGS_API_FALLBACK_URL    Challenger base URL used when the logs API is unavailable
+  DATABASE_PATH            SQLite database holding the bounty ledger (default: challenger.db)
 
 Examples:
   # Use config from environment to verify a transaction
@@ -338,6 +580,15 @@ Examples:
   # Override digest file
   verifier --digest-file ./custom_digest.txt
 
+  # Verify and pay bounties not paid by an earlier run
+  verifier --transfer-bounty
+
+  # Verify specific digests
+  verifier --digests <digest1> --digests <digest2>
+
+  # Machine-readable report
+  verifier --output=json | jq '.failed'
+
   # Override both
   verifier --rpc-url https://fullnode.testnet.sui.io:443 --digest-file ./custom_digest.txt
 `)
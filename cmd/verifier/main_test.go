@@ -1,13 +1,30 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/scoring"
+	localsui "reverse-challenge-system/pkg/sui"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/rs/zerolog"
 )
 
-func TestReadDigestFromFile(t *testing.T) {
+func TestReadDigestsFromFile(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "verifier-test-*")
 	if err != nil {
@@ -25,17 +42,17 @@ func TestReadDigestFromFile(t *testing.T) {
 	}
 
 	// Test reading digest
-	digest, err := readDigestFromFile(digestFile)
+	digests, err := readDigestsFromFile(digestFile)
 	if err != nil {
-		t.Fatalf("readDigestFromFile failed: %v", err)
+		t.Fatalf("readDigestsFromFile failed: %v", err)
 	}
 
-	if digest != testDigest {
-		t.Errorf("expected digest %q, got %q", testDigest, digest)
+	if len(digests) != 1 || digests[0] != testDigest {
+		t.Errorf("expected digests [%q], got %q", testDigest, digests)
 	}
 }
 
-func TestReadDigestFromFileWithWhitespace(t *testing.T) {
+func TestReadDigestsFromFileWithWhitespace(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "verifier-test-*")
 	if err != nil {
@@ -54,26 +71,26 @@ func TestReadDigestFromFileWithWhitespace(t *testing.T) {
 	}
 
 	// Test reading digest
-	digest, err := readDigestFromFile(digestFile)
+	digests, err := readDigestsFromFile(digestFile)
 	if err != nil {
-		t.Fatalf("readDigestFromFile failed: %v", err)
+		t.Fatalf("readDigestsFromFile failed: %v", err)
 	}
 
-	if digest != testDigest {
-		t.Errorf("expected digest %q, got %q", testDigest, digest)
+	if len(digests) != 1 || digests[0] != testDigest {
+		t.Errorf("expected digests [%q], got %q", testDigest, digests)
 	}
 }
 
-func TestReadDigestFromFileNotExists(t *testing.T) {
+func TestReadDigestsFromFileNotExists(t *testing.T) {
 	// Test reading from non-existent file
 	nonExistentFile := "/path/that/does/not/exist/digest.txt"
-	digest, err := readDigestFromFile(nonExistentFile)
+	digests, err := readDigestsFromFile(nonExistentFile)
 	if err == nil {
 		t.Fatal("expected error when reading non-existent file, got nil")
 	}
 
-	if digest != "" {
-		t.Errorf("expected empty digest, got %q", digest)
+	if len(digests) != 0 {
+		t.Errorf("expected no digests, got %q", digests)
 	}
 
 	expectedErr := "digest file does not exist"
@@ -82,7 +99,7 @@ func TestReadDigestFromFileNotExists(t *testing.T) {
 	}
 }
 
-func TestReadDigestFromFileEmpty(t *testing.T) {
+func TestReadDigestsFromFileEmpty(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "verifier-test-*")
 	if err != nil {
@@ -99,25 +116,25 @@ func TestReadDigestFromFileEmpty(t *testing.T) {
 	}
 
 	// Test reading from empty file
-	digest, err := readDigestFromFile(digestFile)
+	digests, err := readDigestsFromFile(digestFile)
 	if err != nil {
-		t.Fatalf("readDigestFromFile failed: %v", err)
+		t.Fatalf("readDigestsFromFile failed: %v", err)
 	}
 
-	if digest != "" {
-		t.Errorf("expected empty digest from empty file, got %q", digest)
+	if len(digests) != 0 {
+		t.Errorf("expected no digests from empty file, got %q", digests)
 	}
 }
 
-func TestReadDigestFromFileEmptyPath(t *testing.T) {
+func TestReadDigestsFromFileEmptyPath(t *testing.T) {
 	// Test reading with empty path
-	digest, err := readDigestFromFile("")
+	digests, err := readDigestsFromFile("")
 	if err == nil {
 		t.Fatal("expected error when path is empty, got nil")
 	}
 
-	if digest != "" {
-		t.Errorf("expected empty digest, got %q", digest)
+	if len(digests) != 0 {
+		t.Errorf("expected no digests, got %q", digests)
 	}
 
 	expectedErr := "digest file path is empty"
@@ -126,7 +143,7 @@ func TestReadDigestFromFileEmptyPath(t *testing.T) {
 	}
 }
 
-func TestReadDigestFromFileWhitespaceOnly(t *testing.T) {
+func TestReadDigestsFromFileWhitespaceOnly(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "verifier-test-*")
 	if err != nil {
@@ -143,12 +160,227 @@ func TestReadDigestFromFileWhitespaceOnly(t *testing.T) {
 	}
 
 	// Test reading from whitespace-only file
-	digest, err := readDigestFromFile(digestFile)
+	digests, err := readDigestsFromFile(digestFile)
+	if err != nil {
+		t.Fatalf("readDigestsFromFile failed: %v", err)
+	}
+
+	if len(digests) != 0 {
+		t.Errorf("expected no digests from whitespace-only file, got %q", digests)
+	}
+}
+
+func TestVerifyScore(t *testing.T) {
+	correct := &models.Result{ReceivedAnswer: "42", IsCorrect: true}
+
+	if err := verifyScore(scoring.Default, correct, &localsui.CommitmentPayload{Score: scoring.MaxScore}); err != nil {
+		t.Errorf("expected matching score to verify, got %v", err)
+	}
+
+	// A challenger that committed full marks for a wrong answer must be caught
+	incorrect := &models.Result{ReceivedAnswer: "41", IsCorrect: false}
+	err := verifyScore(scoring.Default, incorrect, &localsui.CommitmentPayload{Score: scoring.MaxScore})
+	if !errors.Is(err, ErrScoreMismatch) {
+		t.Fatalf("expected ErrScoreMismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "on-chain 100, expected 0") {
+		t.Errorf("expected error to report both scores, got %q", err.Error())
+	}
+}
+
+func TestFetchLogEntryFallsBackToChallenger(t *testing.T) {
+	logsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer logsAPI.Close()
+
+	challenger := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/logs/ch_1:req_1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "logs-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"ch_1:req_1","log":"{}"}`))
+	}))
+	defer challenger.Close()
+
+	cfg := &config.Config{
+		LogsAPIBaseURL:     logsAPI.URL,
+		LogsAPIKey:         "logs-key",
+		LogsAPIFallbackURL: challenger.URL,
+	}
+
+	entry, err := fetchLogEntry("ch_1:req_1", cfg, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("expected fallback fetch to succeed, got %v", err)
+	}
+	if entry.ID != "ch_1:req_1" {
+		t.Errorf("expected entry ch_1:req_1, got %q", entry.ID)
+	}
+
+	cfg.LogsAPIFallbackURL = ""
+	if _, err := fetchLogEntry("ch_1:req_1", cfg, zerolog.Nop()); err == nil {
+		t.Error("expected error without a fallback URL")
+	}
+}
+
+func TestVerifyCommitmentJSONOutput(t *testing.T) {
+	const registryID = "0xregistry"
+	commitmentID := sui.MustObjectIdFromHex("0x42")
+
+	logsAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/logs/"+commitmentID.String() {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(LogEntry{
+			ID:  commitmentID.String(),
+			Log: `{"challenge_id":"ch_1","received_answer":"42","is_correct":true,"status":"success"}`,
+		})
+	}))
+	defer logsAPI.Close()
+
+	expected := sha256.Sum256([]byte(registryID + ":42"))
+	payload := &localsui.CommitmentPayload{
+		Id:         commitmentID,
+		SolverAddr: sui.MustAddressFromHex("0x7"),
+		Score:      scoring.MaxScore,
+		Timestamp:  1700000000,
+		Commitment: expected[:],
+	}
+	cfg := &config.Config{LogsAPIBaseURL: logsAPI.URL, LogsAPIKey: "logs-key"}
+	cfg.SUI.RegistryID = registryID
+
+	result, err := verifyCommitment("digest_1", payload, cfg, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("expected verification to succeed, got %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := writeResult(&out, outputJSON, result); err != nil {
+		t.Fatalf("writeResult() unexpected error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected a single JSON object, got %q: %v", out.String(), err)
+	}
+	if decoded["digest"] != "digest_1" || decoded["verified"] != true {
+		t.Errorf("unexpected digest or verified flag: %v", decoded)
+	}
+	if decoded["recomputed_commitment"] != hex.EncodeToString(expected[:]) {
+		t.Errorf("expected recomputed commitment %x, got %v", expected, decoded["recomputed_commitment"])
+	}
+	commitment, ok := decoded["commitment"].(map[string]interface{})
+	if !ok || commitment["id"] != commitmentID.String() || commitment["score"] != float64(scoring.MaxScore) ||
+		commitment["solver_addr"] != sui.MustAddressFromHex("0x7").String() {
+		t.Errorf("unexpected commitment fields: %v", decoded["commitment"])
+	}
+	entry, ok := decoded["log_entry"].(map[string]interface{})
+	if !ok || entry["id"] != commitmentID.String() || !strings.Contains(entry["log"].(string), `"received_answer":"42"`) {
+		t.Errorf("unexpected log entry: %v", decoded["log_entry"])
+	}
+	if _, ok := decoded["error"]; ok {
+		t.Errorf("expected no error field on success, got %v", decoded["error"])
+	}
+
+	// A tampered commitment is reported as unverified
+	payload.Commitment = []byte("tampered")
+	result, err = verifyCommitment("digest_1", payload, cfg, zerolog.Nop())
+	if !errors.Is(err, ErrCommitmentMismatch) || result.Verified {
+		t.Errorf("expected ErrCommitmentMismatch and verified=false, got %v, %t", err, result.Verified)
+	}
+}
+
+func TestVerifyDigestsFromFixture(t *testing.T) {
+	digests, err := readDigestsFromFile(filepath.Join("testdata", "digests.txt"))
 	if err != nil {
-		t.Fatalf("readDigestFromFile failed: %v", err)
+		t.Fatalf("readDigestsFromFile failed: %v", err)
+	}
+	if len(digests) != 3 {
+		t.Fatalf("expected 3 digests from the fixture, got %q", digests)
+	}
+
+	// The second commitment does not match its log entry
+	failing := digests[1]
+	verify := func(digest string) (*VerificationResult, error) {
+		if digest == failing {
+			return &VerificationResult{Digest: digest}, ErrCommitmentMismatch
+		}
+		return &VerificationResult{Digest: digest, Verified: true}, nil
+	}
+	var paid []string
+	payBounty := func(result *VerificationResult) (string, error) {
+		paid = append(paid, result.Digest)
+		return bountyTransferred, nil
+	}
+
+	report := verifyDigests(digests, verify, payBounty, zerolog.Nop())
+
+	if report.Passed != 2 || report.Failed != 1 || report.OK() {
+		t.Errorf("expected 2 passed, 1 failed and a failing run, got %d, %d, OK=%t", report.Passed, report.Failed, report.OK())
+	}
+	if strings.Join(paid, ",") != digests[0]+","+digests[2] {
+		t.Errorf("expected bounties only for passing digests, got %q", paid)
+	}
+	if report.Results[1].Error != ErrCommitmentMismatch.Error() || report.Results[1].Bounty != "" {
+		t.Errorf("unexpected result for the failing digest: %+v", report.Results[1])
+	}
+
+	var out bytes.Buffer
+	if err := writeReport(&out, outputJSON, report); err != nil {
+		t.Fatalf("writeReport() unexpected error: %v", err)
+	}
+	var decoded VerificationReport
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected a single JSON object, got %q: %v", out.String(), err)
+	}
+	if len(decoded.Results) != 3 || decoded.Passed != 2 || decoded.Failed != 1 {
+		t.Errorf("unexpected JSON report: %s", out.String())
+	}
+
+	out.Reset()
+	if err := writeReport(&out, outputText, report); err != nil {
+		t.Fatalf("writeReport() unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Summary: 2 passed, 1 failed") {
+		t.Errorf("expected a summary line, got:\n%s", out.String())
+	}
+}
+
+func TestVerifyDigestsReportsBountyFailure(t *testing.T) {
+	verify := func(digest string) (*VerificationResult, error) {
+		return &VerificationResult{Digest: digest, Verified: true}, nil
+	}
+	payBounty := func(result *VerificationResult) (string, error) {
+		return "", errors.New("vault is empty")
+	}
+
+	report := verifyDigests([]string{"0x1"}, verify, payBounty, zerolog.Nop())
+
+	if report.Passed != 1 || report.OK() {
+		t.Errorf("expected a verified digest with a failed transfer to fail the run, got %+v", report)
+	}
+	if report.Results[0].Bounty != "" || report.Results[0].Error != "vault is empty" {
+		t.Errorf("unexpected result: %+v", report.Results[0])
+	}
+}
+
+func TestDigestListFlag(t *testing.T) {
+	var digests digestList
+	fs := flag.NewFlagSet("verifier", flag.ContinueOnError)
+	fs.Var(&digests, "digests", "")
+
+	if err := fs.Parse([]string{"--digests", "0x1", "--digests", " 0x2 "}); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if digests.String() != "0x1,0x2" {
+		t.Errorf("expected digests 0x1,0x2, got %s", digests.String())
 	}
 
-	if digest != "" {
-		t.Errorf("expected empty digest from whitespace-only file, got %q", digest)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse([]string{"--digests", " "}); err == nil {
+		t.Error("expected an empty digest to be rejected")
 	}
 }
@@ -26,6 +26,17 @@ module ctf::ctf_events {
         negative_tokens_burned: u64,
         collateral_withdrawn: u64,
     }
+
+    /// Event emitted when a challenge commitment is uploaded
+    public struct CommitmentUploaded has copy, drop {
+        commitment_id: ID,
+        registry_id: ID,
+        challenger_addr: address,
+        solver_addr: address,
+        score: u64,
+        timestamp: u64,
+        commitment: vector<u8>,
+    }
     
     
     
@@ -75,5 +86,26 @@ module ctf::ctf_events {
             collateral_withdrawn,
         });
     }
+
+    /// Emit a commitment uploaded event
+    public(package) fun emit_commitment_uploaded(
+        commitment_id: ID,
+        registry_id: ID,
+        challenger_addr: address,
+        solver_addr: address,
+        score: u64,
+        timestamp: u64,
+        commitment: vector<u8>,
+    ) {
+        event::emit(CommitmentUploaded {
+            commitment_id,
+            registry_id,
+            challenger_addr,
+            solver_addr,
+            score,
+            timestamp,
+            commitment,
+        });
+    }
     
 }
\ No newline at end of file
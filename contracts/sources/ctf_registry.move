@@ -149,7 +149,7 @@ module ctf::ctf_registry {
         timestamp: u64,
         ctx: &mut TxContext,
     ): ChallengeCommitment {
-        ChallengeCommitment {
+        let challenge_commitment = ChallengeCommitment {
             id: object::new(ctx),
             registry_id: object::id(registry),
             challenger_addr: challenger_addr,
@@ -157,7 +157,19 @@ module ctf::ctf_registry {
             score: score,
             timestamp: timestamp,
             commitment: commitment,
-        }
+        };
+
+        ctf_events::emit_commitment_uploaded(
+            object::id(&challenge_commitment),
+            challenge_commitment.registry_id,
+            challenger_addr,
+            solver_addr,
+            score,
+            timestamp,
+            challenge_commitment.commitment,
+        );
+
+        challenge_commitment
     }
 
     public struct Vault has key, store {
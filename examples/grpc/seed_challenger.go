@@ -27,20 +27,27 @@ import (
 //
 //	go run examples/grpc/seed_challenger.go --challenge-id ch_123 --answer "MOCK_ANSWER"
 //	go run examples/grpc/seed_challenger.go --challenge-id ch_abc --type text --text "hello world" --answer "HELLO WORLD"
+//	go run examples/grpc/seed_challenger.go --challenge-id ch_123 --job-id my_job_1
 func main() {
 	var (
 		challengeID string
 		ctype       string
 		text        string
 		answer      string
+		jobID       string
 	)
 
 	flag.StringVar(&challengeID, "challenge-id", "ch_local_e2e", "Challenge ID to seed into challenger.db")
 	flag.StringVar(&ctype, "type", "text", "Challenge type (text|math|captcha)")
 	flag.StringVar(&text, "text", "demo", "Problem text (for type=text)")
 	flag.StringVar(&answer, "answer", "MOCK_ANSWER", "Expected answer (used for ExactMatch validation)")
+	flag.StringVar(&jobID, "job-id", "", "Solver job ID to accept callbacks for (default solver_job_<challenge-id>)")
 	flag.Parse()
 
+	if jobID == "" {
+		jobID = "solver_job_" + challengeID
+	}
+
 	// Load configuration (.env) to get DB paths and secrets
 	cfg, err := config.Load()
 	if err != nil {
@@ -71,8 +78,13 @@ func main() {
 	hmacAuth := auth.NewHMACAuth(cfg.GetChallengerSecrets(), cfg.GetClockSkew())
 	svc := challenger.NewService(cfg, cdb, hmacAuth, suiTxBuilder)
 
+	// Register the job id the gRPC client will submit; the Challenger rejects callbacks for unknown jobs
+	if err := cdb.SaveDispatchedJob(context.Background(), challengeID, jobID); err != nil {
+		log.Fatalf("failed to record dispatched job: %v", err)
+	}
+
 	// If challenge already exists, skip to keep seeding idempotent
-	if existing, _ := cdb.GetChallenge(challengeID); existing != nil {
+	if existing, _ := cdb.GetChallenge(context.Background(), challengeID); existing != nil {
 		fmt.Printf("Challenge %q already exists in %s; skipping.\n", challengeID, cfg.ChallengerDBPath)
 		return
 	}
@@ -100,7 +112,7 @@ func main() {
 		CreatedAt:      time.Now(),
 	}
 
-	if err := svc.CreateChallenge(ch); err != nil {
+	if err := svc.CreateChallenge(context.Background(), ch); err != nil {
 		log.Fatalf("failed to create challenge: %v", err)
 	}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -46,7 +47,7 @@ func main() {
 	defer database.Close()
 
 	// If challenge already exists, skip to keep seeding idempotent
-	if existing, _ := database.GetChallenge(challengeID); existing != nil {
+	if existing, _ := database.GetChallenge(context.Background(), challengeID); existing != nil {
 		fmt.Printf("Pending challenge %q already exists in %s; skipping.\n", challengeID, solverDB)
 		return
 	}
@@ -67,7 +68,7 @@ func main() {
 		NextRetryTime: time.Now(),
 	}
 
-	if err := database.SaveChallenge(challenge); err != nil {
+	if err := database.SaveChallenge(context.Background(), challenge); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to save pending challenge: %v\n", err)
 		os.Exit(1)
 	}
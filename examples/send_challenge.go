@@ -80,7 +80,7 @@ func main() {
 		fmt.Printf("Creating %s...\n", example.name)
 
 		// Save challenge to database
-		if err := service.CreateChallenge(example.challenge); err != nil {
+		if err := service.CreateChallenge(context.Background(), example.challenge); err != nil {
 			log.Printf("Failed to create challenge %s: %v", example.name, err)
 			continue
 		}
@@ -89,7 +89,7 @@ func main() {
 
 		// Send challenge to solver (adjust URL as needed)
 		solverURL := fmt.Sprintf("http://localhost:%s", cfg.SolverPort)
-		if err := service.SendChallenge(example.challenge.ID, solverURL); err != nil {
+		if err := service.SendChallenge(context.Background(), example.challenge.ID, solverURL); err != nil {
 			log.Printf("Failed to send challenge %s: %v", example.name, err)
 		} else {
 			fmt.Printf("Challenge sent successfully!\n")
@@ -0,0 +1,159 @@
+package challenger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"reverse-challenge-system/pkg/events"
+	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/sui"
+
+	"github.com/rs/zerolog"
+)
+
+// commitmentConfirmations pairs uploaded commitments with the CommitmentUploaded events
+// that confirm them. Either side may arrive first: the upload returns once the node has
+// executed the transaction, which can be after its event was pushed to the subscription.
+type commitmentConfirmations struct {
+	mu        sync.Mutex
+	timeout   time.Duration
+	pending   map[string]*pendingConfirmation // Uploaded commitments awaiting their event, by object ID
+	confirmed map[string]earlyConfirmation    // Events that arrived before their upload returned, by object ID
+}
+
+// earlyConfirmation is an event seen before its upload returned
+type earlyConfirmation struct {
+	txDigest   string
+	receivedAt time.Time
+}
+
+// pendingConfirmation is an uploaded commitment whose log entry waits for the event
+type pendingConfirmation struct {
+	challengeID string
+	result      *models.Result
+	lg          zerolog.Logger
+	timer       *time.Timer // Stores the log entry anyway once the timeout passes
+}
+
+// StartCommitmentConfirmations subscribes to the commitment events of the configured registry.
+// From then on a commitment's log entry is stored when its event confirms the upload, or after
+// SUI_EVENT_CONFIRM_TIMEOUT_SECONDS if the event never arrives. Call it before RunCommitmentWorker;
+// the subscription ends with ctx.
+func (s *Service) StartCommitmentConfirmations(ctx context.Context) error {
+	stream, err := s.suiTxBuilder.SubscribeCommitmentEvents(ctx, s.config.SUI.RegistryID)
+	if err != nil {
+		return err
+	}
+
+	s.confirmations = &commitmentConfirmations{
+		timeout:   s.config.GetSuiEventConfirmTimeout(),
+		pending:   make(map[string]*pendingConfirmation),
+		confirmed: make(map[string]earlyConfirmation),
+	}
+
+	listenerLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.General)
+	go func() {
+		for event := range stream {
+			s.handleCommitmentEvent(event, listenerLogger)
+		}
+		listenerLogger.Info().Msg("Commitment event subscription ended")
+	}()
+	return nil
+}
+
+// handleCommitmentEvent confirms the upload the event belongs to. Events of other challengers
+// sharing the registry are ignored.
+func (s *Service) handleCommitmentEvent(event sui.CommitmentEvent, lg zerolog.Logger) {
+	if event.Id == nil || event.ChallengerAddr == nil || *event.ChallengerAddr != *s.suiTxBuilder.Signer().Address {
+		return
+	}
+	objID := event.Id.String()
+
+	c := s.confirmations
+	c.mu.Lock()
+	p, ok := c.pending[objID]
+	if ok {
+		delete(c.pending, objID)
+		p.timer.Stop()
+	} else {
+		// Forget early events whose upload never reported back
+		now := time.Now()
+		for id, early := range c.confirmed {
+			if now.Sub(early.receivedAt) > c.timeout {
+				delete(c.confirmed, id)
+			}
+		}
+		c.confirmed[objID] = earlyConfirmation{txDigest: event.TxDigest, receivedAt: now}
+	}
+	c.mu.Unlock()
+
+	if ok {
+		s.confirmCommitment(objID, event.TxDigest, p)
+		return
+	}
+	lg.Debug().Str("objId", objID).Msg("Commitment event arrived before its upload returned")
+}
+
+// awaitConfirmation defers the log entry of an uploaded commitment until its event arrives
+func (s *Service) awaitConfirmation(objID, challengeID string, result *models.Result, lg zerolog.Logger) {
+	p := &pendingConfirmation{challengeID: challengeID, result: result, lg: lg}
+
+	c := s.confirmations
+	c.mu.Lock()
+	if early, ok := c.confirmed[objID]; ok {
+		delete(c.confirmed, objID)
+		c.mu.Unlock()
+		s.confirmCommitment(objID, early.txDigest, p)
+		return
+	}
+	p.timer = time.AfterFunc(c.timeout, func() { s.confirmationTimedOut(objID) })
+	c.pending[objID] = p
+	c.mu.Unlock()
+
+	lg.Debug().Str("objId", objID).Msg("Waiting for commitment event")
+}
+
+// confirmationTimedOut stores the log entry of a commitment whose event never arrived, so a
+// dropped subscription cannot keep the verifier from finding it
+func (s *Service) confirmationTimedOut(objID string) {
+	c := s.confirmations
+	c.mu.Lock()
+	p, ok := c.pending[objID]
+	delete(c.pending, objID)
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	p.lg.Warn().
+		Str("objId", objID).
+		Dur("timeout", c.timeout).
+		Msg("Commitment event not received in time, storing log entry unconfirmed")
+	if err := s.storeLogEntry(context.Background(), objID, p.result, p.lg); err != nil {
+		p.lg.Error().Err(err).Msg("Failed to marshal result")
+	}
+}
+
+// confirmCommitment announces the confirmed commitment and stores its log entry
+func (s *Service) confirmCommitment(objID, txDigest string, p *pendingConfirmation) {
+	ctx := context.Background()
+
+	p.lg.Info().
+		Str("objId", objID).
+		Str("tx_digest", txDigest).
+		Msg("Challenge commitment confirmed by event")
+
+	s.publishEvent(ctx, events.Event{
+		Type:          events.CommitmentConfirmed,
+		ChallengeID:   p.challengeID,
+		RequestID:     p.result.RequestID,
+		SolverAddress: p.result.SolverAddress,
+		ObjectID:      objID,
+	}, p.lg)
+
+	if err := s.storeLogEntry(ctx, objID, p.result, p.lg); err != nil {
+		p.lg.Error().Err(err).Msg("Failed to marshal result")
+	}
+}
@@ -0,0 +1,162 @@
+package challenger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+)
+
+// Outcomes of an imported record
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate" // A challenge with the same ID is already stored
+	ImportInvalid   = "invalid"   // The record does not decode or fails validation
+	ImportFailed    = "failed"    // The database rejected the challenge
+)
+
+// maxImportLineBytes bounds a single newline-delimited JSON record
+const maxImportLineBytes = 4 << 20
+
+// ImportRecord is the outcome of one challenge definition of an import file
+type ImportRecord struct {
+	Record      int    `json:"record"`       // Line number for newline-delimited JSON, 1-based position for a JSON array
+	ChallengeID string `json:"challenge_id"` // Empty when the record did not decode
+	Status      string `json:"status"`       // ImportCreated, ImportDuplicate, ImportInvalid or ImportFailed
+	Error       string `json:"error,omitempty"`
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Records    []ImportRecord `json:"records"`
+	Created    int            `json:"created"`
+	Duplicates int            `json:"duplicates"`
+	Invalid    int            `json:"invalid"`
+	Failed     int            `json:"failed"`
+}
+
+// importLine is a raw challenge definition and where it came from
+type importLine struct {
+	record int
+	data   []byte
+}
+
+// ImportChallenges creates the challenges defined in r, given as a JSON array or as
+// newline-delimited JSON with one challenge per line. Each record is validated, including
+// its validation rule, and created through CreateChallenge. A bad or duplicate record is
+// reported and skipped; the error is only for input that cannot be read at all.
+func (s *Service) ImportChallenges(ctx context.Context, r io.Reader) (*ImportReport, error) {
+	lines, err := readImportLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Records: make([]ImportRecord, 0, len(lines))}
+	for _, line := range lines {
+		result := s.importChallenge(ctx, line)
+		switch result.Status {
+		case ImportCreated:
+			report.Created++
+		case ImportDuplicate:
+			report.Duplicates++
+		case ImportInvalid:
+			report.Invalid++
+		case ImportFailed:
+			report.Failed++
+		}
+		report.Records = append(report.Records, result)
+	}
+	return report, nil
+}
+
+// importChallenge decodes, validates and creates one challenge
+func (s *Service) importChallenge(ctx context.Context, line importLine) ImportRecord {
+	result := ImportRecord{Record: line.record}
+
+	var challenge models.Challenge
+	decoder := json.NewDecoder(bytes.NewReader(line.data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&challenge); err != nil {
+		result.Status, result.Error = ImportInvalid, fmt.Sprintf("failed to decode challenge: %v", err)
+		return result
+	}
+	result.ChallengeID = challenge.ID
+
+	if err := s.checkImportedChallenge(&challenge); err != nil {
+		result.Status, result.Error = ImportInvalid, err.Error()
+		return result
+	}
+
+	err := s.CreateChallenge(ctx, &challenge)
+	switch {
+	case errors.Is(err, db.ErrDuplicateChallenge):
+		result.Status = ImportDuplicate
+	case err != nil:
+		result.Status, result.Error = ImportFailed, err.Error()
+	default:
+		result.Status = ImportCreated
+	}
+	return result
+}
+
+// checkImportedChallenge rejects definitions the API would never have created
+func (s *Service) checkImportedChallenge(challenge *models.Challenge) error {
+	if challenge.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if challenge.Type == "" {
+		return fmt.Errorf("type is required")
+	}
+	if len(challenge.Problem) == 0 {
+		return fmt.Errorf("problem is required")
+	}
+	if len(challenge.OutputSpec) == 0 {
+		return fmt.Errorf("output_spec is required")
+	}
+	if err := s.validator.CheckRule(challenge.ValidationRule); err != nil {
+		return fmt.Errorf("invalid validation_rule: %w", err)
+	}
+	return nil
+}
+
+// readImportLines splits the input into challenge definitions. Input starting with '['
+// is a JSON array; anything else is newline-delimited JSON, where blank lines are skipped.
+func readImportLines(r io.Reader) ([]importLine, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []json.RawMessage
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON array: %w", err)
+		}
+		lines := make([]importLine, len(records))
+		for i, record := range records {
+			lines[i] = importLine{record: i + 1, data: record}
+		}
+		return lines, nil
+	}
+
+	var lines []importLine
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineBytes)
+	for number := 1; scanner.Scan(); number++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		lines = append(lines, importLine{record: number, data: append([]byte(nil), line...)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read newline-delimited JSON: %w", err)
+	}
+	return lines, nil
+}
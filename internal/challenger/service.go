@@ -4,23 +4,35 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"reverse-challenge-system/pkg/api"
 	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/backoff"
 	"reverse-challenge-system/pkg/config"
 	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/events"
+	"reverse-challenge-system/pkg/httpclient"
 	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/metrics"
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/netutil"
+	"reverse-challenge-system/pkg/scoring"
 	"reverse-challenge-system/pkg/sui"
 	"reverse-challenge-system/pkg/validator"
+	"reverse-challenge-system/pkg/version"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -28,121 +40,492 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// logEntry represents the JSON payload for external log uploads
-type logEntry struct {
-	ID             string `json:"id"`
-	Log            string `json:"log"`
-	ChallengerAddr string `json:"challenger_addr,omitempty"`
-	SolverAddr     string `json:"solver_addr,omitempty"`
-	VerifierAddr   string `json:"verifier_addr,omitempty"`
-}
+// Page sizes for listing solver commitments
+const (
+	DefaultCommitmentPageSize = 50
+	MaxCommitmentPageSize     = 500
+)
+
+// Page sizes for listing webhook audits
+const (
+	DefaultAuditPageSize = 100
+	MaxAuditPageSize     = 1000
+)
+
+// Sizes of the solver leaderboard
+const (
+	DefaultLeaderboardSize = 10
+	MaxLeaderboardSize     = 100
+)
+
+// Commitment upload queue
+const (
+	MaxCommitmentAttempts   = 5           // Failed uploads before a job is left for manual follow-up
+	commitmentRetryInterval = time.Minute // How often failed uploads are retried
+)
+
+// External log uploads
+const (
+	logUploadTimeout          = 10 * time.Second       // Deadline for a single upload attempt
+	logUploadMaxDelay         = 10 * time.Second       // Upper bound on the backoff between attempts
+	logUploadJitter           = 0.15                   // Random +/- fraction applied to each backoff delay
+	logUploadFlushBatch       = 100                    // Queued uploads retried per flush
+	defaultLogUploadAttempts  = 4                      // Attempts when LOG_UPLOAD_MAX_ATTEMPTS is unset
+	defaultLogUploadBaseDelay = 500 * time.Millisecond // Base delay when LOG_UPLOAD_BASE_DELAY_MS is unset
+)
+
+// Challenge dispatch to the solver
+const (
+	dispatchMaxDelay             = 10 * time.Second       // Upper bound on the backoff between attempts
+	dispatchJitter               = 0.15                   // Random +/- fraction applied to each backoff delay
+	dispatchRetryBatch           = 100                    // Queued dispatches retried per pass
+	defaultDispatchTimeout       = 10 * time.Second       // Attempt deadline when DISPATCH_TIMEOUT_SECONDS is unset
+	defaultDispatchAttempts      = 3                      // Attempts when DISPATCH_MAX_ATTEMPTS is unset
+	defaultDispatchBaseDelay     = 500 * time.Millisecond // Base delay when DISPATCH_BASE_DELAY_MS is unset
+	defaultDispatchRetryInterval = 30 * time.Second       // Redispatch interval when DISPATCH_RETRY_INTERVAL_SECONDS is unset
+)
+
+// ErrDispatchQueued is returned by SendChallenge when the solver did not accept the challenge
+// after every retry. The challenge is kept in pending_dispatches and RunDispatchRetrier keeps
+// sending it until the solver accepts it or its deadline passes.
+var ErrDispatchQueued = errors.New("challenge queued for redispatch")
 
 type Service struct {
 	config       *config.Config
-	db           *db.ChallengerDB
+	db           db.ChallengerStore
+	readDB       db.ChallengerStore // Optional read-only store for GET handlers
 	hmacAuth     *auth.HMACAuth
 	validator    *validator.Validator
 	client       *http.Client
 	suiTxBuilder *sui.TransactionBuilder
+	events       events.EventPublisher
+	scorer       scoring.Scorer // Optional; scoring.Default when nil
+
+	commitmentWake chan struct{}            // Signals RunCommitmentWorker that a job was queued
+	confirmations  *commitmentConfirmations // Set by StartCommitmentConfirmations; log entries then wait for the commitment event
 }
 
-func NewService(cfg *config.Config, database *db.ChallengerDB, hmacAuth *auth.HMACAuth, suiTxBuilder *sui.TransactionBuilder) *Service {
+func NewService(cfg *config.Config, database db.ChallengerStore, hmacAuth *auth.HMACAuth, suiTxBuilder *sui.TransactionBuilder) *Service {
 	return &Service{
 		config:       cfg,
 		db:           database,
 		hmacAuth:     hmacAuth,
 		validator:    validator.NewValidator(),
-		client:       &http.Client{Timeout: 30 * time.Second},
+		client:       httpclient.New(cfg.HTTPClient),
 		suiTxBuilder: suiTxBuilder,
+		events:       events.NopPublisher{},
+
+		commitmentWake: make(chan struct{}, 1),
+	}
+}
+
+// SetEventPublisher publishes lifecycle events to a message bus instead of discarding them.
+func (s *Service) SetEventPublisher(publisher events.EventPublisher) {
+	s.events = publisher
+}
+
+// publishEvent stamps and publishes a lifecycle event. Bus failures are logged,
+// never surfaced, so an unavailable bus cannot break challenge processing.
+func (s *Service) publishEvent(ctx context.Context, event events.Event, lg zerolog.Logger) {
+	event.OccurredAt = time.Now()
+	if err := s.events.Publish(ctx, event); err != nil {
+		lg.Warn().Err(err).Str("event_type", event.Type).Msg("Failed to publish event")
+	}
+}
+
+// SetReadStore routes reporting queries to a read-only store, keeping writes on the primary.
+func (s *Service) SetReadStore(store db.ChallengerStore) {
+	s.readDB = store
+}
+
+// reader returns the store used by GET handlers, falling back to the primary.
+func (s *Service) reader() db.ChallengerStore {
+	if s.readDB != nil {
+		return s.readDB
+	}
+	return s.db
+}
+
+// SetScorer replaces the scorer used to rank and commit results.
+// Verifiers recompute committed scores, so they must be configured with the same scorer.
+func (s *Service) SetScorer(scorer scoring.Scorer) {
+	s.scorer = scorer
+}
+
+// score returns the score committed for a result, falling back to scoring.Default.
+func (s *Service) score(result *models.Result) uint64 {
+	if s.scorer != nil {
+		return s.scorer.Score(result)
 	}
+	return scoring.Default.Score(result)
+}
+
+// keepsBestAnswer reports whether solvers may submit successive answers and only the
+// best correct one is committed when the submission window closes.
+func (s *Service) keepsBestAnswer() bool {
+	return s.config.AnswerSubmissionMode == "best"
 }
 
-func (s *Service) CreateChallenge(challenge *models.Challenge) error {
+func (s *Service) CreateChallenge(ctx context.Context, challenge *models.Challenge) error {
 	challenge.CreatedAt = time.Now()
-	return s.db.CreateChallenge(challenge)
+	if err := s.db.CreateChallenge(ctx, challenge); err != nil {
+		return err
+	}
+
+	s.publishEvent(ctx, events.Event{
+		Type:          events.ChallengeCreated,
+		ChallengeID:   challenge.ID,
+		ChallengeType: challenge.Type,
+	}, logger.WithChallengeID(challenge.ID))
+	return nil
+}
+
+func (s *Service) SendChallenge(ctx context.Context, challengeID, solverURL string) error {
+	return s.SendChallengeWithPriority(ctx, challengeID, solverURL, 0)
+}
+
+// SendChallengeToMany sends a challenge to every solver in solverURLs at once and lets them
+// race: the first correct callback closes the challenge, later answers are stored but flagged
+// late, and only the winner's result is committed on-chain. The returned error joins the
+// failure of each solver that did not accept the challenge; the others keep working on it.
+func (s *Service) SendChallengeToMany(ctx context.Context, challengeID string, solverURLs []string) error {
+	if len(solverURLs) == 0 {
+		return fmt.Errorf("at least one solver URL is required")
+	}
+	if len(solverURLs) > 1 {
+		if err := s.db.SetChallengeCompetitive(ctx, challengeID); err != nil {
+			return err
+		}
+	}
+
+	errs := make([]error, len(solverURLs))
+	var wg sync.WaitGroup
+	for i, solverURL := range solverURLs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.SendChallenge(ctx, challengeID, solverURL); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", solverURL, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The solvers share the challenge's job ID; forget it only once none of them can call back
+	delivered := false
+	for _, err := range errs {
+		if err == nil || errors.Is(err, ErrDispatchQueued) {
+			delivered = true
+		}
+	}
+	if !delivered && ctx.Err() == nil {
+		if err := s.db.DeleteDispatchedJob(ctx, challengeID, solverJobID(challengeID)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
-func (s *Service) SendChallenge(challengeID, solverURL string) error {
+// SendChallengeWithPriority is SendChallenge with a dispatch priority for the solver.
+// Challenges with a higher priority are picked up before older, lower-priority ones.
+func (s *Service) SendChallengeWithPriority(ctx context.Context, challengeID, solverURL string, priority int) error {
 	// Create request-specific logger that writes to file
 	requestLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.Request).
 		With().
 		Str("challenge_id", challengeID).
 		Str("solver_url", solverURL).
+		Int("priority", priority).
 		Logger()
 
 	// Get challenge from database
-	challenge, err := s.db.GetChallenge(challengeID)
+	challenge, err := s.db.GetChallenge(ctx, challengeID)
 	if err != nil {
 		return fmt.Errorf("failed to get challenge: %w", err)
 	}
 
-	// Construct callback URL
-	callbackURL := fmt.Sprintf("%s/callback/%s", s.config.PublicCallbackHost, challengeID)
+	// In best-answer mode the solver may keep improving its answer until the window closes
+	deadline := time.Now().Add(5 * time.Minute)
+	if s.keepsBestAnswer() {
+		window, err := s.db.OpenSubmissionWindow(ctx, challengeID, time.Now().Add(s.config.GetSubmissionWindow()))
+		if err != nil {
+			return fmt.Errorf("failed to open submission window: %w", err)
+		}
+		deadline = window.ClosesAt
+	}
+
+	// Callbacks after the deadline sent to the solver are rejected as expired
+	if err := s.db.SetChallengeExpiry(ctx, challengeID, deadline); err != nil {
+		return fmt.Errorf("failed to set challenge expiry: %w", err)
+	}
+
+	body, err := s.solveRequestBody(challenge, deadline, priority)
+	if err != nil {
+		return err
+	}
+
+	// Retry a solver that is momentarily unavailable, then leave the challenge to RunDispatchRetrier
+	maxAttempts, policy := s.dispatchPolicy()
+	for attempt := 0; ; attempt++ {
+		var retryable bool
+		retryable, err = s.postChallenge(ctx, challengeID, solverURL, body, requestLogger)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if !retryable {
+			s.forgetDispatchedJob(ctx, challengeID, requestLogger)
+			return err
+		}
+		if attempt == maxAttempts-1 {
+			break
+		}
+
+		delay := policy.Delay(attempt)
+		requestLogger.Warn().Err(err).
+			Int("attempt", attempt+1).
+			Dur("delay", delay).
+			Msg("Dispatch failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	dispatch := &models.PendingDispatch{ChallengeID: challengeID, SolverURL: solverURL, Priority: priority, LastError: err.Error()}
+	if qerr := s.db.EnqueueDispatch(ctx, dispatch); qerr != nil {
+		return fmt.Errorf("failed to queue undelivered challenge (%v): %w", err, qerr)
+	}
+	requestLogger.Warn().Err(err).
+		Int("attempts", maxAttempts).
+		Msg("Dispatch failed; queued for redispatch")
+	return fmt.Errorf("%w after %d attempts: %v", ErrDispatchQueued, maxAttempts, err)
+}
 
-	// Create solve request
+// solveRequestBody builds the signed body of POST /solve for challenge, with the deadline
+// the solver must answer by.
+func (s *Service) solveRequestBody(challenge *models.Challenge, deadline time.Time, priority int) ([]byte, error) {
 	solveReq := models.SolveRequest{
-		APIVersion:  "v2.1",
-		ChallengeID: challengeID,
+		APIVersion:  version.APIVersion,
+		ChallengeID: challenge.ID,
 		Problem:     challenge.Problem,
 		OutputSpec:  challenge.OutputSpec,
 		Constraints: models.Constraints{
 			TimeoutMs:  30000,
-			DeadlineTs: time.Now().Add(5 * time.Minute).Unix(),
+			DeadlineTs: deadline.Unix(),
 		},
-		CallbackURL: callbackURL,
+		CallbackURL: fmt.Sprintf("%s/callback/%s", s.config.PublicCallbackHost, challenge.ID),
+		Priority:    priority,
 	}
 
-	// Marshal request body
 	body, err := json.Marshal(solveReq)
 	if err != nil {
-		return fmt.Errorf("failed to marshal solve request: %w", err)
+		return nil, fmt.Errorf("failed to marshal solve request: %w", err)
+	}
+	return body, nil
+}
+
+// solverJobID returns the job ID solvers assign to a challenge they accept.
+func solverJobID(challengeID string) string {
+	return fmt.Sprintf("solver_job_%s", challengeID)
+}
+
+// forgetDispatchedJob deletes the job recorded for a challenge whose dispatch failed for good.
+// Competitive challenges keep it, since other solvers sent the same challenge share the job ID.
+func (s *Service) forgetDispatchedJob(ctx context.Context, challengeID string, lg zerolog.Logger) {
+	challenge, err := s.db.GetChallenge(ctx, challengeID)
+	if err != nil {
+		lg.Error().Err(err).Msg("Failed to load challenge of failed dispatch")
+		return
 	}
+	if challenge.Competitive {
+		return
+	}
+	if err := s.db.DeleteDispatchedJob(ctx, challengeID, solverJobID(challengeID)); err != nil {
+		lg.Error().Err(err).Msg("Failed to delete dispatched job")
+	}
+}
 
-	// Create HMAC signature
+// postChallenge makes a single POST /solve of a challenge. The job the solver will assign is
+// recorded before the request goes out, since a fast solver can call back before its 202
+// response arrives. retryable reports whether a failure is worth retrying: network errors,
+// 429 and 5xx.
+func (s *Service) postChallenge(ctx context.Context, challengeID, solverURL string, body []byte, lg zerolog.Logger) (retryable bool, err error) {
+	timeout := defaultDispatchTimeout
+	if s.config.DispatchTimeoutSecs > 0 {
+		timeout = time.Duration(s.config.DispatchTimeoutSecs) * time.Second
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Every attempt is signed with a fresh nonce so the solver does not reject it as a replay
 	nonce := uuid.New().String()
 	authHeader := s.hmacAuth.CreateAuthHeader("POST", "/solve", body, s.config.SolverHMACKeyID, nonce)
 	if authHeader == "" {
-		return fmt.Errorf("failed to create auth header")
+		return false, fmt.Errorf("failed to create auth header")
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", solverURL+"/solve", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(attemptCtx, "POST", solverURL+"/solve", bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Remember the job so its callback can be matched, even one racing the 202 response
+	if err := s.db.SaveDispatchedJob(ctx, challengeID, solverJobID(challengeID)); err != nil {
+		return false, fmt.Errorf("failed to record dispatched job: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", authHeader)
 	req.Header.Set("X-Request-ID", uuid.New().String())
 
-	// Send request
-	requestLogger.Info().Str("solver_url", solverURL).Msg("Sending challenge to solver")
+	lg.Info().Str("solver_url", solverURL).Msg("Sending challenge to solver")
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return true, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("solver returned status %d", resp.StatusCode)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return retryable, fmt.Errorf("solver returned status %d", resp.StatusCode)
 	}
 
 	var solveResp models.SolveResponse
 	if err := json.NewDecoder(resp.Body).Decode(&solveResp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if solveResp.SolverJobID == "" {
+		return false, fmt.Errorf("solver returned empty job id")
+	}
+
+	// Solvers that name their jobs differently are matched by the ID they returned
+	if solveResp.SolverJobID != solverJobID(challengeID) {
+		if err := s.db.SaveDispatchedJob(ctx, challengeID, solveResp.SolverJobID); err != nil {
+			return false, fmt.Errorf("failed to record dispatched job: %w", err)
+		}
+	}
+	if err := s.db.CompleteDispatch(ctx, challengeID, solverURL); err != nil {
+		lg.Error().Err(err).Msg("Failed to remove queued dispatch")
 	}
 
-	requestLogger.Info().
+	lg.Info().
 		Str("solver_job_id", solveResp.SolverJobID).
 		Msg("Challenge sent successfully")
 
-	return nil
+	return false, nil
+}
+
+// dispatchPolicy returns the attempts and backoff for challenge dispatches, keeping defaults
+// for settings left unset.
+func (s *Service) dispatchPolicy() (int, backoff.Policy) {
+	maxAttempts := s.config.DispatchMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultDispatchAttempts
+	}
+	policy := backoff.Policy{
+		BaseDelay: defaultDispatchBaseDelay,
+		MaxDelay:  dispatchMaxDelay,
+		JitterMin: 1 - dispatchJitter,
+		JitterMax: 1 + dispatchJitter,
+	}
+	if s.config.DispatchBaseDelayMs > 0 {
+		policy.BaseDelay = time.Duration(s.config.DispatchBaseDelayMs) * time.Millisecond
+	}
+	return maxAttempts, policy
+}
+
+// RunDispatchRetrier retries the dispatches queued by SendChallenge every
+// DISPATCH_RETRY_INTERVAL_SECONDS until ctx is canceled.
+func (s *Service) RunDispatchRetrier(ctx context.Context) {
+	retryLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.General)
+
+	interval := s.config.GetDispatchRetryInterval()
+	if interval <= 0 {
+		interval = defaultDispatchRetryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.redispatch(ctx, retryLogger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// redispatch makes one delivery attempt for each queued challenge, oldest first. Challenges
+// the solver accepts or rejects outright leave the queue, as do challenges whose deadline
+// passed while they waited; the deadline first sent is kept, so retries never extend it.
+func (s *Service) redispatch(ctx context.Context, lg zerolog.Logger) {
+	dispatches, err := s.db.ListDispatches(ctx, dispatchRetryBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			lg.Error().Err(err).Msg("Failed to list queued dispatches")
+		}
+		return
+	}
+
+	for _, dispatch := range dispatches {
+		if ctx.Err() != nil {
+			return
+		}
+
+		dispatchLogger := lg.With().
+			Str("challenge_id", dispatch.ChallengeID).
+			Str("solver_url", dispatch.SolverURL).
+			Int("priority", dispatch.Priority).
+			Logger()
+
+		retryable, err := s.resendChallenge(ctx, dispatch, dispatchLogger)
+		switch {
+		case err == nil:
+			dispatchLogger.Info().Int("attempts", dispatch.Attempts+1).Msg("Queued challenge dispatched")
+		case !retryable:
+			dispatchLogger.Error().Err(err).Msg("Dropping queued dispatch")
+			if cerr := s.db.CompleteDispatch(ctx, dispatch.ChallengeID, dispatch.SolverURL); cerr != nil {
+				dispatchLogger.Error().Err(cerr).Msg("Failed to remove queued dispatch")
+			}
+			s.forgetDispatchedJob(ctx, dispatch.ChallengeID, dispatchLogger)
+		default:
+			if ferr := s.db.FailDispatch(ctx, dispatch.ChallengeID, dispatch.SolverURL, err.Error()); ferr != nil {
+				dispatchLogger.Error().Err(ferr).Msg("Failed to record dispatch failure")
+			}
+		}
+	}
+}
+
+// resendChallenge makes one attempt to deliver a queued challenge with its original deadline.
+func (s *Service) resendChallenge(ctx context.Context, dispatch *models.PendingDispatch, lg zerolog.Logger) (retryable bool, err error) {
+	challenge, err := s.db.GetChallenge(ctx, dispatch.ChallengeID)
+	if err != nil {
+		return ctx.Err() != nil, err
+	}
+	if challenge.ExpiredAt != nil || challenge.ExpiresAt == nil || !time.Now().Before(*challenge.ExpiresAt) {
+		return false, fmt.Errorf("challenge deadline passed before it could be dispatched")
+	}
+
+	body, err := s.solveRequestBody(challenge, *challenge.ExpiresAt, dispatch.Priority)
+	if err != nil {
+		return false, err
+	}
+	return s.postChallenge(ctx, challenge.ID, dispatch.SolverURL, body, lg)
 }
 
 func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
 	vars := mux.Vars(r)
 	challengeID := vars["challenge_id"]
-	requestID := r.Header.Get("X-Request-ID")
+	requestID := api.RequestID(r)
 
 	// Create callback-specific logger that writes to file
 	callbackLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.Callback).
@@ -157,9 +540,15 @@ func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		Str("user_agent", r.Header.Get("User-Agent")).
 		Msg("Callback request received")
 
-	// Read request body
+	// Read request body, keeping the raw bytes for the solver signature check
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		callbackLogger.Error().Err(err).Msg("Failed to read callback request")
+		s.writeError(w, http.StatusBadRequest, "READ_ERROR", "Failed to read request body", requestID)
+		return
+	}
 	var callbackReq models.CallbackRequest
-	if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&callbackReq); err != nil {
 		callbackLogger.Error().Err(err).Msg("Failed to decode callback request")
 		s.writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON body", requestID)
 		return
@@ -176,11 +565,51 @@ func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject oversized metadata before it reaches the results table or the log entry
+	if limit := s.config.MaxSolverMetadataBytes; limit > 0 && len(callbackReq.Metadata) > limit {
+		callbackLogger.Warn().
+			Int("metadata_bytes", len(callbackReq.Metadata)).
+			Int("max_metadata_bytes", limit).
+			Msg("Solver metadata too large")
+		s.writeError(w, http.StatusRequestEntityTooLarge, "METADATA_TOO_LARGE",
+			fmt.Sprintf("Solver metadata exceeds %d bytes", limit), requestID)
+		return
+	}
+
+	// Decode the full metadata; out-of-range values are rejected rather than stored
+	metadata, err := models.ParseSolverMetadata(callbackReq.Metadata)
+	if err != nil {
+		callbackLogger.Warn().Err(err).Msg("Invalid solver metadata")
+		s.writeError(w, http.StatusBadRequest, "INVALID_METADATA", err.Error(), requestID)
+		return
+	}
+
+	// The solver address is recorded on-chain with the commitment, so it must be a real Sui address
+	solverAddress, err := s.solverAddress(r)
+	if err != nil {
+		callbackLogger.Warn().Err(err).
+			Str("solver_address", r.Header.Get("X-Solver-Address")).
+			Msg("Invalid solver address")
+		s.writeError(w, http.StatusBadRequest, "INVALID_SOLVER_ADDRESS", err.Error(), requestID)
+		return
+	}
+
+	// A signature ties the callback to the key behind the solver address, not just the shared HMAC secret
+	signed, err := s.verifySolverSignature(r, body, solverAddress)
+	if err != nil {
+		callbackLogger.Warn().Err(err).
+			Str("solver_address", solverAddress).
+			Msg("Invalid solver signature")
+		s.writeError(w, http.StatusUnauthorized, "INVALID_SOLVER_SIGNATURE", err.Error(), requestID)
+		return
+	}
+	callbackLogger.Debug().Bool("solver_signed", signed).Str("solver_address", solverAddress).Msg("Solver identity checked")
+
 	// Removed the check-then-insert pattern to avoid race condition
 	// We'll use INSERT OR IGNORE at the database level instead
 
 	// Get challenge for validation
-	challenge, err := s.db.GetChallenge(challengeID)
+	challenge, err := s.db.GetChallenge(r.Context(), challengeID)
 	if err != nil {
 		callbackLogger.Error().Err(err).Msg("Failed to get challenge")
 		s.writeError(w, http.StatusNotFound, "CHALLENGE_NOT_FOUND",
@@ -188,11 +617,63 @@ func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject answers that arrive after the deadline the solver was given
+	if challenge.ExpiresAt != nil && !time.Now().Before(*challenge.ExpiresAt) {
+		callbackLogger.Warn().
+			Time("expires_at", *challenge.ExpiresAt).
+			Msg("Callback for expired challenge")
+		s.writeError(w, http.StatusGone, "CHALLENGE_EXPIRED",
+			"Challenge deadline has passed", requestID)
+		return
+	}
+
+	// Only accept callbacks for jobs this challenger dispatched
+	dispatched, err := s.db.HasDispatchedJob(r.Context(), challengeID, callbackReq.SolverJobID)
+	if err != nil {
+		callbackLogger.Error().Err(err).Msg("Failed to check dispatched job")
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to check solver job", requestID)
+		return
+	}
+	if !dispatched {
+		callbackLogger.Warn().
+			Str("solver_job_id", callbackReq.SolverJobID).
+			Msg("Callback for unknown solver job")
+		s.writeError(w, http.StatusBadRequest, "UNKNOWN_JOB",
+			"Solver job was not dispatched for this challenge", requestID)
+		return
+	}
+
+	// In best-answer mode callbacks are only accepted while the submission window is open.
+	// Windows are normally opened on dispatch; open one lazily for jobs sent before that.
+	if s.keepsBestAnswer() {
+		window, err := s.db.OpenSubmissionWindow(r.Context(), challengeID, time.Now().Add(s.config.GetSubmissionWindow()))
+		if err != nil {
+			callbackLogger.Error().Err(err).Msg("Failed to open submission window")
+			s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+				"Failed to check submission window", requestID)
+			return
+		}
+		if window.SettledAt != nil || !time.Now().Before(window.ClosesAt) {
+			callbackLogger.Warn().
+				Time("closes_at", window.ClosesAt).
+				Msg("Callback after submission window closed")
+			s.writeError(w, http.StatusConflict, "WINDOW_CLOSED",
+				"Submission window for this challenge has closed", requestID)
+			return
+		}
+	}
+
 	// Validate answer if status is success
 	isCorrect := false
 	if callbackReq.Status == "success" && callbackReq.Answer != "" {
 		correct, err := s.validator.ValidateAnswer(challenge.ValidationRule, callbackReq.Answer)
-		if err != nil {
+		var stageErr *validator.PipelineStageError
+		if errors.As(err, &stageErr) && stageErr.Err == nil {
+			callbackLogger.Info().
+				Str("failed_stage", stageErr.Stage).
+				Msg("Answer rejected by validation pipeline")
+		} else if err != nil {
 			callbackLogger.Error().Err(err).
 				Str("answer", callbackReq.Answer).
 				Msg("Failed to validate answer")
@@ -201,12 +682,28 @@ func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Extract solver address from request headers or use a default
-	solverAddress := r.Header.Get("X-Solver-Address")
-	if solverAddress == "" {
-		// For demo purposes, derive a deterministic address from remote IP
-		// In production, this should come from authentication or be provided by solver
-		solverAddress = "0x" + fmt.Sprintf("%040x", len(r.RemoteAddr))[:40] // Placeholder based on IP
+	// Outside best-answer mode the first correct answer closes the challenge. Answers after it
+	// are stored but flagged late and never committed, so the winner alone is paid.
+	late := false
+	if !s.keepsBestAnswer() {
+		if callbackReq.Status == "success" && isCorrect {
+			won, err := s.db.CloseChallenge(r.Context(), challengeID, requestID, solverAddress, time.Now())
+			if err != nil {
+				callbackLogger.Error().Err(err).Msg("Failed to close challenge")
+				s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+					"Failed to close challenge", requestID)
+				return
+			}
+			late = !won
+		} else {
+			late = challenge.ClosedAt != nil
+		}
+	}
+	if late {
+		callbackLogger.Info().
+			Str("solver_address", solverAddress).
+			Bool("is_correct", isCorrect).
+			Msg("Answer received after the challenge closed")
 	}
 
 	// Create result record
@@ -218,21 +715,16 @@ func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		ReceivedAnswer: callbackReq.Answer,
 		IsCorrect:      isCorrect,
 		SolverAddress:  solverAddress,
-		ComputeTimeMs:  0, // Extract from metadata if available
 		SolverMetadata: callbackReq.Metadata,
 		CreatedAt:      time.Now(),
+		IsLate:         late,
 	}
-
-	// Extract compute time from metadata if available
-	if callbackReq.Metadata != nil {
-		var metadata models.SolverMetadata
-		if err := json.Unmarshal(callbackReq.Metadata, &metadata); err == nil {
-			result.ComputeTimeMs = metadata.ComputeTimeMs
-		}
+	if metadata != nil {
+		result.ComputeTimeMs = metadata.ComputeTimeMs
 	}
 
 	// Save result with duplicate check for idempotency
-	wasInserted, err := s.db.SaveResultWithDuplicateCheck(result)
+	wasInserted, err := s.db.SaveResultWithDuplicateCheck(r.Context(), result)
 	if err != nil {
 		callbackLogger.Error().Err(err).Msg("Failed to save result")
 		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
@@ -257,152 +749,421 @@ func (s *Service) HandleCallback(w http.ResponseWriter, r *http.Request) {
 			CreatedAt:   time.Now(),
 		}
 
-		if err := s.db.SaveWebhookAudit(audit); err != nil {
+		if err := s.db.SaveWebhookAudit(r.Context(), audit); err != nil {
 			callbackLogger.Error().Err(err).Msg("Failed to save webhook audit")
 			// Don't fail the request for audit errors
 		}
+
+		s.publishEvent(r.Context(), events.Event{
+			Type:          events.ResultRecorded,
+			ChallengeID:   challengeID,
+			RequestID:     requestID,
+			Status:        result.Status,
+			IsCorrect:     &result.IsCorrect,
+			SolverAddress: solverAddress,
+		}, callbackLogger)
+	}
+
+	// Track the best correct answer; it is committed once when the window is settled
+	if s.keepsBestAnswer() && !isDuplicate && callbackReq.Status == "success" && isCorrect {
+		score := s.score(result)
+		isBest, err := s.db.RecordSubmission(r.Context(), challengeID, requestID, score)
+		if err != nil {
+			callbackLogger.Error().Err(err).Msg("Failed to record submission")
+			s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+				"Failed to record submission", requestID)
+			return
+		}
+		callbackLogger.Info().
+			Uint64("score", score).
+			Bool("is_best", isBest).
+			Msg("Submission recorded")
 	}
 
+	metrics.CallbacksReceived.WithLabelValues(metrics.CallbackOutcome(callbackReq.Status, isCorrect, isDuplicate)).Inc()
+	metrics.CallbackHandlingDuration.Observe(time.Since(start).Seconds())
+
 	callbackLogger.Info().
 		Str("status", callbackReq.Status).
 		Bool("is_correct", isCorrect).
 		Bool("is_duplicate", isDuplicate).
+		Bool("is_late", late).
 		Str("solver_job_id", callbackReq.SolverJobID).
 		Msg("Callback processed successfully")
 
-	// Upload to Sui if enabled and this is a successful, non-duplicate result
-	var commitmentID string
-	if s.suiTxBuilder != nil && !isDuplicate && callbackReq.Status == "success" {
-		objId, err := s.uploadToSuiSync(challengeID, result, callbackLogger)
-		if err != nil {
-			callbackLogger.Error().Err(err).Msg("Failed to upload to Sui")
-			commitmentID = fmt.Sprintf("%s:%s", challengeID, requestID) // fallback to original format
+	// Queue the Sui upload if enabled and this is a successful, non-duplicate result.
+	// The worker stores the log entry under the commitment object ID once the upload lands.
+	// In best-answer mode the upload is queued by SettleClosedWindows instead. Late results
+	// are never committed, and a competitive challenge commits only its winner.
+	committable := !late && (!challenge.Competitive || isCorrect)
+	queued := false
+	if s.suiTxBuilder != nil && !isDuplicate && callbackReq.Status == "success" && !s.keepsBestAnswer() && committable {
+		if err := s.enqueueCommitment(r.Context(), challengeID, requestID); err != nil {
+			callbackLogger.Error().Err(err).Msg("Failed to queue Sui commitment upload")
 		} else {
-			commitmentID = objId.String()
+			queued = true
 		}
-		// Add bounty to vault if vault ID is configured
-		if err := s.VaultAddBounty(s.config.SUI.VaultID); err != nil {
-			callbackLogger.Warn().Err(err).Msg("Failed to add bounty to vault")
+	}
+
+	if !queued {
+		logID := fmt.Sprintf("%s:%s", challengeID, requestID) // fallback to original format
+		if err := s.storeLogEntry(r.Context(), logID, result, callbackLogger); err != nil {
+			callbackLogger.Error().Err(err).Msg("Failed to marshal result")
+			s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+				"Failed to marshal result", requestID)
+			return
 		}
-	} else {
-		commitmentID = fmt.Sprintf("%s:%s", challengeID, requestID) // fallback to original format
 	}
 
-	// Build log entry
-	challengerAddr := ""
-	if s.suiTxBuilder != nil && s.suiTxBuilder.Signer() != nil {
-		challengerAddr = s.suiTxBuilder.Signer().Address.String()
+	s.writeCallbackResponse(w, challengeID, isDuplicate, late, callbackReq.Status == "success", isCorrect)
+}
+
+// solverAddress returns the canonical Sui address from the X-Solver-Address header.
+// A missing header is only tolerated when ALLOW_MISSING_SOLVER_ADDRESS is set, in which
+// case the zero address stands in; a malformed address is always rejected.
+func (s *Service) solverAddress(r *http.Request) (string, error) {
+	header := r.Header.Get("X-Solver-Address")
+	if header == "" {
+		if s.config.AllowNoSolverAddress {
+			return suigo.Address{}.String(), nil
+		}
+		return "", errors.New("X-Solver-Address header is required")
 	}
 
-	b, err := json.Marshal(result)
+	hexPart, ok := strings.CutPrefix(header, "0x")
+	if !ok || hexPart == "" {
+		return "", errors.New("X-Solver-Address must be a 0x-prefixed Sui address")
+	}
+	address, err := suigo.AddressFromHex(header)
 	if err != nil {
-		callbackLogger.Error().Err(err).Msg("Failed to marshal result")
-		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
-			"Failed to marshal result", requestID)
-		return
+		return "", fmt.Errorf("X-Solver-Address is not a valid Sui address: %w", err)
 	}
+	return address.String(), nil
+}
 
-	entry := logEntry{
-		ID:             commitmentID,
-		Log:            string(b),
-		ChallengerAddr: challengerAddr,
-		SolverAddr:     solverAddress,
-		// VerifierAddr left empty for now
+// verifySolverSignature checks the X-Solver-Signature of a callback body against the solver
+// address, reporting whether the callback was signed. Unsigned callbacks are accepted unless
+// REQUIRE_SOLVER_SIGNATURE is set; a signature that is present must always verify.
+func (s *Service) verifySolverSignature(r *http.Request, body []byte, solverAddress string) (bool, error) {
+	signature := r.Header.Get(sui.CallbackSignatureHeader)
+	pubkey := r.Header.Get(sui.CallbackPubkeyHeader)
+	if signature == "" && pubkey == "" {
+		if s.config.RequireSolverSignature {
+			return false, fmt.Errorf("%s and %s headers are required", sui.CallbackSignatureHeader, sui.CallbackPubkeyHeader)
+		}
+		return false, nil
 	}
 
-	// Non-blocking upload with timeout
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		s.uploadCallbackLog(ctx, entry, callbackLogger)
-	}()
-
-	s.writeCallbackResponse(w, challengeID, isDuplicate)
+	address, err := suigo.AddressFromHex(solverAddress)
+	if err != nil {
+		return false, fmt.Errorf("invalid solver address: %w", err)
+	}
+	if err := sui.VerifyCallbackSignature(body, signature, pubkey, address); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (s *Service) writeCallbackResponse(w http.ResponseWriter, challengeID string, duplicate bool) {
-	response := models.CallbackResponse{
-		Received:    true,
-		ChallengeID: challengeID,
-		Duplicate:   duplicate,
-	}
+// HandleGetLogEntry serves a locally stored callback log entry in the same shape as the
+// external log service, so the verifier can use the challenger as a fallback source.
+// Requests must carry the LOGS_API_KEY in X-API-Key.
+func (s *Service) HandleGetLogEntry(w http.ResponseWriter, r *http.Request) {
+	requestID := api.RequestID(r)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
-}
+	if s.config.LogsAPIKey == "" {
+		s.writeError(w, http.StatusServiceUnavailable, "LOGS_API_DISABLED",
+			"LOGS_API_KEY is not configured", requestID)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-Key")), []byte(s.config.LogsAPIKey)) != 1 {
+		s.writeError(w, http.StatusUnauthorized, "UNAUTHORIZED",
+			"Invalid API key", requestID)
+		return
+	}
 
-func (s *Service) writeError(w http.ResponseWriter, statusCode int, code, message, requestID string) {
-	errorResp := models.ErrorResponse{
-		Error: models.ErrorDetails{
-			Code:      code,
-			Message:   message,
-			RequestID: requestID,
-		},
+	entry, err := s.reader().GetLogEntry(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to load log entry", requestID)
+		return
+	}
+	if entry == nil {
+		s.writeError(w, http.StatusNotFound, "LOG_NOT_FOUND",
+			"Log entry not found", requestID)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(errorResp)
+	s.writeJSON(w, http.StatusOK, entry)
 }
 
-func (s *Service) serializeHeaders(headers http.Header) string {
-	var headerLines []string
-	for key, values := range headers {
-		for _, value := range values {
-			headerLines = append(headerLines, fmt.Sprintf("%s: %s", key, value))
-		}
-	}
-	return strings.Join(headerLines, "\n")
-}
+// HandleGetChallenge returns a stored challenge with its secret answer redacted.
+func (s *Service) HandleGetChallenge(w http.ResponseWriter, r *http.Request) {
+	challengeID := mux.Vars(r)["challenge_id"]
+	requestID := api.RequestID(r)
 
-func (s *Service) ValidateCallbackURL(callbackURL string) error {
-	u, err := url.Parse(callbackURL)
+	challenge, err := s.reader().GetChallenge(r.Context(), challengeID)
 	if err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
+		s.writeError(w, http.StatusNotFound, "CHALLENGE_NOT_FOUND",
+			"Challenge not found", requestID)
+		return
 	}
 
-	// If ngrok is enabled, require HTTPS
-	if s.config.UseNgrok {
-		if u.Scheme != "https" {
-			return fmt.Errorf("callback URL must use HTTPS when USE_NGROK=true")
-		}
-		// Allow ngrok domains
-		if strings.Contains(u.Host, "ngrok.io") || strings.Contains(u.Host, "ngrok-free.app") {
-			return nil
-		}
-	} else {
-		// For local development, allow HTTP on localhost/127.0.0.1
-		if u.Scheme == "http" {
-			if strings.Contains(u.Host, "localhost") || strings.Contains(u.Host, "127.0.0.1") {
-				return nil
-			}
-			return fmt.Errorf("HTTP callback URLs only allowed for localhost when USE_NGROK=false")
-		}
-		if u.Scheme == "https" {
-			// HTTPS is always allowed
-			return nil
-		}
-		return fmt.Errorf("callback URL must use HTTP (localhost only) or HTTPS")
-	}
+	s.writeJSON(w, http.StatusOK, redactChallenge(challenge))
+}
 
-	// Basic whitelist check - in production, you'd want a more comprehensive list
-	allowedHosts := []string{
-		"localhost",
-		"127.0.0.1",
-	}
+// HandleListResults returns all results recorded for a challenge.
+func (s *Service) HandleListResults(w http.ResponseWriter, r *http.Request) {
+	challengeID := mux.Vars(r)["challenge_id"]
+	requestID := api.RequestID(r)
 
-	for _, host := range allowedHosts {
-		if strings.Contains(u.Host, host) {
-			return nil
-		}
+	if _, err := s.reader().GetChallenge(r.Context(), challengeID); err != nil {
+		s.writeError(w, http.StatusNotFound, "CHALLENGE_NOT_FOUND",
+			"Challenge not found", requestID)
+		return
 	}
 
-	return fmt.Errorf("callback URL host not in whitelist: %s", u.Host)
+	results, err := s.reader().ListResultsByChallenge(r.Context(), challengeID)
+	if err != nil {
+		lg := logger.WithChallengeID(challengeID)
+		lg.Error().Err(err).Msg("Failed to list results")
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to list results", requestID)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, results)
 }
 
-// uploadToSuiSync uploads challenge commitment to Sui blockchain synchronously and returns the object ID
-func (s *Service) uploadToSuiSync(challengeID string, result *models.Result, callbackLogger zerolog.Logger) (*suigo.ObjectId, error) {
+// HandleStats returns aggregate challenge and result counts for dashboards.
+func (s *Service) HandleStats(w http.ResponseWriter, r *http.Request) {
+	requestID := api.RequestID(r)
+
+	stats, err := s.reader().GetChallengeStats(r.Context())
+	if err != nil {
+		lg := logger.WithRequestID(requestID)
+		lg.Error().Err(err).Msg("Failed to compute challenge stats")
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to compute stats", requestID)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, stats)
+}
+
+// HandleListSolverCommitments returns the on-chain commitments attributed to a solver address
+// together with their verification and settlement status.
+// Supports optional limit and offset query parameters.
+func (s *Service) HandleListSolverCommitments(w http.ResponseWriter, r *http.Request) {
+	solverAddress := mux.Vars(r)["address"]
+	requestID := api.RequestID(r)
+
+	limit, err := queryInt(r, "limit", DefaultCommitmentPageSize)
+	if err != nil || limit <= 0 || limit > MaxCommitmentPageSize {
+		s.writeError(w, http.StatusBadRequest, "INVALID_LIMIT",
+			fmt.Sprintf("limit must be between 1 and %d", MaxCommitmentPageSize), requestID)
+		return
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		s.writeError(w, http.StatusBadRequest, "INVALID_OFFSET",
+			"offset must be a non-negative integer", requestID)
+		return
+	}
+
+	commitments, total, err := s.reader().ListCommitmentsBySolver(r.Context(), solverAddress, limit, offset)
+	if err != nil {
+		lg := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.Request)
+		lg.Error().Err(err).Str("solver_address", solverAddress).Msg("Failed to list solver commitments")
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to list commitments", requestID)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, models.SolverCommitmentsResponse{
+		SolverAddress: solverAddress,
+		Commitments:   commitments,
+		Total:         total,
+		Limit:         limit,
+		Offset:        offset,
+	})
+}
+
+// HandleLeaderboard returns solvers ranked by score, best first.
+// The optional limit query parameter caps how many are returned.
+func (s *Service) HandleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	requestID := api.RequestID(r)
+
+	limit, err := queryInt(r, "limit", DefaultLeaderboardSize)
+	if err != nil || limit <= 0 || limit > MaxLeaderboardSize {
+		s.writeError(w, http.StatusBadRequest, "INVALID_LIMIT",
+			fmt.Sprintf("limit must be between 1 and %d", MaxLeaderboardSize), requestID)
+		return
+	}
+
+	leaderboard, err := s.reader().GetLeaderboard(r.Context(), limit)
+	if err != nil {
+		lg := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.Request)
+		lg.Error().Err(err).Msg("Failed to compute leaderboard")
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to compute leaderboard", requestID)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, leaderboard)
+}
+
+// HandleListAudits returns the callback audit trail, newest first.
+// The optional challenge_id query parameter restricts it to one challenge.
+func (s *Service) HandleListAudits(w http.ResponseWriter, r *http.Request) {
+	challengeID := r.URL.Query().Get("challenge_id")
+	requestID := api.RequestID(r)
+
+	limit, err := queryInt(r, "limit", DefaultAuditPageSize)
+	if err != nil || limit <= 0 || limit > MaxAuditPageSize {
+		s.writeError(w, http.StatusBadRequest, "INVALID_LIMIT",
+			fmt.Sprintf("limit must be between 1 and %d", MaxAuditPageSize), requestID)
+		return
+	}
+
+	audits, err := s.reader().ListWebhookAudits(r.Context(), challengeID, limit)
+	if err != nil {
+		lg := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.Request)
+		lg.Error().Err(err).Str("challenge_id", challengeID).Msg("Failed to list webhook audits")
+		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
+			"Failed to list audits", requestID)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, audits)
+}
+
+// queryInt parses an integer query parameter, returning def when it is absent.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// redactChallenge returns a copy of the challenge without the expected answer,
+// which must never leave the challenger.
+func redactChallenge(challenge *models.Challenge) *models.Challenge {
+	redacted := *challenge
+	redacted.ValidationRule = redactRule(challenge.ValidationRule)
+	return &redacted
+}
+
+// redactRule blanks the answer of a validation rule and strips whatever in its params
+// reveals accepted answers, recursing into Composite and Pipeline rules. Params of
+// unknown or answer-bearing types are dropped.
+func redactRule(rule models.ValidationRule) models.ValidationRule {
+	redacted := models.ValidationRule{Type: rule.Type}
+	switch rule.Type {
+	case "ExactMatch", "NumericTolerance", "FuzzyMatch":
+		// Only matching options, no answers
+		redacted.Params = rule.Params
+	case "SetMembership":
+		var params models.SetMembershipParams
+		if err := json.Unmarshal(rule.Params, &params); err != nil {
+			break
+		}
+		params.Answers = nil
+		redacted.Params, _ = json.Marshal(params)
+	case "Composite":
+		var params models.CompositeParams
+		if err := json.Unmarshal(rule.Params, &params); err != nil {
+			break
+		}
+		for i, child := range params.Rules {
+			params.Rules[i] = redactRule(child)
+		}
+		redacted.Params, _ = json.Marshal(params)
+	case "Pipeline":
+		var params models.PipelineParams
+		if err := json.Unmarshal(rule.Params, &params); err != nil {
+			break
+		}
+		for i, stage := range params.Stages {
+			if stage.Rule != nil {
+				child := redactRule(*stage.Rule)
+				params.Stages[i].Rule = &child
+			}
+		}
+		redacted.Params, _ = json.Marshal(params)
+	}
+	return redacted
+}
+
+func (s *Service) writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeCallbackResponse acknowledges a processed callback.
+// Depending on CALLBACK_CORRECTNESS_MODE the body reports whether the answer was correct ("body"),
+// and incorrect answers to successful callbacks are answered with 422 ("status").
+func (s *Service) writeCallbackResponse(w http.ResponseWriter, challengeID string, duplicate, late, answered, correct bool) {
+	response := models.CallbackResponse{
+		Received:    true,
+		ChallengeID: challengeID,
+		Duplicate:   duplicate,
+		Late:        late,
+	}
+
+	statusCode := http.StatusOK
+	switch s.config.CallbackCorrectness {
+	case "body":
+		response.Correct = &correct
+	case "status":
+		response.Correct = &correct
+		if answered && !correct {
+			statusCode = http.StatusUnprocessableEntity
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Service) writeError(w http.ResponseWriter, statusCode int, code, message, requestID string) {
+	errorResp := models.ErrorResponse{
+		Error: models.ErrorDetails{
+			Code:      code,
+			Message:   message,
+			RequestID: requestID,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorResp)
+}
+
+func (s *Service) serializeHeaders(headers http.Header) string {
+	var headerLines []string
+	for key, values := range headers {
+		for _, value := range values {
+			headerLines = append(headerLines, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+	return strings.Join(headerLines, "\n")
+}
+
+// ValidateCallbackURL checks a callback URL against CALLBACK_ALLOWED_HOSTS and the SSRF guard.
+func (s *Service) ValidateCallbackURL(callbackURL string) error {
+	policy := netutil.CallbackPolicy{
+		AllowedHosts:  s.config.CallbackAllowedHosts,
+		AllowlistOnly: s.config.UseNgrok,
+	}
+	return policy.ValidateCallbackURL(context.Background(), callbackURL)
+}
+
+// commitmentItem builds the on-chain commitment for a result
+func (s *Service) commitmentItem(result *models.Result) (sui.CommitmentItem, error) {
 	// Get type arguments from config
 	typeArgs := sui.TypeArgs{
 		TreasuryCapPositive: fmt.Sprintf("%s::pos::POS", s.config.SUI.PosPackageID),
@@ -412,57 +1173,91 @@ func (s *Service) uploadToSuiSync(challengeID string, result *models.Result, cal
 
 	registryID := s.config.SUI.RegistryID
 	if registryID == "" {
-		return nil, fmt.Errorf("SUI_REGISTRY_ID not configured")
+		return sui.CommitmentItem{}, fmt.Errorf("SUI_REGISTRY_ID not configured")
 	}
 
 	// Create commitment hash from challenge data
 	commitment := sha256.Sum256([]byte(fmt.Sprintf("%s:%s", registryID, result.ReceivedAnswer)))
 
-	// Get challenger address from environment (in production, this would come from authentication)
-	challengerAddr := s.suiTxBuilder.Signer().Address.String()
-
-	// Use solver address from the result data
-	solverAddr := result.SolverAddress
+	return sui.CommitmentItem{
+		TypeArgs:   typeArgs,
+		RegistryID: registryID,
+		Commitment: commitment[:],
+		// Get challenger address from environment (in production, this would come from authentication)
+		ChallengerAddr: s.suiTxBuilder.Signer().Address.String(),
+		// Use solver address from the result data
+		SolverAddr: result.SolverAddress,
+		// The verifier recomputes this with the same scorer, so keep them in sync
+		Score:     s.score(result),
+		Timestamp: uint64(result.CreatedAt.Unix()),
+	}, nil
+}
 
-	// Calculate score (simple example - in production this would be more sophisticated)
-	score := uint64(0)
-	if result.IsCorrect {
-		// FIXME we need to calculate this
-		score = 100
+// uploadToSuiSync uploads challenge commitment to Sui blockchain synchronously and returns the object ID
+func (s *Service) uploadToSuiSync(ctx context.Context, challengeID string, result *models.Result, callbackLogger zerolog.Logger) (*suigo.ObjectId, error) {
+	item, err := s.commitmentItem(result)
+	if err != nil {
+		return nil, err
 	}
 
-	timestamp := uint64(result.CreatedAt.Unix())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	callbackLogger.Info().
-		Str("registry_id", registryID).
-		Str("challenger_addr", challengerAddr).
-		Str("solver_addr", solverAddr).
-		Uint64("score", score).
-		Str("commitment_hex", hex.EncodeToString(commitment[:])).
+		Str("registry_id", item.RegistryID).
+		Str("challenger_addr", item.ChallengerAddr).
+		Str("solver_addr", item.SolverAddr).
+		Uint64("score", item.Score).
+		Str("commitment_hex", hex.EncodeToString(item.Commitment)).
 		Msg("Building challenge commitment transaction")
 
 	// Build the transaction first
 	objId, err := s.suiTxBuilder.UploadChallengeCommitment(
 		ctx,
-		typeArgs,
-		registryID,
-		commitment[:],
-		challengerAddr,
-		solverAddr,
-		score,
-		timestamp,
+		item.TypeArgs,
+		item.RegistryID,
+		item.Commitment,
+		item.ChallengerAddr,
+		item.SolverAddr,
+		item.Score,
+		item.Timestamp,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build challenge commitment transaction: %w", err)
 	}
 
+	s.recordCommitment(ctx, challengeID, result, item, objId, callbackLogger)
+	return objId, nil
+}
+
+// recordCommitment saves an uploaded commitment, announces it, and writes its object ID to the digest file
+func (s *Service) recordCommitment(ctx context.Context, challengeID string, result *models.Result, item sui.CommitmentItem, objId *suigo.ObjectId, callbackLogger zerolog.Logger) {
 	callbackLogger.Info().
 		Str("objId", objId.String()).
 		Msg("Challenge commitment successfully uploaded to Sui")
 
+	// Record the commitment so it can be attributed to the solver later
+	if err := s.db.SaveCommitment(ctx, &models.Commitment{
+		ChallengeID:    challengeID,
+		RequestID:      result.RequestID,
+		ObjectID:       objId.String(),
+		CommitmentHash: hex.EncodeToString(item.Commitment),
+		CreatedAt:      time.Now(),
+	}); err != nil {
+		callbackLogger.Error().Err(err).
+			Str("objId", objId.String()).
+			Msg("Failed to save commitment")
+		// Don't return error here as the upload was successful
+	}
+
+	s.publishEvent(ctx, events.Event{
+		Type:          events.CommitmentUploaded,
+		ChallengeID:   challengeID,
+		RequestID:     result.RequestID,
+		SolverAddress: item.SolverAddr,
+		ObjectID:      objId.String(),
+	}, callbackLogger)
+
 	// Write digest to file
 	if err := s.writeDigestToFile(objId.String(), callbackLogger); err != nil {
 		callbackLogger.Error().Err(err).
@@ -470,8 +1265,374 @@ func (s *Service) uploadToSuiSync(challengeID string, result *models.Result, cal
 			Msg("Failed to write transaction digest to file")
 		// Don't return error here as the upload was successful
 	}
+}
 
-	return objId, nil
+// SettleClosedWindows queues the commitment and payout of the best answer of every submission window that
+// closed at or before now. Each window is claimed before settling, so a winner is
+// committed at most once even if several challengers run the settlement loop.
+func (s *Service) SettleClosedWindows(ctx context.Context, now time.Time) error {
+	windows, err := s.db.ListClosedSubmissionWindows(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		settleLogger := logger.WithChallengeID(window.ChallengeID)
+
+		claimed, err := s.db.SettleSubmissionWindow(ctx, window.ChallengeID, now)
+		if err != nil {
+			settleLogger.Error().Err(err).Msg("Failed to settle submission window")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if window.BestRequestID == "" {
+			settleLogger.Info().Msg("Submission window closed without a correct answer")
+			continue
+		}
+
+		result, err := s.db.GetResult(ctx, window.ChallengeID, window.BestRequestID)
+		if err != nil || result == nil {
+			settleLogger.Error().Err(err).
+				Str("request_id", window.BestRequestID).
+				Msg("Failed to load winning result")
+			continue
+		}
+
+		if s.suiTxBuilder != nil {
+			if err := s.enqueueCommitment(ctx, window.ChallengeID, result.RequestID); err != nil {
+				settleLogger.Error().Err(err).Msg("Failed to queue winning result for Sui upload")
+			}
+		}
+
+		settleLogger.Info().
+			Str("request_id", result.RequestID).
+			Uint64("score", window.BestScore).
+			Msg("Submission window settled")
+
+		s.publishEvent(ctx, events.Event{
+			Type:          events.WindowSettled,
+			ChallengeID:   window.ChallengeID,
+			RequestID:     result.RequestID,
+			SolverAddress: result.SolverAddress,
+		}, settleLogger)
+	}
+
+	return nil
+}
+
+// ExpireChallenges marks every challenge whose deadline passed at or before now as expired and
+// publishes a challenge.expired event for it. Each challenge is claimed before the event is sent,
+// so concurrent sweeps report an expiry once. Callbacks are rejected from the deadline on either way.
+func (s *Service) ExpireChallenges(ctx context.Context, now time.Time) error {
+	challenges, err := s.db.ListExpiredChallenges(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, challenge := range challenges {
+		expireLogger := logger.WithChallengeID(challenge.ID)
+
+		claimed, err := s.db.MarkChallengeExpired(ctx, challenge.ID, now)
+		if err != nil {
+			expireLogger.Error().Err(err).Msg("Failed to mark challenge expired")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		expireLogger.Info().
+			Time("expires_at", *challenge.ExpiresAt).
+			Msg("Challenge expired")
+
+		s.publishEvent(ctx, events.Event{
+			Type:          events.ChallengeExpired,
+			ChallengeID:   challenge.ID,
+			ChallengeType: challenge.Type,
+		}, expireLogger)
+	}
+
+	return nil
+}
+
+// enqueueCommitment persists a commitment upload for the result and wakes the worker.
+func (s *Service) enqueueCommitment(ctx context.Context, challengeID, requestID string) error {
+	if err := s.db.EnqueueCommitmentJob(ctx, challengeID, requestID); err != nil {
+		return err
+	}
+
+	select {
+	case s.commitmentWake <- struct{}{}:
+	default:
+		// A wake-up is already pending; the worker lists every queued job
+	}
+	return nil
+}
+
+// RunCommitmentWorker uploads queued commitments to Sui and adds their bounties until ctx is
+// canceled. Jobs left over from a previous run are picked up on start, and failed uploads are
+// retried every commitmentRetryInterval until MaxCommitmentAttempts is reached. With batching
+// enabled, the worker waits COMMITMENT_BATCH_WINDOW_MS after a wake-up so callbacks arriving
+// close together share one transaction.
+func (s *Service) RunCommitmentWorker(ctx context.Context) {
+	workerLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.General)
+
+	ticker := time.NewTicker(commitmentRetryInterval)
+	defer ticker.Stop()
+
+	retry := true
+	for {
+		s.processCommitmentJobs(ctx, retry, workerLogger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.commitmentWake:
+			retry = false
+			if !s.waitForCommitmentBatch(ctx) {
+				return
+			}
+		case <-ticker.C:
+			retry = true
+		}
+	}
+}
+
+// waitForCommitmentBatch gives more commitments time to queue up before a batch is
+// flushed. It returns false if ctx is canceled while waiting.
+func (s *Service) waitForCommitmentBatch(ctx context.Context) bool {
+	window := s.config.GetCommitmentBatchWindow()
+	if s.config.CommitmentBatchSize <= 1 || window <= 0 {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(window):
+	}
+
+	// Wake-ups sent during the window are covered by the flush that follows
+	select {
+	case <-s.commitmentWake:
+	default:
+	}
+	return true
+}
+
+// processCommitmentJobs runs pending commitment jobs once, oldest first, in batches of up to
+// COMMITMENT_BATCH_SIZE. Jobs that already failed are only run when retry is set.
+func (s *Service) processCommitmentJobs(ctx context.Context, retry bool, workerLogger zerolog.Logger) {
+	jobs, err := s.db.ListCommitmentJobs(ctx, MaxCommitmentAttempts)
+	if err != nil {
+		if ctx.Err() == nil {
+			workerLogger.Error().Err(err).Msg("Failed to list commitment jobs")
+		}
+		return
+	}
+
+	pending := make([]*models.CommitmentJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.Attempts > 0 && !retry {
+			continue
+		}
+		pending = append(pending, job)
+	}
+
+	batchSize := max(s.config.CommitmentBatchSize, 1)
+	for len(pending) > 0 {
+		if ctx.Err() != nil {
+			return
+		}
+
+		n := min(batchSize, len(pending))
+		if n == 1 {
+			s.processCommitmentJob(ctx, pending[0], workerLogger)
+		} else {
+			s.processCommitmentBatch(ctx, pending[:n], workerLogger)
+		}
+		pending = pending[n:]
+	}
+}
+
+// commitmentJobLogger returns a logger tagged with the job's identifiers and attempt number
+func commitmentJobLogger(workerLogger zerolog.Logger, job *models.CommitmentJob) zerolog.Logger {
+	return workerLogger.With().
+		Str("challenge_id", job.ChallengeID).
+		Str("request_id", job.RequestID).
+		Int("attempt", job.Attempts+1).
+		Logger()
+}
+
+// failCommitmentJob logs err and counts it against the job's attempts
+func (s *Service) failCommitmentJob(ctx context.Context, job *models.CommitmentJob, err error, msg string, jobLogger zerolog.Logger) {
+	jobLogger.Error().Err(err).Msg(suiFailureMessage(msg, err))
+	if err := s.db.FailCommitmentJob(ctx, job.ChallengeID, job.RequestID, err.Error()); err != nil {
+		jobLogger.Error().Err(err).Msg("Failed to record commitment job failure")
+	}
+}
+
+// suiFailureMessage extends msg with what an operator should do about a Sui error.
+// Funding problems need the challenger wallet topped up and bad addresses need a config
+// fix; retrying alone resolves neither.
+func suiFailureMessage(msg string, err error) string {
+	switch {
+	case errors.Is(err, sui.ErrNoCoins), errors.Is(err, sui.ErrInsufficientGas):
+		return msg + ": challenger wallet cannot pay for gas, fund it to resume uploads"
+	case errors.Is(err, sui.ErrInvalidAddress):
+		return msg + ": invalid Sui address or object ID, check SUI_REGISTRY_ID, SUI_VAULT_ID and the solver address"
+	case errors.Is(err, sui.ErrTxFailed):
+		return msg + ": transaction failed on chain"
+	}
+	return msg
+}
+
+// loadCommitmentJobResult loads the result a job commits. It returns nil when the job cannot
+// run: a failed load is counted against the job, and a job whose result is gone is dropped.
+func (s *Service) loadCommitmentJobResult(ctx context.Context, job *models.CommitmentJob, jobLogger zerolog.Logger) *models.Result {
+	result, err := s.db.GetResult(ctx, job.ChallengeID, job.RequestID)
+	if err != nil {
+		s.failCommitmentJob(ctx, job, err, "Failed to load result for commitment upload", jobLogger)
+		return nil
+	}
+	if result == nil {
+		jobLogger.Warn().Msg("Result for commitment job no longer exists, dropping job")
+		if err := s.db.CompleteCommitmentJob(ctx, job.ChallengeID, job.RequestID); err != nil {
+			jobLogger.Error().Err(err).Msg("Failed to drop commitment job")
+		}
+	}
+	return result
+}
+
+// processCommitmentJob uploads one result's commitment in its own transaction
+func (s *Service) processCommitmentJob(ctx context.Context, job *models.CommitmentJob, workerLogger zerolog.Logger) {
+	jobLogger := commitmentJobLogger(workerLogger, job)
+
+	result := s.loadCommitmentJobResult(ctx, job, jobLogger)
+	if result == nil {
+		return
+	}
+
+	objId, err := s.uploadToSuiSync(ctx, job.ChallengeID, result, jobLogger)
+	if err != nil {
+		s.failCommitmentJob(ctx, job, err, "Failed to upload to Sui", jobLogger)
+		return
+	}
+
+	s.finishCommitmentJob(ctx, job, result, objId, jobLogger)
+}
+
+// processCommitmentBatch uploads the commitments of several jobs in a single transaction.
+// The batch succeeds or fails as a whole, so a failure counts against every job in it.
+func (s *Service) processCommitmentBatch(ctx context.Context, jobs []*models.CommitmentJob, workerLogger zerolog.Logger) {
+	var (
+		batchJobs []*models.CommitmentJob
+		results   []*models.Result
+		items     []sui.CommitmentItem
+	)
+	for _, job := range jobs {
+		jobLogger := commitmentJobLogger(workerLogger, job)
+
+		result := s.loadCommitmentJobResult(ctx, job, jobLogger)
+		if result == nil {
+			continue
+		}
+		item, err := s.commitmentItem(result)
+		if err != nil {
+			s.failCommitmentJob(ctx, job, err, "Failed to build commitment", jobLogger)
+			continue
+		}
+
+		batchJobs = append(batchJobs, job)
+		results = append(results, result)
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	workerLogger.Info().Int("batch_size", len(items)).Msg("Uploading challenge commitment batch")
+
+	objIds, err := s.suiTxBuilder.UploadChallengeCommitmentsBatch(uploadCtx, items)
+	for i, job := range batchJobs {
+		jobLogger := commitmentJobLogger(workerLogger, job)
+		if err != nil {
+			s.failCommitmentJob(ctx, job, err, "Failed to upload commitment batch to Sui", jobLogger)
+			continue
+		}
+
+		s.recordCommitment(ctx, job.ChallengeID, results[i], items[i], objIds[i], jobLogger)
+		s.finishCommitmentJob(ctx, job, results[i], objIds[i], jobLogger)
+	}
+}
+
+// finishCommitmentJob completes an uploaded job, adds the bounty, and stores the result's log
+// entry under the commitment object ID so the verifier can find it. With commitment events
+// subscribed, the log entry is stored once the event confirms the upload.
+func (s *Service) finishCommitmentJob(ctx context.Context, job *models.CommitmentJob, result *models.Result, objId *suigo.ObjectId, jobLogger zerolog.Logger) {
+	if err := s.db.CompleteCommitmentJob(ctx, job.ChallengeID, job.RequestID); err != nil {
+		jobLogger.Error().Err(err).Msg("Failed to complete commitment job")
+	}
+
+	// Add bounty to vault if vault ID is configured
+	if err := s.VaultAddBounty(s.config.SUI.VaultID); err != nil {
+		jobLogger.Warn().Err(err).Msg(suiFailureMessage("Failed to add bounty to vault", err))
+	} else {
+		s.publishEvent(ctx, events.Event{
+			Type:          events.BountySettled,
+			ChallengeID:   job.ChallengeID,
+			RequestID:     job.RequestID,
+			SolverAddress: result.SolverAddress,
+			VaultID:       s.config.SUI.VaultID,
+		}, jobLogger)
+	}
+
+	if s.confirmations != nil {
+		s.awaitConfirmation(objId.String(), job.ChallengeID, result, jobLogger)
+		return
+	}
+	if err := s.storeLogEntry(ctx, objId.String(), result, jobLogger); err != nil {
+		jobLogger.Error().Err(err).Msg("Failed to marshal result")
+	}
+}
+
+// storeLogEntry records the callback log entry for a result under logID. A local copy is kept
+// so verifiers can fall back to GET /api/logs/{id} when the external log service is
+// unreachable; the upload to the log service happens in the background.
+func (s *Service) storeLogEntry(ctx context.Context, logID string, result *models.Result, lg zerolog.Logger) error {
+	challengerAddr := ""
+	if s.suiTxBuilder != nil && s.suiTxBuilder.Signer() != nil {
+		challengerAddr = s.suiTxBuilder.Signer().Address.String()
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	entry := models.LogEntry{
+		ID:             logID,
+		Log:            string(b),
+		ChallengerAddr: challengerAddr,
+		SolverAddr:     result.SolverAddress,
+		// VerifierAddr left empty for now
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.db.SaveLogEntry(ctx, &entry); err != nil {
+		lg.Error().Err(err).Str("log_id", entry.ID).Msg("Failed to store log entry locally")
+	}
+
+	// Upload in the background; entries the log service keeps refusing are queued locally
+	go s.uploadLogWithRetry(context.Background(), entry, lg)
+
+	return nil
 }
 
 func (s *Service) VaultAddBounty(vaultId string) error {
@@ -504,23 +1665,145 @@ func (s *Service) writeDigestToFile(digest string, logger zerolog.Logger) error
 	return nil
 }
 
-// uploadCallbackLog uploads a callback log entry to the external log service
-func (s *Service) uploadCallbackLog(ctx context.Context, entry logEntry, lg zerolog.Logger) {
+// uploadLogWithRetry uploads a log entry to the external log service, retrying network errors,
+// 429 and 5xx responses with exponential backoff. An entry still undelivered after
+// LOG_UPLOAD_MAX_ATTEMPTS is queued in pending_log_uploads for RunLogUploadFlusher; one the
+// service rejects outright is only kept in the local log_entries copy.
+func (s *Service) uploadLogWithRetry(ctx context.Context, entry models.LogEntry, lg zerolog.Logger) {
 	if s.config.LogServiceURL == "" || s.config.LogServiceAPIKey == "" {
 		lg.Debug().Msg("Log service not configured; skipping upload")
 		return
 	}
 
-	jsonData, err := json.Marshal(entry)
+	maxAttempts, policy := s.logUploadPolicy()
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var retryable bool
+		retryable, err = s.uploadCallbackLog(ctx, entry)
+		if err == nil {
+			lg.Info().Str("log_id", entry.ID).Int("attempt", attempt+1).Msg("Log upload completed")
+			return
+		}
+		if !retryable {
+			lg.Error().Err(err).Str("log_id", entry.ID).Msg("Log service rejected upload; entry remains available locally")
+			return
+		}
+		if attempt == maxAttempts-1 {
+			break
+		}
+
+		delay := policy.Delay(attempt)
+		lg.Warn().Err(err).
+			Str("log_id", entry.ID).
+			Int("attempt", attempt+1).
+			Dur("delay", delay).
+			Msg("Log upload failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	if qerr := s.db.EnqueueLogUpload(ctx, &entry, err.Error()); qerr != nil {
+		lg.Error().Err(qerr).Str("log_id", entry.ID).Msg("Failed to queue log upload; entry remains available locally")
+		return
+	}
+	lg.Warn().Err(err).
+		Str("log_id", entry.ID).
+		Int("attempts", maxAttempts).
+		Msg("Log upload failed; queued for background flush")
+}
+
+// logUploadPolicy returns the attempts and backoff for log uploads, keeping defaults for
+// settings left unset.
+func (s *Service) logUploadPolicy() (int, backoff.Policy) {
+	maxAttempts := s.config.LogUploadMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultLogUploadAttempts
+	}
+	policy := backoff.Policy{
+		BaseDelay: defaultLogUploadBaseDelay,
+		MaxDelay:  logUploadMaxDelay,
+		JitterMin: 1 - logUploadJitter,
+		JitterMax: 1 + logUploadJitter,
+	}
+	if s.config.LogUploadBaseDelayMs > 0 {
+		policy.BaseDelay = time.Duration(s.config.LogUploadBaseDelayMs) * time.Millisecond
+	}
+	return maxAttempts, policy
+}
+
+// RunLogUploadFlusher retries the log uploads queued by uploadLogWithRetry every
+// LOG_UPLOAD_FLUSH_INTERVAL_SECONDS until ctx is canceled.
+func (s *Service) RunLogUploadFlusher(ctx context.Context) {
+	flushLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Challenger, logger.General)
+
+	ticker := time.NewTicker(s.config.GetLogUploadFlushInterval())
+	defer ticker.Stop()
+
+	for {
+		s.flushLogUploads(ctx, flushLogger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushLogUploads makes one upload attempt for each queued entry, oldest first. Delivered
+// entries leave the queue, as do entries the log service rejects outright.
+func (s *Service) flushLogUploads(ctx context.Context, lg zerolog.Logger) {
+	uploads, err := s.db.ListLogUploads(ctx, logUploadFlushBatch)
 	if err != nil {
-		lg.Error().Err(err).Msg("Failed to marshal log entry")
+		if ctx.Err() == nil {
+			lg.Error().Err(err).Msg("Failed to list queued log uploads")
+		}
 		return
 	}
 
+	for _, upload := range uploads {
+		if ctx.Err() != nil {
+			return
+		}
+
+		logID := upload.Entry.ID
+		retryable, err := s.uploadCallbackLog(ctx, upload.Entry)
+		switch {
+		case err == nil:
+			lg.Info().Str("log_id", logID).Msg("Queued log upload delivered")
+		case !retryable:
+			lg.Error().Err(err).Str("log_id", logID).Msg("Log service rejected queued upload; entry remains available locally")
+		default:
+			if ferr := s.db.FailLogUpload(ctx, logID, err.Error()); ferr != nil {
+				lg.Error().Err(ferr).Str("log_id", logID).Msg("Failed to record log upload failure")
+			}
+			continue
+		}
+
+		if err := s.db.CompleteLogUpload(ctx, logID); err != nil {
+			lg.Error().Err(err).Str("log_id", logID).Msg("Failed to remove queued log upload")
+		}
+	}
+}
+
+// uploadCallbackLog makes a single upload of a callback log entry to the external log service.
+// retryable reports whether a failure is worth retrying: network errors, 429 and 5xx.
+func (s *Service) uploadCallbackLog(ctx context.Context, entry models.LogEntry) (retryable bool, err error) {
+	jsonData, err := json.Marshal(entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, logUploadTimeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, "POST", s.config.LogServiceURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		lg.Error().Err(err).Msg("Failed to create log upload request")
-		return
+		return false, fmt.Errorf("failed to create log upload request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -528,13 +1811,13 @@ func (s *Service) uploadCallbackLog(ctx context.Context, entry logEntry, lg zero
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		lg.Error().Err(err).Msg("Log upload request failed")
-		return
+		return true, fmt.Errorf("log upload request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	lg.Info().
-		Str("status", resp.Status).
-		Int("status_code", resp.StatusCode).
-		Msg("Log upload completed")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return retryable, fmt.Errorf("log service returned status %d", resp.StatusCode)
+	}
+	return false, nil
 }
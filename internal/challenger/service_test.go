@@ -1,14 +1,38 @@
 package challenger
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"reverse-challenge-system/pkg/auth"
 	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/events"
 	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/metrics"
+	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/sui"
+	"reverse-challenge-system/pkg/validator"
 
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/gorilla/mux"
+	suigo "github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/suicrypto"
 	"github.com/rs/zerolog"
 )
 
@@ -183,10 +207,1932 @@ func TestWriteDigestToFileOverwrite(t *testing.T) {
 	}
 }
 
+// newTestServiceWithDB creates a service backed by a temporary challenger database
+// seeded with a single challenge whose secret answer is "secret_answer".
+func newTestServiceWithDB(t *testing.T) (*Service, *models.Challenge) {
+	t.Helper()
+
+	database, err := db.NewChallengerDB(filepath.Join(t.TempDir(), "challenger.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	challenge := &models.Challenge{
+		ID:             "ch_redact",
+		Type:           "text",
+		Problem:        json.RawMessage(`{"type":"text","text":"hello"}`),
+		OutputSpec:     json.RawMessage(`{"content_type":"text/plain"}`),
+		ValidationRule: validator.CreateExactMatchRule("secret_answer", true),
+		CreatedAt:      time.Now(),
+	}
+	if err := database.CreateChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("failed to create challenge: %v", err)
+	}
+
+	service := &Service{
+		config:    &config.Config{LogLevel: "info"},
+		db:        database,
+		validator: validator.NewValidator(),
+		events:    events.NopPublisher{},
+	}
+	return service, challenge
+}
+
+func TestSendChallengeWithPriority(t *testing.T) {
+	received := make(chan models.SolveRequest, 1)
+	solver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var solveReq models.SolveRequest
+		if err := json.NewDecoder(r.Body).Decode(&solveReq); err != nil {
+			t.Errorf("failed to decode solve request: %v", err)
+		}
+		received <- solveReq
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(models.SolveResponse{Message: "Challenge accepted", SolverJobID: "solver_job_prio"})
+	}))
+	defer solver.Close()
+
+	service, challenge := newTestServiceWithDB(t)
+	service.config.SolverHMACKeyID = "solver-kid"
+	service.hmacAuth = auth.NewHMACAuth(map[string]string{"solver-kid": "secret"}, 300*time.Second)
+	service.client = solver.Client()
+
+	if err := service.SendChallengeWithPriority(context.Background(), challenge.ID, solver.URL, 7); err != nil {
+		t.Fatalf("SendChallengeWithPriority() unexpected error: %v", err)
+	}
+
+	solveReq := <-received
+	if solveReq.Priority != 7 {
+		t.Errorf("expected priority 7 in the solve request, got %d", solveReq.Priority)
+	}
+	if dispatched, err := service.db.HasDispatchedJob(context.Background(), challenge.ID, "solver_job_prio"); err != nil || !dispatched {
+		t.Errorf("expected the solver job to be recorded, got %v, %v", dispatched, err)
+	}
+}
+
+func TestSendChallengeToManyFirstCorrectWins(t *testing.T) {
+	newSolver := func(jobID string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(models.SolveResponse{Message: "Challenge accepted", SolverJobID: jobID})
+		}))
+	}
+	first, second := newSolver("solver_job_first"), newSolver("solver_job_second")
+	defer first.Close()
+	defer second.Close()
+
+	service, challenge := newTestServiceWithDB(t)
+	service.config.SolverHMACKeyID = "solver-kid"
+	service.hmacAuth = auth.NewHMACAuth(map[string]string{"solver-kid": "secret"}, 300*time.Second)
+	service.client = first.Client()
+
+	// A transaction builder makes callbacks queue commitments; no worker runs to upload them
+	signer := suisigner.NewSigner(make([]byte, 32), suicrypto.KeySchemeFlagEd25519)
+	txBuilder, err := sui.NewTransactionBuilderWithClient(&sui.MockSuiClient{}, "0x1234567890abcdef1234567890abcdef12345678", signer, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to create transaction builder: %v", err)
+	}
+	service.suiTxBuilder = txBuilder
+
+	ctx := context.Background()
+	if err := service.SendChallengeToMany(ctx, challenge.ID, []string{first.URL, second.URL}); err != nil {
+		t.Fatalf("SendChallengeToMany() unexpected error: %v", err)
+	}
+
+	firstAddress := suigo.MustAddressFromHex("0xa11ce").String()
+	secondAddress := suigo.MustAddressFromHex("0xb0b").String()
+	callback := func(jobID, requestID, solverAddress string) models.CallbackResponse {
+		req := newCallbackRequest(t, challenge.ID, jobID, requestID)
+		req.Header.Set("X-Solver-Address", solverAddress)
+		rr := httptest.NewRecorder()
+		service.HandleCallback(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d: %s", requestID, rr.Code, rr.Body.String())
+		}
+		var resp models.CallbackResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode callback response: %v", err)
+		}
+		return resp
+	}
+
+	if resp := callback("solver_job_first", "req_first", firstAddress); resp.Late {
+		t.Error("expected the first correct answer not to be late")
+	}
+	if resp := callback("solver_job_second", "req_second", secondAddress); !resp.Late {
+		t.Error("expected the second correct answer to be reported late")
+	}
+
+	winner, err := service.db.GetResult(ctx, challenge.ID, "req_first")
+	if err != nil || winner == nil || winner.IsLate || !winner.IsCorrect {
+		t.Errorf("expected the first result to be the correct winner, got %+v, %v", winner, err)
+	}
+	late, err := service.db.GetResult(ctx, challenge.ID, "req_second")
+	if err != nil || late == nil || !late.IsLate || !late.IsCorrect {
+		t.Errorf("expected the second result to be stored correct but late, got %+v, %v", late, err)
+	}
+
+	stored, err := service.db.GetChallenge(ctx, challenge.ID)
+	if err != nil {
+		t.Fatalf("failed to get challenge: %v", err)
+	}
+	if !stored.Competitive || stored.WinnerRequestID != "req_first" || stored.WinnerSolverAddress != firstAddress || stored.ClosedAt == nil {
+		t.Errorf("expected the challenge closed by the first solver, got %+v", stored)
+	}
+
+	// Only the winner is committed on-chain, so only the winner can be paid
+	jobs, err := service.db.ListCommitmentJobs(ctx, MaxCommitmentAttempts)
+	if err != nil {
+		t.Fatalf("failed to list commitment jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].RequestID != "req_first" {
+		t.Errorf("expected a single commitment for the winner, got %+v", jobs)
+	}
+}
+
+func TestSendChallengeCallbackBeforeAccepted(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	// The solver finishes and calls back before its 202 response reaches the challenger
+	callbackStatus := make(chan int, 1)
+	solver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rr := httptest.NewRecorder()
+		service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_"+challenge.ID, "req_fast"))
+		callbackStatus <- rr.Code
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(models.SolveResponse{Message: "Challenge accepted", SolverJobID: "solver_job_" + challenge.ID})
+	}))
+	defer solver.Close()
+
+	service.config.SolverHMACKeyID = "solver-kid"
+	service.hmacAuth = auth.NewHMACAuth(map[string]string{"solver-kid": "secret"}, 300*time.Second)
+	service.client = solver.Client()
+
+	if err := service.SendChallenge(context.Background(), challenge.ID, solver.URL); err != nil {
+		t.Fatalf("SendChallenge() unexpected error: %v", err)
+	}
+	if code := <-callbackStatus; code != http.StatusOK {
+		t.Errorf("expected the early callback to be accepted, got status %d", code)
+	}
+}
+
+func TestSendChallengeRejectedForgetsJob(t *testing.T) {
+	solver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer solver.Close()
+
+	service, challenge := newTestServiceWithDB(t)
+	service.config.SolverHMACKeyID = "solver-kid"
+	service.hmacAuth = auth.NewHMACAuth(map[string]string{"solver-kid": "secret"}, 300*time.Second)
+	service.client = solver.Client()
+
+	ctx := context.Background()
+	if err := service.SendChallenge(ctx, challenge.ID, solver.URL); err == nil {
+		t.Fatal("expected an error for a rejected dispatch")
+	}
+	if dispatched, err := service.db.HasDispatchedJob(ctx, challenge.ID, "solver_job_"+challenge.ID); err != nil || dispatched {
+		t.Errorf("expected the rejected job to be forgotten, got %v, %v", dispatched, err)
+	}
+}
+
+func TestSendChallengeRetriesUntilDelivered(t *testing.T) {
+	var failures atomic.Int32
+	solver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(models.SolveResponse{Message: "Challenge accepted", SolverJobID: "solver_job_retry"})
+	}))
+	defer solver.Close()
+
+	newService := func(t *testing.T) (*Service, *models.Challenge) {
+		service, challenge := newTestServiceWithDB(t)
+		service.config.SolverHMACKeyID = "solver-kid"
+		service.config.DispatchBaseDelayMs = 1
+		service.hmacAuth = auth.NewHMACAuth(map[string]string{"solver-kid": "secret"}, 300*time.Second)
+		service.client = solver.Client()
+		return service, challenge
+	}
+	ctx := context.Background()
+
+	t.Run("inline retry", func(t *testing.T) {
+		service, challenge := newService(t)
+		failures.Store(2)
+
+		if err := service.SendChallenge(ctx, challenge.ID, solver.URL); err != nil {
+			t.Fatalf("SendChallenge() unexpected error: %v", err)
+		}
+		if dispatched, err := service.db.HasDispatchedJob(ctx, challenge.ID, "solver_job_retry"); err != nil || !dispatched {
+			t.Errorf("expected the solver job to be recorded, got %v, %v", dispatched, err)
+		}
+	})
+
+	t.Run("redispatch", func(t *testing.T) {
+		service, challenge := newService(t)
+		service.config.DispatchMaxAttempts = 1
+		failures.Store(1)
+
+		err := service.SendChallenge(ctx, challenge.ID, solver.URL)
+		if !errors.Is(err, ErrDispatchQueued) {
+			t.Fatalf("expected ErrDispatchQueued, got %v", err)
+		}
+		queued, err := service.db.ListDispatches(ctx, 10)
+		if err != nil || len(queued) != 1 || queued[0].ChallengeID != challenge.ID {
+			t.Fatalf("expected the challenge to be queued, got %v, %v", queued, err)
+		}
+
+		service.redispatch(ctx, zerolog.Nop())
+
+		if dispatched, err := service.db.HasDispatchedJob(ctx, challenge.ID, "solver_job_retry"); err != nil || !dispatched {
+			t.Errorf("expected the queued challenge to be delivered, got %v, %v", dispatched, err)
+		}
+		if queued, err := service.db.ListDispatches(ctx, 10); err != nil || len(queued) != 0 {
+			t.Errorf("expected the queue to be empty after delivery, got %v, %v", queued, err)
+		}
+	})
+}
+
+func TestSendChallengeCanceled(t *testing.T) {
+	release := make(chan struct{})
+	solver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer solver.Close()
+	defer close(release)
+
+	service, challenge := newTestServiceWithDB(t)
+	service.config.SolverHMACKeyID = "solver-kid"
+	service.hmacAuth = auth.NewHMACAuth(map[string]string{"solver-kid": "secret"}, 300*time.Second)
+	service.client = solver.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := service.SendChallenge(ctx, challenge.ID, solver.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a context.Canceled error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the send to abort promptly, took %v", elapsed)
+	}
+}
+
+func TestHandleGetChallengeRedactsAnswer(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	req := httptest.NewRequest("GET", "/challenges/"+challenge.ID, nil)
+	req = mux.SetURLVars(req, map[string]string{"challenge_id": challenge.ID})
+	rr := httptest.NewRecorder()
+
+	service.HandleGetChallenge(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	if strings.Contains(rr.Body.String(), "secret_answer") {
+		t.Errorf("response leaked the secret answer: %s", rr.Body.String())
+	}
+
+	var got models.Challenge
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if got.ID != challenge.ID {
+		t.Errorf("expected challenge ID %q, got %q", challenge.ID, got.ID)
+	}
+	if got.ValidationRule.Type != "ExactMatch" {
+		t.Errorf("expected validation type to be preserved, got %q", got.ValidationRule.Type)
+	}
+	if got.ValidationRule.Answer != "" {
+		t.Errorf("expected redacted answer, got %q", got.ValidationRule.Answer)
+	}
+
+	// The stored challenge must be unaffected by redaction
+	stored, err := service.db.GetChallenge(context.Background(), challenge.ID)
+	if err != nil {
+		t.Fatalf("failed to reload challenge: %v", err)
+	}
+	if stored.ValidationRule.Answer != "secret_answer" {
+		t.Errorf("stored answer was modified: %q", stored.ValidationRule.Answer)
+	}
+}
+
+// getRedactedChallenge stores a challenge validated by rule and returns the body
+// HandleGetChallenge serves for it.
+func getRedactedChallenge(t *testing.T, service *Service, id string, rule models.ValidationRule) string {
+	t.Helper()
+
+	challenge := &models.Challenge{
+		ID:             id,
+		Type:           "text",
+		Problem:        json.RawMessage(`{"type":"text","text":"hello"}`),
+		OutputSpec:     json.RawMessage(`{"content_type":"text/plain"}`),
+		ValidationRule: rule,
+		CreatedAt:      time.Now(),
+	}
+	if err := service.db.CreateChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("failed to create challenge: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/challenges/"+id, nil)
+	req = mux.SetURLVars(req, map[string]string{"challenge_id": id})
+	rr := httptest.NewRecorder()
+	service.HandleGetChallenge(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	return rr.Body.String()
+}
+
+func TestHandleGetChallengeRedactsSetMembershipAnswers(t *testing.T) {
+	service, _ := newTestServiceWithDB(t)
+
+	body := getRedactedChallenge(t, service, "ch_set", validator.CreateSetMembershipRule([]string{"paris", "lutetia"}, true))
+	if strings.Contains(body, "paris") || strings.Contains(body, "lutetia") {
+		t.Errorf("response leaked accepted answers: %s", body)
+	}
+
+	var got models.Challenge
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var params models.SetMembershipParams
+	if err := json.Unmarshal(got.ValidationRule.Params, &params); err != nil {
+		t.Fatalf("failed to decode params: %v", err)
+	}
+	if !params.CaseSensitive {
+		t.Error("expected case_sensitive to be preserved")
+	}
+}
+
+func TestHandleGetChallengeRedactsNestedAnswers(t *testing.T) {
+	service, _ := newTestServiceWithDB(t)
+
+	composite := validator.CreateCompositeRule("any", "",
+		validator.CreateExactMatchRule("composite_child_answer", true),
+		validator.CreateSetMembershipRule([]string{"composite_set_answer"}, false),
+	)
+	pipelineRule := validator.CreateExactMatchRule("pipeline_stage_answer", false)
+	pipeline := validator.CreatePipelineRule("",
+		models.PipelineStage{Name: "trim", Transform: "trim"},
+		models.PipelineStage{Name: "check", Rule: &pipelineRule},
+		models.PipelineStage{Name: "nested", Rule: &composite},
+	)
+
+	body := getRedactedChallenge(t, service, "ch_nested", pipeline)
+	for _, answer := range []string{"composite_child_answer", "composite_set_answer", "pipeline_stage_answer"} {
+		if strings.Contains(body, answer) {
+			t.Errorf("response leaked nested answer %q: %s", answer, body)
+		}
+	}
+
+	var got models.Challenge
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var params models.PipelineParams
+	if err := json.Unmarshal(got.ValidationRule.Params, &params); err != nil {
+		t.Fatalf("failed to decode params: %v", err)
+	}
+	if len(params.Stages) != 3 || params.Stages[0].Transform != "trim" || params.Stages[1].Rule.Type != "ExactMatch" {
+		t.Errorf("expected the pipeline structure to be preserved, got %+v", params.Stages)
+	}
+}
+
+func TestHandleGetChallengeUsesReadStore(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	// Only the read store knows the challenge; the primary is empty
+	primary, err := db.NewChallengerDB(filepath.Join(t.TempDir(), "primary.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { primary.Close() })
+	service.SetReadStore(service.db)
+	service.db = primary
+
+	req := httptest.NewRequest("GET", "/challenges/"+challenge.ID, nil)
+	req = mux.SetURLVars(req, map[string]string{"challenge_id": challenge.ID})
+	rr := httptest.NewRecorder()
+
+	service.HandleGetChallenge(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 from read store, got %d", rr.Code)
+	}
+}
+
+func TestHandleGetChallengeNotFound(t *testing.T) {
+	service, _ := newTestServiceWithDB(t)
+
+	req := httptest.NewRequest("GET", "/challenges/missing", nil)
+	req = mux.SetURLVars(req, map[string]string{"challenge_id": "missing"})
+	rr := httptest.NewRecorder()
+
+	service.HandleGetChallenge(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
+
+func TestHandleListResults(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	for _, requestID := range []string{"req_1", "req_2"} {
+		err := service.db.SaveResult(context.Background(), &models.Result{
+			ChallengeID:    challenge.ID,
+			RequestID:      requestID,
+			Status:         "success",
+			ReceivedAnswer: "guess",
+			CreatedAt:      time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("failed to save result: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/challenges/"+challenge.ID+"/results", nil)
+	req = mux.SetURLVars(req, map[string]string{"challenge_id": challenge.ID})
+	rr := httptest.NewRecorder()
+
+	service.HandleListResults(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var results []models.Result
+	if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+}
+
+func TestHandleStats(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	for i, correct := range []bool{true, false} {
+		err := service.db.SaveResult(context.Background(), &models.Result{
+			ChallengeID:   challenge.ID,
+			RequestID:     "req_" + strconv.Itoa(i),
+			Status:        "success",
+			IsCorrect:     correct,
+			ComputeTimeMs: 50,
+			CreatedAt:     time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("failed to save result: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	service.HandleStats(rr, httptest.NewRequest("GET", "/stats", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var stats models.ChallengerStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if stats.TotalChallenges != 1 || stats.SolvedChallenges != 1 || stats.TotalResults != 2 {
+		t.Errorf("unexpected counts: %+v", stats)
+	}
+	if stats.CorrectRatio != 0.5 || stats.AvgComputeTimeMs != 50 || stats.StatusBreakdown["success"] != 2 {
+		t.Errorf("unexpected aggregates: %+v", stats)
+	}
+}
+
+func TestHandleListSolverCommitments(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	ctx := context.Background()
+
+	for _, requestID := range []string{"req_1", "req_2"} {
+		if err := service.db.SaveResult(ctx, &models.Result{
+			ChallengeID:   challenge.ID,
+			RequestID:     requestID,
+			Status:        "success",
+			IsCorrect:     true,
+			SolverAddress: "0xsolver",
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to save result: %v", err)
+		}
+		if err := service.db.SaveCommitment(ctx, &models.Commitment{
+			ChallengeID:    challenge.ID,
+			RequestID:      requestID,
+			ObjectID:       "0xobj_" + requestID,
+			CommitmentHash: "hash",
+			CreatedAt:      time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to save commitment: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLen    int
+	}{
+		{"Default", "", http.StatusOK, 2},
+		{"Paged", "?limit=1&offset=1", http.StatusOK, 1},
+		{"InvalidLimit", "?limit=0", http.StatusBadRequest, 0},
+		{"InvalidOffset", "?offset=-1", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/solvers/0xsolver/commitments"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"address": "0xsolver"})
+			rr := httptest.NewRecorder()
+
+			service.HandleListSolverCommitments(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp models.SolverCommitmentsResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Total != 2 || len(resp.Commitments) != tt.wantLen {
+				t.Errorf("expected %d of 2 commitments, got %d of %d", tt.wantLen, len(resp.Commitments), resp.Total)
+			}
+		})
+	}
+}
+
+func TestHandleLeaderboard(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	ctx := context.Background()
+
+	results := []struct {
+		solver  string
+		correct bool
+	}{
+		{"0xslow", true},
+		{"0xfast", true},
+		{"0xfast", true},
+		{"0xwrong", false},
+	}
+	for i, r := range results {
+		if err := service.db.SaveResult(ctx, &models.Result{
+			ChallengeID:   challenge.ID,
+			RequestID:     fmt.Sprintf("req_%d", i),
+			Status:        "success",
+			IsCorrect:     r.correct,
+			SolverAddress: r.solver,
+			ComputeTimeMs: 100,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to save result: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       []string // Solver addresses, best first
+	}{
+		{"Default", "", http.StatusOK, []string{"0xfast", "0xslow", "0xwrong"}},
+		{"Limited", "?limit=1", http.StatusOK, []string{"0xfast"}},
+		{"InvalidLimit", "?limit=0", http.StatusBadRequest, nil},
+		{"LimitTooLarge", fmt.Sprintf("?limit=%d", MaxLeaderboardSize+1), http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			service.HandleLeaderboard(rr, httptest.NewRequest("GET", "/leaderboard"+tt.query, nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var leaderboard []models.SolverScore
+			if err := json.Unmarshal(rr.Body.Bytes(), &leaderboard); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(leaderboard) != len(tt.want) {
+				t.Fatalf("expected %d solvers, got %+v", len(tt.want), leaderboard)
+			}
+			for i, score := range leaderboard {
+				if score.SolverAddress != tt.want[i] {
+					t.Errorf("expected rank %d to be %s, got %s", i+1, tt.want[i], score.SolverAddress)
+				}
+			}
+			if leaderboard[0].Attempts != 2 || leaderboard[0].Correct != 2 || leaderboard[0].Score != 200 {
+				t.Errorf("unexpected aggregates for the leader: %+v", leaderboard[0])
+			}
+		})
+	}
+}
+
+func TestHandleListAudits(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Minute)
+	for i, challengeID := range []string{challenge.ID, "ch_other", challenge.ID} {
+		if err := service.db.SaveWebhookAudit(ctx, &models.WebhookAudit{
+			ChallengeID: challengeID,
+			RequestID:   fmt.Sprintf("req_%d", i),
+			StatusCode:  http.StatusOK,
+			CreatedAt:   base.Add(time.Duration(i) * time.Second),
+		}); err != nil {
+			t.Fatalf("failed to save webhook audit: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       []string // Request IDs, newest first
+	}{
+		{"Default", "", http.StatusOK, []string{"req_2", "req_1", "req_0"}},
+		{"ByChallenge", "?challenge_id=" + challenge.ID, http.StatusOK, []string{"req_2", "req_0"}},
+		{"Limited", "?limit=1", http.StatusOK, []string{"req_2"}},
+		{"InvalidLimit", "?limit=0", http.StatusBadRequest, nil},
+		{"LimitTooLarge", fmt.Sprintf("?limit=%d", MaxAuditPageSize+1), http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/audits"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			service.HandleListAudits(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var audits []*models.WebhookAudit
+			if err := json.Unmarshal(rr.Body.Bytes(), &audits); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(audits) != len(tt.want) {
+				t.Fatalf("expected %d audits, got %d", len(tt.want), len(audits))
+			}
+			for i, audit := range audits {
+				if audit.RequestID != tt.want[i] {
+					t.Errorf("expected audit %d to be %s, got %s", i, tt.want[i], audit.RequestID)
+				}
+			}
+		})
+	}
+}
+
+// newCallbackRequest builds a callback for the challenge as if routed by mux.
+func newCallbackRequest(t *testing.T, challengeID, solverJobID, requestID string) *http.Request {
+	t.Helper()
+	return newCallbackRequestWithMetadata(t, challengeID, solverJobID, requestID, nil)
+}
+
+// newCallbackRequestWithMetadata is newCallbackRequest with solver metadata attached.
+func newCallbackRequestWithMetadata(t *testing.T, challengeID, solverJobID, requestID string, metadata json.RawMessage) *http.Request {
+	t.Helper()
+	return newCallbackRequestFrom(t, requestID, models.CallbackRequest{
+		APIVersion:  "v2.1",
+		ChallengeID: challengeID,
+		SolverJobID: solverJobID,
+		Status:      "success",
+		Answer:      "secret_answer",
+		Metadata:    metadata,
+	})
+}
+
+// newCallbackRequestFrom builds a routed callback request carrying the given body.
+func newCallbackRequestFrom(t *testing.T, requestID string, callbackReq models.CallbackRequest) *http.Request {
+	t.Helper()
+
+	body, err := json.Marshal(callbackReq)
+	if err != nil {
+		t.Fatalf("failed to marshal callback: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/callback/"+callbackReq.ChallengeID, bytes.NewReader(body))
+	req.Header.Set("X-Request-ID", requestID)
+	req.Header.Set("X-Solver-Address", testSolverAddress)
+	return mux.SetURLVars(req, map[string]string{"challenge_id": callbackReq.ChallengeID})
+}
+
+// testSolverAddress is the canonical Sui address test callbacks are sent from
+var testSolverAddress = suigo.MustAddressFromHex("0xb0b").String()
+
+func TestHandleCallbackSolverAddress(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		allowMissing bool
+		wantStatus   int
+		wantAddress  string
+	}{
+		{name: "canonical address", header: testSolverAddress, wantStatus: http.StatusOK, wantAddress: testSolverAddress},
+		{name: "short address is canonicalized", header: "0xB0B", wantStatus: http.StatusOK, wantAddress: testSolverAddress},
+		{name: "missing", header: "", wantStatus: http.StatusBadRequest},
+		{name: "missing allowed for local testing", header: "", allowMissing: true, wantStatus: http.StatusOK, wantAddress: suigo.Address{}.String()},
+		{name: "not hex", header: "0xnot-an-address", allowMissing: true, wantStatus: http.StatusBadRequest},
+		{name: "no 0x prefix", header: "b0b", wantStatus: http.StatusBadRequest},
+		{name: "empty hex", header: "0x", wantStatus: http.StatusBadRequest},
+		{name: "too long", header: "0x" + strings.Repeat("ab", 33), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, challenge := newTestServiceWithDB(t)
+			service.config.AllowNoSolverAddress = tt.allowMissing
+			if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_addr"); err != nil {
+				t.Fatalf("failed to save dispatched job: %v", err)
+			}
+
+			req := newCallbackRequest(t, challenge.ID, "solver_job_addr", "req_addr")
+			if tt.header == "" {
+				req.Header.Del("X-Solver-Address")
+			} else {
+				req.Header.Set("X-Solver-Address", tt.header)
+			}
+
+			rr := httptest.NewRecorder()
+			service.HandleCallback(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+
+			result, err := service.db.GetResult(context.Background(), challenge.ID, "req_addr")
+			if tt.wantStatus != http.StatusOK {
+				var errResp models.ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if errResp.Error.Code != "INVALID_SOLVER_ADDRESS" {
+					t.Errorf("expected error code INVALID_SOLVER_ADDRESS, got %q", errResp.Error.Code)
+				}
+				if err == nil && result != nil {
+					t.Error("expected rejected callback not to be stored")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to get result: %v", err)
+			}
+			if result.SolverAddress != tt.wantAddress {
+				t.Errorf("expected solver address %s, got %s", tt.wantAddress, result.SolverAddress)
+			}
+		})
+	}
+}
+
+func TestHandleCallbackSolverSignature(t *testing.T) {
+	solverSigner := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suicrypto.KeySchemeFlagEd25519)
+	otherSigner := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suicrypto.KeySchemeFlagEd25519)
+
+	tests := []struct {
+		name       string
+		signer     *suisigner.Signer // Signs the body; nil sends no signature
+		tamper     bool              // Changes the body after signing
+		require    bool
+		wantStatus int
+	}{
+		{name: "valid signature", signer: solverSigner, wantStatus: http.StatusOK},
+		{name: "key of another address", signer: otherSigner, wantStatus: http.StatusUnauthorized},
+		{name: "body changed after signing", signer: solverSigner, tamper: true, wantStatus: http.StatusUnauthorized},
+		{name: "unsigned accepted by default", wantStatus: http.StatusOK},
+		{name: "unsigned rejected when required", require: true, wantStatus: http.StatusUnauthorized},
+		{name: "valid signature when required", signer: solverSigner, require: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, challenge := newTestServiceWithDB(t)
+			service.config.RequireSolverSignature = tt.require
+			if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_sig"); err != nil {
+				t.Fatalf("failed to save dispatched job: %v", err)
+			}
+
+			callback := models.CallbackRequest{
+				APIVersion:  "v2.1",
+				ChallengeID: challenge.ID,
+				SolverJobID: "solver_job_sig",
+				Status:      "success",
+				Answer:      "secret_answer",
+			}
+			body, err := json.Marshal(callback)
+			if err != nil {
+				t.Fatalf("failed to marshal callback: %v", err)
+			}
+			var signature, pubkey string
+			if tt.signer != nil {
+				if signature, pubkey, err = sui.SignCallback(tt.signer, body); err != nil {
+					t.Fatalf("failed to sign callback: %v", err)
+				}
+			}
+			if tt.tamper {
+				callback.Answer = "other_answer"
+			}
+
+			req := newCallbackRequestFrom(t, "req_sig", callback)
+			req.Header.Set("X-Solver-Address", solverSigner.Address.String())
+			if tt.signer != nil {
+				req.Header.Set(sui.CallbackSignatureHeader, signature)
+				req.Header.Set(sui.CallbackPubkeyHeader, pubkey)
+			}
+
+			rr := httptest.NewRecorder()
+			service.HandleCallback(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			result, _ := service.db.GetResult(context.Background(), challenge.ID, "req_sig")
+			if tt.wantStatus != http.StatusOK {
+				var errResp models.ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if errResp.Error.Code != "INVALID_SOLVER_SIGNATURE" {
+					t.Errorf("expected error code INVALID_SOLVER_SIGNATURE, got %q", errResp.Error.Code)
+				}
+				if result != nil {
+					t.Error("expected rejected callback not to be stored")
+				}
+				return
+			}
+			if result == nil || result.SolverAddress != solverSigner.Address.String() {
+				t.Errorf("expected the result to record %s, got %+v", solverSigner.Address, result)
+			}
+		})
+	}
+}
+
+func TestHandleCallbackRejectsUnknownJob(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_real"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_forged", "req_forged"))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp.Error.Code != "UNKNOWN_JOB" {
+		t.Errorf("expected error code UNKNOWN_JOB, got %q", errResp.Error.Code)
+	}
+
+	// No result should have been stored for the forged callback
+	if result, err := service.db.GetResult(context.Background(), challenge.ID, "req_forged"); err == nil && result != nil {
+		t.Error("expected forged callback not to be stored")
+	}
+}
+
+func TestHandleCallbackAcceptsDispatchedJob(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+
+	if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_real"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_real", "req_real"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	result, err := service.db.GetResult(context.Background(), challenge.ID, "req_real")
+	if err != nil {
+		t.Fatalf("failed to get result: %v", err)
+	}
+	if !result.IsCorrect {
+		t.Error("expected stored result to be marked correct")
+	}
+}
+
+func TestHandleCallbackRejectsExpiredChallenge(t *testing.T) {
+	tests := []struct {
+		name       string
+		expiresIn  time.Duration
+		wantStatus int
+	}{
+		{name: "before deadline", expiresIn: time.Hour, wantStatus: http.StatusOK},
+		{name: "after deadline", expiresIn: -time.Minute, wantStatus: http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, challenge := newTestServiceWithDB(t)
+			ctx := context.Background()
+
+			if err := service.db.SaveDispatchedJob(ctx, challenge.ID, "solver_job_ttl"); err != nil {
+				t.Fatalf("failed to save dispatched job: %v", err)
+			}
+			if err := service.db.SetChallengeExpiry(ctx, challenge.ID, time.Now().Add(tt.expiresIn)); err != nil {
+				t.Fatalf("failed to set challenge expiry: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_ttl", "req_ttl"))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var errResp models.ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if errResp.Error.Code != "CHALLENGE_EXPIRED" {
+				t.Errorf("expected error code CHALLENGE_EXPIRED, got %q", errResp.Error.Code)
+			}
+			if result, err := service.db.GetResult(ctx, challenge.ID, "req_ttl"); err == nil && result != nil {
+				t.Error("expected late callback not to be stored")
+			}
+		})
+	}
+}
+
+func TestExpireChallengesPublishesOnce(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	publisher := &fakePublisher{}
+	service.SetEventPublisher(publisher)
+	ctx := context.Background()
+
+	if err := service.db.SetChallengeExpiry(ctx, challenge.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("failed to set challenge expiry: %v", err)
+	}
+
+	// A second sweep must find nothing left to expire
+	for i := 0; i < 2; i++ {
+		if err := service.ExpireChallenges(ctx, time.Now()); err != nil {
+			t.Fatalf("ExpireChallenges() unexpected error: %v", err)
+		}
+	}
+
+	expired := eventsOfType(publisher.published(), events.ChallengeExpired)
+	if len(expired) != 1 || expired[0].ChallengeID != challenge.ID {
+		t.Fatalf("expected 1 challenge.expired event, got %+v", expired)
+	}
+
+	stored, err := service.db.GetChallenge(ctx, challenge.ID)
+	if err != nil {
+		t.Fatalf("failed to get challenge: %v", err)
+	}
+	if stored.ExpiredAt == nil {
+		t.Error("expected the challenge to be marked expired")
+	}
+}
+
+func TestHandleCallbackMetadataSizeLimit(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	service.config.MaxSolverMetadataBytes = 64
+
+	if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_real"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	oversized := json.RawMessage(`{"notes":"` + strings.Repeat("x", 100) + `"}`)
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequestWithMetadata(t, challenge.ID, "solver_job_real", "req_big", oversized))
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status 413, got %d", rr.Code)
+	}
+
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp.Error.Code != "METADATA_TOO_LARGE" {
+		t.Errorf("expected error code METADATA_TOO_LARGE, got %q", errResp.Error.Code)
+	}
+	if result, err := service.db.GetResult(context.Background(), challenge.ID, "req_big"); err == nil && result != nil {
+		t.Error("expected oversized callback not to be stored")
+	}
+
+	small := json.RawMessage(`{"compute_time_ms":12}`)
+	rr = httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequestWithMetadata(t, challenge.ID, "solver_job_real", "req_small", small))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	result, err := service.db.GetResult(context.Background(), challenge.ID, "req_small")
+	if err != nil || result == nil {
+		t.Fatalf("expected within-limit callback to be stored (err %v)", err)
+	}
+	if string(result.SolverMetadata) != string(small) {
+		t.Errorf("expected stored metadata %s, got %s", small, result.SolverMetadata)
+	}
+	if result.ComputeTimeMs != 12 {
+		t.Errorf("expected compute time 12, got %d", result.ComputeTimeMs)
+	}
+}
+
+func TestHandleCallbackSolverMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    json.RawMessage
+		wantStatus  int
+		wantCode    string
+		wantCompute int
+		wantMeta    *models.SolverMetadata
+	}{
+		{
+			name:        "valid metadata",
+			metadata:    json.RawMessage(`{"compute_time_ms":250,"algorithm":"beam","confidence":0.92,"attempt_count":3,"resource":{"gpu":"a100"}}`),
+			wantStatus:  http.StatusOK,
+			wantCompute: 250,
+			wantMeta:    &models.SolverMetadata{ComputeTimeMs: 250, Algorithm: "beam", Confidence: 0.92, AttemptCount: 3},
+		},
+		{name: "missing metadata", wantStatus: http.StatusOK},
+		{name: "confidence above range", metadata: json.RawMessage(`{"confidence":1.5}`), wantStatus: http.StatusBadRequest, wantCode: "INVALID_METADATA"},
+		{name: "negative confidence", metadata: json.RawMessage(`{"confidence":-0.1}`), wantStatus: http.StatusBadRequest, wantCode: "INVALID_METADATA"},
+		{name: "negative compute time", metadata: json.RawMessage(`{"compute_time_ms":-5}`), wantStatus: http.StatusBadRequest, wantCode: "INVALID_METADATA"},
+		{name: "negative attempt count", metadata: json.RawMessage(`{"attempt_count":-1}`), wantStatus: http.StatusBadRequest, wantCode: "INVALID_METADATA"},
+		{name: "wrong type", metadata: json.RawMessage(`{"confidence":"high"}`), wantStatus: http.StatusBadRequest, wantCode: "INVALID_METADATA"},
+		{name: "not an object", metadata: json.RawMessage(`[1,2]`), wantStatus: http.StatusBadRequest, wantCode: "INVALID_METADATA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, challenge := newTestServiceWithDB(t)
+			ctx := context.Background()
+			if err := service.db.SaveDispatchedJob(ctx, challenge.ID, "solver_job_meta"); err != nil {
+				t.Fatalf("failed to save dispatched job: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			service.HandleCallback(rr, newCallbackRequestWithMetadata(t, challenge.ID, "solver_job_meta", "req_meta", tt.metadata))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+
+			if tt.wantCode != "" {
+				var errResp models.ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if errResp.Error.Code != tt.wantCode {
+					t.Errorf("expected error code %s, got %q", tt.wantCode, errResp.Error.Code)
+				}
+				if result, err := service.db.GetResult(ctx, challenge.ID, "req_meta"); err == nil && result != nil {
+					t.Error("expected the rejected callback not to be stored")
+				}
+				return
+			}
+
+			result, err := service.db.GetResult(ctx, challenge.ID, "req_meta")
+			if err != nil || result == nil {
+				t.Fatalf("expected the result to be stored (err %v)", err)
+			}
+			if result.ComputeTimeMs != tt.wantCompute {
+				t.Errorf("expected compute time %d, got %d", tt.wantCompute, result.ComputeTimeMs)
+			}
+
+			metadata, err := result.Metadata()
+			if err != nil {
+				t.Fatalf("failed to read stored metadata: %v", err)
+			}
+			if tt.wantMeta == nil {
+				if metadata != nil {
+					t.Errorf("expected no metadata, got %+v", metadata)
+				}
+				return
+			}
+			if metadata == nil || metadata.Algorithm != tt.wantMeta.Algorithm || metadata.Confidence != tt.wantMeta.Confidence ||
+				metadata.AttemptCount != tt.wantMeta.AttemptCount || metadata.ComputeTimeMs != tt.wantMeta.ComputeTimeMs {
+				t.Errorf("expected metadata %+v, got %+v", tt.wantMeta, metadata)
+			}
+			if metadata.Resource["gpu"] != "a100" {
+				t.Errorf("expected resource usage to be kept, got %v", metadata.Resource)
+			}
+		})
+	}
+}
+
+func TestHandleCallbackCorrectnessMode(t *testing.T) {
+	correct, incorrect := true, false
+
+	tests := []struct {
+		name        string
+		mode        string
+		answer      string
+		wantStatus  int
+		wantCorrect *bool
+	}{
+		{"Off_Incorrect", "off", "wrong", http.StatusOK, nil},
+		{"Default_Incorrect", "", "wrong", http.StatusOK, nil},
+		{"Body_Correct", "body", "secret_answer", http.StatusOK, &correct},
+		{"Body_Incorrect", "body", "wrong", http.StatusOK, &incorrect},
+		{"Status_Correct", "status", "secret_answer", http.StatusOK, &correct},
+		{"Status_Incorrect", "status", "wrong", http.StatusUnprocessableEntity, &incorrect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, challenge := newTestServiceWithDB(t)
+			service.config.CallbackCorrectness = tt.mode
+
+			if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_real"); err != nil {
+				t.Fatalf("failed to save dispatched job: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			service.HandleCallback(rr, newCallbackRequestFrom(t, "req_mode", models.CallbackRequest{
+				APIVersion:  "v2.1",
+				ChallengeID: challenge.ID,
+				SolverJobID: "solver_job_real",
+				Status:      "success",
+				Answer:      tt.answer,
+			}))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+
+			var resp models.CallbackResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !resp.Received {
+				t.Error("expected received=true")
+			}
+
+			switch {
+			case tt.wantCorrect == nil && resp.Correct != nil:
+				t.Errorf("expected no correctness flag, got %v", *resp.Correct)
+			case tt.wantCorrect != nil && resp.Correct == nil:
+				t.Errorf("expected correct=%v, got no flag", *tt.wantCorrect)
+			case tt.wantCorrect != nil && *resp.Correct != *tt.wantCorrect:
+				t.Errorf("expected correct=%v, got %v", *tt.wantCorrect, *resp.Correct)
+			}
+
+			// The result is stored regardless of the response mode
+			if result, err := service.db.GetResult(context.Background(), challenge.ID, "req_mode"); err != nil || result == nil {
+				t.Errorf("expected result to be stored (err %v)", err)
+			}
+		})
+	}
+}
+
 // Helper function to create a test logger that doesn't output during tests
+// fakePublisher records published events for assertions.
+type fakePublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, event events.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakePublisher) Close() error { return nil }
+
+func (f *fakePublisher) published() []events.Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]events.Event(nil), f.events...)
+}
+
+func TestCreateChallengePublishesEvent(t *testing.T) {
+	service, _ := newTestServiceWithDB(t)
+	publisher := &fakePublisher{}
+	service.SetEventPublisher(publisher)
+
+	challenge := &models.Challenge{
+		ID:             "ch_event",
+		Type:           "math",
+		Problem:        json.RawMessage(`{"type":"math"}`),
+		OutputSpec:     json.RawMessage(`{"content_type":"text/plain"}`),
+		ValidationRule: validator.CreateExactMatchRule("4", true),
+	}
+	if err := service.CreateChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("failed to create challenge: %v", err)
+	}
+
+	published := publisher.published()
+	if len(published) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(published))
+	}
+
+	event := published[0]
+	if event.Type != events.ChallengeCreated || event.ChallengeID != "ch_event" || event.ChallengeType != "math" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.OccurredAt.IsZero() {
+		t.Error("expected event timestamp to be set")
+	}
+
+	// The secret answer must never leave the challenger via the bus
+	payload, _ := json.Marshal(event)
+	if bytes.Contains(payload, []byte(`"4"`)) {
+		t.Errorf("event payload leaks the answer: %s", payload)
+	}
+}
+
+func TestHandleCallbackPublishesResultRecorded(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	publisher := &fakePublisher{}
+	service.SetEventPublisher(publisher)
+
+	if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_evt"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	// Duplicate delivery must not publish a second event
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_evt", "req_evt"))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+	}
+
+	published := publisher.published()
+	if len(published) != 1 {
+		t.Fatalf("expected 1 event, got %d: %+v", len(published), published)
+	}
+
+	event := published[0]
+	if event.Type != events.ResultRecorded || event.ChallengeID != challenge.ID || event.RequestID != "req_evt" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Status != "success" || event.IsCorrect == nil || !*event.IsCorrect {
+		t.Errorf("expected a correct success result, got %+v", event)
+	}
+}
+
+func TestHandleGetLogEntryServesLocalCopyAfterUploadFailure(t *testing.T) {
+	uploads := make(chan struct{}, 1)
+	logService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		select {
+		case uploads <- struct{}{}:
+		default: // Later retries
+		}
+	}))
+	defer logService.Close()
+
+	service, challenge := newTestServiceWithDB(t)
+	service.client = logService.Client()
+	service.config.LogServiceURL = logService.URL
+	service.config.LogServiceAPIKey = "upload-key"
+	service.config.LogsAPIKey = "logs-key"
+
+	if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_log"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_log", "req_log"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	select {
+	case <-uploads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for log upload attempt")
+	}
+
+	logID := challenge.ID + ":req_log"
+	getLogEntry := func(id, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/logs/"+id, nil)
+		req.Header.Set("X-API-Key", apiKey)
+		rr := httptest.NewRecorder()
+		service.HandleGetLogEntry(rr, mux.SetURLVars(req, map[string]string{"id": id}))
+		return rr
+	}
+
+	rr = getLogEntry(logID, "logs-key")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var entry models.LogEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry: %v", err)
+	}
+	if entry.ID != logID {
+		t.Errorf("expected log id %q, got %q", logID, entry.ID)
+	}
+
+	var result models.Result
+	if err := json.Unmarshal([]byte(entry.Log), &result); err != nil {
+		t.Fatalf("failed to decode logged result: %v", err)
+	}
+	if result.RequestID != "req_log" || !result.IsCorrect {
+		t.Errorf("unexpected logged result: %+v", result)
+	}
+
+	if rr := getLogEntry(logID, "wrong-key"); rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 for wrong key, got %d", rr.Code)
+	}
+	if rr := getLogEntry("ch_missing:req", "logs-key"); rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing entry, got %d", rr.Code)
+	}
+}
+
+func TestUploadLogWithRetryDeliversAfterTransientFailures(t *testing.T) {
+	var hits atomic.Int32
+	delivered := make(chan models.LogEntry, 1)
+	logService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var entry models.LogEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			t.Errorf("failed to decode uploaded entry: %v", err)
+		}
+		delivered <- entry
+	}))
+	defer logService.Close()
+
+	service, _ := newTestServiceWithDB(t)
+	service.client = logService.Client()
+	service.config.LogServiceURL = logService.URL
+	service.config.LogServiceAPIKey = "upload-key"
+	service.config.LogUploadMaxAttempts = 4
+	service.config.LogUploadBaseDelayMs = 1
+
+	ctx := context.Background()
+	service.uploadLogWithRetry(ctx, models.LogEntry{ID: "ch_1:req_1", Log: `{"answer":"a"}`}, createTestLogger())
+
+	select {
+	case entry := <-delivered:
+		if entry.ID != "ch_1:req_1" || entry.Log != `{"answer":"a"}` {
+			t.Errorf("unexpected delivered entry: %+v", entry)
+		}
+	default:
+		t.Fatal("expected the entry to be delivered")
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("expected 3 upload attempts, got %d", got)
+	}
+
+	queued, err := service.db.ListLogUploads(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list queued uploads: %v", err)
+	}
+	if len(queued) != 0 {
+		t.Errorf("expected nothing queued after delivery, got %+v", queued)
+	}
+}
+
+func TestUploadLogWithRetryQueuesAndFlushes(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	logService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer logService.Close()
+
+	service, _ := newTestServiceWithDB(t)
+	service.client = logService.Client()
+	service.config.LogServiceURL = logService.URL
+	service.config.LogServiceAPIKey = "upload-key"
+	service.config.LogUploadMaxAttempts = 2
+	service.config.LogUploadBaseDelayMs = 1
+
+	ctx := context.Background()
+	service.uploadLogWithRetry(ctx, models.LogEntry{ID: "ch_1:req_1", Log: `{}`}, createTestLogger())
+	if got := hits.Load(); got != 2 {
+		t.Errorf("expected 2 upload attempts, got %d", got)
+	}
+
+	queued, err := service.db.ListLogUploads(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list queued uploads: %v", err)
+	}
+	if len(queued) != 1 || queued[0].Entry.ID != "ch_1:req_1" || queued[0].LastError == "" {
+		t.Fatalf("expected the entry to be queued with its error, got %+v", queued)
+	}
+
+	// Still failing: the flush records another attempt and keeps the entry
+	service.flushLogUploads(ctx, createTestLogger())
+	queued, err = service.db.ListLogUploads(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list queued uploads: %v", err)
+	}
+	if len(queued) != 1 || queued[0].Attempts != 1 {
+		t.Fatalf("expected the entry to stay queued with 1 failed flush, got %+v", queued)
+	}
+
+	healthy.Store(true)
+	service.flushLogUploads(ctx, createTestLogger())
+	queued, err = service.db.ListLogUploads(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list queued uploads: %v", err)
+	}
+	if len(queued) != 0 {
+		t.Errorf("expected the queue to be empty after a successful flush, got %+v", queued)
+	}
+}
+
+func TestHandleCallbackIncrementsMetrics(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	if err := service.db.SaveDispatchedJob(context.Background(), challenge.ID, "solver_job_metrics"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	const series = `aibattle_challenger_callbacks_received_total{outcome="correct"}`
+	before := scrapeMetric(t, series)
+
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_metrics", "req_metrics"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if after := scrapeMetric(t, series); after != before+1 {
+		t.Errorf("expected %s to increase from %v to %v, got %v", series, before, before+1, after)
+	}
+}
+
+// slowSuiClient blocks every object lookup until released, simulating a slow chain.
+type slowSuiClient struct {
+	*sui.MockSuiClient
+	called  chan struct{}
+	release chan struct{}
+}
+
+func (c *slowSuiClient) GetObject(ctx context.Context, req *suiclient.GetObjectRequest) (*suiclient.SuiObjectResponse, error) {
+	select {
+	case c.called <- struct{}{}:
+	default:
+	}
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return c.MockSuiClient.GetObject(ctx, req)
+}
+
+func TestHandleCallbackQueuesCommitmentWithSlowChain(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	service.config.SUI.RegistryID = "0x1234567890abcdef1234567890abcdef12345678901234567890abcdef123456"
+	service.commitmentWake = make(chan struct{}, 1)
+
+	client := &slowSuiClient{
+		MockSuiClient: &sui.MockSuiClient{},
+		called:        make(chan struct{}, 1),
+		release:       make(chan struct{}),
+	}
+	signer := suisigner.NewSigner(make([]byte, 32), suicrypto.KeySchemeFlagEd25519)
+	txBuilder, err := sui.NewTransactionBuilderWithClient(client, "0x1234567890abcdef1234567890abcdef12345678", signer, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to create transaction builder: %v", err)
+	}
+	service.suiTxBuilder = txBuilder
+
+	ctx := context.Background()
+	if err := service.db.SaveDispatchedJob(ctx, challenge.ID, "solver_job_slow"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	workerDone := make(chan struct{})
+	go func() {
+		service.RunCommitmentWorker(workerCtx)
+		close(workerDone)
+	}()
+	t.Cleanup(func() {
+		stopWorker()
+		<-workerDone
+	})
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_slow", "req_slow"))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected callback to return promptly, took %v", elapsed)
+	}
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// The job is persisted before the upload finishes, so it survives a restart
+	jobs, err := service.db.ListCommitmentJobs(ctx, MaxCommitmentAttempts)
+	if err != nil {
+		t.Fatalf("failed to list commitment jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ChallengeID != challenge.ID || jobs[0].RequestID != "req_slow" {
+		t.Fatalf("expected a queued job for req_slow, got %+v", jobs)
+	}
+
+	// The worker picks the job up while the callback has already been answered
+	select {
+	case <-client.called:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the worker to start the upload")
+	}
+
+	// The mock chain has no registry object, so the attempt fails and the job stays queued for retry
+	close(client.release)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		jobs, err = service.db.ListCommitmentJobs(ctx, MaxCommitmentAttempts)
+		if err != nil {
+			t.Fatalf("failed to list commitment jobs: %v", err)
+		}
+		if len(jobs) == 1 && jobs[0].Attempts == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the failed upload to be recorded, got %+v", jobs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if jobs[0].LastError == "" {
+		t.Error("expected the upload error to be recorded")
+	}
+}
+
+// computeTimeScorer ranks correct results by speed, so resubmissions can improve their score.
+type computeTimeScorer struct{}
+
+func (computeTimeScorer) Score(result *models.Result) uint64 {
+	if !result.IsCorrect {
+		return 0
+	}
+	return uint64(1000 - result.ComputeTimeMs)
+}
+
+func TestBestAnswerModeSettlesBestSubmission(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	service.config.AnswerSubmissionMode = "best"
+	service.config.SubmissionWindowSecs = 60
+	service.SetScorer(computeTimeScorer{})
+	publisher := &fakePublisher{}
+	service.SetEventPublisher(publisher)
+	ctx := context.Background()
+
+	if err := service.db.SaveDispatchedJob(ctx, challenge.ID, "solver_job_iter"); err != nil {
+		t.Fatalf("failed to save dispatched job: %v", err)
+	}
+
+	submit := func(requestID, answer string, computeTimeMs int) {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		service.HandleCallback(rr, newCallbackRequestFrom(t, requestID, models.CallbackRequest{
+			APIVersion:  "v2.1",
+			ChallengeID: challenge.ID,
+			SolverJobID: "solver_job_iter",
+			Status:      "success",
+			Answer:      answer,
+			Metadata:    json.RawMessage(`{"compute_time_ms":` + strconv.Itoa(computeTimeMs) + `}`),
+		}))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", requestID, rr.Code, rr.Body.String())
+		}
+	}
+
+	submit("req_first", "secret_answer", 500)
+	submit("req_wrong", "wrong", 10)
+	submit("req_better", "secret_answer", 100)
+	submit("req_worse", "secret_answer", 300)
+	submit("req_first", "secret_answer", 500) // Replayed callback
+
+	window, err := service.db.GetSubmissionWindow(ctx, challenge.ID)
+	if err != nil || window == nil {
+		t.Fatalf("expected a submission window (err %v)", err)
+	}
+	if window.BestRequestID != "req_better" || window.BestScore != 900 {
+		t.Errorf("expected req_better with score 900 as best, got %s with %d", window.BestRequestID, window.BestScore)
+	}
+
+	// Nothing is settled while the window is open
+	if err := service.SettleClosedWindows(ctx, time.Now()); err != nil {
+		t.Fatalf("failed to settle windows: %v", err)
+	}
+	if settled := eventsOfType(publisher.published(), events.WindowSettled); len(settled) != 0 {
+		t.Fatalf("expected no settlement before the window closes, got %+v", settled)
+	}
+
+	// Settling at close picks the later, better answer; a second pass is a no-op
+	for i := 0; i < 2; i++ {
+		if err := service.SettleClosedWindows(ctx, window.ClosesAt); err != nil {
+			t.Fatalf("failed to settle windows: %v", err)
+		}
+	}
+	settled := eventsOfType(publisher.published(), events.WindowSettled)
+	if len(settled) != 1 {
+		t.Fatalf("expected 1 settlement, got %d: %+v", len(settled), settled)
+	}
+	if settled[0].ChallengeID != challenge.ID || settled[0].RequestID != "req_better" {
+		t.Errorf("expected req_better to win, got %+v", settled[0])
+	}
+
+	// Answers after settlement are rejected
+	rr := httptest.NewRecorder()
+	service.HandleCallback(rr, newCallbackRequest(t, challenge.ID, "solver_job_iter", "req_late"))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp.Error.Code != "WINDOW_CLOSED" {
+		t.Errorf("expected error code WINDOW_CLOSED, got %q", errResp.Error.Code)
+	}
+}
+
+// eventsOfType filters published events by type.
+func eventsOfType(published []events.Event, eventType string) []events.Event {
+	var matched []events.Event
+	for _, event := range published {
+		if event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// scrapeMetric reads one series from the /metrics handler, returning 0 when it is not exported yet.
+func scrapeMetric(t *testing.T, series string) float64 {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected metrics status 200, got %d", rr.Code)
+	}
+
+	for _, line := range strings.Split(rr.Body.String(), "\n") {
+		if value, ok := strings.CutPrefix(line, series+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("failed to parse %s value %q: %v", series, value, err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
 func createTestLogger() zerolog.Logger {
 	return zerolog.New(zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 		w.Out = os.Stderr
 		w.TimeFormat = "15:04:05"
 	})).With().Timestamp().Logger()
 }
+
+func TestCommitmentEventsDriveLogEntries(t *testing.T) {
+	service, challenge := newTestServiceWithDB(t)
+	service.config.SUI.RegistryID = "0x00000000000000000000000000000000000000000000000000000000000000a1"
+	service.config.SUI.EventConfirmTimeoutSecs = 60
+	publisher := &fakePublisher{}
+	service.SetEventPublisher(publisher)
+
+	signer := suisigner.NewSigner(make([]byte, 32), suicrypto.KeySchemeFlagEd25519)
+	txBuilder, err := sui.NewTransactionBuilderWithClient(&sui.MockSuiClient{}, "0x1234567890abcdef1234567890abcdef12345678", signer, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to create transaction builder: %v", err)
+	}
+	service.suiTxBuilder = txBuilder
+
+	commitmentEvent := func(objID string, challenger *suigo.Address) sui.CommitmentEvent {
+		return sui.CommitmentEvent{
+			CommitmentPayload: sui.CommitmentPayload{
+				Id:             suigo.MustObjectIdFromHex(objID),
+				RegistryId:     suigo.MustObjectIdFromHex(service.config.SUI.RegistryID),
+				ChallengerAddr: challenger,
+				SolverAddr:     suigo.MustAddressFromHex("0xb0b"),
+			},
+			TxDigest: "22222222222222222222222222222222",
+		}
+	}
+	streamed := func(event sui.CommitmentEvent) suiclient.Event {
+		b, err := bcs.Marshal(event.CommitmentPayload)
+		if err != nil {
+			t.Fatalf("failed to marshal event: %v", err)
+		}
+		return suiclient.Event{Id: suiclient.EventId{TxDigest: *suigo.MustNewDigest(event.TxDigest)}, Bcs: b}
+	}
+
+	// The early event reaches the subscription before its upload returns; the other
+	// challenger's event is ignored
+	early := commitmentEvent("0xc1", signer.Address)
+	foreign := commitmentEvent("0xc9", suigo.MustAddressFromHex("0xdead"))
+	txBuilder.SetEventSubscriber(&sui.MockEventSubscriber{Events: []suiclient.Event{streamed(foreign), streamed(early)}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := service.StartCommitmentConfirmations(ctx); err != nil {
+		t.Fatalf("StartCommitmentConfirmations() unexpected error: %v", err)
+	}
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	hasLogEntry := func(objID string) bool {
+		entry, err := service.db.GetLogEntry(ctx, suigo.MustObjectIdFromHex(objID).String())
+		return err == nil && entry != nil
+	}
+	waitFor("the early event", func() bool {
+		service.confirmations.mu.Lock()
+		defer service.confirmations.mu.Unlock()
+		_, ok := service.confirmations.confirmed[early.Id.String()]
+		return ok
+	})
+	service.confirmations.mu.Lock()
+	if _, ok := service.confirmations.confirmed[foreign.Id.String()]; ok {
+		t.Error("expected the other challenger's event to be ignored")
+	}
+	service.confirmations.mu.Unlock()
+
+	result := &models.Result{ChallengeID: challenge.ID, RequestID: "req_confirm", SolverAddress: "0xb0b", CreatedAt: time.Now()}
+	testLogger := zerolog.Nop()
+
+	// An upload whose event already arrived is confirmed right away
+	service.awaitConfirmation(early.Id.String(), challenge.ID, result, testLogger)
+	if !hasLogEntry("0xc1") {
+		t.Error("expected the log entry of the early-confirmed commitment")
+	}
+
+	// An upload waits for its event before its log entry is stored
+	late := commitmentEvent("0xc2", signer.Address)
+	service.awaitConfirmation(late.Id.String(), challenge.ID, result, testLogger)
+	if hasLogEntry("0xc2") {
+		t.Fatal("expected no log entry before the event arrives")
+	}
+	service.handleCommitmentEvent(late, testLogger)
+	if !hasLogEntry("0xc2") {
+		t.Error("expected the log entry once the event arrives")
+	}
+
+	var confirmed []string
+	for _, event := range publisher.published() {
+		if event.Type == events.CommitmentConfirmed {
+			confirmed = append(confirmed, event.ObjectID)
+		}
+	}
+	if len(confirmed) != 2 || confirmed[0] != early.Id.String() || confirmed[1] != late.Id.String() {
+		t.Errorf("expected commitment.confirmed for both commitments, got %v", confirmed)
+	}
+
+	// Without an event the log entry is stored once the timeout passes
+	service.confirmations.mu.Lock()
+	service.confirmations.timeout = 20 * time.Millisecond
+	service.confirmations.mu.Unlock()
+	service.awaitConfirmation(suigo.MustObjectIdFromHex("0xc3").String(), challenge.ID, result, testLogger)
+	waitFor("the unconfirmed log entry", func() bool { return hasLogEntry("0xc3") })
+}
+
+func TestSuiFailureMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"no coins", fmt.Errorf("upload: %w", sui.ErrNoCoins), "fund it"},
+		{"insufficient gas", fmt.Errorf("upload: %w", sui.ErrInsufficientGas), "fund it"},
+		{"invalid address", fmt.Errorf("upload: %w", sui.ErrInvalidAddress), "check SUI_REGISTRY_ID"},
+		{"transaction failed", fmt.Errorf("upload: %w", sui.ErrTxFailed), "failed on chain"},
+		{"other", errors.New("connection refused"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := suiFailureMessage("Failed to upload to Sui", tt.err)
+			if tt.want == "" {
+				if got != "Failed to upload to Sui" {
+					t.Errorf("expected the message unchanged, got %q", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, "Failed to upload to Sui: ") || !strings.Contains(got, tt.want) {
+				t.Errorf("expected a message mentioning %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestImportChallenges(t *testing.T) {
+	service, _ := newTestServiceWithDB(t)
+	publisher := &fakePublisher{}
+	service.SetEventPublisher(publisher)
+
+	file, err := os.Open(filepath.Join("testdata", "import_challenges.ndjson"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	report, err := service.ImportChallenges(context.Background(), file)
+	if err != nil {
+		t.Fatalf("ImportChallenges() unexpected error: %v", err)
+	}
+
+	want := []ImportRecord{
+		{Record: 1, ChallengeID: "imp_text", Status: ImportCreated},
+		{Record: 2, ChallengeID: "imp_math", Status: ImportCreated},
+		{Record: 4, ChallengeID: "imp_text", Status: ImportDuplicate},
+		{Record: 5, ChallengeID: "ch_redact", Status: ImportDuplicate},
+		{Record: 6, ChallengeID: "imp_bad_regex", Status: ImportInvalid},
+		{Record: 7, ChallengeID: "imp_unknown_rule", Status: ImportInvalid},
+		{Record: 8, Status: ImportInvalid},
+		{Record: 9, Status: ImportInvalid},
+		{Record: 10, ChallengeID: "imp_schema", Status: ImportCreated},
+	}
+	if len(report.Records) != len(want) {
+		t.Fatalf("expected %d records, got %+v", len(want), report.Records)
+	}
+	for i, w := range want {
+		got := report.Records[i]
+		if got.Record != w.Record || got.ChallengeID != w.ChallengeID || got.Status != w.Status {
+			t.Errorf("record %d: expected %+v, got %+v", i, w, got)
+		}
+		if (got.Status == ImportInvalid) != (got.Error != "") {
+			t.Errorf("record %d: expected an error only for invalid records, got %q", got.Record, got.Error)
+		}
+	}
+	if report.Created != 3 || report.Duplicates != 2 || report.Invalid != 4 || report.Failed != 0 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+
+	// The first definition of a duplicated ID wins
+	stored, err := service.db.GetChallenge(context.Background(), "imp_text")
+	if err != nil {
+		t.Fatalf("failed to load imported challenge: %v", err)
+	}
+	if !strings.Contains(string(stored.Problem), "Say hello\"") {
+		t.Errorf("expected the first imp_text definition to be stored, got %s", stored.Problem)
+	}
+	if got := len(publisher.published()); got != 3 {
+		t.Errorf("expected a created event per imported challenge, got %d", got)
+	}
+}
+
+func TestImportChallenges_JSONArray(t *testing.T) {
+	service, _ := newTestServiceWithDB(t)
+
+	input := `[
+		{"id":"arr_1","type":"text","problem":{"data":"a"},"output_spec":{},"validation_rule":{"type":"ExactMatch","answer":"a"}},
+		{"id":"arr_2","type":"text","problem":{"data":"b"},"output_spec":{},"validation_rule":{"type":"FuzzyMatch","answer":"b"}}
+	]`
+	report, err := service.ImportChallenges(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ImportChallenges() unexpected error: %v", err)
+	}
+	if report.Created != 1 || report.Invalid != 1 || report.Records[1].Record != 2 {
+		t.Errorf("expected arr_1 created and arr_2 rejected for missing params, got %+v", report)
+	}
+
+	if _, err := service.ImportChallenges(context.Background(), strings.NewReader(`[{"id":`)); err == nil {
+		t.Error("expected an error for a malformed JSON array")
+	}
+}
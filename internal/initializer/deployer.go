@@ -2,10 +2,19 @@ package initializer
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"math/big"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/fardream/go-bcs/bcs"
 	"github.com/pattonkan/sui-go/sui"
 	"github.com/pattonkan/sui-go/sui/suiptb"
@@ -22,11 +31,16 @@ type Deployer interface {
 	Deploy(ctx context.Context, cfg *config.Config) (*Result, error)
 }
 
+// publishGasBudget is the gas budget for publishing the main package, higher than the
+// default for complex contracts
+const publishGasBudget = 20 * suiclient.DefaultGasBudget
+
 // SuiDeployer implements Sui Move contract deployment
 type SuiDeployer struct {
 	client       *suiclient.ClientImpl
 	signer       *suisigner.Signer
 	contractPath string
+	progress     *DeploymentProgress // Steps completed by an earlier run, set via Resume
 }
 
 // NewSuiDeployer creates a new Sui contract deployer
@@ -38,7 +52,13 @@ func NewSuiDeployer(client *suiclient.ClientImpl, signer *suisigner.Signer, cont
 	}
 }
 
-// Deploy deploys Sui Move contracts and returns deployment results
+// Resume makes the next Deploy skip the steps recorded in progress
+func (d *SuiDeployer) Resume(progress *DeploymentProgress) {
+	d.progress = progress
+}
+
+// Deploy deploys Sui Move contracts and returns deployment results.
+// If a step fails after objects were created, the error is a *PartialDeploymentError.
 func (d *SuiDeployer) Deploy(ctx context.Context, cfg *config.Config) (*Result, error) {
 	log.Info().
 		Str("contract_path", d.contractPath).
@@ -46,41 +66,106 @@ func (d *SuiDeployer) Deploy(ctx context.Context, cfg *config.Config) (*Result,
 		Msg("Starting Sui Move contract deployment")
 
 	// Build and deploy the main package
-	packageId, registryId, posPackageId, negPackageId, vaultId, vaultAdminCapId, txDigest, err := d.deployConditionalsFramework(ctx, cfg)
+	progress, err := d.deployConditionalsFramework(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deploy conditionals framework: %w", err)
 	}
 
 	result := &Result{
-		PackageId:     packageId,
-		RegistryId:    registryId,
-		TransactionId: txDigest,
+		PackageId:     progress.PackageId,
+		RegistryId:    progress.RegistryId,
+		TransactionId: progress.TxDigest,
 		Network:       cfg.SUI.ChainID,
 		Metadata: map[string]interface{}{
-			"pos_package_id":     posPackageId.String(),
-			"neg_package_id":     negPackageId.String(),
-			"vault_id":           vaultId.String(),
-			"vault_admin_cap_id": vaultAdminCapId.String(),
+			"pos_package_id":     progress.PosPackageId.String(),
+			"neg_package_id":     progress.NegPackageId.String(),
+			"vault_id":           progress.VaultId.String(),
+			"vault_admin_cap_id": progress.VaultAdminCapId.String(),
 		},
 	}
 
 	log.Info().
-		Str("package_id", packageId.String()).
-		Str("registry_id", registryId.String()).
-		Str("vault_id", vaultId.String()).
-		Str("vault_admin_cap_id", vaultAdminCapId.String()).
-		Str("tx_digest", txDigest).
+		Str("package_id", progress.PackageId.String()).
+		Str("registry_id", progress.RegistryId.String()).
+		Str("vault_id", progress.VaultId.String()).
+		Str("vault_admin_cap_id", progress.VaultAdminCapId.String()).
+		Str("tx_digest", progress.TxDigest).
 		Msg("Successfully deployed Sui Move contracts")
 
 	return result, nil
 }
 
-// deployConditionalsFramework builds and publishes the conditional tokens framework
-func (d *SuiDeployer) deployConditionalsFramework(ctx context.Context, cfg *config.Config) (*sui.PackageId, *sui.ObjectId, *sui.PackageId, *sui.PackageId, *sui.ObjectId, *sui.ObjectId, string, error) {
+// deployConditionalsFramework publishes the main package and the pos/neg tokens, then creates
+// the registry and vault. Steps already present in d.progress are skipped. Once any object
+// exists on-chain, failures are returned as a *PartialDeploymentError carrying the progress.
+func (d *SuiDeployer) deployConditionalsFramework(ctx context.Context) (*DeploymentProgress, error) {
+	progress := &DeploymentProgress{}
+	if d.progress != nil {
+		*progress = *d.progress
+		log.Info().Interface("completed", progress.metadata()).Msg("Resuming partial deployment")
+	}
+
+	// partial wraps err so the caller can record what was created before the failure
+	partial := func(err error) error {
+		if progress.PackageId == nil {
+			return err
+		}
+		return &PartialDeploymentError{Progress: progress, Err: err}
+	}
+
+	if progress.PackageId == nil {
+		packageId, txDigest, err := d.publishMainPackage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		progress.PackageId = packageId
+		progress.TxDigest = txDigest
+	}
+
+	if progress.PosPackageId == nil || progress.PosTreasuryCap == nil {
+		packageIdPos, treasuryCapPos, err := buildDeployToken(ctx, d.client, d.signer, "pos")
+		if err != nil {
+			return nil, partial(fmt.Errorf("failed to publish token Pos: %w", err))
+		}
+		progress.PosPackageId = packageIdPos
+		progress.PosTreasuryCap = treasuryCapPos
+	}
+	if progress.NegPackageId == nil || progress.NegTreasuryCap == nil {
+		packageIdNeg, treasuryCapNeg, err := buildDeployToken(ctx, d.client, d.signer, "neg")
+		if err != nil {
+			return nil, partial(fmt.Errorf("failed to publish token Neg: %w", err))
+		}
+		progress.NegPackageId = packageIdNeg
+		progress.NegTreasuryCap = treasuryCapNeg
+	}
+
+	// The registry takes ownership of both treasury caps
+	if progress.RegistryId == nil {
+		registryId, _, _, err := d.createRegistry(ctx, progress.PackageId, progress.PosPackageId, progress.NegPackageId, progress.PosTreasuryCap, progress.NegTreasuryCap)
+		if err != nil {
+			return nil, partial(fmt.Errorf("failed to create registry object: %w", err))
+		}
+		progress.RegistryId = registryId
+	}
+
+	if progress.VaultId == nil || progress.VaultAdminCapId == nil {
+		vaultId, vaultAdminCapId, err := d.createVault(ctx, progress.PackageId)
+		if err != nil {
+			return nil, partial(fmt.Errorf("failed to create vault object: %w", err))
+		}
+		progress.VaultId = vaultId
+		progress.VaultAdminCapId = vaultAdminCapId
+	}
+
+	return progress, nil
+}
+
+// publishMainPackage builds and publishes the Move package at the contract path
+func (d *SuiDeployer) publishMainPackage(ctx context.Context) (*sui.PackageId, string, error) {
 	// Find the package root (directory containing Move.toml)
-	packageRoot, err := d.findPackageRoot()
+	packageRoot, err := findPackageRoot(d.contractPath)
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to find package root: %w", err)
+		return nil, "", fmt.Errorf("failed to find package root: %w", err)
 	}
 
 	log.Info().Str("package_root", packageRoot).Msg("Building Sui Move package")
@@ -88,7 +173,7 @@ func (d *SuiDeployer) deployConditionalsFramework(ctx context.Context, cfg *conf
 	// Build the Move package
 	modules, err := utils.MoveBuild(packageRoot)
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to build Move package: %w", err)
+		return nil, "", fmt.Errorf("failed to build Move package: %w", err)
 	}
 
 	log.Info().
@@ -101,10 +186,10 @@ func (d *SuiDeployer) deployConditionalsFramework(ctx context.Context, cfg *conf
 		Sender:          d.signer.Address,
 		CompiledModules: modules.Modules,
 		Dependencies:    modules.Dependencies,
-		GasBudget:       sui.NewBigInt(20 * suiclient.DefaultGasBudget), // Higher gas budget for complex contracts
+		GasBudget:       sui.NewBigInt(publishGasBudget),
 	})
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to publish package: %w", err)
+		return nil, "", fmt.Errorf("failed to publish package: %w", err)
 	}
 
 	// Sign and execute the transaction
@@ -119,52 +204,26 @@ func (d *SuiDeployer) deployConditionalsFramework(ctx context.Context, cfg *conf
 		},
 	)
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to execute publish transaction: %w", err)
+		return nil, "", fmt.Errorf("failed to execute publish transaction: %w", err)
 	}
 
 	// Check transaction success
 	if !txnResponse.Effects.Data.IsSuccess() {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("publish transaction failed")
+		return nil, "", fmt.Errorf("publish transaction failed")
 	}
 
 	// Extract package ID
 	packageId, err := txnResponse.GetPublishedPackageId()
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to extract package ID: %w", err)
-	}
-
-	packageIdPos, treasuryCapPos, err := buildDeployToken(ctx, d.client, d.signer, "pos")
-	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to publish token Pos: %w", err)
-	}
-	packageIdNeg, treasuryCapNeg, err := buildDeployToken(ctx, d.client, d.signer, "neg")
-	if err != nil {
-		return nil, nil, nil, nil, nil, nil, "", fmt.Errorf("failed to publish token Neg: %w", err)
-	}
-
-	// Create a registry object after successful package deployment
-	// For now using a placeholder - in a real implementation, you'd call a Move function
-	// to create the registry object using the deployed package
-	registryId, _, _, err := d.createRegistry(ctx, packageId, packageIdPos, packageIdNeg, treasuryCapPos, treasuryCapNeg)
-	if err != nil {
-		log.Warn().Err(err).Msg("Failed to create registry object, using placeholder")
-		registryId = &sui.ObjectId{} // Use placeholder if registry creation fails
+		return nil, "", fmt.Errorf("failed to extract package ID: %w", err)
 	}
-	vaultId, vaultAdminCapId, err := d.createVault(ctx, packageId)
-	if err != nil {
-		log.Warn().Err(err).Msg("Failed to create vault object, using placeholder")
-		vaultId = &sui.ObjectId{}
-		vaultAdminCapId = &sui.ObjectId{}
-	}
-
-	txDigest := txnResponse.Digest
 
 	log.Info().
 		Str("package_id", packageId.String()).
-		Str("tx_digest", string(txDigest)).
+		Str("tx_digest", string(txnResponse.Digest)).
 		Msg("Package published successfully")
 
-	return packageId, registryId, packageIdPos, packageIdNeg, vaultId, vaultAdminCapId, string(txDigest), nil
+	return packageId, string(txnResponse.Digest), nil
 }
 
 func buildDeployToken(ctx context.Context, client *suiclient.ClientImpl, signer *suisigner.Signer, tokenName string) (*sui.PackageId, *sui.ObjectId, error) {
@@ -215,7 +274,6 @@ func buildDeployToken(ctx context.Context, client *suiclient.ClientImpl, signer
 }
 
 // createRegistry creates a registry object using the deployed package
-// This is a placeholder implementation - in a real scenario, you'd call a Move function
 func (d *SuiDeployer) createRegistry(
 	ctx context.Context,
 	packageId, posPackageId, negPackageId *sui.PackageId,
@@ -404,13 +462,13 @@ func (d *SuiDeployer) createVault(
 	return vaultId, vaultAdminCapId, nil
 }
 
-// findPackageRoot searches for the directory containing Move.toml
-func (d *SuiDeployer) findPackageRoot() (string, error) {
+// findPackageRoot searches contractPath and its parents for the directory containing Move.toml
+func findPackageRoot(contractPath string) (string, error) {
 	// Start from the contract path and search upwards for Move.toml
-	currentDir := d.contractPath
+	currentDir := contractPath
 
 	// Try the contract path directly first
-	if d.hasMoveTOML(currentDir) {
+	if hasMoveTOML(currentDir) {
 		return currentDir, nil
 	}
 
@@ -421,37 +479,46 @@ func (d *SuiDeployer) findPackageRoot() (string, error) {
 			break // Reached filesystem root
 		}
 
-		if d.hasMoveTOML(parent) {
+		if hasMoveTOML(parent) {
 			return parent, nil
 		}
 
 		currentDir = parent
 	}
 
-	return "", fmt.Errorf("Move.toml not found in %s or its parent directories", d.contractPath)
+	return "", fmt.Errorf("Move.toml not found in %s or its parent directories", contractPath)
 }
 
 // hasMoveTOML checks if a directory contains Move.toml
-func (d *SuiDeployer) hasMoveTOML(dir string) bool {
+func hasMoveTOML(dir string) bool {
 	moveTomlPath := filepath.Join(dir, "Move.toml")
 	return fileExists(moveTomlPath)
 }
 
 // fileExists checks if a file exists
 func fileExists(filename string) bool {
-	_, err := filepath.Abs(filename)
+	_, err := os.Stat(filename)
 	return err == nil
 }
 
-// EthereumDeployer would implement Ethereum contract deployment for future use
-// Currently not implemented as the project uses Sui Move contracts
+// EthereumBackend is the subset of an Ethereum client the deployer needs.
+// *ethclient.Client satisfies it; tests use a simulated backend.
+type EthereumBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ethereum.ChainIDReader
+}
+
+// EthereumDeployer deploys the compiled challenge registry to an EVM chain
 type EthereumDeployer struct {
 	rpcURL     string
 	privateKey string
 	chainID    int64
+	backend    EthereumBackend
 }
 
-// NewEthereumDeployer creates a new Ethereum contract deployer
+// NewEthereumDeployer creates a new Ethereum contract deployer.
+// A chainID of 0 asks the node for its chain ID.
 func NewEthereumDeployer(rpcURL, privateKey string, chainID int64) *EthereumDeployer {
 	return &EthereumDeployer{
 		rpcURL:     rpcURL,
@@ -460,7 +527,107 @@ func NewEthereumDeployer(rpcURL, privateKey string, chainID int64) *EthereumDepl
 	}
 }
 
-// Deploy implements Ethereum contract deployment (placeholder for future implementation)
+// NewEthereumDeployerWithBackend creates an Ethereum deployer on top of an existing backend
+func NewEthereumDeployerWithBackend(backend EthereumBackend, privateKey string, chainID int64) *EthereumDeployer {
+	return &EthereumDeployer{
+		privateKey: privateKey,
+		chainID:    chainID,
+		backend:    backend,
+	}
+}
+
+// Deploy sends the contract bytecode from ETH_CONTRACT_BYTECODE_PATH in a contract creation
+// transaction and waits for its receipt
 func (d *EthereumDeployer) Deploy(ctx context.Context, cfg *config.Config) (*Result, error) {
-	return nil, fmt.Errorf("Ethereum deployment not yet implemented - project uses Sui Move contracts")
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(d.privateKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Ethereum private key: %w", err)
+	}
+
+	bytecode, err := readBytecode(cfg.ETH.BytecodePath)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := d.backend
+	if backend == nil {
+		client, err := ethclient.DialContext(ctx, d.rpcURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", d.rpcURL, err)
+		}
+		defer client.Close()
+		backend = client
+	}
+
+	chainID := big.NewInt(d.chainID)
+	if d.chainID == 0 {
+		if chainID, err = backend.ChainID(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get chain ID: %w", err)
+		}
+	}
+
+	auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactor: %w", err)
+	}
+	auth.Context = ctx
+
+	log.Info().
+		Str("deployer", auth.From.Hex()).
+		Str("chain_id", chainID.String()).
+		Int("bytecode_size", len(bytecode)).
+		Msg("Deploying contract to Ethereum")
+
+	address, tx, _, err := bind.DeployContract(auth, abi.ABI{}, bytecode, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send deployment transaction: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for deployment receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("deployment transaction %s reverted", tx.Hash().Hex())
+	}
+	if receipt.ContractAddress != address {
+		return nil, fmt.Errorf("receipt contract address %s does not match expected %s", receipt.ContractAddress.Hex(), address.Hex())
+	}
+
+	log.Info().
+		Str("address", receipt.ContractAddress.Hex()).
+		Str("tx_hash", tx.Hash().Hex()).
+		Uint64("block", receipt.BlockNumber.Uint64()).
+		Msg("Successfully deployed contract to Ethereum")
+
+	return &Result{
+		Address:       receipt.ContractAddress.Hex(),
+		TransactionId: tx.Hash().Hex(),
+		Network:       chainID.String(),
+		Metadata: map[string]interface{}{
+			"block_number": receipt.BlockNumber.Uint64(),
+			"gas_used":     receipt.GasUsed,
+		},
+	}, nil
+}
+
+// readBytecode loads hex-encoded contract bytecode, as written by solc --bin
+func readBytecode(path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("ETH_CONTRACT_BYTECODE_PATH is required")
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract bytecode: %w", err)
+	}
+
+	bytecode, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(raw)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract bytecode in %s: %w", path, err)
+	}
+	if len(bytecode) == 0 {
+		return nil, fmt.Errorf("contract bytecode in %s is empty", path)
+	}
+	return bytecode, nil
 }
@@ -0,0 +1,145 @@
+package initializer
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+
+	"reverse-challenge-system/pkg/config"
+)
+
+// testContractBytecode is init code that deploys the one-byte runtime code 0x00 (STOP):
+// CODECOPY the trailing byte into memory and RETURN it.
+const testContractBytecode = "6001600c60003960016000f300"
+
+func writeBytecode(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "registry.bin")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("failed to write bytecode: %v", err)
+	}
+	return path
+}
+
+// newSimulatedBackend returns a simulated chain that funds a fresh deployer key and
+// mines a block every few milliseconds so deployments get a receipt.
+func newSimulatedBackend(t *testing.T) (*simulated.Backend, string) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	deployer := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		deployer: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				backend.Commit()
+			}
+		}
+	}()
+
+	return backend, hex.EncodeToString(crypto.FromECDSA(key))
+}
+
+func TestEthereumDeployer_DeploysToSimulatedBackend(t *testing.T) {
+	backend, privateKey := newSimulatedBackend(t)
+	client := backend.Client()
+
+	cfg := &config.Config{ETH: config.EthereumConfig{BytecodePath: writeBytecode(t, "0x"+testContractBytecode+"\n")}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Chain ID 0 makes the deployer ask the backend
+	deployer := NewEthereumDeployerWithBackend(client, "0x"+privateKey, 0)
+	result, err := deployer.Deploy(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Deploy() unexpected error: %v", err)
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatalf("ChainID() unexpected error: %v", err)
+	}
+	if result.Network != chainID.String() {
+		t.Errorf("Expected network %s, got %s", chainID, result.Network)
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(result.TransactionId))
+	if err != nil {
+		t.Fatalf("TransactionReceipt() unexpected error: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("Expected successful receipt, got status %d", receipt.Status)
+	}
+	if result.Address != receipt.ContractAddress.Hex() {
+		t.Errorf("Expected address %s from the receipt, got %s", receipt.ContractAddress.Hex(), result.Address)
+	}
+
+	code, err := client.CodeAt(ctx, receipt.ContractAddress, nil)
+	if err != nil {
+		t.Fatalf("CodeAt() unexpected error: %v", err)
+	}
+	if len(code) != 1 || code[0] != 0x00 {
+		t.Errorf("Expected deployed runtime code 0x00, got %x", code)
+	}
+}
+
+func TestEthereumDeployer_Errors(t *testing.T) {
+	backend, privateKey := newSimulatedBackend(t)
+
+	tests := []struct {
+		name       string
+		privateKey string
+		bytecode   string
+		wantErr    string
+	}{
+		{name: "invalid private key", privateKey: "not-a-key", bytecode: testContractBytecode, wantErr: "invalid Ethereum private key"},
+		{name: "missing bytecode path", privateKey: privateKey, wantErr: "ETH_CONTRACT_BYTECODE_PATH is required"},
+		{name: "invalid bytecode", privateKey: privateKey, bytecode: "zz", wantErr: "invalid contract bytecode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			if tt.bytecode != "" {
+				cfg.ETH.BytecodePath = writeBytecode(t, tt.bytecode)
+			}
+
+			_, err := NewEthereumDeployerWithBackend(backend.Client(), tt.privateKey, 0).Deploy(context.Background(), cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Deploy() expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
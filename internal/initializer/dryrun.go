@@ -0,0 +1,105 @@
+package initializer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// PublishDryRunner is the subset of the Sui client a publish dry run needs.
+// *suiclient.ClientImpl satisfies it; tests substitute a mock.
+type PublishDryRunner interface {
+	Publish(ctx context.Context, req *suiclient.PublishRequest) (*suiclient.TransactionBytes, error)
+	DryRunTransaction(ctx context.Context, txDataBytes sui.Base64) (*suiclient.DryRunTransactionBlockResponse, error)
+}
+
+var _ PublishDryRunner = (*suiclient.ClientImpl)(nil)
+
+// MoveBuilder compiles the Move package at packageRoot; utils.MoveBuild shells out to the sui CLI
+type MoveBuilder func(packageRoot string) (*utils.CompiledMoveModules, error)
+
+// DryRunReport summarizes a simulated publish of the main package
+type DryRunReport struct {
+	PackageRoot     string `json:"package_root"`
+	ModuleCount     int    `json:"module_count"`
+	DependencyCount int    `json:"dependency_count"`
+	GasBudget       uint64 `json:"gas_budget"`
+	ComputationCost int64  `json:"computation_cost"`
+	StorageCost     int64  `json:"storage_cost"`
+	StorageRebate   int64  `json:"storage_rebate"`
+	EstimatedGas    int64  `json:"estimated_gas"` // Net fee in MIST: computation + storage - rebate
+}
+
+// DryRun builds the Move package containing contractPath and simulates publishing it from
+// sender, surfacing compilation and gas problems without signing or executing anything.
+// A nil build uses utils.MoveBuild.
+func DryRun(ctx context.Context, client PublishDryRunner, sender *sui.Address, contractPath string, build MoveBuilder) (*DryRunReport, error) {
+	if build == nil {
+		build = utils.MoveBuild
+	}
+
+	packageRoot, err := findPackageRoot(contractPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find package root: %w", err)
+	}
+
+	log.Info().Str("package_root", packageRoot).Msg("Building Sui Move package")
+
+	modules, err := build(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Move package: %w", err)
+	}
+
+	report := &DryRunReport{
+		PackageRoot:     packageRoot,
+		ModuleCount:     len(modules.Modules),
+		DependencyCount: len(modules.Dependencies),
+		GasBudget:       publishGasBudget,
+	}
+
+	log.Info().
+		Int("module_count", report.ModuleCount).
+		Int("dependency_count", report.DependencyCount).
+		Msg("Successfully built Move package")
+
+	// The node builds the unsigned publish transaction; it is only ever dry-run
+	txnBytes, err := client.Publish(ctx, &suiclient.PublishRequest{
+		Sender:          sender,
+		CompiledModules: modules.Modules,
+		Dependencies:    modules.Dependencies,
+		GasBudget:       sui.NewBigInt(publishGasBudget),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build publish transaction: %w", err)
+	}
+
+	dryRun, err := client.DryRunTransaction(ctx, txnBytes.TxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dry-run publish transaction: %w", err)
+	}
+
+	effects := dryRun.Effects.Data
+	if effects.V1 == nil {
+		return nil, fmt.Errorf("dry run returned no transaction effects")
+	}
+	if !effects.IsSuccess() {
+		return nil, fmt.Errorf("publish dry run failed: %s", effects.V1.Status.Error)
+	}
+
+	gas := effects.V1.GasUsed
+	report.ComputationCost = gas.ComputationCost.Int64()
+	report.StorageCost = gas.StorageCost.Int64()
+	report.StorageRebate = gas.StorageRebate.Int64()
+	report.EstimatedGas = effects.GasFee()
+
+	log.Info().
+		Int64("estimated_gas", report.EstimatedGas).
+		Uint64("gas_budget", report.GasBudget).
+		Msg("Publish dry run succeeded")
+
+	return report, nil
+}
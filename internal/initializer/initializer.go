@@ -1,8 +1,8 @@
 package initializer
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,6 +19,7 @@ import (
 	"reverse-challenge-system/pkg/config"
 	"reverse-challenge-system/pkg/db"
 	"reverse-challenge-system/pkg/models"
+	localsui "reverse-challenge-system/pkg/sui"
 )
 
 // Options configures the initializer behavior
@@ -26,19 +27,56 @@ type Options struct {
 	ContractPath   string // Override default contract path
 	SkipIfExists   bool   // Skip deployment if contract already exists
 	FundFromFaucet bool   // Request funds from faucet before deployment
+
+	Faucet   localsui.Faucet // Faucet used for funding; defaults to the network's HTTP faucet
+	Deployer Deployer        // Deployer to use; defaults to a SuiDeployer for ContractPath, or an EthereumDeployer when DEPLOY_TARGET=ethereum
 }
 
 // Result contains deployment results
 type Result struct {
 	PackageId     *sui.PackageId
 	RegistryId    *sui.ObjectId
+	Address       string // Contract address for EVM deployments
 	TransactionId string
 	Network       string
 	Metadata      map[string]interface{}
 }
 
-// Run executes the contract initialization process
-func Run(ctx context.Context, cfg *config.Config, opts ...func(*Options)) error {
+// Summary is the machine-readable form of a deployment Result
+type Summary struct {
+	PackageID       string `json:"package_id"`
+	RegistryID      string `json:"registry_id"`
+	PosPackageID    string `json:"pos_package_id"`
+	NegPackageID    string `json:"neg_package_id"`
+	VaultID         string `json:"vault_id"`
+	VaultAdminCapID string `json:"vault_admin_cap_id"`
+	Address         string `json:"address,omitempty"`
+	TxDigest        string `json:"tx_digest"`
+	Network         string `json:"network"`
+}
+
+// NewSummary flattens a deployment result into a Summary
+func NewSummary(result *Result) Summary {
+	summary := Summary{
+		Address:  result.Address,
+		TxDigest: result.TransactionId,
+		Network:  result.Network,
+	}
+	if result.PackageId != nil {
+		summary.PackageID = result.PackageId.String()
+	}
+	if result.RegistryId != nil {
+		summary.RegistryID = result.RegistryId.String()
+	}
+	summary.PosPackageID, _ = result.Metadata["pos_package_id"].(string)
+	summary.NegPackageID, _ = result.Metadata["neg_package_id"].(string)
+	summary.VaultID, _ = result.Metadata["vault_id"].(string)
+	summary.VaultAdminCapID, _ = result.Metadata["vault_admin_cap_id"].(string)
+	return summary
+}
+
+// Run executes the contract initialization process and returns the deployment result
+func Run(ctx context.Context, cfg *config.Config, opts ...func(*Options)) (*Result, error) {
 	// Apply options
 	options := &Options{
 		ContractPath: filepath.Join(cfg.ContractsPath, "sources"),
@@ -52,16 +90,20 @@ func Run(ctx context.Context, cfg *config.Config, opts ...func(*Options)) error
 
 	// Validate required configuration
 	if err := validateConfig(cfg); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	// Initialize database connection
 	database, err := db.NewDatabase(cfg.DatabasePath)
 	if err != nil {
-		return fmt.Errorf("failed to initialize database: %w", err)
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 	defer database.Close()
 
+	if cfg.DeployTarget == "ethereum" {
+		return runEthereum(ctx, cfg, options, database)
+	}
+
 	// // Check for existing deployment
 	// if options.SkipIfExists {
 	// 	existing, err := database.GetContractByName(ctx, "conditional_tokens_framework", cfg.SUI.ChainID)
@@ -80,21 +122,47 @@ func Run(ctx context.Context, cfg *config.Config, opts ...func(*Options)) error
 	// Initialize Sui client and signer
 	client, signer, err := initializeSuiClient(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize Sui client: %w", err)
+		return nil, fmt.Errorf("failed to initialize Sui client: %w", err)
 	}
 
 	// Request funds from faucet if requested
 	if options.FundFromFaucet {
-		if err := fundFromFaucet(client, signer, cfg); err != nil {
-			return fmt.Errorf("failed to fund from faucet: %w", err)
+		if err := fundFromFaucet(ctx, client, signer, cfg, options.Faucet); err != nil {
+			return nil, fmt.Errorf("failed to fund from faucet: %w", err)
 		}
 	}
 
 	// Deploy contracts
-	deployer := NewSuiDeployer(client, signer, options.ContractPath)
+	deployer := options.Deployer
+	if deployer == nil {
+		deployer = NewSuiDeployer(client, signer, options.ContractPath)
+	}
+	resumable, ok := deployer.(ResumableDeployer)
+	if ok {
+		progress, err := loadProgress(ctx, database, cfg.SUI.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			resumable.Resume(progress)
+		}
+	}
+
 	result, err := deployer.Deploy(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("deployment failed: %w", err)
+		// Record what was already created so the next run resumes instead of redeploying.
+		// Nothing else is persisted: no contract row and no .env update.
+		var partialErr *PartialDeploymentError
+		if errors.As(err, &partialErr) {
+			if saveErr := saveProgress(ctx, database, cfg.SUI.ChainID, partialErr.Progress); saveErr != nil {
+				log.Error().Err(saveErr).Msg("Failed to record partial deployment progress")
+			} else {
+				log.Warn().
+					Interface("completed", partialErr.Progress.metadata()).
+					Msg("Recorded partial deployment progress; the next run will resume from it")
+			}
+		}
+		return nil, fmt.Errorf("deployment failed: %w", err)
 	}
 
 	// Store deployment result in database
@@ -116,7 +184,11 @@ func Run(ctx context.Context, cfg *config.Config, opts ...func(*Options)) error
 	}
 
 	if err := database.SaveContract(ctx, contract); err != nil {
-		return fmt.Errorf("failed to save contract deployment: %w", err)
+		return nil, fmt.Errorf("failed to save contract deployment: %w", err)
+	}
+
+	if err := database.DeleteContract(ctx, progressContractName, cfg.SUI.ChainID); err != nil {
+		log.Warn().Err(err).Msg("Failed to clear partial deployment progress")
 	}
 
 	// Update .env file with deployment results
@@ -132,7 +204,42 @@ func Run(ctx context.Context, cfg *config.Config, opts ...func(*Options)) error
 		Str("network", cfg.SUI.ChainID).
 		Msg("Successfully deployed contracts and saved to database")
 
-	return nil
+	return result, nil
+}
+
+// runEthereum deploys the challenge registry to an EVM chain and records the deployment
+func runEthereum(ctx context.Context, cfg *config.Config, options *Options, database db.Database) (*Result, error) {
+	deployer := options.Deployer
+	if deployer == nil {
+		deployer = NewEthereumDeployer(cfg.ETH.RPCUrl, cfg.ETH.PrivateKey, cfg.ETH.ChainID)
+	}
+	result, err := deployer.Deploy(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("deployment failed: %w", err)
+	}
+
+	contract := &models.Contract{
+		Name:         "challenge_registry",
+		Address:      result.Address,
+		Network:      result.Network,
+		TxHash:       result.TransactionId,
+		DeployedAt:   time.Now(),
+		ChainID:      result.Network,
+		ContractType: "ethereum_contract",
+		Metadata:     result.Metadata,
+	}
+
+	if err := database.SaveContract(ctx, contract); err != nil {
+		return nil, fmt.Errorf("failed to save contract deployment: %w", err)
+	}
+
+	log.Info().
+		Str("address", result.Address).
+		Str("transaction_id", result.TransactionId).
+		Str("network", result.Network).
+		Msg("Successfully deployed contract and saved to database")
+
+	return result, nil
 }
 
 // updateEnvFile updates the .env file with the deployed package and registry IDs
@@ -165,97 +272,216 @@ func updateEnvFile(packageId, registryId string, metadata map[string]interface{}
 		Str("vault_admin_cap_id", vaultAdminCapId).
 		Msg("Updating .env file with deployment results")
 
-	// Read the existing .env file
-	file, err := os.Open(envPath)
+	// Package and registry IDs are always written; the rest only when known
+	updates := []envUpdate{
+		{"SUI_PACKAGE_ID", packageId},
+		{"SUI_REGISTRY_ID", registryId},
+	}
+	for _, u := range []envUpdate{
+		{"SUI_POS_PACKAGE_ID", posPackageId},
+		{"SUI_NEG_PACKAGE_ID", negPackageId},
+		{"SUI_VAULT_ID", vaultId},
+		{"SUI_VAULT_ADMIN_CAP_ID", vaultAdminCapId},
+	} {
+		if u.value != "" {
+			updates = append(updates, u)
+		}
+	}
+
+	if err := rewriteEnvFile(envPath, updates); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("env_path", envPath).
+		Msg("Successfully updated .env file with deployment results")
+
+	return nil
+}
+
+// envUpdate is a key to set in a .env file
+type envUpdate struct {
+	key   string
+	value string
+}
+
+// rewriteEnvFile sets each key in the .env file at path, appending keys that are missing.
+// Comments, blank lines, `export` prefixes, quoting and inline comments are preserved.
+// The new content is written to a temp file in the same directory and renamed over the
+// original, so a crash never leaves a truncated .env behind.
+func rewriteEnvFile(path string, updates []envUpdate) error {
+	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("failed to open .env file: %w", err)
 	}
-	defer file.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading .env file: %w", err)
+	}
+
+	values := make(map[string]string, len(updates))
+	for _, u := range updates {
+		values[u.key] = u.value
+	}
+
+	text := strings.TrimSuffix(string(content), "\n")
 
 	var lines []string
-	scanner := bufio.NewScanner(file)
-
-	packageIdPattern := regexp.MustCompile(`^SUI_PACKAGE_ID=.*`)
-	registryIdPattern := regexp.MustCompile(`^SUI_REGISTRY_ID=.*`)
-	posPackageIdPattern := regexp.MustCompile(`^SUI_POS_PACKAGE_ID=.*`)
-	negPackageIdPattern := regexp.MustCompile(`^SUI_NEG_PACKAGE_ID=.*`)
-	vaultIdPattern := regexp.MustCompile(`^SUI_VAULT_ID=.*`)
-	vaultAdminCapIdPattern := regexp.MustCompile(`^SUI_VAULT_ADMIN_CAP_ID=.*`)
-
-	packageIdUpdated := false
-	registryIdUpdated := false
-	posPackageIdUpdated := false
-	negPackageIdUpdated := false
-	vaultIdUpdated := false
-	vaultAdminCapIdUpdated := false
-
-	// Read and update existing lines
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if packageIdPattern.MatchString(line) {
-			lines = append(lines, fmt.Sprintf("SUI_PACKAGE_ID=%s", packageId))
-			packageIdUpdated = true
-		} else if registryIdPattern.MatchString(line) {
-			lines = append(lines, fmt.Sprintf("SUI_REGISTRY_ID=%s", registryId))
-			registryIdUpdated = true
-		} else if posPackageIdPattern.MatchString(line) && posPackageId != "" {
-			lines = append(lines, fmt.Sprintf("SUI_POS_PACKAGE_ID=%s", posPackageId))
-			posPackageIdUpdated = true
-		} else if negPackageIdPattern.MatchString(line) && negPackageId != "" {
-			lines = append(lines, fmt.Sprintf("SUI_NEG_PACKAGE_ID=%s", negPackageId))
-			negPackageIdUpdated = true
-		} else if vaultIdPattern.MatchString(line) && vaultId != "" {
-			lines = append(lines, fmt.Sprintf("SUI_VAULT_ID=%s", vaultId))
-			vaultIdUpdated = true
-		} else if vaultAdminCapIdPattern.MatchString(line) && vaultAdminCapId != "" {
-			lines = append(lines, fmt.Sprintf("SUI_VAULT_ADMIN_CAP_ID=%s", vaultAdminCapId))
-			vaultAdminCapIdUpdated = true
-		} else {
-			lines = append(lines, line)
-		}
+	if text != "" {
+		lines = strings.Split(text, "\n")
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading .env file: %w", err)
+	updated := make(map[string]bool, len(updates))
+	for i, line := range lines {
+		entry, ok := parseEnvLine(line)
+		if !ok {
+			continue
+		}
+		value, ok := values[entry.key]
+		if !ok {
+			continue
+		}
+		lines[i] = entry.withValue(value)
+		updated[entry.key] = true
 	}
 
 	// Add missing entries if they weren't found
-	if !packageIdUpdated {
-		lines = append(lines, fmt.Sprintf("SUI_PACKAGE_ID=%s", packageId))
+	for _, u := range updates {
+		if !updated[u.key] {
+			lines = append(lines, fmt.Sprintf("%s=%s", u.key, u.value))
+		}
+	}
+
+	output := strings.Join(lines, "\n") + "\n"
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".env.tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary .env file: %w", err)
 	}
-	if !registryIdUpdated {
-		lines = append(lines, fmt.Sprintf("SUI_REGISTRY_ID=%s", registryId))
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := tmp.WriteString(output); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write .env file: %w", err)
 	}
-	if !posPackageIdUpdated && posPackageId != "" {
-		lines = append(lines, fmt.Sprintf("SUI_POS_PACKAGE_ID=%s", posPackageId))
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write .env file: %w", err)
 	}
-	if !negPackageIdUpdated && negPackageId != "" {
-		lines = append(lines, fmt.Sprintf("SUI_NEG_PACKAGE_ID=%s", negPackageId))
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write .env file: %w", err)
 	}
-	if !vaultIdUpdated && vaultId != "" {
-		lines = append(lines, fmt.Sprintf("SUI_VAULT_ID=%s", vaultId))
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set .env file permissions: %w", err)
 	}
-	if !vaultAdminCapIdUpdated && vaultAdminCapId != "" {
-		lines = append(lines, fmt.Sprintf("SUI_VAULT_ADMIN_CAP_ID=%s", vaultAdminCapId))
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace .env file: %w", err)
 	}
 
-	// Write the updated content back to the file
-	content := strings.Join(lines, "\n") + "\n"
-	err = os.WriteFile(envPath, []byte(content), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write .env file: %w", err)
+	return nil
+}
+
+// envLine is an assignment line of a .env file split into its parts
+type envLine struct {
+	prefix string // Everything before the value: indentation, `export `, key and `=`
+	key    string
+	quote  byte   // Quote character around the value, or 0 if unquoted
+	suffix string // Everything after the value: whitespace and any inline comment
+}
+
+// withValue renders the line with value in place of the old one, keeping its quoting
+func (e envLine) withValue(value string) string {
+	if e.quote == 0 {
+		return e.prefix + value + e.suffix
+	}
+	if e.quote == '"' {
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
 	}
+	q := string(e.quote)
+	return e.prefix + q + value + q + e.suffix
+}
 
-	log.Info().
-		Str("env_path", envPath).
-		Msg("Successfully updated .env file with deployment results")
+// parseEnvLine splits a KEY=VALUE line. It reports false for comments, blank lines
+// and anything else that is not an assignment.
+func parseEnvLine(line string) (envLine, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || trimmed[0] == '#' {
+		return envLine{}, false
+	}
 
-	return nil
+	eq := strings.IndexByte(line, '=')
+	if eq < 0 {
+		return envLine{}, false
+	}
+	key := strings.TrimSpace(line[:eq])
+	key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+	if !envKeyPattern.MatchString(key) {
+		return envLine{}, false
+	}
+
+	// Keep whitespace after `=` as part of the prefix
+	valueStart := eq + 1
+	for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t') {
+		valueStart++
+	}
+	entry := envLine{prefix: line[:valueStart], key: key}
+	rest := line[valueStart:]
+
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+		if end := closingQuote(rest); end > 0 {
+			entry.quote = rest[0]
+			entry.suffix = rest[end+1:]
+			return entry, true
+		}
+	}
+
+	// Unquoted: an inline comment starts at whitespace followed by '#'
+	if idx := inlineCommentPattern.FindStringIndex(rest); idx != nil {
+		entry.suffix = rest[idx[0]:]
+		return entry, true
+	}
+	trailing := len(rest) - len(strings.TrimRight(rest, " \t"))
+	entry.suffix = rest[len(rest)-trailing:]
+	return entry, true
+}
+
+// closingQuote returns the index of the quote closing s[0], or -1 if it is unterminated.
+// Backslash escapes are honoured inside double quotes only.
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
 }
 
+var (
+	envKeyPattern        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	inlineCommentPattern = regexp.MustCompile(`[ \t]+#`)
+)
+
 // validateConfig ensures all required configuration is present
 func validateConfig(cfg *config.Config) error {
+	if cfg.DeployTarget == "ethereum" {
+		if cfg.ETH.RPCUrl == "" {
+			return fmt.Errorf("ETH_RPC_URL is required")
+		}
+		if cfg.ETH.PrivateKey == "" {
+			return fmt.Errorf("ETH_PRIVATE_KEY is required")
+		}
+		if cfg.ETH.BytecodePath == "" {
+			return fmt.Errorf("ETH_CONTRACT_BYTECODE_PATH is required")
+		}
+		return nil
+	}
+
 	if cfg.SUI.RPCUrl == "" {
 		return fmt.Errorf("SUI_RPC_URL is required")
 	}
@@ -283,39 +509,49 @@ func initializeSuiClient(cfg *config.Config) (*suiclient.ClientImpl, *suisigner.
 }
 
 // fundFromFaucet requests SUI tokens from the faucet for the deployer address
-func fundFromFaucet(client *suiclient.ClientImpl, signer *suisigner.Signer, cfg *config.Config) error {
+func fundFromFaucet(ctx context.Context, client *suiclient.ClientImpl, signer *suisigner.Signer, cfg *config.Config, faucet localsui.Faucet) error {
 	log.Info().
 		Str("address", signer.Address.String()).
 		Str("network", cfg.SUI.ChainID).
 		Msg("Requesting funds from faucet")
 
-	// Determine faucet URL based on network
-	var faucetUrl string
-	fmt.Println("cfg.SUI.ChainID: ", cfg.SUI.ChainID)
-	switch cfg.SUI.ChainID {
-	case "testnet":
-		faucetUrl = "https://faucet.testnet.sui.io/gas"
-	case "devnet":
-		faucetUrl = "https://faucet.devnet.sui.io/gas"
-	case "localnet":
-		faucetUrl = "http://127.0.0.1:9123/gas" // Default localnet faucet
-	default:
-		return fmt.Errorf("faucet not available for network: %s (use testnet, devnet, or localnet)", cfg.SUI.ChainID)
-	}
-
-	err := suiclient.RequestFundFromFaucet(signer.Address, faucetUrl)
-	if err != nil {
-		return fmt.Errorf("failed to request funds from faucet %s: %w", faucetUrl, err)
+	balances := localsui.NewClientBalanceChecker(client)
+	if faucet == nil {
+		faucetUrl, err := faucetURLForNetwork(cfg.SUI.ChainID)
+		if err != nil {
+			return err
+		}
+		if err := localsui.FundFromFaucetWithRetry(ctx, signer.Address, faucetUrl, localsui.DefaultFaucetMinBalance, balances, log.Logger); err != nil {
+			return err
+		}
+	} else {
+		funder := localsui.NewFunder(faucet, balances, localsui.DefaultFaucetMinBalance, log.Logger)
+		if _, err := funder.EnsureFunded(ctx, signer.Address); err != nil {
+			return err
+		}
 	}
 
 	log.Info().
 		Str("address", signer.Address.String()).
-		Str("faucet_url", faucetUrl).
 		Msg("Successfully requested funds from faucet")
 
 	return nil
 }
 
+// faucetURLForNetwork returns the public faucet endpoint for a network
+func faucetURLForNetwork(chainID string) (string, error) {
+	switch chainID {
+	case "testnet":
+		return "https://faucet.testnet.sui.io/gas", nil
+	case "devnet":
+		return "https://faucet.devnet.sui.io/gas", nil
+	case "localnet":
+		return "http://127.0.0.1:9123/gas", nil // Default localnet faucet
+	default:
+		return "", fmt.Errorf("faucet not available for network: %s (use testnet, devnet, or localnet)", chainID)
+	}
+}
+
 // WithContractPath sets a custom contract path
 func WithContractPath(path string) func(*Options) {
 	return func(o *Options) {
@@ -336,3 +572,17 @@ func WithFundFromFaucet(fund bool) func(*Options) {
 		o.FundFromFaucet = fund
 	}
 }
+
+// WithFaucet overrides the faucet used to fund the deployer address
+func WithFaucet(faucet localsui.Faucet) func(*Options) {
+	return func(o *Options) {
+		o.Faucet = faucet
+	}
+}
+
+// WithDeployer overrides the deployer used to publish contracts
+func WithDeployer(deployer Deployer) func(*Options) {
+	return func(o *Options) {
+		o.Deployer = deployer
+	}
+}
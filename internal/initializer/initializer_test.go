@@ -0,0 +1,301 @@
+package initializer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pattonkan/sui-go/sui"
+
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
+)
+
+// flakyDeployer creates the main package and pos token, then fails to create the registry
+// until failRegistry is cleared. It records the progress it was resumed with.
+type flakyDeployer struct {
+	failRegistry bool
+	resumed      *DeploymentProgress
+	published    int // Number of times the main package was published
+}
+
+func (d *flakyDeployer) Resume(progress *DeploymentProgress) {
+	d.resumed = progress
+}
+
+func (d *flakyDeployer) Deploy(ctx context.Context, cfg *config.Config) (*Result, error) {
+	progress := &DeploymentProgress{}
+	if d.resumed != nil {
+		*progress = *d.resumed
+	}
+	if progress.PackageId == nil {
+		d.published++
+		progress.PackageId = sui.MustPackageIdFromHex("0x1")
+		progress.TxDigest = "publish_digest"
+	}
+	if progress.PosPackageId == nil {
+		progress.PosPackageId = sui.MustPackageIdFromHex("0x3")
+		progress.PosTreasuryCap = sui.MustObjectIdFromHex("0x31")
+	}
+	if progress.NegPackageId == nil {
+		progress.NegPackageId = sui.MustPackageIdFromHex("0x4")
+		progress.NegTreasuryCap = sui.MustObjectIdFromHex("0x41")
+	}
+	if d.failRegistry {
+		return nil, &PartialDeploymentError{Progress: progress, Err: errors.New("registry creation failed")}
+	}
+	progress.RegistryId = sui.MustObjectIdFromHex("0x2")
+	progress.VaultId = sui.MustObjectIdFromHex("0x5")
+	progress.VaultAdminCapId = sui.MustObjectIdFromHex("0x6")
+
+	return &Result{
+		PackageId:     progress.PackageId,
+		RegistryId:    progress.RegistryId,
+		TransactionId: progress.TxDigest,
+		Network:       cfg.SUI.ChainID,
+		Metadata: map[string]interface{}{
+			"pos_package_id":     progress.PosPackageId.String(),
+			"neg_package_id":     progress.NegPackageId.String(),
+			"vault_id":           progress.VaultId.String(),
+			"vault_admin_cap_id": progress.VaultAdminCapId.String(),
+		},
+	}, nil
+}
+
+func newRunTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	if err := os.WriteFile(".env", []byte("SUI_RPC_URL=http://localhost:9000\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .env: %v", err)
+	}
+
+	cfg := &config.Config{DatabasePath: filepath.Join(tempDir, "initializer.db")}
+	cfg.SUI.RPCUrl = "http://localhost:9000"
+	cfg.SUI.ChainID = "localnet"
+	cfg.SUI.InitializerMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	return cfg
+}
+
+func openTestDatabase(t *testing.T, cfg *config.Config) db.Database {
+	t.Helper()
+
+	database, err := db.NewDatabase(cfg.DatabasePath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestRun_PartialFailureRecordsProgressWithoutPlaceholders(t *testing.T) {
+	cfg := newRunTestConfig(t)
+	deployer := &flakyDeployer{failRegistry: true}
+
+	_, err := Run(context.Background(), cfg, WithFundFromFaucet(false), WithDeployer(deployer))
+	if err == nil || !strings.Contains(err.Error(), "registry creation failed") {
+		t.Fatalf("Run() expected registry failure, got %v", err)
+	}
+
+	database := openTestDatabase(t, cfg)
+	ctx := context.Background()
+
+	if _, err := database.GetContractByName(ctx, "conditional_tokens_framework", cfg.SUI.ChainID); err == nil {
+		t.Error("Expected no deployment to be recorded after a partial failure")
+	}
+
+	progress, err := database.GetContractByName(ctx, progressContractName, cfg.SUI.ChainID)
+	if err != nil {
+		t.Fatalf("Expected partial progress to be recorded: %v", err)
+	}
+	if progress.Address != sui.MustPackageIdFromHex("0x1").String() || progress.TxHash != "publish_digest" {
+		t.Errorf("Unexpected progress record: address=%s tx=%s", progress.Address, progress.TxHash)
+	}
+	placeholder := (&sui.ObjectId{}).String()
+	for key, value := range progress.Metadata {
+		if value == placeholder {
+			t.Errorf("Expected no placeholder ids, got %s=%v", key, value)
+		}
+	}
+	for _, key := range []string{"registry_id", "vault_id", "vault_admin_cap_id"} {
+		if _, ok := progress.Metadata[key]; ok {
+			t.Errorf("Expected %s to be absent from progress, got %v", key, progress.Metadata[key])
+		}
+	}
+	if len(progress.Metadata) != 5 {
+		t.Errorf("Expected 5 completed ids, got %v", progress.Metadata)
+	}
+
+	env, err := os.ReadFile(".env")
+	if err != nil {
+		t.Fatalf("Failed to read .env: %v", err)
+	}
+	if string(env) != "SUI_RPC_URL=http://localhost:9000\n" {
+		t.Errorf("Expected .env to be untouched after a partial failure, got:\n%s", env)
+	}
+}
+
+func TestRun_ResumesPartialDeployment(t *testing.T) {
+	cfg := newRunTestConfig(t)
+	deployer := &flakyDeployer{failRegistry: true}
+
+	if _, err := Run(context.Background(), cfg, WithFundFromFaucet(false), WithDeployer(deployer)); err == nil {
+		t.Fatal("Run() expected the first attempt to fail")
+	}
+
+	deployer.failRegistry = false
+	result, err := Run(context.Background(), cfg, WithFundFromFaucet(false), WithDeployer(deployer))
+	if err != nil {
+		t.Fatalf("Run() unexpected error on resume: %v", err)
+	}
+
+	if deployer.resumed == nil || deployer.resumed.PosTreasuryCap.String() != sui.MustObjectIdFromHex("0x31").String() {
+		t.Fatalf("Expected the deployer to be resumed with recorded progress, got %+v", deployer.resumed)
+	}
+	if deployer.published != 1 {
+		t.Errorf("Expected the main package to be published once, got %d", deployer.published)
+	}
+	if result.RegistryId.String() != sui.MustObjectIdFromHex("0x2").String() {
+		t.Errorf("Unexpected registry id %s", result.RegistryId)
+	}
+
+	database := openTestDatabase(t, cfg)
+	ctx := context.Background()
+	if _, err := database.GetContractByName(ctx, "conditional_tokens_framework", cfg.SUI.ChainID); err != nil {
+		t.Errorf("Expected the completed deployment to be recorded: %v", err)
+	}
+	if _, err := database.GetContractByName(ctx, progressContractName, cfg.SUI.ChainID); err == nil {
+		t.Error("Expected partial progress to be cleared after a successful deployment")
+	}
+
+	env, err := os.ReadFile(".env")
+	if err != nil {
+		t.Fatalf("Failed to read .env: %v", err)
+	}
+	if !strings.Contains(string(env), "SUI_REGISTRY_ID="+sui.MustObjectIdFromHex("0x2").String()) {
+		t.Errorf("Expected .env to carry the real registry id, got:\n%s", env)
+	}
+}
+
+func TestProgressFromContract(t *testing.T) {
+	progress := &DeploymentProgress{
+		PackageId:      sui.MustPackageIdFromHex("0x1"),
+		TxDigest:       "digest",
+		PosPackageId:   sui.MustPackageIdFromHex("0x3"),
+		PosTreasuryCap: sui.MustObjectIdFromHex("0x31"),
+	}
+
+	cfg := newRunTestConfig(t)
+	database := openTestDatabase(t, cfg)
+	ctx := context.Background()
+	if err := saveProgress(ctx, database, "localnet", progress); err != nil {
+		t.Fatalf("saveProgress() unexpected error: %v", err)
+	}
+
+	loaded, err := loadProgress(ctx, database, "localnet")
+	if err != nil {
+		t.Fatalf("loadProgress() unexpected error: %v", err)
+	}
+	if loaded.TxDigest != "digest" || loaded.PackageId.String() != progress.PackageId.String() ||
+		loaded.PosTreasuryCap.String() != progress.PosTreasuryCap.String() {
+		t.Errorf("Round trip mismatch: %+v", loaded)
+	}
+	if loaded.NegPackageId != nil || loaded.RegistryId != nil || loaded.VaultId != nil {
+		t.Errorf("Expected incomplete steps to stay nil, got %+v", loaded)
+	}
+
+	if none, err := loadProgress(ctx, database, "devnet"); err != nil || none != nil {
+		t.Errorf("Expected no progress on another chain, got %+v, %v", none, err)
+	}
+}
+
+func TestUpdateEnvFile_PreservesCommentsAndQuoting(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "fixture.env"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "fixture.env.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(".env", fixture, 0600); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+
+	err = updateEnvFile("0x1", "0x2", map[string]interface{}{
+		"pos_package_id":     "0x3",
+		"neg_package_id":     "0x4",
+		"vault_id":           "0x5",
+		"vault_admin_cap_id": "0x6",
+	})
+	if err != nil {
+		t.Fatalf("updateEnvFile() unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(".env")
+	if err != nil {
+		t.Fatalf("Failed to read .env: %v", err)
+	}
+	if string(got) != string(golden) {
+		t.Errorf("Unexpected .env after update:\n%s\nwant:\n%s", got, golden)
+	}
+
+	info, err := os.Stat(".env")
+	if err != nil {
+		t.Fatalf("Failed to stat .env: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected .env permissions 0600 to be kept, got %v", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only .env to remain, got %d entries", len(entries))
+	}
+}
+
+func TestParseEnvLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		value string
+		want  string
+		ok    bool
+	}{
+		{line: "KEY=old", value: "new", want: "KEY=new", ok: true},
+		{line: "KEY = old  # note", value: "new", want: "KEY = new  # note", ok: true},
+		{line: `KEY="a \"quoted\" value" # note`, value: `say "hi"`, want: `KEY="say \"hi\"" # note`, ok: true},
+		{line: "KEY='single'", value: "x", want: "KEY='x'", ok: true},
+		{line: "KEY=a#b", value: "x", want: "KEY=x", ok: true},
+		{line: `KEY="unterminated`, value: "x", want: "KEY=x", ok: true},
+		{line: "# KEY=commented", ok: false},
+		{line: "   ", ok: false},
+		{line: "not an assignment", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			entry, ok := parseEnvLine(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("parseEnvLine(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if entry.key != "KEY" {
+				t.Errorf("Expected key KEY, got %q", entry.key)
+			}
+			if got := entry.withValue(tt.value); got != tt.want {
+				t.Errorf("withValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+)
+
+const (
+	progressContractName = "conditional_tokens_framework_progress"
+	progressContractType = "sui_deployment_progress"
+)
+
+// DeploymentProgress records the objects a Sui deployment has created so far.
+// Nil fields are steps that have not completed yet.
+type DeploymentProgress struct {
+	PackageId       *sui.PackageId
+	TxDigest        string // Digest of the main package publish
+	PosPackageId    *sui.PackageId
+	PosTreasuryCap  *sui.ObjectId
+	NegPackageId    *sui.PackageId
+	NegTreasuryCap  *sui.ObjectId
+	RegistryId      *sui.ObjectId
+	VaultId         *sui.ObjectId
+	VaultAdminCapId *sui.ObjectId
+}
+
+// progressFields pairs each metadata key with its progress field
+func (p *DeploymentProgress) progressFields() map[string]**sui.ObjectId {
+	return map[string]**sui.ObjectId{
+		"package_id":         &p.PackageId,
+		"pos_package_id":     &p.PosPackageId,
+		"pos_treasury_cap":   &p.PosTreasuryCap,
+		"neg_package_id":     &p.NegPackageId,
+		"neg_treasury_cap":   &p.NegTreasuryCap,
+		"registry_id":        &p.RegistryId,
+		"vault_id":           &p.VaultId,
+		"vault_admin_cap_id": &p.VaultAdminCapId,
+	}
+}
+
+// metadata returns the completed steps as contract metadata, omitting steps not yet done
+func (p *DeploymentProgress) metadata() map[string]interface{} {
+	metadata := make(map[string]interface{})
+	for key, field := range p.progressFields() {
+		if *field != nil {
+			metadata[key] = (*field).String()
+		}
+	}
+	return metadata
+}
+
+// progressFromContract restores deployment progress from its stored record
+func progressFromContract(contract *models.Contract) (*DeploymentProgress, error) {
+	progress := &DeploymentProgress{TxDigest: contract.TxHash}
+	for key, field := range progress.progressFields() {
+		value, ok := contract.Metadata[key].(string)
+		if !ok {
+			continue
+		}
+		id, err := sui.ObjectIdFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in deployment progress: %w", key, err)
+		}
+		*field = id
+	}
+	return progress, nil
+}
+
+// PartialDeploymentError is returned when a deployment fails after some objects were
+// already created on-chain. Progress holds those objects so a later run can resume.
+type PartialDeploymentError struct {
+	Progress *DeploymentProgress
+	Err      error
+}
+
+func (e *PartialDeploymentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialDeploymentError) Unwrap() error {
+	return e.Err
+}
+
+// ResumableDeployer is a Deployer that can skip steps completed by an earlier, failed run
+type ResumableDeployer interface {
+	Deployer
+	Resume(progress *DeploymentProgress)
+}
+
+var _ ResumableDeployer = (*SuiDeployer)(nil)
+
+// loadProgress returns the recorded progress of an earlier failed deployment, or nil if there is none
+func loadProgress(ctx context.Context, database db.Database, chainID string) (*DeploymentProgress, error) {
+	contract, err := database.GetContractByName(ctx, progressContractName, chainID)
+	if err != nil {
+		if err.Error() == "contract not found" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load deployment progress: %w", err)
+	}
+	return progressFromContract(contract)
+}
+
+// saveProgress records the objects created by a partially failed deployment
+func saveProgress(ctx context.Context, database db.Database, chainID string, progress *DeploymentProgress) error {
+	address := ""
+	if progress.PackageId != nil {
+		address = progress.PackageId.String()
+	}
+	return database.SaveContract(ctx, &models.Contract{
+		Name:         progressContractName,
+		Address:      address,
+		Network:      chainID,
+		TxHash:       progress.TxDigest,
+		DeployedAt:   time.Now(),
+		ChainID:      chainID,
+		ContractType: progressContractType,
+		Metadata:     progress.metadata(),
+	})
+}
@@ -0,0 +1,110 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+// maxBackendResponseSize caps how much of a backend response is read.
+const maxBackendResponseSize = 1 << 20
+
+// SolverBackend computes answers for challenge problems, typically by calling
+// an external inference service.
+type SolverBackend interface {
+	Solve(ctx context.Context, problem json.RawMessage, outputSpec json.RawMessage) (answer string, metadata models.SolverMetadata, err error)
+}
+
+// backendSolveRequest is the body POSTed to an HTTP solver backend.
+type backendSolveRequest struct {
+	Problem    json.RawMessage `json:"problem"`     // Problem exactly as received from the challenger
+	OutputSpec json.RawMessage `json:"output_spec"` // Expected answer format
+}
+
+// backendSolveResponse is the body expected back from an HTTP solver backend.
+type backendSolveResponse struct {
+	Answer   string                `json:"answer"`             // Solved answer
+	Metadata models.SolverMetadata `json:"metadata,omitempty"` // Optional solver metadata
+}
+
+// HTTPSolverBackend solves challenges by POSTing them as JSON to an external service.
+// The service receives {"problem": ..., "output_spec": ...} and must reply with
+// a 2xx status and {"answer": "...", "metadata": {...}}.
+type HTTPSolverBackend struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSolverBackend creates a backend that calls url, aborting requests after timeout.
+func NewHTTPSolverBackend(url string, timeout time.Duration) *HTTPSolverBackend {
+	return &HTTPSolverBackend{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Solve sends the problem to the backend and returns its answer and metadata.
+func (b *HTTPSolverBackend) Solve(ctx context.Context, problem json.RawMessage, outputSpec json.RawMessage) (string, models.SolverMetadata, error) {
+	body, err := json.Marshal(backendSolveRequest{Problem: problem, OutputSpec: outputSpec})
+	if err != nil {
+		return "", models.SolverMetadata{}, fmt.Errorf("failed to marshal backend request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.url, bytes.NewReader(body))
+	if err != nil {
+		return "", models.SolverMetadata{}, fmt.Errorf("failed to create backend request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", models.SolverMetadata{}, fmt.Errorf("backend request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendResponseSize))
+	if err != nil {
+		return "", models.SolverMetadata{}, fmt.Errorf("failed to read backend response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", models.SolverMetadata{}, fmt.Errorf("backend returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var solveResp backendSolveResponse
+	if err := json.Unmarshal(respBody, &solveResp); err != nil {
+		return "", models.SolverMetadata{}, fmt.Errorf("failed to decode backend response: %w", err)
+	}
+
+	return solveResp.Answer, solveResp.Metadata, nil
+}
+
+// backendSolver adapts a SolverBackend to the worker pool's Solver interface.
+// The solve context is passed through so timeouts and pool shutdown abort in-flight requests.
+func backendSolver(backend SolverBackend) Solver {
+	return SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		startTime := time.Now()
+
+		answer, metadata, err := backend.Solve(ctx, challenge.Problem, challenge.OutputSpec)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if metadata.ComputeTimeMs == 0 {
+			metadata.ComputeTimeMs = int(time.Since(startTime).Milliseconds())
+		}
+
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal solver metadata: %w", err)
+		}
+
+		return answer, metadataJSON, nil
+	})
+}
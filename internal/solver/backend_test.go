@@ -0,0 +1,157 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+func TestHTTPSolverBackend_Solve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+
+		var req backendSolveRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode backend request: %v", err)
+		}
+		if string(req.Problem) != `{"type":"math","data":"2+2"}` {
+			t.Errorf("unexpected problem: %s", req.Problem)
+		}
+		if string(req.OutputSpec) != `{"content_type":"text/plain"}` {
+			t.Errorf("unexpected output spec: %s", req.OutputSpec)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"answer":"4","metadata":{"algorithm":"remote-v1","compute_time_ms":12}}`))
+	}))
+	defer server.Close()
+
+	backend := NewHTTPSolverBackend(server.URL, 5*time.Second)
+	answer, metadata, err := backend.Solve(context.Background(),
+		json.RawMessage(`{"type":"math","data":"2+2"}`),
+		json.RawMessage(`{"content_type":"text/plain"}`))
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if answer != "4" {
+		t.Errorf("expected answer 4, got %q", answer)
+	}
+	if metadata.Algorithm != "remote-v1" || metadata.ComputeTimeMs != 12 {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestHTTPSolverBackend_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"server error", http.StatusInternalServerError, `model overloaded`, "status 500"},
+		{"malformed body", http.StatusOK, `not json`, "decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			backend := NewHTTPSolverBackend(server.URL, 5*time.Second)
+			_, _, err := backend.Solve(context.Background(), json.RawMessage(`{}`), json.RawMessage(`{}`))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHTTPSolverBackend_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	backend := NewHTTPSolverBackend(server.URL, 50*time.Millisecond)
+	if _, _, err := backend.Solve(context.Background(), json.RawMessage(`{}`), json.RawMessage(`{}`)); err == nil {
+		t.Error("expected timeout error")
+	}
+}
+
+func TestWorkerUsesConfiguredBackend(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"answer":"from-backend","metadata":{"algorithm":"remote-v1"}}`))
+	}))
+	defer backendServer.Close()
+
+	callbacks := make(chan models.CallbackRequest, 1)
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq
+	}))
+	defer callbackServer.Close()
+
+	service, database := newTestService(t)
+	service.SetSolverBackend(NewHTTPSolverBackend(backendServer.URL, 5*time.Second))
+
+	ctx := context.Background()
+	if err := database.SaveChallenge(ctx, &models.PendingChallenge{
+		ID:            "ch_backend",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   callbackServer.URL + "/callback/ch_backend",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	defer pool.Stop()
+
+	challenge, err := database.GetChallenge(ctx, "ch_backend")
+	if err != nil {
+		t.Fatalf("failed to load challenge: %v", err)
+	}
+	pool.jobQueue <- challenge
+
+	select {
+	case callbackReq := <-callbacks:
+		if callbackReq.Status != "success" || callbackReq.Answer != "from-backend" {
+			t.Errorf("expected backend answer, got status %q answer %q", callbackReq.Status, callbackReq.Answer)
+		}
+
+		var metadata models.SolverMetadata
+		if err := json.Unmarshal(callbackReq.Metadata, &metadata); err != nil {
+			t.Fatalf("failed to decode solver metadata: %v", err)
+		}
+		if metadata.Algorithm != "remote-v1" {
+			t.Errorf("expected backend metadata to be forwarded, got %+v", metadata)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+}
@@ -0,0 +1,259 @@
+//go:build grpcbridge
+
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/nonce"
+	solverapi "reverse-challenge-system/proto/solverapi"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Metadata keys of the solve API
+const (
+	grpcAuthMetadataKey      = "authorization" // RCS-HMAC-SHA256 header, as sent over HTTP
+	grpcRequestIDMetadataKey = "x-request-id"  // Optional request ID for logs
+	grpcRetryAfterHeaderKey  = "retry-after"   // Seconds to wait, sent with QUEUE_FULL
+)
+
+// grpcSolveServer implements the Solver gRPC service on top of the same Service as /solve.
+type grpcSolveServer struct {
+	solverapi.UnimplementedSolverServer
+	svc *Service
+}
+
+// Submit queues a challenge, as POST /solve does
+func (g *grpcSolveServer) Submit(ctx context.Context, req *solverapi.SolveRequest) (*solverapi.SolveResponse, error) {
+	problem, err := grpcJSONField("problem", req.GetProblem())
+	if err != nil {
+		return nil, err
+	}
+	outputSpec, err := grpcJSONField("output_spec", req.GetOutputSpec())
+	if err != nil {
+		return nil, err
+	}
+
+	solveReq := &models.SolveRequest{
+		APIVersion:  req.GetApiVersion(),
+		ChallengeID: req.GetChallengeId(),
+		Problem:     problem,
+		OutputSpec:  outputSpec,
+		Constraints: models.Constraints{
+			TimeoutMs:  int(req.GetTimeoutMs()),
+			DeadlineTs: req.GetDeadlineTs(),
+		},
+		CallbackURL: req.GetCallbackUrl(),
+		Priority:    int(req.GetPriority()),
+	}
+
+	resp, err := g.svc.AcceptSolve(ctx, solveReq, grpcRequestID(ctx))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &solverapi.SolveResponse{
+		Message:     resp.Message,
+		SolverJobId: resp.SolverJobID,
+		ApiVersion:  resp.APIVersion,
+	}, nil
+}
+
+// GetStatus reports a challenge's processing status, as GET /solve/{challenge_id} does
+func (g *grpcSolveServer) GetStatus(ctx context.Context, req *solverapi.ChallengeId) (*solverapi.Status, error) {
+	if req.GetChallengeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "MISSING_CHALLENGE_ID: Challenge ID is required")
+	}
+
+	resp, err := g.svc.ChallengeStatus(ctx, req.GetChallengeId(), grpcRequestID(ctx))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	result := &solverapi.Status{
+		ChallengeId:  resp.ChallengeID,
+		Status:       resp.Status,
+		AttemptCount: int32(resp.AttemptCount),
+	}
+	if resp.NextRetryTime != nil {
+		result.NextRetryTime = timestamppb.New(*resp.NextRetryTime)
+	}
+	return result, nil
+}
+
+// grpcJSONField returns a JSON field of a request, rejecting values that are not valid JSON.
+// An empty value is left empty, as an absent field is in an HTTP request.
+func grpcJSONField(name, value string) (json.RawMessage, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if !json.Valid([]byte(value)) {
+		return nil, status.Errorf(codes.InvalidArgument, "INVALID_JSON: %s is not valid JSON", name)
+	}
+	return json.RawMessage(value), nil
+}
+
+// grpcRequestID returns the request ID sent in metadata, or a fresh one
+func grpcRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(grpcRequestIDMetadataKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return uuid.New().String()
+}
+
+// grpcError converts an error of AcceptSolve or ChallengeStatus to a gRPC status. The message
+// starts with the HTTP error code, and QUEUE_FULL sends a retry-after header.
+func grpcError(ctx context.Context, err error) error {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		return status.Error(codes.Internal, "INTERNAL_ERROR: Internal error")
+	}
+	if reqErr.RetryAfter > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs(grpcRetryAfterHeaderKey, strconv.Itoa(int(reqErr.RetryAfter.Seconds()))))
+	}
+
+	code := codes.Internal
+	switch reqErr.StatusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusServiceUnavailable:
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, reqErr.Error())
+}
+
+// GRPCAuth verifies HMAC-signed gRPC calls, as api.Middleware.HMACAuthForKeys does for HTTP.
+// The "authorization" metadata carries the usual RCS-HMAC-SHA256 header, signed over method
+// "POST", the full gRPC method name as path, and the deterministic binary encoding of the
+// request message as body. Nonces are claimed in the same store as HTTP requests use.
+type GRPCAuth struct {
+	hmacAuth *auth.HMACAuth
+	nonces   nonce.Store     // Replay protection; nil skips nonce checks
+	nonceTTL time.Duration   // How long a nonce is remembered
+	allowed  map[string]bool // Key IDs that may call; nil accepts every known key
+}
+
+// NewGRPCAuth creates a verifier accepting the given key IDs, or every known key when none are given.
+// A zero nonceTTL uses the same default as api.NewMiddleware.
+func NewGRPCAuth(hmacAuth *auth.HMACAuth, nonces nonce.Store, nonceTTL time.Duration, keyIDs ...string) *GRPCAuth {
+	if nonceTTL <= 0 {
+		nonceTTL = 2 * auth.DefaultClockSkew * time.Second
+	}
+	a := &GRPCAuth{hmacAuth: hmacAuth, nonces: nonces, nonceTTL: nonceTTL}
+	if len(keyIDs) > 0 {
+		a.allowed = make(map[string]bool, len(keyIDs))
+		for _, keyID := range keyIDs {
+			a.allowed[keyID] = true
+		}
+	}
+	return a
+}
+
+// UnaryInterceptor authenticates calls to the named gRPC service. Calls to other services
+// on the same server pass through unchanged, so the unauthenticated bridge keeps working.
+func (a *GRPCAuth) UnaryInterceptor(serviceName string) grpc.UnaryServerInterceptor {
+	prefix := "/" + serviceName + "/"
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		if err := a.authenticate(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authenticate checks the signature, key and nonce of one call
+func (a *GRPCAuth) authenticate(ctx context.Context, fullMethod string, req interface{}) error {
+	lg := logger.WithRequestID(grpcRequestID(ctx))
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(grpcAuthMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return status.Error(codes.Unauthenticated, "MISSING_AUTH: authorization metadata required")
+	}
+
+	authInfo, err := auth.ParseAuthHeader(values[0])
+	if err != nil {
+		lg.Error().Err(err).Msg("gRPC: failed to parse auth metadata")
+		return status.Error(codes.Unauthenticated, "INVALID_AUTH: Invalid authorization metadata")
+	}
+
+	body, err := grpcSigningBody(req)
+	if err != nil {
+		return status.Error(codes.Internal, "INTERNAL_ERROR: failed to encode request")
+	}
+
+	if err := a.hmacAuth.VerifySignature("POST", fullMethod, body, authInfo); err != nil {
+		lg.Error().Err(err).Str("key_id", authInfo.KeyID).Msg("gRPC: signature verification failed")
+		if errors.Is(err, auth.ErrKeyNotValid) {
+			return status.Error(codes.Unauthenticated, "KEY_NOT_VALID: Signing key is expired or not yet valid")
+		}
+		return status.Error(codes.Unauthenticated, "INVALID_SIGNATURE: Signature verification failed")
+	}
+
+	if a.allowed != nil && !a.allowed[authInfo.KeyID] {
+		lg.Warn().Str("key_id", authInfo.KeyID).Str("method", fullMethod).Msg("gRPC: signing key not allowed for this method")
+		return status.Error(codes.PermissionDenied, "KEY_NOT_ALLOWED: Signing key is not allowed for this method")
+	}
+
+	// Claimed after verification so unsigned traffic cannot fill the store
+	if a.nonces != nil {
+		firstSeen, err := a.nonces.CheckAndSet(ctx, authInfo.Nonce, a.nonceTTL)
+		if err != nil {
+			lg.Error().Err(err).Msg("gRPC: failed to check nonce")
+			return status.Error(codes.Unavailable, "NONCE_STORE_UNAVAILABLE: Replay protection is unavailable")
+		}
+		if !firstSeen {
+			lg.Error().Str("nonce", authInfo.Nonce).Msg("gRPC: nonce replay detected")
+			return status.Error(codes.Unauthenticated, "REPLAY_ATTACK: Nonce already seen")
+		}
+	}
+	return nil
+}
+
+// SignGRPCCalls returns a client interceptor that signs every call with keyID, for Go
+// clients of the solve API.
+func SignGRPCCalls(hmacAuth *auth.HMACAuth, keyID string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		body, err := grpcSigningBody(req)
+		if err != nil {
+			return err
+		}
+		header := hmacAuth.CreateAuthHeader("POST", method, body, keyID, uuid.New().String())
+		if header == "" {
+			return status.Errorf(codes.Unauthenticated, "unknown signing key %s", keyID)
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, grpcAuthMetadataKey, header)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// grpcSigningBody encodes a request message the way it is signed
+func grpcSigningBody(req interface{}) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, errors.New("request is not a protobuf message")
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
@@ -0,0 +1,214 @@
+//go:build grpcbridge
+
+package solver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/nonce"
+	solverapi "reverse-challenge-system/proto/solverapi"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialTestGRPC serves NewGRPCServer over an in-memory listener and returns a client
+// connection using opts
+func dialTestGRPC(t *testing.T, service *Service, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(service, nonce.NewMemoryStore())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCSubmitRoundTrip(t *testing.T) {
+	service, database := newVersionedTestService(t, "v2.1")
+	service.config.SolverHMACKeyID = "test-key"
+
+	conn := dialTestGRPC(t, service, grpc.WithUnaryInterceptor(SignGRPCCalls(service.hmacAuth, "test-key")))
+	client := solverapi.NewSolverClient(conn)
+	ctx := context.Background()
+
+	resp, err := client.Submit(ctx, &solverapi.SolveRequest{
+		ApiVersion:  "v2.1",
+		ChallengeId: "ch_grpc",
+		Problem:     `{"type":"text"}`,
+		OutputSpec:  `{"content_type":"text/plain"}`,
+		CallbackUrl: "http://127.0.0.1:9/callback/ch_grpc",
+		TimeoutMs:   5000,
+		Priority:    3,
+	})
+	if err != nil {
+		t.Fatalf("Submit() unexpected error: %v", err)
+	}
+	if resp.GetSolverJobId() != "solver_job_ch_grpc" || resp.GetApiVersion() != "v2.1" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+
+	stored, err := database.GetChallenge(ctx, "ch_grpc")
+	if err != nil || stored == nil {
+		t.Fatalf("expected the challenge to be stored, got %v, %v", stored, err)
+	}
+	if stored.Priority != 3 || stored.TimeoutMs != 5000 || string(stored.Problem) != `{"type":"text"}` {
+		t.Errorf("stored challenge does not match the request: %+v", stored)
+	}
+
+	got, err := client.GetStatus(ctx, &solverapi.ChallengeId{ChallengeId: "ch_grpc"})
+	if err != nil {
+		t.Fatalf("GetStatus() unexpected error: %v", err)
+	}
+	if got.GetStatus() != "pending" || got.GetNextRetryTime() == nil {
+		t.Errorf("unexpected status: %v", got)
+	}
+
+	// Errors carry the HTTP error code and a matching gRPC code
+	_, err = client.GetStatus(ctx, &solverapi.ChallengeId{ChallengeId: "ch_unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown challenge, got %v", err)
+	}
+	_, err = client.Submit(ctx, &solverapi.SolveRequest{ApiVersion: "v9", ChallengeId: "ch_v9"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an unsupported version, got %v", err)
+	}
+	_, err = client.Submit(ctx, &solverapi.SolveRequest{ApiVersion: "v2.1", ChallengeId: "ch_bad", Problem: "{"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for invalid problem JSON, got %v", err)
+	}
+}
+
+func TestGRPCSubmitQueueFull(t *testing.T) {
+	service, _ := newVersionedTestService(t, "v2.1")
+	service.config.SolverHMACKeyID = "test-key"
+	service.config.SolverMaxQueue = 1
+
+	conn := dialTestGRPC(t, service, grpc.WithUnaryInterceptor(SignGRPCCalls(service.hmacAuth, "test-key")))
+	client := solverapi.NewSolverClient(conn)
+
+	for _, id := range []string{"ch_q1", "ch_q2"} {
+		var header metadata.MD
+		_, err := client.Submit(context.Background(), &solverapi.SolveRequest{
+			ApiVersion:  "v2.1",
+			ChallengeId: id,
+			Problem:     `{"type":"text"}`,
+			OutputSpec:  `{"content_type":"text/plain"}`,
+			CallbackUrl: "http://127.0.0.1:9/callback/" + id,
+		}, grpc.Header(&header))
+		if id == "ch_q1" {
+			if err != nil {
+				t.Fatalf("Submit(%s) unexpected error: %v", id, err)
+			}
+			continue
+		}
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("expected ResourceExhausted at the limit, got %v", err)
+		}
+		if got := header.Get("retry-after"); len(got) != 1 || got[0] != "5" {
+			t.Errorf("expected retry-after 5, got %v", got)
+		}
+	}
+}
+
+func TestGRPCAuthInterceptor(t *testing.T) {
+	service, _ := newVersionedTestService(t, "v2.1")
+	service.config.SolverHMACKeyID = "test-key"
+	service.hmacAuth.AddSecret("other-key", "other-secret")
+	wrongSecret := auth.NewHMACAuth(map[string]string{"test-key": "wrong-secret"}, 0)
+
+	request := &solverapi.ChallengeId{ChallengeId: "ch_missing"}
+	fullMethod := solverapi.Solver_GetStatus_FullMethodName
+
+	tests := []struct {
+		name     string
+		opts     []grpc.DialOption
+		wantCode codes.Code
+	}{
+		{name: "unsigned", wantCode: codes.Unauthenticated},
+		{
+			name:     "wrong secret",
+			opts:     []grpc.DialOption{grpc.WithUnaryInterceptor(SignGRPCCalls(wrongSecret, "test-key"))},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "key not allowed",
+			opts:     []grpc.DialOption{grpc.WithUnaryInterceptor(SignGRPCCalls(service.hmacAuth, "other-key"))},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			// Authenticated, so the call reaches the service and the challenge is unknown
+			name:     "valid",
+			opts:     []grpc.DialOption{grpc.WithUnaryInterceptor(SignGRPCCalls(service.hmacAuth, "test-key"))},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := solverapi.NewSolverClient(dialTestGRPC(t, service, tt.opts...))
+			_, err := client.GetStatus(context.Background(), request)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("expected %v, got %v (%v)", tt.wantCode, got, err)
+			}
+		})
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		body, err := grpcSigningBody(request)
+		if err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+		header := service.hmacAuth.CreateAuthHeader("POST", fullMethod, body, "test-key", "fixed-nonce")
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", header)
+
+		client := solverapi.NewSolverClient(dialTestGRPC(t, service))
+		if _, err := client.GetStatus(ctx, request); status.Code(err) != codes.NotFound {
+			t.Fatalf("expected the first call to pass authentication, got %v", err)
+		}
+		if _, err := client.GetStatus(ctx, request); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected a replayed nonce to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("signature covers the request", func(t *testing.T) {
+		body, err := grpcSigningBody(&solverapi.ChallengeId{ChallengeId: "ch_other"})
+		if err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+		header := service.hmacAuth.CreateAuthHeader("POST", fullMethod, body, "test-key", "nonce-tampered")
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", header)
+
+		client := solverapi.NewSolverClient(dialTestGRPC(t, service))
+		if _, err := client.GetStatus(ctx, request); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected a signature over a different request to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("bridge stays unauthenticated", func(t *testing.T) {
+		interceptor := NewGRPCAuth(service.hmacAuth, nil, 0).UnaryInterceptor(solverapi.Solver_ServiceDesc.ServiceName)
+		called := false
+		_, err := interceptor(context.Background(), request, &grpc.UnaryServerInfo{FullMethod: "/solverbridge.SolverBridge/SubmitAnswer"},
+			func(context.Context, interface{}) (interface{}, error) { called = true; return nil, nil })
+		if err != nil || !called {
+			t.Errorf("expected other services to pass through, got %v (called=%v)", err, called)
+		}
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"net"
 
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/nonce"
+	solverapi "reverse-challenge-system/proto/solverapi"
 	solverbridge "reverse-challenge-system/proto/solverbridge"
 
 	"github.com/rs/zerolog/log"
@@ -26,7 +28,7 @@ func (g *grpcServer) SubmitAnswer(ctx context.Context, req *solverbridge.SubmitA
 	}
 
 	// Fetch pending challenge to obtain callback URL
-	ch, err := g.svc.db.GetChallenge(req.GetChallengeId())
+	ch, err := g.svc.db.GetChallenge(ctx, req.GetChallengeId())
 	if err != nil {
 		log.Error().Err(err).Str("challenge_id", req.GetChallengeId()).Msg("gRPC: failed to load pending challenge")
 		return &solverbridge.SubmitAnswerResponse{Accepted: false, Message: "database error"}, nil
@@ -41,7 +43,7 @@ func (g *grpcServer) SubmitAnswer(ctx context.Context, req *solverbridge.SubmitA
 	}
 
 	cb := &models.CallbackRequest{
-		APIVersion:   "v2.1",
+		APIVersion:   callbackAPIVersion(ch),
 		ChallengeID:  req.GetChallengeId(),
 		SolverJobID:  req.GetSolverJobId(),
 		Status:       status,
@@ -51,7 +53,7 @@ func (g *grpcServer) SubmitAnswer(ctx context.Context, req *solverbridge.SubmitA
 	}
 
 	// Send callback to Challenger using existing HTTP/HMAC path
-	statusCode, sendErr := g.svc.SendCallback(ch.CallbackURL, cb)
+	statusCode, sendErr := g.svc.SendCallback(ctx, ch.CallbackURL, cb, ch.CallbackRequestID)
 	if sendErr != nil {
 		log.Error().Err(sendErr).Str("challenge_id", req.GetChallengeId()).Msg("gRPC: callback send failed")
 		return &solverbridge.SubmitAnswerResponse{Accepted: false, Message: fmt.Sprintf("callback send failed: %v", sendErr)}, nil
@@ -59,23 +61,35 @@ func (g *grpcServer) SubmitAnswer(ctx context.Context, req *solverbridge.SubmitA
 
 	if statusCode >= 200 && statusCode < 300 {
 		// On success, remove the pending challenge
-		_ = g.svc.db.DeleteChallenge(req.GetChallengeId())
+		_ = g.svc.db.DeleteChallenge(ctx, req.GetChallengeId())
+		g.svc.recordOutcome(req.GetChallengeId(), "completed", ch.AttemptCount+1)
 		return &solverbridge.SubmitAnswerResponse{Accepted: true, Message: "callback accepted"}, nil
 	}
 
 	return &solverbridge.SubmitAnswerResponse{Accepted: false, Message: fmt.Sprintf("callback status %d", statusCode)}, nil
 }
 
-// StartGRPCBridge starts a gRPC server for external solvers to submit answers.
+// NewGRPCServer creates a gRPC server with the SolverBridge service and the Solver solve
+// API. Solve API calls must be signed with the key /solve accepts (SOLVER_HMAC_KEY_ID);
+// nonces are claimed in nonces. The bridge stays unauthenticated.
+func NewGRPCServer(s *Service, nonces nonce.Store) *grpc.Server {
+	grpcAuth := NewGRPCAuth(s.hmacAuth, nonces, s.config.GetNonceTTL(), s.config.SolverHMACKeyID)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(grpcAuth.UnaryInterceptor(solverapi.Solver_ServiceDesc.ServiceName)))
+	solverbridge.RegisterSolverBridgeServer(srv, &grpcServer{svc: s})
+	solverapi.RegisterSolverServer(srv, &grpcSolveServer{svc: s})
+	return srv
+}
+
+// StartGRPCBridge starts a gRPC server for external solvers to submit answers and for
+// challengers to submit challenges; see NewGRPCServer.
 // Example addr: ":9090". Returns a shutdown function.
-func StartGRPCBridge(s *Service, addr string) (func(context.Context) error, error) {
+func StartGRPCBridge(s *Service, addr string, nonces nonce.Store) (func(context.Context) error, error) {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
-	srv := grpc.NewServer()
-	solverbridge.RegisterSolverBridgeServer(srv, &grpcServer{svc: s})
+	srv := NewGRPCServer(s, nonces)
 
 	go func() {
 		log.Info().Str("addr", addr).Msg("Solver gRPC bridge listening")
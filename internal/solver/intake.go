@@ -0,0 +1,72 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validateProblem checks that a problem is a JSON object whose "type" is a non-empty string
+// accepted by SOLVER_PROBLEM_TYPES. Workers dispatch on the type, so a problem without one
+// would only fail after it was queued.
+func (s *Service) validateProblem(problem json.RawMessage) error {
+	if len(problem) == 0 {
+		return fmt.Errorf("problem is required")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(problem, &fields); err != nil || fields == nil {
+		return fmt.Errorf("problem must be a JSON object")
+	}
+
+	var problemType string
+	if raw, ok := fields["type"]; !ok {
+		return fmt.Errorf("problem.type is required")
+	} else if err := json.Unmarshal(raw, &problemType); err != nil || problemType == "" {
+		return fmt.Errorf("problem.type must be a non-empty string")
+	}
+
+	if !s.SupportsProblemType(problemType) {
+		return fmt.Errorf("unsupported problem type %q (supported: %s)", problemType, s.problemTypeList())
+	}
+	return nil
+}
+
+// validateOutputSpec checks an output spec against the minimal schema every solver relies on:
+// a JSON object with a non-empty "content_type" string and, when given, a "schema" object.
+func validateOutputSpec(outputSpec json.RawMessage) error {
+	if len(outputSpec) == 0 {
+		return fmt.Errorf("output_spec is required")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(outputSpec, &fields); err != nil || fields == nil {
+		return fmt.Errorf("output_spec must be a JSON object")
+	}
+
+	var contentType string
+	if raw, ok := fields["content_type"]; !ok {
+		return fmt.Errorf("output_spec.content_type is required")
+	} else if err := json.Unmarshal(raw, &contentType); err != nil || contentType == "" {
+		return fmt.Errorf("output_spec.content_type must be a non-empty string")
+	}
+
+	if raw, ok := fields["schema"]; ok {
+		var schema map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &schema); err != nil || schema == nil {
+			return fmt.Errorf("output_spec.schema must be a JSON object")
+		}
+	}
+	return nil
+}
+
+// problemTypeList returns the accepted problem types for error messages
+func (s *Service) problemTypeList() string {
+	types := make([]string, 0, len(s.problemTypes))
+	for t := range s.problemTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return strings.Join(types, ", ")
+}
@@ -2,38 +2,72 @@ package solver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
-	"strings"
+	"strconv"
 	"time"
 
+	"reverse-challenge-system/pkg/api"
 	"reverse-challenge-system/pkg/auth"
 	"reverse-challenge-system/pkg/config"
 	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/httpclient"
 	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/metrics"
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/netutil"
+	"reverse-challenge-system/pkg/sui"
+	"reverse-challenge-system/pkg/version"
 
 	"github.com/google/uuid"
 	"github.com/pattonkan/sui-go/suisigner"
 	"github.com/pattonkan/sui-go/suisigner/suicrypto"
 )
 
+// queueFullRetryAfter is the Retry-After sent with 503 QUEUE_FULL, one dispatcher poll interval
+const queueFullRetryAfter = 5 * time.Second
+
 type Service struct {
 	config     *config.Config
-	db         *db.SolverDB
+	db         db.SolverStore
+	readDB     db.SolverStore // Optional read-only store for stats
 	hmacAuth   *auth.HMACAuth
 	client     *http.Client
 	workerPool *WorkerPool
+	outcomes   *outcomeCache // Recently finished challenges for GET /solve/{challenge_id}
+
+	supportedAPIVersions map[string]bool // api_version values accepted by /solve
+	problemTypes         map[string]bool // Problem types accepted by /solve; nil accepts any
 }
 
-func NewService(cfg *config.Config, database *db.SolverDB, hmacAuth *auth.HMACAuth) *Service {
+func NewService(cfg *config.Config, database db.SolverStore, hmacAuth *auth.HMACAuth) *Service {
 	service := &Service{
 		config:   cfg,
 		db:       database,
 		hmacAuth: hmacAuth,
-		client:   &http.Client{Timeout: 30 * time.Second},
+		client:   httpclient.NewCallbackClient(cfg.HTTPClient, callbackPolicy(cfg)),
+		outcomes: newOutcomeCache(outcomeRetention),
+
+		supportedAPIVersions: make(map[string]bool),
+	}
+	for _, v := range cfg.SolverAPIVersions {
+		service.supportedAPIVersions[v] = true
+	}
+	if len(service.supportedAPIVersions) == 0 {
+		service.supportedAPIVersions[version.APIVersion] = true
+	}
+	for _, t := range cfg.SolverProblemTypes {
+		if t == "*" {
+			service.problemTypes = nil
+			break
+		}
+		if service.problemTypes == nil {
+			service.problemTypes = make(map[string]bool)
+		}
+		service.problemTypes[t] = true
 	}
 
 	// Initialize worker pool
@@ -42,6 +76,39 @@ func NewService(cfg *config.Config, database *db.SolverDB, hmacAuth *auth.HMACAu
 	return service
 }
 
+// SupportsAPIVersion reports whether /solve accepts requests sent with apiVersion.
+func (s *Service) SupportsAPIVersion(apiVersion string) bool {
+	return s.supportedAPIVersions[apiVersion]
+}
+
+// SupportsProblemType reports whether /solve accepts problems of problemType.
+func (s *Service) SupportsProblemType(problemType string) bool {
+	return s.problemTypes == nil || s.problemTypes[problemType]
+}
+
+// SetReadStore routes reporting queries to a read-only store, keeping writes on the primary.
+func (s *Service) SetReadStore(store db.SolverStore) {
+	s.readDB = store
+}
+
+// reader returns the store used for reporting queries, falling back to the primary.
+func (s *Service) reader() db.SolverStore {
+	if s.readDB != nil {
+		return s.readDB
+	}
+	return s.db
+}
+
+// SetSolver replaces the solver used by the worker pool. Must be called before Start.
+func (s *Service) SetSolver(solver Solver) {
+	s.workerPool.SetSolver(solver)
+}
+
+// SetSolverBackend routes solving to an external backend. Must be called before Start.
+func (s *Service) SetSolverBackend(backend SolverBackend) {
+	s.workerPool.SetBackend(backend)
+}
+
 func (s *Service) Start() {
 	startupLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Solver, logger.Startup)
 	startupLogger.Info().Msg("Starting solver service")
@@ -54,8 +121,30 @@ func (s *Service) Stop() {
 	s.workerPool.Stop()
 }
 
+// Drain lets in-flight challenges finish and deliver their callbacks before stopping the workers.
+// See WorkerPool.Drain.
+func (s *Service) Drain(ctx context.Context) error {
+	startupLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Solver, logger.Startup)
+	startupLogger.Info().Msg("Draining solver service")
+	return s.workerPool.Drain(ctx)
+}
+
+// RequestError is a solve or status request the service rejected. StatusCode and Code are
+// what the HTTP API reports; the gRPC API maps them to gRPC status codes.
+type RequestError struct {
+	StatusCode int           // HTTP status
+	Code       string        // Error code, e.g. QUEUE_FULL
+	Message    string        // Human-readable message
+	RetryAfter time.Duration // When to retry, set for QUEUE_FULL
+}
+
+// Error implements the error interface.
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
 func (s *Service) HandleSolve(w http.ResponseWriter, r *http.Request) {
-	requestID := r.Header.Get("X-Request-ID")
+	requestID := api.RequestID(r)
 
 	// Create request-specific logger that writes to file
 	requestLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Solver, logger.Request).
@@ -77,48 +166,96 @@ func (s *Service) HandleSolve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate request
-	if solveReq.APIVersion != "v2.1" {
-		s.writeError(w, http.StatusBadRequest, "UNSUPPORTED_VERSION",
-			"Unsupported API version", requestID)
+	response, err := s.AcceptSolve(r.Context(), &solveReq, requestID)
+	if err != nil {
+		s.writeRequestError(w, err, requestID)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AcceptSolve validates a solve request and queues its challenge, as POST /solve does.
+// A challenge that is already queued is acknowledged again without being stored twice.
+// Rejected requests return a *RequestError.
+func (s *Service) AcceptSolve(ctx context.Context, solveReq *models.SolveRequest, requestID string) (*models.SolveResponse, error) {
+	requestLogger := logger.NewCategoryLogger(s.config.LogLevel, logger.Solver, logger.Request).
+		With().
+		Str("request_id", requestID).
+		Logger()
+
+	// Validate request
+	if !s.SupportsAPIVersion(solveReq.APIVersion) {
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Code: "UNSUPPORTED_VERSION",
+			Message: "Unsupported API version"}
+	}
+
 	if solveReq.ChallengeID == "" {
-		s.writeError(w, http.StatusBadRequest, "MISSING_CHALLENGE_ID",
-			"Challenge ID is required", requestID)
-		return
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Code: "MISSING_CHALLENGE_ID",
+			Message: "Challenge ID is required"}
+	}
+
+	// Reject problems the worker could not dispatch before anything is stored
+	if err := s.validateProblem(solveReq.Problem); err != nil {
+		requestLogger.Warn().Err(err).Str("challenge_id", solveReq.ChallengeID).Msg("Invalid problem")
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Code: "INVALID_PROBLEM",
+			Message: err.Error()}
+	}
+	if err := validateOutputSpec(solveReq.OutputSpec); err != nil {
+		requestLogger.Warn().Err(err).Str("challenge_id", solveReq.ChallengeID).Msg("Invalid output spec")
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Code: "INVALID_OUTPUT_SPEC",
+			Message: err.Error()}
 	}
 
 	// Validate callback URL
-	if err := s.validateCallbackURL(solveReq.CallbackURL); err != nil {
+	if err := s.validateCallbackURL(ctx, solveReq.CallbackURL); err != nil {
 		requestLogger.Error().Err(err).Str("callback_url", solveReq.CallbackURL).Msg("Invalid callback URL")
-		s.writeError(w, http.StatusBadRequest, "INVALID_CALLBACK_URL",
-			"Invalid callback URL", requestID)
-		return
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Code: "INVALID_CALLBACK_URL",
+			Message: "Invalid callback URL"}
 	}
 
 	// Check if we've already seen this challenge
-	existingChallenge, err := s.db.GetChallenge(solveReq.ChallengeID)
+	existingChallenge, err := s.db.GetChallenge(ctx, solveReq.ChallengeID)
 	if err != nil {
 		requestLogger.Error().Err(err).Msg("Failed to check existing challenge")
-		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
-			"Database error", requestID)
-		return
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Code: "DB_ERROR",
+			Message: "Database error"}
+	}
+
+	// A challenge left expired is never processed again, so a re-sent copy replaces it
+	if existingChallenge != nil && existingChallenge.Status == "expired" {
+		if err := s.db.DeleteChallenge(ctx, existingChallenge.ID); err != nil {
+			requestLogger.Error().Err(err).Msg("Failed to remove expired challenge")
+			return nil, &RequestError{StatusCode: http.StatusInternalServerError, Code: "DB_ERROR",
+				Message: "Database error"}
+		}
+		existingChallenge = nil
 	}
 
 	if existingChallenge != nil {
 		// Already have this challenge, return existing job ID
-		jobID := fmt.Sprintf("solver_job_%s", solveReq.ChallengeID)
-		response := models.SolveResponse{
+		return &models.SolveResponse{
 			Message:     "Challenge already accepted",
-			SolverJobID: jobID,
-		}
+			SolverJobID: fmt.Sprintf("solver_job_%s", solveReq.ChallengeID),
+			APIVersion:  callbackAPIVersion(existingChallenge),
+		}, nil
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusAccepted)
-		json.NewEncoder(w).Encode(response)
-		return
+	// Shed load before persisting anything once the queue is full
+	if limit := s.config.SolverMaxQueue; limit > 0 {
+		depth, err := s.db.CountPendingChallenges(ctx)
+		if err != nil {
+			requestLogger.Error().Err(err).Msg("Failed to count pending challenges")
+			return nil, &RequestError{StatusCode: http.StatusInternalServerError, Code: "DB_ERROR",
+				Message: "Database error"}
+		}
+		if depth >= limit {
+			requestLogger.Warn().Int("queue_depth", depth).Int("max_queue", limit).Msg("Queue full, rejecting challenge")
+			return nil, &RequestError{StatusCode: http.StatusServiceUnavailable, Code: "QUEUE_FULL",
+				Message: "Solver queue is full", RetryAfter: queueFullRetryAfter}
+		}
 	}
 
 	// Create pending challenge
@@ -131,34 +268,48 @@ func (s *Service) HandleSolve(w http.ResponseWriter, r *http.Request) {
 		Status:        "pending",
 		AttemptCount:  0,
 		NextRetryTime: time.Now(),
+		TimeoutMs:     solveReq.Constraints.TimeoutMs,
+		DeadlineTs:    solveReq.Constraints.DeadlineTs,
+		Priority:      solveReq.Priority,
+
+		CallbackRequestID: uuid.New().String(),
+		APIVersion:        solveReq.APIVersion,
 	}
 
 	// Save to database
-	if err := s.db.SaveChallenge(challenge); err != nil {
+	if err := s.db.SaveChallenge(ctx, challenge); err != nil {
 		requestLogger.Error().Err(err).Msg("Failed to save challenge")
-		s.writeError(w, http.StatusInternalServerError, "DB_ERROR",
-			"Failed to save challenge", requestID)
-		return
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Code: "DB_ERROR",
+			Message: "Failed to save challenge"}
 	}
 
+	metrics.ChallengesReceived.Inc()
+
 	requestLogger.Info().
 		Str("challenge_id", solveReq.ChallengeID).
 		Str("callback_url", solveReq.CallbackURL).
 		Msg("Challenge accepted and queued for processing")
 
-	// Return success response
-	jobID := fmt.Sprintf("solver_job_%s", solveReq.ChallengeID)
-	response := models.SolveResponse{
+	return &models.SolveResponse{
 		Message:     "Challenge accepted",
-		SolverJobID: jobID,
-	}
+		SolverJobID: fmt.Sprintf("solver_job_%s", solveReq.ChallengeID),
+		APIVersion:  challenge.APIVersion,
+	}, nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(response)
+// callbackAPIVersion returns the API version negotiated for challenge, which its callback echoes.
+// Challenges stored before the version was recorded fall back to the current version.
+func callbackAPIVersion(challenge *models.PendingChallenge) string {
+	if challenge.APIVersion == "" {
+		return version.APIVersion
+	}
+	return challenge.APIVersion
 }
 
-func (s *Service) SendCallback(callbackURL string, callbackReq *models.CallbackRequest) (int, error) {
+// SendCallback delivers a signed callback to the challenger. requestID is sent as X-Request-ID;
+// pass the challenge's CallbackRequestID so every attempt for a challenge is deduplicated by the
+// challenger. An empty requestID sends a fresh one. Canceling ctx aborts an in-flight send.
+func (s *Service) SendCallback(ctx context.Context, callbackURL string, callbackReq *models.CallbackRequest, requestID string) (int, error) {
 	logger := logger.WithChallengeID(callbackReq.ChallengeID)
 
 	// Marshal request body
@@ -180,14 +331,17 @@ func (s *Service) SendCallback(callbackURL string, callbackReq *models.CallbackR
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequest("POST", callbackURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", callbackURL, bytes.NewReader(body))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("X-Request-ID", uuid.New().String())
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	req.Header.Set("X-Request-ID", requestID)
 	signer, err := suisigner.NewSignerWithMnemonic(s.config.SUI.SolverMnemonic, suicrypto.KeySchemeFlagEd25519)
 	if err != nil {
 		panic(err)
@@ -195,14 +349,27 @@ func (s *Service) SendCallback(callbackURL string, callbackReq *models.CallbackR
 	logger.Info().Str("solver address", signer.Address.String())
 	req.Header.Set("X-Solver-Address", signer.Address.String())
 
+	// Sign the body with the key behind X-Solver-Address so the challenger can attribute the answer
+	signature, pubkey, err := sui.SignCallback(signer, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set(sui.CallbackSignatureHeader, signature)
+	req.Header.Set(sui.CallbackPubkeyHeader, pubkey)
+
 	// Send request
 	logger.Info().Str("callback_url", callbackURL).Msg("Sending callback")
+	start := time.Now()
 	resp, err := s.client.Do(req)
+	metrics.CallbackLatency.Observe(time.Since(start).Seconds())
 	if err != nil {
+		metrics.CallbacksSent.WithLabelValues(metrics.StatusCodeLabel(0)).Inc()
 		return 0, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	metrics.CallbacksSent.WithLabelValues(metrics.StatusCodeLabel(resp.StatusCode)).Inc()
+
 	logger.Info().
 		Int("status_code", resp.StatusCode).
 		Msg("Callback response received")
@@ -210,59 +377,32 @@ func (s *Service) SendCallback(callbackURL string, callbackReq *models.CallbackR
 	return resp.StatusCode, nil
 }
 
-func (s *Service) validateCallbackURL(callbackURL string) error {
-	// Basic validation - ensure it's not empty
-	if callbackURL == "" {
-		return fmt.Errorf("callback URL cannot be empty")
-	}
-
-	if len(callbackURL) > 2048 {
-		return fmt.Errorf("callback URL too long")
-	}
-
-	// Parse URL for validation
-	u, err := url.Parse(callbackURL)
-	if err != nil {
-		return fmt.Errorf("invalid callback URL: %w", err)
-	}
+// validateCallbackURL checks a callback URL against CALLBACK_ALLOWED_HOSTS and the SSRF guard.
+// The callback client enforces the same policy again on every connection it makes.
+func (s *Service) validateCallbackURL(ctx context.Context, callbackURL string) error {
+	return callbackPolicy(s.config).ValidateCallbackURL(ctx, callbackURL)
+}
 
-	// If ngrok is enabled, require HTTPS
-	if s.config.UseNgrok {
-		if u.Scheme != "https" {
-			return fmt.Errorf("callback URL must use HTTPS when USE_NGROK=true")
-		}
-		// Allow ngrok domains
-		if strings.Contains(u.Host, "ngrok.io") || strings.Contains(u.Host, "ngrok-free.app") {
-			return nil
-		}
-	} else {
-		// For local development, allow HTTP on localhost/127.0.0.1
-		if u.Scheme == "http" {
-			if strings.Contains(u.Host, "localhost") || strings.Contains(u.Host, "127.0.0.1") {
-				return nil
-			}
-			return fmt.Errorf("HTTP callback URLs only allowed for localhost when USE_NGROK=false")
-		}
-		if u.Scheme == "https" {
-			// HTTPS is always allowed
-			return nil
-		}
-		return fmt.Errorf("callback URL must use HTTP (localhost only) or HTTPS")
+// callbackPolicy returns the callback URL policy configured by CALLBACK_ALLOWED_HOSTS and USE_NGROK.
+func callbackPolicy(cfg *config.Config) netutil.CallbackPolicy {
+	return netutil.CallbackPolicy{
+		AllowedHosts:  cfg.CallbackAllowedHosts,
+		AllowlistOnly: cfg.UseNgrok,
 	}
+}
 
-	// Basic whitelist check
-	allowedHosts := []string{
-		"localhost",
-		"127.0.0.1",
+// writeRequestError writes err as an error response, using its status and code when it
+// is a *RequestError and 500 INTERNAL_ERROR otherwise
+func (s *Service) writeRequestError(w http.ResponseWriter, err error, requestID string) {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		s.writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal error", requestID)
+		return
 	}
-
-	for _, host := range allowedHosts {
-		if strings.Contains(u.Host, host) {
-			return nil
-		}
+	if reqErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(reqErr.RetryAfter.Seconds())))
 	}
-
-	return fmt.Errorf("callback URL host not in whitelist: %s", u.Host)
+	s.writeError(w, reqErr.StatusCode, reqErr.Code, reqErr.Message, requestID)
 }
 
 func (s *Service) writeError(w http.ResponseWriter, statusCode int, code, message, requestID string) {
@@ -279,9 +419,9 @@ func (s *Service) writeError(w http.ResponseWriter, statusCode int, code, messag
 	json.NewEncoder(w).Encode(errorResp)
 }
 
-func (s *Service) GetStats() map[string]interface{} {
+func (s *Service) GetStats(ctx context.Context) map[string]interface{} {
 	// Get some basic stats from the database
-	challenges, _ := s.db.GetPendingChallenges(1000) // Get up to 1000 for stats
+	challenges, _ := s.reader().GetPendingChallenges(ctx, 1000) // Get up to 1000 for stats
 
 	statusCounts := make(map[string]int)
 	for _, challenge := range challenges {
@@ -0,0 +1,339 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/sui"
+
+	suigo "github.com/pattonkan/sui-go/sui"
+)
+
+// newVersionedTestService creates a solver service that accepts apiVersions and
+// delivers callbacks to the local test server
+func newVersionedTestService(t *testing.T, apiVersions ...string) (*Service, *db.SolverDB) {
+	t.Helper()
+
+	database, err := db.NewSolverDB(filepath.Join(t.TempDir(), "solver.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	cfg := &config.Config{
+		LogLevel:             "info",
+		SolverWorkerCount:    1,
+		ChalHMACKeyID:        "test-key",
+		SolverAPIVersions:    apiVersions,
+		CallbackAllowedHosts: []string{"127.0.0.1"},
+	}
+	cfg.SUI.SolverMnemonic = testSolverMnemonic
+
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 0)
+	return NewService(cfg, database, hmacAuth), database
+}
+
+// postSolve sends a solve request for challengeID with apiVersion to HandleSolve
+func postSolve(t *testing.T, service *Service, challengeID, apiVersion, callbackURL string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(models.SolveRequest{
+		ChallengeID: challengeID,
+		Problem:     json.RawMessage(`{"type":"text"}`),
+		OutputSpec:  json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL: callbackURL,
+		APIVersion:  apiVersion,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal solve request: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	service.HandleSolve(w, httptest.NewRequest("POST", "/solve", bytes.NewReader(body)))
+	return w
+}
+
+func TestHandleSolveAPIVersions(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "current version", apiVersion: "v2.1", wantStatus: http.StatusAccepted},
+		{name: "newer version", apiVersion: "v2.2", wantStatus: http.StatusAccepted},
+		{name: "unsupported version", apiVersion: "v9.0", wantStatus: http.StatusBadRequest, wantCode: "UNSUPPORTED_VERSION"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, database := newVersionedTestService(t, "v2.1", "v2.2")
+
+			w := postSolve(t, service, "ch_version", tt.apiVersion, "http://127.0.0.1:9/callback")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			if tt.wantCode != "" {
+				var errorResp models.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+					t.Fatalf("failed to decode error response: %v", err)
+				}
+				if errorResp.Error.Code != tt.wantCode {
+					t.Errorf("expected error code %s, got %s", tt.wantCode, errorResp.Error.Code)
+				}
+				return
+			}
+
+			var resp models.SolveResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode solve response: %v", err)
+			}
+			if resp.APIVersion != tt.apiVersion {
+				t.Errorf("expected negotiated version %s, got %s", tt.apiVersion, resp.APIVersion)
+			}
+
+			challenge, err := database.GetChallenge(context.Background(), "ch_version")
+			if err != nil || challenge == nil {
+				t.Fatalf("expected the challenge to be stored, got %v, %v", challenge, err)
+			}
+			if challenge.APIVersion != tt.apiVersion {
+				t.Errorf("expected stored version %s, got %s", tt.apiVersion, challenge.APIVersion)
+			}
+		})
+	}
+}
+
+func TestHandleSolveDefaultsToCurrentVersion(t *testing.T) {
+	service, _ := newVersionedTestService(t)
+
+	if w := postSolve(t, service, "ch_current", "v2.1", "http://127.0.0.1:9/callback"); w.Code != http.StatusAccepted {
+		t.Errorf("expected the current version to be accepted, got %d", w.Code)
+	}
+	if w := postSolve(t, service, "ch_newer", "v2.2", "http://127.0.0.1:9/callback"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected an unconfigured version to be rejected, got %d", w.Code)
+	}
+}
+
+func TestCallbackEchoesNegotiatedAPIVersion(t *testing.T) {
+	callbacks := make(chan models.CallbackRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq
+	}))
+	defer server.Close()
+
+	service, database := newVersionedTestService(t, "v2.1", "v2.2")
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		return "ok", nil, nil
+	}))
+
+	if w := postSolve(t, service, "ch_echo", "v2.2", server.URL+"/callback/ch_echo"); w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	defer pool.Stop()
+
+	challenge, err := database.GetChallenge(context.Background(), "ch_echo")
+	if err != nil {
+		t.Fatalf("failed to load challenge: %v", err)
+	}
+	pool.jobQueue <- challenge
+
+	select {
+	case callbackReq := <-callbacks:
+		if callbackReq.APIVersion != "v2.2" {
+			t.Errorf("expected the callback to echo v2.2, got %s", callbackReq.APIVersion)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the callback")
+	}
+}
+
+func TestSendCallbackSignsBody(t *testing.T) {
+	verified := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		address, err := suigo.AddressFromHex(r.Header.Get("X-Solver-Address"))
+		if err == nil {
+			err = sui.VerifyCallbackSignature(body, r.Header.Get(sui.CallbackSignatureHeader), r.Header.Get(sui.CallbackPubkeyHeader), address)
+		}
+		w.WriteHeader(http.StatusOK)
+		verified <- err
+	}))
+	defer server.Close()
+
+	service, _ := newVersionedTestService(t, "v2.1")
+	callbackReq := &models.CallbackRequest{APIVersion: "v2.1", ChallengeID: "ch_signed", Status: "success", Answer: "42"}
+	if _, err := service.SendCallback(context.Background(), server.URL+"/callback/ch_signed", callbackReq, ""); err != nil {
+		t.Fatalf("SendCallback() unexpected error: %v", err)
+	}
+
+	if err := <-verified; err != nil {
+		t.Errorf("expected the callback signature to verify against X-Solver-Address, got %v", err)
+	}
+}
+
+func TestHandleSolveValidatesProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		problem    string
+		outputSpec string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "valid", problem: `{"type":"math","operation":"add","a":1,"b":2}`, outputSpec: `{"content_type":"text/plain","schema":{"type":"string"}}`, wantStatus: http.StatusAccepted},
+		{name: "missing type", problem: `{"operation":"add"}`, outputSpec: `{"content_type":"text/plain"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_PROBLEM"},
+		{name: "unknown type", problem: `{"type":"chess"}`, outputSpec: `{"content_type":"text/plain"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_PROBLEM"},
+		{name: "type not a string", problem: `{"type":7}`, outputSpec: `{"content_type":"text/plain"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_PROBLEM"},
+		{name: "problem not an object", problem: `["text"]`, outputSpec: `{"content_type":"text/plain"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_PROBLEM"},
+		{name: "missing problem", outputSpec: `{"content_type":"text/plain"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_PROBLEM"},
+		{name: "missing content type", problem: `{"type":"text"}`, outputSpec: `{"schema":{"type":"string"}}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_OUTPUT_SPEC"},
+		{name: "schema not an object", problem: `{"type":"text"}`, outputSpec: `{"content_type":"text/plain","schema":"string"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_OUTPUT_SPEC"},
+		{name: "missing output spec", problem: `{"type":"text"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_OUTPUT_SPEC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, database := newVersionedTestService(t, "v2.1")
+			service.problemTypes = map[string]bool{"captcha": true, "math": true, "text": true}
+
+			solveReq := models.SolveRequest{
+				APIVersion:  "v2.1",
+				ChallengeID: "ch_intake",
+				CallbackURL: "http://127.0.0.1:9/callback/ch_intake",
+			}
+			if tt.problem != "" {
+				solveReq.Problem = json.RawMessage(tt.problem)
+			}
+			if tt.outputSpec != "" {
+				solveReq.OutputSpec = json.RawMessage(tt.outputSpec)
+			}
+			body, err := json.Marshal(solveReq)
+			if err != nil {
+				t.Fatalf("failed to marshal solve request: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			service.HandleSolve(w, httptest.NewRequest("POST", "/solve", bytes.NewReader(body)))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			stored, err := database.GetChallenge(context.Background(), "ch_intake")
+			if err != nil {
+				t.Fatalf("failed to look up challenge: %v", err)
+			}
+			if tt.wantCode == "" {
+				if stored == nil {
+					t.Error("expected the valid challenge to be stored")
+				}
+				return
+			}
+
+			var errorResp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if errorResp.Error.Code != tt.wantCode {
+				t.Errorf("expected error code %s, got %s", tt.wantCode, errorResp.Error.Code)
+			}
+			if stored != nil {
+				t.Error("expected the rejected challenge not to be stored")
+			}
+		})
+	}
+}
+
+func TestSupportsProblemTypeWildcard(t *testing.T) {
+	service, _ := newVersionedTestService(t, "v2.1")
+	service.config.SolverProblemTypes = []string{"math", "*"}
+	service = NewService(service.config, service.db, service.hmacAuth)
+
+	if !service.SupportsProblemType("chess") {
+		t.Error(`expected "*" to accept any problem type`)
+	}
+}
+
+func TestHandleSolveQueueFull(t *testing.T) {
+	service, database := newVersionedTestService(t, "v2.1")
+	service.config.SolverMaxQueue = 2
+
+	for _, id := range []string{"ch_q1", "ch_q2"} {
+		if w := postSolve(t, service, id, "v2.1", "http://127.0.0.1:9/callback/"+id); w.Code != http.StatusAccepted {
+			t.Fatalf("expected %s below the limit to be accepted, got %d: %s", id, w.Code, w.Body.String())
+		}
+	}
+
+	w := postSolve(t, service, "ch_q3", "v2.1", "http://127.0.0.1:9/callback/ch_q3")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 at the limit, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("expected Retry-After 5, got %q", got)
+	}
+	var errorResp models.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if errorResp.Error.Code != "QUEUE_FULL" {
+		t.Errorf("expected error code QUEUE_FULL, got %s", errorResp.Error.Code)
+	}
+	if challenge, err := database.GetChallenge(context.Background(), "ch_q3"); err != nil || challenge != nil {
+		t.Errorf("expected the rejected challenge not to be stored, got %v, %v", challenge, err)
+	}
+
+	// A resend of an accepted challenge is still acknowledged
+	if w := postSolve(t, service, "ch_q1", "v2.1", "http://127.0.0.1:9/callback/ch_q1"); w.Code != http.StatusAccepted {
+		t.Errorf("expected a resend to be accepted with a full queue, got %d", w.Code)
+	}
+}
+
+func TestHandleSolveReacceptsExpiredChallenge(t *testing.T) {
+	service, database := newVersionedTestService(t, "v2.1")
+	ctx := context.Background()
+
+	if w := postSolve(t, service, "ch_resent", "v2.1", "http://127.0.0.1:9/callback/ch_resent"); w.Code != http.StatusAccepted {
+		t.Fatalf("expected the challenge to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := database.UpdateChallengeStatus(ctx, "ch_resent", "expired", 1, time.Now()); err != nil {
+		t.Fatalf("failed to expire challenge: %v", err)
+	}
+
+	w := postSolve(t, service, "ch_resent", "v2.1", "http://127.0.0.1:9/callback/ch_resent")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected the re-sent challenge to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.SolveResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Message != "Challenge accepted" {
+		t.Errorf("expected the re-sent challenge to be queued again, got %q", resp.Message)
+	}
+
+	stored, err := database.GetChallenge(ctx, "ch_resent")
+	if err != nil || stored == nil {
+		t.Fatalf("failed to load challenge: %v", err)
+	}
+	if stored.Status != "pending" || stored.AttemptCount != 0 {
+		t.Errorf("expected a fresh pending challenge, got status %q after %d attempts", stored.Status, stored.AttemptCount)
+	}
+}
@@ -0,0 +1,114 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"reverse-challenge-system/pkg/api"
+	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/gorilla/mux"
+)
+
+// outcomeRetention is how long GET /solve/{challenge_id} keeps reporting a challenge
+// after its row has left pending_challenges.
+const outcomeRetention = 10 * time.Minute
+
+// outcome is the final status of a challenge that is no longer in pending_challenges
+type outcome struct {
+	status       string
+	attemptCount int
+	at           time.Time
+}
+
+// outcomeCache remembers recently finished challenges. Successful challenges are deleted
+// and failed ones are dead-lettered, so GetChallenge alone cannot tell them from unknown IDs.
+type outcomeCache struct {
+	mu        sync.Mutex
+	retention time.Duration
+	entries   map[string]outcome
+}
+
+func newOutcomeCache(retention time.Duration) *outcomeCache {
+	return &outcomeCache{retention: retention, entries: make(map[string]outcome)}
+}
+
+// record stores a final status for id, dropping entries older than the retention window
+func (c *outcomeCache) record(id, status string, attemptCount int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.Sub(entry.at) > c.retention {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[id] = outcome{status: status, attemptCount: attemptCount, at: now}
+}
+
+// lookup returns the final status recorded for id within the retention window
+func (c *outcomeCache) lookup(id string) (outcome, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[id]
+	if !ok || time.Since(entry.at) > c.retention {
+		return outcome{}, false
+	}
+	return entry, true
+}
+
+// recordOutcome remembers that challengeID left the queue with status
+func (s *Service) recordOutcome(challengeID, status string, attemptCount int) {
+	s.outcomes.record(challengeID, status, attemptCount)
+}
+
+// HandleGetStatus reports a challenge's processing status: pending, processing, or failed
+// while it is queued, and completed or failed for a short while after it leaves the queue.
+func (s *Service) HandleGetStatus(w http.ResponseWriter, r *http.Request) {
+	requestID := api.RequestID(r)
+	challengeID := mux.Vars(r)["challenge_id"]
+
+	resp, err := s.ChallengeStatus(r.Context(), challengeID, requestID)
+	if err != nil {
+		s.writeRequestError(w, err, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ChallengeStatus looks up a challenge's processing status, as GET /solve/{challenge_id}
+// does. Unknown challenges and lookup failures return a *RequestError.
+func (s *Service) ChallengeStatus(ctx context.Context, challengeID, requestID string) (*models.ChallengeStatusResponse, error) {
+	challenge, err := s.db.GetChallenge(ctx, challengeID)
+	if err != nil {
+		lg := logger.WithRequestID(requestID)
+		lg.Error().Err(err).Str("challenge_id", challengeID).Msg("Failed to load challenge status")
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Code: "INTERNAL_ERROR",
+			Message: "Failed to load challenge"}
+	}
+
+	resp := &models.ChallengeStatusResponse{ChallengeID: challengeID}
+	if challenge != nil {
+		resp.Status = challenge.Status
+		resp.AttemptCount = challenge.AttemptCount
+		resp.NextRetryTime = &challenge.NextRetryTime
+		return resp, nil
+	}
+
+	finished, ok := s.outcomes.lookup(challengeID)
+	if !ok {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Code: "CHALLENGE_NOT_FOUND",
+			Message: "Challenge not found"}
+	}
+	resp.Status = finished.status
+	resp.AttemptCount = finished.attemptCount
+	return resp, nil
+}
@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/gorilla/mux"
+)
+
+// getStatus calls HandleGetStatus for challengeID
+func getStatus(service *Service, challengeID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/solve/"+challengeID, nil)
+	req = mux.SetURLVars(req, map[string]string{"challenge_id": challengeID})
+	w := httptest.NewRecorder()
+	service.HandleGetStatus(w, req)
+	return w
+}
+
+func TestHandleGetStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		claim       bool // Claim the challenge for a worker before querying
+		wantStatus  string
+		wantAttempt int
+	}{
+		{name: "pending", wantStatus: "pending", wantAttempt: 1},
+		{name: "processing", claim: true, wantStatus: "processing", wantAttempt: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, database := newTestService(t)
+			ctx := context.Background()
+
+			nextRetryTime := time.Now().Add(time.Minute).Truncate(time.Second)
+			challenge := &models.PendingChallenge{
+				ID:            "ch_status",
+				Problem:       json.RawMessage(`{"type":"text"}`),
+				OutputSpec:    json.RawMessage(`{}`),
+				CallbackURL:   "http://127.0.0.1:9/callback/ch_status",
+				ReceivedAt:    time.Now(),
+				Status:        "pending",
+				AttemptCount:  1,
+				NextRetryTime: nextRetryTime,
+			}
+			if err := database.SaveChallenge(ctx, challenge); err != nil {
+				t.Fatalf("failed to save challenge: %v", err)
+			}
+			if tt.claim {
+				// Claiming needs the challenge to be due
+				if err := database.UpdateChallengeStatus(ctx, challenge.ID, "pending", 1, time.Now().Add(-time.Second)); err != nil {
+					t.Fatalf("failed to update challenge: %v", err)
+				}
+				nextRetryTime = time.Now().Add(claimLease).Truncate(time.Second)
+				if claimed, err := database.ClaimChallenge(ctx, challenge.ID, nextRetryTime); err != nil || !claimed {
+					t.Fatalf("failed to claim challenge: %v, %v", claimed, err)
+				}
+			}
+
+			w := getStatus(service, challenge.ID)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp models.ChallengeStatusResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ChallengeID != challenge.ID || resp.Status != tt.wantStatus || resp.AttemptCount != tt.wantAttempt {
+				t.Errorf("unexpected status response %+v", resp)
+			}
+			if resp.NextRetryTime == nil || !resp.NextRetryTime.Equal(nextRetryTime) {
+				t.Errorf("expected next retry time %v, got %v", nextRetryTime, resp.NextRetryTime)
+			}
+		})
+	}
+}
+
+func TestHandleGetStatusUnknown(t *testing.T) {
+	service, _ := newTestService(t)
+
+	w := getStatus(service, "ch_missing")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+	var errorResp models.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if errorResp.Error.Code != "CHALLENGE_NOT_FOUND" {
+		t.Errorf("expected error code CHALLENGE_NOT_FOUND, got %s", errorResp.Error.Code)
+	}
+}
+
+func TestHandleGetStatusCompleted(t *testing.T) {
+	service, _ := newTestService(t)
+	service.recordOutcome("ch_done", "completed", 2)
+
+	w := getStatus(service, "ch_done")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.ChallengeStatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Status != "completed" || resp.AttemptCount != 2 || resp.NextRetryTime != nil {
+		t.Errorf("unexpected status response %+v", resp)
+	}
+}
+
+func TestOutcomeCacheExpires(t *testing.T) {
+	cache := newOutcomeCache(time.Millisecond)
+	cache.record("ch_old", "completed", 1)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.lookup("ch_old"); ok {
+		t.Error("expected the outcome to expire after the retention window")
+	}
+
+	// Recording prunes expired entries
+	cache.record("ch_new", "failed", 3)
+	if _, ok := cache.entries["ch_old"]; ok {
+		t.Error("expected the expired entry to be pruned")
+	}
+	if got, ok := cache.lookup("ch_new"); !ok || got.status != "failed" {
+		t.Errorf("expected the new outcome to be found, got %+v, %v", got, ok)
+	}
+}
@@ -1,20 +1,29 @@
 package solver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"math/rand"
+	"net/http"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
+	"reverse-challenge-system/pkg/backoff"
+	"reverse-challenge-system/pkg/config"
 	"reverse-challenge-system/pkg/db"
 	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/metrics"
 	"reverse-challenge-system/pkg/models"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
+// Default callback retry policy, used when the corresponding config values are unset
 const (
 	MaxRetryAttempts = 6
 	BaseDelay        = 500 * time.Millisecond
@@ -23,24 +32,130 @@ const (
 	JitterMax        = 1.15
 )
 
+// claimLease is how long a dispatched challenge stays claimed by its worker. A worker that dies
+// mid-challenge releases it to the dispatcher once the lease passes.
+const claimLease = 5 * time.Minute
+
+// RetryPolicy controls how callbacks are retried after transient failures.
+type RetryPolicy struct {
+	MaxAttempts int           // Total delivery attempts, including the first
+	BaseDelay   time.Duration // Delay before the first retry; doubles each attempt
+	MaxDelay    time.Duration // Upper bound on the backoff delay
+	JitterMin   float64       // Lower multiplier applied to each delay
+	JitterMax   float64       // Upper multiplier applied to each delay
+}
+
+// DefaultRetryPolicy returns the built-in retry policy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: MaxRetryAttempts,
+		BaseDelay:   BaseDelay,
+		MaxDelay:    MaxDelay,
+		JitterMin:   JitterMin,
+		JitterMax:   JitterMax,
+	}
+}
+
+// retryPolicyFromConfig builds the policy from SOLVER_* settings, keeping defaults for unset
+// durations and attempts. Jitter is taken as-is since 0 legitimately disables it.
+func retryPolicyFromConfig(cfg *config.Config) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if cfg.SolverMaxRetryAttempts > 0 {
+		policy.MaxAttempts = cfg.SolverMaxRetryAttempts
+	}
+	if cfg.SolverBaseDelayMs > 0 {
+		policy.BaseDelay = time.Duration(cfg.SolverBaseDelayMs) * time.Millisecond
+	}
+	if cfg.SolverMaxDelayMs > 0 {
+		policy.MaxDelay = time.Duration(cfg.SolverMaxDelayMs) * time.Millisecond
+	}
+	policy.JitterMin = 1 - float64(cfg.SolverJitterPct)/100
+	policy.JitterMax = 1 + float64(cfg.SolverJitterPct)/100
+	return policy
+}
+
+// errSolverPanic marks a solve attempt that was aborted by a panic in the solver
+var errSolverPanic = errors.New("solver panicked")
+
+// errSolverTimeout marks a solve attempt that exceeded the challenge timeout or deadline
+var errSolverTimeout = errors.New("solver timed out")
+
+// errCallbackPastDeadline is returned when the next callback retry would land after the challenge
+// deadline, where the challenger rejects it as expired.
+var errCallbackPastDeadline = errors.New("callback retry would miss the deadline")
+
+// Solver computes the answer and solver metadata for a pending challenge.
+// The context expires when the challenge timeout or deadline is reached.
+type Solver interface {
+	Solve(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error)
+}
+
+// SolverFunc adapts an ordinary function to the Solver interface.
+type SolverFunc func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error)
+
+func (f SolverFunc) Solve(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+	return f(ctx, challenge)
+}
+
 type WorkerPool struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
 	workers    int
-	db         *db.SolverDB
+	db         db.SolverStore
 	service    *Service
+	solver     Solver
+	retry      RetryPolicy
 	jobQueue   chan *models.PendingChallenge
 	quit       chan struct{}
 	workerQuit []chan struct{}
+	typeSlots  map[string]chan struct{} // Per problem type semaphores from SOLVER_TYPE_LIMITS
+
+	dispatcherDone chan struct{}  // Closed when the dispatcher goroutine returns
+	workersDone    sync.WaitGroup // Running worker goroutines
+	stopDispatch   sync.Once
+	stopWorkers    sync.Once
 }
 
-func NewWorkerPool(workers int, database *db.SolverDB, service *Service) *WorkerPool {
-	return &WorkerPool{
+func NewWorkerPool(workers int, database db.SolverStore, service *Service) *WorkerPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	wp := &WorkerPool{
+		ctx:        ctx,
+		cancel:     cancel,
 		workers:    workers,
 		db:         database,
 		service:    service,
+		retry:      retryPolicyFromConfig(service.config),
 		jobQueue:   make(chan *models.PendingChallenge, workers*2),
 		quit:       make(chan struct{}),
 		workerQuit: make([]chan struct{}, workers),
+		typeSlots:  make(map[string]chan struct{}),
+	}
+	for challengeType, limit := range service.config.SolverTypeLimits {
+		wp.typeSlots[challengeType] = make(chan struct{}, limit)
 	}
+	wp.solver = SolverFunc(wp.solveChallenge)
+
+	// Prefer the external backend when configured; otherwise keep the mock solver
+	if url := service.config.SolverBackendURL; url != "" {
+		wp.solver = backendSolver(NewHTTPSolverBackend(url, service.config.GetSolverBackendTimeout()))
+	}
+
+	return wp
+}
+
+// SetSolver replaces the built-in mock solver. Must be called before Start.
+func (wp *WorkerPool) SetSolver(solver Solver) {
+	wp.solver = solver
+}
+
+// SetRetryPolicy overrides the callback retry policy. Must be called before Start.
+func (wp *WorkerPool) SetRetryPolicy(policy RetryPolicy) {
+	wp.retry = policy
+}
+
+// SetBackend routes solving to an external backend. Must be called before Start.
+func (wp *WorkerPool) SetBackend(backend SolverBackend) {
+	wp.solver = backendSolver(backend)
 }
 
 func (wp *WorkerPool) Start() {
@@ -50,27 +165,100 @@ func (wp *WorkerPool) Start() {
 	// Start workers
 	for i := 0; i < wp.workers; i++ {
 		wp.workerQuit[i] = make(chan struct{})
+		wp.workersDone.Add(1)
 		go wp.worker(i, wp.workerQuit[i])
 	}
 
 	// Start job dispatcher
+	wp.dispatcherDone = make(chan struct{})
 	go wp.dispatcher()
 }
 
+// Stop shuts the pool down immediately, aborting challenges that are mid-solve.
+// Use Drain to let them finish first. Safe to call after Drain.
 func (wp *WorkerPool) Stop() {
 	workerLogger := logger.NewCategoryLogger(wp.service.config.LogLevel, logger.Solver, logger.Worker)
 	workerLogger.Info().Msg("Stopping worker pool")
 
-	// Signal stop to dispatcher
-	close(wp.quit)
+	// Signal stop to dispatcher and abort pending database calls
+	wp.stopDispatcher()
+	wp.cancel()
 
 	// Signal stop to all workers
-	for i := 0; i < wp.workers; i++ {
-		close(wp.workerQuit[i])
+	wp.stopAllWorkers()
+}
+
+// Drain stops dispatching new work and waits for every worker to finish the challenge it is on,
+// including its callback retries, then stops the pool. Challenges still waiting in the queue are
+// released for the next start. If ctx ends first, the remaining work is aborted as with Stop and
+// ctx.Err() is returned.
+func (wp *WorkerPool) Drain(ctx context.Context) error {
+	defer wp.Stop()
+
+	workerLogger := logger.NewCategoryLogger(wp.service.config.LogLevel, logger.Solver, logger.Worker)
+	if wp.dispatcherDone == nil {
+		return nil // Never started
+	}
+	workerLogger.Info().Msg("Draining worker pool")
+
+	// Wait for an in-progress dispatch so nothing is queued after the workers leave
+	wp.stopDispatcher()
+	select {
+	case <-wp.dispatcherDone:
+	case <-ctx.Done():
+		workerLogger.Warn().Err(ctx.Err()).Msg("Drain deadline reached before the dispatcher stopped")
+		return ctx.Err()
+	}
+
+	// Idle workers exit now; busy ones once their current challenge is done
+	wp.stopAllWorkers()
+	done := make(chan struct{})
+	go func() {
+		wp.workersDone.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		workerLogger.Warn().Err(ctx.Err()).Msg("Drain deadline reached with challenges still in progress")
+		return ctx.Err()
+	}
+
+	for {
+		select {
+		case challenge := <-wp.jobQueue:
+			wp.releaseClaim(workerLogger, challenge)
+		default:
+			workerLogger.Info().Msg("Worker pool drained")
+			return nil
+		}
+	}
+}
+
+func (wp *WorkerPool) stopDispatcher() {
+	wp.stopDispatch.Do(func() { close(wp.quit) })
+}
+
+func (wp *WorkerPool) stopAllWorkers() {
+	wp.stopWorkers.Do(func() {
+		for i := 0; i < wp.workers; i++ {
+			if wp.workerQuit[i] != nil {
+				close(wp.workerQuit[i])
+			}
+		}
+	})
+}
+
+// releaseClaim returns a claimed but unprocessed challenge to the state it was dispatched in
+func (wp *WorkerPool) releaseClaim(workerLogger zerolog.Logger, challenge *models.PendingChallenge) {
+	if err := wp.db.UpdateChallengeStatus(wp.ctx, challenge.ID, challenge.Status, challenge.AttemptCount, challenge.NextRetryTime); err != nil {
+		workerLogger.Error().Err(err).Str("challenge_id", challenge.ID).Msg("Failed to release challenge claim")
 	}
 }
 
 func (wp *WorkerPool) dispatcher() {
+	defer close(wp.dispatcherDone)
+
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
@@ -82,28 +270,58 @@ func (wp *WorkerPool) dispatcher() {
 			return
 
 		case <-ticker.C:
-			// Get pending challenges from database
-			challenges, err := wp.db.GetPendingChallenges(wp.workers * 2)
-			if err != nil {
-				workerLogger := logger.NewCategoryLogger(wp.service.config.LogLevel, logger.Solver, logger.Worker)
-				workerLogger.Error().Err(err).Msg("Failed to get pending challenges")
-				continue
-			}
+			wp.dispatchPending()
+		}
+	}
+}
 
-			// Dispatch challenges to workers
-			for _, challenge := range challenges {
-				// Check if it's time to retry
-				if challenge.Status == "processing" && time.Now().Before(challenge.NextRetryTime) {
-					continue
-				}
-
-				select {
-				case wp.jobQueue <- challenge:
-					// Job queued successfully
-				default:
-					// Queue is full, skip this challenge for now
-				}
-			}
+// dispatchPending queues the challenges that are ready for processing. Each challenge is claimed
+// before it is queued, so a challenge still waiting in the queue or being solved is not picked up
+// again by a later tick.
+func (wp *WorkerPool) dispatchPending() {
+	workerLogger := logger.NewCategoryLogger(wp.service.config.LogLevel, logger.Solver, logger.Worker)
+
+	// Get pending challenges from database
+	challenges, err := wp.db.GetPendingChallenges(wp.ctx, wp.workers*2)
+	if err != nil {
+		workerLogger.Error().Err(err).Msg("Failed to get pending challenges")
+		return
+	}
+
+	// Dispatch challenges to workers
+	queuedByType := make(map[string]int)
+	for _, challenge := range challenges {
+		// Check if it's time to retry
+		if challenge.Status == "processing" && time.Now().Before(challenge.NextRetryTime) {
+			continue
+		}
+
+		// Leave challenges of a type at its concurrency limit for a later tick
+		challengeType := problemType(challenge)
+		if !wp.typeHasCapacity(challengeType, queuedByType[challengeType]) {
+			continue
+		}
+
+		// Stop once the queue is full rather than claiming work no worker can take yet
+		if len(wp.jobQueue) == cap(wp.jobQueue) {
+			return
+		}
+
+		claimed, err := wp.db.ClaimChallenge(wp.ctx, challenge.ID, time.Now().Add(claimLease))
+		if err != nil {
+			workerLogger.Error().Err(err).Str("challenge_id", challenge.ID).Msg("Failed to claim challenge")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		select {
+		case wp.jobQueue <- challenge:
+			queuedByType[challengeType]++
+		default:
+			// Queue filled up after the check; release the claim for a later tick
+			wp.releaseClaim(workerLogger, challenge)
 		}
 	}
 }
@@ -115,6 +333,7 @@ func (wp *WorkerPool) worker(id int, quit chan struct{}) {
 		Logger()
 	workerLogger.Info().Msg("Worker started")
 
+	defer wp.workersDone.Done()
 	defer workerLogger.Info().Msg("Worker stopped")
 
 	for {
@@ -123,41 +342,100 @@ func (wp *WorkerPool) worker(id int, quit chan struct{}) {
 			return
 
 		case challenge := <-wp.jobQueue:
+			release, ok := wp.acquireTypeSlot(problemType(challenge))
+			if !ok {
+				// Another worker holds the type's last slot; hand the challenge back
+				// instead of blocking this worker
+				workerLogger.Debug().Str("challenge_id", challenge.ID).Msg("Challenge type at concurrency limit, requeueing")
+				wp.releaseClaim(workerLogger, challenge)
+				continue
+			}
 			wp.processChallenge(workerLogger, challenge)
+			release()
 		}
 	}
 }
 
+// problemType returns the "type" field of a challenge's problem, or "" when it has none
+func problemType(challenge *models.PendingChallenge) string {
+	var problem struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(challenge.Problem, &problem); err != nil {
+		return ""
+	}
+	return problem.Type
+}
+
+// typeHasCapacity reports whether another challenge of challengeType can be queued,
+// given how many were already queued in the current dispatch.
+func (wp *WorkerPool) typeHasCapacity(challengeType string, queued int) bool {
+	slots, limited := wp.typeSlots[challengeType]
+	if !limited {
+		return true
+	}
+	return cap(slots)-len(slots) > queued
+}
+
+// acquireTypeSlot takes one of challengeType's concurrency slots without blocking.
+// The returned func gives the slot back; types without a limit always succeed.
+func (wp *WorkerPool) acquireTypeSlot(challengeType string) (func(), bool) {
+	slots, limited := wp.typeSlots[challengeType]
+	if !limited {
+		return func() {}, true
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, true
+	default:
+		return nil, false
+	}
+}
+
 func (wp *WorkerPool) processChallenge(workerLogger zerolog.Logger, challenge *models.PendingChallenge) {
 	challengeLogger := workerLogger.With().Str("challenge_id", challenge.ID).Logger()
 	challengeLogger.Info().Msg("Processing challenge")
 
-	// Update status to processing
-	if err := wp.db.UpdateChallengeStatus(challenge.ID, "processing", challenge.AttemptCount, time.Now()); err != nil {
-		challengeLogger.Error().Err(err).Msg("Failed to update challenge status")
+	// Nobody is waiting for an answer past the deadline, so skip the work entirely
+	if challenge.DeadlineTs > 0 && !time.Now().Before(time.Unix(challenge.DeadlineTs, 0)) {
+		challengeLogger.Warn().Int64("deadline_ts", challenge.DeadlineTs).Msg("Deadline passed before processing, dropping challenge")
+		wp.dropExpired(challengeLogger, challenge, "deadline passed before processing")
 		return
 	}
 
 	// Solve the challenge
-	answer, metadata, err := wp.solveChallenge(challenge)
+	solveStart := time.Now()
+	answer, metadata, err := wp.safeSolve(challengeLogger, challenge)
+	metrics.SolveDuration.Observe(time.Since(solveStart).Seconds())
 
 	// Prepare callback request
 	var callbackReq models.CallbackRequest
 
 	if err != nil {
+		errorCode := "SOLVER_ERROR"
+		switch {
+		case errors.Is(err, errSolverPanic):
+			errorCode = "SOLVER_PANIC"
+		case errors.Is(err, errSolverTimeout):
+			errorCode = "TIMEOUT"
+		}
+
+		metrics.ChallengesFailed.WithLabelValues(errorCode).Inc()
 		challengeLogger.Error().Err(err).Msg("Failed to solve challenge")
 		callbackReq = models.CallbackRequest{
-			APIVersion:   "v2.1",
+			APIVersion:   callbackAPIVersion(challenge),
 			ChallengeID:  challenge.ID,
 			SolverJobID:  fmt.Sprintf("solver_job_%s", challenge.ID),
 			Status:       "failed",
-			ErrorCode:    "SOLVER_ERROR",
+			ErrorCode:    errorCode,
 			ErrorMessage: err.Error(),
 			Metadata:     metadata,
 		}
 	} else {
+		metrics.ChallengesSolved.Inc()
 		callbackReq = models.CallbackRequest{
-			APIVersion:  "v2.1",
+			APIVersion:  callbackAPIVersion(challenge),
 			ChallengeID: challenge.ID,
 			SolverJobID: fmt.Sprintf("solver_job_%s", challenge.ID),
 			Status:      "success",
@@ -167,20 +445,114 @@ func (wp *WorkerPool) processChallenge(workerLogger zerolog.Logger, challenge *m
 	}
 
 	// Send callback with retry
-	if err := wp.sendCallbackWithRetry(challenge, &callbackReq); err != nil {
+	if err := wp.sendCallbackWithRetry(wp.ctx, challenge, &callbackReq); err != nil {
+		// Shutting down: leave the claim to lapse so the challenge is picked up again after restart
+		if wp.ctx.Err() != nil {
+			challengeLogger.Warn().Err(err).Msg("Callback interrupted by shutdown")
+			return
+		}
+		if errors.Is(err, errCallbackPastDeadline) {
+			challengeLogger.Warn().Err(err).Int64("deadline_ts", challenge.DeadlineTs).Msg("Deadline passed during callback retries, dropping challenge")
+			wp.dropExpired(challengeLogger, challenge, err.Error())
+			return
+		}
 		challengeLogger.Error().Err(err).Msg("Failed to send callback after all retries")
-		// Mark as failed
-		wp.db.UpdateChallengeStatus(challenge.ID, "failed", MaxRetryAttempts, time.Now())
+		// Park it in the dead-letter table so the work queue does not grow forever
+		if err := wp.db.MoveToDeadLetter(wp.ctx, challenge.ID, err.Error()); err != nil {
+			challengeLogger.Error().Err(err).Msg("Failed to dead-letter challenge")
+			wp.db.UpdateChallengeStatus(wp.ctx, challenge.ID, "failed", wp.retry.MaxAttempts, time.Now())
+		} else {
+			wp.service.recordOutcome(challenge.ID, "failed", wp.retry.MaxAttempts)
+		}
 	} else {
 		challengeLogger.Info().Msg("Challenge completed successfully")
 		// Remove from pending challenges
-		wp.db.DeleteChallenge(challenge.ID)
+		wp.db.DeleteChallenge(wp.ctx, challenge.ID)
+		wp.service.recordOutcome(challenge.ID, "completed", challenge.AttemptCount+1)
+	}
+}
+
+// dropExpired moves a challenge whose deadline passed to the dead-letter table with a
+// DEADLINE_EXCEEDED reason, so it leaves the work queue. If the move fails the row is only
+// marked expired, and AcceptSolve replaces it when the challenger sends the challenge again.
+func (wp *WorkerPool) dropExpired(challengeLogger zerolog.Logger, challenge *models.PendingChallenge, detail string) {
+	if err := wp.db.MoveToDeadLetter(wp.ctx, challenge.ID, "DEADLINE_EXCEEDED: "+detail); err != nil {
+		challengeLogger.Error().Err(err).Msg("Failed to dead-letter expired challenge")
+		if err := wp.db.UpdateChallengeStatus(wp.ctx, challenge.ID, "expired", challenge.AttemptCount, time.Now()); err != nil {
+			challengeLogger.Error().Err(err).Msg("Failed to mark challenge expired")
+		}
+		return
+	}
+	wp.service.recordOutcome(challenge.ID, "expired", challenge.AttemptCount)
+}
+
+// solveResult carries a solver's output back from its goroutine.
+type solveResult struct {
+	answer   string
+	metadata json.RawMessage
+	err      error
+}
+
+// safeSolve runs the solver under the challenge's timeout and deadline and
+// converts a panic into a failed attempt, so a single bad problem cannot take
+// down the worker goroutine. A solver that ignores its context is abandoned
+// once the limit passes.
+func (wp *WorkerPool) safeSolve(challengeLogger zerolog.Logger, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+	ctx, cancel := solveContext(wp.ctx, challenge)
+	defer cancel()
+
+	done := make(chan solveResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				challengeLogger.Error().
+					Interface("panic", r).
+					Str("stack", string(debug.Stack())).
+					Msg("Solver panicked")
+				done <- solveResult{err: fmt.Errorf("%w: %v", errSolverPanic, r)}
+			}
+		}()
+
+		answer, metadata, err := wp.solver.Solve(ctx, challenge)
+		done <- solveResult{answer: answer, metadata: metadata, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		// A solver that honours ctx returns its own error; report it as a timeout too
+		if res.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", nil, fmt.Errorf("%w: %v", errSolverTimeout, res.err)
+		}
+		return res.answer, res.metadata, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", nil, fmt.Errorf("%w: %v", errSolverTimeout, ctx.Err())
+		}
+		return "", nil, ctx.Err()
 	}
 }
 
-func (wp *WorkerPool) solveChallenge(challenge *models.PendingChallenge) (string, json.RawMessage, error) {
-	// This is where the actual solving logic would go
-	// For this MVP, we'll implement a simple mock solver
+// solveContext bounds a solve attempt by the challenge's timeout and deadline, whichever is sooner.
+func solveContext(parent context.Context, challenge *models.PendingChallenge) (context.Context, context.CancelFunc) {
+	var deadline time.Time
+	if challenge.TimeoutMs > 0 {
+		deadline = time.Now().Add(time.Duration(challenge.TimeoutMs) * time.Millisecond)
+	}
+	if challenge.DeadlineTs > 0 {
+		if d := time.Unix(challenge.DeadlineTs, 0); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+
+	if deadline.IsZero() {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, deadline)
+}
+
+// solveChallenge is the built-in mock solver used when no SolverBackend is configured.
+func (wp *WorkerPool) solveChallenge(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+	// Real deployments set SOLVER_BACKEND_URL; this mock covers local development
 
 	// Parse the problem to determine challenge type
 	var problem map[string]interface{}
@@ -218,8 +590,12 @@ func (wp *WorkerPool) solveChallenge(challenge *models.PendingChallenge) (string
 		return "", nil, fmt.Errorf("unsupported challenge type: %s", challengeType)
 	}
 
-	// Add some random delay to simulate processing time
-	time.Sleep(time.Duration(rand.Intn(2000)) * time.Millisecond)
+	// Add some random delay to simulate processing time, cut short by the challenge timeout
+	select {
+	case <-time.After(time.Duration(rand.Intn(2000)) * time.Millisecond):
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
 
 	computeTime := time.Since(startTime)
 
@@ -298,17 +674,34 @@ func (wp *WorkerPool) solveMockText(problem map[string]interface{}) string {
 	return strings.ToUpper(text)
 }
 
-func (wp *WorkerPool) sendCallbackWithRetry(challenge *models.PendingChallenge, callbackReq *models.CallbackRequest) error {
+// sendCallbackWithRetry delivers callbackReq, retrying with backoff on retryable failures.
+// It gives up as soon as ctx is canceled, including while waiting between attempts.
+func (wp *WorkerPool) sendCallbackWithRetry(ctx context.Context, challenge *models.PendingChallenge, callbackReq *models.CallbackRequest) error {
 	challengeLogger := logger.NewCategoryLogger(wp.service.config.LogLevel, logger.Solver, logger.Worker).
 		With().
 		Str("challenge_id", challenge.ID).
 		Logger()
 
-	for attempt := 0; attempt < MaxRetryAttempts; attempt++ {
+	// Every attempt carries the same X-Request-ID so the challenger can deduplicate retries.
+	// Rows saved before the column existed get one here.
+	requestID := challenge.CallbackRequestID
+	if requestID == "" {
+		requestID = uuid.New().String()
+		challenge.CallbackRequestID = requestID
+	}
+
+	for attempt := 0; attempt < wp.retry.MaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("callback canceled: %w", err)
+		}
 		attemptLogger := challengeLogger.With().Int("attempt", attempt+1).Logger()
 
 		// Send callback
-		statusCode, err := wp.service.SendCallback(challenge.CallbackURL, callbackReq)
+		statusCode, err := wp.service.SendCallback(ctx, challenge.CallbackURL, callbackReq, requestID)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			attemptLogger.Warn().Err(err).Msg("Callback canceled")
+			return fmt.Errorf("callback canceled: %w", ctxErr)
+		}
 
 		if err == nil && statusCode >= 200 && statusCode < 300 {
 			// Success
@@ -316,13 +709,20 @@ func (wp *WorkerPool) sendCallbackWithRetry(challenge *models.PendingChallenge,
 			return nil
 		}
 
+		// 422 means the challenger received the callback but rejected the answer
+		// (CALLBACK_CORRECTNESS_MODE=status); the callback itself was delivered
+		if err == nil && statusCode == http.StatusUnprocessableEntity {
+			attemptLogger.Warn().Int("status_code", statusCode).Msg("Callback delivered; answer marked incorrect")
+			return nil
+		}
+
 		// Check if we should retry
 		shouldRetry := wp.shouldRetry(statusCode, err)
 
 		attemptLogger.Error().
 			Err(err).
 			Int("status_code", statusCode).
-			Bool("will_retry", shouldRetry && attempt < MaxRetryAttempts-1).
+			Bool("will_retry", shouldRetry && attempt < wp.retry.MaxAttempts-1).
 			Msg("Callback failed")
 
 		if !shouldRetry {
@@ -333,24 +733,33 @@ func (wp *WorkerPool) sendCallbackWithRetry(challenge *models.PendingChallenge,
 		}
 
 		// Last attempt?
-		if attempt == MaxRetryAttempts-1 {
-			return fmt.Errorf("callback failed after %d attempts", MaxRetryAttempts)
+		if attempt == wp.retry.MaxAttempts-1 {
+			return fmt.Errorf("callback failed after %d attempts", wp.retry.MaxAttempts)
 		}
 
 		// Calculate backoff delay with jitter
 		delay := wp.calculateBackoffDelay(attempt)
 		nextRetryTime := time.Now().Add(delay)
+		if challenge.DeadlineTs > 0 && nextRetryTime.After(time.Unix(challenge.DeadlineTs, 0)) {
+			return fmt.Errorf("%w after %d attempts", errCallbackPastDeadline, attempt+1)
+		}
 
-		// Update database with retry info
-		if err := wp.db.UpdateChallengeStatus(challenge.ID, "processing", attempt+1, nextRetryTime); err != nil {
+		// Update database with retry info, keeping the claim while this worker waits to retry
+		if err := wp.db.UpdateChallengeStatus(wp.ctx, challenge.ID, "processing", attempt+1, nextRetryTime.Add(claimLease)); err != nil {
 			attemptLogger.Error().Err(err).Msg("Failed to update retry status")
 		}
 
 		attemptLogger.Info().Dur("delay", delay).Msg("Waiting before retry")
-		time.Sleep(delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("callback canceled: %w", ctx.Err())
+		case <-timer.C:
+		}
 	}
 
-	return fmt.Errorf("callback failed after %d attempts", MaxRetryAttempts)
+	return fmt.Errorf("callback failed after %d attempts", wp.retry.MaxAttempts)
 }
 
 func (wp *WorkerPool) shouldRetry(statusCode int, err error) bool {
@@ -379,16 +788,10 @@ func (wp *WorkerPool) shouldRetry(statusCode int, err error) bool {
 }
 
 func (wp *WorkerPool) calculateBackoffDelay(attempt int) time.Duration {
-	// Exponential backoff: delay = min(max, base * 2^attempt)
-	delay := BaseDelay * time.Duration(math.Pow(2, float64(attempt)))
-
-	if delay > MaxDelay {
-		delay = MaxDelay
-	}
-
-	// Add jitter: delay * random(0.85, 1.15)
-	jitter := JitterMin + rand.Float64()*(JitterMax-JitterMin)
-	delay = time.Duration(float64(delay) * jitter)
-
-	return delay
+	return backoff.Policy{
+		BaseDelay: wp.retry.BaseDelay,
+		MaxDelay:  wp.retry.MaxDelay,
+		JitterMin: wp.retry.JitterMin,
+		JitterMax: wp.retry.JitterMax,
+	}.Delay(attempt)
 }
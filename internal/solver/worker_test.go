@@ -0,0 +1,815 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/rs/zerolog"
+)
+
+const testSolverMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// newTestService creates a solver service with a single worker backed by a temporary database
+func newTestService(t *testing.T) (*Service, *db.SolverDB) {
+	t.Helper()
+
+	database, err := db.NewSolverDB(filepath.Join(t.TempDir(), "solver.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	cfg := &config.Config{
+		LogLevel:             "info",
+		SolverWorkerCount:    1,
+		ChalHMACKeyID:        "test-key",
+		CallbackAllowedHosts: []string{"127.0.0.1"},
+	}
+	cfg.SUI.SolverMnemonic = testSolverMnemonic
+
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 0)
+	return NewService(cfg, database, hmacAuth), database
+}
+
+func TestWorkerSurvivesSolverPanic(t *testing.T) {
+	callbacks := make(chan models.CallbackRequest, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		if challenge.ID == "ch_panic" {
+			panic("solver exploded")
+		}
+		return "ok", nil, nil
+	}))
+
+	ctx := context.Background()
+	for _, id := range []string{"ch_panic", "ch_after_panic"} {
+		if err := database.SaveChallenge(ctx, &models.PendingChallenge{
+			ID:            id,
+			Problem:       json.RawMessage(`{"type":"text"}`),
+			OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+			CallbackURL:   server.URL + "/callback/" + id,
+			ReceivedAt:    time.Now(),
+			Status:        "pending",
+			NextRetryTime: time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to save challenge %s: %v", id, err)
+		}
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	defer pool.Stop()
+
+	// Queue directly instead of waiting for the dispatcher tick
+	for _, id := range []string{"ch_panic", "ch_after_panic"} {
+		challenge, err := database.GetChallenge(ctx, id)
+		if err != nil {
+			t.Fatalf("failed to load challenge %s: %v", id, err)
+		}
+		pool.jobQueue <- challenge
+	}
+
+	got := make(map[string]models.CallbackRequest)
+	for len(got) < 2 {
+		select {
+		case callbackReq := <-callbacks:
+			got[callbackReq.ChallengeID] = callbackReq
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for callbacks, got %d", len(got))
+		}
+	}
+
+	panicked := got["ch_panic"]
+	if panicked.Status != "failed" {
+		t.Errorf("expected panicking challenge to be marked failed, got %q", panicked.Status)
+	}
+	if panicked.ErrorCode != "SOLVER_PANIC" {
+		t.Errorf("expected error code SOLVER_PANIC, got %q", panicked.ErrorCode)
+	}
+
+	// The only worker must still be alive to have solved the second challenge
+	after := got["ch_after_panic"]
+	if after.Status != "success" || after.Answer != "ok" {
+		t.Errorf("expected second challenge to succeed, got status %q answer %q", after.Status, after.Answer)
+	}
+}
+
+func TestDispatcherQueuesChallengeOnce(t *testing.T) {
+	var callbacks atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callbacks.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	var solves atomic.Int32
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		solves.Add(1)
+		return "ok", nil, nil
+	}))
+
+	ctx := context.Background()
+	if err := database.SaveChallenge(ctx, &models.PendingChallenge{
+		ID:            "ch_once",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   server.URL + "/callback/ch_once",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	// Overlapping dispatcher ticks all see the challenge as pending
+	pool := service.workerPool
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.dispatchPending()
+		}()
+	}
+	wg.Wait()
+
+	if queued := len(pool.jobQueue); queued != 1 {
+		t.Fatalf("expected the challenge to be queued once, got %d", queued)
+	}
+
+	// A tick while the challenge waits in the queue must not queue it again
+	pool.dispatchPending()
+	if queued := len(pool.jobQueue); queued != 1 {
+		t.Fatalf("expected a claimed challenge not to be re-queued, got %d", queued)
+	}
+
+	pool.processChallenge(zerolog.Nop(), <-pool.jobQueue)
+	pool.dispatchPending()
+	if queued := len(pool.jobQueue); queued != 0 {
+		t.Errorf("expected nothing left to dispatch, got %d", queued)
+	}
+
+	if solves.Load() != 1 || callbacks.Load() != 1 {
+		t.Errorf("expected one solve and one callback, got %d solves and %d callbacks", solves.Load(), callbacks.Load())
+	}
+}
+
+func TestWorkerTypeLimitKeepsOtherTypesMoving(t *testing.T) {
+	callbacks := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq.ChallengeID
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	service.config.SolverTypeLimits = map[string]int{"captcha": 1}
+	service.workerPool = NewWorkerPool(3, database, service)
+
+	// Captchas hold their slot until the math challenge has been solved, so math
+	// can only finish if it is not stuck behind them
+	var activeCaptchas, maxCaptchas atomic.Int32
+	mathSolved := make(chan struct{})
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		if problemType(challenge) == "math" {
+			close(mathSolved)
+			return "8.00", nil, nil
+		}
+
+		active := activeCaptchas.Add(1)
+		defer activeCaptchas.Add(-1)
+		for {
+			current := maxCaptchas.Load()
+			if active <= current || maxCaptchas.CompareAndSwap(current, active) {
+				break
+			}
+		}
+		select {
+		case <-mathSolved:
+		case <-time.After(5 * time.Second):
+			t.Error("math challenge did not run while a captcha was in progress")
+		}
+		time.Sleep(50 * time.Millisecond)
+		return "abcde", nil, nil
+	}))
+
+	ctx := context.Background()
+	problems := map[string]string{
+		"captcha_1": `{"type":"captcha"}`,
+		"captcha_2": `{"type":"captcha"}`,
+		"math_1":    `{"type":"math","operation":"add","a":5,"b":3}`,
+	}
+	for _, id := range []string{"captcha_1", "captcha_2", "math_1"} {
+		if err := database.SaveChallenge(ctx, &models.PendingChallenge{
+			ID:            id,
+			Problem:       json.RawMessage(problems[id]),
+			OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+			CallbackURL:   server.URL + "/callback/" + id,
+			ReceivedAt:    time.Now(),
+			Status:        "pending",
+			NextRetryTime: time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to save challenge %s: %v", id, err)
+		}
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	defer pool.Stop()
+
+	// Queue both captchas at once so the workers, not just the dispatcher, enforce the limit
+	for _, id := range []string{"captcha_1", "captcha_2", "math_1"} {
+		challenge, err := database.GetChallenge(ctx, id)
+		if err != nil {
+			t.Fatalf("failed to load challenge %s: %v", id, err)
+		}
+		if claimed, err := database.ClaimChallenge(ctx, id, time.Now().Add(claimLease)); err != nil || !claimed {
+			t.Fatalf("failed to claim challenge %s: %v", id, err)
+		}
+		pool.jobQueue <- challenge
+	}
+
+	// Keep dispatching so a captcha handed back at the limit is picked up again
+	done := make(map[string]bool)
+	timeout := time.After(10 * time.Second)
+	for len(done) < 3 {
+		select {
+		case id := <-callbacks:
+			done[id] = true
+		case <-time.After(50 * time.Millisecond):
+			pool.dispatchPending()
+		case <-timeout:
+			t.Fatalf("timed out waiting for callbacks, got %v", done)
+		}
+	}
+
+	if got := maxCaptchas.Load(); got != 1 {
+		t.Errorf("expected at most 1 captcha at a time, got %d", got)
+	}
+}
+
+func TestDrainFinishesJobInProgress(t *testing.T) {
+	callbacks := make(chan models.CallbackRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	started := make(chan struct{})
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		close(started)
+		select {
+		case <-time.After(300 * time.Millisecond):
+			return "drained", nil, nil
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		}
+	}))
+
+	ctx := context.Background()
+	challenge := &models.PendingChallenge{
+		ID:            "ch_drain",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   server.URL + "/callback/ch_drain",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+	}
+	if err := database.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	pool.jobQueue <- challenge
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the worker to start solving")
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := pool.Drain(drainCtx); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+
+	// The callback was delivered before Drain returned
+	select {
+	case callbackReq := <-callbacks:
+		if callbackReq.Status != "success" || callbackReq.Answer != "drained" {
+			t.Errorf("expected the in-progress challenge to succeed, got status %q answer %q", callbackReq.Status, callbackReq.Answer)
+		}
+	default:
+		t.Fatal("expected the callback to be sent before Drain returned")
+	}
+
+	if remaining, err := database.GetChallenge(ctx, challenge.ID); err != nil || remaining != nil {
+		t.Errorf("expected the completed challenge to be removed, got %+v (err %v)", remaining, err)
+	}
+
+	// The shutdown path still calls Stop afterwards
+	pool.Stop()
+}
+
+func TestWorkerTimesOutSlowBackend(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Outlast the challenge timeout; the worker cancels the request
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		w.Write([]byte(`{"answer":"too-late"}`))
+	}))
+	defer backendServer.Close()
+
+	callbacks := make(chan models.CallbackRequest, 1)
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq
+	}))
+	defer callbackServer.Close()
+
+	service, database := newTestService(t)
+	service.SetSolverBackend(NewHTTPSolverBackend(backendServer.URL, 30*time.Second))
+
+	ctx := context.Background()
+	if err := database.SaveChallenge(ctx, &models.PendingChallenge{
+		ID:            "ch_slow",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   callbackServer.URL + "/callback/ch_slow",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+		TimeoutMs:     100,
+	}); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	defer pool.Stop()
+
+	challenge, err := database.GetChallenge(ctx, "ch_slow")
+	if err != nil {
+		t.Fatalf("failed to load challenge: %v", err)
+	}
+	start := time.Now()
+	pool.jobQueue <- challenge
+
+	select {
+	case callbackReq := <-callbacks:
+		if callbackReq.Status != "failed" || callbackReq.ErrorCode != "TIMEOUT" {
+			t.Errorf("expected failed TIMEOUT callback, got status %q code %q", callbackReq.Status, callbackReq.ErrorCode)
+		}
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("expected the timeout to cut the solve short, took %v", elapsed)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+}
+
+func TestWorkerTimesOutSolverIgnoringContext(t *testing.T) {
+	callbacks := make(chan models.CallbackRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callbackReq models.CallbackRequest
+		if err := json.NewDecoder(r.Body).Decode(&callbackReq); err != nil {
+			t.Errorf("failed to decode callback: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		callbacks <- callbackReq
+	}))
+	defer server.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	service, database := newTestService(t)
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		<-release
+		return "too-late", nil, nil
+	}))
+
+	ctx := context.Background()
+	if err := database.SaveChallenge(ctx, &models.PendingChallenge{
+		ID:            "ch_stuck",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   server.URL + "/callback/ch_stuck",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+		TimeoutMs:     100,
+	}); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	pool := service.workerPool
+	pool.Start()
+	defer pool.Stop()
+
+	challenge, err := database.GetChallenge(ctx, "ch_stuck")
+	if err != nil {
+		t.Fatalf("failed to load challenge: %v", err)
+	}
+	pool.jobQueue <- challenge
+
+	select {
+	case callbackReq := <-callbacks:
+		if callbackReq.ErrorCode != "TIMEOUT" {
+			t.Errorf("expected TIMEOUT error code, got %q", callbackReq.ErrorCode)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+}
+
+func TestWorkerDropsChallengePastDeadline(t *testing.T) {
+	called := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called <- struct{}{}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	solved := false
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		solved = true
+		return "ok", nil, nil
+	}))
+
+	ctx := context.Background()
+	challenge := &models.PendingChallenge{
+		ID:            "ch_expired",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   server.URL + "/callback/ch_expired",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+		DeadlineTs:    time.Now().Add(-time.Minute).Unix(),
+	}
+	if err := database.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	// Run synchronously so the outcome is known when it returns
+	service.workerPool.processChallenge(zerolog.Nop(), challenge)
+
+	if solved {
+		t.Error("expected expired challenge not to be solved")
+	}
+	select {
+	case <-called:
+		t.Error("expected no callback for an expired challenge")
+	default:
+	}
+
+	// The challenge leaves the work queue for the dead-letter table
+	if stored, err := database.GetChallenge(ctx, "ch_expired"); err != nil || stored != nil {
+		t.Errorf("expected the expired challenge to leave the queue, got %+v, %v", stored, err)
+	}
+	failed, err := database.ListDeadLetter(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to list dead-letter challenges: %v", err)
+	}
+	if len(failed) != 1 || failed[0].ID != "ch_expired" || !strings.HasPrefix(failed[0].Reason, "DEADLINE_EXCEEDED") {
+		t.Errorf("expected ch_expired dead-lettered with DEADLINE_EXCEEDED, got %+v", failed)
+	}
+}
+
+func TestWorkerStopsCallbackRetriesAtDeadline(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		return "ok", nil, nil
+	}))
+	// The first retry would land well after the deadline
+	service.workerPool.SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Minute,
+		JitterMin:   1,
+		JitterMax:   1,
+	})
+
+	ctx := context.Background()
+	challenge := &models.PendingChallenge{
+		ID:            "ch_retry_deadline",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   server.URL + "/callback/ch_retry_deadline",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+		DeadlineTs:    time.Now().Add(30 * time.Second).Unix(),
+	}
+	if err := database.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	start := time.Now()
+	service.workerPool.processChallenge(zerolog.Nop(), challenge)
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the worker not to wait for a retry past the deadline, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("expected a single callback attempt, got %d", got)
+	}
+
+	if stored, err := database.GetChallenge(ctx, challenge.ID); err != nil || stored != nil {
+		t.Errorf("expected the challenge to leave the queue, got %+v, %v", stored, err)
+	}
+	failed, err := database.ListDeadLetter(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to list dead-letter challenges: %v", err)
+	}
+	if len(failed) != 1 || failed[0].ID != challenge.ID || !strings.HasPrefix(failed[0].Reason, "DEADLINE_EXCEEDED") {
+		t.Errorf("expected the challenge dead-lettered with DEADLINE_EXCEEDED, got %+v", failed)
+	}
+}
+
+func TestSendCallbackWithRetryHonorsPolicy(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	service, _ := newTestService(t)
+	pool := service.workerPool
+	pool.SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		JitterMin:   1,
+		JitterMax:   1,
+	})
+
+	challenge := &models.PendingChallenge{ID: "ch_retry", CallbackURL: server.URL + "/callback/ch_retry"}
+	err := pool.sendCallbackWithRetry(context.Background(), challenge, &models.CallbackRequest{
+		APIVersion:  "v2.1",
+		ChallengeID: challenge.ID,
+		Status:      "success",
+		Answer:      "ok",
+	})
+	if err == nil {
+		t.Fatal("expected callback to fail against an unavailable challenger")
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", got)
+	}
+}
+
+func TestSendCallbackCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	service, _ := newTestService(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := service.SendCallback(ctx, server.URL+"/callback/ch_cancel",
+		&models.CallbackRequest{APIVersion: "v2.1", ChallengeID: "ch_cancel", Status: "success", Answer: "ok"}, "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a context.Canceled error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the send to abort promptly, took %v", elapsed)
+	}
+}
+
+func TestSendCallbackWithRetryStopsWhenCanceled(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	service, _ := newTestService(t)
+	pool := service.workerPool
+	// Long enough that the test would time out if the wait ignored cancellation
+	pool.SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Minute,
+		JitterMin:   1,
+		JitterMax:   1,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	challenge := &models.PendingChallenge{ID: "ch_retry_cancel", CallbackURL: server.URL + "/callback/ch_retry_cancel"}
+	start := time.Now()
+	err := pool.sendCallbackWithRetry(ctx, challenge, &models.CallbackRequest{
+		APIVersion:  "v2.1",
+		ChallengeID: challenge.ID,
+		Status:      "success",
+		Answer:      "ok",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a context.Canceled error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the retry wait to abort promptly, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("expected no attempts after cancellation, got %d", got)
+	}
+}
+
+func TestSendCallbackWithRetryReusesRequestID(t *testing.T) {
+	var attempts int32
+	requestIDs := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs <- r.Header.Get("X-Request-ID")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	pool := service.workerPool
+	pool.SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		JitterMin:   1,
+		JitterMax:   1,
+	})
+
+	challenge := &models.PendingChallenge{
+		ID:                "ch_stable_id",
+		CallbackURL:       server.URL + "/callback/ch_stable_id",
+		Status:            "pending",
+		ReceivedAt:        time.Now(),
+		NextRetryTime:     time.Now(),
+		CallbackRequestID: "req_ch_stable_id",
+	}
+	if err := database.SaveChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	callbackReq := &models.CallbackRequest{APIVersion: "v2.1", ChallengeID: challenge.ID, Status: "success", Answer: "ok"}
+	if err := pool.sendCallbackWithRetry(context.Background(), challenge, callbackReq); err != nil {
+		t.Fatalf("expected the second attempt to succeed, got %v", err)
+	}
+
+	// A restarted solver reloads the challenge and sends the same ID again
+	reloaded, err := database.GetChallenge(context.Background(), challenge.ID)
+	if err != nil || reloaded == nil {
+		t.Fatalf("failed to reload challenge: %v", err)
+	}
+	if err := pool.sendCallbackWithRetry(context.Background(), reloaded, callbackReq); err != nil {
+		t.Fatalf("unexpected error after reload: %v", err)
+	}
+
+	close(requestIDs)
+	var got []string
+	for id := range requestIDs {
+		got = append(got, id)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 callback attempts, got %d", len(got))
+	}
+	for _, id := range got {
+		if id != "req_ch_stable_id" {
+			t.Errorf("expected every attempt to send X-Request-ID req_ch_stable_id, got %v", got)
+			break
+		}
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	policy := retryPolicyFromConfig(&config.Config{
+		SolverMaxRetryAttempts: 3,
+		SolverBaseDelayMs:      100,
+		SolverMaxDelayMs:       1000,
+		SolverJitterPct:        10,
+	})
+
+	if policy.MaxAttempts != 3 || policy.BaseDelay != 100*time.Millisecond || policy.MaxDelay != time.Second {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+	if policy.JitterMin != 0.9 || policy.JitterMax != 1.1 {
+		t.Errorf("expected jitter bounds 0.9-1.1, got %v-%v", policy.JitterMin, policy.JitterMax)
+	}
+
+	// Unset values fall back to the built-in defaults
+	defaults := retryPolicyFromConfig(&config.Config{SolverJitterPct: 15})
+	if defaults.MaxAttempts != MaxRetryAttempts || defaults.BaseDelay != BaseDelay || defaults.MaxDelay != MaxDelay {
+		t.Errorf("expected default policy, got %+v", defaults)
+	}
+}
+
+func TestWorkerDeadLettersUndeliverableChallenge(t *testing.T) {
+	// 400 is non-retryable, so the first failed delivery is terminal
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	service, database := newTestService(t)
+	service.SetSolver(SolverFunc(func(ctx context.Context, challenge *models.PendingChallenge) (string, json.RawMessage, error) {
+		return "ok", nil, nil
+	}))
+
+	ctx := context.Background()
+	challenge := &models.PendingChallenge{
+		ID:            "ch_undeliverable",
+		Problem:       json.RawMessage(`{"type":"text"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   server.URL + "/callback/ch_undeliverable",
+		ReceivedAt:    time.Now(),
+		Status:        "pending",
+		NextRetryTime: time.Now(),
+	}
+	if err := database.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("failed to save challenge: %v", err)
+	}
+
+	service.workerPool.processChallenge(zerolog.Nop(), challenge)
+
+	pending, err := database.GetPendingChallenges(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to get pending challenges: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending challenges, got %d", len(pending))
+	}
+
+	failed, err := database.ListDeadLetter(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(failed) != 1 || failed[0].ID != "ch_undeliverable" {
+		t.Fatalf("expected the challenge to be dead-lettered, got %+v", failed)
+	}
+	if !strings.Contains(failed[0].Reason, "400") {
+		t.Errorf("expected reason to carry the final error, got %q", failed[0].Reason)
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/models"
+)
+
+// Actions accepted by POST /admin/keys
+const (
+	KeyActionAdd    = "add"
+	KeyActionRevoke = "revoke"
+)
+
+// KeyUpdateRequest adds or revokes an HMAC key through POST /admin/keys.
+// Secret and the validity window only apply to KeyActionAdd.
+type KeyUpdateRequest struct {
+	Action    string     `json:"action"`               // "add" or "revoke"
+	KeyID     string     `json:"key_id"`               // Key to add, replace or revoke
+	Secret    string     `json:"secret,omitempty"`     // Signing secret for the key
+	NotBefore *time.Time `json:"not_before,omitempty"` // Key is rejected before this time (RFC 3339)
+	NotAfter  *time.Time `json:"not_after,omitempty"`  // Key is rejected from this time on (RFC 3339)
+}
+
+// KeyListResponse lists the key IDs currently accepted, without their secrets
+type KeyListResponse struct {
+	KeyIDs []string `json:"key_ids"`
+}
+
+// BackupResponse describes the snapshot written by POST /admin/backup
+type BackupResponse struct {
+	Path      string    `json:"path"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VacuumResponse reports how long POST /admin/vacuum took
+type VacuumResponse struct {
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// SetAdminKey sets the key that must be sent in X-Admin-Key to use the /admin endpoints.
+// An empty key disables them.
+func (m *Middleware) SetAdminKey(key string) {
+	m.adminKey = key
+}
+
+// HandleListKeys serves GET /admin/keys with the configured HMAC key IDs.
+func (m *Middleware) HandleListKeys(w http.ResponseWriter, r *http.Request) {
+	if !m.authorizeAdmin(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(KeyListResponse{KeyIDs: m.hmacAuth.ListKeyIDs()})
+}
+
+// HandleUpdateKeys serves POST /admin/keys, adding or revoking an HMAC key without a restart.
+// The change is persisted first when the database implements db.HMACKeyStore, so it is
+// reapplied by LoadHMACKeys on the next start; a failed write leaves the keys untouched.
+func (m *Middleware) HandleUpdateKeys(w http.ResponseWriter, r *http.Request) {
+	if !m.authorizeAdmin(w, r) {
+		return
+	}
+
+	requestID := RequestID(r)
+	logger := logger.WithRequestID(requestID)
+
+	var req KeyUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		m.writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON in request body", requestID)
+		return
+	}
+
+	key, err := keyFromRequest(&req)
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, "INVALID_KEY_UPDATE", err.Error(), requestID)
+		return
+	}
+
+	if store, ok := m.db.(db.HMACKeyStore); ok {
+		if err := store.SaveHMACKey(r.Context(), key); err != nil {
+			logger.Error().Err(err).Str("key_id", key.KeyID).Msg("Failed to persist HMAC key")
+			m.writeError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to persist HMAC key", requestID)
+			return
+		}
+	}
+	applyHMACKey(m.hmacAuth, key)
+
+	logger.Info().Str("action", req.Action).Str("key_id", key.KeyID).Msg("HMAC key updated")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(KeyListResponse{KeyIDs: m.hmacAuth.ListKeyIDs()})
+}
+
+// SetBackupDir sets where POST /admin/backup writes snapshots, named <prefix>-<UTC time>.db.
+// An empty dir disables the endpoint.
+func (m *Middleware) SetBackupDir(dir, prefix string) {
+	m.backupDir = dir
+	m.backupPrefix = prefix
+}
+
+// HandleBackup serves POST /admin/backup, writing a consistent snapshot of the database to the
+// backup directory while the service keeps serving. Only the SQLite stores support it.
+func (m *Middleware) HandleBackup(w http.ResponseWriter, r *http.Request) {
+	if !m.authorizeAdmin(w, r) {
+		return
+	}
+
+	requestID := RequestID(r)
+	logger := logger.WithRequestID(requestID)
+
+	store, ok := m.db.(db.BackupStore)
+	if !ok {
+		m.writeError(w, http.StatusNotImplemented, "BACKUP_UNSUPPORTED", "The database driver does not support online backups", requestID)
+		return
+	}
+	if m.backupDir == "" {
+		m.writeError(w, http.StatusServiceUnavailable, "BACKUP_DISABLED", "BACKUP_DIR is not configured", requestID)
+		return
+	}
+
+	if err := os.MkdirAll(m.backupDir, 0o750); err != nil {
+		logger.Error().Err(err).Str("dir", m.backupDir).Msg("Failed to create backup directory")
+		m.writeError(w, http.StatusInternalServerError, "BACKUP_FAILED", "Failed to create backup directory", requestID)
+		return
+	}
+
+	createdAt := time.Now().UTC()
+	path := filepath.Join(m.backupDir, fmt.Sprintf("%s-%s.db", m.backupPrefix, createdAt.Format("20060102T150405.000Z")))
+	if err := store.Backup(r.Context(), path); err != nil {
+		logger.Error().Err(err).Str("path", path).Msg("Database backup failed")
+		m.writeError(w, http.StatusInternalServerError, "BACKUP_FAILED", "Database backup failed", requestID)
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		logger.Error().Err(err).Str("path", path).Msg("Failed to stat database backup")
+		m.writeError(w, http.StatusInternalServerError, "BACKUP_FAILED", "Database backup failed", requestID)
+		return
+	}
+
+	logger.Info().Str("path", path).Int64("size_bytes", info.Size()).Msg("Database backed up")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BackupResponse{Path: path, SizeBytes: info.Size(), CreatedAt: createdAt})
+}
+
+// HandleVacuum serves POST /admin/vacuum, compacting the database and truncating its WAL.
+// Writes wait while it runs, so trigger it when the service is quiet.
+func (m *Middleware) HandleVacuum(w http.ResponseWriter, r *http.Request) {
+	if !m.authorizeAdmin(w, r) {
+		return
+	}
+
+	requestID := RequestID(r)
+	logger := logger.WithRequestID(requestID)
+
+	store, ok := m.db.(db.BackupStore)
+	if !ok {
+		m.writeError(w, http.StatusNotImplemented, "VACUUM_UNSUPPORTED", "The database driver does not support vacuuming", requestID)
+		return
+	}
+
+	start := time.Now()
+	if err := store.Vacuum(r.Context()); err != nil {
+		logger.Error().Err(err).Msg("Database vacuum failed")
+		m.writeError(w, http.StatusInternalServerError, "VACUUM_FAILED", "Database vacuum failed", requestID)
+		return
+	}
+	duration := time.Since(start)
+
+	logger.Info().Dur("duration", duration).Msg("Database vacuumed")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(VacuumResponse{DurationMs: duration.Milliseconds()})
+}
+
+// LoadHMACKeys applies the keys persisted by HandleUpdateKeys on top of the configured ones.
+// Call it at startup, after the environment keys are added. Returns how many records were applied.
+func LoadHMACKeys(ctx context.Context, hmacAuth *auth.HMACAuth, store db.HMACKeyStore) (int, error) {
+	keys, err := store.ListHMACKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		applyHMACKey(hmacAuth, key)
+	}
+	return len(keys), nil
+}
+
+// authorizeAdmin checks X-Admin-Key, writing the error response when the request is refused
+func (m *Middleware) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	requestID := RequestID(r)
+
+	if m.adminKey == "" {
+		m.writeError(w, http.StatusServiceUnavailable, "ADMIN_API_DISABLED", "ADMIN_API_KEY is not configured", requestID)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Key")), []byte(m.adminKey)) != 1 {
+		m.writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid admin key", requestID)
+		return false
+	}
+	return true
+}
+
+// keyFromRequest validates an update request and converts it to the stored form
+func keyFromRequest(req *KeyUpdateRequest) (*models.HMACKey, error) {
+	if req.KeyID == "" {
+		return nil, fmt.Errorf("key_id is required")
+	}
+
+	key := &models.HMACKey{KeyID: req.KeyID, UpdatedAt: time.Now()}
+	switch req.Action {
+	case KeyActionAdd:
+		if req.Secret == "" {
+			return nil, fmt.Errorf("secret is required to add a key")
+		}
+		if req.NotBefore != nil && req.NotAfter != nil && !req.NotAfter.After(*req.NotBefore) {
+			return nil, fmt.Errorf("not_after must be after not_before")
+		}
+		key.Secret = req.Secret
+		key.NotBefore = req.NotBefore
+		key.NotAfter = req.NotAfter
+	case KeyActionRevoke:
+		key.RevokedAt = &key.UpdatedAt
+	default:
+		return nil, fmt.Errorf("unsupported action %q (use add or revoke)", req.Action)
+	}
+	return key, nil
+}
+
+// applyHMACKey adds or revokes key in hmacAuth
+func applyHMACKey(hmacAuth *auth.HMACAuth, key *models.HMACKey) {
+	if key.RevokedAt != nil {
+		hmacAuth.RemoveSecret(key.KeyID)
+		return
+	}
+
+	var notBefore, notAfter time.Time
+	if key.NotBefore != nil {
+		notBefore = *key.NotBefore
+	}
+	if key.NotAfter != nil {
+		notAfter = *key.NotAfter
+	}
+	if notBefore.IsZero() && notAfter.IsZero() {
+		hmacAuth.AddSecret(key.KeyID, key.Secret)
+		return
+	}
+	hmacAuth.AddSecretWithValidity(key.KeyID, key.Secret, notBefore, notAfter)
+}
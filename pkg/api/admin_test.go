@@ -0,0 +1,305 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+const testAdminKey = "admin-secret"
+
+func newAdminTestStore(t *testing.T) *db.SolverDB {
+	t.Helper()
+
+	store, err := db.NewSolverDB(filepath.Join(t.TempDir(), "solver.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func postKeyUpdate(t *testing.T, m *Middleware, adminKey string, req KeyUpdateRequest) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	r := httptest.NewRequest("POST", "/admin/keys", bytes.NewReader(body))
+	r.Header.Set("X-Admin-Key", adminKey)
+	w := httptest.NewRecorder()
+	m.HandleUpdateKeys(w, r)
+	return w
+}
+
+// signedStatus sends a request signed with keyID/secret through the HMACAuth middleware
+func signedStatus(m *Middleware, keyID, secret string) int {
+	signer := auth.NewHMACAuth(map[string]string{keyID: secret}, 300*time.Second)
+	body := []byte(`{"challenge_id":"c1"}`)
+
+	r := httptest.NewRequest("POST", "/solve", bytes.NewReader(body))
+	r.Header.Set("Authorization", signer.CreateAuthHeader("POST", "/solve", body, keyID, uuid.New().String()))
+	w := httptest.NewRecorder()
+	m.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestHandleUpdateKeys_AddedKeyAuthenticates(t *testing.T) {
+	store := newAdminTestStore(t)
+	hmacAuth := auth.NewHMACAuth(map[string]string{"solver-kid-1": "old-secret"}, 300*time.Second)
+	m := NewMiddleware(hmacAuth, store)
+	m.SetAdminKey(testAdminKey)
+
+	if code := signedStatus(m, "solver-kid-2", "new-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("Expected an unknown key to be rejected, got %d", code)
+	}
+
+	w := postKeyUpdate(t, m, testAdminKey, KeyUpdateRequest{Action: KeyActionAdd, KeyID: "solver-kid-2", Secret: "new-secret"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 adding a key, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp KeyListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if got := strings.Join(resp.KeyIDs, ","); got != "solver-kid-1,solver-kid-2" {
+		t.Errorf("Expected both key IDs to be listed, got %s", got)
+	}
+	if strings.Contains(w.Body.String(), "new-secret") {
+		t.Error("Expected the response not to echo the secret")
+	}
+
+	if code := signedStatus(m, "solver-kid-2", "new-secret"); code != http.StatusOK {
+		t.Errorf("Expected a request signed with the added key to authenticate, got %d", code)
+	}
+
+	// A restarted service picks the key up from the database
+	restarted := auth.NewHMACAuth(map[string]string{"solver-kid-1": "old-secret"}, 300*time.Second)
+	if n, err := LoadHMACKeys(context.Background(), restarted, store); err != nil || n != 1 {
+		t.Fatalf("LoadHMACKeys() = %d, %v; want 1, nil", n, err)
+	}
+	if code := signedStatus(NewMiddleware(restarted, store), "solver-kid-2", "new-secret"); code != http.StatusOK {
+		t.Errorf("Expected the persisted key to authenticate after a restart, got %d", code)
+	}
+}
+
+func TestHandleUpdateKeys_RevokeSurvivesRestart(t *testing.T) {
+	store := newAdminTestStore(t)
+	envSecrets := func() map[string]string { return map[string]string{"chal-kid-1": "leaked-secret"} }
+	m := NewMiddleware(auth.NewHMACAuth(envSecrets(), 300*time.Second), store)
+	m.SetAdminKey(testAdminKey)
+
+	w := postKeyUpdate(t, m, testAdminKey, KeyUpdateRequest{Action: KeyActionRevoke, KeyID: "chal-kid-1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 revoking a key, got %d: %s", w.Code, w.Body.String())
+	}
+	if code := signedStatus(m, "chal-kid-1", "leaked-secret"); code != http.StatusUnauthorized {
+		t.Errorf("Expected the revoked key to be rejected, got %d", code)
+	}
+
+	// The key is still in the environment, but the revocation wins on restart
+	restarted := auth.NewHMACAuth(envSecrets(), 300*time.Second)
+	if _, err := LoadHMACKeys(context.Background(), restarted, store); err != nil {
+		t.Fatalf("LoadHMACKeys() unexpected error: %v", err)
+	}
+	if _, ok := restarted.GetSecret("chal-kid-1"); ok {
+		t.Error("Expected the revoked key to stay revoked after a restart")
+	}
+
+	keys, err := store.ListHMACKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListHMACKeys() unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].RevokedAt == nil || keys[0].Secret != "" {
+		t.Errorf("Expected a revocation record without the secret, got %+v", keys)
+	}
+}
+
+func TestHandleUpdateKeys_ValidityWindow(t *testing.T) {
+	store := newAdminTestStore(t)
+	m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), store)
+	m.SetAdminKey(testAdminKey)
+
+	notBefore := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	w := postKeyUpdate(t, m, testAdminKey, KeyUpdateRequest{Action: KeyActionAdd, KeyID: "next-kid", Secret: "next-secret", NotBefore: &notBefore})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if code := signedStatus(m, "next-kid", "next-secret"); code != http.StatusUnauthorized {
+		t.Errorf("Expected a not-yet-valid key to be rejected, got %d", code)
+	}
+
+	keys, err := store.ListHMACKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListHMACKeys() unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].NotBefore == nil || !keys[0].NotBefore.Equal(notBefore) || keys[0].NotAfter != nil {
+		t.Errorf("Expected the validity window to be persisted, got %+v", keys)
+	}
+}
+
+func TestAdminKeys_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminKey   string // Configured key
+		sentKey    string
+		req        KeyUpdateRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "disabled", sentKey: testAdminKey, req: KeyUpdateRequest{Action: KeyActionAdd, KeyID: "k", Secret: "s"}, wantStatus: http.StatusServiceUnavailable, wantCode: "ADMIN_API_DISABLED"},
+		{name: "wrong admin key", adminKey: testAdminKey, sentKey: "guess", req: KeyUpdateRequest{Action: KeyActionAdd, KeyID: "k", Secret: "s"}, wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "missing secret", adminKey: testAdminKey, sentKey: testAdminKey, req: KeyUpdateRequest{Action: KeyActionAdd, KeyID: "k"}, wantStatus: http.StatusBadRequest, wantCode: "INVALID_KEY_UPDATE"},
+		{name: "missing key id", adminKey: testAdminKey, sentKey: testAdminKey, req: KeyUpdateRequest{Action: KeyActionRevoke}, wantStatus: http.StatusBadRequest, wantCode: "INVALID_KEY_UPDATE"},
+		{name: "unknown action", adminKey: testAdminKey, sentKey: testAdminKey, req: KeyUpdateRequest{Action: "rotate", KeyID: "k"}, wantStatus: http.StatusBadRequest, wantCode: "INVALID_KEY_UPDATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hmacAuth := auth.NewHMACAuth(map[string]string{}, 300*time.Second)
+			m := NewMiddleware(hmacAuth, NewMockDB())
+			m.SetAdminKey(tt.adminKey)
+
+			w := postKeyUpdate(t, m, tt.sentKey, tt.req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var errorResp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Error.Code != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, errorResp.Error.Code)
+			}
+			if len(hmacAuth.ListKeyIDs()) != 0 {
+				t.Errorf("Expected no keys to change, got %v", hmacAuth.ListKeyIDs())
+			}
+		})
+	}
+}
+
+func TestHandleListKeys(t *testing.T) {
+	m := NewMiddleware(auth.NewHMACAuth(map[string]string{"b-kid": "x", "a-kid": "y"}, 300*time.Second), NewMockDB())
+	m.SetAdminKey(testAdminKey)
+
+	r := httptest.NewRequest("GET", "/admin/keys", nil)
+	r.Header.Set("X-Admin-Key", testAdminKey)
+	w := httptest.NewRecorder()
+	m.HandleListKeys(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var resp KeyListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if got := strings.Join(resp.KeyIDs, ","); got != "a-kid,b-kid" {
+		t.Errorf("Expected sorted key IDs, got %s", got)
+	}
+}
+
+// postAdmin calls an admin handler with the given X-Admin-Key
+func postAdmin(handler http.HandlerFunc, path, adminKey string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", path, nil)
+	r.Header.Set("X-Admin-Key", adminKey)
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestHandleBackup(t *testing.T) {
+	store := newAdminTestStore(t)
+	if err := store.SaveNonce(context.Background(), "backed_up"); err != nil {
+		t.Fatalf("Failed to save nonce: %v", err)
+	}
+
+	m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), store)
+	m.SetAdminKey(testAdminKey)
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	m.SetBackupDir(backupDir, "solver")
+
+	w := postAdmin(m.HandleBackup, "/admin/backup", testAdminKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp BackupResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if filepath.Dir(resp.Path) != backupDir || !strings.HasPrefix(filepath.Base(resp.Path), "solver-") || resp.SizeBytes == 0 {
+		t.Errorf("Unexpected backup response %+v", resp)
+	}
+
+	restored, err := db.NewSolverDB(resp.Path)
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	defer restored.Close()
+	if seen, err := restored.HasSeenNonce(context.Background(), "backed_up"); err != nil || !seen {
+		t.Errorf("Expected the backup to contain the saved nonce (err %v)", err)
+	}
+
+	w = postAdmin(m.HandleVacuum, "/admin/vacuum", testAdminKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected vacuum status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAdminBackup_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		store      interface{}
+		backupDir  string
+		sentKey    string
+		handler    func(m *Middleware) http.HandlerFunc
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "wrong admin key", backupDir: "backups", sentKey: "guess", handler: func(m *Middleware) http.HandlerFunc { return m.HandleBackup }, wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "backup unsupported", store: NewMockDB(), backupDir: "backups", sentKey: testAdminKey, handler: func(m *Middleware) http.HandlerFunc { return m.HandleBackup }, wantStatus: http.StatusNotImplemented, wantCode: "BACKUP_UNSUPPORTED"},
+		{name: "backup disabled", sentKey: testAdminKey, handler: func(m *Middleware) http.HandlerFunc { return m.HandleBackup }, wantStatus: http.StatusServiceUnavailable, wantCode: "BACKUP_DISABLED"},
+		{name: "vacuum unsupported", store: NewMockDB(), sentKey: testAdminKey, handler: func(m *Middleware) http.HandlerFunc { return m.HandleVacuum }, wantStatus: http.StatusNotImplemented, wantCode: "VACUUM_UNSUPPORTED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.store
+			if store == nil {
+				store = newAdminTestStore(t)
+			}
+			m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), store)
+			m.SetAdminKey(testAdminKey)
+			if tt.backupDir != "" {
+				m.SetBackupDir(filepath.Join(t.TempDir(), tt.backupDir), "solver")
+			}
+
+			w := postAdmin(tt.handler(m), "/admin/backup", tt.sentKey)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var errorResp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Error.Code != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, errorResp.Error.Code)
+			}
+		})
+	}
+}
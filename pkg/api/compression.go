@@ -0,0 +1,173 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"reverse-challenge-system/pkg/logger"
+)
+
+// Compression returns middleware that handles gzip in both directions. Request bodies sent with
+// Content-Encoding: gzip are decompressed before the next handler reads them, so HMACAuth
+// signatures and SizeLimitN limits apply to the decoded body; register it before both.
+// Responses are gzip-encoded when the client sends Accept-Encoding: gzip and the body reaches
+// minBytes; smaller responses, and responses the handler already encoded, are sent unchanged.
+func (m *Middleware) Compression(minBytes int) func(http.Handler) http.Handler {
+	if minBytes < 0 {
+		minBytes = 0
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+			case "", "identity":
+			case "gzip":
+				if r.Body == nil || r.Body == http.NoBody {
+					break
+				}
+				zr, err := gzip.NewReader(r.Body)
+				if err != nil {
+					requestID := RequestID(r)
+					logger := logger.WithRequestID(requestID)
+					logger.Warn().Err(err).Str("path", r.URL.Path).Msg("Invalid gzip request body")
+					m.writeError(w, http.StatusBadRequest, "INVALID_ENCODING", "Request body is not valid gzip", requestID)
+					return
+				}
+				defer zr.Close()
+
+				r.Body = zr
+				r.ContentLength = -1
+				r.Header.Del("Content-Encoding")
+				r.Header.Del("Content-Length")
+			default:
+				m.writeError(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_ENCODING",
+					"Unsupported Content-Encoding "+encoding, RequestID(r))
+				return
+			}
+
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+			gw := &gzipWriter{ResponseWriter: w, minBytes: minBytes, statusCode: http.StatusOK}
+			next.ServeHTTP(gw, r)
+			// Not deferred: after a panic Recover must still be able to send its 500
+			gw.Close()
+		})
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows a gzip response
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter buffers a response until it reaches minBytes, then switches to gzip.
+// Responses that never reach minBytes are written uncompressed by Close.
+type gzipWriter struct {
+	http.ResponseWriter
+	minBytes    int
+	statusCode  int
+	wroteHeader bool // WriteHeader was called by the handler
+	buf         bytes.Buffer
+	zw          *gzip.Writer // Non-nil once compression has started
+	passthrough bool         // Response is written as is
+}
+
+func (gw *gzipWriter) WriteHeader(code int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+	gw.statusCode = code
+
+	// Bodiless responses and responses the handler encoded itself are not compressed
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified ||
+		gw.Header().Get("Content-Encoding") != "" {
+		gw.passthrough = true
+		gw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (gw *gzipWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.passthrough {
+		return gw.ResponseWriter.Write(b)
+	}
+	if gw.zw != nil {
+		return gw.zw.Write(b)
+	}
+
+	gw.buf.Write(b)
+	if gw.buf.Len() < gw.minBytes {
+		return len(b), nil
+	}
+
+	header := gw.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(gw.buf.Bytes()))
+	}
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+
+	gw.zw = gzip.NewWriter(gw.ResponseWriter)
+	if _, err := gw.zw.Write(gw.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	gw.buf.Reset()
+	return len(b), nil
+}
+
+// Flush sends the compressed bytes written so far, for handlers that stream. A response still
+// under minBytes stays buffered, since it may yet be sent uncompressed.
+func (gw *gzipWriter) Flush() {
+	if gw.zw != nil {
+		gw.zw.Flush()
+	} else if !gw.passthrough {
+		return
+	}
+	http.NewResponseController(gw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (gw *gzipWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// Close finishes the gzip stream, or writes a response that stayed under minBytes uncompressed
+func (gw *gzipWriter) Close() error {
+	switch {
+	case gw.passthrough:
+		return nil
+	case gw.zw != nil:
+		return gw.zw.Close()
+	}
+
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+	if gw.buf.Len() == 0 {
+		return nil
+	}
+	_, err := gw.ResponseWriter.Write(gw.buf.Bytes())
+	return err
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatalf("Failed to gzip: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// echoHandler writes the request body back as the response
+var echoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+})
+
+func TestMiddleware_Compression_GzipRequestThroughHMAC(t *testing.T) {
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+	m := NewMiddleware(hmacAuth, NewMockDB())
+	handler := m.Compression(64)(m.SizeLimitN(1024 * 1024)(m.HMACAuth(echoHandler)))
+
+	// A large problem, like an embedded CAPTCHA image, compresses well
+	body := []byte(`{"challenge_id":"c1","problem":{"image":"` + strings.Repeat("iVBORw0KGgo", 500) + `"}}`)
+	compressed := gzipBytes(t, body)
+
+	req := httptest.NewRequest("POST", "/solve", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Accept-Encoding", "gzip")
+	// The signature covers the decoded body
+	req.Header.Set("Authorization", hmacAuth.CreateAuthHeader("POST", "/solve", body, "test-key", uuid.New().String()))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected a gzip response, got Content-Encoding %q", got)
+	}
+	if w.Body.Len() >= len(body) {
+		t.Errorf("Expected the response to be compressed, got %d bytes for %d", w.Body.Len(), len(body))
+	}
+
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Failed to read gzip response: %v", err)
+	}
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !bytes.Equal(decoded, body) {
+		t.Error("Expected the handler to receive and echo the decoded body")
+	}
+}
+
+func TestMiddleware_Compression_SmallResponseUncompressed(t *testing.T) {
+	m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), NewMockDB())
+	handler := m.Compression(1024)(echoHandler)
+
+	req := httptest.NewRequest("POST", "/solve", strings.NewReader(`{"ok":true}`))
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected a response under the threshold to stay uncompressed, got %q", got)
+	}
+	if w.Body.String() != `{"ok":true}` {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", got)
+	}
+}
+
+func TestMiddleware_Compression_NoAcceptEncoding(t *testing.T) {
+	m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), NewMockDB())
+	handler := m.Compression(0)(echoHandler)
+
+	req := httptest.NewRequest("POST", "/solve", strings.NewReader(strings.Repeat("a", 4096)))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected no compression without Accept-Encoding, got %q", got)
+	}
+	if w.Body.Len() != 4096 {
+		t.Errorf("Expected the body unchanged, got %d bytes", w.Body.Len())
+	}
+}
+
+func TestMiddleware_Compression_RejectsBadEncoding(t *testing.T) {
+	tests := []struct {
+		name       string
+		encoding   string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid gzip", encoding: "gzip", wantStatus: http.StatusBadRequest, wantCode: "INVALID_ENCODING"},
+		{name: "unsupported encoding", encoding: "br", wantStatus: http.StatusUnsupportedMediaType, wantCode: "UNSUPPORTED_ENCODING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), NewMockDB())
+			called := false
+			handler := m.Compression(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest("POST", "/solve", strings.NewReader("not compressed"))
+			req.Header.Set("Content-Encoding", tt.encoding)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if called {
+				t.Error("Expected the handler not to run")
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var errorResp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Error.Code != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, errorResp.Error.Code)
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "gzip", want: true},
+		{header: "deflate, gzip;q=0.8", want: true},
+		{header: "GZIP", want: true},
+		{header: "*", want: true},
+		{header: "gzip;q=0", want: false},
+		{header: "br, deflate", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := acceptsGzip(tt.header); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
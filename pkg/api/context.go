@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"reverse-challenge-system/pkg/auth"
+)
+
+// contextKey is the type of the request context values set by the middleware, so they
+// cannot collide with keys from other packages
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota // Request ID assigned by RequestLogging
+	authInfoKey                    // Parsed Authorization header of a request HMACAuth verified
+)
+
+// WithRequestID returns a copy of ctx carrying requestID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by RequestLogging, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithAuthInfo returns a copy of ctx carrying the verified authorization of a request.
+func WithAuthInfo(ctx context.Context, info auth.AuthHeader) context.Context {
+	return context.WithValue(ctx, authInfoKey, info)
+}
+
+// AuthInfoFromContext returns the authorization HMACAuth verified for the request, and
+// false when the request did not go through HMACAuth.
+func AuthInfoFromContext(ctx context.Context) (auth.AuthHeader, bool) {
+	info, ok := ctx.Value(authInfoKey).(auth.AuthHeader)
+	return info, ok
+}
+
+// RequestID returns the ID of r: the one RequestLogging stored in its context, or the
+// X-Request-ID header for handlers served without RequestLogging.
+func RequestID(r *http.Request) string {
+	if requestID := RequestIDFromContext(r.Context()); requestID != "" {
+		return requestID
+	}
+	return r.Header.Get("X-Request-ID")
+}
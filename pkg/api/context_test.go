@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"reverse-challenge-system/pkg/auth"
+)
+
+func TestRequestIDFromContext(t *testing.T) {
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Errorf("Expected no request ID in an empty context, got %q", got)
+	}
+
+	ctx := WithRequestID(context.Background(), "req_1")
+	if got := RequestIDFromContext(ctx); got != "req_1" {
+		t.Errorf("Expected req_1, got %q", got)
+	}
+
+	// A plain string key from another package does not collide with the typed key
+	ctx = context.WithValue(context.Background(), "request_id", "req_other")
+	if got := RequestIDFromContext(ctx); got != "" {
+		t.Errorf("Expected an untyped key to be ignored, got %q", got)
+	}
+}
+
+func TestAuthInfoFromContext(t *testing.T) {
+	if _, ok := AuthInfoFromContext(context.Background()); ok {
+		t.Error("Expected no auth info in an empty context")
+	}
+
+	want := auth.AuthHeader{KeyID: "test-key", Timestamp: "1700000000", Nonce: "nonce-1", Signature: "sig"}
+	got, ok := AuthInfoFromContext(WithAuthInfo(context.Background(), want))
+	if !ok || got != want {
+		t.Errorf("Expected %+v, got %+v (ok=%v)", want, got, ok)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		contextID string
+		want      string
+	}{
+		{name: "context", contextID: "req_ctx", want: "req_ctx"},
+		{name: "context wins over header", header: "req_header", contextID: "req_ctx", want: "req_ctx"},
+		{name: "header without middleware", header: "req_header", want: "req_header"},
+		{name: "neither", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			if tt.contextID != "" {
+				req = req.WithContext(WithRequestID(req.Context(), tt.contextID))
+			}
+			if got := RequestID(req); got != tt.want {
+				t.Errorf("RequestID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
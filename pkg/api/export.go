@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/logger"
+	"reverse-challenge-system/pkg/models"
+)
+
+// Record types of the POST /admin/export stream
+const (
+	ExportTypeResult  = "result"  // Record is a models.Result
+	ExportTypeWebhook = "webhook" // Record is a models.WebhookAudit
+	ExportTypeError   = "error"   // Record is a models.ErrorDetails; the export stopped early
+)
+
+// exportPageSize is how many rows of each table an export holds in memory at a time
+var exportPageSize = 500
+
+// ExportRequest selects the records streamed by POST /admin/export
+type ExportRequest struct {
+	From time.Time `json:"from"` // First creation time included (RFC 3339)
+	To   time.Time `json:"to"`   // Records created from this time on are left out (RFC 3339)
+}
+
+// ExportRecord is one line of the POST /admin/export stream
+type ExportRecord struct {
+	Type      string      `json:"type"`       // ExportTypeResult, ExportTypeWebhook or ExportTypeError
+	CreatedAt time.Time   `json:"created_at"` // Creation time of the record
+	Record    interface{} `json:"record"`     // The stored result or callback audit
+
+	id int64 // Row ID, the tie-breaker of the export cursor
+}
+
+// HandleExport serves POST /admin/export, streaming the results and callback audits created in
+// [from, to) as JSON Lines, oldest first. The tables are read in pages with a keyset cursor, so
+// a range of any size is exported without loading it into memory. If reading fails after the
+// stream has started, a final line of type "error" marks the export as incomplete.
+func (m *Middleware) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if !m.authorizeAdmin(w, r) {
+		return
+	}
+
+	requestID := RequestID(r)
+	logger := logger.WithRequestID(requestID)
+
+	store, ok := m.db.(db.ExportStore)
+	if !ok {
+		m.writeError(w, http.StatusNotImplemented, "EXPORT_UNSUPPORTED", "The database does not support exports", requestID)
+		return
+	}
+
+	var req ExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		m.writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON in request body", requestID)
+		return
+	}
+	if req.From.IsZero() || req.To.IsZero() || !req.To.After(req.From) {
+		m.writeError(w, http.StatusBadRequest, "INVALID_EXPORT_RANGE", "from and to are required and to must be after from", requestID)
+		return
+	}
+
+	ctx := r.Context()
+	results := &exportStream{cursor: db.ExportCursor{CreatedAt: req.From}, fetch: func(after db.ExportCursor) ([]ExportRecord, error) {
+		page, err := store.ListResultsAfter(ctx, after, req.To, exportPageSize)
+		records := make([]ExportRecord, len(page))
+		for i, result := range page {
+			records[i] = ExportRecord{Type: ExportTypeResult, CreatedAt: result.CreatedAt, Record: result, id: result.ID}
+		}
+		return records, err
+	}}
+	audits := &exportStream{cursor: db.ExportCursor{CreatedAt: req.From}, fetch: func(after db.ExportCursor) ([]ExportRecord, error) {
+		page, err := store.ListWebhookAuditsAfter(ctx, after, req.To, exportPageSize)
+		records := make([]ExportRecord, len(page))
+		for i, audit := range page {
+			records[i] = ExportRecord{Type: ExportTypeWebhook, CreatedAt: audit.CreatedAt, Record: audit, id: audit.ID}
+		}
+		return records, err
+	}}
+
+	// Read the first pages before answering, so an unreadable database still gets a 500
+	nextResult, err := results.peek()
+	if err == nil {
+		_, err = audits.peek()
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to read export records")
+		m.writeError(w, http.StatusInternalServerError, "EXPORT_FAILED", "Failed to read export records", requestID)
+		return
+	}
+
+	// The export may outlast the server's write timeout
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+
+	lines := 0
+	for {
+		nextAudit, err := audits.peek()
+		if err == nil {
+			nextResult, err = results.peek()
+		}
+		if err != nil {
+			logger.Error().Err(err).Int("lines", lines).Msg("Export stopped early")
+			enc.Encode(ExportRecord{Type: ExportTypeError, CreatedAt: time.Now().UTC(), Record: models.ErrorDetails{
+				Code: "EXPORT_FAILED", Message: "Failed to read export records", RequestID: requestID,
+			}})
+			return
+		}
+
+		// Merge the two streams by creation time; a result goes before an audit of the same instant
+		var next *ExportRecord
+		switch {
+		case nextResult == nil && nextAudit == nil:
+			logger.Info().Int("lines", lines).Time("from", req.From).Time("to", req.To).Msg("Export completed")
+			return
+		case nextAudit == nil || (nextResult != nil && !nextAudit.CreatedAt.Before(nextResult.CreatedAt)):
+			next = results.pop()
+		default:
+			next = audits.pop()
+		}
+
+		if err := enc.Encode(next); err != nil {
+			logger.Warn().Err(err).Int("lines", lines).Msg("Export client went away")
+			return
+		}
+		lines++
+		if lines%exportPageSize == 0 {
+			rc.Flush()
+		}
+	}
+}
+
+// exportStream pages through one table of an export, holding one page in memory at a time
+type exportStream struct {
+	fetch  func(after db.ExportCursor) ([]ExportRecord, error) // Reads the page after a cursor
+	cursor db.ExportCursor                                     // Position of the last fetched record
+	page   []ExportRecord                                      // Fetched records not yet written
+	done   bool                                                // The last page has been fetched
+}
+
+// peek returns the next record of the stream, fetching a page when needed, or nil at the end
+func (s *exportStream) peek() (*ExportRecord, error) {
+	if len(s.page) == 0 && !s.done {
+		page, err := s.fetch(s.cursor)
+		if err != nil {
+			return nil, err
+		}
+		s.page = page
+		s.done = len(page) < exportPageSize
+		if len(page) > 0 {
+			last := page[len(page)-1]
+			s.cursor = db.ExportCursor{CreatedAt: last.CreatedAt, ID: last.id}
+		}
+	}
+	if len(s.page) == 0 {
+		return nil, nil
+	}
+	return &s.page[0], nil
+}
+
+// pop removes and returns the record returned by the last peek
+func (s *exportStream) pop() *ExportRecord {
+	record := &s.page[0]
+	s.page = s.page[1:]
+	return record
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+)
+
+func postExport(handler http.Handler, adminKey string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", "/admin/export", bytes.NewBufferString(body))
+	r.Header.Set("X-Admin-Key", adminKey)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandleExport(t *testing.T) {
+	// Small pages so the range spans several of them
+	defer func(size int) { exportPageSize = size }(exportPageSize)
+	exportPageSize = 3
+
+	store, err := db.NewChallengerDB(filepath.Join(t.TempDir(), "challenger.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+
+	if err := store.CreateChallenge(ctx, &models.Challenge{ID: "ch_export", Type: "test", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+
+	// Every callback leaves a result and an audit a moment later; one of each falls outside the range
+	base := time.Now().UTC().Add(-time.Hour)
+	for i := -1; i <= 8; i++ {
+		createdAt := base.Add(time.Duration(i) * time.Minute)
+		requestID := fmt.Sprintf("req_%d", i)
+		if err := store.SaveResult(ctx, &models.Result{
+			ChallengeID: "ch_export", RequestID: requestID, Status: "success", CreatedAt: createdAt,
+		}); err != nil {
+			t.Fatalf("Failed to save result: %v", err)
+		}
+		if err := store.SaveWebhookAudit(ctx, &models.WebhookAudit{
+			ChallengeID: "ch_export", RequestID: requestID, StatusCode: 200, CreatedAt: createdAt.Add(time.Second),
+		}); err != nil {
+			t.Fatalf("Failed to save webhook audit: %v", err)
+		}
+	}
+
+	m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), store)
+	m.SetAdminKey(testAdminKey)
+	handler := m.Compression(0)(http.HandlerFunc(m.HandleExport))
+
+	body := fmt.Sprintf(`{"from":%q,"to":%q}`, base.Format(time.RFC3339Nano), base.Add(8*time.Minute).Format(time.RFC3339Nano))
+	w := postExport(handler, testAdminKey, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Expected Content-Type application/x-ndjson, got %q", got)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected a gzip response, got Content-Encoding %q", got)
+	}
+
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Failed to open gzip response: %v", err)
+	}
+	counts := map[string]int{}
+	var previous time.Time
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !json.Valid(line) {
+			t.Fatalf("Line %d is not valid JSON: %s", counts[ExportTypeResult]+counts[ExportTypeWebhook]+1, line)
+		}
+		var record struct {
+			Type      string          `json:"type"`
+			CreatedAt time.Time       `json:"created_at"`
+			Record    json.RawMessage `json:"record"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to decode line %s: %v", line, err)
+		}
+		if record.CreatedAt.Before(previous) {
+			t.Errorf("Line %s is out of order after %v", line, previous)
+		}
+		if record.CreatedAt.Before(base) || !record.CreatedAt.Before(base.Add(8*time.Minute)) {
+			t.Errorf("Line %s is outside the requested range", line)
+		}
+		previous = record.CreatedAt
+		counts[record.Type]++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+
+	// req_0 to req_7 are in range, as records of both kinds
+	if counts[ExportTypeResult] != 8 || counts[ExportTypeWebhook] != 8 || len(counts) != 2 {
+		t.Errorf("Expected 8 results and 8 webhook audits, got %v", counts)
+	}
+}
+
+func TestHandleExport_Errors(t *testing.T) {
+	challengerStore, err := db.NewChallengerDB(filepath.Join(t.TempDir(), "challenger.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { challengerStore.Close() })
+
+	tests := []struct {
+		name       string
+		store      interface{}
+		sentKey    string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "wrong admin key", store: challengerStore, sentKey: "guess", body: `{}`, wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "unsupported store", store: newAdminTestStore(t), sentKey: testAdminKey, body: `{}`, wantStatus: http.StatusNotImplemented, wantCode: "EXPORT_UNSUPPORTED"},
+		{name: "invalid JSON", store: challengerStore, sentKey: testAdminKey, body: `{`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_JSON"},
+		{name: "missing range", store: challengerStore, sentKey: testAdminKey, body: `{}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_EXPORT_RANGE"},
+		{
+			name: "empty range", store: challengerStore, sentKey: testAdminKey,
+			body:       `{"from":"2024-01-02T00:00:00Z","to":"2024-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest, wantCode: "INVALID_EXPORT_RANGE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(auth.NewHMACAuth(map[string]string{}, 300*time.Second), tt.store)
+			m.SetAdminKey(testAdminKey)
+
+			w := postExport(http.HandlerFunc(m.HandleExport), tt.sentKey, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if resp.Error.Code != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, resp.Error.Code)
+			}
+		})
+	}
+}
@@ -5,38 +5,105 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
 	"time"
 
 	"reverse-challenge-system/pkg/auth"
 	"reverse-challenge-system/pkg/db"
 	"reverse-challenge-system/pkg/logger"
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/nonce"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	MaxRequestSize = 5 * 1024 * 1024 // Maximum allowed request size: 5MB
+	MaxRequestSize = 5 * 1024 * 1024 // Default request body limit (MAX_REQUEST_BYTES): 5MB
+)
+
+// Defaults advertised by CORS until SetCORS overrides them
+var (
+	DefaultCORSMethods = []string{"GET", "POST", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Content-Type", "Authorization", "X-Request-ID"}
 )
 
 // Middleware provides HTTP middleware functionality with HMAC authentication and request logging.
-// Supports both challenger and solver database types through interface{} typing.
+// Works with any challenger or solver store through the db.NonceStore interface.
 type Middleware struct {
 	hmacAuth *auth.HMACAuth // HMAC authenticator for request verification
-	db       interface{}    // Database instance - can be either ChallengerDB or SolverDB
+	db       interface{}    // Database instance - nonce checks apply when it implements db.NonceStore
+	limiter  *rateLimiter   // Per-client rate limiter; nil disables RateLimit
+	adminKey string         // Key required by the /admin endpoints; empty disables them
+
+	trustedProxies []*net.IPNet // Peers whose X-Forwarded-For entries RateLimit honors
+
+	keyRolesMu sync.RWMutex
+	keyRoles   map[string]string // Primary key ID -> peer role, consulted by HMACAuthForRole
+
+	backupDir    string // Directory POST /admin/backup writes snapshots to; empty disables it
+	backupPrefix string // Snapshot file name prefix, e.g. "challenger"
+
+	nonces   nonce.Store   // Replay protection; nil skips nonce checks
+	nonceTTL time.Duration // How long a nonce is remembered
+
+	corsOrigins   map[string]bool // Origins echoed back by CORS
+	corsAnyOrigin bool            // "*" was configured: any origin is allowed
+	corsMethods   string          // Access-Control-Allow-Methods value
+	corsHeaders   string          // Access-Control-Allow-Headers value
 }
 
 // NewMiddleware creates a new middleware instance with HMAC authentication and database.
-// The database parameter can be either *db.ChallengerDB or *db.SolverDB.
+// The database parameter is typically a db.ChallengerStore or db.SolverStore.
+// Nonces are kept in the database when it implements db.NonceStore; see SetNonceStore.
 func NewMiddleware(hmacAuth *auth.HMACAuth, database interface{}) *Middleware {
-	return &Middleware{
-		hmacAuth: hmacAuth,
-		db:       database,
+	m := &Middleware{
+		hmacAuth:    hmacAuth,
+		db:          database,
+		nonceTTL:    2 * auth.DefaultClockSkew * time.Second,
+		corsMethods: strings.Join(DefaultCORSMethods, ", "),
+		corsHeaders: strings.Join(DefaultCORSHeaders, ", "),
+	}
+	if store, ok := database.(db.NonceStore); ok {
+		m.nonces = nonce.NewDBStore(store)
+	}
+	return m
+}
+
+// SetNonceStore replaces the store used for replay protection. ttl should cover the
+// HMAC clock skew window on both sides of now, i.e. twice the allowed skew.
+func (m *Middleware) SetNonceStore(store nonce.Store, ttl time.Duration) {
+	m.nonces = store
+	m.nonceTTL = ttl
+}
+
+// SetCORS configures the CORS middleware. Only listed origins are allowed; "*" is an explicit
+// opt-in for any origin. Empty methods or headers keep the defaults.
+func (m *Middleware) SetCORS(origins, methods, headers []string) {
+	m.corsOrigins = make(map[string]bool, len(origins))
+	m.corsAnyOrigin = false
+	for _, origin := range origins {
+		if origin == "*" {
+			m.corsAnyOrigin = true
+			continue
+		}
+		m.corsOrigins[origin] = true
+	}
+
+	if len(methods) > 0 {
+		m.corsMethods = strings.Join(methods, ", ")
+	}
+	if len(headers) > 0 {
+		m.corsHeaders = strings.Join(headers, ", ")
 	}
 }
 
@@ -47,13 +114,14 @@ func (m *Middleware) RequestLogging(next http.Handler) http.Handler {
 		start := time.Now()
 
 		// Create request ID if not present
-		requestID := r.Header.Get("X-Request-ID")
+		requestID := RequestID(r)
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
 
-		// Add to context for later use
-		r.Header.Set("X-Request-ID", requestID)
+		// Handlers read it from the context; clients get it back in the response
+		r = r.WithContext(WithRequestID(r.Context(), requestID))
+		w.Header().Set("X-Request-ID", requestID)
 
 		// Create response writer wrapper to capture status code
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: 200}
@@ -79,20 +147,237 @@ func (m *Middleware) RequestLogging(next http.Handler) http.Handler {
 	})
 }
 
-// SizeLimit middleware restricts request body size to prevent resource exhaustion.
-// Rejects requests larger than MaxRequestSize (5MB) with appropriate error response.
-func (m *Middleware) SizeLimit(next http.Handler) http.Handler {
+// Recover middleware turns a panicking handler into a 500 response instead of letting it
+// take down the server. The panic and stack trace are logged with the request ID.
+// Register it first so it also covers the other middleware.
+func (m *Middleware) Recover(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestSize)
-		next.ServeHTTP(w, r)
+		wrapped := &recoverWriter{ResponseWriter: w}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// Deliberate aborts are handled by net/http itself
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			// RequestLogging runs inside Recover, so its ID is read back from the response
+			requestID := wrapped.Header().Get("X-Request-ID")
+			if requestID == "" {
+				requestID = RequestID(r)
+			}
+			logger := logger.WithRequestID(requestID)
+			logger.Error().
+				Interface("panic", rec).
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Bytes("stack", debug.Stack()).
+				Msg("Recovered from handler panic")
+
+			// A partially written response cannot be replaced
+			if wrapped.wroteHeader {
+				return
+			}
+			m.writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error", requestID)
+		}()
+
+		next.ServeHTTP(wrapped, r)
 	})
 }
 
+// Timeout middleware bounds handler execution. The handler runs with a context that expires
+// after d; if it has not finished by then the client gets 503 REQUEST_TIMEOUT and anything the
+// handler writes afterwards is discarded. Responses are buffered so a timeout can still send a
+// clean error. A non-positive d disables the timeout.
+func (m *Middleware) Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{header: make(http.Header), statusCode: http.StatusOK}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+
+			go func() {
+				defer func() {
+					if rec := recover(); rec != nil {
+						panicked <- rec
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case rec := <-panicked:
+				// Re-raise on the serving goroutine so Recover can handle it
+				panic(rec)
+
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				for key, values := range tw.header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(tw.statusCode)
+				w.Write(tw.body.Bytes())
+
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+
+				requestID := RequestID(r)
+				logger := logger.WithRequestID(requestID)
+				logger.Warn().
+					Err(ctx.Err()).
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Dur("timeout", d).
+					Msg("Request timed out")
+
+				m.writeError(w, http.StatusServiceUnavailable, "REQUEST_TIMEOUT", "Request timed out", requestID)
+			}
+		})
+	}
+}
+
+// SizeLimitN returns middleware that restricts request bodies to maxBytes to prevent resource
+// exhaustion. Larger bodies are rejected with 413 PAYLOAD_TOO_LARGE before the handler runs;
+// the body is read up front and handed on as a buffered copy. Limits nest, so a subrouter can
+// tighten the router-wide limit but not raise it; use SizeLimitFor for a route limit that
+// replaces it. A non-positive maxBytes disables the limit.
+func (m *Middleware) SizeLimitN(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxBytes <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				m.writePayloadTooLarge(w, r, maxBytes)
+				return
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+				if err != nil {
+					var tooLarge *http.MaxBytesError
+					if errors.As(err, &tooLarge) {
+						m.writePayloadTooLarge(w, r, maxBytes)
+						return
+					}
+
+					requestID := RequestID(r)
+					logger := logger.WithRequestID(requestID)
+					logger.Error().Err(err).Msg("Failed to read request body")
+					m.writeError(w, http.StatusBadRequest, "READ_ERROR", "Failed to read request body", requestID)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SizeLimitFor is SizeLimitN with per-route limits. A request whose path is, or is below, a key
+// of routes is limited to that value instead of maxBytes, so an override takes precedence and
+// may raise the limit as well as lower it. Zero route limits fall back to maxBytes.
+func (m *Middleware) SizeLimitFor(maxBytes int64, routes map[string]int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fallback := m.SizeLimitN(maxBytes)(next)
+		limited := make(map[string]http.Handler, len(routes))
+		for prefix, limit := range routes {
+			if limit != 0 {
+				limited[strings.TrimSuffix(prefix, "/")] = m.SizeLimitN(limit)(next)
+			}
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The longest matching prefix wins
+			handler, matched := fallback, ""
+			for prefix, h := range limited {
+				if len(prefix) > len(matched) && (r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, prefix+"/")) {
+					handler, matched = h, prefix
+				}
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writePayloadTooLarge rejects a request whose body exceeds maxBytes
+func (m *Middleware) writePayloadTooLarge(w http.ResponseWriter, r *http.Request, maxBytes int64) {
+	requestID := RequestID(r)
+	logger := logger.WithRequestID(requestID)
+	logger.Warn().
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Int64("content_length", r.ContentLength).
+		Int64("max_bytes", maxBytes).
+		Msg("Request body too large")
+
+	m.writeError(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE",
+		fmt.Sprintf("Request body exceeds %d bytes", maxBytes), requestID)
+}
+
 // HMACAuth middleware validates HMAC-SHA256 signatures and prevents replay attacks.
 // Checks authorization headers, verifies signatures, and tracks nonces.
+// Any known key is accepted; use HMACAuthForKeys to restrict a route to specific peers.
 func (m *Middleware) HMACAuth(next http.Handler) http.Handler {
+	return m.hmacAuthHandler(next, nil)
+}
+
+// HMACAuthForKeys returns HMACAuth restricted to the given key IDs. A correctly signed
+// request using any other known key is rejected with 403 KEY_NOT_ALLOWED.
+func (m *Middleware) HMACAuthForKeys(keyIDs ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(keyIDs))
+	for _, keyID := range keyIDs {
+		allowed[keyID] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return m.hmacAuthHandler(next, func(keyID string) bool { return allowed[keyID] })
+	}
+}
+
+// SetKeyRoles replaces the primary key IDs and the peer role each belongs to (see
+// config.GetKeyRoles). It is safe to call while serving, e.g. after a configuration reload.
+func (m *Middleware) SetKeyRoles(roles map[string]string) {
+	m.keyRolesMu.Lock()
+	defer m.keyRolesMu.Unlock()
+	m.keyRoles = roles
+}
+
+// HMACAuthForRole returns HMACAuth for a route called by the peer with role. Keys without a
+// role - rotation keys and keys added through /admin/keys - are accepted too; a primary key
+// of another peer is rejected with 403 KEY_NOT_ALLOWED. Roles are looked up on every request,
+// so SetKeyRoles and newly added keys take effect without a restart.
+func (m *Middleware) HMACAuthForRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return m.hmacAuthHandler(next, func(keyID string) bool {
+			m.keyRolesMu.RLock()
+			defer m.keyRolesMu.RUnlock()
+			keyRole, primary := m.keyRoles[keyID]
+			return !primary || keyRole == role
+		})
+	}
+}
+
+// hmacAuthHandler implements HMACAuth. A nil allowed func accepts every known key.
+func (m *Middleware) hmacAuthHandler(next http.Handler, allowed func(keyID string) bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestID := r.Header.Get("X-Request-ID")
+		requestID := RequestID(r)
 		logger := logger.WithRequestID(requestID)
 
 		// Check Authorization header
@@ -121,43 +406,67 @@ func (m *Middleware) HMACAuth(next http.Handler) http.Handler {
 		// Restore body for later use
 		r.Body = io.NopCloser(bytes.NewReader(body))
 
-		// Check for nonce replay
-		if err := m.checkNonce(authInfo.Nonce); err != nil {
-			logger.Error().Err(err).Str("nonce", authInfo.Nonce).Msg("Nonce replay detected")
-			m.writeError(w, http.StatusUnauthorized, "REPLAY_ATTACK", "Nonce already seen", requestID)
-			return
-		}
-
 		// Verify signature
 		if err := m.hmacAuth.VerifySignature(r.Method, r.URL.EscapedPath(), body, authInfo); err != nil {
 			logger.Error().Err(err).Str("key_id", authInfo.KeyID).Msg("Signature verification failed")
+			if errors.Is(err, auth.ErrKeyNotValid) {
+				m.writeError(w, http.StatusUnauthorized, "KEY_NOT_VALID", "Signing key is expired or not yet valid", requestID)
+				return
+			}
 			m.writeError(w, http.StatusUnauthorized, "INVALID_SIGNATURE", "Signature verification failed", requestID)
 			return
 		}
 
-		// Save nonce to prevent replay
-		if err := m.saveNonce(authInfo.Nonce); err != nil {
-			logger.Error().Err(err).Msg("Failed to save nonce")
-			// Continue anyway - this is not critical
+		// A valid signature from a peer that may not call this route
+		if allowed != nil && !allowed(authInfo.KeyID) {
+			logger.Warn().Str("key_id", authInfo.KeyID).Str("path", r.URL.Path).Msg("Signing key not allowed for this route")
+			m.writeError(w, http.StatusForbidden, "KEY_NOT_ALLOWED", "Signing key is not allowed for this endpoint", requestID)
+			return
 		}
 
-		// Add auth info to headers for handlers to use
-		r.Header.Set("X-Auth-KeyID", authInfo.KeyID)
-		r.Header.Set("X-Auth-Timestamp", authInfo.Timestamp)
-		r.Header.Set("X-Auth-Nonce", authInfo.Nonce)
+		// Record the nonce and reject replays in one step, so two concurrent copies of a
+		// request cannot both pass. Done after verification so unsigned traffic cannot fill the store.
+		firstSeen, err := m.claimNonce(r.Context(), authInfo.Nonce)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to check nonce")
+			m.writeError(w, http.StatusServiceUnavailable, "NONCE_STORE_UNAVAILABLE", "Replay protection is unavailable", requestID)
+			return
+		}
+		if !firstSeen {
+			logger.Error().Str("nonce", authInfo.Nonce).Msg("Nonce replay detected")
+			m.writeError(w, http.StatusUnauthorized, "REPLAY_ATTACK", "Nonce already seen", requestID)
+			return
+		}
 
 		logger.Debug().Str("key_id", authInfo.KeyID).Msg("Authentication successful")
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(WithAuthInfo(r.Context(), *authInfo)))
 	})
 }
 
-// CORS middleware adds Cross-Origin Resource Sharing headers.
-// Allows cross-origin requests for web-based challenger interfaces.
+// CORS middleware adds Cross-Origin Resource Sharing headers for origins allowed by SetCORS.
+// A matching Origin is echoed back (with credentials allowed); other origins get no CORS headers.
+// In wildcard mode every request gets "Access-Control-Allow-Origin: *".
 func (m *Middleware) CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		origin := r.Header.Get("Origin")
+		allowed := true
+		switch {
+		case m.corsAnyOrigin:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case origin != "" && m.corsOrigins[origin]:
+			// The response depends on the Origin header, so caches must key on it
+			w.Header().Add("Vary", "Origin")
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		default:
+			w.Header().Add("Vary", "Origin")
+			allowed = false
+		}
+
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Methods", m.corsMethods)
+			w.Header().Set("Access-Control-Allow-Headers", m.corsHeaders)
+		}
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -182,40 +491,13 @@ func (m *Middleware) HTTPSOnly(next http.Handler) http.Handler {
 	})
 }
 
-// checkNonce verifies if a nonce has been seen before to prevent replay attacks.
-// Works with both challenger and solver databases through type assertion.
-func (m *Middleware) checkNonce(nonce string) error {
-	switch db := m.db.(type) {
-	case *db.ChallengerDB:
-		seen, err := db.HasSeenNonce(nonce)
-		if err != nil {
-			return err
-		}
-		if seen {
-			return fmt.Errorf("nonce already seen")
-		}
-	case *db.SolverDB:
-		seen, err := db.HasSeenNonce(nonce)
-		if err != nil {
-			return err
-		}
-		if seen {
-			return fmt.Errorf("nonce already seen")
-		}
-	}
-	return nil
-}
-
-// saveNonce stores a nonce in the database to prevent future replay attacks.
-// Handles both challenger and solver database types.
-func (m *Middleware) saveNonce(nonce string) error {
-	switch db := m.db.(type) {
-	case *db.ChallengerDB:
-		return db.SaveNonce(nonce)
-	case *db.SolverDB:
-		return db.SaveNonce(nonce)
+// claimNonce records nonce and reports whether it had not been seen within the nonce ttl.
+// Every nonce is new when no store is configured.
+func (m *Middleware) claimNonce(ctx context.Context, nonceValue string) (bool, error) {
+	if m.nonces == nil {
+		return true, nil
 	}
-	return nil
+	return m.nonces.CheckAndSet(ctx, nonceValue, m.nonceTTL)
 }
 
 // writeError sends a standardized JSON error response to the client.
@@ -248,6 +530,68 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// recoverWriter records whether a response has been started,
+// so Recover knows if it can still send an error response.
+type recoverWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoverWriter) WriteHeader(code int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoverWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *recoverWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// timeoutWriter buffers a handler's response for the Timeout middleware.
+// Writes after the timeout fail with http.ErrHandlerTimeout.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.statusCode = code
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.body.Write(b)
+}
+
 // HealthCheck provides a simple health status endpoint.
 // Returns 200 OK with status message for load balancer health checks.
 func HealthCheck(w http.ResponseWriter, r *http.Request) {
@@ -256,29 +600,44 @@ func HealthCheck(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
+// SuiHealthChecker confirms the Sui RPC is reachable. Implemented by sui.TransactionBuilder.
+type SuiHealthChecker interface {
+	CheckRPC(ctx context.Context) error
+}
+
 // ReadinessCheck provides a readiness probe that verifies database connectivity.
 // Returns 503 Service Unavailable if database operations fail.
 func ReadinessCheck(database interface{}) http.HandlerFunc {
+	return ReadinessCheckWithSui(database, nil, 0)
+}
+
+// ReadinessCheckWithSui extends ReadinessCheck with a Sui RPC probe bounded by timeout, so a
+// service that uploads commitments is not reported ready while the RPC is down. A nil checker
+// skips the probe.
+func ReadinessCheckWithSui(database interface{}, checker SuiHealthChecker, timeout time.Duration) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Simple DB ping check
 		status := "ok"
 		statusCode := http.StatusOK
 
-		switch dbInstance := database.(type) {
-		case *db.ChallengerDB:
+		if store, ok := database.(db.NonceStore); ok {
 			// Try a simple nonce check to verify DB is working
-			_, err := dbInstance.HasSeenNonce("readiness-check")
+			_, err := store.HasSeenNonce(r.Context(), "readiness-check")
 			if err != nil {
 				status = "database connection failed"
 				statusCode = http.StatusServiceUnavailable
 				log.Error().Err(err).Msg("Database readiness check failed")
 			}
-		case *db.SolverDB:
-			_, err := dbInstance.HasSeenNonce("readiness-check")
+		}
+
+		if checker != nil && statusCode == http.StatusOK {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			err := checker.CheckRPC(ctx)
+			cancel()
 			if err != nil {
-				status = "database connection failed"
+				status = "sui rpc unreachable"
 				statusCode = http.StatusServiceUnavailable
-				log.Error().Err(err).Msg("Database readiness check failed")
+				log.Error().Err(err).Dur("timeout", timeout).Msg("Sui readiness check failed")
 			}
 		}
 
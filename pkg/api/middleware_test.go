@@ -2,19 +2,28 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/nonce"
 
 	"github.com/google/uuid"
 )
@@ -32,7 +41,7 @@ func NewMockDB() *MockDB {
 	}
 }
 
-func (m *MockDB) HasSeenNonce(nonce string) (bool, error) {
+func (m *MockDB) HasSeenNonce(ctx context.Context, nonce string) (bool, error) {
 	// Check if this nonce is marked as a replay test nonce
 	if m.replayNonces[nonce] {
 		return true, nil
@@ -40,7 +49,7 @@ func (m *MockDB) HasSeenNonce(nonce string) (bool, error) {
 	return m.nonces[nonce], nil
 }
 
-func (m *MockDB) SaveNonce(nonce string) error {
+func (m *MockDB) SaveNonce(ctx context.Context, nonce string) error {
 	m.nonces[nonce] = true
 	return nil
 }
@@ -57,7 +66,9 @@ func TestMiddleware_RequestLogging(t *testing.T) {
 	middleware := NewMiddleware(hmacAuth, mockDB)
 
 	// Create a test handler
+	var handlerRequestID string
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = RequestIDFromContext(r.Context())
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("test response"))
 	})
@@ -77,10 +88,35 @@ func TestMiddleware_RequestLogging(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 
-	// Check that request ID was added
-	requestID := req.Header.Get("X-Request-ID")
-	if requestID == "" {
-		t.Error("Expected X-Request-ID to be added to request")
+	// Check that request ID was added to the context and echoed to the client
+	if handlerRequestID == "" {
+		t.Error("Expected a request ID in the request context")
+	}
+	if got := w.Header().Get("X-Request-ID"); got != handlerRequestID {
+		t.Errorf("Expected response X-Request-ID %q, got %q", handlerRequestID, got)
+	}
+	// The inbound headers are left untouched
+	if req.Header.Get("X-Request-ID") != "" {
+		t.Error("Expected the request headers not to be modified")
+	}
+}
+
+func TestMiddleware_RequestLogging_KeepsClientRequestID(t *testing.T) {
+	middleware := NewMiddleware(auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second), NewMockDB())
+
+	var handlerRequestID string
+	handler := middleware.RequestLogging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = RequestID(r)
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-ID", "req_client")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if handlerRequestID != "req_client" || w.Header().Get("X-Request-ID") != "req_client" {
+		t.Errorf("Expected the client's request ID to be kept, got %q in the handler and %q in the response",
+			handlerRequestID, w.Header().Get("X-Request-ID"))
 	}
 }
 
@@ -95,7 +131,7 @@ func TestMiddleware_SizeLimit(t *testing.T) {
 		w.Write([]byte("OK"))
 	})
 
-	handler := middleware.SizeLimit(testHandler)
+	handler := middleware.SizeLimitN(MaxRequestSize)(testHandler)
 
 	// Test normal sized request
 	normalBody := strings.Repeat("a", 1000) // 1KB
@@ -107,9 +143,124 @@ func TestMiddleware_SizeLimit(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("Expected status 200 for normal request, got %d", w.Code)
 	}
+}
+
+func TestMiddleware_SizeLimitN_RejectsOversizedBody(t *testing.T) {
+	middleware := NewMiddleware(auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second), NewMockDB())
+
+	var received string
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Handler failed to read body: %v", err)
+		}
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// The callback route tightens the router-wide limit
+	handler := middleware.SizeLimitN(1024)(middleware.SizeLimitN(100)(testHandler))
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64 // -1 simulates a chunked body of unknown length
+		wantStatus    int
+	}{
+		{name: "at the limit", body: strings.Repeat("a", 100), contentLength: 100, wantStatus: http.StatusOK},
+		{name: "declared length over the limit", body: strings.Repeat("a", 101), contentLength: 101, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "chunked body over the limit", body: strings.Repeat("a", 500), contentLength: -1, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "over the outer limit", body: strings.Repeat("a", 2000), contentLength: -1, wantStatus: http.StatusRequestEntityTooLarge},
+	}
 
-	// Test oversized request - we can't easily test this without actually hitting the limit
-	// since MaxBytesReader works at the connection level
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest("POST", "/callback/test", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+			req.Header.Set("X-Request-ID", "req-413")
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if received != tt.body {
+					t.Errorf("Expected handler to receive the full body, got %d bytes", len(received))
+				}
+				return
+			}
+
+			if received != "" {
+				t.Error("Expected handler not to run for an oversized body")
+			}
+			var errorResp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Error.Code != "PAYLOAD_TOO_LARGE" {
+				t.Errorf("Expected error code PAYLOAD_TOO_LARGE, got %s", errorResp.Error.Code)
+			}
+			if errorResp.Error.RequestID != "req-413" {
+				t.Errorf("Expected request ID req-413, got %s", errorResp.Error.RequestID)
+			}
+		})
+	}
+}
+
+func TestMiddleware_SizeLimitN_Disabled(t *testing.T) {
+	middleware := NewMiddleware(auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second), NewMockDB())
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.SizeLimitN(0)(testHandler)
+
+	req := httptest.NewRequest("POST", "/solve", strings.NewReader(strings.Repeat("a", MaxRequestSize+1)))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected a zero limit to disable the check, got %d", w.Code)
+	}
+}
+
+func TestMiddleware_SizeLimitFor(t *testing.T) {
+	middleware := NewMiddleware(auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second), NewMockDB())
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.SizeLimitFor(100, map[string]int64{"/solve": 1000, "/callback": 10, "/stats": 0})(testHandler)
+
+	tests := []struct {
+		name       string
+		path       string
+		bodySize   int
+		wantStatus int
+	}{
+		{name: "override raises the limit", path: "/solve", bodySize: 500, wantStatus: http.StatusOK},
+		{name: "override applies below its prefix", path: "/solve/c1", bodySize: 500, wantStatus: http.StatusOK},
+		{name: "override still limits", path: "/solve", bodySize: 1001, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "override lowers the limit", path: "/callback/c1", bodySize: 50, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "prefix only matches whole segments", path: "/solvers/0xabc/commitments", bodySize: 500, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "zero override uses the global limit", path: "/stats", bodySize: 500, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "other routes use the global limit", path: "/admin/keys", bodySize: 50, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(strings.Repeat("a", tt.bodySize)))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
 }
 
 func TestMiddleware_CORS(t *testing.T) {
@@ -117,6 +268,7 @@ func TestMiddleware_CORS(t *testing.T) {
 	hmacAuth := auth.NewHMACAuth(secrets, 300*time.Second)
 	mockDB := NewMockDB()
 	middleware := NewMiddleware(hmacAuth, mockDB)
+	middleware.SetCORS([]string{"*"}, nil, nil)
 
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -164,6 +316,73 @@ func TestMiddleware_CORS(t *testing.T) {
 	}
 }
 
+func TestMiddleware_CORS_AllowedOrigin(t *testing.T) {
+	middleware := NewMiddleware(nil, nil)
+	middleware.SetCORS([]string{"https://dashboard.example.com"}, []string{"GET", "OPTIONS"}, []string{"Content-Type"})
+
+	handler := middleware.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("OPTIONS", "/callback/ch_1", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://dashboard.example.com" {
+		t.Errorf("Expected origin to be echoed back, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, OPTIONS" {
+		t.Errorf("Expected configured methods, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type" {
+		t.Errorf("Expected configured headers, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestMiddleware_CORS_DisallowedOrigin(t *testing.T) {
+	middleware := NewMiddleware(nil, nil)
+	middleware.SetCORS([]string{"https://dashboard.example.com"}, nil, nil)
+
+	handler := middleware.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, origin := range []string{"https://evil.example.com", ""} {
+		req := httptest.NewRequest("GET", "/solve", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers"} {
+			if got := w.Header().Get(header); got != "" {
+				t.Errorf("Origin %q: expected no %s header, got %q", origin, header, got)
+			}
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("Origin %q: expected request to pass through, got %d", origin, w.Code)
+		}
+	}
+
+	// Without SetCORS no origin is allowed
+	handler = NewMiddleware(nil, nil).CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest("GET", "/solve", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no CORS header by default, got %q", got)
+	}
+}
+
 func TestMiddleware_HMACAuth_MissingAuthHeader(t *testing.T) {
 	secrets := map[string]string{"test-key": "test-secret"}
 	hmacAuth := auth.NewHMACAuth(secrets, 300*time.Second)
@@ -242,15 +461,16 @@ func TestMiddleware_HMACAuth_ValidRequest(t *testing.T) {
 	middleware := NewMiddleware(hmacAuth, mockDB)
 
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check that auth info was added to headers
-		if r.Header.Get("X-Auth-KeyID") == "" {
-			t.Error("Expected X-Auth-KeyID header to be set")
+		// Check that auth info was added to the context
+		info, ok := AuthInfoFromContext(r.Context())
+		if !ok {
+			t.Fatal("Expected auth info in the request context")
 		}
-		if r.Header.Get("X-Auth-Timestamp") == "" {
-			t.Error("Expected X-Auth-Timestamp header to be set")
+		if info.KeyID != "test-key" || info.Timestamp == "" || info.Nonce == "" {
+			t.Errorf("Unexpected auth info: %+v", info)
 		}
-		if r.Header.Get("X-Auth-Nonce") == "" {
-			t.Error("Expected X-Auth-Nonce header to be set")
+		if r.Header.Get("X-Auth-KeyID") != "" {
+			t.Error("Expected the request headers not to be modified")
 		}
 
 		w.WriteHeader(http.StatusOK)
@@ -281,11 +501,161 @@ func TestMiddleware_HMACAuth_ValidRequest(t *testing.T) {
 	}
 }
 
+// signedRequest builds a request to /test signed by hmacAuth with the given nonce
+func signedRequest(hmacAuth *auth.HMACAuth, nonceValue string) *http.Request {
+	body := []byte(`{"test": "data"}`)
+	req := httptest.NewRequest("POST", "/test", bytes.NewReader(body))
+	req.Header.Set("Authorization", hmacAuth.CreateAuthHeader("POST", "/test", body, "test-key", nonceValue))
+	return req
+}
+
 func TestMiddleware_HMACAuth_NonceReplay(t *testing.T) {
-	// This test demonstrates the nonce replay detection logic
-	// Note: Full replay detection requires actual database persistence
-	// which is tested in the database integration tests
-	t.Skip("Nonce replay detection requires actual database - covered in integration tests")
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+	middleware.SetNonceStore(nonce.NewMemoryStore(), time.Minute)
+	handler := middleware.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	nonceValue := uuid.New().String()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(hmacAuth, nonceValue))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the first request to succeed, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(hmacAuth, nonceValue))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected the replay to be rejected with 401, got %d", w.Code)
+	}
+	var errorResp models.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if errorResp.Error.Code != "REPLAY_ATTACK" {
+		t.Errorf("Expected error code REPLAY_ATTACK, got %s", errorResp.Error.Code)
+	}
+}
+
+func TestMiddleware_HMACAuth_ConcurrentReplay(t *testing.T) {
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+	middleware.SetNonceStore(nonce.NewMemoryStore(), time.Minute)
+
+	var handled int32
+	handler := middleware.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&handled, 1)
+	}))
+
+	// Copies of one request racing each other: only one may get through
+	nonceValue := uuid.New().String()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), signedRequest(hmacAuth, nonceValue))
+		}()
+	}
+	wg.Wait()
+
+	if handled != 1 {
+		t.Errorf("Expected exactly one copy to be handled, got %d", handled)
+	}
+}
+
+func TestMiddleware_HMACAuth_ConcurrentReplayDatabase(t *testing.T) {
+	stores := map[string]func(t *testing.T) interface{}{
+		"challenger": func(t *testing.T) interface{} {
+			store, err := db.NewChallengerDB(filepath.Join(t.TempDir(), "challenger.db"))
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			t.Cleanup(func() { store.Close() })
+			return store
+		},
+		"solver": func(t *testing.T) interface{} { return newAdminTestStore(t) },
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+			// The default nonce store is the service database
+			middleware := NewMiddleware(hmacAuth, open(t))
+
+			var handled int32
+			handler := middleware.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&handled, 1)
+			}))
+
+			nonceValue := uuid.New().String()
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					handler.ServeHTTP(httptest.NewRecorder(), signedRequest(hmacAuth, nonceValue))
+				}()
+			}
+			wg.Wait()
+
+			if handled != 1 {
+				t.Errorf("Expected exactly one copy to be handled, got %d", handled)
+			}
+		})
+	}
+}
+
+func TestMiddleware_HMACAuth_InvalidSignatureKeepsNonce(t *testing.T) {
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+	store := nonce.NewMemoryStore()
+	middleware.SetNonceStore(store, time.Minute)
+	handler := middleware.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	nonceValue := uuid.New().String()
+	forged := auth.NewHMACAuth(map[string]string{"test-key": "wrong-secret"}, 300*time.Second)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(forged, nonceValue))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected a forged request to be rejected, got %d", w.Code)
+	}
+	if store.Len() != 0 {
+		t.Errorf("Expected an unverified request not to record its nonce, got %d", store.Len())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(hmacAuth, nonceValue))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the genuine request to succeed, got %d", w.Code)
+	}
+}
+
+// failingNonceStore is a nonce.Store whose backend is unreachable
+type failingNonceStore struct{}
+
+func (failingNonceStore) CheckAndSet(ctx context.Context, nonceValue string, ttl time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (failingNonceStore) Close() error { return nil }
+
+func TestMiddleware_HMACAuth_NonceStoreUnavailable(t *testing.T) {
+	hmacAuth := auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+	middleware.SetNonceStore(failingNonceStore{}, time.Minute)
+	handler := middleware.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the handler not to run without replay protection")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(hmacAuth, uuid.New().String()))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
 }
 
 func TestMiddleware_HMACAuth_ExpiredTimestamp(t *testing.T) {
@@ -343,6 +713,137 @@ func TestMiddleware_HMACAuth_ExpiredTimestamp(t *testing.T) {
 	}
 }
 
+func TestMiddleware_HMACAuth_ExpiredKey(t *testing.T) {
+	hmacAuth := auth.NewHMACAuth(map[string]string{}, 300*time.Second)
+	hmacAuth.AddSecretWithValidity("retired-key", "test-secret", time.Time{}, time.Now().Add(-time.Minute))
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+
+	handler := middleware.HMACAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected handler not to run for a retired key")
+	}))
+
+	body := []byte(`{"test": "data"}`)
+	req := httptest.NewRequest("POST", "/test", bytes.NewReader(body))
+	req.Header.Set("Authorization", hmacAuth.CreateAuthHeader("POST", "/test", body, "retired-key", uuid.New().String()))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a retired key, got %d", w.Code)
+	}
+
+	var errorResp models.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if errorResp.Error.Code != "KEY_NOT_VALID" {
+		t.Errorf("Expected error code 'KEY_NOT_VALID', got '%s'", errorResp.Error.Code)
+	}
+}
+
+func TestMiddleware_HMACAuthForKeys(t *testing.T) {
+	// Both keys share a secret, as with SHARED_SECRET_KEY
+	secrets := map[string]string{"chal-kid-1": "shared-secret", "solver-kid-1": "shared-secret"}
+	hmacAuth := auth.NewHMACAuth(secrets, 300*time.Second)
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+
+	tests := []struct {
+		name       string
+		keyID      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "allowed key", keyID: "chal-kid-1", wantStatus: http.StatusOK},
+		{name: "other known key", keyID: "solver-kid-1", wantStatus: http.StatusForbidden, wantCode: "KEY_NOT_ALLOWED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.HMACAuthForKeys("chal-kid-1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			body := []byte(`{"challenge_id": "c1"}`)
+			req := httptest.NewRequest("POST", "/callback/c1", bytes.NewReader(body))
+			req.Header.Set("Authorization", hmacAuth.CreateAuthHeader("POST", "/callback/c1", body, tt.keyID, uuid.New().String()))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var errorResp models.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if errorResp.Error.Code != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, errorResp.Error.Code)
+			}
+		})
+	}
+}
+
+func TestMiddleware_HMACAuthForRole_Rotation(t *testing.T) {
+	secrets := map[string]string{"chal-kid-1": "shared-secret", "solver-kid-1": "shared-secret"}
+	hmacAuth := auth.NewHMACAuth(secrets, 300*time.Second)
+	middleware := NewMiddleware(hmacAuth, NewMockDB())
+	middleware.SetKeyRoles((&config.Config{ChalHMACKeyID: "chal-kid-1", SolverHMACKeyID: "solver-kid-1"}).GetKeyRoles())
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	solve := middleware.HMACAuthForRole(config.KeyRoleChallenger)(ok)
+	callback := middleware.HMACAuthForRole(config.KeyRoleSolver)(ok)
+
+	status := func(handler http.Handler, path, keyID string) int {
+		body := []byte(`{"challenge_id": "c1"}`)
+		req := httptest.NewRequest("POST", path, bytes.NewReader(body))
+		req.Header.Set("Authorization", hmacAuth.CreateAuthHeader("POST", path, body, keyID, uuid.New().String()))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if got := status(callback, "/callback/c1", "solver-kid-1"); got != http.StatusForbidden {
+		t.Errorf("Expected the challenger's key to be refused on /callback, got %d", got)
+	}
+	if got := status(solve, "/solve", "chal-kid-1"); got != http.StatusForbidden {
+		t.Errorf("Expected the solver's key to be refused on /solve, got %d", got)
+	}
+
+	// A rotation key added while serving, as by CHAL_HMAC_KEYS or POST /admin/keys
+	hmacAuth.AddSecret("rotation-kid-2", "next-secret")
+	if got := status(solve, "/solve", "rotation-kid-2"); got != http.StatusOK {
+		t.Errorf("Expected rotation key on /solve to be accepted, got %d", got)
+	}
+	if got := status(callback, "/callback/c1", "rotation-kid-2"); got != http.StatusOK {
+		t.Errorf("Expected rotation key on /callback to be accepted, got %d", got)
+	}
+
+	// Rotate both primary key IDs, as after a SIGHUP reload
+	hmacAuth.AddSecret("chal-kid-2", "next-secret")
+	hmacAuth.AddSecret("solver-kid-2", "next-secret")
+	middleware.SetKeyRoles((&config.Config{ChalHMACKeyID: "chal-kid-2", SolverHMACKeyID: "solver-kid-2"}).GetKeyRoles())
+
+	if got := status(solve, "/solve", "solver-kid-2"); got != http.StatusOK {
+		t.Errorf("Expected new challenger key on /solve to be accepted, got %d", got)
+	}
+	if got := status(callback, "/callback/c1", "chal-kid-2"); got != http.StatusOK {
+		t.Errorf("Expected new solver key on /callback to be accepted, got %d", got)
+	}
+	if got := status(solve, "/solve", "chal-kid-2"); got != http.StatusForbidden {
+		t.Errorf("Expected new solver key to be refused on /solve, got %d", got)
+	}
+	if got := status(callback, "/callback/c1", "solver-kid-2"); got != http.StatusForbidden {
+		t.Errorf("Expected new challenger key to be refused on /callback, got %d", got)
+	}
+}
+
 func TestMiddleware_HTTPSOnly(t *testing.T) {
 	secrets := map[string]string{"test-key": "test-secret"}
 	hmacAuth := auth.NewHMACAuth(secrets, 300*time.Second)
@@ -438,6 +939,179 @@ func TestReadinessCheck(t *testing.T) {
 	}
 }
 
+// mockSuiChecker answers CheckRPC with err, or blocks until the context ends when hang is set
+type mockSuiChecker struct {
+	err  error
+	hang bool
+}
+
+func (m *mockSuiChecker) CheckRPC(ctx context.Context) error {
+	if m.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return m.err
+}
+
+func TestReadinessCheckWithSui(t *testing.T) {
+	tests := []struct {
+		name       string
+		checker    *mockSuiChecker
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "healthy rpc", checker: &mockSuiChecker{}, wantStatus: http.StatusOK, wantBody: "ok"},
+		{name: "rpc error", checker: &mockSuiChecker{err: errors.New("connection refused")}, wantStatus: http.StatusServiceUnavailable, wantBody: "sui rpc unreachable"},
+		{name: "rpc timeout", checker: &mockSuiChecker{hang: true}, wantStatus: http.StatusServiceUnavailable, wantBody: "sui rpc unreachable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ReadinessCheckWithSui(NewMockDB(), tt.checker, 20*time.Millisecond)
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest("GET", "/readyz", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var response map[string]string
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response["status"] != tt.wantBody {
+				t.Errorf("Expected status %q, got %q", tt.wantBody, response["status"])
+			}
+		})
+	}
+}
+
+func TestMiddleware_Recover(t *testing.T) {
+	middleware := NewMiddleware(nil, nil)
+
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var metadata *models.SolverMetadata
+		_ = metadata.ComputeTimeMs // nil dereference
+	})
+
+	handler := middleware.Recover(middleware.RequestLogging(panicking))
+
+	req := httptest.NewRequest("POST", "/solve", nil)
+	req.Header.Set("X-Request-ID", "req-panic")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if errResp.Error.Code != "INTERNAL_ERROR" || errResp.Error.RequestID != "req-panic" {
+		t.Errorf("Unexpected error response: %+v", errResp.Error)
+	}
+
+	// The server keeps serving after a panic
+	ok := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	w = httptest.NewRecorder()
+	ok.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 after recovery, got %d", w.Code)
+	}
+}
+
+func TestMiddleware_RecoverAfterPartialResponse(t *testing.T) {
+	middleware := NewMiddleware(nil, nil)
+
+	handler := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late failure")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/solve", nil))
+
+	// The response already started, so the original status is kept and nothing is appended
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no error body after a started response, got %q", w.Body.String())
+	}
+}
+
+func TestMiddleware_Timeout(t *testing.T) {
+	middleware := NewMiddleware(nil, nil)
+
+	canceled := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(canceled)
+		case <-time.After(5 * time.Second):
+		}
+		// Writes after the deadline are discarded
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("too late"))
+	})
+
+	req := httptest.NewRequest("POST", "/callback/ch_1", nil)
+	req.Header.Set("X-Request-ID", "req-slow")
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	middleware.Timeout(50*time.Millisecond)(slow).ServeHTTP(w, req)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Expected handler to be cut off after the timeout, took %v", elapsed)
+	}
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", w.Code)
+	}
+
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to decode error response %q: %v", w.Body.String(), err)
+	}
+	if errResp.Error.Code != "REQUEST_TIMEOUT" || errResp.Error.RequestID != "req-slow" {
+		t.Errorf("Unexpected error response: %+v", errResp.Error)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the handler context to be canceled")
+	}
+}
+
+func TestMiddleware_TimeoutPassesThroughFastHandler(t *testing.T) {
+	middleware := NewMiddleware(nil, nil)
+
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("queued"))
+	})
+
+	for _, d := range []time.Duration{time.Second, 0} {
+		w := httptest.NewRecorder()
+		middleware.Timeout(d)(fast).ServeHTTP(w, httptest.NewRequest("POST", "/solve", nil))
+
+		if w.Code != http.StatusAccepted || w.Body.String() != "queued" || w.Header().Get("Content-Type") != "text/plain" {
+			t.Errorf("Timeout(%v): expected handler response to pass through, got %d %q %q",
+				d, w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+		}
+	}
+}
+
 // Test the responseWriter wrapper
 func TestResponseWriter_WriteHeader(t *testing.T) {
 	w := httptest.NewRecorder()
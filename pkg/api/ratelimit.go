@@ -0,0 +1,187 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"reverse-challenge-system/pkg/logger"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	rateLimitSweepInterval = time.Minute     // How often idle client limiters are pruned
+	rateLimitIdleTimeout   = 3 * time.Minute // Limiters unused for this long are dropped
+)
+
+// rateLimiter keeps one token bucket per client IP.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rps       rate.Limit
+	burst     int
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// SetRateLimit enables per-client-IP rate limiting for the RateLimit middleware.
+// A non-positive rps disables limiting.
+func (m *Middleware) SetRateLimit(rps float64, burst int) {
+	if rps <= 0 {
+		m.limiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	m.limiter = &rateLimiter{
+		rps:       rate.Limit(rps),
+		burst:     burst,
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// SetTrustedProxies sets the proxies, as IPs or CIDRs, whose X-Forwarded-For entries
+// RateLimit uses to find the client IP. With none configured the header is ignored.
+func (m *Middleware) SetTrustedProxies(proxies []string) error {
+	trusted, err := ParseTrustedProxies(proxies)
+	if err != nil {
+		return err
+	}
+	m.trustedProxies = trusted
+	return nil
+}
+
+// ParseTrustedProxies parses proxy IPs and CIDRs; a bare IP matches only itself.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	trusted := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, network)
+			continue
+		}
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: want an IP or CIDR", proxy)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return trusted, nil
+}
+
+// RateLimit middleware applies a token bucket per client IP, honoring X-Forwarded-For only
+// from trusted proxies (see SetTrustedProxies).
+// Requests over the limit get 429 with a Retry-After header. It is a no-op until SetRateLimit is called.
+func (m *Middleware) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		clientIP := ClientIP(r, m.trustedProxies)
+		if delay := m.limiter.reserve(clientIP, time.Now()); delay > 0 {
+			requestID := RequestID(r)
+			logger := logger.WithRequestID(requestID)
+			logger.Warn().
+				Str("client_ip", clientIP).
+				Str("path", r.URL.Path).
+				Msg("Rate limit exceeded")
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			m.writeError(w, http.StatusTooManyRequests, "RATE_LIMITED", "Too many requests", requestID)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reserve takes a token for the client. It returns zero when the request may proceed,
+// otherwise how long the client must wait before a token is available.
+func (l *rateLimiter) reserve(clientIP string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		for ip, client := range l.clients {
+			if now.Sub(client.lastSeen) >= rateLimitIdleTimeout {
+				delete(l.clients, ip)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	client, ok := l.clients[clientIP]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.clients[clientIP] = client
+	}
+	client.lastSeen = now
+
+	reservation := client.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Rejected requests must not consume future tokens
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// ClientIP returns the originating client IP. X-Forwarded-For is only honored when the peer
+// in RemoteAddr is a trusted proxy: entries are walked from the right, past further trusted
+// proxies, and the first untrusted hop is the client. Entries left of it are client-supplied
+// and ignored. Otherwise the client is the host part of RemoteAddr.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !isTrustedProxy(peer, trustedProxies) {
+		return peer
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		client = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return client
+}
+
+// isTrustedProxy reports whether ip falls in one of the trusted proxy networks.
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/models"
+)
+
+func newRateLimitedHandler(rps float64, burst int, trustedProxies ...string) http.Handler {
+	middleware := NewMiddleware(auth.NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second), NewMockDB())
+	middleware.SetRateLimit(rps, burst)
+	if err := middleware.SetTrustedProxies(trustedProxies); err != nil {
+		panic(err)
+	}
+
+	return middleware.RateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func sendFrom(handler http.Handler, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/solve", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Request-ID", "req-rate")
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestMiddleware_RateLimit_RejectsOverBurst(t *testing.T) {
+	const burst = 3
+	handler := newRateLimitedHandler(0.5, burst)
+
+	for i := 0; i < burst; i++ {
+		if rr := sendFrom(handler, "192.0.2.1:1234", ""); rr.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i+1, rr.Code)
+		}
+	}
+
+	rr := sendFrom(handler, "192.0.2.1:1234", "")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 after burst, got %d", rr.Code)
+	}
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Errorf("Expected Retry-After 2, got %q", retryAfter)
+	}
+
+	var errorResp models.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &errorResp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if errorResp.Error.Code != "RATE_LIMITED" || errorResp.Error.RequestID != "req-rate" {
+		t.Errorf("Unexpected error response: %+v", errorResp.Error)
+	}
+
+	// Other clients keep their own bucket
+	if rr := sendFrom(handler, "192.0.2.2:1234", ""); rr.Code != http.StatusOK {
+		t.Errorf("Expected a different client to pass, got %d", rr.Code)
+	}
+}
+
+func TestMiddleware_RateLimit_KeysOnForwardedFor(t *testing.T) {
+	handler := newRateLimitedHandler(0.5, 1, "10.0.0.0/24")
+
+	// Both requests arrive through the same proxy but come from different clients
+	if rr := sendFrom(handler, "10.0.0.1:443", "203.0.113.1"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected first client to pass, got %d", rr.Code)
+	}
+	if rr := sendFrom(handler, "10.0.0.1:443", "203.0.113.2, 10.0.0.1"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected second client to pass, got %d", rr.Code)
+	}
+	if rr := sendFrom(handler, "10.0.0.2:443", "203.0.113.1"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected first client to be limited via another proxy, got %d", rr.Code)
+	}
+}
+
+func TestMiddleware_RateLimit_IgnoresSpoofedForwardedFor(t *testing.T) {
+	handler := newRateLimitedHandler(0.5, 1, "10.0.0.1")
+
+	// A client talking to the service directly cannot pick a new bucket per request
+	if rr := sendFrom(handler, "198.51.100.9:5555", "203.0.113.1"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d", rr.Code)
+	}
+	if rr := sendFrom(handler, "198.51.100.9:5555", "203.0.113.2"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a spoofed X-Forwarded-For from an untrusted peer to be ignored, got %d", rr.Code)
+	}
+
+	// Through the trusted proxy, entries the client prepended are ignored too
+	if rr := sendFrom(handler, "10.0.0.1:443", "203.0.113.3, 198.51.100.7"); rr.Code != http.StatusOK {
+		t.Fatalf("Expected first proxied request to pass, got %d", rr.Code)
+	}
+	if rr := sendFrom(handler, "10.0.0.1:443", "203.0.113.4, 198.51.100.7"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the proxied client to be limited despite a spoofed prefix, got %d", rr.Code)
+	}
+}
+
+func TestMiddleware_RateLimit_DisabledByDefault(t *testing.T) {
+	handler := newRateLimitedHandler(0, 0)
+
+	for i := 0; i < 50; i++ {
+		if rr := sendFrom(handler, "192.0.2.1:1234", ""); rr.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200 with rate limiting disabled, got %d", i+1, rr.Code)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         string
+	}{
+		{"remote addr", "192.0.2.1:1234", "", "192.0.2.1"},
+		{"ipv6 remote addr", "[2001:db8::1]:1234", "", "2001:db8::1"},
+		{"forwarded for", "10.0.0.1:443", "203.0.113.7", "203.0.113.7"},
+		{"forwarded chain", "10.0.0.1:443", " 203.0.113.7 , 10.0.0.2", "203.0.113.7"},
+		{"spoofed prefix", "10.0.0.1:443", "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"untrusted peer", "192.0.2.1:1234", "203.0.113.7", "192.0.2.1"},
+		{"only proxies", "10.0.0.1:443", "10.0.0.2", "10.0.0.2"},
+		{"remote addr without port", "192.0.2.1", "", "192.0.2.1"},
+	}
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/24"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			if got := ClientIP(req, trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
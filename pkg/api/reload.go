@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/db"
+)
+
+// ConfigHMACKeys are the keys a service takes from its configuration: the secrets of
+// GetChallengerSecrets/GetSolverSecrets and the CHAL_HMAC_KEYS rotation keys.
+type ConfigHMACKeys struct {
+	Secrets  map[string]string
+	Rotation []config.HMACKey
+}
+
+// keyIDs returns every key ID in k
+func (k ConfigHMACKeys) keyIDs() map[string]bool {
+	ids := make(map[string]bool, len(k.Secrets)+len(k.Rotation))
+	for keyID := range k.Secrets {
+		ids[keyID] = true
+	}
+	for _, key := range k.Rotation {
+		ids[key.KeyID] = true
+	}
+	return ids
+}
+
+// ReloadHMACKeys swaps the configured keys of a running service from previous to current.
+// Keys only previous had are revoked, the others are added or updated in place, so requests
+// signed with unchanged keys keep verifying throughout. Keys persisted through /admin/keys
+// still take precedence and are left untouched. Returns how many keys were revoked.
+func ReloadHMACKeys(ctx context.Context, hmacAuth *auth.HMACAuth, store db.HMACKeyStore, previous, current ConfigHMACKeys) (int, error) {
+	persisted, err := store.ListHMACKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	overridden := make(map[string]bool, len(persisted))
+	for _, key := range persisted {
+		overridden[key.KeyID] = true
+	}
+
+	for keyID, secret := range current.Secrets {
+		if !overridden[keyID] {
+			hmacAuth.AddSecret(keyID, secret)
+		}
+	}
+	for _, key := range current.Rotation {
+		if !overridden[key.KeyID] {
+			hmacAuth.AddSecretWithValidity(key.KeyID, key.Secret, key.NotBefore, key.NotAfter)
+		}
+	}
+
+	revoked := 0
+	currentIDs := current.keyIDs()
+	for keyID := range previous.keyIDs() {
+		if !currentIDs[keyID] && !overridden[keyID] {
+			hmacAuth.RemoveSecret(keyID)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/auth"
+	"reverse-challenge-system/pkg/config"
+)
+
+func TestReloadHMACKeys_SwapsSecrets(t *testing.T) {
+	store := newAdminTestStore(t)
+	previous := ConfigHMACKeys{
+		Secrets:  map[string]string{"chal-kid-1": "old-secret", "solver-kid-1": "solver-secret"},
+		Rotation: []config.HMACKey{{KeyID: "kid-retired", Secret: "retired-secret"}},
+	}
+	hmacAuth := auth.NewHMACAuth(previous.Secrets, 300*time.Second)
+	hmacAuth.AddSecret("kid-retired", "retired-secret")
+	m := NewMiddleware(hmacAuth, store)
+	m.SetAdminKey(testAdminKey)
+
+	// A key added at runtime is not part of the configuration and must survive the reload
+	if w := postKeyUpdate(t, m, testAdminKey, KeyUpdateRequest{Action: KeyActionAdd, KeyID: "kid-runtime", Secret: "runtime-secret"}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 adding a key, got %d: %s", w.Code, w.Body.String())
+	}
+
+	current := ConfigHMACKeys{
+		Secrets:  map[string]string{"chal-kid-1": "new-secret", "solver-kid-1": "solver-secret"},
+		Rotation: []config.HMACKey{{KeyID: "kid-next", Secret: "next-secret"}},
+	}
+	revoked, err := ReloadHMACKeys(context.Background(), hmacAuth, store, previous, current)
+	if err != nil {
+		t.Fatalf("ReloadHMACKeys() unexpected error: %v", err)
+	}
+	if revoked != 1 {
+		t.Errorf("Expected 1 revoked key, got %d", revoked)
+	}
+
+	tests := []struct {
+		keyID, secret string
+		want          int
+	}{
+		{"chal-kid-1", "old-secret", http.StatusUnauthorized},
+		{"chal-kid-1", "new-secret", http.StatusOK},
+		{"solver-kid-1", "solver-secret", http.StatusOK},
+		{"kid-retired", "retired-secret", http.StatusUnauthorized},
+		{"kid-next", "next-secret", http.StatusOK},
+		{"kid-runtime", "runtime-secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := signedStatus(m, tt.keyID, tt.secret); code != tt.want {
+			t.Errorf("%s signed with %s: expected status %d, got %d", tt.keyID, tt.secret, tt.want, code)
+		}
+	}
+}
+
+func TestReloadHMACKeys_PersistedKeysTakePrecedence(t *testing.T) {
+	store := newAdminTestStore(t)
+	previous := ConfigHMACKeys{Secrets: map[string]string{"chal-kid-1": "leaked-secret"}}
+	hmacAuth := auth.NewHMACAuth(previous.Secrets, 300*time.Second)
+	m := NewMiddleware(hmacAuth, store)
+	m.SetAdminKey(testAdminKey)
+
+	if w := postKeyUpdate(t, m, testAdminKey, KeyUpdateRequest{Action: KeyActionRevoke, KeyID: "chal-kid-1"}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 revoking a key, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The environment still lists the revoked key; the reload must not bring it back
+	if _, err := ReloadHMACKeys(context.Background(), hmacAuth, store, previous, previous); err != nil {
+		t.Fatalf("ReloadHMACKeys() unexpected error: %v", err)
+	}
+	if _, ok := hmacAuth.GetSecret("chal-kid-1"); ok {
+		t.Error("Expected the revoked key to stay revoked after a reload")
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,11 +21,26 @@ const (
 	DefaultClockSkew = 300               // Default clock skew tolerance: 300 seconds = 5 minutes
 )
 
+// ErrKeyNotValid is returned by VerifySignature when a known key is used outside its validity window
+var ErrKeyNotValid = errors.New("key outside its validity window")
+
 // HMACAuth manages HMAC-SHA256 authentication with multiple key support.
 // Handles both signing outbound requests and verifying inbound requests.
+// Safe for concurrent use; keys may be added or revoked while requests are verified.
 type HMACAuth struct {
-	secrets   map[string]string // Map of keyId to secret for multi-key support
-	clockSkew time.Duration     // Maximum allowed time difference between request and verification
+	mu        sync.RWMutex           // Guards secrets and validity
+	secrets   map[string]string      // Map of keyId to secret for multi-key support
+	validity  map[string]keyValidity // Validity windows for keys added with AddSecretWithValidity
+	clockSkew time.Duration          // Maximum allowed time difference between request and verification
+
+	pastSkew   time.Duration // How far a request timestamp may lag behind now
+	futureSkew time.Duration // How far a request timestamp may be ahead of now
+}
+
+// keyValidity bounds when a key is accepted. A zero time leaves that side of the window open.
+type keyValidity struct {
+	notBefore time.Time
+	notAfter  time.Time
 }
 
 // AuthHeader represents the parsed components of an HMAC authentication header.
@@ -35,21 +53,95 @@ type AuthHeader struct {
 }
 
 // NewHMACAuth creates a new HMAC authenticator with the provided secrets and clock skew.
+// The secrets are copied, so later changes go through AddSecret and RemoveSecret under the lock.
 // If clockSkew is 0, uses the default 5-minute tolerance.
 func NewHMACAuth(secrets map[string]string, clockSkew time.Duration) *HMACAuth {
 	if clockSkew == 0 {
 		clockSkew = DefaultClockSkew * time.Second
 	}
+	copied := make(map[string]string, len(secrets))
+	for keyID, secret := range secrets {
+		copied[keyID] = secret
+	}
 	return &HMACAuth{
-		secrets:   secrets,
-		clockSkew: clockSkew,
+		secrets:    copied,
+		validity:   make(map[string]keyValidity),
+		clockSkew:  clockSkew,
+		pastSkew:   clockSkew,
+		futureSkew: clockSkew,
+	}
+}
+
+// SetClockSkew sets separate tolerances for timestamps behind and ahead of now, so late
+// requests can be accepted while future-dated ones, usually a misconfigured or malicious
+// client, are held to a tighter window. A zero value keeps the symmetric clock skew.
+// Must be called before the authenticator verifies requests.
+func (h *HMACAuth) SetClockSkew(past, future time.Duration) {
+	h.pastSkew = h.clockSkew
+	if past > 0 {
+		h.pastSkew = past
+	}
+	h.futureSkew = h.clockSkew
+	if future > 0 {
+		h.futureSkew = future
 	}
 }
 
 // AddSecret adds or updates a signing secret for the given key ID.
+// The key is always valid; any validity window set earlier is dropped.
 // Allows dynamic key management without recreating the authenticator.
 func (h *HMACAuth) AddSecret(keyID, secret string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.secrets[keyID] = secret
+	delete(h.validity, keyID)
+}
+
+// AddSecretWithValidity adds or updates a signing secret that VerifySignature only accepts
+// between notBefore and notAfter. A zero time leaves that side of the window open, so
+// rotation can introduce a new key ahead of time and retire the old one after a grace period.
+func (h *HMACAuth) AddSecretWithValidity(keyID, secret string, notBefore, notAfter time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.secrets[keyID] = secret
+	h.validity[keyID] = keyValidity{notBefore: notBefore, notAfter: notAfter}
+}
+
+// RemoveSecret revokes the key with the given ID; requests signed with it are rejected as unknown.
+// Removing a key that does not exist is a no-op.
+func (h *HMACAuth) RemoveSecret(keyID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.secrets, keyID)
+	delete(h.validity, keyID)
+}
+
+// ListKeyIDs returns the configured key IDs in sorted order, without their secrets.
+func (h *HMACAuth) ListKeyIDs() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	keyIDs := make([]string, 0, len(h.secrets))
+	for keyID := range h.secrets {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+	return keyIDs
+}
+
+// checkValidity reports whether keyID may be used at now. Callers must hold h.mu.
+func (h *HMACAuth) checkValidity(keyID string, now time.Time) error {
+	window, ok := h.validity[keyID]
+	if !ok {
+		return nil
+	}
+	if !window.notBefore.IsZero() && now.Before(window.notBefore) {
+		return fmt.Errorf("%w: keyId %s is not valid before %s", ErrKeyNotValid, keyID, window.notBefore.UTC().Format(time.RFC3339))
+	}
+	if !window.notAfter.IsZero() && !now.Before(window.notAfter) {
+		return fmt.Errorf("%w: keyId %s expired at %s", ErrKeyNotValid, keyID, window.notAfter.UTC().Format(time.RFC3339))
+	}
+	return nil
 }
 
 // BodySHA256Hex computes the SHA-256 hash of the request body and returns it as a hex string.
@@ -86,7 +178,7 @@ func ComputeSignature(method, path string, body []byte, ts, nonce, secret string
 // Returns empty string if the keyID is not found in the secrets map.
 func (h *HMACAuth) CreateAuthHeader(method, path string, body []byte, keyID, nonce string) string {
 	ts := strconv.FormatInt(time.Now().Unix(), 10)
-	secret, exists := h.secrets[keyID]
+	secret, exists := h.GetSecret(keyID)
 	if !exists {
 		return ""
 	}
@@ -139,14 +231,24 @@ func ParseAuthHeader(authHeader string) (*AuthHeader, error) {
 }
 
 // VerifySignature validates an incoming request's HMAC signature.
-// Performs comprehensive validation including key existence, timestamp freshness,
-// and signature verification using constant-time comparison to prevent timing attacks.
+// Performs comprehensive validation including key existence and validity window, timestamp
+// freshness, and signature verification using constant-time comparison to prevent timing attacks.
 func (h *HMACAuth) VerifySignature(method, path string, body []byte, auth *AuthHeader) error {
-	// Check if keyID exists
+	// Check if keyID exists and is within its validity window
+	h.mu.RLock()
 	secret, exists := h.secrets[auth.KeyID]
+	var validityErr error
+	if exists {
+		validityErr = h.checkValidity(auth.KeyID, time.Now())
+	}
+	h.mu.RUnlock()
+
 	if !exists {
 		return fmt.Errorf("unknown keyId: %s", auth.KeyID)
 	}
+	if validityErr != nil {
+		return validityErr
+	}
 
 	// Verify timestamp is within clock skew
 	ts, err := strconv.ParseInt(auth.Timestamp, 10, 64)
@@ -155,8 +257,11 @@ func (h *HMACAuth) VerifySignature(method, path string, body []byte, auth *AuthH
 	}
 
 	now := time.Now().Unix()
-	if abs(now-ts) > int64(h.clockSkew.Seconds()) {
-		return fmt.Errorf("timestamp outside allowed skew: %d vs %d", ts, now)
+	if ts > now && ts-now > int64(h.futureSkew.Seconds()) {
+		return fmt.Errorf("timestamp outside allowed skew: %d vs %d (%s ahead)", ts, now, time.Duration(ts-now)*time.Second)
+	}
+	if ts <= now && now-ts > int64(h.pastSkew.Seconds()) {
+		return fmt.Errorf("timestamp outside allowed skew: %d vs %d (%s behind)", ts, now, time.Duration(now-ts)*time.Second)
 	}
 
 	// Compute expected signature
@@ -170,19 +275,12 @@ func (h *HMACAuth) VerifySignature(method, path string, body []byte, auth *AuthH
 	return nil
 }
 
-// abs returns the absolute value of an int64.
-// Helper function for timestamp difference calculations.
-func abs(x int64) int64 {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
 // GetSecret retrieves the secret for a given key ID.
 // Returns the secret and a boolean indicating whether the key exists.
 // Used for debugging and testing purposes.
 func (h *HMACAuth) GetSecret(keyID string) (string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	secret, exists := h.secrets[keyID]
 	return secret, exists
 }
@@ -1,7 +1,11 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -108,6 +112,55 @@ func TestHMACAuth(t *testing.T) {
 	})
 }
 
+func TestHMACAuth_AsymmetricClockSkew(t *testing.T) {
+	auth := NewHMACAuth(map[string]string{"test-key": "test-secret"}, 300*time.Second)
+	auth.SetClockSkew(600*time.Second, 30*time.Second)
+
+	tests := []struct {
+		name    string
+		offset  time.Duration // Request timestamp relative to now
+		wantErr bool
+	}{
+		{name: "slightly in the future within the tight window", offset: 20 * time.Second},
+		{name: "future beyond the tight window", offset: 60 * time.Second, wantErr: true},
+		{name: "past beyond the symmetric skew but within the wider past window", offset: -450 * time.Second},
+		{name: "past beyond the wider window", offset: -700 * time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(`{"test": "data"}`)
+			timestamp := strconv.FormatInt(time.Now().Add(tt.offset).Unix(), 10)
+			authInfo := &AuthHeader{
+				KeyID:     "test-key",
+				Timestamp: timestamp,
+				Nonce:     "skew-nonce",
+				Signature: ComputeSignature("POST", "/test", body, timestamp, "skew-nonce", "test-secret"),
+			}
+
+			err := auth.VerifySignature("POST", "/test", body, authInfo)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "timestamp outside allowed skew") {
+					t.Errorf("Expected a skew error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected the timestamp to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHMACAuth_SetClockSkewFallsBackToSymmetric(t *testing.T) {
+	auth := NewHMACAuth(map[string]string{}, 300*time.Second)
+	auth.SetClockSkew(0, 30*time.Second)
+
+	if auth.pastSkew != 300*time.Second || auth.futureSkew != 30*time.Second {
+		t.Errorf("Expected past 5m0s and future 30s, got %v and %v", auth.pastSkew, auth.futureSkew)
+	}
+}
+
 func TestCanonicalString(t *testing.T) {
 	method := "POST"
 	path := "/callback/test-challenge-123"
@@ -177,3 +230,112 @@ func TestParseAuthHeader(t *testing.T) {
 		}
 	})
 }
+
+func TestHMACAuth_KeyValidity(t *testing.T) {
+	now := time.Now()
+	auth := NewHMACAuth(map[string]string{}, 300*time.Second)
+	auth.AddSecretWithValidity("expired-key", "old-secret", now.Add(-48*time.Hour), now.Add(-time.Hour))
+	auth.AddSecretWithValidity("future-key", "new-secret", now.Add(time.Hour), time.Time{})
+	auth.AddSecretWithValidity("current-key", "current-secret", now.Add(-time.Hour), now.Add(time.Hour))
+
+	tests := []struct {
+		keyID   string
+		wantErr string
+	}{
+		{keyID: "expired-key", wantErr: "expired at"},
+		{keyID: "future-key", wantErr: "not valid before"},
+		{keyID: "current-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyID, func(t *testing.T) {
+			body := []byte(`{"test": "data"}`)
+			authInfo, err := ParseAuthHeader(auth.CreateAuthHeader("POST", "/callback/1", body, tt.keyID, "nonce-"+tt.keyID))
+			if err != nil {
+				t.Fatalf("Failed to parse auth header: %v", err)
+			}
+
+			err = auth.VerifySignature("POST", "/callback/1", body, authInfo)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected key within its window to verify, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrKeyNotValid) {
+				t.Fatalf("Expected ErrKeyNotValid, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), tt.keyID) {
+				t.Errorf("Expected error mentioning %q and the key, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// AddSecret makes the key valid at all times again
+	auth.AddSecret("expired-key", "old-secret")
+	body := []byte(`{}`)
+	authInfo, _ := ParseAuthHeader(auth.CreateAuthHeader("POST", "/solve", body, "expired-key", "nonce-reset"))
+	if err := auth.VerifySignature("POST", "/solve", body, authInfo); err != nil {
+		t.Errorf("Expected AddSecret to clear the validity window, got %v", err)
+	}
+}
+
+func TestHMACAuth_RemoveSecretAndListKeyIDs(t *testing.T) {
+	auth := NewHMACAuth(map[string]string{"solver-kid-1": "a", "chal-kid-1": "b"}, 300*time.Second)
+	auth.AddSecretWithValidity("chal-kid-2", "c", time.Time{}, time.Now().Add(time.Hour))
+
+	if got := strings.Join(auth.ListKeyIDs(), ","); got != "chal-kid-1,chal-kid-2,solver-kid-1" {
+		t.Errorf("Expected sorted key IDs, got %s", got)
+	}
+
+	body := []byte(`{}`)
+	authInfo, _ := ParseAuthHeader(auth.CreateAuthHeader("POST", "/solve", body, "chal-kid-1", "nonce"))
+
+	auth.RemoveSecret("chal-kid-1")
+	auth.RemoveSecret("missing-kid")
+
+	if err := auth.VerifySignature("POST", "/solve", body, authInfo); err == nil || !strings.Contains(err.Error(), "unknown keyId") {
+		t.Errorf("Expected a revoked key to be unknown, got %v", err)
+	}
+	if _, ok := auth.GetSecret("chal-kid-1"); ok {
+		t.Error("Expected the revoked secret to be gone")
+	}
+	if got := strings.Join(auth.ListKeyIDs(), ","); got != "chal-kid-2,solver-kid-1" {
+		t.Errorf("Expected remaining key IDs, got %s", got)
+	}
+}
+
+func TestHMACAuth_ConcurrentKeyManagement(t *testing.T) {
+	auth := NewHMACAuth(map[string]string{"stable-key": "stable-secret"}, 300*time.Second)
+	body := []byte(`{"test": "data"}`)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			keyID := fmt.Sprintf("rotating-key-%d", i)
+			for j := 0; j < 100; j++ {
+				auth.AddSecretWithValidity(keyID, "secret", time.Time{}, time.Now().Add(time.Hour))
+
+				authInfo, err := ParseAuthHeader(auth.CreateAuthHeader("POST", "/callback/1", body, "stable-key", fmt.Sprintf("nonce-%d-%d", i, j)))
+				if err != nil {
+					t.Errorf("Failed to parse auth header: %v", err)
+					return
+				}
+				if err := auth.VerifySignature("POST", "/callback/1", body, authInfo); err != nil {
+					t.Errorf("Expected stable key to verify during rotation, got %v", err)
+					return
+				}
+
+				auth.ListKeyIDs()
+				auth.RemoveSecret(keyID)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := auth.ListKeyIDs(); len(got) != 1 || got[0] != "stable-key" {
+		t.Errorf("Expected only the stable key to remain, got %v", got)
+	}
+}
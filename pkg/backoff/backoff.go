@@ -0,0 +1,32 @@
+// Package backoff computes exponential retry delays with jitter. It is shared by the
+// solver's callback retries and the challenger's log uploads.
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Policy describes an exponential backoff: BaseDelay doubles each attempt up to MaxDelay,
+// and every delay is scaled by a random factor in [JitterMin, JitterMax).
+type Policy struct {
+	BaseDelay time.Duration // Delay before the first retry
+	MaxDelay  time.Duration // Upper bound on the delay before jitter
+	JitterMin float64       // Lower multiplier applied to each delay
+	JitterMax float64       // Upper multiplier applied to each delay
+}
+
+// Delay returns how long to wait after the given failed attempt (0 for the first).
+func (p Policy) Delay(attempt int) time.Duration {
+	// Exponential backoff: delay = min(max, base * 2^attempt),
+	// computed in float64 so large attempts cannot overflow
+	delay := p.MaxDelay
+	if d := float64(p.BaseDelay) * math.Pow(2, float64(attempt)); d < float64(p.MaxDelay) {
+		delay = time.Duration(d)
+	}
+
+	// Add jitter: delay * random(JitterMin, JitterMax)
+	jitter := p.JitterMin + rand.Float64()*(p.JitterMax-p.JitterMin)
+	return time.Duration(float64(delay) * jitter)
+}
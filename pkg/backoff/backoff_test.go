@@ -0,0 +1,38 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, JitterMin: 1, JitterMax: 1}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 100 * time.Millisecond},
+		{attempt: 1, want: 200 * time.Millisecond},
+		{attempt: 3, want: 800 * time.Millisecond},
+		{attempt: 4, want: time.Second},
+		{attempt: 100, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Delay(tt.attempt); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyDelayJitter(t *testing.T) {
+	policy := Policy{BaseDelay: time.Second, MaxDelay: time.Minute, JitterMin: 0.85, JitterMax: 1.15}
+
+	for i := 0; i < 100; i++ {
+		got := policy.Delay(0)
+		if got < 850*time.Millisecond || got > 1150*time.Millisecond {
+			t.Fatalf("Delay(0) = %v, want within 15%% of 1s", got)
+		}
+	}
+}
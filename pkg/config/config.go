@@ -4,12 +4,21 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/suicrypto"
+
+	"reverse-challenge-system/pkg/version"
 )
 
 // SuiConfig contains Sui blockchain-specific configuration
@@ -29,23 +38,73 @@ type SuiConfig struct {
 	TreasuryPos         string // Treasury positive type argument
 	TreasuryNeg         string // Treasury negative type argument
 	Collateral          string // Collateral type argument
+	RPCMaxRetries       int    // Retries for transient Sui RPC failures (0 disables)
+
+	ReadinessProbe     bool // /readyz also pings the Sui RPC (challenger only)
+	ReadinessTimeoutMs int  // Timeout for that ping in milliseconds
+
+	WSUrl                   string // Sui websocket endpoint for commitment event subscriptions (empty disables)
+	EventConfirmTimeoutSecs int    // How long a commitment waits for its event before its log entry is stored anyway
+}
+
+// EthereumConfig holds settings for deploying the challenge registry to an EVM chain
+type EthereumConfig struct {
+	RPCUrl       string // EVM JSON-RPC endpoint URL
+	PrivateKey   string // Hex-encoded deployer private key
+	ChainID      int64  // EIP-155 chain ID used to sign transactions (0 asks the node)
+	BytecodePath string // File holding the compiled registry bytecode as hex (solc --bin output)
+}
+
+// HTTPClientConfig tunes the outbound HTTP clients (challenger /solve, solver callbacks, verifier log fetches)
+type HTTPClientConfig struct {
+	TimeoutSecs               int // Overall deadline for a request, including reading the body (0 disables)
+	DialTimeoutSecs           int // Timeout for establishing a TCP connection
+	TLSHandshakeTimeoutSecs   int // Timeout for the TLS handshake
+	ResponseHeaderTimeoutSecs int // Timeout waiting for response headers after the request is sent (0 disables)
+	IdleConnTimeoutSecs       int // How long an idle pooled connection is kept open
+	MaxIdleConns              int // Idle connections kept across all hosts (0 means no limit)
+	MaxIdleConnsPerHost       int // Idle connections kept per host
+}
+
+// HMACKey is an additional HMAC key with an optional validity window, loaded from CHAL_HMAC_KEYS
+// as a JSON list. Zero NotBefore/NotAfter leave that side of the window open.
+type HMACKey struct {
+	KeyID     string    `json:"key_id"`
+	Secret    string    `json:"secret"`
+	NotBefore time.Time `json:"not_before"` // RFC 3339
+	NotAfter  time.Time `json:"not_after"`  // RFC 3339
 }
 
 // Config holds all configuration settings for both challenger and solver services.
 // Provides centralized configuration management with validation and helper methods.
 type Config struct {
 	// Challenger Configuration
-	ChallengerHost        string // Challenger service bind host address
-	ChallengerPort        string // Challenger service bind port
-	UseNgrok              bool   // Whether to use ngrok for callbacks (requires HTTPS)
-	PublicCallbackHost    string // Public URL for callbacks (e.g., ngrok URL or localhost)
-	ChallengerCallbackKey string // API key for challenger callback validation
-	ChalHMACKeyID         string // Key identifier for challenger HMAC signing
-	ChalHMACSecret        string // Secret for challenger HMAC signing
+	ChallengerHost            string // Challenger service bind host address
+	ChallengerPort            string // Challenger service bind port
+	UseNgrok                  bool   // Whether to use ngrok for callbacks (requires HTTPS)
+	PublicCallbackHost        string // Public URL for callbacks (e.g., ngrok URL or localhost)
+	ChallengerCallbackKey     string // API key for challenger callback validation
+	ChalHMACKeyID             string // Key identifier for challenger HMAC signing
+	ChalHMACSecret            string // Secret for challenger HMAC signing
+	CallbackCorrectness       string // Correctness signal in callback responses: "off", "body", or "status"
+	AnswerSubmissionMode      string // "first" commits every correct answer; "best" keeps the best answer and settles once the window closes
+	SubmissionWindowSecs      int    // Acceptance window for answers in seconds, also sent to solvers as the deadline
+	CommitmentBatchSize       int    // Max commitments uploaded per Sui transaction (1 disables batching)
+	CommitmentBatchWaitMs     int    // How long queued commitments are collected before a batch is flushed
+	AllowNoSolverAddress      bool   // Accept callbacks without X-Solver-Address, recording the zero address (local testing only)
+	RequireSolverSignature    bool   // Reject callbacks without an Ed25519 X-Solver-Signature for X-Solver-Address
+	WebhookAuditRetentionDays int    // Days to keep callback audit records before the hourly cleanup deletes them (0 keeps them)
+
+	// HMAC Key Rotation
+	ChalHMACKeys []HMACKey // Extra keys both services accept, each only within its validity window
 
 	// Sui Configuration
 	SUI SuiConfig // Sui blockchain configuration
 
+	// Deployment Target
+	DeployTarget string         // Chain the initializer deploys to: "sui" or "ethereum"
+	ETH          EthereumConfig // Ethereum deployment configuration
+
 	// Contract Configuration
 	ContractsPath string // Path to contract sources directory
 	DatabasePath  string // Primary database path for initializer
@@ -58,45 +117,138 @@ type Config struct {
 	SolverHMACKeyID   string // Key identifier for solver HMAC signing
 	SolverHMACSecret  string // Secret for solver HMAC signing
 
+	// Solver API Versions
+	SolverAPIVersions []string // api_version values /solve accepts; the request's version is echoed in the callback
+
+	// Solver Backend
+	SolverBackendURL            string // External inference service; empty uses the built-in mock solver
+	SolverBackendTimeoutSeconds int    // Timeout for a single backend solve request
+
+	// Solver Intake
+	SolverProblemTypes []string // Problem types /solve accepts, matching the installed backend; "*" or empty accepts any
+
+	// Solver Callback Retry
+	SolverMaxRetryAttempts int // Total callback delivery attempts, including the first
+	SolverBaseDelayMs      int // Backoff before the first retry in milliseconds; doubles each attempt
+	SolverMaxDelayMs       int // Upper bound on the backoff delay in milliseconds
+	SolverJitterPct        int // Random +/- percentage applied to each backoff delay
+
+	// Solver Concurrency
+	SolverTypeLimits map[string]int // Maximum challenges of a problem type solved at once; unlisted types are unlimited
+	SolverMaxQueue   int            // Maximum queued or processing challenges before /solve answers 503 (0 = unlimited)
+
 	// Shared Configuration
 	SharedSecretKey string // Shared secret for simplified HMAC setup (overrides individual secrets)
 
 	// Database
-	ChallengerDBPath string // File path for challenger SQLite database
-	SolverDBPath     string // File path for solver SQLite database
+	DBDriver              string // Database driver for challenger and solver stores (sqlite or postgres)
+	ChallengerDBPath      string // File path for challenger SQLite database
+	SolverDBPath          string // File path for solver SQLite database
+	ChallengerDatabaseURL string // Postgres connection string for the challenger (DB_DRIVER=postgres)
+	SolverDatabaseURL     string // Postgres connection string for the solver (DB_DRIVER=postgres)
+	SQLiteBusyTimeoutMs   int    // How long a SQLite statement waits for a lock before failing with "database is locked"
+	SQLiteMaxOpenConns    int    // SQLite writer connections per store; 1 serializes writes (0 is unbounded)
+	BackupDir             string // Directory POST /admin/backup writes SQLite snapshots to (empty disables it)
+
+	// Read-only handles for reporting endpoints; unset falls back to the primary
+	ChallengerReadDBPath      string // SQLite file opened read-only for challenger GET handlers
+	SolverReadDBPath          string // SQLite file opened read-only for solver stats
+	ChallengerReadDatabaseURL string // Postgres read replica for the challenger (DB_DRIVER=postgres)
+	SolverReadDatabaseURL     string // Postgres read replica for the solver (DB_DRIVER=postgres)
 
 	// Security
-	ClockSkewSeconds int // Maximum allowed time difference for HMAC timestamp validation
+	ClockSkewSeconds       int      // Maximum allowed time difference for HMAC timestamp validation
+	ClockSkewPastSeconds   int      // How far an HMAC timestamp may lag behind now (0 uses ClockSkewSeconds)
+	ClockSkewFutureSeconds int      // How far an HMAC timestamp may be ahead of now (0 uses ClockSkewSeconds)
+	MaxSolverMetadataBytes int      // Maximum size of callback solver metadata in bytes (0 disables the limit)
+	RateLimitRPS           float64  // Sustained requests per second per client IP on /solve and /callback (0 disables)
+	RateLimitBurst         int      // Requests a client IP may send at once before being limited
+	TrustedProxies         []string // Proxy IPs or CIDRs whose X-Forwarded-For entries identify the client IP
+	CORSAllowedOrigins     []string // Origins allowed to make cross-origin requests; "*" allows any (empty disables CORS)
+	CORSAllowedMethods     []string // Methods advertised in Access-Control-Allow-Methods
+	CORSAllowedHeaders     []string // Headers advertised in Access-Control-Allow-Headers
+	RequestTimeoutSecs     int      // Per-request handler deadline in seconds, independent of the HTTP server timeouts (0 disables)
+	CallbackAllowedHosts   []string // Callback hosts trusted explicitly (may use HTTP and private addresses); ".example.com" matches subdomains
+	MaxRequestBytes        int      // Request body limit for routes without their own limit (0 disables)
+	MaxCallbackBytes       int      // Body limit for /callback, replacing MaxRequestBytes; 0 uses MaxRequestBytes
+	MaxSolveBytes          int      // Body limit for the solver's /solve, replacing MaxRequestBytes; 0 uses MaxRequestBytes
+	CompressionMinBytes    int      // Responses at least this large are gzipped for clients that accept it (0 compresses all)
+	AdminAPIKey            string   // Key required in X-Admin-Key by the /admin endpoints (empty disables them)
+	OperatorHMACKeyIDs     []string // HMAC key IDs besides SolverHMACKeyID allowed on the challenger's /challenges routes
+
+	// Replay Protection
+	NonceStore    string // Where HMAC nonces are remembered: "db", "memory", or "redis"
+	NonceRedisURL string // Redis server for NONCE_STORE=redis, e.g. redis://:password@localhost:6379/0
+
+	// Outbound HTTP
+	HTTPClient HTTPClientConfig // Connection pooling and timeouts for outbound requests
+
+	// Event Bus
+	EventBusDriver        string // Lifecycle event publisher: "none" or "nats"
+	EventBusURL           string // Bus server URL (e.g. nats://localhost:4222)
+	EventBusSubjectPrefix string // Prefix for published subjects, e.g. "aibattle" -> aibattle.challenge.created
 
 	// Logging
-	LogLevel string // Log level (debug, info, warn, error)
+	LogLevel      string // Log level (debug, info, warn, error)
+	LogDir        string // Directory service log files are written to
+	LogFormat     string // Stdout log format: "console" (pretty) or "json"
+	LogMaxSizeMB  int    // Size in MB at which a service log file is rotated
+	LogMaxBackups int    // Rotated log files to keep per service (0 keeps all)
+	LogMaxAgeDays int    // Days to keep log files before they are removed (0 keeps them)
 
 	// Verifier Configuration
 	TxDigestFile string // File path for storing last transaction digest
 
 	// Log Service Configuration
-	LogServiceURL    string // External log collector endpoint
-	LogServiceAPIKey string // API key for log service
-	LogsAPIBaseURL   string // Base URL for logs API endpoint
-	LogsAPIKey       string // API key for logs API access
+	LogServiceURL      string // External log collector endpoint
+	LogServiceAPIKey   string // API key for log service
+	LogsAPIBaseURL     string // Base URL for logs API endpoint
+	LogsAPIKey         string // API key for logs API access
+	LogsAPIFallbackURL string // Challenger base URL serving local log copies when the logs API is unavailable
+
+	// Log Upload Retry
+	LogUploadMaxAttempts       int // Upload attempts per entry, including the first, before it is queued locally
+	LogUploadBaseDelayMs       int // Backoff before the first retry in milliseconds; doubles each attempt
+	LogUploadFlushIntervalSecs int // How often queued uploads are retried in the background
+
+	// Challenge Dispatch Retry
+	DispatchTimeoutSecs       int // Deadline for a single POST /solve to the solver
+	DispatchMaxAttempts       int // Dispatch attempts per challenge, including the first, before it is queued for redispatch
+	DispatchBaseDelayMs       int // Backoff before the first retry in milliseconds; doubles each attempt
+	DispatchRetryIntervalSecs int // How often queued dispatches are retried in the background
 }
 
+// dotenvKeys are the variables Load took from the .env file rather than the process
+// environment. Later calls refresh them, so a reload sees edits to the file.
+var (
+	dotenvMu   sync.Mutex
+	dotenvKeys = make(map[string]bool)
+)
+
 // Load reads configuration from environment variables and .env file.
 // Returns a validated configuration instance with all required settings.
 // Automatically loads .env file if present, with environment variables taking precedence.
+// Calling it again (e.g. on SIGHUP) picks up changes to the .env file.
 func Load() (*Config, error) {
-	// Try to load .env file (ignore error if file doesn't exist)
-	_ = godotenv.Load()
+	loadDotenv()
 
 	config := &Config{
 		// Challenger Configuration
-		ChallengerHost:        getEnv("CHALLENGER_HOST", "0.0.0.0"),
-		ChallengerPort:        getEnv("CHALLENGER_PORT", "8080"),
-		UseNgrok:              getEnvAsBool("USE_NGROK", false),
-		PublicCallbackHost:    getEnv("PUBLIC_CALLBACK_HOST", ""),
-		ChallengerCallbackKey: getEnv("CHALLENGER_CALLBACK_KEY", ""),
-		ChalHMACKeyID:         getEnv("CHAL_HMAC_KEY_ID", "chal-kid-1"),
-		ChalHMACSecret:        getEnv("CHAL_HMAC_SECRET", ""),
+		ChallengerHost:            getEnv("CHALLENGER_HOST", "0.0.0.0"),
+		ChallengerPort:            getEnv("CHALLENGER_PORT", "8080"),
+		UseNgrok:                  getEnvAsBool("USE_NGROK", false),
+		PublicCallbackHost:        getEnv("PUBLIC_CALLBACK_HOST", ""),
+		ChallengerCallbackKey:     getEnv("CHALLENGER_CALLBACK_KEY", ""),
+		ChalHMACKeyID:             getEnv("CHAL_HMAC_KEY_ID", "chal-kid-1"),
+		ChalHMACSecret:            getEnv("CHAL_HMAC_SECRET", ""),
+		CallbackCorrectness:       getEnv("CALLBACK_CORRECTNESS_MODE", "off"),
+		AnswerSubmissionMode:      getEnv("ANSWER_SUBMISSION_MODE", "first"),
+		SubmissionWindowSecs:      getEnvAsInt("SUBMISSION_WINDOW_SECONDS", 300),
+		CommitmentBatchSize:       getEnvAsInt("COMMITMENT_BATCH_SIZE", 1),
+		CommitmentBatchWaitMs:     getEnvAsInt("COMMITMENT_BATCH_WINDOW_MS", 500),
+		AllowNoSolverAddress:      getEnvAsBool("ALLOW_MISSING_SOLVER_ADDRESS", false),
+		RequireSolverSignature:    getEnvAsBool("REQUIRE_SOLVER_SIGNATURE", false),
+		WebhookAuditRetentionDays: getEnvAsInt("WEBHOOK_AUDIT_RETENTION_DAYS", 30),
 
 		// Sui Configuration
 		SUI: SuiConfig{
@@ -115,6 +267,22 @@ func Load() (*Config, error) {
 			TreasuryPos:         getEnv("SUI_TYPE_TREASURY_POS", "0x2::sui::SUI"),
 			TreasuryNeg:         getEnv("SUI_TYPE_TREASURY_NEG", "0x2::sui::SUI"),
 			Collateral:          getEnv("SUI_TYPE_COLLATERAL", "0x2::sui::SUI"),
+			RPCMaxRetries:       getEnvAsInt("SUI_RPC_MAX_RETRIES", 3),
+
+			ReadinessProbe:     getEnvAsBool("SUI_READINESS_PROBE", false),
+			ReadinessTimeoutMs: getEnvAsInt("SUI_READINESS_TIMEOUT_MS", 2000),
+
+			WSUrl:                   getEnv("SUI_WS_URL", ""),
+			EventConfirmTimeoutSecs: getEnvAsInt("SUI_EVENT_CONFIRM_TIMEOUT_SECONDS", 120),
+		},
+
+		// Deployment Target
+		DeployTarget: getEnv("DEPLOY_TARGET", "sui"),
+		ETH: EthereumConfig{
+			RPCUrl:       getEnv("ETH_RPC_URL", ""),
+			PrivateKey:   getEnv("ETH_PRIVATE_KEY", ""),
+			ChainID:      int64(getEnvAsInt("ETH_CHAIN_ID", 0)),
+			BytecodePath: getEnv("ETH_CONTRACT_BYTECODE_PATH", ""),
 		},
 
 		// Contract Configuration
@@ -129,32 +297,161 @@ func Load() (*Config, error) {
 		SolverHMACKeyID:   getEnv("SOLVER_HMAC_KEY_ID", "solver-kid-1"),
 		SolverHMACSecret:  getEnv("SOLVER_HMAC_SECRET", ""),
 
+		// Solver API Versions
+		SolverAPIVersions: getEnvAsList("SOLVER_API_VERSIONS", []string{version.APIVersion}),
+
+		// Solver Backend
+		SolverBackendURL:            getEnv("SOLVER_BACKEND_URL", ""),
+		SolverBackendTimeoutSeconds: getEnvAsInt("SOLVER_BACKEND_TIMEOUT_SECONDS", 30),
+
+		// Solver Intake (defaults to the types of the built-in mock solver)
+		SolverProblemTypes: getEnvAsList("SOLVER_PROBLEM_TYPES", []string{"captcha", "math", "text"}),
+
+		// Solver Callback Retry
+		SolverMaxRetryAttempts: getEnvAsInt("SOLVER_MAX_RETRY_ATTEMPTS", 6),
+		SolverBaseDelayMs:      getEnvAsInt("SOLVER_BASE_DELAY_MS", 500),
+		SolverMaxDelayMs:       getEnvAsInt("SOLVER_MAX_DELAY_MS", 30000),
+		SolverJitterPct:        getEnvAsInt("SOLVER_JITTER_PCT", 15),
+
+		// Solver Concurrency
+		SolverMaxQueue: getEnvAsInt("SOLVER_MAX_QUEUE", 10000),
+
 		// Shared Configuration
 		SharedSecretKey: getEnv("SHARED_SECRET_KEY", ""),
 
 		// Database
-		ChallengerDBPath: getEnv("CHALLENGER_DB_PATH", "challenger.db"),
-		SolverDBPath:     getEnv("SOLVER_DB_PATH", "solver.db"),
+		DBDriver:              getEnv("DB_DRIVER", "sqlite"),
+		ChallengerDBPath:      getEnv("CHALLENGER_DB_PATH", "challenger.db"),
+		SolverDBPath:          getEnv("SOLVER_DB_PATH", "solver.db"),
+		ChallengerDatabaseURL: getEnv("CHALLENGER_DATABASE_URL", ""),
+		SolverDatabaseURL:     getEnv("SOLVER_DATABASE_URL", ""),
+		SQLiteBusyTimeoutMs:   getEnvAsInt("SQLITE_BUSY_TIMEOUT_MS", 5000),
+		SQLiteMaxOpenConns:    getEnvAsInt("SQLITE_MAX_OPEN_CONNS", 1),
+		BackupDir:             getEnv("BACKUP_DIR", "backups"),
+
+		// Read-only handles
+		ChallengerReadDBPath:      getEnv("CHALLENGER_READ_DB_PATH", ""),
+		SolverReadDBPath:          getEnv("SOLVER_READ_DB_PATH", ""),
+		ChallengerReadDatabaseURL: getEnv("CHALLENGER_READ_DATABASE_URL", ""),
+		SolverReadDatabaseURL:     getEnv("SOLVER_READ_DATABASE_URL", ""),
 
 		// Security
-		ClockSkewSeconds: getEnvAsInt("CLOCK_SKEW_SECONDS", 300),
+		ClockSkewSeconds:       getEnvAsInt("CLOCK_SKEW_SECONDS", 300),
+		ClockSkewPastSeconds:   getEnvAsInt("CLOCK_SKEW_PAST_SECONDS", 0),
+		ClockSkewFutureSeconds: getEnvAsInt("CLOCK_SKEW_FUTURE_SECONDS", 0),
+		MaxSolverMetadataBytes: getEnvAsInt("MAX_SOLVER_METADATA_BYTES", 16*1024),
+		RateLimitRPS:           getEnvAsFloat("RATE_LIMIT_RPS", 20),
+		RateLimitBurst:         getEnvAsInt("RATE_LIMIT_BURST", 40),
+		TrustedProxies:         getEnvAsList("RATE_LIMIT_TRUSTED_PROXIES", nil),
+		CORSAllowedOrigins:     getEnvAsList("CORS_ALLOWED_ORIGINS", nil),
+		CORSAllowedMethods:     getEnvAsList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "OPTIONS"}),
+		CORSAllowedHeaders:     getEnvAsList("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Request-ID"}),
+		RequestTimeoutSecs:     getEnvAsInt("REQUEST_TIMEOUT_SECONDS", 10),
+		CallbackAllowedHosts:   getEnvAsList("CALLBACK_ALLOWED_HOSTS", []string{"localhost", "127.0.0.1", ".ngrok.io", ".ngrok-free.app"}),
+		MaxRequestBytes:        getEnvAsInt("MAX_REQUEST_BYTES", 5*1024*1024),
+		MaxCallbackBytes:       getEnvAsInt("MAX_CALLBACK_BYTES", 1024*1024),
+		MaxSolveBytes:          getEnvAsInt("MAX_SOLVE_BYTES", 0),
+		CompressionMinBytes:    getEnvAsInt("COMPRESSION_MIN_BYTES", 1024),
+		AdminAPIKey:            getEnv("ADMIN_API_KEY", ""),
+		OperatorHMACKeyIDs:     getEnvAsList("OPERATOR_HMAC_KEY_IDS", nil),
+
+		// Replay Protection
+		NonceStore:    getEnv("NONCE_STORE", "db"),
+		NonceRedisURL: getEnv("NONCE_REDIS_URL", ""),
+
+		// Outbound HTTP
+		HTTPClient: HTTPClientConfig{
+			TimeoutSecs:               getEnvAsInt("HTTP_CLIENT_TIMEOUT_SECONDS", 30),
+			DialTimeoutSecs:           getEnvAsInt("HTTP_CLIENT_DIAL_TIMEOUT_SECONDS", 5),
+			TLSHandshakeTimeoutSecs:   getEnvAsInt("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT_SECONDS", 5),
+			ResponseHeaderTimeoutSecs: getEnvAsInt("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT_SECONDS", 15),
+			IdleConnTimeoutSecs:       getEnvAsInt("HTTP_CLIENT_IDLE_CONN_TIMEOUT_SECONDS", 90),
+			MaxIdleConns:              getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS", 100),
+			MaxIdleConnsPerHost:       getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", 10),
+		},
+
+		// Event Bus
+		EventBusDriver:        getEnv("EVENT_BUS_DRIVER", "none"),
+		EventBusURL:           getEnv("EVENT_BUS_URL", ""),
+		EventBusSubjectPrefix: getEnv("EVENT_BUS_SUBJECT_PREFIX", "aibattle"),
 
 		// Logging
-		LogLevel: getEnv("LOG_LEVEL", "info"),
+		LogLevel:      getEnv("LOG_LEVEL", "info"),
+		LogDir:        getEnv("LOG_DIR", "logs"),
+		LogFormat:     getEnv("LOG_FORMAT", "console"),
+		LogMaxSizeMB:  getEnvAsInt("LOG_MAX_SIZE_MB", 100),
+		LogMaxBackups: getEnvAsInt("LOG_MAX_BACKUPS", 5),
+		LogMaxAgeDays: getEnvAsInt("LOG_MAX_AGE_DAYS", 7),
 
 		// Verifier Configuration
 		TxDigestFile: getEnv("TX_DIGEST_FILE", "./data/last_tx_digest.txt"),
 
 		// Log Service Configuration
-		LogServiceURL:    getEnv("LOG_SERVICE_URL", ""),
-		LogServiceAPIKey: getEnv("LOG_SERVICE_API_KEY", ""),
-		LogsAPIBaseURL:   getEnv("LOGS_API_BASE_URL", ""),
-		LogsAPIKey:       getEnv("LOGS_API_KEY", ""),
+		LogServiceURL:      getEnv("LOG_SERVICE_URL", ""),
+		LogServiceAPIKey:   getEnv("LOG_SERVICE_API_KEY", ""),
+		LogsAPIBaseURL:     getEnv("LOGS_API_BASE_URL", ""),
+		LogsAPIKey:         getEnv("LOGS_API_KEY", ""),
+		LogsAPIFallbackURL: getEnv("LOGS_API_FALLBACK_URL", ""),
+
+		// Log Upload Retry
+		LogUploadMaxAttempts:       getEnvAsInt("LOG_UPLOAD_MAX_ATTEMPTS", 4),
+		LogUploadBaseDelayMs:       getEnvAsInt("LOG_UPLOAD_BASE_DELAY_MS", 500),
+		LogUploadFlushIntervalSecs: getEnvAsInt("LOG_UPLOAD_FLUSH_INTERVAL_SECONDS", 60),
+
+		// Challenge Dispatch Retry
+		DispatchTimeoutSecs:       getEnvAsInt("DISPATCH_TIMEOUT_SECONDS", 10),
+		DispatchMaxAttempts:       getEnvAsInt("DISPATCH_MAX_ATTEMPTS", 3),
+		DispatchBaseDelayMs:       getEnvAsInt("DISPATCH_BASE_DELAY_MS", 500),
+		DispatchRetryIntervalSecs: getEnvAsInt("DISPATCH_RETRY_INTERVAL_SECONDS", 30),
+	}
+
+	keys, err := parseHMACKeys(getEnv("CHAL_HMAC_KEYS", ""))
+	if err != nil {
+		return nil, err
+	}
+	config.ChalHMACKeys = keys
+
+	limits, err := parseTypeLimits(getEnv("SOLVER_TYPE_LIMITS", ""))
+	if err != nil {
+		return nil, err
 	}
+	config.SolverTypeLimits = limits
 
 	return config, config.validate()
 }
 
+// parseTypeLimits decodes SOLVER_TYPE_LIMITS, a comma-separated list of type:limit pairs
+// such as "captcha:2,math:8"; an empty value means no limits
+func parseTypeLimits(raw string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || name == "" || err != nil {
+			return nil, fmt.Errorf("invalid SOLVER_TYPE_LIMITS entry %q (use type:limit)", item)
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
+
+// parseHMACKeys decodes the CHAL_HMAC_KEYS JSON list; an empty value means no extra keys
+func parseHMACKeys(raw string) ([]HMACKey, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var keys []HMACKey
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		return nil, fmt.Errorf("invalid CHAL_HMAC_KEYS: %w", err)
+	}
+	return keys, nil
+}
+
 // validate ensures all required configuration values are present and valid.
 // Checks that either shared secret or individual HMAC secrets are configured.
 // Validates that the public callback host is set for proper callback routing.
@@ -163,6 +460,195 @@ func (c *Config) validate() error {
 		return fmt.Errorf("either SHARED_SECRET_KEY or both CHAL_HMAC_SECRET and SOLVER_HMAC_SECRET must be set")
 	}
 
+	for _, key := range c.ChalHMACKeys {
+		if key.KeyID == "" || key.Secret == "" {
+			return fmt.Errorf("CHAL_HMAC_KEYS entries require key_id and secret")
+		}
+		if !key.NotBefore.IsZero() && !key.NotAfter.IsZero() && !key.NotAfter.After(key.NotBefore) {
+			return fmt.Errorf("CHAL_HMAC_KEYS key %s: not_after must be after not_before", key.KeyID)
+		}
+	}
+
+	switch c.DBDriver {
+	case "sqlite", "postgres":
+	default:
+		return fmt.Errorf("unsupported DB_DRIVER %q (use sqlite or postgres)", c.DBDriver)
+	}
+
+	if c.SQLiteBusyTimeoutMs < 0 {
+		return fmt.Errorf("SQLITE_BUSY_TIMEOUT_MS must not be negative")
+	}
+	if c.SQLiteMaxOpenConns < 0 {
+		return fmt.Errorf("SQLITE_MAX_OPEN_CONNS must not be negative")
+	}
+
+	switch c.CallbackCorrectness {
+	case "off", "body", "status":
+	default:
+		return fmt.Errorf("unsupported CALLBACK_CORRECTNESS_MODE %q (use off, body or status)", c.CallbackCorrectness)
+	}
+
+	switch c.AnswerSubmissionMode {
+	case "first", "best":
+	default:
+		return fmt.Errorf("unsupported ANSWER_SUBMISSION_MODE %q (use first or best)", c.AnswerSubmissionMode)
+	}
+	if c.SubmissionWindowSecs <= 0 {
+		return fmt.Errorf("SUBMISSION_WINDOW_SECONDS must be positive")
+	}
+
+	switch c.DeployTarget {
+	case "sui", "ethereum":
+	default:
+		return fmt.Errorf("unsupported DEPLOY_TARGET %q (use sui or ethereum)", c.DeployTarget)
+	}
+	if c.ETH.ChainID < 0 {
+		return fmt.Errorf("ETH_CHAIN_ID must not be negative")
+	}
+
+	if c.CommitmentBatchSize < 1 {
+		return fmt.Errorf("COMMITMENT_BATCH_SIZE must be at least 1")
+	}
+	if c.CommitmentBatchWaitMs < 0 {
+		return fmt.Errorf("COMMITMENT_BATCH_WINDOW_MS must not be negative")
+	}
+
+	if c.SUI.RPCMaxRetries < 0 {
+		return fmt.Errorf("SUI_RPC_MAX_RETRIES must not be negative")
+	}
+	if c.SUI.ReadinessTimeoutMs <= 0 {
+		return fmt.Errorf("SUI_READINESS_TIMEOUT_MS must be positive")
+	}
+	if c.SUI.EventConfirmTimeoutSecs <= 0 {
+		return fmt.Errorf("SUI_EVENT_CONFIRM_TIMEOUT_SECONDS must be positive")
+	}
+	if err := c.validateSui(); err != nil {
+		return err
+	}
+
+	if c.WebhookAuditRetentionDays < 0 {
+		return fmt.Errorf("WEBHOOK_AUDIT_RETENTION_DAYS must not be negative")
+	}
+
+	if c.ClockSkewPastSeconds < 0 || c.ClockSkewFutureSeconds < 0 {
+		return fmt.Errorf("CLOCK_SKEW_PAST_SECONDS and CLOCK_SKEW_FUTURE_SECONDS must not be negative")
+	}
+
+	switch c.NonceStore {
+	case "db", "memory":
+	case "redis":
+		if c.NonceRedisURL == "" {
+			return fmt.Errorf("NONCE_REDIS_URL must be set when NONCE_STORE=redis")
+		}
+	default:
+		return fmt.Errorf("unsupported NONCE_STORE %q (use db, memory, or redis)", c.NonceStore)
+	}
+
+	switch c.EventBusDriver {
+	case "none":
+	case "nats":
+		if c.EventBusURL == "" {
+			return fmt.Errorf("EVENT_BUS_URL must be set when EVENT_BUS_DRIVER=nats")
+		}
+	default:
+		return fmt.Errorf("unsupported EVENT_BUS_DRIVER %q (use none or nats)", c.EventBusDriver)
+	}
+
+	if c.SolverMaxRetryAttempts < 1 {
+		return fmt.Errorf("SOLVER_MAX_RETRY_ATTEMPTS must be at least 1")
+	}
+	if c.SolverBaseDelayMs <= 0 || c.SolverMaxDelayMs < c.SolverBaseDelayMs {
+		return fmt.Errorf("SOLVER_BASE_DELAY_MS must be positive and not exceed SOLVER_MAX_DELAY_MS")
+	}
+	if c.SolverJitterPct < 0 || c.SolverJitterPct >= 100 {
+		return fmt.Errorf("SOLVER_JITTER_PCT must be between 0 and 99")
+	}
+	if len(c.SolverAPIVersions) == 0 {
+		return fmt.Errorf("SOLVER_API_VERSIONS must list at least one version")
+	}
+	for name, limit := range c.SolverTypeLimits {
+		if limit < 1 {
+			return fmt.Errorf("SOLVER_TYPE_LIMITS limit for %q must be at least 1", name)
+		}
+	}
+	if c.SolverMaxQueue < 0 {
+		return fmt.Errorf("SOLVER_MAX_QUEUE must not be negative")
+	}
+
+	if c.MaxSolverMetadataBytes < 0 {
+		return fmt.Errorf("MAX_SOLVER_METADATA_BYTES must not be negative")
+	}
+
+	if c.RateLimitRPS < 0 {
+		return fmt.Errorf("RATE_LIMIT_RPS must not be negative")
+	}
+	if c.RateLimitRPS > 0 && c.RateLimitBurst < 1 {
+		return fmt.Errorf("RATE_LIMIT_BURST must be at least 1 when rate limiting is enabled")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("RATE_LIMIT_TRUSTED_PROXIES entry %q must be an IP or CIDR", proxy)
+		}
+	}
+
+	if c.RequestTimeoutSecs < 0 {
+		return fmt.Errorf("REQUEST_TIMEOUT_SECONDS must not be negative")
+	}
+
+	if c.MaxRequestBytes < 0 {
+		return fmt.Errorf("MAX_REQUEST_BYTES must not be negative")
+	}
+	if c.HTTPClient.TimeoutSecs < 0 || c.HTTPClient.DialTimeoutSecs < 0 || c.HTTPClient.TLSHandshakeTimeoutSecs < 0 ||
+		c.HTTPClient.ResponseHeaderTimeoutSecs < 0 || c.HTTPClient.IdleConnTimeoutSecs < 0 {
+		return fmt.Errorf("HTTP_CLIENT_*_SECONDS timeouts must not be negative")
+	}
+	if c.HTTPClient.MaxIdleConns < 0 || c.HTTPClient.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("HTTP_CLIENT_MAX_IDLE_CONNS and HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST must not be negative")
+	}
+
+	if c.LogUploadMaxAttempts < 1 {
+		return fmt.Errorf("LOG_UPLOAD_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.LogUploadBaseDelayMs < 0 {
+		return fmt.Errorf("LOG_UPLOAD_BASE_DELAY_MS must not be negative")
+	}
+	if c.LogUploadFlushIntervalSecs <= 0 {
+		return fmt.Errorf("LOG_UPLOAD_FLUSH_INTERVAL_SECONDS must be positive")
+	}
+
+	if c.DispatchTimeoutSecs <= 0 {
+		return fmt.Errorf("DISPATCH_TIMEOUT_SECONDS must be positive")
+	}
+	if c.DispatchMaxAttempts < 1 {
+		return fmt.Errorf("DISPATCH_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.DispatchBaseDelayMs < 0 {
+		return fmt.Errorf("DISPATCH_BASE_DELAY_MS must not be negative")
+	}
+	if c.DispatchRetryIntervalSecs <= 0 {
+		return fmt.Errorf("DISPATCH_RETRY_INTERVAL_SECONDS must be positive")
+	}
+
+	if c.CompressionMinBytes < 0 {
+		return fmt.Errorf("COMPRESSION_MIN_BYTES must not be negative")
+	}
+	if c.MaxCallbackBytes < 0 {
+		return fmt.Errorf("MAX_CALLBACK_BYTES must not be negative")
+	}
+	if c.MaxSolveBytes < 0 {
+		return fmt.Errorf("MAX_SOLVE_BYTES must not be negative")
+	}
+
+	if c.LogFormat != "console" && c.LogFormat != "json" {
+		return fmt.Errorf("unsupported LOG_FORMAT %q (use console or json)", c.LogFormat)
+	}
+	if c.LogMaxSizeMB < 1 {
+		return fmt.Errorf("LOG_MAX_SIZE_MB must be at least 1")
+	}
+	if c.LogMaxBackups < 0 || c.LogMaxAgeDays < 0 {
+		return fmt.Errorf("LOG_MAX_BACKUPS and LOG_MAX_AGE_DAYS must not be negative")
+	}
+
 	if c.PublicCallbackHost == "" {
 		// Provide default based on USE_NGROK setting
 		if c.UseNgrok {
@@ -188,12 +674,170 @@ func (c *Config) GetSolverAddr() string {
 	return fmt.Sprintf("%s:%s", c.SolverHost, c.SolverPort)
 }
 
+// GetChallengerDSN returns the data source for the challenger store.
+// Resolves to the Postgres connection string or the SQLite file path depending on DBDriver.
+func (c *Config) GetChallengerDSN() string {
+	if c.DBDriver == "postgres" {
+		return c.ChallengerDatabaseURL
+	}
+	return c.ChallengerDBPath
+}
+
+// GetSQLiteBusyTimeout returns how long a SQLite statement waits for a lock.
+func (c *Config) GetSQLiteBusyTimeout() time.Duration {
+	return time.Duration(c.SQLiteBusyTimeoutMs) * time.Millisecond
+}
+
+// GetSolverDSN returns the data source for the solver store.
+// Resolves to the Postgres connection string or the SQLite file path depending on DBDriver.
+func (c *Config) GetSolverDSN() string {
+	if c.DBDriver == "postgres" {
+		return c.SolverDatabaseURL
+	}
+	return c.SolverDBPath
+}
+
+// GetChallengerReadDSN returns the data source for the challenger's read-only handle.
+// Returns an empty string when no read handle is configured, meaning reads use the primary.
+func (c *Config) GetChallengerReadDSN() string {
+	if c.DBDriver == "postgres" {
+		return c.ChallengerReadDatabaseURL
+	}
+	return c.ChallengerReadDBPath
+}
+
+// GetSolverReadDSN returns the data source for the solver's read-only handle.
+// Returns an empty string when no read handle is configured, meaning reads use the primary.
+func (c *Config) GetSolverReadDSN() string {
+	if c.DBDriver == "postgres" {
+		return c.SolverReadDatabaseURL
+	}
+	return c.SolverReadDBPath
+}
+
+// GetRequestTimeout returns the per-request handler deadline as a time.Duration.
+// Zero means handlers are not bounded by the Timeout middleware.
+func (c *Config) GetRequestTimeout() time.Duration {
+	return time.Duration(c.RequestTimeoutSecs) * time.Second
+}
+
+// GetLogUploadFlushInterval returns how often queued log uploads are retried as a time.Duration.
+func (c *Config) GetLogUploadFlushInterval() time.Duration {
+	return time.Duration(c.LogUploadFlushIntervalSecs) * time.Second
+}
+
+// GetDispatchRetryInterval returns how often queued challenge dispatches are retried as a time.Duration.
+func (c *Config) GetDispatchRetryInterval() time.Duration {
+	return time.Duration(c.DispatchRetryIntervalSecs) * time.Second
+}
+
+// GetSolverBackendTimeout returns the backend solve timeout as a time.Duration.
+func (c *Config) GetSolverBackendTimeout() time.Duration {
+	return time.Duration(c.SolverBackendTimeoutSeconds) * time.Second
+}
+
+// GetSuiReadinessTimeout returns the Sui readiness probe timeout as a time.Duration.
+func (c *Config) GetSuiReadinessTimeout() time.Duration {
+	return time.Duration(c.SUI.ReadinessTimeoutMs) * time.Millisecond
+}
+
+// validateSui checks the Sui IDs and mnemonics that are set, so a typo fails at startup
+// instead of inside the first transaction build. Empty values are left to the services
+// that need them.
+func (c *Config) validateSui() error {
+	packages := []struct{ env, value string }{
+		{"SUI_PACKAGE_ID", c.SUI.PackageID},
+		{"SUI_POS_PACKAGE_ID", c.SUI.PosPackageID},
+		{"SUI_NEG_PACKAGE_ID", c.SUI.NegPackageID},
+	}
+	for _, p := range packages {
+		if p.value == "" {
+			continue
+		}
+		if _, err := sui.PackageIdFromHex(p.value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", p.env, p.value, err)
+		}
+	}
+
+	objects := []struct{ env, value string }{
+		{"SUI_REGISTRY_ID", c.SUI.RegistryID},
+		{"SUI_VAULT_ID", c.SUI.VaultID},
+		{"SUI_VAULT_ADMIN_CAP_ID", c.SUI.VaultAdminCapID},
+	}
+	for _, o := range objects {
+		if o.value == "" {
+			continue
+		}
+		if _, err := sui.ObjectIdFromHex(o.value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", o.env, o.value, err)
+		}
+	}
+
+	// The mnemonics are secrets, so only the variable name is reported
+	mnemonics := []struct{ env, value string }{
+		{"SUI_CHALLENGER_MNEMONIC", c.SUI.ChallengerMnemonic},
+		{"SUI_SOLVER_MNEMONIC", c.SUI.SolverMnemonic},
+		{"SUI_INITIALIZER_MNEMONIC", c.SUI.InitializerMnemonic},
+	}
+	for _, m := range mnemonics {
+		if m.value == "" {
+			continue
+		}
+		if _, err := suisigner.NewSignerWithMnemonic(m.value, suicrypto.KeySchemeFlagEd25519); err != nil {
+			return fmt.Errorf("invalid %s: cannot derive a signer: %w", m.env, err)
+		}
+	}
+	return nil
+}
+
+// GetSuiEventConfirmTimeout returns how long a commitment waits for its confirming event as a time.Duration.
+func (c *Config) GetSuiEventConfirmTimeout() time.Duration {
+	return time.Duration(c.SUI.EventConfirmTimeoutSecs) * time.Second
+}
+
+// GetSubmissionWindow returns the answer acceptance window as a time.Duration.
+func (c *Config) GetSubmissionWindow() time.Duration {
+	return time.Duration(c.SubmissionWindowSecs) * time.Second
+}
+
+// GetCommitmentBatchWindow returns how long queued commitments are collected before a batch is flushed.
+func (c *Config) GetCommitmentBatchWindow() time.Duration {
+	return time.Duration(c.CommitmentBatchWaitMs) * time.Millisecond
+}
+
 // GetClockSkew returns the clock skew tolerance as a time.Duration.
 // Converts the configured seconds value to a duration for HMAC validation.
 func (c *Config) GetClockSkew() time.Duration {
 	return time.Duration(c.ClockSkewSeconds) * time.Second
 }
 
+// GetClockSkewPast returns how far an HMAC timestamp may lag behind now.
+func (c *Config) GetClockSkewPast() time.Duration {
+	if c.ClockSkewPastSeconds > 0 {
+		return time.Duration(c.ClockSkewPastSeconds) * time.Second
+	}
+	return c.GetClockSkew()
+}
+
+// GetClockSkewFuture returns how far an HMAC timestamp may be ahead of now.
+func (c *Config) GetClockSkewFuture() time.Duration {
+	if c.ClockSkewFutureSeconds > 0 {
+		return time.Duration(c.ClockSkewFutureSeconds) * time.Second
+	}
+	return c.GetClockSkew()
+}
+
+// GetNonceTTL returns how long a nonce must be remembered: a signed request is accepted
+// from the past skew behind now to the future skew ahead of it.
+func (c *Config) GetNonceTTL() time.Duration {
+	return c.GetClockSkewPast() + c.GetClockSkewFuture()
+}
+
+// GetWebhookAuditRetention returns how long callback audit records are kept; zero keeps them forever.
+func (c *Config) GetWebhookAuditRetention() time.Duration {
+	return time.Duration(c.WebhookAuditRetentionDays) * 24 * time.Hour
+}
+
 // GetChallengerSecrets returns the HMAC secrets map for challenger service.
 // Includes secrets for validating requests from both challenger and solver keys.
 // Prefers shared secret configuration over individual secrets if available.
@@ -240,6 +884,56 @@ func (c *Config) GetSolverSecrets() map[string]string {
 	return secrets
 }
 
+// Key roles name the peer that signs with a primary HMAC key
+const (
+	KeyRoleChallenger = "challenger" // SOLVER_HMAC_KEY_ID: the challenger signs /solve requests
+	KeyRoleSolver     = "solver"     // CHAL_HMAC_KEY_ID: the solver signs callbacks
+)
+
+// GetKeyRoles maps each primary key ID to the peer that signs with it. Keys not listed, such
+// as CHAL_HMAC_KEYS rotation keys and keys added through /admin/keys, have no role and are
+// accepted on both /solve and /callback.
+func (c *Config) GetKeyRoles() map[string]string {
+	return map[string]string{
+		c.ChalHMACKeyID:   KeyRoleSolver,
+		c.SolverHMACKeyID: KeyRoleChallenger,
+	}
+}
+
+// GetInspectionKeyIDs returns the key IDs the challenger accepts on /challenges: its own
+// signing key plus OPERATOR_HMAC_KEY_IDS. Solvers sign with CHAL_HMAC_KEY_ID and are refused,
+// since the stored challenge holds the expected answers.
+func (c *Config) GetInspectionKeyIDs() []string {
+	return append([]string{c.SolverHMACKeyID}, c.OperatorHMACKeyIDs...)
+}
+
+// loadDotenv copies the .env file into the environment. Variables already set in the
+// process environment are kept; variables an earlier call took from the file are
+// updated, or unset when they were removed from it. A missing file is ignored.
+func loadDotenv() {
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	values, err := godotenv.Read()
+	if err != nil {
+		values = nil
+	}
+
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set && !dotenvKeys[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		dotenvKeys[key] = true
+	}
+}
+
 // getEnv retrieves an environment variable or returns a default value.
 // Helper function for loading configuration with fallback defaults.
 func getEnv(key, defaultValue string) string {
@@ -260,6 +954,34 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat retrieves an environment variable as float64 or returns a default.
+// Safely converts string environment variables to floats with error handling.
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList retrieves a comma-separated environment variable as a list or returns a default.
+// Entries are trimmed and empty entries are dropped.
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // getEnvAsBool retrieves an environment variable as boolean or returns a default.
 // Safely converts string environment variables to booleans with error handling.
 func getEnvAsBool(key string, defaultValue bool) bool {
@@ -1,20 +1,30 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 // Helper function to clear all environment variables used by the config
 func clearConfigEnv() {
 	envVars := []string{
 		"CHALLENGER_HOST", "CHALLENGER_PORT", "USE_NGROK", "PUBLIC_CALLBACK_HOST",
-		"CHALLENGER_CALLBACK_KEY", "CHAL_HMAC_KEY_ID", "CHAL_HMAC_SECRET",
+		"CHALLENGER_CALLBACK_KEY", "CHAL_HMAC_KEY_ID", "CHAL_HMAC_SECRET", "CALLBACK_CORRECTNESS_MODE", "ANSWER_SUBMISSION_MODE", "SUBMISSION_WINDOW_SECONDS", "COMMITMENT_BATCH_SIZE", "COMMITMENT_BATCH_WINDOW_MS", "ALLOW_MISSING_SOLVER_ADDRESS", "REQUIRE_SOLVER_SIGNATURE", "WEBHOOK_AUDIT_RETENTION_DAYS", "CHAL_HMAC_KEYS",
 		"SOLVER_HOST", "SOLVER_PORT", "SOLVER_API_KEY", "SOLVER_WORKER_COUNT",
-		"SOLVER_HMAC_KEY_ID", "SOLVER_HMAC_SECRET", "SHARED_SECRET_KEY",
-		"CHALLENGER_DB_PATH", "SOLVER_DB_PATH", "CLOCK_SKEW_SECONDS", "LOG_LEVEL",
-		"SUI_MNEMONIC", "SUI_PACKAGE_ID", "SUI_TYPE_TREASURY_POS", "SUI_TYPE_TREASURY_NEG", "SUI_TYPE_COLLATERAL", // Add Sui related env vars for cleanup
+		"SOLVER_HMAC_KEY_ID", "SOLVER_HMAC_SECRET", "SOLVER_API_VERSIONS", "SOLVER_PROBLEM_TYPES", "SOLVER_BACKEND_URL", "SOLVER_BACKEND_TIMEOUT_SECONDS",
+		"SOLVER_MAX_RETRY_ATTEMPTS", "SOLVER_BASE_DELAY_MS", "SOLVER_MAX_DELAY_MS", "SOLVER_JITTER_PCT", "SOLVER_TYPE_LIMITS", "SOLVER_MAX_QUEUE", "SHARED_SECRET_KEY",
+		"CHALLENGER_DB_PATH", "SOLVER_DB_PATH", "DB_DRIVER", "CHALLENGER_DATABASE_URL", "SOLVER_DATABASE_URL", "SQLITE_BUSY_TIMEOUT_MS", "SQLITE_MAX_OPEN_CONNS", "BACKUP_DIR", "CHALLENGER_READ_DB_PATH", "SOLVER_READ_DB_PATH", "CHALLENGER_READ_DATABASE_URL", "SOLVER_READ_DATABASE_URL", "CLOCK_SKEW_SECONDS", "CLOCK_SKEW_PAST_SECONDS", "CLOCK_SKEW_FUTURE_SECONDS", "MAX_SOLVER_METADATA_BYTES", "RATE_LIMIT_RPS", "RATE_LIMIT_BURST", "RATE_LIMIT_TRUSTED_PROXIES", "CORS_ALLOWED_ORIGINS", "CORS_ALLOWED_METHODS", "CORS_ALLOWED_HEADERS", "REQUEST_TIMEOUT_SECONDS", "CALLBACK_ALLOWED_HOSTS", "MAX_REQUEST_BYTES", "MAX_CALLBACK_BYTES", "MAX_SOLVE_BYTES", "COMPRESSION_MIN_BYTES", "OPERATOR_HMAC_KEY_IDS", "NONCE_STORE", "NONCE_REDIS_URL", "HTTP_CLIENT_TIMEOUT_SECONDS", "HTTP_CLIENT_DIAL_TIMEOUT_SECONDS", "HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT_SECONDS", "HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT_SECONDS", "HTTP_CLIENT_IDLE_CONN_TIMEOUT_SECONDS", "HTTP_CLIENT_MAX_IDLE_CONNS", "HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", "LOG_LEVEL", "LOG_DIR", "LOG_FORMAT", "LOG_MAX_SIZE_MB", "LOG_MAX_BACKUPS", "LOG_MAX_AGE_DAYS",
+		"EVENT_BUS_DRIVER", "EVENT_BUS_URL", "EVENT_BUS_SUBJECT_PREFIX",
+		"LOG_SERVICE_URL", "LOG_SERVICE_API_KEY", "LOGS_API_BASE_URL", "LOGS_API_KEY", "LOGS_API_FALLBACK_URL", "LOG_UPLOAD_MAX_ATTEMPTS", "LOG_UPLOAD_BASE_DELAY_MS", "LOG_UPLOAD_FLUSH_INTERVAL_SECONDS",
+		"DISPATCH_TIMEOUT_SECONDS", "DISPATCH_MAX_ATTEMPTS", "DISPATCH_BASE_DELAY_MS", "DISPATCH_RETRY_INTERVAL_SECONDS",
+		"SUI_CHALLENGER_MNEMONIC", "SUI_SOLVER_MNEMONIC", "SUI_INITIALIZER_MNEMONIC", "SUI_PACKAGE_ID", "SUI_REGISTRY_ID", "SUI_POS_PACKAGE_ID", "SUI_NEG_PACKAGE_ID", "SUI_VAULT_ID", "SUI_VAULT_ADMIN_CAP_ID", "SUI_TYPE_TREASURY_POS", "SUI_TYPE_TREASURY_NEG", "SUI_TYPE_COLLATERAL", "SUI_RPC_MAX_RETRIES", "SUI_READINESS_PROBE", "SUI_READINESS_TIMEOUT_MS", "SUI_WS_URL", "SUI_EVENT_CONFIRM_TIMEOUT_SECONDS", "DEPLOY_TARGET", "ETH_RPC_URL", "ETH_PRIVATE_KEY", "ETH_CHAIN_ID", "ETH_CONTRACT_BYTECODE_PATH", // Add Sui related env vars for cleanup
 	}
 	for _, envVar := range envVars {
 		os.Unsetenv(envVar)
@@ -59,9 +69,40 @@ func TestConfig_Load_WithDefaults(t *testing.T) {
 		t.Errorf("Expected ClockSkewSeconds 300, got %d", config.ClockSkewSeconds)
 	}
 
+	if config.SolverBackendURL != "" {
+		t.Errorf("Expected empty SolverBackendURL, got '%s'", config.SolverBackendURL)
+	}
+
+	if config.GetSolverBackendTimeout() != 30*time.Second {
+		t.Errorf("Expected solver backend timeout 30s, got %v", config.GetSolverBackendTimeout())
+	}
+	if config.SolverMaxRetryAttempts != 6 || config.SolverBaseDelayMs != 500 || config.SolverMaxDelayMs != 30000 || config.SolverJitterPct != 15 {
+		t.Errorf("Unexpected retry defaults: attempts=%d base=%d max=%d jitter=%d",
+			config.SolverMaxRetryAttempts, config.SolverBaseDelayMs, config.SolverMaxDelayMs, config.SolverJitterPct)
+	}
+	if config.EventBusDriver != "none" {
+		t.Errorf("Expected event bus driver none, got %s", config.EventBusDriver)
+	}
+
+	if config.MaxSolverMetadataBytes != 16*1024 {
+		t.Errorf("Expected MaxSolverMetadataBytes 16384, got %d", config.MaxSolverMetadataBytes)
+	}
+	if config.RateLimitRPS != 20 || config.RateLimitBurst != 40 {
+		t.Errorf("Expected rate limit 20 rps / burst 40, got %v / %d", config.RateLimitRPS, config.RateLimitBurst)
+	}
+
 	if config.LogLevel != "info" {
 		t.Errorf("Expected LogLevel 'info', got '%s'", config.LogLevel)
 	}
+	if config.LogDir != "logs" {
+		t.Errorf("Expected LogDir 'logs', got '%s'", config.LogDir)
+	}
+	if config.AllowNoSolverAddress {
+		t.Error("Expected AllowNoSolverAddress to default to false")
+	}
+	if config.RequireSolverSignature {
+		t.Error("Expected RequireSolverSignature to default to false")
+	}
 
 	if config.ChallengerDBPath != "challenger.db" {
 		t.Errorf("Expected ChallengerDBPath 'challenger.db', got '%s'", config.ChallengerDBPath)
@@ -71,6 +112,18 @@ func TestConfig_Load_WithDefaults(t *testing.T) {
 		t.Errorf("Expected SolverDBPath 'solver.db', got '%s'", config.SolverDBPath)
 	}
 
+	if config.DBDriver != "sqlite" {
+		t.Errorf("Expected DBDriver 'sqlite', got '%s'", config.DBDriver)
+	}
+
+	if config.GetChallengerDSN() != "challenger.db" {
+		t.Errorf("Expected challenger DSN 'challenger.db', got '%s'", config.GetChallengerDSN())
+	}
+
+	if config.GetChallengerReadDSN() != "" {
+		t.Errorf("Expected no challenger read DSN by default, got '%s'", config.GetChallengerReadDSN())
+	}
+
 	if config.ChalHMACKeyID != "chal-kid-1" {
 		t.Errorf("Expected ChalHMACKeyID 'chal-kid-1', got '%s'", config.ChalHMACKeyID)
 	}
@@ -95,9 +148,10 @@ func TestConfig_Load_WithCustomValues(t *testing.T) {
 	os.Setenv("SOLVER_DB_PATH", "/tmp/custom-solver.db")
 	os.Setenv("CLOCK_SKEW_SECONDS", "600")
 	os.Setenv("LOG_LEVEL", "debug")
+	os.Setenv("LOG_DIR", "/var/log/aibattle")
 	os.Setenv("CHAL_HMAC_KEY_ID", "custom-chal-key")
 	os.Setenv("SOLVER_HMAC_KEY_ID", "custom-solver-key")
-	os.Setenv("SUI_MNEMONIC", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+	os.Setenv("SUI_CHALLENGER_MNEMONIC", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
 	os.Setenv("SUI_PACKAGE_ID", "0x1234567890abcdef1234567890abcdef12345678")
 	os.Setenv("SUI_TYPE_TREASURY_POS", "0x2::custom::TOKEN")
 	os.Setenv("SUI_TYPE_TREASURY_NEG", "0x2::custom::TOKEN2")
@@ -141,6 +195,9 @@ func TestConfig_Load_WithCustomValues(t *testing.T) {
 	if config.LogLevel != "debug" {
 		t.Errorf("Expected LogLevel 'debug', got '%s'", config.LogLevel)
 	}
+	if config.LogDir != "/var/log/aibattle" {
+		t.Errorf("Expected LogDir '/var/log/aibattle', got '%s'", config.LogDir)
+	}
 
 	if config.ChallengerDBPath != "/tmp/custom-challenger.db" {
 		t.Errorf("Expected ChallengerDBPath '/tmp/custom-challenger.db', got '%s'", config.ChallengerDBPath)
@@ -158,8 +215,8 @@ func TestConfig_Load_WithCustomValues(t *testing.T) {
 		t.Errorf("Expected SolverHMACKeyID 'custom-solver-key', got '%s'", config.SolverHMACKeyID)
 	}
 
-	if config.SUI.Mnemonic != "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about" {
-		t.Errorf("Expected Mnemonic to match custom value, got '%s'", config.SUI.Mnemonic)
+	if config.SUI.ChallengerMnemonic != "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about" {
+		t.Errorf("Expected ChallengerMnemonic to match custom value, got '%s'", config.SUI.ChallengerMnemonic)
 	}
 
 	if config.SUI.PackageID != "0x1234567890abcdef1234567890abcdef12345678" {
@@ -269,6 +326,1377 @@ func TestConfig_Validation_BothIndividualSecrets(t *testing.T) {
 	}
 }
 
+func TestConfig_Load_PostgresDriver(t *testing.T) {
+	clearConfigEnv()
+
+	os.Setenv("SHARED_SECRET_KEY", "test-secret")
+	os.Setenv("DB_DRIVER", "postgres")
+	os.Setenv("CHALLENGER_DATABASE_URL", "postgres://localhost/challenger")
+	os.Setenv("SOLVER_DATABASE_URL", "postgres://localhost/solver")
+	os.Setenv("CHALLENGER_READ_DATABASE_URL", "postgres://replica/challenger")
+	os.Setenv("CHALLENGER_READ_DB_PATH", "ignored.db")
+	defer clearConfigEnv()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.GetChallengerDSN() != "postgres://localhost/challenger" {
+		t.Errorf("Expected challenger DSN to be the Postgres URL, got '%s'", config.GetChallengerDSN())
+	}
+
+	if config.GetSolverDSN() != "postgres://localhost/solver" {
+		t.Errorf("Expected solver DSN to be the Postgres URL, got '%s'", config.GetSolverDSN())
+	}
+
+	if config.GetChallengerReadDSN() != "postgres://replica/challenger" {
+		t.Errorf("Expected challenger read DSN to be the replica URL, got '%s'", config.GetChallengerReadDSN())
+	}
+
+	// No replica configured: reads fall back to the primary
+	if config.GetSolverReadDSN() != "" {
+		t.Errorf("Expected empty solver read DSN, got '%s'", config.GetSolverReadDSN())
+	}
+}
+
+func TestConfig_Validation_UnknownDBDriver(t *testing.T) {
+	clearConfigEnv()
+
+	os.Setenv("SHARED_SECRET_KEY", "test-secret")
+	os.Setenv("DB_DRIVER", "mysql")
+	defer clearConfigEnv()
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error for unsupported DB_DRIVER")
+	}
+}
+
+func TestConfig_Validation_UnknownCallbackCorrectnessMode(t *testing.T) {
+	clearConfigEnv()
+
+	os.Setenv("SHARED_SECRET_KEY", "test-secret")
+	os.Setenv("CALLBACK_CORRECTNESS_MODE", "loud")
+	defer clearConfigEnv()
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error for unsupported CALLBACK_CORRECTNESS_MODE")
+	}
+}
+
+func TestConfig_Validation_AnswerSubmission(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		window     string
+		wantWindow time.Duration
+		wantErr    bool
+	}{
+		{"best mode", "best", "120", 2 * time.Minute, false},
+		{"first mode", "first", "300", 5 * time.Minute, false},
+		{"unknown mode", "latest", "300", 0, true},
+		{"zero window", "best", "0", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("ANSWER_SUBMISSION_MODE", tt.mode)
+			os.Setenv("SUBMISSION_WINDOW_SECONDS", tt.window)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.GetSubmissionWindow() != tt.wantWindow {
+				t.Errorf("expected submission window %v, got %v", tt.wantWindow, cfg.GetSubmissionWindow())
+			}
+		})
+	}
+}
+
+func TestConfig_Validation_EventBus(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		url     string
+		wantErr bool
+	}{
+		{"nats with url", "nats", "nats://localhost:4222", false},
+		{"nats without url", "nats", "", true},
+		{"unknown driver", "kafka", "localhost:9092", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("EVENT_BUS_DRIVER", tt.driver)
+			os.Setenv("EVENT_BUS_URL", tt.url)
+
+			_, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validation_SolverRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{"zero attempts", "SOLVER_MAX_RETRY_ATTEMPTS", "0"},
+		{"max below base", "SOLVER_MAX_DELAY_MS", "100"},
+		{"jitter too large", "SOLVER_JITTER_PCT", "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv(tt.key, tt.val)
+
+			if _, err := Load(); err == nil {
+				t.Errorf("Expected error for %s=%s", tt.key, tt.val)
+			}
+		})
+	}
+}
+
+func TestConfig_Validation_RateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		rps     string
+		burst   string
+		wantErr bool
+	}{
+		{"fractional rps", "0.5", "1", false},
+		{"disabled ignores burst", "0", "0", false},
+		{"negative rps", "-1", "10", true},
+		{"zero burst", "5", "0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("RATE_LIMIT_RPS", tt.rps)
+			os.Setenv("RATE_LIMIT_BURST", tt.burst)
+
+			_, err := Load()
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected error for RATE_LIMIT_RPS=%s RATE_LIMIT_BURST=%s", tt.rps, tt.burst)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Validation_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies string
+		wantErr bool
+	}{
+		{"unset", "", false},
+		{"ips and cidrs", "10.0.0.1, 192.168.0.0/16, 2001:db8::/32", false},
+		{"hostname", "proxy.internal", true},
+		{"bad cidr", "10.0.0.0/33", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("RATE_LIMIT_TRUSTED_PROXIES", tt.proxies)
+
+			_, err := Load()
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected error for RATE_LIMIT_TRUSTED_PROXIES=%s", tt.proxies)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_RequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"default", "", 10 * time.Second, false},
+		{"custom", "45", 45 * time.Second, false},
+		{"disabled", "0", 0, false},
+		{"negative", "-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("REQUEST_TIMEOUT_SECONDS", tt.value)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.GetRequestTimeout() != tt.want {
+				t.Errorf("Expected request timeout %v, got %v", tt.want, cfg.GetRequestTimeout())
+			}
+		})
+	}
+}
+
+func TestConfig_LogRotation(t *testing.T) {
+	tests := []struct {
+		name                               string
+		size, backups, age                 string
+		wantSize, wantBackups, wantAgeDays int
+		wantErr                            bool
+	}{
+		{"defaults", "", "", "", 100, 5, 7, false},
+		{"custom", "10", "3", "30", 10, 3, 30, false},
+		{"keep all", "50", "0", "0", 50, 0, 0, false},
+		{"zero size", "0", "", "", 0, 0, 0, true},
+		{"negative backups", "", "-1", "", 0, 0, 0, true},
+		{"negative age", "", "", "-1", 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("LOG_MAX_SIZE_MB", tt.size)
+			os.Setenv("LOG_MAX_BACKUPS", tt.backups)
+			os.Setenv("LOG_MAX_AGE_DAYS", tt.age)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.LogMaxSizeMB != tt.wantSize || cfg.LogMaxBackups != tt.wantBackups || cfg.LogMaxAgeDays != tt.wantAgeDays {
+				t.Errorf("Expected rotation %d/%d/%d, got %d/%d/%d", tt.wantSize, tt.wantBackups, tt.wantAgeDays,
+					cfg.LogMaxSizeMB, cfg.LogMaxBackups, cfg.LogMaxAgeDays)
+			}
+		})
+	}
+}
+
+func TestConfig_LogFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", "console", false},
+		{"json", "json", "json", false},
+		{"console", "console", "console", false},
+		{"unsupported", "logfmt", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("LOG_FORMAT", tt.value)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.LogFormat != tt.want {
+				t.Errorf("Expected LogFormat %q, got %q", tt.want, cfg.LogFormat)
+			}
+		})
+	}
+}
+
+func TestConfig_CommitmentBatching(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       string
+		window     string
+		wantSize   int
+		wantWindow time.Duration
+		wantErr    bool
+	}{
+		{"defaults", "", "", 1, 500 * time.Millisecond, false},
+		{"custom", "20", "250", 20, 250 * time.Millisecond, false},
+		{"zero size", "0", "", 0, 0, true},
+		{"negative window", "", "-1", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("COMMITMENT_BATCH_SIZE", tt.size)
+			os.Setenv("COMMITMENT_BATCH_WINDOW_MS", tt.window)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.CommitmentBatchSize != tt.wantSize {
+				t.Errorf("Expected batch size %d, got %d", tt.wantSize, cfg.CommitmentBatchSize)
+			}
+			if cfg.GetCommitmentBatchWindow() != tt.wantWindow {
+				t.Errorf("Expected batch window %v, got %v", tt.wantWindow, cfg.GetCommitmentBatchWindow())
+			}
+		})
+	}
+}
+
+func TestConfig_DeployTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", "sui", false},
+		{"ethereum", "ethereum", "ethereum", false},
+		{"unsupported", "solana", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("DEPLOY_TARGET", tt.target)
+			os.Setenv("ETH_RPC_URL", "http://127.0.0.1:8545")
+			os.Setenv("ETH_CHAIN_ID", "31337")
+			os.Setenv("ETH_CONTRACT_BYTECODE_PATH", "./registry.bin")
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.DeployTarget != tt.want {
+				t.Errorf("Expected deploy target %q, got %q", tt.want, cfg.DeployTarget)
+			}
+			if cfg.ETH.RPCUrl != "http://127.0.0.1:8545" || cfg.ETH.ChainID != 31337 || cfg.ETH.BytecodePath != "./registry.bin" {
+				t.Errorf("Unexpected Ethereum config: %+v", cfg.ETH)
+			}
+		})
+	}
+}
+
+func TestConfig_SuiRPCMaxRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"default", "", 3, false},
+		{"custom", "6", 6, false},
+		{"disabled", "0", 0, false},
+		{"negative", "-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("SUI_RPC_MAX_RETRIES", tt.value)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.SUI.RPCMaxRetries != tt.want {
+				t.Errorf("Expected %d Sui RPC retries, got %d", tt.want, cfg.SUI.RPCMaxRetries)
+			}
+		})
+	}
+}
+
+func TestConfig_SuiReadinessProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		probe       string
+		timeout     string
+		wantProbe   bool
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{name: "default", wantProbe: false, wantTimeout: 2 * time.Second},
+		{name: "enabled", probe: "true", timeout: "500", wantProbe: true, wantTimeout: 500 * time.Millisecond},
+		{name: "zero timeout", probe: "true", timeout: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("SUI_READINESS_PROBE", tt.probe)
+			os.Setenv("SUI_READINESS_TIMEOUT_MS", tt.timeout)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.SUI.ReadinessProbe != tt.wantProbe || cfg.GetSuiReadinessTimeout() != tt.wantTimeout {
+				t.Errorf("Expected probe %v with timeout %v, got %v with %v",
+					tt.wantProbe, tt.wantTimeout, cfg.SUI.ReadinessProbe, cfg.GetSuiReadinessTimeout())
+			}
+		})
+	}
+}
+
+func TestConfig_CORSLists(t *testing.T) {
+	clearConfigEnv()
+	defer clearConfigEnv()
+
+	os.Setenv("SHARED_SECRET_KEY", "test-secret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cfg.CORSAllowedOrigins) != 0 {
+		t.Errorf("Expected no CORS origins by default, got %v", cfg.CORSAllowedOrigins)
+	}
+	if !reflect.DeepEqual(cfg.CORSAllowedMethods, []string{"GET", "POST", "OPTIONS"}) {
+		t.Errorf("Unexpected default CORS methods: %v", cfg.CORSAllowedMethods)
+	}
+
+	os.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com, ,https://b.example.com ")
+	os.Setenv("CORS_ALLOWED_HEADERS", "Content-Type")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"https://a.example.com", "https://b.example.com"}; !reflect.DeepEqual(cfg.CORSAllowedOrigins, want) {
+		t.Errorf("Expected origins %v, got %v", want, cfg.CORSAllowedOrigins)
+	}
+	if want := []string{"Content-Type"}; !reflect.DeepEqual(cfg.CORSAllowedHeaders, want) {
+		t.Errorf("Expected headers %v, got %v", want, cfg.CORSAllowedHeaders)
+	}
+}
+
+func TestConfig_CallbackAllowedHosts(t *testing.T) {
+	clearConfigEnv()
+	defer clearConfigEnv()
+
+	os.Setenv("SHARED_SECRET_KEY", "test-secret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"localhost", "127.0.0.1", ".ngrok.io", ".ngrok-free.app"}; !reflect.DeepEqual(cfg.CallbackAllowedHosts, want) {
+		t.Errorf("Expected default callback hosts %v, got %v", want, cfg.CallbackAllowedHosts)
+	}
+
+	os.Setenv("CALLBACK_ALLOWED_HOSTS", "challenger.internal, .example.com")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"challenger.internal", ".example.com"}; !reflect.DeepEqual(cfg.CallbackAllowedHosts, want) {
+		t.Errorf("Expected callback hosts %v, got %v", want, cfg.CallbackAllowedHosts)
+	}
+}
+
+func TestConfig_RequestBodyLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRequest   string
+		maxCallback  string
+		maxSolve     string
+		wantRequest  int
+		wantCallback int
+		wantSolve    int
+		wantErr      bool
+	}{
+		{name: "defaults", wantRequest: 5 * 1024 * 1024, wantCallback: 1024 * 1024},
+		{name: "custom", maxRequest: "20971520", maxCallback: "65536", wantRequest: 20971520, wantCallback: 65536},
+		{name: "disabled", maxRequest: "0", maxCallback: "0", wantRequest: 0, wantCallback: 0},
+		{name: "solve limit above request limit", maxSolve: "52428800", wantRequest: 5 * 1024 * 1024, wantCallback: 1024 * 1024, wantSolve: 52428800},
+		{name: "negative request limit", maxRequest: "-1", wantErr: true},
+		{name: "negative callback limit", maxCallback: "-1", wantErr: true},
+		{name: "negative solve limit", maxSolve: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.maxRequest != "" {
+				os.Setenv("MAX_REQUEST_BYTES", tt.maxRequest)
+			}
+			if tt.maxCallback != "" {
+				os.Setenv("MAX_CALLBACK_BYTES", tt.maxCallback)
+			}
+			if tt.maxSolve != "" {
+				os.Setenv("MAX_SOLVE_BYTES", tt.maxSolve)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.MaxRequestBytes != tt.wantRequest || cfg.MaxCallbackBytes != tt.wantCallback {
+				t.Errorf("Expected limits %d/%d, got %d/%d", tt.wantRequest, tt.wantCallback, cfg.MaxRequestBytes, cfg.MaxCallbackBytes)
+			}
+			if cfg.MaxSolveBytes != tt.wantSolve {
+				t.Errorf("Expected solve limit %d, got %d", tt.wantSolve, cfg.MaxSolveBytes)
+			}
+		})
+	}
+}
+
+func TestConfig_CompressionMinBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		minBytes string
+		want     int
+		wantErr  bool
+	}{
+		{name: "default", want: 1024},
+		{name: "custom", minBytes: "4096", want: 4096},
+		{name: "compress everything", minBytes: "0", want: 0},
+		{name: "negative", minBytes: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.minBytes != "" {
+				os.Setenv("COMPRESSION_MIN_BYTES", tt.minBytes)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.CompressionMinBytes != tt.want {
+				t.Errorf("Expected COMPRESSION_MIN_BYTES %d, got %d", tt.want, cfg.CompressionMinBytes)
+			}
+		})
+	}
+}
+
+func TestConfig_HTTPClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    HTTPClientConfig
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: HTTPClientConfig{TimeoutSecs: 30, DialTimeoutSecs: 5, TLSHandshakeTimeoutSecs: 5, ResponseHeaderTimeoutSecs: 15,
+				IdleConnTimeoutSecs: 90, MaxIdleConns: 100, MaxIdleConnsPerHost: 10},
+		},
+		{
+			name: "custom",
+			env:  map[string]string{"HTTP_CLIENT_TIMEOUT_SECONDS": "60", "HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST": "25"},
+			want: HTTPClientConfig{TimeoutSecs: 60, DialTimeoutSecs: 5, TLSHandshakeTimeoutSecs: 5, ResponseHeaderTimeoutSecs: 15,
+				IdleConnTimeoutSecs: 90, MaxIdleConns: 100, MaxIdleConnsPerHost: 25},
+		},
+		{name: "negative timeout", env: map[string]string{"HTTP_CLIENT_DIAL_TIMEOUT_SECONDS": "-1"}, wantErr: true},
+		{name: "negative idle connections", env: map[string]string{"HTTP_CLIENT_MAX_IDLE_CONNS": "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.HTTPClient != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, cfg.HTTPClient)
+			}
+		})
+	}
+}
+
+func TestConfig_LogUploadRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantAttempts int
+		wantDelayMs  int
+		wantInterval time.Duration
+		wantErr      bool
+	}{
+		{name: "defaults", wantAttempts: 4, wantDelayMs: 500, wantInterval: time.Minute},
+		{
+			name:         "custom",
+			env:          map[string]string{"LOG_UPLOAD_MAX_ATTEMPTS": "2", "LOG_UPLOAD_BASE_DELAY_MS": "100", "LOG_UPLOAD_FLUSH_INTERVAL_SECONDS": "5"},
+			wantAttempts: 2, wantDelayMs: 100, wantInterval: 5 * time.Second,
+		},
+		{name: "zero attempts", env: map[string]string{"LOG_UPLOAD_MAX_ATTEMPTS": "0"}, wantErr: true},
+		{name: "negative delay", env: map[string]string{"LOG_UPLOAD_BASE_DELAY_MS": "-1"}, wantErr: true},
+		{name: "zero flush interval", env: map[string]string{"LOG_UPLOAD_FLUSH_INTERVAL_SECONDS": "0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.LogUploadMaxAttempts != tt.wantAttempts || cfg.LogUploadBaseDelayMs != tt.wantDelayMs {
+				t.Errorf("Expected %d attempts and %dms base delay, got %d and %dms",
+					tt.wantAttempts, tt.wantDelayMs, cfg.LogUploadMaxAttempts, cfg.LogUploadBaseDelayMs)
+			}
+			if got := cfg.GetLogUploadFlushInterval(); got != tt.wantInterval {
+				t.Errorf("Expected flush interval %v, got %v", tt.wantInterval, got)
+			}
+		})
+	}
+}
+
+func TestConfig_DispatchRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantTimeout  int
+		wantAttempts int
+		wantDelayMs  int
+		wantInterval time.Duration
+		wantErr      string
+	}{
+		{name: "defaults", wantTimeout: 10, wantAttempts: 3, wantDelayMs: 500, wantInterval: 30 * time.Second},
+		{
+			name: "custom",
+			env: map[string]string{"DISPATCH_TIMEOUT_SECONDS": "2", "DISPATCH_MAX_ATTEMPTS": "5",
+				"DISPATCH_BASE_DELAY_MS": "100", "DISPATCH_RETRY_INTERVAL_SECONDS": "5"},
+			wantTimeout: 2, wantAttempts: 5, wantDelayMs: 100, wantInterval: 5 * time.Second,
+		},
+		{name: "zero timeout", env: map[string]string{"DISPATCH_TIMEOUT_SECONDS": "0"}, wantErr: "DISPATCH_TIMEOUT_SECONDS"},
+		{name: "zero attempts", env: map[string]string{"DISPATCH_MAX_ATTEMPTS": "0"}, wantErr: "DISPATCH_MAX_ATTEMPTS"},
+		{name: "negative delay", env: map[string]string{"DISPATCH_BASE_DELAY_MS": "-1"}, wantErr: "DISPATCH_BASE_DELAY_MS"},
+		{name: "zero retry interval", env: map[string]string{"DISPATCH_RETRY_INTERVAL_SECONDS": "0"}, wantErr: "DISPATCH_RETRY_INTERVAL_SECONDS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected a %s error, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.DispatchTimeoutSecs != tt.wantTimeout || cfg.DispatchMaxAttempts != tt.wantAttempts || cfg.DispatchBaseDelayMs != tt.wantDelayMs {
+				t.Errorf("Expected %ds timeout, %d attempts and %dms base delay, got %ds, %d and %dms",
+					tt.wantTimeout, tt.wantAttempts, tt.wantDelayMs, cfg.DispatchTimeoutSecs, cfg.DispatchMaxAttempts, cfg.DispatchBaseDelayMs)
+			}
+			if got := cfg.GetDispatchRetryInterval(); got != tt.wantInterval {
+				t.Errorf("Expected retry interval %v, got %v", tt.wantInterval, got)
+			}
+		})
+	}
+}
+
+func TestConfig_SolverMaxQueue(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxQueue string
+		want     int
+		wantErr  bool
+	}{
+		{name: "unset", want: 10000},
+		{name: "custom", maxQueue: "500", want: 500},
+		{name: "unlimited", maxQueue: "0", want: 0},
+		{name: "negative", maxQueue: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.maxQueue != "" {
+				os.Setenv("SOLVER_MAX_QUEUE", tt.maxQueue)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "SOLVER_MAX_QUEUE") {
+					t.Errorf("Expected a SOLVER_MAX_QUEUE error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.SolverMaxQueue != tt.want {
+				t.Errorf("Expected SolverMaxQueue %d, got %d", tt.want, cfg.SolverMaxQueue)
+			}
+		})
+	}
+}
+
+func TestConfig_NonceStore(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    string
+		redisURL string
+		want     string
+		wantErr  bool
+	}{
+		{name: "unset", want: "db"},
+		{name: "memory", store: "memory", want: "memory"},
+		{name: "redis", store: "redis", redisURL: "redis://localhost:6379", want: "redis"},
+		{name: "redis without url", store: "redis", wantErr: true},
+		{name: "unknown", store: "memcached", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.store != "" {
+				os.Setenv("NONCE_STORE", tt.store)
+			}
+			if tt.redisURL != "" {
+				os.Setenv("NONCE_REDIS_URL", tt.redisURL)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "NONCE_") {
+					t.Errorf("Expected a NONCE_STORE error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.NonceStore != tt.want {
+				t.Errorf("Expected NonceStore %s, got %s", tt.want, cfg.NonceStore)
+			}
+			if got := cfg.GetNonceTTL(); got != 600*time.Second {
+				t.Errorf("Expected a nonce ttl of twice the clock skew, got %v", got)
+			}
+		})
+	}
+}
+
+func TestConfig_AsymmetricClockSkew(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantPast   time.Duration
+		wantFuture time.Duration
+		wantErr    bool
+	}{
+		{name: "unset", wantPast: 300 * time.Second, wantFuture: 300 * time.Second},
+		{name: "symmetric override", env: map[string]string{"CLOCK_SKEW_SECONDS": "120"}, wantPast: 120 * time.Second, wantFuture: 120 * time.Second},
+		{name: "asymmetric", env: map[string]string{"CLOCK_SKEW_PAST_SECONDS": "600", "CLOCK_SKEW_FUTURE_SECONDS": "30"}, wantPast: 600 * time.Second, wantFuture: 30 * time.Second},
+		{name: "future only", env: map[string]string{"CLOCK_SKEW_FUTURE_SECONDS": "30"}, wantPast: 300 * time.Second, wantFuture: 30 * time.Second},
+		{name: "negative", env: map[string]string{"CLOCK_SKEW_FUTURE_SECONDS": "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "CLOCK_SKEW_") {
+					t.Errorf("Expected a clock skew error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := cfg.GetClockSkewPast(); got != tt.wantPast {
+				t.Errorf("Expected past skew %v, got %v", tt.wantPast, got)
+			}
+			if got := cfg.GetClockSkewFuture(); got != tt.wantFuture {
+				t.Errorf("Expected future skew %v, got %v", tt.wantFuture, got)
+			}
+			if got := cfg.GetNonceTTL(); got != tt.wantPast+tt.wantFuture {
+				t.Errorf("Expected nonce ttl %v, got %v", tt.wantPast+tt.wantFuture, got)
+			}
+		})
+	}
+}
+
+func TestConfig_WebhookAuditRetention(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: 30 * 24 * time.Hour},
+		{name: "custom", value: "7", want: 7 * 24 * time.Hour},
+		{name: "keep forever", value: "0", want: 0},
+		{name: "negative", value: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.value != "" {
+				os.Setenv("WEBHOOK_AUDIT_RETENTION_DAYS", tt.value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "WEBHOOK_AUDIT_RETENTION_DAYS") {
+					t.Errorf("Expected a WEBHOOK_AUDIT_RETENTION_DAYS error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := cfg.GetWebhookAuditRetention(); got != tt.want {
+				t.Errorf("Expected retention %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfig_SQLitePool(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantTimeout time.Duration
+		wantConns   int
+		wantErr     string
+	}{
+		{name: "defaults", wantTimeout: 5 * time.Second, wantConns: 1},
+		{name: "custom", env: map[string]string{"SQLITE_BUSY_TIMEOUT_MS": "15000", "SQLITE_MAX_OPEN_CONNS": "4"}, wantTimeout: 15 * time.Second, wantConns: 4},
+		{name: "unbounded pool", env: map[string]string{"SQLITE_MAX_OPEN_CONNS": "0"}, wantTimeout: 5 * time.Second, wantConns: 0},
+		{name: "negative timeout", env: map[string]string{"SQLITE_BUSY_TIMEOUT_MS": "-1"}, wantErr: "SQLITE_BUSY_TIMEOUT_MS"},
+		{name: "negative conns", env: map[string]string{"SQLITE_MAX_OPEN_CONNS": "-1"}, wantErr: "SQLITE_MAX_OPEN_CONNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error mentioning %s, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := cfg.GetSQLiteBusyTimeout(); got != tt.wantTimeout {
+				t.Errorf("Expected busy timeout %v, got %v", tt.wantTimeout, got)
+			}
+			if cfg.SQLiteMaxOpenConns != tt.wantConns {
+				t.Errorf("Expected %d max open conns, got %d", tt.wantConns, cfg.SQLiteMaxOpenConns)
+			}
+		})
+	}
+}
+
+func TestConfig_SuiEventSubscription(t *testing.T) {
+	tests := []struct {
+		name        string
+		wsURL       string
+		timeout     string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{name: "default", wantTimeout: 120 * time.Second},
+		{name: "custom", wsURL: "ws://127.0.0.1:9000", timeout: "30", wantTimeout: 30 * time.Second},
+		{name: "zero timeout", wsURL: "ws://127.0.0.1:9000", timeout: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			os.Setenv("SUI_WS_URL", tt.wsURL)
+			os.Setenv("SUI_EVENT_CONFIRM_TIMEOUT_SECONDS", tt.timeout)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.SUI.WSUrl != tt.wsURL || cfg.GetSuiEventConfirmTimeout() != tt.wantTimeout {
+				t.Errorf("Expected %q with timeout %v, got %q with %v",
+					tt.wsURL, tt.wantTimeout, cfg.SUI.WSUrl, cfg.GetSuiEventConfirmTimeout())
+			}
+		})
+	}
+}
+
+func TestConfig_SuiIDValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "valid IDs and mnemonic",
+			env: map[string]string{
+				"SUI_PACKAGE_ID":          "0x1234567890abcdef1234567890abcdef12345678",
+				"SUI_REGISTRY_ID":         "0x00000000000000000000000000000000000000000000000000000000000000a1",
+				"SUI_VAULT_ID":            "0xb1",
+				"SUI_CHALLENGER_MNEMONIC": "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			},
+		},
+		{
+			name:    "malformed package ID",
+			env:     map[string]string{"SUI_PACKAGE_ID": "0xnot-hex"},
+			wantErr: "SUI_PACKAGE_ID",
+		},
+		{
+			name:    "malformed registry ID",
+			env:     map[string]string{"SUI_REGISTRY_ID": "registry"},
+			wantErr: "SUI_REGISTRY_ID",
+		},
+		{
+			name:    "registry ID too long",
+			env:     map[string]string{"SUI_REGISTRY_ID": "0x" + strings.Repeat("a", 65)},
+			wantErr: "SUI_REGISTRY_ID",
+		},
+		{
+			name:    "invalid mnemonic",
+			env:     map[string]string{"SUI_SOLVER_MNEMONIC": "not a real mnemonic"},
+			wantErr: "SUI_SOLVER_MNEMONIC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			_, err := Load()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to name %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_LogSafe(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	cfg := &Config{
+		ChallengerCallbackKey: "secret-callback-key",
+		ChalHMACSecret:        "secret-chal-hmac",
+		ChalHMACKeys:          []HMACKey{{KeyID: "kid-2", Secret: "secret-rotated-hmac"}},
+		SolverAPIKey:          "secret-solver-api",
+		SolverHMACSecret:      "secret-solver-hmac",
+		SharedSecretKey:       "secret-shared",
+		AdminAPIKey:           "secret-admin",
+		LogServiceAPIKey:      "secret-log-service",
+		LogsAPIKey:            "secret-logs-api",
+		ChallengerDatabaseURL: "postgres://aibattle:secret-db-password@db:5432/challenger",
+		SolverDatabaseURL:     "host=db user=aibattle password=secret-dsn-password",
+		NonceRedisURL:         "redis://:secret-redis-password@localhost:6379/0",
+		ETH:                   EthereumConfig{PrivateKey: "secret-eth-key"},
+		SUI: SuiConfig{
+			ChallengerMnemonic:  mnemonic,
+			SolverMnemonic:      mnemonic,
+			InitializerMnemonic: mnemonic,
+			PackageID:           "0x42",
+		},
+		ChallengerPort: "8080",
+	}
+
+	var logged bytes.Buffer
+	logger := zerolog.New(&logged)
+	logger.Info().Object("config", cfg.LogSafe()).Msg("Configuration loaded")
+
+	outputs := map[string]string{
+		"String":     cfg.String(),
+		"Sprintf":    fmt.Sprintf("%v", cfg),
+		"SuiString":  cfg.SUI.String(),
+		"zerolog":    logged.String(),
+		"LogSafe %+": fmt.Sprintf("%+v", *cfg.LogSafe()),
+	}
+	for name, out := range outputs {
+		for _, secret := range []string{"secret-", "abandon"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%s output leaks %q: %s", name, secret, out)
+			}
+		}
+	}
+
+	if !strings.Contains(logged.String(), `"ChallengerPort":"8080"`) || !strings.Contains(logged.String(), `"PackageID":"0x42"`) {
+		t.Errorf("Expected non-secret fields in the log, got %s", logged.String())
+	}
+	if !strings.Contains(cfg.String(), "postgres://aibattle:xxxxx@db:5432/challenger") {
+		t.Errorf("Expected only the database password to be masked, got %s", cfg.String())
+	}
+
+	// The snapshot must not modify the live configuration
+	if cfg.ChalHMACKeys[0].Secret != "secret-rotated-hmac" || cfg.SUI.ChallengerMnemonic != mnemonic {
+		t.Error("LogSafe() modified the original configuration")
+	}
+}
+
+func TestConfig_LoadPicksUpDotenvChanges(t *testing.T) {
+	clearConfigEnv()
+	defer clearConfigEnv()
+	t.Chdir(t.TempDir())
+
+	// A variable set in the process environment wins over the file on every load
+	os.Setenv("CHAL_HMAC_KEY_ID", "from-environment")
+	writeDotenv := func(contents string) {
+		if err := os.WriteFile(".env", []byte(contents), 0600); err != nil {
+			t.Fatalf("Failed to write .env: %v", err)
+		}
+	}
+	// Forget the file's variables so later tests start from a clean environment
+	t.Cleanup(func() {
+		writeDotenv("")
+		loadDotenv()
+	})
+
+	writeDotenv("SHARED_SECRET_KEY=old-secret\nLOG_LEVEL=info\nCHAL_HMAC_KEY_ID=from-file\n")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.SharedSecretKey != "old-secret" || cfg.LogLevel != "info" {
+		t.Fatalf("Expected the .env values, got secret %q and level %q", cfg.SharedSecretKey, cfg.LogLevel)
+	}
+
+	writeDotenv("SHARED_SECRET_KEY=new-secret\nCHAL_HMAC_KEY_ID=from-file\n")
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error on reload: %v", err)
+	}
+	if reloaded.SharedSecretKey != "new-secret" {
+		t.Errorf("Expected the reload to swap the secret, got %q", reloaded.SharedSecretKey)
+	}
+	if reloaded.LogLevel != "info" || os.Getenv("LOG_LEVEL") != "" {
+		t.Errorf("Expected LOG_LEVEL removed from .env to fall back to the default, got %q", reloaded.LogLevel)
+	}
+	if reloaded.ChalHMACKeyID != "from-environment" {
+		t.Errorf("Expected the process environment to win, got %q", reloaded.ChalHMACKeyID)
+	}
+}
+
+func TestConfig_ChalHMACKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    string
+		want    []HMACKey
+		wantErr string
+	}{
+		{name: "unset"},
+		{
+			name: "validity windows",
+			keys: `[{"key_id":"chal-kid-1","secret":"old","not_after":"2026-03-01T00:00:00Z"},{"key_id":"chal-kid-2","secret":"new","not_before":"2026-02-01T00:00:00Z"}]`,
+			want: []HMACKey{
+				{KeyID: "chal-kid-1", Secret: "old", NotAfter: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+				{KeyID: "chal-kid-2", Secret: "new", NotBefore: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{name: "invalid json", keys: `{"key_id":`, wantErr: "invalid CHAL_HMAC_KEYS"},
+		{name: "missing secret", keys: `[{"key_id":"chal-kid-2"}]`, wantErr: "require key_id and secret"},
+		{
+			name:    "empty window",
+			keys:    `[{"key_id":"chal-kid-2","secret":"new","not_before":"2026-03-01T00:00:00Z","not_after":"2026-02-01T00:00:00Z"}]`,
+			wantErr: "not_after must be after not_before",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.keys != "" {
+				os.Setenv("CHAL_HMAC_KEYS", tt.keys)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(cfg.ChalHMACKeys) != len(tt.want) {
+				t.Fatalf("Expected %d keys, got %+v", len(tt.want), cfg.ChalHMACKeys)
+			}
+			for i, want := range tt.want {
+				got := cfg.ChalHMACKeys[i]
+				if got.KeyID != want.KeyID || got.Secret != want.Secret ||
+					!got.NotBefore.Equal(want.NotBefore) || !got.NotAfter.Equal(want.NotAfter) {
+					t.Errorf("Key %d: expected %+v, got %+v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_SolverAPIVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions string
+		want     string
+		wantErr  bool
+	}{
+		{name: "unset", want: "v2.1"},
+		{name: "several versions", versions: "v2.1, v2.2", want: "v2.1,v2.2"},
+		{name: "only separators", versions: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.versions != "" {
+				os.Setenv("SOLVER_API_VERSIONS", tt.versions)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "SOLVER_API_VERSIONS") {
+					t.Errorf("Expected a SOLVER_API_VERSIONS error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := strings.Join(cfg.SolverAPIVersions, ","); got != tt.want {
+				t.Errorf("Expected versions %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfig_SolverProblemTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		types string
+		want  string
+	}{
+		{name: "unset", want: "captcha,math,text"},
+		{name: "backend types", types: "ocr, translation", want: "ocr,translation"},
+		{name: "any type", types: "*", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.types != "" {
+				os.Setenv("SOLVER_PROBLEM_TYPES", tt.types)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := strings.Join(cfg.SolverProblemTypes, ","); got != tt.want {
+				t.Errorf("Expected problem types %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfig_SolverTypeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  string
+		want    map[string]int
+		wantErr string
+	}{
+		{name: "unset", want: map[string]int{}},
+		{name: "several types", limits: "captcha:2, math:8,", want: map[string]int{"captcha": 2, "math": 8}},
+		{name: "missing limit", limits: "captcha", wantErr: "invalid SOLVER_TYPE_LIMITS entry"},
+		{name: "non-numeric limit", limits: "captcha:two", wantErr: "invalid SOLVER_TYPE_LIMITS entry"},
+		{name: "missing type", limits: ":2", wantErr: "invalid SOLVER_TYPE_LIMITS entry"},
+		{name: "zero limit", limits: "captcha:0", wantErr: "must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv()
+			defer clearConfigEnv()
+
+			os.Setenv("SHARED_SECRET_KEY", "test-secret")
+			if tt.limits != "" {
+				os.Setenv("SOLVER_TYPE_LIMITS", tt.limits)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(cfg.SolverTypeLimits) != len(tt.want) {
+				t.Fatalf("Expected limits %v, got %v", tt.want, cfg.SolverTypeLimits)
+			}
+			for name, limit := range tt.want {
+				if cfg.SolverTypeLimits[name] != limit {
+					t.Errorf("Expected %s limit %d, got %d", name, limit, cfg.SolverTypeLimits[name])
+				}
+			}
+		})
+	}
+}
+
 func TestConfig_GetChallengerAddr(t *testing.T) {
 	clearConfigEnv()
 
@@ -368,6 +1796,37 @@ func TestConfig_GetChallengerSecrets_WithSharedSecret(t *testing.T) {
 	}
 }
 
+func TestConfig_GetKeyRoles(t *testing.T) {
+	config := &Config{
+		ChalHMACKeyID:   "chal-kid-1",
+		SolverHMACKeyID: "solver-kid-1",
+		ChalHMACKeys:    []HMACKey{{KeyID: "chal-kid-2", Secret: "next"}},
+	}
+
+	want := map[string]string{"chal-kid-1": KeyRoleSolver, "solver-kid-1": KeyRoleChallenger}
+	if got := config.GetKeyRoles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected key roles %v, got %v", want, got)
+	}
+}
+
+func TestConfig_GetInspectionKeyIDs(t *testing.T) {
+	clearConfigEnv()
+	defer clearConfigEnv()
+
+	os.Setenv("SHARED_SECRET_KEY", "test-secret")
+	os.Setenv("OPERATOR_HMAC_KEY_IDS", "ops-kid-1, ops-kid-2")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := strings.Join(cfg.GetInspectionKeyIDs(), ",")
+	if got != "solver-kid-1,ops-kid-1,ops-kid-2" {
+		t.Errorf("Expected inspection key IDs solver-kid-1,ops-kid-1,ops-kid-2, got %s", got)
+	}
+}
+
 func TestConfig_GetChallengerSecrets_WithIndividualSecrets(t *testing.T) {
 	clearConfigEnv()
 
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// redacted replaces secret values in log output
+const redacted = "***"
+
+// LogSafe returns a copy of the configuration with every secret replaced by "***".
+// Log this snapshot, never the Config itself.
+func (c *Config) LogSafe() *Config {
+	safe := *c
+	safe.ChallengerCallbackKey = redact(c.ChallengerCallbackKey)
+	safe.ChalHMACSecret = redact(c.ChalHMACSecret)
+	safe.SolverAPIKey = redact(c.SolverAPIKey)
+	safe.SolverHMACSecret = redact(c.SolverHMACSecret)
+	safe.SharedSecretKey = redact(c.SharedSecretKey)
+	safe.AdminAPIKey = redact(c.AdminAPIKey)
+	safe.LogServiceAPIKey = redact(c.LogServiceAPIKey)
+	safe.LogsAPIKey = redact(c.LogsAPIKey)
+	safe.ETH.PrivateKey = redact(c.ETH.PrivateKey)
+	safe.SUI = *c.SUI.LogSafe()
+
+	// Connection strings may carry passwords
+	safe.ChallengerDatabaseURL = redactURL(c.ChallengerDatabaseURL)
+	safe.SolverDatabaseURL = redactURL(c.SolverDatabaseURL)
+	safe.ChallengerReadDatabaseURL = redactURL(c.ChallengerReadDatabaseURL)
+	safe.SolverReadDatabaseURL = redactURL(c.SolverReadDatabaseURL)
+	safe.NonceRedisURL = redactURL(c.NonceRedisURL)
+	safe.EventBusURL = redactURL(c.EventBusURL)
+
+	// Copy the keys so the snapshot does not share them with c
+	if c.ChalHMACKeys != nil {
+		safe.ChalHMACKeys = make([]HMACKey, len(c.ChalHMACKeys))
+		for i, key := range c.ChalHMACKeys {
+			key.Secret = redact(key.Secret)
+			safe.ChalHMACKeys[i] = key
+		}
+	}
+	return &safe
+}
+
+// String renders the redacted configuration as JSON, so printing a Config with %v
+// cannot leak secrets
+func (c *Config) String() string {
+	data, err := json.Marshal(c.LogSafe())
+	if err != nil {
+		return "config: " + err.Error()
+	}
+	return string(data)
+}
+
+// MarshalZerologObject logs every field of the redacted configuration
+func (c *Config) MarshalZerologObject(e *zerolog.Event) {
+	marshalFields(e, c.LogSafe())
+}
+
+// LogSafe returns a copy of the Sui configuration with the mnemonics replaced by "***"
+func (s *SuiConfig) LogSafe() *SuiConfig {
+	safe := *s
+	safe.ChallengerMnemonic = redact(s.ChallengerMnemonic)
+	safe.SolverMnemonic = redact(s.SolverMnemonic)
+	safe.InitializerMnemonic = redact(s.InitializerMnemonic)
+	return &safe
+}
+
+// String renders the redacted Sui configuration as JSON
+func (s *SuiConfig) String() string {
+	data, err := json.Marshal(s.LogSafe())
+	if err != nil {
+		return "sui config: " + err.Error()
+	}
+	return string(data)
+}
+
+// MarshalZerologObject logs every field of the redacted Sui configuration
+func (s *SuiConfig) MarshalZerologObject(e *zerolog.Event) {
+	marshalFields(e, s.LogSafe())
+}
+
+// marshalFields adds each exported field of the struct v points to under its Go name
+func marshalFields(e *zerolog.Event, v interface{}) {
+	value := reflect.ValueOf(v).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.IsExported() {
+			e.Interface(field.Name, value.Field(i).Interface())
+		}
+	}
+}
+
+// redact hides a secret, keeping empty values visible so missing settings still show up
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURL hides the password of a connection URL as url.URL.Redacted does. Values that
+// do not parse as a URL with a scheme (such as key=value Postgres DSNs) are hidden entirely.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !strings.Contains(raw, "://") {
+		return redacted
+	}
+	return u.Redacted()
+}
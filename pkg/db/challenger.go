@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/scoring"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -21,35 +22,41 @@ type ChallengerDB struct {
 	db *sql.DB // SQLite database connection
 }
 
-// NewChallengerDB creates and initializes a new challenger database instance.
+// NewChallengerDB creates and initializes a new challenger database instance with DefaultSQLiteOptions.
 // Opens SQLite connection, enables WAL mode for better concurrency, and creates required tables.
 // Returns configured database ready for challenger operations.
 func NewChallengerDB(dbPath string) (*ChallengerDB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	// Enable WAL mode for better concurrent access and set busy timeout
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
-	}
+	return NewChallengerDBWithOptions(dbPath, DefaultSQLiteOptions())
+}
 
-	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
-		return nil, fmt.Errorf("failed to set busy timeout: %w", err)
+// NewChallengerDBWithOptions is NewChallengerDB with a configured busy timeout and pool size.
+func NewChallengerDBWithOptions(dbPath string, opts SQLiteOptions) (*ChallengerDB, error) {
+	db, err := openSQLite(dbPath, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	cdb := &ChallengerDB{db: db}
-	if err := cdb.createTables(); err != nil {
-		return nil, fmt.Errorf("failed to create tables: %w", err)
+	if err := cdb.Migrate(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
 	return cdb, nil
 }
 
-// createTables initializes all required database tables for challenger operations.
-// Creates tables for challenges, results, webhook audits, and nonce tracking.
-func (c *ChallengerDB) createTables() error {
+// NewChallengerReadOnlyDB opens a read-only connection to an existing challenger database.
+// Used by reporting endpoints so reads do not contend with the write path; tables are not created.
+func NewChallengerReadOnlyDB(dbPath string, opts SQLiteOptions) (*ChallengerDB, error) {
+	db, err := openSQLiteReadOnly(dbPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ChallengerDB{db: db}, nil
+}
+
+// createChallengerSchema creates the challenger tables: challenges, results, webhook audits,
+// nonce tracking and indexes. Databases created before versioning already have some of them.
+func createChallengerSchema(ctx context.Context, tx *sql.Tx) error {
 	queries := []string{
 		`CREATE TABLE IF NOT EXISTS challenges (
 			id TEXT PRIMARY KEY,
@@ -57,7 +64,9 @@ func (c *ChallengerDB) createTables() error {
 			problem TEXT NOT NULL,
 			output_spec TEXT NOT NULL,
 			validation_rule TEXT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP,
+			expired_at TIMESTAMP
 		)`,
 		`CREATE TABLE IF NOT EXISTS results (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -87,6 +96,7 @@ func (c *ChallengerDB) createTables() error {
 			nonce TEXT PRIMARY KEY,
 			seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
+		sqliteHMACKeysTable,
 		`CREATE TABLE IF NOT EXISTS contracts (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			name TEXT NOT NULL,
@@ -99,34 +109,184 @@ func (c *ChallengerDB) createTables() error {
 			metadata TEXT,
 			UNIQUE (name, chain_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS dispatched_jobs (
+			challenge_id TEXT NOT NULL,
+			solver_job_id TEXT NOT NULL,
+			dispatched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (challenge_id, solver_job_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS commitments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			challenge_id TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			object_id TEXT NOT NULL,
+			commitment_hash TEXT NOT NULL,
+			settlement_status TEXT NOT NULL DEFAULT 'pending',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (challenge_id, request_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS log_entries (
+			id TEXT PRIMARY KEY,
+			log TEXT NOT NULL,
+			challenger_addr TEXT,
+			solver_addr TEXT,
+			verifier_addr TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS commitment_jobs (
+			challenge_id TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (challenge_id, request_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS pending_log_uploads (
+			log_id TEXT PRIMARY KEY,
+			payload TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS submission_windows (
+			challenge_id TEXT PRIMARY KEY,
+			closes_at TIMESTAMP NOT NULL,
+			best_request_id TEXT,
+			best_score INTEGER NOT NULL DEFAULT 0,
+			settled_at TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS bounty_transfers (
+			commitment_id TEXT NOT NULL,
+			vault_id TEXT NOT NULL,
+			digest TEXT NOT NULL,
+			solver_address TEXT NOT NULL,
+			transferred_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (commitment_id, vault_id)
+		)`,
 		`CREATE INDEX IF NOT EXISTS ix_results_cid_created ON results(challenge_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_results_solver_address ON results(solver_address)`,
 		`CREATE INDEX IF NOT EXISTS ix_seen_nonces_seen_at ON seen_nonces(seen_at)`,
 		`CREATE INDEX IF NOT EXISTS ix_contracts_name_chain ON contracts(name, chain_id)`,
+		`CREATE INDEX IF NOT EXISTS ix_webhooks_cid_created ON webhooks(challenge_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_webhooks_created_at ON webhooks(created_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_submission_windows_closes_at ON submission_windows(closes_at)`,
 	}
 
 	for _, query := range queries {
-		if _, err := c.db.Exec(query); err != nil {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
 			return fmt.Errorf("failed to execute query %s: %w", query, err)
 		}
 	}
 
+	// Columns added before schema versioning; CREATE TABLE IF NOT EXISTS leaves old tables untouched
+	for _, column := range []string{"expires_at", "expired_at"} {
+		if err := addColumnIfMissing(ctx, tx, "challenges", column, "TIMESTAMP"); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS ix_challenges_expires_at ON challenges(expires_at)`); err != nil {
+		return fmt.Errorf("failed to create challenges expiry index: %w", err)
+	}
+
+	return nil
+}
+
+// challengerMigrations is the SQLite challenger schema history, applied in order by Migrate.
+// Append new steps for schema changes; never edit a migration that has shipped.
+var challengerMigrations = []migration{
+	{version: 1, description: "initial schema", up: createChallengerSchema},
+	{version: 2, description: "index results by creation time", up: createResultsCreatedAtIndex},
+	{version: 3, description: "queue undelivered challenge dispatches", up: createPendingDispatchesTable},
+	{version: 4, description: "record first-correct winners", up: addChallengeWinnerColumns},
+}
+
+// addChallengeWinnerColumns records the first correct answer of each challenge, flags results
+// received after it, and keys queued dispatches by solver so a challenge can wait on several
+func addChallengeWinnerColumns(ctx context.Context, tx *sql.Tx) error {
+	columns := []struct{ table, column, definition string }{
+		{"challenges", "competitive", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"challenges", "winner_request_id", "TEXT"},
+		{"challenges", "winner_solver_address", "TEXT"},
+		{"challenges", "closed_at", "TIMESTAMP"},
+		{"results", "is_late", "BOOLEAN NOT NULL DEFAULT 0"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(ctx, tx, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	// SQLite cannot change a primary key in place, so the queue is copied into a new table
+	queries := []string{
+		`CREATE TABLE pending_dispatches_v4 (
+			challenge_id TEXT NOT NULL,
+			solver_url TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (challenge_id, solver_url)
+		)`,
+		`INSERT INTO pending_dispatches_v4 SELECT challenge_id, solver_url, priority, attempts, last_error, created_at FROM pending_dispatches`,
+		`DROP TABLE pending_dispatches`,
+		`ALTER TABLE pending_dispatches_v4 RENAME TO pending_dispatches`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to rekey pending_dispatches: %w", err)
+		}
+	}
+	return nil
+}
+
+// createPendingDispatchesTable adds the queue of challenges the solver did not accept
+func createPendingDispatchesTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS pending_dispatches (
+			challenge_id TEXT PRIMARY KEY,
+			solver_url TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create pending_dispatches table: %w", err)
+	}
+	return nil
+}
+
+// createResultsCreatedAtIndex indexes results for the time-ordered audit export. The statement
+// is the same for both drivers.
+func createResultsCreatedAtIndex(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS ix_results_created_at ON results(created_at, id)`); err != nil {
+		return fmt.Errorf("failed to create results creation time index: %w", err)
+	}
 	return nil
 }
 
+// Migrate applies the challenger schema migrations the database has not recorded yet.
+// It runs on open and is safe to call again.
+func (c *ChallengerDB) Migrate(ctx context.Context) error {
+	return runMigrations(ctx, c.db, sqliteMigrationDialect, challengerSchema, challengerMigrations)
+}
+
 // CreateChallenge stores a new challenge in the database.
 // Serializes validation rules to JSON and handles the complete challenge lifecycle.
-func (c *ChallengerDB) CreateChallenge(challenge *models.Challenge) error {
+func (c *ChallengerDB) CreateChallenge(ctx context.Context, challenge *models.Challenge) error {
 	validationRuleJSON, err := json.Marshal(challenge.ValidationRule)
 	if err != nil {
 		return fmt.Errorf("failed to marshal validation rule: %w", err)
 	}
 
-	_, err = c.db.Exec(`
-		INSERT INTO challenges (id, type, problem, output_spec, validation_rule, created_at)
-		VALUES (?, ?, ?, ?, ?, ?)`,
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO challenges (id, type, problem, output_spec, validation_rule, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
 		challenge.ID, challenge.Type, string(challenge.Problem),
-		string(challenge.OutputSpec), string(validationRuleJSON), challenge.CreatedAt)
+		string(challenge.OutputSpec), string(validationRuleJSON), challenge.CreatedAt, nullTime(challenge.ExpiresAt))
 
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", ErrDuplicateChallenge, challenge.ID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert challenge: %w", err)
 	}
@@ -136,19 +296,144 @@ func (c *ChallengerDB) CreateChallenge(challenge *models.Challenge) error {
 
 // GetChallenge retrieves a challenge by its ID from the database.
 // Reconstructs the challenge object with proper JSON deserialization of validation rules.
-func (c *ChallengerDB) GetChallenge(id string) (*models.Challenge, error) {
-	row := c.db.QueryRow(`
-		SELECT id, type, problem, output_spec, validation_rule, created_at
+func (c *ChallengerDB) GetChallenge(ctx context.Context, id string) (*models.Challenge, error) {
+	row := c.db.QueryRowContext(ctx, `
+		SELECT id, type, problem, output_spec, validation_rule, created_at, expires_at, expired_at,
+			competitive, winner_request_id, winner_solver_address, closed_at
 		FROM challenges WHERE id = ?`, id)
 
+	challenge, err := scanChallenge(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// SetChallengeExpiry records the deadline sent to a solver. The expiry only moves later, so
+// dispatching to another solver extends it, and an extended challenge is no longer marked expired.
+func (c *ChallengerDB) SetChallengeExpiry(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE challenges SET expires_at = ?, expired_at = NULL
+		WHERE id = ? AND (expires_at IS NULL OR expires_at < ?)`,
+		expiresAt.UTC(), id, expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set challenge expiry: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredChallenges returns challenges whose expiry is at or before now and that
+// have not been marked expired yet, oldest expiry first.
+func (c *ChallengerDB) ListExpiredChallenges(ctx context.Context, now time.Time) ([]*models.Challenge, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, type, problem, output_spec, validation_rule, created_at, expires_at, expired_at,
+			competitive, winner_request_id, winner_solver_address, closed_at
+		FROM challenges WHERE expired_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?
+		ORDER BY expires_at ASC`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []*models.Challenge{}
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, challenge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired challenges: %w", err)
+	}
+
+	return challenges, nil
+}
+
+// MarkChallengeExpired marks a challenge expired. It returns false if the challenge was already
+// marked or its expiry was extended, so each expiry is handled by exactly one sweep.
+func (c *ChallengerDB) MarkChallengeExpired(ctx context.Context, id string, expiredAt time.Time) (bool, error) {
+	res, err := c.db.ExecContext(ctx, `
+		UPDATE challenges SET expired_at = ?
+		WHERE id = ? AND expired_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?`,
+		expiredAt.UTC(), id, expiredAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to mark challenge expired: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetChallengeCompetitive marks a challenge as sent to several solvers, so only the first
+// correct answer is committed.
+func (c *ChallengerDB) SetChallengeCompetitive(ctx context.Context, id string) error {
+	if _, err := c.db.ExecContext(ctx, `UPDATE challenges SET competitive = 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to mark challenge competitive: %w", err)
+	}
+	return nil
+}
+
+// CloseChallenge records requestID as the first correct answer to a challenge and closes it.
+// It returns false when another answer closed the challenge first. The winner is never
+// rewritten: a retried callback is recognized only when both the request ID, which clients
+// choose, and the solver address match the recorded winner.
+func (c *ChallengerDB) CloseChallenge(ctx context.Context, id, requestID, solverAddress string, closedAt time.Time) (bool, error) {
+	res, err := c.db.ExecContext(ctx, `
+		UPDATE challenges SET winner_request_id = ?, winner_solver_address = ?, closed_at = ?
+		WHERE id = ? AND closed_at IS NULL`,
+		requestID, solverAddress, closedAt.UTC(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to close challenge: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return true, nil
+	}
+
+	var count int
+	err = c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM challenges
+		WHERE id = ? AND winner_request_id = ? AND winner_solver_address = ?`,
+		id, requestID, solverAddress).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check challenge winner: %w", err)
+	}
+	return count > 0, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanChallenge reads a challenges row and decodes its JSON columns.
+func scanChallenge(row rowScanner) (*models.Challenge, error) {
 	var challenge models.Challenge
 	var problemText, outputSpecText, validationRuleJSON string
 
+	var expiresAt, expiredAt, closedAt sql.NullTime
+	var winnerRequestID, winnerSolverAddress sql.NullString
+
 	err := row.Scan(&challenge.ID, &challenge.Type, &problemText,
-		&outputSpecText, &validationRuleJSON, &challenge.CreatedAt)
+		&outputSpecText, &validationRuleJSON, &challenge.CreatedAt, &expiresAt, &expiredAt,
+		&challenge.Competitive, &winnerRequestID, &winnerSolverAddress, &closedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get challenge: %w", err)
+		return nil, err
 	}
+	challenge.ExpiresAt = timePtr(expiresAt)
+	challenge.ExpiredAt = timePtr(expiredAt)
+	challenge.WinnerRequestID = winnerRequestID.String
+	challenge.WinnerSolverAddress = winnerSolverAddress.String
+	challenge.ClosedAt = timePtr(closedAt)
 
 	// Convert text back to json.RawMessage
 	challenge.Problem = json.RawMessage(problemText)
@@ -163,19 +448,19 @@ func (c *ChallengerDB) GetChallenge(id string) (*models.Challenge, error) {
 
 // SaveResult stores a challenge result in the database.
 // Handles serialization of solver metadata and prevents duplicate insertions.
-func (c *ChallengerDB) SaveResult(result *models.Result) error {
+func (c *ChallengerDB) SaveResult(ctx context.Context, result *models.Result) error {
 	metadataJSON := ""
 	if result.SolverMetadata != nil {
 		metadataJSON = string(result.SolverMetadata)
 	}
 
-	_, err := c.db.Exec(`
+	_, err := c.db.ExecContext(ctx, `
 		INSERT INTO results (challenge_id, request_id, solver_job_id, status,
-			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		result.ChallengeID, result.RequestID, result.SolverJobID, result.Status,
 		result.ReceivedAnswer, result.IsCorrect, result.SolverAddress, result.ComputeTimeMs,
-		metadataJSON, result.CreatedAt)
+		metadataJSON, result.CreatedAt, result.IsLate)
 
 	if err != nil {
 		return fmt.Errorf("failed to save result: %w", err)
@@ -187,19 +472,19 @@ func (c *ChallengerDB) SaveResult(result *models.Result) error {
 // SaveResultWithDuplicateCheck saves a result and returns insertion status.
 // Returns true if the result was newly inserted, false if it was a duplicate.
 // Implements idempotent result storage for reliable callback handling.
-func (c *ChallengerDB) SaveResultWithDuplicateCheck(result *models.Result) (bool, error) {
+func (c *ChallengerDB) SaveResultWithDuplicateCheck(ctx context.Context, result *models.Result) (bool, error) {
 	metadataJSON := ""
 	if result.SolverMetadata != nil {
 		metadataJSON = string(result.SolverMetadata)
 	}
 
-	res, err := c.db.Exec(`
+	res, err := c.db.ExecContext(ctx, `
 		INSERT OR IGNORE INTO results (challenge_id, request_id, solver_job_id, status,
-			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		result.ChallengeID, result.RequestID, result.SolverJobID, result.Status,
 		result.ReceivedAnswer, result.IsCorrect, result.SolverAddress, result.ComputeTimeMs,
-		metadataJSON, result.CreatedAt)
+		metadataJSON, result.CreatedAt, result.IsLate)
 
 	if err != nil {
 		return false, fmt.Errorf("failed to save result: %w", err)
@@ -216,25 +501,35 @@ func (c *ChallengerDB) SaveResultWithDuplicateCheck(result *models.Result) (bool
 
 // GetResult retrieves a stored result by challenge ID and request ID.
 // Returns nil if no result is found, otherwise returns the complete result with metadata.
-func (c *ChallengerDB) GetResult(challengeID, requestID string) (*models.Result, error) {
-	row := c.db.QueryRow(`
+func (c *ChallengerDB) GetResult(ctx context.Context, challengeID, requestID string) (*models.Result, error) {
+	row := c.db.QueryRowContext(ctx, `
 		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
-			is_correct, solver_address, compute_time_ms, solver_metadata, created_at
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
 		FROM results WHERE challenge_id = ? AND request_id = ?`, challengeID, requestID)
 
+	result, err := scanResult(row)
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get result: %w", err)
+	}
+
+	return result, nil
+}
+
+// scanResult reads a results row, mapping NULL metadata and solver address to zero values.
+// Scan errors are returned unwrapped so callers can detect sql.ErrNoRows.
+func scanResult(row rowScanner) (*models.Result, error) {
 	var result models.Result
 	var metadataJSON sql.NullString
 	var solverAddress sql.NullString
 
 	err := row.Scan(&result.ID, &result.ChallengeID, &result.RequestID, &result.SolverJobID,
 		&result.Status, &result.ReceivedAnswer, &result.IsCorrect, &solverAddress, &result.ComputeTimeMs,
-		&metadataJSON, &result.CreatedAt)
-
-	if err == sql.ErrNoRows {
-		return nil, nil // Not found
-	}
+		&metadataJSON, &result.CreatedAt, &result.IsLate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get result: %w", err)
+		return nil, err
 	}
 
 	if metadataJSON.Valid && metadataJSON.String != "" {
@@ -248,10 +543,321 @@ func (c *ChallengerDB) GetResult(challengeID, requestID string) (*models.Result,
 	return &result, nil
 }
 
+// ListResultsByChallenge retrieves all stored results for a challenge ordered by creation time.
+// Returns an empty slice if the challenge has no results yet.
+func (c *ChallengerDB) ListResultsByChallenge(ctx context.Context, challengeID string) ([]*models.Result, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results WHERE challenge_id = ? ORDER BY created_at ASC, id ASC`, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	return scanResults(rows)
+}
+
+// ListResults retrieves a page of results ordered by creation time, newest first.
+// Empty challengeID or status match all results; a non-positive limit returns every row after offset.
+// Returns the page together with the total number of matching results.
+func (c *ChallengerDB) ListResults(ctx context.Context, challengeID string, status string, limit, offset int) ([]*models.Result, int, error) {
+	where := "WHERE 1=1"
+	var args []interface{}
+	if challengeID != "" {
+		where += " AND challenge_id = ?"
+		args = append(args, challengeID)
+	}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+
+	var total int
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM results "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count results: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results `+where+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, pageLimit(limit), pageOffset(offset))...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := scanResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// ListResultsAfter returns up to limit results created after the cursor and before until,
+// oldest first. A non-positive limit returns every matching row.
+func (c *ChallengerDB) ListResultsAfter(ctx context.Context, after ExportCursor, until time.Time, limit int) ([]*models.Result, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results
+		WHERE created_at < ? AND (created_at > ? OR (created_at = ? AND id > ?))
+		ORDER BY created_at ASC, id ASC LIMIT ?`,
+		until, after.CreatedAt, after.CreatedAt, after.ID, pageLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	return scanResults(rows)
+}
+
+// GetChallengeStats aggregates challenge and result counts in the database.
+func (c *ChallengerDB) GetChallengeStats(ctx context.Context) (models.ChallengerStats, error) {
+	return challengeStats(ctx, c.db)
+}
+
+// GetLeaderboard ranks solvers by score, then by correct answers and faster average compute
+// time. Each correct answer that was not late scores scoring.MaxScore, the on-chain score of
+// the default scorer. Results without a solver address are left out; a non-positive limit
+// returns every solver.
+func (c *ChallengerDB) GetLeaderboard(ctx context.Context, limit int) ([]models.SolverScore, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT solver_address,
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN is_correct THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(compute_time_ms), 0),
+			COALESCE(SUM(CASE WHEN is_correct AND NOT is_late THEN ? ELSE 0 END), 0) AS score
+		FROM results
+		WHERE solver_address IS NOT NULL AND solver_address != ''
+		GROUP BY solver_address
+		ORDER BY score DESC, 3 DESC, 4 ASC, solver_address ASC
+		LIMIT ?`, scoring.MaxScore, pageLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLeaderboard(rows)
+}
+
+// scanLeaderboard reads the rows of a leaderboard query.
+func scanLeaderboard(rows *sql.Rows) ([]models.SolverScore, error) {
+	scores := []models.SolverScore{}
+	for rows.Next() {
+		var score models.SolverScore
+		if err := rows.Scan(&score.SolverAddress, &score.Attempts, &score.Correct,
+			&score.AvgComputeTimeMs, &score.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard row: %w", err)
+		}
+		scores = append(scores, score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating leaderboard: %w", err)
+	}
+	return scores, nil
+}
+
+// challengeStats computes ChallengerStats with SQL aggregates shared by both drivers.
+func challengeStats(ctx context.Context, db *sql.DB) (models.ChallengerStats, error) {
+	stats := models.ChallengerStats{StatusBreakdown: map[string]int{}}
+
+	err := db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM challenges),
+			(SELECT COUNT(DISTINCT challenge_id) FROM results WHERE is_correct)`).
+		Scan(&stats.TotalChallenges, &stats.SolvedChallenges)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count challenges: %w", err)
+	}
+
+	err = db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COALESCE(SUM(CASE WHEN is_correct THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN NOT is_correct THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(compute_time_ms), 0)
+		FROM results`).
+		Scan(&stats.TotalResults, &stats.CorrectResults, &stats.IncorrectResults, &stats.AvgComputeTimeMs)
+	if err != nil {
+		return stats, fmt.Errorf("failed to aggregate results: %w", err)
+	}
+	if stats.TotalResults > 0 {
+		stats.CorrectRatio = float64(stats.CorrectResults) / float64(stats.TotalResults)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT status, COUNT(*) FROM results GROUP BY status`)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count results by status: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return stats, fmt.Errorf("failed to scan result status count: %w", err)
+		}
+		stats.StatusBreakdown[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("error iterating result status counts: %w", err)
+	}
+
+	return stats, nil
+}
+
+// scanResults reads every row of a results query into models.
+func scanResults(rows *sql.Rows) ([]*models.Result, error) {
+	results := []*models.Result{}
+	for rows.Next() {
+		result, err := scanResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating results: %w", err)
+	}
+
+	return results, nil
+}
+
+// pageLimit converts a caller-supplied limit into a SQLite LIMIT value.
+// Non-positive limits map to -1, which SQLite treats as unbounded.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// pageOffset clamps negative offsets to zero.
+func pageOffset(offset int) int {
+	if offset < 0 {
+		return 0
+	}
+	return offset
+}
+
+// SaveCommitment records a commitment uploaded to Sui for a stored result.
+// An empty settlement status is stored as "pending".
+func (c *ChallengerDB) SaveCommitment(ctx context.Context, commitment *models.Commitment) error {
+	settlementStatus := commitment.SettlementStatus
+	if settlementStatus == "" {
+		settlementStatus = "pending"
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO commitments (challenge_id, request_id, object_id, commitment_hash,
+			settlement_status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		commitment.ChallengeID, commitment.RequestID, commitment.ObjectID,
+		commitment.CommitmentHash, settlementStatus, commitment.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save commitment: %w", err)
+	}
+
+	return nil
+}
+
+// ListCommitmentsBySolver retrieves a page of commitments whose result was submitted by the solver address,
+// newest first. A non-positive limit returns every row after offset.
+// Returns the page together with the total number of commitments for the solver.
+func (c *ChallengerDB) ListCommitmentsBySolver(ctx context.Context, solverAddress string, limit, offset int) ([]*models.SolverCommitment, int, error) {
+	var total int
+	if err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM commitments cm
+		JOIN results r ON r.challenge_id = cm.challenge_id AND r.request_id = cm.request_id
+		WHERE r.solver_address = ?`, solverAddress).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count commitments: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT cm.id, cm.challenge_id, cm.request_id, cm.object_id, cm.commitment_hash,
+			cm.settlement_status, cm.created_at, r.solver_address, r.status, r.is_correct
+		FROM commitments cm
+		JOIN results r ON r.challenge_id = cm.challenge_id AND r.request_id = cm.request_id
+		WHERE r.solver_address = ?
+		ORDER BY cm.created_at DESC, cm.id DESC LIMIT ? OFFSET ?`,
+		solverAddress, pageLimit(limit), pageOffset(offset))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query commitments: %w", err)
+	}
+	defer rows.Close()
+
+	commitments, err := scanSolverCommitments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return commitments, total, nil
+}
+
+// scanSolverCommitments reads every row of a commitments-by-solver query into models.
+func scanSolverCommitments(rows *sql.Rows) ([]*models.SolverCommitment, error) {
+	commitments := []*models.SolverCommitment{}
+	for rows.Next() {
+		var commitment models.SolverCommitment
+		if err := rows.Scan(&commitment.ID, &commitment.ChallengeID, &commitment.RequestID,
+			&commitment.ObjectID, &commitment.CommitmentHash, &commitment.SettlementStatus,
+			&commitment.CreatedAt, &commitment.SolverAddress, &commitment.ResultStatus,
+			&commitment.IsCorrect); err != nil {
+			return nil, fmt.Errorf("failed to scan commitment: %w", err)
+		}
+		commitments = append(commitments, &commitment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating commitments: %w", err)
+	}
+
+	return commitments, nil
+}
+
+// SaveDispatchedJob records a solver job id returned when a challenge was sent out.
+// Callbacks are only accepted for jobs recorded here; re-recording the same job is a no-op.
+func (c *ChallengerDB) SaveDispatchedJob(ctx context.Context, challengeID, solverJobID string) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO dispatched_jobs (challenge_id, solver_job_id, dispatched_at)
+		VALUES (?, ?, ?)`, challengeID, solverJobID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save dispatched job: %w", err)
+	}
+	return nil
+}
+
+// HasDispatchedJob reports whether the solver job id was dispatched for the challenge.
+func (c *ChallengerDB) HasDispatchedJob(ctx context.Context, challengeID, solverJobID string) (bool, error) {
+	var count int
+	err := c.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM dispatched_jobs WHERE challenge_id = ? AND solver_job_id = ?`,
+		challengeID, solverJobID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check dispatched job: %w", err)
+	}
+	return count > 0, nil
+}
+
+// DeleteDispatchedJob forgets a solver job whose dispatch failed, so its callbacks are rejected.
+func (c *ChallengerDB) DeleteDispatchedJob(ctx context.Context, challengeID, solverJobID string) error {
+	_, err := c.db.ExecContext(ctx, `
+		DELETE FROM dispatched_jobs WHERE challenge_id = ? AND solver_job_id = ?`,
+		challengeID, solverJobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dispatched job: %w", err)
+	}
+	return nil
+}
+
 // SaveWebhookAudit stores audit information for webhook callbacks.
 // Used for debugging, monitoring, and security analysis of incoming callbacks.
-func (c *ChallengerDB) SaveWebhookAudit(audit *models.WebhookAudit) error {
-	_, err := c.db.Exec(`
+func (c *ChallengerDB) SaveWebhookAudit(ctx context.Context, audit *models.WebhookAudit) error {
+	_, err := c.db.ExecContext(ctx, `
 		INSERT INTO webhooks (challenge_id, request_id, headers, body_hash, status_code, created_at)
 		VALUES (?, ?, ?, ?, ?, ?)`,
 		audit.ChallengeID, audit.RequestID, audit.Headers, audit.BodyHash,
@@ -264,17 +870,450 @@ func (c *ChallengerDB) SaveWebhookAudit(audit *models.WebhookAudit) error {
 	return nil
 }
 
-func (c *ChallengerDB) HasSeenNonce(nonce string) (bool, error) {
+// ListWebhookAudits returns callback audit records, newest first.
+// An empty challengeID matches every challenge; a non-positive limit returns every row.
+func (c *ChallengerDB) ListWebhookAudits(ctx context.Context, challengeID string, limit int) ([]*models.WebhookAudit, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, headers, body_hash, status_code, created_at
+		FROM webhooks WHERE (? = '' OR challenge_id = ?)
+		ORDER BY created_at DESC, id DESC LIMIT ?`,
+		challengeID, challengeID, pageLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook audits: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookAudits(rows)
+}
+
+// ListWebhookAuditsAfter returns up to limit callback audits created after the cursor and
+// before until, oldest first. A non-positive limit returns every matching row.
+func (c *ChallengerDB) ListWebhookAuditsAfter(ctx context.Context, after ExportCursor, until time.Time, limit int) ([]*models.WebhookAudit, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, headers, body_hash, status_code, created_at
+		FROM webhooks
+		WHERE created_at < ? AND (created_at > ? OR (created_at = ? AND id > ?))
+		ORDER BY created_at ASC, id ASC LIMIT ?`,
+		until, after.CreatedAt, after.CreatedAt, after.ID, pageLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook audits: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookAudits(rows)
+}
+
+// CleanupOldWebhookAudits deletes callback audit records created before olderThan.
+func (c *ChallengerDB) CleanupOldWebhookAudits(ctx context.Context, olderThan time.Time) error {
+	_, err := c.db.ExecContext(ctx, "DELETE FROM webhooks WHERE created_at < ?", olderThan)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup old webhook audits: %w", err)
+	}
+	return nil
+}
+
+// scanWebhookAudits reads webhook audit rows. Headers and body hash may be NULL.
+func scanWebhookAudits(rows *sql.Rows) ([]*models.WebhookAudit, error) {
+	audits := []*models.WebhookAudit{}
+	for rows.Next() {
+		audit := &models.WebhookAudit{}
+		var headers, bodyHash sql.NullString
+		var statusCode sql.NullInt64
+		if err := rows.Scan(&audit.ID, &audit.ChallengeID, &audit.RequestID, &headers, &bodyHash,
+			&statusCode, &audit.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook audit: %w", err)
+		}
+		audit.Headers = headers.String
+		audit.BodyHash = bodyHash.String
+		audit.StatusCode = int(statusCode.Int64)
+		audits = append(audits, audit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook audits: %w", err)
+	}
+
+	return audits, nil
+}
+
+// SaveLogEntry keeps a local copy of a callback log entry. Re-saving an ID is a no-op.
+func (c *ChallengerDB) SaveLogEntry(ctx context.Context, entry *models.LogEntry) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO log_entries (id, log, challenger_addr, solver_addr, verifier_addr, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.Log, entry.ChallengerAddr, entry.SolverAddr, entry.VerifierAddr, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save log entry: %w", err)
+	}
+	return nil
+}
+
+// GetLogEntry returns a locally stored log entry, or nil if it does not exist.
+func (c *ChallengerDB) GetLogEntry(ctx context.Context, id string) (*models.LogEntry, error) {
+	row := c.db.QueryRowContext(ctx, `
+		SELECT id, log, challenger_addr, solver_addr, verifier_addr, created_at
+		FROM log_entries WHERE id = ?`, id)
+
+	entry, err := scanLogEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log entry: %w", err)
+	}
+	return entry, nil
+}
+
+// scanLogEntry reads a log_entries row.
+// Scan errors are returned unwrapped so callers can detect sql.ErrNoRows.
+func scanLogEntry(row rowScanner) (*models.LogEntry, error) {
+	var entry models.LogEntry
+	var challengerAddr, solverAddr, verifierAddr sql.NullString
+	if err := row.Scan(&entry.ID, &entry.Log, &challengerAddr, &solverAddr, &verifierAddr, &entry.CreatedAt); err != nil {
+		return nil, err
+	}
+	entry.ChallengerAddr = challengerAddr.String
+	entry.SolverAddr = solverAddr.String
+	entry.VerifierAddr = verifierAddr.String
+	return &entry, nil
+}
+
+// EnqueueCommitmentJob persists a pending commitment upload. Re-queuing a result is a no-op.
+func (c *ChallengerDB) EnqueueCommitmentJob(ctx context.Context, challengeID, requestID string) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO commitment_jobs (challenge_id, request_id, created_at) VALUES (?, ?, ?)`,
+		challengeID, requestID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue commitment job: %w", err)
+	}
+	return nil
+}
+
+// ListCommitmentJobs returns pending commitment uploads with fewer than maxAttempts failures, oldest first.
+func (c *ChallengerDB) ListCommitmentJobs(ctx context.Context, maxAttempts int) ([]*models.CommitmentJob, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT challenge_id, request_id, attempts, last_error, created_at
+		FROM commitment_jobs WHERE attempts < ?
+		ORDER BY created_at ASC`, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commitment jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*models.CommitmentJob{}
+	for rows.Next() {
+		job, err := scanCommitmentJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan commitment job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating commitment jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// CompleteCommitmentJob removes a commitment upload once it has succeeded.
+func (c *ChallengerDB) CompleteCommitmentJob(ctx context.Context, challengeID, requestID string) error {
+	_, err := c.db.ExecContext(ctx, `
+		DELETE FROM commitment_jobs WHERE challenge_id = ? AND request_id = ?`,
+		challengeID, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to complete commitment job: %w", err)
+	}
+	return nil
+}
+
+// FailCommitmentJob records a failed upload attempt so the job is retried later.
+func (c *ChallengerDB) FailCommitmentJob(ctx context.Context, challengeID, requestID, errMsg string) error {
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE commitment_jobs SET attempts = attempts + 1, last_error = ?
+		WHERE challenge_id = ? AND request_id = ?`,
+		errMsg, challengeID, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to record commitment job failure: %w", err)
+	}
+	return nil
+}
+
+// scanCommitmentJob reads a commitment_jobs row.
+func scanCommitmentJob(row rowScanner) (*models.CommitmentJob, error) {
+	var job models.CommitmentJob
+	var lastError sql.NullString
+	if err := row.Scan(&job.ChallengeID, &job.RequestID, &job.Attempts, &lastError, &job.CreatedAt); err != nil {
+		return nil, err
+	}
+	job.LastError = lastError.String
+	return &job, nil
+}
+
+// EnqueueLogUpload keeps a log entry the log service did not accept for a later flush.
+// Queuing an entry again replaces its payload and error but keeps its attempts.
+func (c *ChallengerDB) EnqueueLogUpload(ctx context.Context, entry *models.LogEntry, errMsg string) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO pending_log_uploads (log_id, payload, last_error, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (log_id) DO UPDATE SET payload = excluded.payload, last_error = excluded.last_error`,
+		entry.ID, string(payload), errMsg, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue log upload: %w", err)
+	}
+	return nil
+}
+
+// ListLogUploads returns up to limit queued log uploads, oldest first.
+func (c *ChallengerDB) ListLogUploads(ctx context.Context, limit int) ([]*models.PendingLogUpload, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT payload, attempts, last_error, created_at
+		FROM pending_log_uploads ORDER BY created_at ASC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query log uploads: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLogUploads(rows)
+}
+
+// CompleteLogUpload removes a queued log upload once it has been delivered.
+func (c *ChallengerDB) CompleteLogUpload(ctx context.Context, logID string) error {
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM pending_log_uploads WHERE log_id = ?`, logID); err != nil {
+		return fmt.Errorf("failed to complete log upload: %w", err)
+	}
+	return nil
+}
+
+// FailLogUpload records a failed flush of a queued log upload.
+func (c *ChallengerDB) FailLogUpload(ctx context.Context, logID, errMsg string) error {
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE pending_log_uploads SET attempts = attempts + 1, last_error = ? WHERE log_id = ?`,
+		errMsg, logID)
+	if err != nil {
+		return fmt.Errorf("failed to record log upload failure: %w", err)
+	}
+	return nil
+}
+
+// scanLogUploads reads pending_log_uploads rows.
+func scanLogUploads(rows *sql.Rows) ([]*models.PendingLogUpload, error) {
+	uploads := []*models.PendingLogUpload{}
+	for rows.Next() {
+		var upload models.PendingLogUpload
+		var payload string
+		var lastError sql.NullString
+		if err := rows.Scan(&payload, &upload.Attempts, &lastError, &upload.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan log upload: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &upload.Entry); err != nil {
+			return nil, fmt.Errorf("failed to decode log upload payload: %w", err)
+		}
+		upload.LastError = lastError.String
+		uploads = append(uploads, &upload)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating log uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// EnqueueDispatch keeps a challenge a solver did not accept for a later redispatch.
+// Queuing a challenge for the same solver again replaces its priority and error but keeps its attempts.
+func (c *ChallengerDB) EnqueueDispatch(ctx context.Context, dispatch *models.PendingDispatch) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO pending_dispatches (challenge_id, solver_url, priority, last_error, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (challenge_id, solver_url) DO UPDATE SET priority = excluded.priority, last_error = excluded.last_error`,
+		dispatch.ChallengeID, dispatch.SolverURL, dispatch.Priority, dispatch.LastError, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue dispatch: %w", err)
+	}
+	return nil
+}
+
+// ListDispatches returns up to limit queued dispatches, oldest first.
+func (c *ChallengerDB) ListDispatches(ctx context.Context, limit int) ([]*models.PendingDispatch, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT challenge_id, solver_url, priority, attempts, last_error, created_at
+		FROM pending_dispatches ORDER BY created_at ASC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dispatches: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDispatches(rows)
+}
+
+// CompleteDispatch removes a queued dispatch once the solver has accepted the challenge.
+// Completing a challenge that is not queued for the solver is a no-op.
+func (c *ChallengerDB) CompleteDispatch(ctx context.Context, challengeID, solverURL string) error {
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM pending_dispatches WHERE challenge_id = ? AND solver_url = ?`, challengeID, solverURL); err != nil {
+		return fmt.Errorf("failed to complete dispatch: %w", err)
+	}
+	return nil
+}
+
+// FailDispatch records a failed redispatch of a queued challenge.
+func (c *ChallengerDB) FailDispatch(ctx context.Context, challengeID, solverURL, errMsg string) error {
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE pending_dispatches SET attempts = attempts + 1, last_error = ? WHERE challenge_id = ? AND solver_url = ?`,
+		errMsg, challengeID, solverURL)
+	if err != nil {
+		return fmt.Errorf("failed to record dispatch failure: %w", err)
+	}
+	return nil
+}
+
+// scanDispatches reads pending_dispatches rows.
+func scanDispatches(rows *sql.Rows) ([]*models.PendingDispatch, error) {
+	dispatches := []*models.PendingDispatch{}
+	for rows.Next() {
+		var dispatch models.PendingDispatch
+		var lastError sql.NullString
+		if err := rows.Scan(&dispatch.ChallengeID, &dispatch.SolverURL, &dispatch.Priority, &dispatch.Attempts,
+			&lastError, &dispatch.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dispatch: %w", err)
+		}
+		dispatch.LastError = lastError.String
+		dispatches = append(dispatches, &dispatch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dispatches: %w", err)
+	}
+	return dispatches, nil
+}
+
+// OpenSubmissionWindow starts the acceptance window of a challenge and returns it.
+// If the window already exists it is returned unchanged, so the first close time wins.
+func (c *ChallengerDB) OpenSubmissionWindow(ctx context.Context, challengeID string, closesAt time.Time) (*models.SubmissionWindow, error) {
+	if _, err := c.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO submission_windows (challenge_id, closes_at) VALUES (?, ?)`,
+		challengeID, closesAt.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to open submission window: %w", err)
+	}
+
+	window, err := c.GetSubmissionWindow(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, fmt.Errorf("submission window for %s disappeared after insert", challengeID)
+	}
+	return window, nil
+}
+
+// GetSubmissionWindow returns the acceptance window of a challenge, or nil if none was opened.
+func (c *ChallengerDB) GetSubmissionWindow(ctx context.Context, challengeID string) (*models.SubmissionWindow, error) {
+	row := c.db.QueryRowContext(ctx, `
+		SELECT challenge_id, closes_at, best_request_id, best_score, settled_at
+		FROM submission_windows WHERE challenge_id = ?`, challengeID)
+
+	window, err := scanSubmissionWindow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission window: %w", err)
+	}
+	return window, nil
+}
+
+// RecordSubmission makes requestID the best result of an unsettled window if its score
+// beats the current best. Ties keep the earlier result, which also makes re-recording
+// the same request a no-op. Returns true if requestID became the best result.
+func (c *ChallengerDB) RecordSubmission(ctx context.Context, challengeID, requestID string, score uint64) (bool, error) {
+	res, err := c.db.ExecContext(ctx, `
+		UPDATE submission_windows SET best_request_id = ?, best_score = ?
+		WHERE challenge_id = ? AND settled_at IS NULL
+			AND (best_request_id IS NULL OR best_score < ?)`,
+		requestID, int64(score), challengeID, int64(score))
+	if err != nil {
+		return false, fmt.Errorf("failed to record submission: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ListClosedSubmissionWindows returns unsettled windows whose close time is at or before now.
+func (c *ChallengerDB) ListClosedSubmissionWindows(ctx context.Context, now time.Time) ([]*models.SubmissionWindow, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT challenge_id, closes_at, best_request_id, best_score, settled_at
+		FROM submission_windows WHERE settled_at IS NULL AND closes_at <= ?
+		ORDER BY closes_at ASC`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submission windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []*models.SubmissionWindow{}
+	for rows.Next() {
+		window, err := scanSubmissionWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating submission windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// SettleSubmissionWindow marks a window settled. It returns false if the window was already
+// settled, so concurrent settlers commit and pay the winner at most once.
+func (c *ChallengerDB) SettleSubmissionWindow(ctx context.Context, challengeID string, settledAt time.Time) (bool, error) {
+	res, err := c.db.ExecContext(ctx, `
+		UPDATE submission_windows SET settled_at = ? WHERE challenge_id = ? AND settled_at IS NULL`,
+		settledAt.UTC(), challengeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to settle submission window: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// scanSubmissionWindow reads a submission_windows row.
+// Scan errors are returned unwrapped so callers can detect sql.ErrNoRows.
+func scanSubmissionWindow(row rowScanner) (*models.SubmissionWindow, error) {
+	var window models.SubmissionWindow
+	var bestRequestID sql.NullString
+	var bestScore int64
+	var settledAt sql.NullTime
+	if err := row.Scan(&window.ChallengeID, &window.ClosesAt, &bestRequestID, &bestScore, &settledAt); err != nil {
+		return nil, err
+	}
+
+	window.BestRequestID = bestRequestID.String
+	window.BestScore = uint64(bestScore)
+	if settledAt.Valid {
+		window.SettledAt = &settledAt.Time
+	}
+	return &window, nil
+}
+
+func (c *ChallengerDB) HasSeenNonce(ctx context.Context, nonce string) (bool, error) {
 	var count int
-	err := c.db.QueryRow("SELECT COUNT(*) FROM seen_nonces WHERE nonce = ?", nonce).Scan(&count)
+	err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM seen_nonces WHERE nonce = ?", nonce).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check nonce: %w", err)
 	}
 	return count > 0, nil
 }
 
-func (c *ChallengerDB) SaveNonce(nonce string) error {
-	_, err := c.db.Exec("INSERT OR IGNORE INTO seen_nonces (nonce, seen_at) VALUES (?, ?)",
+func (c *ChallengerDB) SaveNonce(ctx context.Context, nonce string) error {
+	_, err := c.db.ExecContext(ctx, "INSERT OR IGNORE INTO seen_nonces (nonce, seen_at) VALUES (?, ?)",
 		nonce, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to save nonce: %w", err)
@@ -282,8 +1321,8 @@ func (c *ChallengerDB) SaveNonce(nonce string) error {
 	return nil
 }
 
-func (c *ChallengerDB) CleanupOldNonces(olderThan time.Time) error {
-	_, err := c.db.Exec("DELETE FROM seen_nonces WHERE seen_at < ?", olderThan)
+func (c *ChallengerDB) CleanupOldNonces(ctx context.Context, olderThan time.Time) error {
+	_, err := c.db.ExecContext(ctx, "DELETE FROM seen_nonces WHERE seen_at < ?", olderThan)
 	if err != nil {
 		return fmt.Errorf("failed to cleanup old nonces: %w", err)
 	}
@@ -298,7 +1337,7 @@ func (c *ChallengerDB) SaveContract(ctx context.Context, contract *models.Contra
 		return fmt.Errorf("failed to marshal contract metadata: %w", err)
 	}
 
-	_, err = c.db.Exec(`
+	_, err = c.db.ExecContext(ctx, `
 		INSERT OR REPLACE INTO contracts (name, address, network, chain_id, tx_hash, deployed_at, contract_type, metadata)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 		contract.Name, contract.Address, contract.Network, contract.ChainID,
@@ -314,7 +1353,7 @@ func (c *ChallengerDB) SaveContract(ctx context.Context, contract *models.Contra
 // GetContractByName retrieves a contract by name and chain ID from the database.
 // Returns the contract with deserialized metadata or an error if not found.
 func (c *ChallengerDB) GetContractByName(ctx context.Context, name, chainID string) (*models.Contract, error) {
-	row := c.db.QueryRow(`
+	row := c.db.QueryRowContext(ctx, `
 		SELECT id, name, address, network, chain_id, tx_hash, deployed_at, contract_type, metadata
 		FROM contracts WHERE name = ? AND chain_id = ?`, name, chainID)
 
@@ -337,18 +1376,43 @@ func (c *ChallengerDB) GetContractByName(ctx context.Context, name, chainID stri
 	return &contract, nil
 }
 
-// ListContracts retrieves all contracts for a given chain ID.
-// Returns a slice of contracts with deserialized metadata.
-func (c *ChallengerDB) ListContracts(ctx context.Context, chainID string) ([]*models.Contract, error) {
-	rows, err := c.db.Query(`
+// DeleteContract removes a contract by name and chain ID.
+// Deleting a contract that does not exist is not an error.
+func (c *ChallengerDB) DeleteContract(ctx context.Context, name, chainID string) error {
+	_, err := c.db.ExecContext(ctx, `DELETE FROM contracts WHERE name = ? AND chain_id = ?`, name, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to delete contract: %w", err)
+	}
+
+	return nil
+}
+
+// ListContracts retrieves a page of contracts for a given chain ID, newest first.
+// An empty contractType matches every type; a non-positive limit returns every row after offset.
+// Returns the page with deserialized metadata together with the total number of matching contracts.
+func (c *ChallengerDB) ListContracts(ctx context.Context, chainID, contractType string, limit, offset int) ([]*models.Contract, int, error) {
+	where := "WHERE chain_id = ?"
+	args := []interface{}{chainID}
+	if contractType != "" {
+		where += " AND contract_type = ?"
+		args = append(args, contractType)
+	}
+
+	var total int
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM contracts "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count contracts: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, `
 		SELECT id, name, address, network, chain_id, tx_hash, deployed_at, contract_type, metadata
-		FROM contracts WHERE chain_id = ? ORDER BY deployed_at DESC`, chainID)
+		FROM contracts `+where+` ORDER BY deployed_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, pageLimit(limit), pageOffset(offset))...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query contracts: %w", err)
+		return nil, 0, fmt.Errorf("failed to query contracts: %w", err)
 	}
 	defer rows.Close()
 
-	var contracts []*models.Contract
+	contracts := []*models.Contract{}
 	for rows.Next() {
 		var contract models.Contract
 		var metadataJSON string
@@ -356,21 +1420,60 @@ func (c *ChallengerDB) ListContracts(ctx context.Context, chainID string) ([]*mo
 		err := rows.Scan(&contract.ID, &contract.Name, &contract.Address, &contract.Network,
 			&contract.ChainID, &contract.TxHash, &contract.DeployedAt, &contract.ContractType, &metadataJSON)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan contract: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan contract: %w", err)
 		}
 
 		if err := json.Unmarshal([]byte(metadataJSON), &contract.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal contract metadata: %w", err)
+			return nil, 0, fmt.Errorf("failed to unmarshal contract metadata: %w", err)
 		}
 
 		contracts = append(contracts, &contract)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating contracts: %w", err)
+		return nil, 0, fmt.Errorf("error iterating contracts: %w", err)
 	}
 
-	return contracts, nil
+	return contracts, total, nil
+}
+
+// HasBountyTransfer reports whether the bounty for commitmentID was already paid from vaultID.
+func (c *ChallengerDB) HasBountyTransfer(ctx context.Context, commitmentID, vaultID string) (bool, error) {
+	var count int
+	err := c.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bounty_transfers WHERE commitment_id = ? AND vault_id = ?`,
+		commitmentID, vaultID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check bounty transfer: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RecordBountyTransfer records a paid bounty. Recording the same commitment and vault again is a no-op.
+func (c *ChallengerDB) RecordBountyTransfer(ctx context.Context, transfer *models.BountyTransfer) error {
+	if transfer.TransferredAt.IsZero() {
+		transfer.TransferredAt = time.Now()
+	}
+
+	_, err := c.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO bounty_transfers (commitment_id, vault_id, digest, solver_address, transferred_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		transfer.CommitmentID, transfer.VaultID, transfer.Digest, transfer.SolverAddress, transfer.TransferredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record bounty transfer: %w", err)
+	}
+	return nil
+}
+
+// Truncate deletes every row from the challenger tables. Intended for test isolation.
+func (c *ChallengerDB) Truncate(ctx context.Context) error {
+	// Children before parents so foreign keys never dangle mid-reset
+	tables := []string{"commitments", "results", "dispatched_jobs", "webhooks", "log_entries", "commitment_jobs", "pending_log_uploads", "pending_dispatches", "submission_windows", "bounty_transfers", "seen_nonces", "hmac_keys", "contracts", "challenges"}
+	for _, table := range tables {
+		if _, err := c.db.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", table, err)
+		}
+	}
+	return nil
 }
 
 func (c *ChallengerDB) Close() error {
@@ -0,0 +1,1021 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+	"reverse-challenge-system/pkg/scoring"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresChallengerDB is the Postgres-backed ChallengerStore.
+// Allows several challenger replicas to share state behind a load balancer.
+type PostgresChallengerDB struct {
+	db *sql.DB // Postgres connection pool
+}
+
+// NewPostgresChallengerDB connects to Postgres using the given connection string
+// and creates the challenger tables if they do not exist.
+func NewPostgresChallengerDB(dsn string) (*PostgresChallengerDB, error) {
+	db, err := openPostgres(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	pdb := &PostgresChallengerDB{db: db}
+	if err := pdb.Migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return pdb, nil
+}
+
+// NewPostgresChallengerReadOnlyDB connects to a Postgres read replica of the challenger database.
+// Tables are expected to exist on the primary and are not created.
+func NewPostgresChallengerReadOnlyDB(dsn string) (*PostgresChallengerDB, error) {
+	db, err := openPostgres(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresChallengerDB{db: db}, nil
+}
+
+// openPostgres opens a connection pool and verifies the server is reachable.
+func openPostgres(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return db, nil
+}
+
+// createPostgresChallengerSchema mirrors the SQLite challenger schema using Postgres types.
+func createPostgresChallengerSchema(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS challenges (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL,
+			problem TEXT NOT NULL,
+			output_spec TEXT NOT NULL,
+			validation_rule TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMPTZ,
+			expired_at TIMESTAMPTZ
+		)`,
+		// Columns added after the initial schema
+		`ALTER TABLE challenges ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ`,
+		`ALTER TABLE challenges ADD COLUMN IF NOT EXISTS expired_at TIMESTAMPTZ`,
+		`CREATE TABLE IF NOT EXISTS results (
+			id BIGSERIAL PRIMARY KEY,
+			challenge_id TEXT NOT NULL REFERENCES challenges(id),
+			request_id TEXT NOT NULL,
+			solver_job_id TEXT,
+			status TEXT NOT NULL,
+			received_answer TEXT,
+			is_correct BOOLEAN,
+			solver_address TEXT,
+			compute_time_ms BIGINT,
+			solver_metadata TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (challenge_id, request_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS dispatched_jobs (
+			challenge_id TEXT NOT NULL,
+			solver_job_id TEXT NOT NULL,
+			dispatched_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (challenge_id, solver_job_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id BIGSERIAL PRIMARY KEY,
+			challenge_id TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			headers TEXT,
+			body_hash TEXT,
+			status_code INTEGER,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS seen_nonces (
+			nonce TEXT PRIMARY KEY,
+			seen_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		postgresHMACKeysTable,
+		`CREATE TABLE IF NOT EXISTS commitments (
+			id BIGSERIAL PRIMARY KEY,
+			challenge_id TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			object_id TEXT NOT NULL,
+			commitment_hash TEXT NOT NULL,
+			settlement_status TEXT NOT NULL DEFAULT 'pending',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (challenge_id, request_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS log_entries (
+			id TEXT PRIMARY KEY,
+			log TEXT NOT NULL,
+			challenger_addr TEXT,
+			solver_addr TEXT,
+			verifier_addr TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS commitment_jobs (
+			challenge_id TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (challenge_id, request_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS pending_log_uploads (
+			log_id TEXT PRIMARY KEY,
+			payload TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS submission_windows (
+			challenge_id TEXT PRIMARY KEY,
+			closes_at TIMESTAMPTZ NOT NULL,
+			best_request_id TEXT,
+			best_score BIGINT NOT NULL DEFAULT 0,
+			settled_at TIMESTAMPTZ
+		)`,
+		`CREATE INDEX IF NOT EXISTS ix_results_cid_created ON results(challenge_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_results_solver_address ON results(solver_address)`,
+		`CREATE INDEX IF NOT EXISTS ix_seen_nonces_seen_at ON seen_nonces(seen_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_submission_windows_closes_at ON submission_windows(closes_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_webhooks_cid_created ON webhooks(challenge_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_webhooks_created_at ON webhooks(created_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_challenges_expires_at ON challenges(expires_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to execute query %s: %w", query, err)
+		}
+	}
+
+	return nil
+}
+
+// postgresChallengerMigrations is the Postgres challenger schema history, applied in order by Migrate.
+// Append new steps for schema changes; never edit a migration that has shipped.
+var postgresChallengerMigrations = []migration{
+	{version: 1, description: "initial schema", up: createPostgresChallengerSchema},
+	{version: 2, description: "index results by creation time", up: createResultsCreatedAtIndex},
+	{version: 3, description: "queue undelivered challenge dispatches", up: createPostgresPendingDispatchesTable},
+	{version: 4, description: "record first-correct winners", up: addPostgresChallengeWinnerColumns},
+}
+
+// addPostgresChallengeWinnerColumns records the first correct answer of each challenge, flags
+// results received after it, and keys queued dispatches by solver so a challenge can wait on several
+func addPostgresChallengeWinnerColumns(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`ALTER TABLE challenges ADD COLUMN IF NOT EXISTS competitive BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE challenges ADD COLUMN IF NOT EXISTS winner_request_id TEXT`,
+		`ALTER TABLE challenges ADD COLUMN IF NOT EXISTS winner_solver_address TEXT`,
+		`ALTER TABLE challenges ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ`,
+		`ALTER TABLE results ADD COLUMN IF NOT EXISTS is_late BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE pending_dispatches DROP CONSTRAINT IF EXISTS pending_dispatches_pkey`,
+		`ALTER TABLE pending_dispatches ADD PRIMARY KEY (challenge_id, solver_url)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to execute query %s: %w", query, err)
+		}
+	}
+	return nil
+}
+
+// createPostgresPendingDispatchesTable adds the queue of challenges the solver did not accept
+func createPostgresPendingDispatchesTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS pending_dispatches (
+			challenge_id TEXT PRIMARY KEY,
+			solver_url TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create pending_dispatches table: %w", err)
+	}
+	return nil
+}
+
+// Migrate applies the challenger schema migrations the database has not recorded yet.
+// It runs on open and is safe to call again.
+func (p *PostgresChallengerDB) Migrate(ctx context.Context) error {
+	return runMigrations(ctx, p.db, postgresMigrationDialect, challengerSchema, postgresChallengerMigrations)
+}
+
+// CreateChallenge stores a new challenge in the database.
+func (p *PostgresChallengerDB) CreateChallenge(ctx context.Context, challenge *models.Challenge) error {
+	validationRuleJSON, err := json.Marshal(challenge.ValidationRule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal validation rule: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO challenges (id, type, problem, output_spec, validation_rule, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		challenge.ID, challenge.Type, string(challenge.Problem),
+		string(challenge.OutputSpec), string(validationRuleJSON), challenge.CreatedAt, nullTime(challenge.ExpiresAt))
+
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", ErrDuplicateChallenge, challenge.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallenge retrieves a challenge by its ID from the database.
+func (p *PostgresChallengerDB) GetChallenge(ctx context.Context, id string) (*models.Challenge, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT id, type, problem, output_spec, validation_rule, created_at, expires_at, expired_at,
+			competitive, winner_request_id, winner_solver_address, closed_at
+		FROM challenges WHERE id = $1`, id)
+
+	challenge, err := scanChallenge(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// SetChallengeExpiry records the deadline sent to a solver, only ever moving it later.
+func (p *PostgresChallengerDB) SetChallengeExpiry(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := p.db.ExecContext(ctx, `
+		UPDATE challenges SET expires_at = $1, expired_at = NULL
+		WHERE id = $2 AND (expires_at IS NULL OR expires_at < $1)`,
+		expiresAt.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set challenge expiry: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredChallenges returns challenges past their expiry that have not been marked expired yet.
+func (p *PostgresChallengerDB) ListExpiredChallenges(ctx context.Context, now time.Time) ([]*models.Challenge, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, type, problem, output_spec, validation_rule, created_at, expires_at, expired_at,
+			competitive, winner_request_id, winner_solver_address, closed_at
+		FROM challenges WHERE expired_at IS NULL AND expires_at IS NOT NULL AND expires_at <= $1
+		ORDER BY expires_at ASC`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []*models.Challenge{}
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, challenge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired challenges: %w", err)
+	}
+
+	return challenges, nil
+}
+
+// MarkChallengeExpired marks a challenge expired, returning false if another sweep got there first.
+func (p *PostgresChallengerDB) MarkChallengeExpired(ctx context.Context, id string, expiredAt time.Time) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE challenges SET expired_at = $1
+		WHERE id = $2 AND expired_at IS NULL AND expires_at IS NOT NULL AND expires_at <= $1`,
+		expiredAt.UTC(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark challenge expired: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetChallengeCompetitive marks a challenge as sent to several solvers, so only the first
+// correct answer is committed.
+func (p *PostgresChallengerDB) SetChallengeCompetitive(ctx context.Context, id string) error {
+	if _, err := p.db.ExecContext(ctx, `UPDATE challenges SET competitive = TRUE WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to mark challenge competitive: %w", err)
+	}
+	return nil
+}
+
+// CloseChallenge records requestID as the first correct answer to a challenge and closes it,
+// returning false when another answer closed it first. The conditional update makes the
+// first of several concurrent callbacks, across replicas, the only winner; a retry of the
+// winning callback is a read-only match on both its request ID and solver address.
+func (p *PostgresChallengerDB) CloseChallenge(ctx context.Context, id, requestID, solverAddress string, closedAt time.Time) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE challenges SET winner_request_id = $1, winner_solver_address = $2, closed_at = $3
+		WHERE id = $4 AND closed_at IS NULL`,
+		requestID, solverAddress, closedAt.UTC(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to close challenge: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return true, nil
+	}
+
+	var count int
+	err = p.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM challenges
+		WHERE id = $1 AND winner_request_id = $2 AND winner_solver_address = $3`,
+		id, requestID, solverAddress).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check challenge winner: %w", err)
+	}
+	return count > 0, nil
+}
+
+// SaveResult stores a challenge result in the database.
+func (p *PostgresChallengerDB) SaveResult(ctx context.Context, result *models.Result) error {
+	metadataJSON := ""
+	if result.SolverMetadata != nil {
+		metadataJSON = string(result.SolverMetadata)
+	}
+
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO results (challenge_id, request_id, solver_job_id, status,
+			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		result.ChallengeID, result.RequestID, result.SolverJobID, result.Status,
+		result.ReceivedAnswer, result.IsCorrect, result.SolverAddress, result.ComputeTimeMs,
+		metadataJSON, result.CreatedAt, result.IsLate)
+
+	if err != nil {
+		return fmt.Errorf("failed to save result: %w", err)
+	}
+
+	return nil
+}
+
+// SaveResultWithDuplicateCheck saves a result and returns insertion status.
+// Uses ON CONFLICT DO NOTHING so concurrent replicas get the same idempotency as SQLite's INSERT OR IGNORE.
+func (p *PostgresChallengerDB) SaveResultWithDuplicateCheck(ctx context.Context, result *models.Result) (bool, error) {
+	metadataJSON := ""
+	if result.SolverMetadata != nil {
+		metadataJSON = string(result.SolverMetadata)
+	}
+
+	res, err := p.db.ExecContext(ctx, `
+		INSERT INTO results (challenge_id, request_id, solver_job_id, status,
+			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (challenge_id, request_id) DO NOTHING`,
+		result.ChallengeID, result.RequestID, result.SolverJobID, result.Status,
+		result.ReceivedAnswer, result.IsCorrect, result.SolverAddress, result.ComputeTimeMs,
+		metadataJSON, result.CreatedAt, result.IsLate)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to save result: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetResult retrieves a stored result by challenge ID and request ID.
+// Returns nil if no result is found.
+func (p *PostgresChallengerDB) GetResult(ctx context.Context, challengeID, requestID string) (*models.Result, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results WHERE challenge_id = $1 AND request_id = $2`, challengeID, requestID)
+
+	result, err := scanResult(row)
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get result: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListResultsByChallenge retrieves all stored results for a challenge ordered by creation time.
+func (p *PostgresChallengerDB) ListResultsByChallenge(ctx context.Context, challengeID string) ([]*models.Result, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results WHERE challenge_id = $1 ORDER BY created_at ASC, id ASC`, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	return scanResults(rows)
+}
+
+// ListResults retrieves a page of results ordered by creation time, newest first.
+// Empty challengeID or status match all results; a non-positive limit returns every row after offset.
+func (p *PostgresChallengerDB) ListResults(ctx context.Context, challengeID string, status string, limit, offset int) ([]*models.Result, int, error) {
+	where := "WHERE 1=1"
+	var args []interface{}
+	if challengeID != "" {
+		args = append(args, challengeID)
+		where += fmt.Sprintf(" AND challenge_id = $%d", len(args))
+	}
+	if status != "" {
+		args = append(args, status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int
+	if err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM results "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count results: %w", err)
+	}
+
+	// Postgres treats LIMIT NULL as unbounded
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	args = append(args, pgLimit, pageOffset(offset))
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results %s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := scanResults(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// ListResultsAfter returns up to limit results created after the cursor and before until,
+// oldest first. A non-positive limit returns every matching row.
+func (p *PostgresChallengerDB) ListResultsAfter(ctx context.Context, after ExportCursor, until time.Time, limit int) ([]*models.Result, error) {
+	// Postgres treats LIMIT NULL as unbounded
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, solver_job_id, status, received_answer,
+			is_correct, solver_address, compute_time_ms, solver_metadata, created_at, is_late
+		FROM results
+		WHERE created_at < $1 AND (created_at, id) > ($2, $3)
+		ORDER BY created_at ASC, id ASC LIMIT $4`,
+		until, after.CreatedAt, after.ID, pgLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	return scanResults(rows)
+}
+
+// GetChallengeStats aggregates challenge and result counts in the database.
+func (p *PostgresChallengerDB) GetChallengeStats(ctx context.Context) (models.ChallengerStats, error) {
+	return challengeStats(ctx, p.db)
+}
+
+// GetLeaderboard ranks solvers by score, then by correct answers and faster average compute
+// time. Each correct answer that was not late scores scoring.MaxScore.
+func (p *PostgresChallengerDB) GetLeaderboard(ctx context.Context, limit int) ([]models.SolverScore, error) {
+	// Postgres treats LIMIT NULL as unbounded
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT solver_address,
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN is_correct THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(compute_time_ms), 0)::float8,
+			COALESCE(SUM(CASE WHEN is_correct AND NOT is_late THEN $1::bigint ELSE 0 END), 0) AS score
+		FROM results
+		WHERE solver_address IS NOT NULL AND solver_address != ''
+		GROUP BY solver_address
+		ORDER BY score DESC, 3 DESC, 4 ASC, solver_address ASC
+		LIMIT $2`, int64(scoring.MaxScore), pgLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLeaderboard(rows)
+}
+
+// SaveDispatchedJob records a solver job id returned when a challenge was sent out.
+func (p *PostgresChallengerDB) SaveDispatchedJob(ctx context.Context, challengeID, solverJobID string) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO dispatched_jobs (challenge_id, solver_job_id, dispatched_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (challenge_id, solver_job_id) DO NOTHING`, challengeID, solverJobID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save dispatched job: %w", err)
+	}
+	return nil
+}
+
+// HasDispatchedJob reports whether the solver job id was dispatched for the challenge.
+func (p *PostgresChallengerDB) HasDispatchedJob(ctx context.Context, challengeID, solverJobID string) (bool, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM dispatched_jobs WHERE challenge_id = $1 AND solver_job_id = $2`,
+		challengeID, solverJobID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check dispatched job: %w", err)
+	}
+	return count > 0, nil
+}
+
+// DeleteDispatchedJob forgets a solver job whose dispatch failed, so its callbacks are rejected.
+func (p *PostgresChallengerDB) DeleteDispatchedJob(ctx context.Context, challengeID, solverJobID string) error {
+	_, err := p.db.ExecContext(ctx, `
+		DELETE FROM dispatched_jobs WHERE challenge_id = $1 AND solver_job_id = $2`,
+		challengeID, solverJobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dispatched job: %w", err)
+	}
+	return nil
+}
+
+// SaveCommitment records a commitment uploaded to Sui for a stored result.
+func (p *PostgresChallengerDB) SaveCommitment(ctx context.Context, commitment *models.Commitment) error {
+	settlementStatus := commitment.SettlementStatus
+	if settlementStatus == "" {
+		settlementStatus = "pending"
+	}
+
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO commitments (challenge_id, request_id, object_id, commitment_hash,
+			settlement_status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		commitment.ChallengeID, commitment.RequestID, commitment.ObjectID,
+		commitment.CommitmentHash, settlementStatus, commitment.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save commitment: %w", err)
+	}
+
+	return nil
+}
+
+// ListCommitmentsBySolver retrieves a page of commitments whose result was submitted by the solver address,
+// newest first. A non-positive limit returns every row after offset.
+func (p *PostgresChallengerDB) ListCommitmentsBySolver(ctx context.Context, solverAddress string, limit, offset int) ([]*models.SolverCommitment, int, error) {
+	var total int
+	if err := p.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM commitments cm
+		JOIN results r ON r.challenge_id = cm.challenge_id AND r.request_id = cm.request_id
+		WHERE r.solver_address = $1`, solverAddress).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count commitments: %w", err)
+	}
+
+	// Postgres treats LIMIT NULL as unbounded
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT cm.id, cm.challenge_id, cm.request_id, cm.object_id, cm.commitment_hash,
+			cm.settlement_status, cm.created_at, r.solver_address, r.status, r.is_correct
+		FROM commitments cm
+		JOIN results r ON r.challenge_id = cm.challenge_id AND r.request_id = cm.request_id
+		WHERE r.solver_address = $1
+		ORDER BY cm.created_at DESC, cm.id DESC LIMIT $2 OFFSET $3`,
+		solverAddress, pgLimit, pageOffset(offset))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query commitments: %w", err)
+	}
+	defer rows.Close()
+
+	commitments, err := scanSolverCommitments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return commitments, total, nil
+}
+
+// SaveWebhookAudit stores audit information for webhook callbacks.
+func (p *PostgresChallengerDB) SaveWebhookAudit(ctx context.Context, audit *models.WebhookAudit) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO webhooks (challenge_id, request_id, headers, body_hash, status_code, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		audit.ChallengeID, audit.RequestID, audit.Headers, audit.BodyHash,
+		audit.StatusCode, audit.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save webhook audit: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhookAudits returns callback audit records, newest first.
+// An empty challengeID matches every challenge; a non-positive limit returns every row.
+func (p *PostgresChallengerDB) ListWebhookAudits(ctx context.Context, challengeID string, limit int) ([]*models.WebhookAudit, error) {
+	// Postgres treats LIMIT NULL as unbounded
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, headers, body_hash, status_code, created_at
+		FROM webhooks WHERE ($1 = '' OR challenge_id = $1)
+		ORDER BY created_at DESC, id DESC LIMIT $2`,
+		challengeID, pgLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook audits: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookAudits(rows)
+}
+
+// ListWebhookAuditsAfter returns up to limit callback audits created after the cursor and
+// before until, oldest first. A non-positive limit returns every matching row.
+func (p *PostgresChallengerDB) ListWebhookAuditsAfter(ctx context.Context, after ExportCursor, until time.Time, limit int) ([]*models.WebhookAudit, error) {
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, challenge_id, request_id, headers, body_hash, status_code, created_at
+		FROM webhooks
+		WHERE created_at < $1 AND (created_at, id) > ($2, $3)
+		ORDER BY created_at ASC, id ASC LIMIT $4`,
+		until, after.CreatedAt, after.ID, pgLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook audits: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookAudits(rows)
+}
+
+// CleanupOldWebhookAudits deletes callback audit records created before olderThan.
+func (p *PostgresChallengerDB) CleanupOldWebhookAudits(ctx context.Context, olderThan time.Time) error {
+	_, err := p.db.ExecContext(ctx, "DELETE FROM webhooks WHERE created_at < $1", olderThan)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup old webhook audits: %w", err)
+	}
+	return nil
+}
+
+// SaveLogEntry keeps a local copy of a callback log entry. Re-saving an ID is a no-op.
+func (p *PostgresChallengerDB) SaveLogEntry(ctx context.Context, entry *models.LogEntry) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO log_entries (id, log, challenger_addr, solver_addr, verifier_addr, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING`,
+		entry.ID, entry.Log, entry.ChallengerAddr, entry.SolverAddr, entry.VerifierAddr, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save log entry: %w", err)
+	}
+	return nil
+}
+
+// GetLogEntry returns a locally stored log entry, or nil if it does not exist.
+func (p *PostgresChallengerDB) GetLogEntry(ctx context.Context, id string) (*models.LogEntry, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT id, log, challenger_addr, solver_addr, verifier_addr, created_at
+		FROM log_entries WHERE id = $1`, id)
+
+	entry, err := scanLogEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log entry: %w", err)
+	}
+	return entry, nil
+}
+
+// EnqueueCommitmentJob persists a pending commitment upload. Re-queuing a result is a no-op.
+func (p *PostgresChallengerDB) EnqueueCommitmentJob(ctx context.Context, challengeID, requestID string) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO commitment_jobs (challenge_id, request_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (challenge_id, request_id) DO NOTHING`,
+		challengeID, requestID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue commitment job: %w", err)
+	}
+	return nil
+}
+
+// ListCommitmentJobs returns pending commitment uploads with fewer than maxAttempts failures, oldest first.
+func (p *PostgresChallengerDB) ListCommitmentJobs(ctx context.Context, maxAttempts int) ([]*models.CommitmentJob, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT challenge_id, request_id, attempts, last_error, created_at
+		FROM commitment_jobs WHERE attempts < $1
+		ORDER BY created_at ASC`, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commitment jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*models.CommitmentJob{}
+	for rows.Next() {
+		job, err := scanCommitmentJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan commitment job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating commitment jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// CompleteCommitmentJob removes a commitment upload once it has succeeded.
+func (p *PostgresChallengerDB) CompleteCommitmentJob(ctx context.Context, challengeID, requestID string) error {
+	_, err := p.db.ExecContext(ctx, `
+		DELETE FROM commitment_jobs WHERE challenge_id = $1 AND request_id = $2`,
+		challengeID, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to complete commitment job: %w", err)
+	}
+	return nil
+}
+
+// FailCommitmentJob records a failed upload attempt so the job is retried later.
+func (p *PostgresChallengerDB) FailCommitmentJob(ctx context.Context, challengeID, requestID, errMsg string) error {
+	_, err := p.db.ExecContext(ctx, `
+		UPDATE commitment_jobs SET attempts = attempts + 1, last_error = $1
+		WHERE challenge_id = $2 AND request_id = $3`,
+		errMsg, challengeID, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to record commitment job failure: %w", err)
+	}
+	return nil
+}
+
+// EnqueueLogUpload keeps a log entry the log service did not accept for a later flush.
+// Queuing an entry again replaces its payload and error but keeps its attempts.
+func (p *PostgresChallengerDB) EnqueueLogUpload(ctx context.Context, entry *models.LogEntry, errMsg string) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO pending_log_uploads (log_id, payload, last_error, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (log_id) DO UPDATE SET payload = excluded.payload, last_error = excluded.last_error`,
+		entry.ID, string(payload), errMsg, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue log upload: %w", err)
+	}
+	return nil
+}
+
+// ListLogUploads returns up to limit queued log uploads, oldest first.
+func (p *PostgresChallengerDB) ListLogUploads(ctx context.Context, limit int) ([]*models.PendingLogUpload, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT payload, attempts, last_error, created_at
+		FROM pending_log_uploads ORDER BY created_at ASC LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query log uploads: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLogUploads(rows)
+}
+
+// CompleteLogUpload removes a queued log upload once it has been delivered.
+func (p *PostgresChallengerDB) CompleteLogUpload(ctx context.Context, logID string) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM pending_log_uploads WHERE log_id = $1`, logID); err != nil {
+		return fmt.Errorf("failed to complete log upload: %w", err)
+	}
+	return nil
+}
+
+// FailLogUpload records a failed flush of a queued log upload.
+func (p *PostgresChallengerDB) FailLogUpload(ctx context.Context, logID, errMsg string) error {
+	_, err := p.db.ExecContext(ctx, `
+		UPDATE pending_log_uploads SET attempts = attempts + 1, last_error = $1 WHERE log_id = $2`,
+		errMsg, logID)
+	if err != nil {
+		return fmt.Errorf("failed to record log upload failure: %w", err)
+	}
+	return nil
+}
+
+// EnqueueDispatch keeps a challenge a solver did not accept for a later redispatch.
+// Queuing a challenge for the same solver again replaces its priority and error but keeps its attempts.
+func (p *PostgresChallengerDB) EnqueueDispatch(ctx context.Context, dispatch *models.PendingDispatch) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO pending_dispatches (challenge_id, solver_url, priority, last_error, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (challenge_id, solver_url) DO UPDATE SET priority = excluded.priority, last_error = excluded.last_error`,
+		dispatch.ChallengeID, dispatch.SolverURL, dispatch.Priority, dispatch.LastError, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue dispatch: %w", err)
+	}
+	return nil
+}
+
+// ListDispatches returns up to limit queued dispatches, oldest first.
+func (p *PostgresChallengerDB) ListDispatches(ctx context.Context, limit int) ([]*models.PendingDispatch, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT challenge_id, solver_url, priority, attempts, last_error, created_at
+		FROM pending_dispatches ORDER BY created_at ASC LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dispatches: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDispatches(rows)
+}
+
+// CompleteDispatch removes a queued dispatch once the solver has accepted the challenge.
+// Completing a challenge that is not queued for the solver is a no-op.
+func (p *PostgresChallengerDB) CompleteDispatch(ctx context.Context, challengeID, solverURL string) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM pending_dispatches WHERE challenge_id = $1 AND solver_url = $2`, challengeID, solverURL); err != nil {
+		return fmt.Errorf("failed to complete dispatch: %w", err)
+	}
+	return nil
+}
+
+// FailDispatch records a failed redispatch of a queued challenge.
+func (p *PostgresChallengerDB) FailDispatch(ctx context.Context, challengeID, solverURL, errMsg string) error {
+	_, err := p.db.ExecContext(ctx, `
+		UPDATE pending_dispatches SET attempts = attempts + 1, last_error = $1 WHERE challenge_id = $2 AND solver_url = $3`,
+		errMsg, challengeID, solverURL)
+	if err != nil {
+		return fmt.Errorf("failed to record dispatch failure: %w", err)
+	}
+	return nil
+}
+
+// OpenSubmissionWindow starts the acceptance window of a challenge and returns it.
+// If the window already exists it is returned unchanged, so the first close time wins.
+func (p *PostgresChallengerDB) OpenSubmissionWindow(ctx context.Context, challengeID string, closesAt time.Time) (*models.SubmissionWindow, error) {
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT INTO submission_windows (challenge_id, closes_at) VALUES ($1, $2)
+		ON CONFLICT (challenge_id) DO NOTHING`,
+		challengeID, closesAt.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to open submission window: %w", err)
+	}
+
+	window, err := p.GetSubmissionWindow(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, fmt.Errorf("submission window for %s disappeared after insert", challengeID)
+	}
+	return window, nil
+}
+
+// GetSubmissionWindow returns the acceptance window of a challenge, or nil if none was opened.
+func (p *PostgresChallengerDB) GetSubmissionWindow(ctx context.Context, challengeID string) (*models.SubmissionWindow, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT challenge_id, closes_at, best_request_id, best_score, settled_at
+		FROM submission_windows WHERE challenge_id = $1`, challengeID)
+
+	window, err := scanSubmissionWindow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission window: %w", err)
+	}
+	return window, nil
+}
+
+// RecordSubmission makes requestID the best result of an unsettled window if its score
+// beats the current best. Ties keep the earlier result, which also makes re-recording
+// the same request a no-op. Returns true if requestID became the best result.
+func (p *PostgresChallengerDB) RecordSubmission(ctx context.Context, challengeID, requestID string, score uint64) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE submission_windows SET best_request_id = $1, best_score = $2
+		WHERE challenge_id = $3 AND settled_at IS NULL
+			AND (best_request_id IS NULL OR best_score < $2)`,
+		requestID, int64(score), challengeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record submission: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ListClosedSubmissionWindows returns unsettled windows whose close time is at or before now.
+func (p *PostgresChallengerDB) ListClosedSubmissionWindows(ctx context.Context, now time.Time) ([]*models.SubmissionWindow, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT challenge_id, closes_at, best_request_id, best_score, settled_at
+		FROM submission_windows WHERE settled_at IS NULL AND closes_at <= $1
+		ORDER BY closes_at ASC`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submission windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []*models.SubmissionWindow{}
+	for rows.Next() {
+		window, err := scanSubmissionWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating submission windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// SettleSubmissionWindow marks a window settled. It returns false if the window was already
+// settled, so concurrent settlers commit and pay the winner at most once.
+func (p *PostgresChallengerDB) SettleSubmissionWindow(ctx context.Context, challengeID string, settledAt time.Time) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE submission_windows SET settled_at = $1 WHERE challenge_id = $2 AND settled_at IS NULL`,
+		settledAt.UTC(), challengeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to settle submission window: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (p *PostgresChallengerDB) HasSeenNonce(ctx context.Context, nonce string) (bool, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM seen_nonces WHERE nonce = $1", nonce).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check nonce: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (p *PostgresChallengerDB) SaveNonce(ctx context.Context, nonce string) error {
+	_, err := p.db.ExecContext(ctx, "INSERT INTO seen_nonces (nonce, seen_at) VALUES ($1, $2) ON CONFLICT (nonce) DO NOTHING",
+		nonce, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save nonce: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresChallengerDB) CleanupOldNonces(ctx context.Context, olderThan time.Time) error {
+	_, err := p.db.ExecContext(ctx, "DELETE FROM seen_nonces WHERE seen_at < $1", olderThan)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup old nonces: %w", err)
+	}
+	return nil
+}
+
+// Truncate deletes every row from the challenger tables. Intended for test isolation.
+func (p *PostgresChallengerDB) Truncate(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, `TRUNCATE commitments, results, dispatched_jobs, webhooks, log_entries, commitment_jobs, pending_log_uploads, pending_dispatches, submission_windows, seen_nonces, hmac_keys, challenges`)
+	if err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresChallengerDB) Close() error {
+	return p.db.Close()
+}
@@ -1,9 +1,13 @@
 package db
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -63,13 +67,13 @@ func TestChallengerDB_CreateChallenge(t *testing.T) {
 
 	challenge := createTestChallenge()
 
-	err := db.CreateChallenge(challenge)
+	err := db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge: %v", err)
 	}
 
 	// Verify it was created by trying to get it
-	retrieved, err := db.GetChallenge(challenge.ID)
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve challenge: %v", err)
 	}
@@ -90,19 +94,19 @@ func TestChallengerDB_GetChallenge(t *testing.T) {
 	challenge := createTestChallenge()
 
 	// Test getting non-existent challenge
-	_, err := db.GetChallenge("non_existent")
+	_, err := db.GetChallenge(context.Background(), "non_existent")
 	if err == nil {
 		t.Error("Expected error when getting non-existent challenge")
 	}
 
 	// Create challenge
-	err = db.CreateChallenge(challenge)
+	err = db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge: %v", err)
 	}
 
 	// Test getting existing challenge
-	retrieved, err := db.GetChallenge(challenge.ID)
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve challenge: %v", err)
 	}
@@ -140,15 +144,15 @@ func TestChallengerDB_CreateDuplicateChallenge(t *testing.T) {
 	challenge := createTestChallenge()
 
 	// Create first time - should succeed
-	err := db.CreateChallenge(challenge)
+	err := db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge first time: %v", err)
 	}
 
 	// Try to create again with same ID - should fail
-	err = db.CreateChallenge(challenge)
-	if err == nil {
-		t.Error("Expected error when creating duplicate challenge")
+	err = db.CreateChallenge(context.Background(), challenge)
+	if !errors.Is(err, ErrDuplicateChallenge) {
+		t.Errorf("Expected ErrDuplicateChallenge when creating duplicate challenge, got %v", err)
 	}
 }
 
@@ -158,7 +162,7 @@ func TestChallengerDB_SaveResult(t *testing.T) {
 
 	// Create challenge first
 	challenge := createTestChallenge()
-	err := db.CreateChallenge(challenge)
+	err := db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge: %v", err)
 	}
@@ -175,7 +179,7 @@ func TestChallengerDB_SaveResult(t *testing.T) {
 		CreatedAt:      time.Now(),
 	}
 
-	err = db.SaveResult(result)
+	err = db.SaveResult(context.Background(), result)
 	if err != nil {
 		t.Fatalf("Failed to save result: %v", err)
 	}
@@ -187,13 +191,13 @@ func TestChallengerDB_GetResult(t *testing.T) {
 
 	// Create challenge first
 	challenge := createTestChallenge()
-	err := db.CreateChallenge(challenge)
+	err := db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge: %v", err)
 	}
 
 	// Test getting non-existent result
-	result, err := db.GetResult("non_existent", "req_123")
+	result, err := db.GetResult(context.Background(), "non_existent", "req_123")
 	if err != nil {
 		t.Fatalf("Unexpected error when getting non-existent result: %v", err)
 	}
@@ -214,13 +218,13 @@ func TestChallengerDB_GetResult(t *testing.T) {
 		CreatedAt:      time.Now(),
 	}
 
-	err = db.SaveResult(testResult)
+	err = db.SaveResult(context.Background(), testResult)
 	if err != nil {
 		t.Fatalf("Failed to save result: %v", err)
 	}
 
 	// Get result
-	retrieved, err := db.GetResult(challenge.ID, "req_123")
+	retrieved, err := db.GetResult(context.Background(), challenge.ID, "req_123")
 	if err != nil {
 		t.Fatalf("Failed to get result: %v", err)
 	}
@@ -247,6 +251,704 @@ func TestChallengerDB_GetResult(t *testing.T) {
 	}
 }
 
+func TestChallengerDB_ListResultsByChallenge(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+
+	challenge := createTestChallenge()
+	if err := db.CreateChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+
+	// No results yet
+	results, err := db.ListResultsByChallenge(context.Background(), challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected 0 results, got %d", len(results))
+	}
+
+	base := time.Now()
+	for i, requestID := range []string{"req_1", "req_2", "req_3"} {
+		result := &models.Result{
+			ChallengeID:    challenge.ID,
+			RequestID:      requestID,
+			SolverJobID:    "job_" + requestID,
+			Status:         "success",
+			ReceivedAnswer: "answer",
+			CreatedAt:      base.Add(time.Duration(i) * time.Second),
+		}
+		if err := db.SaveResult(context.Background(), result); err != nil {
+			t.Fatalf("Failed to save result: %v", err)
+		}
+	}
+
+	results, err = db.ListResultsByChallenge(context.Background(), challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	if results[0].RequestID != "req_1" || results[2].RequestID != "req_3" {
+		t.Errorf("Expected results ordered by created_at, got %s..%s", results[0].RequestID, results[2].RequestID)
+	}
+
+	// Other challenges are not included
+	other, err := db.ListResultsByChallenge(context.Background(), "other_challenge")
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("Expected 0 results for other challenge, got %d", len(other))
+	}
+}
+
+func TestChallengerDB_ListResults(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// 25 results across two challenges: even indices succeed, odd ones fail.
+	// Results 0..19 belong to ch_a, 20..24 to ch_b.
+	base := time.Now()
+	for i := 0; i < 25; i++ {
+		challengeID := "ch_a"
+		if i >= 20 {
+			challengeID = "ch_b"
+		}
+		status := "success"
+		if i%2 == 1 {
+			status = "failed"
+		}
+		result := &models.Result{
+			ChallengeID: challengeID,
+			RequestID:   fmt.Sprintf("req_%02d", i),
+			SolverJobID: fmt.Sprintf("job_%02d", i),
+			Status:      status,
+			CreatedAt:   base.Add(time.Duration(i) * time.Second),
+		}
+		if err := db.SaveResult(context.Background(), result); err != nil {
+			t.Fatalf("Failed to save result %d: %v", i, err)
+		}
+	}
+
+	// First page is newest first
+	page, total, err := db.ListResults(ctx, "", "", 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if total != 25 {
+		t.Errorf("Expected total 25, got %d", total)
+	}
+	if len(page) != 10 || page[0].RequestID != "req_24" || page[9].RequestID != "req_15" {
+		t.Errorf("Unexpected first page: len=%d first=%s last=%s", len(page), page[0].RequestID, page[len(page)-1].RequestID)
+	}
+
+	// Last page is partial
+	page, total, err = db.ListResults(ctx, "", "", 10, 20)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if total != 25 || len(page) != 5 || page[0].RequestID != "req_04" || page[4].RequestID != "req_00" {
+		t.Errorf("Unexpected last page: total=%d len=%d", total, len(page))
+	}
+
+	// Offset past the end returns an empty page with the full total
+	page, total, err = db.ListResults(ctx, "", "", 10, 30)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if total != 25 || len(page) != 0 {
+		t.Errorf("Expected empty page with total 25, got len=%d total=%d", len(page), total)
+	}
+
+	// Challenge filter
+	page, total, err = db.ListResults(ctx, "ch_b", "", 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if total != 5 || len(page) != 5 {
+		t.Errorf("Expected 5 results for ch_b, got len=%d total=%d", len(page), total)
+	}
+	for _, r := range page {
+		if r.ChallengeID != "ch_b" {
+			t.Errorf("Expected only ch_b results, got %s", r.ChallengeID)
+		}
+	}
+
+	// Challenge and status filters combined
+	page, total, err = db.ListResults(ctx, "ch_a", "failed", 4, 4)
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if total != 10 {
+		t.Errorf("Expected 10 failed results for ch_a, got %d", total)
+	}
+	if len(page) != 4 || page[0].RequestID != "req_11" || page[3].RequestID != "req_05" {
+		t.Errorf("Unexpected filtered page: len=%d", len(page))
+	}
+	for _, r := range page {
+		if r.Status != "failed" {
+			t.Errorf("Expected only failed results, got %s", r.Status)
+		}
+	}
+}
+
+func TestChallengerDB_GetChallengeStats(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Empty database
+	stats, err := db.GetChallengeStats(ctx)
+	if err != nil {
+		t.Fatalf("GetChallengeStats() error: %v", err)
+	}
+	if stats.TotalChallenges != 0 || stats.TotalResults != 0 || stats.CorrectRatio != 0 || len(stats.StatusBreakdown) != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+
+	for _, id := range []string{"ch_1", "ch_2", "ch_3"} {
+		challenge := createTestChallenge()
+		challenge.ID = id
+		if err := db.CreateChallenge(ctx, challenge); err != nil {
+			t.Fatalf("Failed to create challenge %s: %v", id, err)
+		}
+	}
+
+	results := []*models.Result{
+		{ChallengeID: "ch_1", RequestID: "req_1", Status: "success", IsCorrect: true, ComputeTimeMs: 100},
+		{ChallengeID: "ch_1", RequestID: "req_2", Status: "success", IsCorrect: true, ComputeTimeMs: 200},
+		{ChallengeID: "ch_2", RequestID: "req_3", Status: "success", IsCorrect: false, ComputeTimeMs: 300},
+		{ChallengeID: "ch_2", RequestID: "req_4", Status: "failed", IsCorrect: false, ComputeTimeMs: 400},
+	}
+	for _, result := range results {
+		result.CreatedAt = time.Now()
+		if err := db.SaveResult(ctx, result); err != nil {
+			t.Fatalf("Failed to save result %s: %v", result.RequestID, err)
+		}
+	}
+
+	stats, err = db.GetChallengeStats(ctx)
+	if err != nil {
+		t.Fatalf("GetChallengeStats() error: %v", err)
+	}
+	if stats.TotalChallenges != 3 {
+		t.Errorf("Expected 3 challenges, got %d", stats.TotalChallenges)
+	}
+	if stats.SolvedChallenges != 1 {
+		t.Errorf("Expected 1 solved challenge, got %d", stats.SolvedChallenges)
+	}
+	if stats.TotalResults != 4 || stats.CorrectResults != 2 || stats.IncorrectResults != 2 {
+		t.Errorf("Expected 4 results (2 correct, 2 incorrect), got %+v", stats)
+	}
+	if stats.CorrectRatio != 0.5 {
+		t.Errorf("Expected correct ratio 0.5, got %v", stats.CorrectRatio)
+	}
+	if stats.AvgComputeTimeMs != 250 {
+		t.Errorf("Expected average compute time 250, got %v", stats.AvgComputeTimeMs)
+	}
+	if stats.StatusBreakdown["success"] != 3 || stats.StatusBreakdown["failed"] != 1 || len(stats.StatusBreakdown) != 2 {
+		t.Errorf("Unexpected status breakdown: %v", stats.StatusBreakdown)
+	}
+}
+
+func TestChallengerDB_ListContracts(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// 25 contracts on localnet alternating between two types, plus one on another chain
+	base := time.Now()
+	for i := 0; i < 25; i++ {
+		contractType := "sui_move_package"
+		if i%5 == 0 {
+			contractType = "ethereum_contract"
+		}
+		contract := &models.Contract{
+			Name:         fmt.Sprintf("contract_%02d", i),
+			Address:      fmt.Sprintf("0x%02x", i),
+			Network:      "localnet",
+			ChainID:      "localnet",
+			TxHash:       fmt.Sprintf("tx_%02d", i),
+			DeployedAt:   base.Add(time.Duration(i) * time.Second),
+			ContractType: contractType,
+			Metadata:     map[string]interface{}{"index": i},
+		}
+		if err := db.SaveContract(ctx, contract); err != nil {
+			t.Fatalf("Failed to save contract %d: %v", i, err)
+		}
+	}
+	if err := db.SaveContract(ctx, &models.Contract{
+		Name: "contract_devnet", Address: "0xff", Network: "devnet", ChainID: "devnet",
+		TxHash: "tx_devnet", DeployedAt: base, ContractType: "sui_move_package",
+	}); err != nil {
+		t.Fatalf("Failed to save devnet contract: %v", err)
+	}
+
+	page, total, err := db.ListContracts(ctx, "localnet", "", 10, 10)
+	if err != nil {
+		t.Fatalf("Failed to list contracts: %v", err)
+	}
+	if total != 25 {
+		t.Errorf("Expected total 25, got %d", total)
+	}
+	if len(page) != 10 || page[0].Name != "contract_14" || page[9].Name != "contract_05" {
+		t.Errorf("Unexpected middle page: len=%d", len(page))
+	}
+
+	page, total, err = db.ListContracts(ctx, "localnet", "", 10, 20)
+	if err != nil {
+		t.Fatalf("Failed to list contracts: %v", err)
+	}
+	if total != 25 || len(page) != 5 || page[4].Name != "contract_00" {
+		t.Errorf("Unexpected last page: total=%d len=%d", total, len(page))
+	}
+
+	page, total, err = db.ListContracts(ctx, "localnet", "ethereum_contract", 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to list contracts: %v", err)
+	}
+	if total != 5 || len(page) != 5 {
+		t.Errorf("Expected 5 ethereum contracts, got len=%d total=%d", len(page), total)
+	}
+	for _, c := range page {
+		if c.ContractType != "ethereum_contract" {
+			t.Errorf("Expected only ethereum contracts, got %s", c.ContractType)
+		}
+	}
+
+	page, total, err = db.ListContracts(ctx, "devnet", "", 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list contracts: %v", err)
+	}
+	if total != 1 || len(page) != 1 || page[0].Name != "contract_devnet" {
+		t.Errorf("Expected only the devnet contract, got len=%d total=%d", len(page), total)
+	}
+}
+
+func TestChallengerDB_BountyTransfers(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if paid, err := db.HasBountyTransfer(ctx, "0xc1", "0xvault"); err != nil || paid {
+		t.Fatalf("Expected no transfer before recording, got %v, %v", paid, err)
+	}
+
+	transfer := &models.BountyTransfer{CommitmentID: "0xc1", VaultID: "0xvault", Digest: "digest_1", SolverAddress: "0xsolver"}
+	if err := db.RecordBountyTransfer(ctx, transfer); err != nil {
+		t.Fatalf("Failed to record transfer: %v", err)
+	}
+	// Recording again is a no-op
+	if err := db.RecordBountyTransfer(ctx, transfer); err != nil {
+		t.Fatalf("Expected a repeated record to succeed, got %v", err)
+	}
+
+	if paid, err := db.HasBountyTransfer(ctx, "0xc1", "0xvault"); err != nil || !paid {
+		t.Errorf("Expected the transfer to be recorded, got %v, %v", paid, err)
+	}
+	if paid, err := db.HasBountyTransfer(ctx, "0xc1", "0xother_vault"); err != nil || paid {
+		t.Errorf("Expected a different vault not to count as paid, got %v, %v", paid, err)
+	}
+}
+
+func TestChallengerDB_DeleteContract(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, chainID := range []string{"localnet", "devnet"} {
+		if err := db.SaveContract(ctx, &models.Contract{
+			Name: "progress", Address: "0x1", Network: chainID, ChainID: chainID,
+			TxHash: "tx", DeployedAt: time.Now(), ContractType: "sui_deployment_progress",
+		}); err != nil {
+			t.Fatalf("Failed to save %s contract: %v", chainID, err)
+		}
+	}
+
+	if err := db.DeleteContract(ctx, "progress", "localnet"); err != nil {
+		t.Fatalf("Failed to delete contract: %v", err)
+	}
+	if _, err := db.GetContractByName(ctx, "progress", "localnet"); err == nil || err.Error() != "contract not found" {
+		t.Errorf("Expected deleted contract to be gone, got %v", err)
+	}
+	if _, err := db.GetContractByName(ctx, "progress", "devnet"); err != nil {
+		t.Errorf("Expected devnet contract to remain, got %v", err)
+	}
+
+	// Deleting again is a no-op
+	if err := db.DeleteContract(ctx, "progress", "localnet"); err != nil {
+		t.Errorf("Expected deleting a missing contract to succeed, got %v", err)
+	}
+}
+
+func TestChallengerDB_HMACKeys(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	notAfter := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := db.SaveHMACKey(ctx, &models.HMACKey{KeyID: "chal-kid-2", Secret: "new", NotAfter: &notAfter}); err != nil {
+		t.Fatalf("Failed to save key: %v", err)
+	}
+	if err := db.SaveHMACKey(ctx, &models.HMACKey{KeyID: "chal-kid-1", Secret: "old"}); err != nil {
+		t.Fatalf("Failed to save key: %v", err)
+	}
+
+	// Revoking replaces the row
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	if err := db.SaveHMACKey(ctx, &models.HMACKey{KeyID: "chal-kid-1", RevokedAt: &revokedAt}); err != nil {
+		t.Fatalf("Failed to revoke key: %v", err)
+	}
+
+	keys, err := db.ListHMACKeys(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list keys: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(keys))
+	}
+	if keys[0].KeyID != "chal-kid-1" || keys[0].Secret != "" || keys[0].RevokedAt == nil || !keys[0].RevokedAt.Equal(revokedAt) {
+		t.Errorf("Expected chal-kid-1 to be revoked without its secret, got %+v", keys[0])
+	}
+	if keys[1].KeyID != "chal-kid-2" || keys[1].Secret != "new" || keys[1].NotBefore != nil ||
+		keys[1].NotAfter == nil || !keys[1].NotAfter.Equal(notAfter) || keys[1].RevokedAt != nil {
+		t.Errorf("Unexpected chal-kid-2 record: %+v", keys[1])
+	}
+}
+
+func TestChallengerDB_CanceledContext(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := db.CreateChallenge(ctx, createTestChallenge()); !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateChallenge() expected context.Canceled, got %v", err)
+	}
+	if _, err := db.GetChallenge(ctx, "test_challenge_123"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetChallenge() expected context.Canceled, got %v", err)
+	}
+	if _, err := db.HasSeenNonce(ctx, "nonce"); !errors.Is(err, context.Canceled) {
+		t.Errorf("HasSeenNonce() expected context.Canceled, got %v", err)
+	}
+}
+
+func TestChallengerDB_ContextCanceledMidQuery(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+
+	// Seed enough rows that listing them takes well over the deadline
+	tx, err := db.db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO results (challenge_id, request_id, solver_job_id, status,
+			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		t.Fatalf("Failed to prepare insert: %v", err)
+	}
+	now := time.Now()
+	for i := 0; i < 50000; i++ {
+		if _, err := stmt.Exec("ch_slow", fmt.Sprintf("req_%d", i), "job", "success", "answer", true, "", 10, "", now); err != nil {
+			t.Fatalf("Failed to insert result %d: %v", i, err)
+		}
+	}
+	stmt.Close()
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = db.ListResults(ctx, "ch_slow", "", 0, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ListResults() expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ListResults() took %v after cancellation", elapsed)
+	}
+}
+
+func TestChallengerDB_DispatchedJobs(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := db.SaveDispatchedJob(ctx, "ch_1", "job_1"); err != nil {
+		t.Fatalf("Failed to save dispatched job: %v", err)
+	}
+	// Recording the same job twice is a no-op
+	if err := db.SaveDispatchedJob(ctx, "ch_1", "job_1"); err != nil {
+		t.Fatalf("Failed to re-save dispatched job: %v", err)
+	}
+
+	tests := []struct {
+		challengeID string
+		jobID       string
+		want        bool
+	}{
+		{"ch_1", "job_1", true},
+		{"ch_1", "job_2", false},
+		{"ch_2", "job_1", false},
+	}
+	for _, tt := range tests {
+		got, err := db.HasDispatchedJob(ctx, tt.challengeID, tt.jobID)
+		if err != nil {
+			t.Fatalf("HasDispatchedJob(%s, %s) error: %v", tt.challengeID, tt.jobID, err)
+		}
+		if got != tt.want {
+			t.Errorf("HasDispatchedJob(%s, %s) = %v, want %v", tt.challengeID, tt.jobID, got, tt.want)
+		}
+	}
+
+	if err := db.DeleteDispatchedJob(ctx, "ch_1", "job_1"); err != nil {
+		t.Fatalf("Failed to delete dispatched job: %v", err)
+	}
+	if got, err := db.HasDispatchedJob(ctx, "ch_1", "job_1"); err != nil || got {
+		t.Errorf("Expected job_1 to be forgotten, got %v (err %v)", got, err)
+	}
+}
+
+func TestChallengerDB_ChallengeExpiry(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := createTestChallenge()
+	if err := db.CreateChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	deadline := now.Add(time.Minute)
+	if err := db.SetChallengeExpiry(ctx, challenge.ID, deadline); err != nil {
+		t.Fatalf("SetChallengeExpiry() error: %v", err)
+	}
+	// An earlier deadline never shortens the expiry
+	if err := db.SetChallengeExpiry(ctx, challenge.ID, now.Add(-time.Hour)); err != nil {
+		t.Fatalf("SetChallengeExpiry() error: %v", err)
+	}
+
+	stored, err := db.GetChallenge(ctx, challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to get challenge: %v", err)
+	}
+	if stored.ExpiresAt == nil || !stored.ExpiresAt.Equal(deadline) {
+		t.Fatalf("Expected expiry %v, got %v", deadline, stored.ExpiresAt)
+	}
+
+	if expired, err := db.ListExpiredChallenges(ctx, now); err != nil || len(expired) != 0 {
+		t.Fatalf("Expected nothing expired before the deadline, got %d, %v", len(expired), err)
+	}
+	expired, err := db.ListExpiredChallenges(ctx, deadline)
+	if err != nil || len(expired) != 1 || expired[0].ID != challenge.ID {
+		t.Fatalf("Expected the challenge to be listed at its deadline, got %v, %v", expired, err)
+	}
+
+	// Only the first claim wins
+	for i, want := range []bool{true, false} {
+		claimed, err := db.MarkChallengeExpired(ctx, challenge.ID, deadline)
+		if err != nil {
+			t.Fatalf("MarkChallengeExpired() error: %v", err)
+		}
+		if claimed != want {
+			t.Errorf("MarkChallengeExpired() call %d = %v, want %v", i+1, claimed, want)
+		}
+	}
+	if expired, err := db.ListExpiredChallenges(ctx, deadline); err != nil || len(expired) != 0 {
+		t.Errorf("Expected a marked challenge not to be listed again, got %d, %v", len(expired), err)
+	}
+}
+
+func TestChallengerDB_ListCommitmentsBySolver(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := createTestChallenge()
+	if err := db.CreateChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+
+	const solverA = "0xaaaa"
+	const solverB = "0xbbbb"
+
+	base := time.Now()
+	seed := []struct {
+		requestID string
+		solver    string
+		isCorrect bool
+		commit    bool
+	}{
+		{"req_a1", solverA, true, true},
+		{"req_a2", solverA, false, true},
+		{"req_a3", solverA, true, false}, // result without an on-chain commitment
+		{"req_b1", solverB, true, true},
+	}
+	for i, s := range seed {
+		createdAt := base.Add(time.Duration(i) * time.Second)
+		if err := db.SaveResult(ctx, &models.Result{
+			ChallengeID:   challenge.ID,
+			RequestID:     s.requestID,
+			Status:        "success",
+			IsCorrect:     s.isCorrect,
+			SolverAddress: s.solver,
+			CreatedAt:     createdAt,
+		}); err != nil {
+			t.Fatalf("Failed to save result %s: %v", s.requestID, err)
+		}
+		if !s.commit {
+			continue
+		}
+		if err := db.SaveCommitment(ctx, &models.Commitment{
+			ChallengeID:    challenge.ID,
+			RequestID:      s.requestID,
+			ObjectID:       "0xobj_" + s.requestID,
+			CommitmentHash: "hash_" + s.requestID,
+			CreatedAt:      createdAt,
+		}); err != nil {
+			t.Fatalf("Failed to save commitment %s: %v", s.requestID, err)
+		}
+	}
+
+	commitments, total, err := db.ListCommitmentsBySolver(ctx, solverA, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to list commitments: %v", err)
+	}
+	if total != 2 || len(commitments) != 2 {
+		t.Fatalf("Expected 2 commitments for solver A, got len=%d total=%d", len(commitments), total)
+	}
+
+	// Newest first, with verification and settlement status from the join
+	if commitments[0].RequestID != "req_a2" || commitments[0].IsCorrect {
+		t.Errorf("Expected req_a2 (incorrect) first, got %s (is_correct=%v)", commitments[0].RequestID, commitments[0].IsCorrect)
+	}
+	if commitments[1].RequestID != "req_a1" || !commitments[1].IsCorrect {
+		t.Errorf("Expected req_a1 (correct) second, got %s (is_correct=%v)", commitments[1].RequestID, commitments[1].IsCorrect)
+	}
+	for _, c := range commitments {
+		if c.SolverAddress != solverA {
+			t.Errorf("Expected solver address %s, got %s", solverA, c.SolverAddress)
+		}
+		if c.SettlementStatus != "pending" {
+			t.Errorf("Expected settlement status pending, got %s", c.SettlementStatus)
+		}
+		if c.ResultStatus != "success" {
+			t.Errorf("Expected result status success, got %s", c.ResultStatus)
+		}
+		if c.ObjectID != "0xobj_"+c.RequestID {
+			t.Errorf("Unexpected object id %s for %s", c.ObjectID, c.RequestID)
+		}
+	}
+
+	page, total, err := db.ListCommitmentsBySolver(ctx, solverA, 1, 1)
+	if err != nil {
+		t.Fatalf("Failed to list commitments page: %v", err)
+	}
+	if total != 2 || len(page) != 1 || page[0].RequestID != "req_a1" {
+		t.Errorf("Unexpected second page: total=%d len=%d", total, len(page))
+	}
+
+	commitments, total, err = db.ListCommitmentsBySolver(ctx, solverB, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to list commitments: %v", err)
+	}
+	if total != 1 || len(commitments) != 1 || commitments[0].RequestID != "req_b1" {
+		t.Errorf("Expected only req_b1 for solver B, got len=%d total=%d", len(commitments), total)
+	}
+
+	commitments, total, err = db.ListCommitmentsBySolver(ctx, "0xunknown", 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to list commitments: %v", err)
+	}
+	if total != 0 || len(commitments) != 0 {
+		t.Errorf("Expected no commitments for unknown solver, got %d", len(commitments))
+	}
+}
+
+func TestChallengerDB_ReadOnlyDuringWrite(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "challenger.db")
+	primary, err := NewChallengerDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create primary database: %v", err)
+	}
+	defer primary.Close()
+	ctx := context.Background()
+
+	challenge := createTestChallenge()
+	if err := primary.CreateChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+	if err := primary.SaveResult(ctx, &models.Result{
+		ChallengeID: challenge.ID,
+		RequestID:   "req_committed",
+		Status:      "success",
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save result: %v", err)
+	}
+
+	reader, err := NewChallengerReadOnlyDB(dbPath, DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("Failed to open read-only database: %v", err)
+	}
+	defer reader.Close()
+
+	// Hold an open write transaction on the primary
+	tx, err := primary.db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO results (challenge_id, request_id, solver_job_id, status,
+			received_answer, is_correct, solver_address, compute_time_ms, solver_metadata, created_at)
+		VALUES (?, ?, '', 'success', '', 0, '', 0, '', ?)`, challenge.ID, "req_in_flight", time.Now()); err != nil {
+		t.Fatalf("Failed to write in transaction: %v", err)
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	results, total, err := reader.ListResults(readCtx, challenge.ID, "", 0, 0)
+	if err != nil {
+		t.Fatalf("Read during write failed: %v", err)
+	}
+	if total != 1 || len(results) != 1 || results[0].RequestID != "req_committed" {
+		t.Errorf("Expected only the committed result, got len=%d total=%d", len(results), total)
+	}
+
+	if _, err := reader.GetChallenge(readCtx, challenge.ID); err != nil {
+		t.Errorf("GetChallenge via read handle failed: %v", err)
+	}
+
+	// The read handle must refuse writes
+	if err := reader.SaveNonce(ctx, "nonce_ro"); err == nil {
+		t.Error("Expected write through read-only handle to fail")
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	_, total, err = reader.ListResults(ctx, challenge.ID, "", 0, 0)
+	if err != nil {
+		t.Fatalf("Read after commit failed: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected committed write to be visible, got total=%d", total)
+	}
+}
+
 func TestChallengerDB_SaveWebhookAudit(t *testing.T) {
 	db, cleanup := createTestChallengerDB(t)
 	defer cleanup()
@@ -260,12 +962,174 @@ func TestChallengerDB_SaveWebhookAudit(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 
-	err := db.SaveWebhookAudit(audit)
+	err := db.SaveWebhookAudit(context.Background(), audit)
 	if err != nil {
 		t.Fatalf("Failed to save webhook audit: %v", err)
 	}
 }
 
+func TestChallengerDB_ListWebhookAudits(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour)
+	audits := []*models.WebhookAudit{
+		{ChallengeID: "ch_1", RequestID: "req_1", Headers: "X-Request-ID: req_1", BodyHash: "hash_1", StatusCode: 200, CreatedAt: base},
+		{ChallengeID: "ch_2", RequestID: "req_2", BodyHash: "hash_2", StatusCode: 401, CreatedAt: base.Add(time.Minute)},
+		{ChallengeID: "ch_1", RequestID: "req_3", BodyHash: "hash_3", StatusCode: 200, CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, audit := range audits {
+		if err := db.SaveWebhookAudit(ctx, audit); err != nil {
+			t.Fatalf("Failed to save webhook audit: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		challengeID string
+		limit       int
+		want        []string // Request IDs, newest first
+	}{
+		{"All", "", 0, []string{"req_3", "req_2", "req_1"}},
+		{"ByChallenge", "ch_1", 0, []string{"req_3", "req_1"}},
+		{"Limited", "", 2, []string{"req_3", "req_2"}},
+		{"UnknownChallenge", "ch_missing", 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.ListWebhookAudits(ctx, tt.challengeID, tt.limit)
+			if err != nil {
+				t.Fatalf("Failed to list webhook audits: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d audits, got %d", len(tt.want), len(got))
+			}
+			for i, audit := range got {
+				if audit.RequestID != tt.want[i] {
+					t.Errorf("Expected audit %d to be %s, got %s", i, tt.want[i], audit.RequestID)
+				}
+			}
+		})
+	}
+
+	got, err := db.ListWebhookAudits(ctx, "ch_1", 0)
+	if err != nil {
+		t.Fatalf("Failed to list webhook audits: %v", err)
+	}
+	oldest := got[len(got)-1]
+	if oldest.ID == 0 || oldest.Headers != "X-Request-ID: req_1" || oldest.BodyHash != "hash_1" ||
+		oldest.StatusCode != 200 || !oldest.CreatedAt.Equal(base) {
+		t.Errorf("Unexpected audit fields: %+v", oldest)
+	}
+}
+
+func TestChallengerDB_ExportPaging(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := createTestChallenge()
+	if err := db.CreateChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+
+	// req_2 and req_3 share a timestamp, so paging has to break the tie by ID
+	base := time.Now().Add(-time.Hour)
+	offsets := map[string]time.Duration{
+		"req_0": -time.Minute, "req_1": 0, "req_2": time.Minute, "req_3": time.Minute, "req_4": 2 * time.Minute, "req_5": 3 * time.Minute,
+	}
+	for _, requestID := range []string{"req_0", "req_1", "req_2", "req_3", "req_4", "req_5"} {
+		createdAt := base.Add(offsets[requestID])
+		if err := db.SaveResult(ctx, &models.Result{
+			ChallengeID: challenge.ID, RequestID: requestID, Status: "success", CreatedAt: createdAt,
+		}); err != nil {
+			t.Fatalf("Failed to save result: %v", err)
+		}
+		if err := db.SaveWebhookAudit(ctx, &models.WebhookAudit{
+			ChallengeID: challenge.ID, RequestID: requestID, StatusCode: 200, CreatedAt: createdAt,
+		}); err != nil {
+			t.Fatalf("Failed to save webhook audit: %v", err)
+		}
+	}
+
+	// [base, base+3m) in pages of two: req_0 is too early and req_5 too late
+	want := []string{"req_1", "req_2", "req_3", "req_4"}
+	until := base.Add(3 * time.Minute)
+
+	var results []string
+	for cursor := (ExportCursor{CreatedAt: base}); ; {
+		page, err := db.ListResultsAfter(ctx, cursor, until, 2)
+		if err != nil {
+			t.Fatalf("Failed to list results: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, result := range page {
+			results = append(results, result.RequestID)
+		}
+		last := page[len(page)-1]
+		cursor = ExportCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	if fmt.Sprint(results) != fmt.Sprint(want) {
+		t.Errorf("Expected results %v, got %v", want, results)
+	}
+
+	var audits []string
+	for cursor := (ExportCursor{CreatedAt: base}); ; {
+		page, err := db.ListWebhookAuditsAfter(ctx, cursor, until, 2)
+		if err != nil {
+			t.Fatalf("Failed to list webhook audits: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, audit := range page {
+			audits = append(audits, audit.RequestID)
+		}
+		last := page[len(page)-1]
+		cursor = ExportCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	if fmt.Sprint(audits) != fmt.Sprint(want) {
+		t.Errorf("Expected webhook audits %v, got %v", want, audits)
+	}
+}
+
+func TestChallengerDB_CleanupOldWebhookAudits(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now()
+	for requestID, createdAt := range map[string]time.Time{
+		"req_old":    now.Add(-48 * time.Hour),
+		"req_recent": now.Add(-time.Hour),
+	} {
+		if err := db.SaveWebhookAudit(ctx, &models.WebhookAudit{
+			ChallengeID: "ch_1",
+			RequestID:   requestID,
+			StatusCode:  200,
+			CreatedAt:   createdAt,
+		}); err != nil {
+			t.Fatalf("Failed to save webhook audit: %v", err)
+		}
+	}
+
+	if err := db.CleanupOldWebhookAudits(ctx, now.Add(-24*time.Hour)); err != nil {
+		t.Fatalf("Failed to cleanup old webhook audits: %v", err)
+	}
+
+	audits, err := db.ListWebhookAudits(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("Failed to list webhook audits: %v", err)
+	}
+	if len(audits) != 1 || audits[0].RequestID != "req_recent" {
+		t.Errorf("Expected only req_recent to remain, got %+v", audits)
+	}
+}
+
 func TestChallengerDB_NonceOperations(t *testing.T) {
 	db, cleanup := createTestChallengerDB(t)
 	defer cleanup()
@@ -273,7 +1137,7 @@ func TestChallengerDB_NonceOperations(t *testing.T) {
 	nonce := "test_nonce_123"
 
 	// Check non-existent nonce
-	seen, err := db.HasSeenNonce(nonce)
+	seen, err := db.HasSeenNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to check nonce: %v", err)
 	}
@@ -282,13 +1146,13 @@ func TestChallengerDB_NonceOperations(t *testing.T) {
 	}
 
 	// Save nonce
-	err = db.SaveNonce(nonce)
+	err = db.SaveNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to save nonce: %v", err)
 	}
 
 	// Check nonce again - should be seen now
-	seen, err = db.HasSeenNonce(nonce)
+	seen, err = db.HasSeenNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to check nonce: %v", err)
 	}
@@ -297,7 +1161,7 @@ func TestChallengerDB_NonceOperations(t *testing.T) {
 	}
 
 	// Try to save the same nonce again - should not fail (multiple saves allowed)
-	err = db.SaveNonce(nonce)
+	err = db.SaveNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to save nonce again: %v", err)
 	}
@@ -311,19 +1175,19 @@ func TestChallengerDB_CleanupOldNonces(t *testing.T) {
 	nonce1 := "old_nonce"
 	nonce2 := "new_nonce"
 
-	err := db.SaveNonce(nonce1)
+	err := db.SaveNonce(context.Background(), nonce1)
 	if err != nil {
 		t.Fatalf("Failed to save nonce1: %v", err)
 	}
 
-	err = db.SaveNonce(nonce2)
+	err = db.SaveNonce(context.Background(), nonce2)
 	if err != nil {
 		t.Fatalf("Failed to save nonce2: %v", err)
 	}
 
 	// Verify both exist
-	seen1, _ := db.HasSeenNonce(nonce1)
-	seen2, _ := db.HasSeenNonce(nonce2)
+	seen1, _ := db.HasSeenNonce(context.Background(), nonce1)
+	seen2, _ := db.HasSeenNonce(context.Background(), nonce2)
 
 	if !seen1 || !seen2 {
 		t.Fatal("Expected both nonces to exist")
@@ -331,20 +1195,65 @@ func TestChallengerDB_CleanupOldNonces(t *testing.T) {
 
 	// Clean up nonces older than future time (should clean all)
 	futureTime := time.Now().Add(1 * time.Hour)
-	err = db.CleanupOldNonces(futureTime)
+	err = db.CleanupOldNonces(context.Background(), futureTime)
 	if err != nil {
 		t.Fatalf("Failed to cleanup old nonces: %v", err)
 	}
 
 	// Verify they're gone
-	seen1, _ = db.HasSeenNonce(nonce1)
-	seen2, _ = db.HasSeenNonce(nonce2)
+	seen1, _ = db.HasSeenNonce(context.Background(), nonce1)
+	seen2, _ = db.HasSeenNonce(context.Background(), nonce2)
 
 	if seen1 || seen2 {
 		t.Error("Expected nonces to be cleaned up")
 	}
 }
 
+func TestChallengerDB_ConcurrentSaveNonce(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_challenger.db")
+
+	// The default store serializes its writes on one connection; the second handle has an
+	// unbounded pool and relies on the busy timeout alone, like a second process would.
+	primary, err := NewChallengerDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer primary.Close()
+	unbounded, err := NewChallengerDBWithOptions(dbPath, SQLiteOptions{BusyTimeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to open second handle: %v", err)
+	}
+	defer unbounded.Close()
+
+	const goroutines, perGoroutine = 32, 25
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		store := primary
+		if i%2 == 1 {
+			store = unbounded
+		}
+		wg.Add(1)
+		go func(i int, store *ChallengerDB) {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				if err := store.SaveNonce(context.Background(), fmt.Sprintf("nonce_%d_%d", i, j)); err != nil {
+					t.Errorf("Failed to save nonce: %v", err)
+					return
+				}
+			}
+		}(i, store)
+	}
+	wg.Wait()
+
+	var count int
+	if err := primary.db.QueryRow("SELECT COUNT(*) FROM seen_nonces").Scan(&count); err != nil {
+		t.Fatalf("Failed to count nonces: %v", err)
+	}
+	if count != goroutines*perGoroutine {
+		t.Errorf("Expected %d nonces, got %d", goroutines*perGoroutine, count)
+	}
+}
+
 func TestChallengerDB_Close(t *testing.T) {
 	db, cleanup := createTestChallengerDB(t)
 	defer cleanup()
@@ -355,7 +1264,7 @@ func TestChallengerDB_Close(t *testing.T) {
 	}
 
 	// Trying to use closed database should fail
-	err = db.SaveNonce("test")
+	err = db.SaveNonce(context.Background(), "test")
 	if err == nil {
 		t.Error("Expected error when using closed database")
 	}
@@ -367,7 +1276,7 @@ func TestChallengerDB_ResultUniqueness(t *testing.T) {
 
 	// Create challenge first
 	challenge := createTestChallenge()
-	err := db.CreateChallenge(challenge)
+	err := db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge: %v", err)
 	}
@@ -384,13 +1293,13 @@ func TestChallengerDB_ResultUniqueness(t *testing.T) {
 	}
 
 	// Save first time - should succeed
-	err = db.SaveResult(result)
+	err = db.SaveResult(context.Background(), result)
 	if err != nil {
 		t.Fatalf("Failed to save result first time: %v", err)
 	}
 
 	// Try to save again with same challenge_id and request_id - should fail due to UNIQUE constraint
-	err = db.SaveResult(result)
+	err = db.SaveResult(context.Background(), result)
 	if err == nil {
 		t.Error("Expected error when saving duplicate result")
 	}
@@ -402,7 +1311,7 @@ func TestChallengerDB_SaveResultWithDuplicateCheck(t *testing.T) {
 
 	// Create challenge first
 	challenge := createTestChallenge()
-	err := db.CreateChallenge(challenge)
+	err := db.CreateChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to create challenge: %v", err)
 	}
@@ -419,7 +1328,7 @@ func TestChallengerDB_SaveResultWithDuplicateCheck(t *testing.T) {
 	}
 
 	// Save first time - should succeed and return true (was inserted)
-	wasInserted, err := db.SaveResultWithDuplicateCheck(result)
+	wasInserted, err := db.SaveResultWithDuplicateCheck(context.Background(), result)
 	if err != nil {
 		t.Fatalf("Failed to save result first time: %v", err)
 	}
@@ -428,7 +1337,7 @@ func TestChallengerDB_SaveResultWithDuplicateCheck(t *testing.T) {
 	}
 
 	// Try to save again with same challenge_id and request_id - should succeed but return false (was duplicate)
-	wasInserted, err = db.SaveResultWithDuplicateCheck(result)
+	wasInserted, err = db.SaveResultWithDuplicateCheck(context.Background(), result)
 	if err != nil {
 		t.Fatalf("Failed to save duplicate result: %v", err)
 	}
@@ -437,7 +1346,7 @@ func TestChallengerDB_SaveResultWithDuplicateCheck(t *testing.T) {
 	}
 
 	// Verify the result still exists and can be retrieved
-	retrieved, err := db.GetResult(challenge.ID, "req_123")
+	retrieved, err := db.GetResult(context.Background(), challenge.ID, "req_123")
 	if err != nil {
 		t.Fatalf("Failed to get result: %v", err)
 	}
@@ -451,3 +1360,428 @@ func TestChallengerDB_SaveResultWithDuplicateCheck(t *testing.T) {
 		t.Error("Result data mismatch - possible duplicate was created")
 	}
 }
+
+func TestChallengerDB_SaveLogEntry(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	entry := &models.LogEntry{
+		ID:             "ch_1:req_1",
+		Log:            `{"request_id":"req_1"}`,
+		ChallengerAddr: "0xchallenger",
+		SolverAddr:     "0xsolver",
+		CreatedAt:      time.Now(),
+	}
+	if err := db.SaveLogEntry(ctx, entry); err != nil {
+		t.Fatalf("Failed to save log entry: %v", err)
+	}
+
+	// Saving the same ID again keeps the original entry
+	if err := db.SaveLogEntry(ctx, &models.LogEntry{ID: entry.ID, Log: "{}", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save duplicate log entry: %v", err)
+	}
+
+	got, err := db.GetLogEntry(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("Failed to get log entry: %v", err)
+	}
+	if got == nil {
+		t.Fatal("Expected log entry, got nil")
+	}
+	if got.Log != entry.Log || got.ChallengerAddr != entry.ChallengerAddr || got.SolverAddr != entry.SolverAddr || got.VerifierAddr != "" {
+		t.Errorf("Unexpected log entry: %+v", got)
+	}
+
+	missing, err := db.GetLogEntry(ctx, "ch_missing:req")
+	if err != nil {
+		t.Fatalf("Failed to get missing log entry: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for missing log entry, got %+v", missing)
+	}
+}
+
+func TestChallengerDB_SubmissionWindow(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	closesAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	window, err := db.OpenSubmissionWindow(ctx, "ch_window", closesAt)
+	if err != nil {
+		t.Fatalf("Failed to open submission window: %v", err)
+	}
+	if !window.ClosesAt.Equal(closesAt) || window.BestRequestID != "" || window.SettledAt != nil {
+		t.Errorf("Unexpected new window: %+v", window)
+	}
+
+	// Re-opening keeps the original close time
+	reopened, err := db.OpenSubmissionWindow(ctx, "ch_window", closesAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to reopen submission window: %v", err)
+	}
+	if !reopened.ClosesAt.Equal(closesAt) {
+		t.Errorf("Expected close time %v to be kept, got %v", closesAt, reopened.ClosesAt)
+	}
+
+	submissions := []struct {
+		requestID string
+		score     uint64
+		wantBest  bool
+	}{
+		{"req_1", 50, true},
+		{"req_2", 40, false}, // Worse answer
+		{"req_3", 80, true},  // Better answer replaces the best
+		{"req_4", 80, false}, // Ties keep the earlier answer
+		{"req_3", 80, false}, // Replaying the best answer is a no-op
+	}
+	for _, sub := range submissions {
+		isBest, err := db.RecordSubmission(ctx, "ch_window", sub.requestID, sub.score)
+		if err != nil {
+			t.Fatalf("Failed to record submission %s: %v", sub.requestID, err)
+		}
+		if isBest != sub.wantBest {
+			t.Errorf("RecordSubmission(%s, %d) = %v, want %v", sub.requestID, sub.score, isBest, sub.wantBest)
+		}
+	}
+
+	window, err = db.GetSubmissionWindow(ctx, "ch_window")
+	if err != nil {
+		t.Fatalf("Failed to get submission window: %v", err)
+	}
+	if window.BestRequestID != "req_3" || window.BestScore != 80 {
+		t.Errorf("Expected best req_3 with score 80, got %s with %d", window.BestRequestID, window.BestScore)
+	}
+
+	// Not listed until the window has closed
+	closed, err := db.ListClosedSubmissionWindows(ctx, closesAt.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("Failed to list closed windows: %v", err)
+	}
+	if len(closed) != 0 {
+		t.Errorf("Expected no closed windows before close time, got %d", len(closed))
+	}
+
+	closed, err = db.ListClosedSubmissionWindows(ctx, closesAt)
+	if err != nil {
+		t.Fatalf("Failed to list closed windows: %v", err)
+	}
+	if len(closed) != 1 || closed[0].ChallengeID != "ch_window" {
+		t.Fatalf("Expected ch_window to be closed, got %+v", closed)
+	}
+
+	// Only the first settle claims the window
+	for i, want := range []bool{true, false} {
+		claimed, err := db.SettleSubmissionWindow(ctx, "ch_window", closesAt)
+		if err != nil {
+			t.Fatalf("Failed to settle submission window: %v", err)
+		}
+		if claimed != want {
+			t.Errorf("Settle attempt %d: expected claimed=%v, got %v", i+1, want, claimed)
+		}
+	}
+
+	// Settled windows are frozen and no longer listed
+	if isBest, err := db.RecordSubmission(ctx, "ch_window", "req_5", 100); err != nil || isBest {
+		t.Errorf("Expected submission after settlement to be ignored, got %v (err %v)", isBest, err)
+	}
+	closed, err = db.ListClosedSubmissionWindows(ctx, closesAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to list closed windows: %v", err)
+	}
+	if len(closed) != 0 {
+		t.Errorf("Expected settled window not to be listed, got %d", len(closed))
+	}
+
+	missing, err := db.GetSubmissionWindow(ctx, "ch_missing")
+	if err != nil {
+		t.Fatalf("Failed to get missing submission window: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for missing submission window, got %+v", missing)
+	}
+}
+
+func TestChallengerDB_CommitmentJobs(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := db.EnqueueCommitmentJob(ctx, "ch_1", "req_1"); err != nil {
+		t.Fatalf("Failed to enqueue commitment job: %v", err)
+	}
+	if err := db.EnqueueCommitmentJob(ctx, "ch_1", "req_2"); err != nil {
+		t.Fatalf("Failed to enqueue commitment job: %v", err)
+	}
+	// Re-queuing the same result is a no-op
+	if err := db.EnqueueCommitmentJob(ctx, "ch_1", "req_1"); err != nil {
+		t.Fatalf("Failed to re-enqueue commitment job: %v", err)
+	}
+
+	jobs, err := db.ListCommitmentJobs(ctx, 2)
+	if err != nil {
+		t.Fatalf("Failed to list commitment jobs: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].RequestID != "req_1" || jobs[1].RequestID != "req_2" {
+		t.Fatalf("Expected req_1 and req_2 oldest first, got %+v", jobs)
+	}
+
+	// Failures are counted until the job reaches maxAttempts
+	for i := 0; i < 2; i++ {
+		if err := db.FailCommitmentJob(ctx, "ch_1", "req_1", "rpc unavailable"); err != nil {
+			t.Fatalf("Failed to record commitment job failure: %v", err)
+		}
+	}
+	jobs, err = db.ListCommitmentJobs(ctx, 3)
+	if err != nil {
+		t.Fatalf("Failed to list commitment jobs: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].Attempts != 2 || jobs[0].LastError != "rpc unavailable" {
+		t.Fatalf("Expected req_1 with 2 failed attempts, got %+v", jobs)
+	}
+
+	jobs, err = db.ListCommitmentJobs(ctx, 2)
+	if err != nil {
+		t.Fatalf("Failed to list commitment jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].RequestID != "req_2" {
+		t.Fatalf("Expected only req_2 below the attempt limit, got %+v", jobs)
+	}
+
+	if err := db.CompleteCommitmentJob(ctx, "ch_1", "req_2"); err != nil {
+		t.Fatalf("Failed to complete commitment job: %v", err)
+	}
+	jobs, err = db.ListCommitmentJobs(ctx, 2)
+	if err != nil {
+		t.Fatalf("Failed to list commitment jobs: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("Expected no pending jobs after completion, got %+v", jobs)
+	}
+}
+
+func TestChallengerDB_LogUploads(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	first := &models.LogEntry{ID: "ch_1:req_1", Log: `{"answer":"a"}`, SolverAddr: "0xsolver"}
+	if err := db.EnqueueLogUpload(ctx, first, "status 503"); err != nil {
+		t.Fatalf("Failed to enqueue log upload: %v", err)
+	}
+	if err := db.EnqueueLogUpload(ctx, &models.LogEntry{ID: "ch_1:req_2", Log: `{}`}, "timeout"); err != nil {
+		t.Fatalf("Failed to enqueue log upload: %v", err)
+	}
+
+	uploads, err := db.ListLogUploads(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list log uploads: %v", err)
+	}
+	if len(uploads) != 2 || uploads[0].Entry.ID != "ch_1:req_1" || uploads[1].Entry.ID != "ch_1:req_2" {
+		t.Fatalf("Expected both uploads oldest first, got %+v", uploads)
+	}
+	if uploads[0].Entry.Log != first.Log || uploads[0].Entry.SolverAddr != "0xsolver" || uploads[0].LastError != "status 503" {
+		t.Errorf("Expected the queued payload to round-trip, got %+v", uploads[0])
+	}
+
+	if err := db.FailLogUpload(ctx, "ch_1:req_1", "connection refused"); err != nil {
+		t.Fatalf("Failed to record log upload failure: %v", err)
+	}
+	// Re-queuing keeps the attempt count
+	if err := db.EnqueueLogUpload(ctx, first, "status 502"); err != nil {
+		t.Fatalf("Failed to re-enqueue log upload: %v", err)
+	}
+	uploads, err = db.ListLogUploads(ctx, 1)
+	if err != nil {
+		t.Fatalf("Failed to list log uploads: %v", err)
+	}
+	if len(uploads) != 1 || uploads[0].Attempts != 1 || uploads[0].LastError != "status 502" {
+		t.Fatalf("Expected req_1 with one failed attempt, got %+v", uploads)
+	}
+
+	if err := db.CompleteLogUpload(ctx, "ch_1:req_1"); err != nil {
+		t.Fatalf("Failed to complete log upload: %v", err)
+	}
+	uploads, err = db.ListLogUploads(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list log uploads: %v", err)
+	}
+	if len(uploads) != 1 || uploads[0].Entry.ID != "ch_1:req_2" {
+		t.Errorf("Expected only req_2 to remain, got %+v", uploads)
+	}
+}
+
+func TestChallengerDB_Dispatches(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	first := &models.PendingDispatch{ChallengeID: "ch_1", SolverURL: "http://solver-a", Priority: 3, LastError: "status 503"}
+	if err := db.EnqueueDispatch(ctx, first); err != nil {
+		t.Fatalf("Failed to enqueue dispatch: %v", err)
+	}
+	if err := db.EnqueueDispatch(ctx, &models.PendingDispatch{ChallengeID: "ch_2", SolverURL: "http://solver-a", LastError: "timeout"}); err != nil {
+		t.Fatalf("Failed to enqueue dispatch: %v", err)
+	}
+
+	dispatches, err := db.ListDispatches(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list dispatches: %v", err)
+	}
+	if len(dispatches) != 2 || dispatches[0].ChallengeID != "ch_1" || dispatches[1].ChallengeID != "ch_2" {
+		t.Fatalf("Expected both dispatches oldest first, got %+v", dispatches)
+	}
+	if dispatches[0].SolverURL != "http://solver-a" || dispatches[0].Priority != 3 || dispatches[0].LastError != "status 503" {
+		t.Errorf("Expected the queued dispatch to round-trip, got %+v", dispatches[0])
+	}
+
+	if err := db.FailDispatch(ctx, "ch_1", "http://solver-a", "connection refused"); err != nil {
+		t.Fatalf("Failed to record dispatch failure: %v", err)
+	}
+	// Re-queuing for the same solver keeps the attempt count
+	first.LastError = "status 502"
+	if err := db.EnqueueDispatch(ctx, first); err != nil {
+		t.Fatalf("Failed to re-enqueue dispatch: %v", err)
+	}
+	dispatches, err = db.ListDispatches(ctx, 1)
+	if err != nil {
+		t.Fatalf("Failed to list dispatches: %v", err)
+	}
+	if len(dispatches) != 1 || dispatches[0].Attempts != 1 || dispatches[0].LastError != "status 502" {
+		t.Fatalf("Expected ch_1 with one failed attempt, got %+v", dispatches)
+	}
+
+	// Each solver of a challenge is queued separately
+	if err := db.EnqueueDispatch(ctx, &models.PendingDispatch{ChallengeID: "ch_1", SolverURL: "http://solver-b", LastError: "status 503"}); err != nil {
+		t.Fatalf("Failed to enqueue dispatch: %v", err)
+	}
+	if err := db.CompleteDispatch(ctx, "ch_1", "http://solver-a"); err != nil {
+		t.Fatalf("Failed to complete dispatch: %v", err)
+	}
+	dispatches, err = db.ListDispatches(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list dispatches: %v", err)
+	}
+	if len(dispatches) != 2 || dispatches[0].ChallengeID != "ch_2" || dispatches[1].SolverURL != "http://solver-b" {
+		t.Errorf("Expected ch_2 and ch_1 on solver-b to remain, got %+v", dispatches)
+	}
+}
+
+func TestChallengerDB_CloseChallenge(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := &models.Challenge{
+		ID:             "ch_race",
+		Type:           "text",
+		Problem:        json.RawMessage(`{"type":"text"}`),
+		OutputSpec:     json.RawMessage(`{"content_type":"text/plain"}`),
+		ValidationRule: models.ValidationRule{Type: "ExactMatch", Answer: "ok"},
+		CreatedAt:      time.Now(),
+	}
+	if err := db.CreateChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+	if err := db.SetChallengeCompetitive(ctx, challenge.ID); err != nil {
+		t.Fatalf("Failed to mark challenge competitive: %v", err)
+	}
+
+	won, err := db.CloseChallenge(ctx, challenge.ID, "req_first", "0xfirst", time.Now())
+	if err != nil || !won {
+		t.Fatalf("Expected the first answer to win, got %v, %v", won, err)
+	}
+	if won, err := db.CloseChallenge(ctx, challenge.ID, "req_second", "0xsecond", time.Now()); err != nil || won {
+		t.Errorf("Expected a later answer to lose, got %v, %v", won, err)
+	}
+	if won, err := db.CloseChallenge(ctx, challenge.ID, "req_first", "0xfirst", time.Now()); err != nil || !won {
+		t.Errorf("Expected the winner to stay the winner on retry, got %v, %v", won, err)
+	}
+	// Request IDs are client-chosen, so reusing the winner's ID from another address must not win
+	if won, err := db.CloseChallenge(ctx, challenge.ID, "req_first", "0xsecond", time.Now()); err != nil || won {
+		t.Errorf("Expected another address reusing the winning request ID to lose, got %v, %v", won, err)
+	}
+
+	got, err := db.GetChallenge(ctx, challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to get challenge: %v", err)
+	}
+	if !got.Competitive || got.WinnerRequestID != "req_first" || got.WinnerSolverAddress != "0xfirst" || got.ClosedAt == nil {
+		t.Errorf("Expected the challenge closed by req_first, got %+v", got)
+	}
+
+	result := &models.Result{ChallengeID: challenge.ID, RequestID: "req_second", Status: "success", IsCorrect: true, IsLate: true, CreatedAt: time.Now()}
+	if _, err := db.SaveResultWithDuplicateCheck(ctx, result); err != nil {
+		t.Fatalf("Failed to save result: %v", err)
+	}
+	stored, err := db.GetResult(ctx, challenge.ID, "req_second")
+	if err != nil || stored == nil || !stored.IsLate {
+		t.Errorf("Expected the late flag to round-trip, got %+v, %v", stored, err)
+	}
+}
+
+func TestChallengerDB_GetLeaderboard(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	results := []struct {
+		solver        string
+		correct, late bool
+		computeTimeMs int
+	}{
+		{solver: "0xalice", correct: true, computeTimeMs: 100},
+		{solver: "0xalice", correct: true, computeTimeMs: 200},
+		{solver: "0xalice", correct: false, computeTimeMs: 300},
+		{solver: "0xbob", correct: true, computeTimeMs: 50},
+		{solver: "0xbob", correct: true, late: true, computeTimeMs: 150},
+		{solver: "0xcarol", correct: true, computeTimeMs: 400},
+		{solver: "0xdave", correct: false, computeTimeMs: 10},
+		{solver: "", correct: true, computeTimeMs: 10},
+	}
+	for i, r := range results {
+		err := db.SaveResult(ctx, &models.Result{
+			ChallengeID:   fmt.Sprintf("ch_%d", i),
+			RequestID:     fmt.Sprintf("req_%d", i),
+			Status:        "success",
+			IsCorrect:     r.correct,
+			IsLate:        r.late,
+			SolverAddress: r.solver,
+			ComputeTimeMs: r.computeTimeMs,
+			CreatedAt:     time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to save result %d: %v", i, err)
+		}
+	}
+
+	leaderboard, err := db.GetLeaderboard(ctx, 0)
+	if err != nil {
+		t.Fatalf("Failed to get leaderboard: %v", err)
+	}
+
+	// bob and carol tie on score; bob's extra late answer still ranks him ahead on correct answers
+	want := []models.SolverScore{
+		{SolverAddress: "0xalice", Attempts: 3, Correct: 2, AvgComputeTimeMs: 200, Score: 200},
+		{SolverAddress: "0xbob", Attempts: 2, Correct: 2, AvgComputeTimeMs: 100, Score: 100},
+		{SolverAddress: "0xcarol", Attempts: 1, Correct: 1, AvgComputeTimeMs: 400, Score: 100},
+		{SolverAddress: "0xdave", Attempts: 1, Correct: 0, AvgComputeTimeMs: 10, Score: 0},
+	}
+	if len(leaderboard) != len(want) {
+		t.Fatalf("Expected %d solvers, got %+v", len(want), leaderboard)
+	}
+	for i := range want {
+		if leaderboard[i] != want[i] {
+			t.Errorf("Rank %d: expected %+v, got %+v", i+1, want[i], leaderboard[i])
+		}
+	}
+
+	top, err := db.GetLeaderboard(ctx, 2)
+	if err != nil {
+		t.Fatalf("Failed to get leaderboard: %v", err)
+	}
+	if len(top) != 2 || top[0].SolverAddress != "0xalice" || top[1].SolverAddress != "0xbob" {
+		t.Errorf("Expected the top two solvers, got %+v", top)
+	}
+}
@@ -9,7 +9,8 @@ import (
 type Database interface {
 	SaveContract(ctx context.Context, contract *models.Contract) error
 	GetContractByName(ctx context.Context, name, chainID string) (*models.Contract, error)
-	ListContracts(ctx context.Context, chainID string) ([]*models.Contract, error)
+	ListContracts(ctx context.Context, chainID, contractType string, limit, offset int) ([]*models.Contract, int, error)
+	DeleteContract(ctx context.Context, name, chainID string) error
 	Close() error
 }
 
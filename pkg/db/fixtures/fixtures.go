@@ -0,0 +1,118 @@
+//go:build fixtures
+
+// Package fixtures seeds and resets stores for service and integration tests.
+// It is only compiled with the fixtures build tag:
+//
+//	go test -tags fixtures ./...
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/db"
+	"reverse-challenge-system/pkg/models"
+)
+
+// KnownAnswer is the expected answer of every challenge built by Challenge.
+const KnownAnswer = "expected_answer"
+
+// Challenge builds a text challenge with a case-sensitive ExactMatch rule for KnownAnswer.
+func Challenge(id string) *models.Challenge {
+	return &models.Challenge{
+		ID:         id,
+		Type:       "text",
+		Problem:    json.RawMessage(`{"type":"text","data":"fixture problem"}`),
+		OutputSpec: json.RawMessage(`{"content_type":"text/plain"}`),
+		ValidationRule: models.ValidationRule{
+			Type:   "ExactMatch",
+			Answer: KnownAnswer,
+			Params: json.RawMessage(`{"case_sensitive":true}`),
+		},
+		CreatedAt: time.Now(),
+	}
+}
+
+// Result builds a success result for challengeID. Correct results carry KnownAnswer.
+func Result(challengeID, requestID string, correct bool) *models.Result {
+	answer := "wrong_answer"
+	if correct {
+		answer = KnownAnswer
+	}
+
+	return &models.Result{
+		ChallengeID:    challengeID,
+		RequestID:      requestID,
+		SolverJobID:    "job_" + requestID,
+		Status:         "success",
+		ReceivedAnswer: answer,
+		IsCorrect:      correct,
+		SolverMetadata: json.RawMessage(`{}`),
+		CreatedAt:      time.Now(),
+	}
+}
+
+// PendingChallenge builds a pending solver row that is immediately eligible for processing.
+func PendingChallenge(id string) *models.PendingChallenge {
+	now := time.Now()
+	return &models.PendingChallenge{
+		ID:            id,
+		Problem:       json.RawMessage(`{"type":"text","data":"fixture problem"}`),
+		OutputSpec:    json.RawMessage(`{"content_type":"text/plain"}`),
+		CallbackURL:   "http://localhost:8080/callback/" + id,
+		ReceivedAt:    now,
+		Status:        "pending",
+		NextRetryTime: now,
+	}
+}
+
+// SeedChallenges stores the given challenges, failing the test on error.
+func SeedChallenges(tb testing.TB, store db.ChallengerStore, challenges ...*models.Challenge) {
+	tb.Helper()
+
+	for _, challenge := range challenges {
+		if err := store.CreateChallenge(context.Background(), challenge); err != nil {
+			tb.Fatalf("failed to seed challenge %s: %v", challenge.ID, err)
+		}
+	}
+}
+
+// SeedResults stores the given results, failing the test on error.
+// The referenced challenges must already be seeded.
+func SeedResults(tb testing.TB, store db.ChallengerStore, results ...*models.Result) {
+	tb.Helper()
+
+	for _, result := range results {
+		if err := store.SaveResult(context.Background(), result); err != nil {
+			tb.Fatalf("failed to seed result %s/%s: %v", result.ChallengeID, result.RequestID, err)
+		}
+	}
+}
+
+// SeedPendingChallenges stores the given solver rows, failing the test on error.
+func SeedPendingChallenges(tb testing.TB, store db.SolverStore, challenges ...*models.PendingChallenge) {
+	tb.Helper()
+
+	for _, challenge := range challenges {
+		if err := store.SaveChallenge(context.Background(), challenge); err != nil {
+			tb.Fatalf("failed to seed pending challenge %s: %v", challenge.ID, err)
+		}
+	}
+}
+
+// Truncate empties every table of the store, failing the test on error.
+func Truncate(tb testing.TB, store db.Truncater) {
+	tb.Helper()
+
+	if err := store.Truncate(context.Background()); err != nil {
+		tb.Fatalf("failed to truncate store: %v", err)
+	}
+}
+
+// TruncateOnCleanup empties the store when the test and its subtests finish.
+func TruncateOnCleanup(tb testing.TB, store db.Truncater) {
+	tb.Helper()
+	tb.Cleanup(func() { Truncate(tb, store) })
+}
@@ -0,0 +1,84 @@
+//go:build fixtures
+
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"reverse-challenge-system/pkg/db"
+)
+
+func TestSeedAndTruncateChallengerStore(t *testing.T) {
+	store, err := db.NewChallengerDB(filepath.Join(t.TempDir(), "challenger.db"))
+	if err != nil {
+		t.Fatalf("Failed to create challenger database: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	SeedChallenges(t, store, Challenge("ch_1"), Challenge("ch_2"))
+	SeedResults(t, store, Result("ch_1", "req_1", true), Result("ch_1", "req_2", false))
+
+	challenge, err := store.GetChallenge(ctx, "ch_2")
+	if err != nil || challenge == nil {
+		t.Fatalf("Expected seeded challenge to be retrievable, got %+v (err %v)", challenge, err)
+	}
+	if challenge.ValidationRule.Answer != KnownAnswer {
+		t.Errorf("Expected answer %q, got %q", KnownAnswer, challenge.ValidationRule.Answer)
+	}
+
+	result, err := store.GetResult(ctx, "ch_1", "req_1")
+	if err != nil || result == nil || !result.IsCorrect {
+		t.Fatalf("Expected correct seeded result, got %+v (err %v)", result, err)
+	}
+	if _, total, err := store.ListResults(ctx, "ch_1", "", 0, 0); err != nil || total != 2 {
+		t.Errorf("Expected 2 seeded results, got %d (err %v)", total, err)
+	}
+
+	Truncate(t, store)
+
+	for _, id := range []string{"ch_1", "ch_2"} {
+		if _, err := store.GetChallenge(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected challenge %s to be truncated, got err %v", id, err)
+		}
+	}
+	if _, total, err := store.ListResults(ctx, "", "", 0, 0); err != nil || total != 0 {
+		t.Errorf("Expected no results after truncate, got %d (err %v)", total, err)
+	}
+
+	// The same IDs can be seeded again once the store is empty
+	SeedChallenges(t, store, Challenge("ch_1"))
+}
+
+func TestSeedAndTruncateSolverStore(t *testing.T) {
+	store, err := db.NewSolverDB(filepath.Join(t.TempDir(), "solver.db"))
+	if err != nil {
+		t.Fatalf("Failed to create solver database: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	SeedPendingChallenges(t, store, PendingChallenge("pending_1"), PendingChallenge("pending_2"))
+
+	pending, err := store.GetPendingChallenges(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("Expected 2 seeded pending challenges, got %d", len(pending))
+	}
+
+	Truncate(t, store)
+
+	pending, err = store.GetPendingChallenges(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending challenges after truncate, got %d", len(pending))
+	}
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+// Runtime-managed HMAC keys share one schema across the challenger and solver stores
+const (
+	sqliteHMACKeysTable = `CREATE TABLE IF NOT EXISTS hmac_keys (
+			key_id TEXT PRIMARY KEY,
+			secret TEXT NOT NULL DEFAULT '',
+			not_before TIMESTAMP,
+			not_after TIMESTAMP,
+			revoked_at TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`
+	postgresHMACKeysTable = `CREATE TABLE IF NOT EXISTS hmac_keys (
+			key_id TEXT PRIMARY KEY,
+			secret TEXT NOT NULL DEFAULT '',
+			not_before TIMESTAMPTZ,
+			not_after TIMESTAMPTZ,
+			revoked_at TIMESTAMPTZ,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`
+
+	sqliteSaveHMACKey = `
+		INSERT INTO hmac_keys (key_id, secret, not_before, not_after, revoked_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (key_id) DO UPDATE SET
+			secret = excluded.secret, not_before = excluded.not_before, not_after = excluded.not_after,
+			revoked_at = excluded.revoked_at, updated_at = excluded.updated_at`
+	postgresSaveHMACKey = `
+		INSERT INTO hmac_keys (key_id, secret, not_before, not_after, revoked_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key_id) DO UPDATE SET
+			secret = excluded.secret, not_before = excluded.not_before, not_after = excluded.not_after,
+			revoked_at = excluded.revoked_at, updated_at = excluded.updated_at`
+
+	listHMACKeysQuery = `
+		SELECT key_id, secret, not_before, not_after, revoked_at, updated_at
+		FROM hmac_keys ORDER BY key_id`
+)
+
+// SaveHMACKey inserts or replaces a runtime-managed HMAC key.
+func (c *ChallengerDB) SaveHMACKey(ctx context.Context, key *models.HMACKey) error {
+	return saveHMACKey(ctx, c.db, sqliteSaveHMACKey, key)
+}
+
+// ListHMACKeys returns every runtime-managed HMAC key, including revoked ones.
+func (c *ChallengerDB) ListHMACKeys(ctx context.Context) ([]*models.HMACKey, error) {
+	return listHMACKeys(ctx, c.db)
+}
+
+// SaveHMACKey inserts or replaces a runtime-managed HMAC key.
+func (s *SolverDB) SaveHMACKey(ctx context.Context, key *models.HMACKey) error {
+	return saveHMACKey(ctx, s.db, sqliteSaveHMACKey, key)
+}
+
+// ListHMACKeys returns every runtime-managed HMAC key, including revoked ones.
+func (s *SolverDB) ListHMACKeys(ctx context.Context) ([]*models.HMACKey, error) {
+	return listHMACKeys(ctx, s.db)
+}
+
+// SaveHMACKey inserts or replaces a runtime-managed HMAC key.
+func (p *PostgresChallengerDB) SaveHMACKey(ctx context.Context, key *models.HMACKey) error {
+	return saveHMACKey(ctx, p.db, postgresSaveHMACKey, key)
+}
+
+// ListHMACKeys returns every runtime-managed HMAC key, including revoked ones.
+func (p *PostgresChallengerDB) ListHMACKeys(ctx context.Context) ([]*models.HMACKey, error) {
+	return listHMACKeys(ctx, p.db)
+}
+
+// SaveHMACKey inserts or replaces a runtime-managed HMAC key.
+func (p *PostgresSolverDB) SaveHMACKey(ctx context.Context, key *models.HMACKey) error {
+	return saveHMACKey(ctx, p.db, postgresSaveHMACKey, key)
+}
+
+// ListHMACKeys returns every runtime-managed HMAC key, including revoked ones.
+func (p *PostgresSolverDB) ListHMACKeys(ctx context.Context) ([]*models.HMACKey, error) {
+	return listHMACKeys(ctx, p.db)
+}
+
+// saveHMACKey upserts key with the driver-specific query. A zero UpdatedAt is set to now.
+func saveHMACKey(ctx context.Context, db *sql.DB, query string, key *models.HMACKey) error {
+	if key.UpdatedAt.IsZero() {
+		key.UpdatedAt = time.Now()
+	}
+
+	_, err := db.ExecContext(ctx, query, key.KeyID, key.Secret,
+		nullTime(key.NotBefore), nullTime(key.NotAfter), nullTime(key.RevokedAt), key.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save HMAC key: %w", err)
+	}
+	return nil
+}
+
+// listHMACKeys loads every stored HMAC key ordered by key ID
+func listHMACKeys(ctx context.Context, db *sql.DB) ([]*models.HMACKey, error) {
+	rows, err := db.QueryContext(ctx, listHMACKeysQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HMAC keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*models.HMACKey
+	for rows.Next() {
+		var key models.HMACKey
+		var notBefore, notAfter, revokedAt sql.NullTime
+		if err := rows.Scan(&key.KeyID, &key.Secret, &notBefore, &notAfter, &revokedAt, &key.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan HMAC key: %w", err)
+		}
+		key.NotBefore = timePtr(notBefore)
+		key.NotAfter = timePtr(notAfter)
+		key.RevokedAt = timePtr(revokedAt)
+		keys = append(keys, &key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating HMAC keys: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Applied schema versions are recorded in schema_migrations, one row per store and migration,
+// so the challenger and solver schemas can share a database without mixing up their versions
+const (
+	sqliteSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+			store TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			description TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (store, version)
+		)`
+	postgresSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+			store TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			description TEXT NOT NULL,
+			applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (store, version)
+		)`
+
+	// Store names in schema_migrations
+	challengerSchema = "challenger"
+	solverSchema     = "solver"
+)
+
+// migration is one step of a store's schema history. up runs in the same transaction
+// that records the version, so a failed step leaves neither its changes nor its row behind.
+type migration struct {
+	version     int
+	description string
+	up          func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrationDialect holds the driver-specific statements used to track migrations
+type migrationDialect struct {
+	createTable string // Creates schema_migrations
+	applied     string // Counts rows for a store and version
+	record      string // Inserts the row for an applied version
+	lock        string // Serializes migrating processes inside the transaction (optional)
+}
+
+var sqliteMigrationDialect = migrationDialect{
+	createTable: sqliteSchemaMigrationsTable,
+	applied:     `SELECT COUNT(*) FROM schema_migrations WHERE store = ? AND version = ?`,
+	record:      `INSERT INTO schema_migrations (store, version, description, applied_at) VALUES (?, ?, ?, ?)`,
+}
+
+var postgresMigrationDialect = migrationDialect{
+	createTable: postgresSchemaMigrationsTable,
+	applied:     `SELECT COUNT(*) FROM schema_migrations WHERE store = $1 AND version = $2`,
+	record:      `INSERT INTO schema_migrations (store, version, description, applied_at) VALUES ($1, $2, $3, $4)`,
+	// Replicas starting together wait for each other instead of racing on the same version
+	lock: `SELECT pg_advisory_xact_lock(7243351)`,
+}
+
+// runMigrations applies, in order, every migration of store whose version is not recorded yet.
+// Already-applied versions are skipped, so running it again is a no-op.
+func runMigrations(ctx context.Context, db *sql.DB, dialect migrationDialect, store string, migrations []migration) error {
+	if _, err := db.ExecContext(ctx, dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := applyMigration(ctx, db, dialect, store, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration in its own transaction unless it was already applied
+func applyMigration(ctx context.Context, db *sql.DB, dialect migrationDialect, store string, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if dialect.lock != "" {
+		if _, err := tx.ExecContext(ctx, dialect.lock); err != nil {
+			return fmt.Errorf("failed to lock schema_migrations: %w", err)
+		}
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, dialect.applied, store, m.version).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check schema version: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := m.up(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, dialect.record, store, m.version, m.description, time.Now()); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
+
+// execStatements returns a migration step that runs each statement in order
+func execStatements(statements ...string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("failed to execute query %s: %w", statement, err)
+			}
+		}
+		return nil
+	}
+}
+
+// addColumnIfMissing adds a column to an existing SQLite table.
+// SQLite has no ADD COLUMN IF NOT EXISTS, so the table is inspected first.
+func addColumnIfMissing(ctx context.Context, tx *sql.Tx, table, column, definition string) error {
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
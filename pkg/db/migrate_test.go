@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// appliedVersions lists the versions recorded in schema_migrations
+func appliedVersions(t *testing.T, db *sql.DB) []int {
+	t.Helper()
+
+	rows, err := db.Query("SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		t.Fatalf("Failed to query schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	versions := []int{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			t.Fatalf("Failed to scan version: %v", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+func TestMigrate_EmptyDatabaseIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+
+	challengerDB, err := NewChallengerDB(filepath.Join(dir, "challenger.db"))
+	if err != nil {
+		t.Fatalf("Failed to create challenger database: %v", err)
+	}
+	defer challengerDB.Close()
+	solverDB, err := NewSolverDB(filepath.Join(dir, "solver.db"))
+	if err != nil {
+		t.Fatalf("Failed to create solver database: %v", err)
+	}
+	defer solverDB.Close()
+
+	stores := []struct {
+		name    string
+		db      *sql.DB
+		migrate func(context.Context) error
+		want    int
+	}{
+		{"challenger", challengerDB.db, challengerDB.Migrate, len(challengerMigrations)},
+		{"solver", solverDB.db, solverDB.Migrate, len(solverMigrations)},
+	}
+
+	for _, store := range stores {
+		t.Run(store.name, func(t *testing.T) {
+			if got := appliedVersions(t, store.db); len(got) != store.want {
+				t.Fatalf("Expected %d applied migrations after open, got %v", store.want, got)
+			}
+
+			// Re-running applies nothing and leaves the data alone
+			if _, err := store.db.Exec("INSERT INTO seen_nonces (nonce) VALUES ('kept')"); err != nil {
+				t.Fatalf("Failed to insert nonce: %v", err)
+			}
+			for i := 0; i < 2; i++ {
+				if err := store.migrate(context.Background()); err != nil {
+					t.Fatalf("Failed to re-run migrations: %v", err)
+				}
+			}
+			if got := appliedVersions(t, store.db); len(got) != store.want {
+				t.Errorf("Expected %d applied migrations after re-running, got %v", store.want, got)
+			}
+
+			var count int
+			if err := store.db.QueryRow("SELECT COUNT(*) FROM seen_nonces").Scan(&count); err != nil || count != 1 {
+				t.Errorf("Expected the nonce to survive re-running migrations, got %d (err %v)", count, err)
+			}
+		})
+	}
+}
+
+func TestMigrate_AppliesOnlyPendingSteps(t *testing.T) {
+	db, err := openSQLite(filepath.Join(t.TempDir(), "migrate.db"), DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	runs := map[int]int{}
+	step := func(version int, statement string) migration {
+		return migration{
+			version:     version,
+			description: statement,
+			up: func(ctx context.Context, tx *sql.Tx) error {
+				runs[version]++
+				return execStatements(statement)(ctx, tx)
+			},
+		}
+	}
+
+	migrations := []migration{
+		step(1, "CREATE TABLE widgets (id INTEGER PRIMARY KEY)"),
+		step(2, "ALTER TABLE widgets ADD COLUMN name TEXT"),
+	}
+	if err := runMigrations(ctx, db, sqliteMigrationDialect, "test", migrations); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	migrations = append(migrations, step(3, "CREATE INDEX ix_widgets_name ON widgets(name)"))
+	if err := runMigrations(ctx, db, sqliteMigrationDialect, "test", migrations); err != nil {
+		t.Fatalf("Failed to run new migration: %v", err)
+	}
+
+	if runs[1] != 1 || runs[2] != 1 || runs[3] != 1 {
+		t.Errorf("Expected every step to run exactly once, got %v", runs)
+	}
+	if got := appliedVersions(t, db); len(got) != 3 || got[2] != 3 {
+		t.Errorf("Expected versions 1-3 to be recorded, got %v", got)
+	}
+}
+
+func TestMigrate_FailedStepRollsBack(t *testing.T) {
+	db, err := openSQLite(filepath.Join(t.TempDir(), "migrate.db"), DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	errBroken := errors.New("broken step")
+	migrations := []migration{{
+		version:     1,
+		description: "broken",
+		up: func(ctx context.Context, tx *sql.Tx) error {
+			if err := execStatements("CREATE TABLE half_done (id INTEGER)")(ctx, tx); err != nil {
+				return err
+			}
+			return errBroken
+		},
+	}}
+
+	err = runMigrations(context.Background(), db, sqliteMigrationDialect, "test", migrations)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected the step error, got %v", err)
+	}
+
+	if got := appliedVersions(t, db); len(got) != 0 {
+		t.Errorf("Expected no recorded versions, got %v", got)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'half_done'").Scan(&count); err != nil || count != 0 {
+		t.Errorf("Expected the half-applied table to be rolled back, got %d (err %v)", count, err)
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Claiming a nonce inserts it unless it is already stored; the primary key makes this atomic
+const (
+	sqliteClaimNonce   = `INSERT INTO seen_nonces (nonce, seen_at) VALUES (?, ?) ON CONFLICT (nonce) DO NOTHING`
+	postgresClaimNonce = `INSERT INTO seen_nonces (nonce, seen_at) VALUES ($1, $2) ON CONFLICT (nonce) DO NOTHING`
+)
+
+// ClaimNonce records nonce and reports whether it was not stored before.
+func (c *ChallengerDB) ClaimNonce(ctx context.Context, nonce string) (bool, error) {
+	return claimNonce(ctx, c.db, sqliteClaimNonce, nonce)
+}
+
+// ClaimNonce records nonce and reports whether it was not stored before.
+func (s *SolverDB) ClaimNonce(ctx context.Context, nonce string) (bool, error) {
+	return claimNonce(ctx, s.db, sqliteClaimNonce, nonce)
+}
+
+// ClaimNonce records nonce and reports whether it was not stored before.
+func (p *PostgresChallengerDB) ClaimNonce(ctx context.Context, nonce string) (bool, error) {
+	return claimNonce(ctx, p.db, postgresClaimNonce, nonce)
+}
+
+// ClaimNonce records nonce and reports whether it was not stored before.
+func (p *PostgresSolverDB) ClaimNonce(ctx context.Context, nonce string) (bool, error) {
+	return claimNonce(ctx, p.db, postgresClaimNonce, nonce)
+}
+
+// claimNonce runs the driver-specific insert and reports whether it added a row
+func claimNonce(ctx context.Context, db *sql.DB, query, nonce string) (bool, error) {
+	res, err := db.ExecContext(ctx, query, nonce, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim nonce: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -17,34 +18,40 @@ type SolverDB struct {
 	db *sql.DB // SQLite database connection
 }
 
-// NewSolverDB creates and initializes a new solver database instance.
+// NewSolverDB creates and initializes a new solver database instance with DefaultSQLiteOptions.
 // Opens SQLite connection, enables WAL mode, and creates required tables.
 func NewSolverDB(dbPath string) (*SolverDB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	// Enable WAL mode for better concurrent access and set busy timeout
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
-	}
+	return NewSolverDBWithOptions(dbPath, DefaultSQLiteOptions())
+}
 
-	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
-		return nil, fmt.Errorf("failed to set busy timeout: %w", err)
+// NewSolverDBWithOptions is NewSolverDB with a configured busy timeout and pool size.
+func NewSolverDBWithOptions(dbPath string, opts SQLiteOptions) (*SolverDB, error) {
+	db, err := openSQLite(dbPath, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	sdb := &SolverDB{db: db}
-	if err := sdb.createTables(); err != nil {
-		return nil, fmt.Errorf("failed to create tables: %w", err)
+	if err := sdb.Migrate(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
 	return sdb, nil
 }
 
-// createTables initializes all required database tables for solver operations.
-// Creates tables for pending challenges, nonce tracking, and performance indexes.
-func (s *SolverDB) createTables() error {
+// NewSolverReadOnlyDB opens a read-only connection to an existing solver database.
+// Used by reporting endpoints so reads do not contend with the workers; tables are not created.
+func NewSolverReadOnlyDB(dbPath string, opts SQLiteOptions) (*SolverDB, error) {
+	db, err := openSQLiteReadOnly(dbPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SolverDB{db: db}, nil
+}
+
+// createSolverSchema creates the solver tables: pending challenges, nonce tracking, and performance
+// indexes. Databases created before versioning already have some of them.
+func createSolverSchema(ctx context.Context, tx *sql.Tx) error {
 	queries := []string{
 		`CREATE TABLE IF NOT EXISTS pending_challenges (
 			id TEXT PRIMARY KEY,
@@ -54,35 +61,79 @@ func (s *SolverDB) createTables() error {
 			received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			status TEXT NOT NULL DEFAULT 'pending',
 			attempt_count INTEGER DEFAULT 0,
-			next_retry_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			next_retry_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			timeout_ms INTEGER NOT NULL DEFAULT 0,
+			deadline_ts INTEGER NOT NULL DEFAULT 0,
+			priority INTEGER NOT NULL DEFAULT 0,
+			callback_request_id TEXT NOT NULL DEFAULT '',
+			api_version TEXT NOT NULL DEFAULT ''
 		)`,
 		`CREATE TABLE IF NOT EXISTS seen_nonces (
 			nonce TEXT PRIMARY KEY,
 			seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
+		sqliteHMACKeysTable,
+		`CREATE TABLE IF NOT EXISTS failed_challenges (
+			id TEXT PRIMARY KEY,
+			problem TEXT NOT NULL,
+			output_spec TEXT NOT NULL,
+			callback_url TEXT NOT NULL,
+			received_at TIMESTAMP,
+			attempt_count INTEGER DEFAULT 0,
+			reason TEXT NOT NULL,
+			failed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS ix_failed_challenges_failed_at ON failed_challenges(failed_at)`,
 		`CREATE INDEX IF NOT EXISTS ix_pending_status_retry ON pending_challenges(status, next_retry_time)`,
 		`CREATE INDEX IF NOT EXISTS ix_seen_nonces_seen_at ON seen_nonces(seen_at)`,
 	}
 
 	for _, query := range queries {
-		if _, err := s.db.Exec(query); err != nil {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
 			return fmt.Errorf("failed to execute query %s: %w", query, err)
 		}
 	}
 
+	// Columns added before schema versioning; CREATE TABLE IF NOT EXISTS leaves old tables untouched
+	columns := []struct{ column, definition string }{
+		{"timeout_ms", "INTEGER NOT NULL DEFAULT 0"},
+		{"deadline_ts", "INTEGER NOT NULL DEFAULT 0"},
+		{"priority", "INTEGER NOT NULL DEFAULT 0"},
+		{"callback_request_id", "TEXT NOT NULL DEFAULT ''"},
+		{"api_version", "TEXT NOT NULL DEFAULT ''"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(ctx, tx, "pending_challenges", c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// solverMigrations is the SQLite solver schema history, applied in order by Migrate.
+// Append new steps for schema changes; never edit a migration that has shipped.
+var solverMigrations = []migration{
+	{version: 1, description: "initial schema", up: createSolverSchema},
+}
+
+// Migrate applies the solver schema migrations the database has not recorded yet.
+// It runs on open and is safe to call again.
+func (s *SolverDB) Migrate(ctx context.Context) error {
+	return runMigrations(ctx, s.db, sqliteMigrationDialect, solverSchema, solverMigrations)
+}
+
 // SaveChallenge stores a new challenge for processing by the solver workers.
 // Converts JSON fields to strings for database storage and sets initial status.
-func (s *SolverDB) SaveChallenge(challenge *models.PendingChallenge) error {
-	_, err := s.db.Exec(`
+func (s *SolverDB) SaveChallenge(ctx context.Context, challenge *models.PendingChallenge) error {
+	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO pending_challenges (id, problem, output_spec, callback_url, 
-			received_at, status, attempt_count, next_retry_time)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			received_at, status, attempt_count, next_retry_time, timeout_ms, deadline_ts, priority, callback_request_id, api_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		challenge.ID, string(challenge.Problem), string(challenge.OutputSpec),
 		challenge.CallbackURL, challenge.ReceivedAt, challenge.Status,
-		challenge.AttemptCount, challenge.NextRetryTime)
+		challenge.AttemptCount, challenge.NextRetryTime, challenge.TimeoutMs, challenge.DeadlineTs,
+		challenge.Priority, challenge.CallbackRequestID, challenge.APIVersion)
 
 	if err != nil {
 		return fmt.Errorf("failed to save challenge: %w", err)
@@ -93,24 +144,36 @@ func (s *SolverDB) SaveChallenge(challenge *models.PendingChallenge) error {
 
 // GetChallenge retrieves a specific challenge by ID from the solver database.
 // Reconstructs the challenge with proper JSON field conversion from stored text.
-func (s *SolverDB) GetChallenge(id string) (*models.PendingChallenge, error) {
-	row := s.db.QueryRow(`
+func (s *SolverDB) GetChallenge(ctx context.Context, id string) (*models.PendingChallenge, error) {
+	row := s.db.QueryRowContext(ctx, `
 		SELECT id, problem, output_spec, callback_url, received_at, status, 
-			attempt_count, next_retry_time
+			attempt_count, next_retry_time, timeout_ms, deadline_ts, priority, callback_request_id, api_version
 		FROM pending_challenges WHERE id = ?`, id)
 
+	challenge, err := scanPendingChallenge(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// scanPendingChallenge reads a pending_challenges row.
+// Scan errors are returned unwrapped so callers can detect sql.ErrNoRows.
+func scanPendingChallenge(row rowScanner) (*models.PendingChallenge, error) {
 	var challenge models.PendingChallenge
 	var problemText, outputSpecText string
 
 	err := row.Scan(&challenge.ID, &problemText, &outputSpecText,
 		&challenge.CallbackURL, &challenge.ReceivedAt, &challenge.Status,
-		&challenge.AttemptCount, &challenge.NextRetryTime)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
+		&challenge.AttemptCount, &challenge.NextRetryTime,
+		&challenge.TimeoutMs, &challenge.DeadlineTs, &challenge.Priority,
+		&challenge.CallbackRequestID, &challenge.APIVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get challenge: %w", err)
+		return nil, err
 	}
 
 	// Convert text back to json.RawMessage
@@ -120,8 +183,8 @@ func (s *SolverDB) GetChallenge(id string) (*models.PendingChallenge, error) {
 	return &challenge, nil
 }
 
-func (s *SolverDB) UpdateChallengeStatus(id, status string, attemptCount int, nextRetryTime time.Time) error {
-	_, err := s.db.Exec(`
+func (s *SolverDB) UpdateChallengeStatus(ctx context.Context, id, status string, attemptCount int, nextRetryTime time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
 		UPDATE pending_challenges 
 		SET status = ?, attempt_count = ?, next_retry_time = ?
 		WHERE id = ?`, status, attemptCount, nextRetryTime, id)
@@ -133,15 +196,36 @@ func (s *SolverDB) UpdateChallengeStatus(id, status string, attemptCount int, ne
 	return nil
 }
 
+// ClaimChallenge atomically marks a challenge ready for processing as "processing" until leaseUntil.
+// Returns false when the challenge is already claimed or gone, so only one dispatcher queues it.
+// A claim whose lease has passed can be taken again, which recovers challenges from a crashed worker.
+func (s *SolverDB) ClaimChallenge(ctx context.Context, id string, leaseUntil time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE pending_challenges
+		SET status = 'processing', next_retry_time = ?
+		WHERE id = ? AND (status = 'pending' OR (status = 'processing' AND next_retry_time <= ?))`,
+		leaseUntil, id, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim challenge: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
 // GetPendingChallenges retrieves challenges ready for processing by worker threads.
-// Returns challenges in pending status or failed challenges ready for retry.
-func (s *SolverDB) GetPendingChallenges(limit int) ([]*models.PendingChallenge, error) {
-	rows, err := s.db.Query(`
+// Returns challenges in pending status or failed challenges ready for retry,
+// highest priority first and oldest first within a priority.
+func (s *SolverDB) GetPendingChallenges(ctx context.Context, limit int) ([]*models.PendingChallenge, error) {
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT id, problem, output_spec, callback_url, received_at, status, 
-			attempt_count, next_retry_time
+			attempt_count, next_retry_time, timeout_ms, deadline_ts, priority, callback_request_id, api_version
 		FROM pending_challenges 
 		WHERE (status = 'pending' OR (status = 'processing' AND next_retry_time <= ?))
-		ORDER BY received_at ASC
+		ORDER BY priority DESC, received_at ASC
 		LIMIT ?`, time.Now(), limit)
 
 	if err != nil {
@@ -152,45 +236,119 @@ func (s *SolverDB) GetPendingChallenges(limit int) ([]*models.PendingChallenge,
 	var challenges []*models.PendingChallenge
 
 	for rows.Next() {
-		var challenge models.PendingChallenge
-		var problemText, outputSpecText string
-		err := rows.Scan(&challenge.ID, &problemText, &outputSpecText,
-			&challenge.CallbackURL, &challenge.ReceivedAt, &challenge.Status,
-			&challenge.AttemptCount, &challenge.NextRetryTime)
-
+		challenge, err := scanPendingChallenge(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan challenge: %w", err)
 		}
+		challenges = append(challenges, challenge)
+	}
 
-		// Convert text back to json.RawMessage
-		challenge.Problem = json.RawMessage(problemText)
-		challenge.OutputSpec = json.RawMessage(outputSpecText)
-
-		challenges = append(challenges, &challenge)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending challenges: %w", err)
 	}
 
 	return challenges, nil
 }
 
-func (s *SolverDB) DeleteChallenge(id string) error {
-	_, err := s.db.Exec("DELETE FROM pending_challenges WHERE id = ?", id)
+// CountPendingChallenges returns how many challenges are queued or being processed.
+// Expired challenges are not counted.
+func (s *SolverDB) CountPendingChallenges(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM pending_challenges WHERE status IN ('pending', 'processing')`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending challenges: %w", err)
+	}
+	return count, nil
+}
+
+func (s *SolverDB) DeleteChallenge(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM pending_challenges WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete challenge: %w", err)
 	}
 	return nil
 }
 
-func (s *SolverDB) HasSeenNonce(nonce string) (bool, error) {
+// MoveToDeadLetter copies a pending challenge into failed_challenges with the final
+// error and removes it from the work queue. A challenge that failed before is overwritten.
+func (s *SolverDB) MoveToDeadLetter(ctx context.Context, id, reason string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO failed_challenges (id, problem, output_spec, callback_url,
+			received_at, attempt_count, reason, failed_at)
+		SELECT id, problem, output_spec, callback_url, received_at, attempt_count, ?, ?
+		FROM pending_challenges WHERE id = ?`, reason, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter challenge: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to dead-letter challenge: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("pending challenge %s not found", id)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pending_challenges WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to remove dead-lettered challenge: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dead-letter move: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetter returns dead-lettered challenges, most recently failed first.
+// A non-positive limit returns all of them.
+func (s *SolverDB) ListDeadLetter(ctx context.Context, limit int) ([]*models.FailedChallenge, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, problem, output_spec, callback_url, received_at, attempt_count, reason, failed_at
+		FROM failed_challenges ORDER BY failed_at DESC, id DESC LIMIT ?`, pageLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered challenges: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFailedChallenges(rows)
+}
+
+// scanFailedChallenges reads failed_challenges rows.
+func scanFailedChallenges(rows *sql.Rows) ([]*models.FailedChallenge, error) {
+	var failed []*models.FailedChallenge
+	for rows.Next() {
+		var fc models.FailedChallenge
+		var problemText, outputSpecText string
+		if err := rows.Scan(&fc.ID, &problemText, &outputSpecText, &fc.CallbackURL,
+			&fc.ReceivedAt, &fc.AttemptCount, &fc.Reason, &fc.FailedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dead-lettered challenge: %w", err)
+		}
+		fc.Problem = json.RawMessage(problemText)
+		fc.OutputSpec = json.RawMessage(outputSpecText)
+		failed = append(failed, &fc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dead-lettered challenges: %w", err)
+	}
+	return failed, nil
+}
+
+func (s *SolverDB) HasSeenNonce(ctx context.Context, nonce string) (bool, error) {
 	var count int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM seen_nonces WHERE nonce = ?", nonce).Scan(&count)
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM seen_nonces WHERE nonce = ?", nonce).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check nonce: %w", err)
 	}
 	return count > 0, nil
 }
 
-func (s *SolverDB) SaveNonce(nonce string) error {
-	_, err := s.db.Exec("INSERT INTO seen_nonces (nonce, seen_at) VALUES (?, ?)",
+func (s *SolverDB) SaveNonce(ctx context.Context, nonce string) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO seen_nonces (nonce, seen_at) VALUES (?, ?)",
 		nonce, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to save nonce: %w", err)
@@ -198,14 +356,24 @@ func (s *SolverDB) SaveNonce(nonce string) error {
 	return nil
 }
 
-func (s *SolverDB) CleanupOldNonces(olderThan time.Time) error {
-	_, err := s.db.Exec("DELETE FROM seen_nonces WHERE seen_at < ?", olderThan)
+func (s *SolverDB) CleanupOldNonces(ctx context.Context, olderThan time.Time) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM seen_nonces WHERE seen_at < ?", olderThan)
 	if err != nil {
 		return fmt.Errorf("failed to cleanup old nonces: %w", err)
 	}
 	return nil
 }
 
+// Truncate deletes every row from the solver tables. Intended for test isolation.
+func (s *SolverDB) Truncate(ctx context.Context) error {
+	for _, table := range []string{"pending_challenges", "failed_challenges", "seen_nonces", "hmac_keys"} {
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
 func (s *SolverDB) Close() error {
 	return s.db.Close()
 }
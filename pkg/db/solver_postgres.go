@@ -0,0 +1,323 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresSolverDB is the Postgres-backed SolverStore.
+type PostgresSolverDB struct {
+	db *sql.DB // Postgres connection pool
+}
+
+// NewPostgresSolverDB connects to Postgres using the given connection string
+// and creates the solver tables if they do not exist.
+func NewPostgresSolverDB(dsn string) (*PostgresSolverDB, error) {
+	db, err := openPostgres(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	pdb := &PostgresSolverDB{db: db}
+	if err := pdb.Migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return pdb, nil
+}
+
+// NewPostgresSolverReadOnlyDB connects to a Postgres read replica of the solver database.
+// Tables are expected to exist on the primary and are not created.
+func NewPostgresSolverReadOnlyDB(dsn string) (*PostgresSolverDB, error) {
+	db, err := openPostgres(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresSolverDB{db: db}, nil
+}
+
+// createPostgresSolverSchema mirrors the SQLite solver schema using Postgres types.
+func createPostgresSolverSchema(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS pending_challenges (
+			id TEXT PRIMARY KEY,
+			problem TEXT NOT NULL,
+			output_spec TEXT NOT NULL,
+			callback_url TEXT NOT NULL,
+			received_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempt_count INTEGER DEFAULT 0,
+			next_retry_time TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			timeout_ms INTEGER NOT NULL DEFAULT 0,
+			deadline_ts BIGINT NOT NULL DEFAULT 0,
+			priority INTEGER NOT NULL DEFAULT 0,
+			callback_request_id TEXT NOT NULL DEFAULT '',
+			api_version TEXT NOT NULL DEFAULT ''
+		)`,
+		// Columns added after the initial schema
+		`ALTER TABLE pending_challenges ADD COLUMN IF NOT EXISTS timeout_ms INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE pending_challenges ADD COLUMN IF NOT EXISTS deadline_ts BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE pending_challenges ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE pending_challenges ADD COLUMN IF NOT EXISTS callback_request_id TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE pending_challenges ADD COLUMN IF NOT EXISTS api_version TEXT NOT NULL DEFAULT ''`,
+		`CREATE TABLE IF NOT EXISTS seen_nonces (
+			nonce TEXT PRIMARY KEY,
+			seen_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		postgresHMACKeysTable,
+		`CREATE TABLE IF NOT EXISTS failed_challenges (
+			id TEXT PRIMARY KEY,
+			problem TEXT NOT NULL,
+			output_spec TEXT NOT NULL,
+			callback_url TEXT NOT NULL,
+			received_at TIMESTAMPTZ,
+			attempt_count INTEGER DEFAULT 0,
+			reason TEXT NOT NULL,
+			failed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS ix_failed_challenges_failed_at ON failed_challenges(failed_at)`,
+		`CREATE INDEX IF NOT EXISTS ix_pending_status_retry ON pending_challenges(status, next_retry_time)`,
+		`CREATE INDEX IF NOT EXISTS ix_seen_nonces_seen_at ON seen_nonces(seen_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to execute query %s: %w", query, err)
+		}
+	}
+
+	return nil
+}
+
+// postgresSolverMigrations is the Postgres solver schema history, applied in order by Migrate.
+// Append new steps for schema changes; never edit a migration that has shipped.
+var postgresSolverMigrations = []migration{
+	{version: 1, description: "initial schema", up: createPostgresSolverSchema},
+}
+
+// Migrate applies the solver schema migrations the database has not recorded yet.
+// It runs on open and is safe to call again.
+func (p *PostgresSolverDB) Migrate(ctx context.Context) error {
+	return runMigrations(ctx, p.db, postgresMigrationDialect, solverSchema, postgresSolverMigrations)
+}
+
+// SaveChallenge stores a new challenge for processing by the solver workers.
+func (p *PostgresSolverDB) SaveChallenge(ctx context.Context, challenge *models.PendingChallenge) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO pending_challenges (id, problem, output_spec, callback_url,
+			received_at, status, attempt_count, next_retry_time, timeout_ms, deadline_ts, priority, callback_request_id, api_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		challenge.ID, string(challenge.Problem), string(challenge.OutputSpec),
+		challenge.CallbackURL, challenge.ReceivedAt, challenge.Status,
+		challenge.AttemptCount, challenge.NextRetryTime, challenge.TimeoutMs, challenge.DeadlineTs,
+		challenge.Priority, challenge.CallbackRequestID, challenge.APIVersion)
+
+	if err != nil {
+		return fmt.Errorf("failed to save challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallenge retrieves a specific challenge by ID. Returns nil if it does not exist.
+func (p *PostgresSolverDB) GetChallenge(ctx context.Context, id string) (*models.PendingChallenge, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT id, problem, output_spec, callback_url, received_at, status,
+			attempt_count, next_retry_time, timeout_ms, deadline_ts, priority, callback_request_id, api_version
+		FROM pending_challenges WHERE id = $1`, id)
+
+	challenge, err := scanPendingChallenge(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+func (p *PostgresSolverDB) UpdateChallengeStatus(ctx context.Context, id, status string, attemptCount int, nextRetryTime time.Time) error {
+	_, err := p.db.ExecContext(ctx, `
+		UPDATE pending_challenges
+		SET status = $1, attempt_count = $2, next_retry_time = $3
+		WHERE id = $4`, status, attemptCount, nextRetryTime, id)
+
+	if err != nil {
+		return fmt.Errorf("failed to update challenge status: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimChallenge atomically marks a challenge ready for processing as "processing" until leaseUntil.
+// Returns false when the challenge is already claimed or gone.
+func (p *PostgresSolverDB) ClaimChallenge(ctx context.Context, id string, leaseUntil time.Time) (bool, error) {
+	res, err := p.db.ExecContext(ctx, `
+		UPDATE pending_challenges
+		SET status = 'processing', next_retry_time = $1
+		WHERE id = $2 AND (status = 'pending' OR (status = 'processing' AND next_retry_time <= $3))`,
+		leaseUntil, id, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim challenge: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetPendingChallenges retrieves challenges ready for processing by worker threads.
+func (p *PostgresSolverDB) GetPendingChallenges(ctx context.Context, limit int) ([]*models.PendingChallenge, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, problem, output_spec, callback_url, received_at, status,
+			attempt_count, next_retry_time, timeout_ms, deadline_ts, priority, callback_request_id, api_version
+		FROM pending_challenges
+		WHERE (status = 'pending' OR (status = 'processing' AND next_retry_time <= $1))
+		ORDER BY priority DESC, received_at ASC
+		LIMIT $2`, time.Now(), limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending challenges: %w", err)
+	}
+	defer rows.Close()
+
+	var challenges []*models.PendingChallenge
+
+	for rows.Next() {
+		challenge, err := scanPendingChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending challenges: %w", err)
+	}
+
+	return challenges, nil
+}
+
+// CountPendingChallenges returns how many challenges are queued or being processed.
+func (p *PostgresSolverDB) CountPendingChallenges(ctx context.Context) (int, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM pending_challenges WHERE status IN ('pending', 'processing')`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending challenges: %w", err)
+	}
+	return count, nil
+}
+
+func (p *PostgresSolverDB) DeleteChallenge(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, "DELETE FROM pending_challenges WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete challenge: %w", err)
+	}
+	return nil
+}
+
+// MoveToDeadLetter copies a pending challenge into failed_challenges with the final
+// error and removes it from the work queue. A challenge that failed before is overwritten.
+func (p *PostgresSolverDB) MoveToDeadLetter(ctx context.Context, id, reason string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO failed_challenges (id, problem, output_spec, callback_url,
+			received_at, attempt_count, reason, failed_at)
+		SELECT id, problem, output_spec, callback_url, received_at, attempt_count, $1, $2
+		FROM pending_challenges WHERE id = $3
+		ON CONFLICT (id) DO UPDATE SET problem = EXCLUDED.problem, output_spec = EXCLUDED.output_spec,
+			callback_url = EXCLUDED.callback_url, received_at = EXCLUDED.received_at,
+			attempt_count = EXCLUDED.attempt_count, reason = EXCLUDED.reason, failed_at = EXCLUDED.failed_at`,
+		reason, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter challenge: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to dead-letter challenge: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("pending challenge %s not found", id)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pending_challenges WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to remove dead-lettered challenge: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dead-letter move: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetter returns dead-lettered challenges, most recently failed first.
+// A non-positive limit returns all of them.
+func (p *PostgresSolverDB) ListDeadLetter(ctx context.Context, limit int) ([]*models.FailedChallenge, error) {
+	// Postgres treats LIMIT NULL as unbounded
+	var pgLimit interface{}
+	if limit > 0 {
+		pgLimit = limit
+	}
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, problem, output_spec, callback_url, received_at, attempt_count, reason, failed_at
+		FROM failed_challenges ORDER BY failed_at DESC, id DESC LIMIT $1`, pgLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered challenges: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFailedChallenges(rows)
+}
+
+func (p *PostgresSolverDB) HasSeenNonce(ctx context.Context, nonce string) (bool, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM seen_nonces WHERE nonce = $1", nonce).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check nonce: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (p *PostgresSolverDB) SaveNonce(ctx context.Context, nonce string) error {
+	_, err := p.db.ExecContext(ctx, "INSERT INTO seen_nonces (nonce, seen_at) VALUES ($1, $2)",
+		nonce, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save nonce: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresSolverDB) CleanupOldNonces(ctx context.Context, olderThan time.Time) error {
+	_, err := p.db.ExecContext(ctx, "DELETE FROM seen_nonces WHERE seen_at < $1", olderThan)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup old nonces: %w", err)
+	}
+	return nil
+}
+
+// Truncate deletes every row from the solver tables. Intended for test isolation.
+func (p *PostgresSolverDB) Truncate(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, `TRUNCATE pending_challenges, failed_challenges, seen_nonces, hmac_keys`)
+	if err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresSolverDB) Close() error {
+	return p.db.Close()
+}
@@ -1,10 +1,14 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -60,13 +64,13 @@ func TestSolverDB_SaveChallenge(t *testing.T) {
 
 	challenge := createTestPendingChallenge()
 
-	err := db.SaveChallenge(challenge)
+	err := db.SaveChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to save challenge: %v", err)
 	}
 
 	// Verify it was saved by trying to get it
-	retrieved, err := db.GetChallenge(challenge.ID)
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve challenge: %v", err)
 	}
@@ -89,7 +93,7 @@ func TestSolverDB_GetChallenge(t *testing.T) {
 	defer cleanup()
 
 	// Test getting non-existent challenge
-	retrieved, err := db.GetChallenge("non_existent")
+	retrieved, err := db.GetChallenge(context.Background(), "non_existent")
 	if err != nil {
 		t.Fatalf("Unexpected error when getting non-existent challenge: %v", err)
 	}
@@ -99,13 +103,13 @@ func TestSolverDB_GetChallenge(t *testing.T) {
 
 	// Create challenge
 	challenge := createTestPendingChallenge()
-	err = db.SaveChallenge(challenge)
+	err = db.SaveChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to save challenge: %v", err)
 	}
 
 	// Test getting existing challenge
-	retrieved, err = db.GetChallenge(challenge.ID)
+	retrieved, err = db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve challenge: %v", err)
 	}
@@ -146,7 +150,7 @@ func TestSolverDB_UpdateChallengeStatus(t *testing.T) {
 
 	// Create challenge
 	challenge := createTestPendingChallenge()
-	err := db.SaveChallenge(challenge)
+	err := db.SaveChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to save challenge: %v", err)
 	}
@@ -156,13 +160,13 @@ func TestSolverDB_UpdateChallengeStatus(t *testing.T) {
 	newAttemptCount := 1
 	newNextRetryTime := time.Now().Add(1 * time.Hour)
 
-	err = db.UpdateChallengeStatus(challenge.ID, newStatus, newAttemptCount, newNextRetryTime)
+	err = db.UpdateChallengeStatus(context.Background(), challenge.ID, newStatus, newAttemptCount, newNextRetryTime)
 	if err != nil {
 		t.Fatalf("Failed to update challenge status: %v", err)
 	}
 
 	// Verify update
-	updated, err := db.GetChallenge(challenge.ID)
+	updated, err := db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve updated challenge: %v", err)
 	}
@@ -182,12 +186,69 @@ func TestSolverDB_UpdateChallengeStatus(t *testing.T) {
 	}
 }
 
+func TestSolverDB_ClaimChallenge(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := createTestPendingChallenge()
+	if err := db.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to save challenge: %v", err)
+	}
+
+	// Concurrent claims on the same challenge: exactly one wins
+	const claimers = 10
+	var wg sync.WaitGroup
+	var claimed atomic.Int32
+	for i := 0; i < claimers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := db.ClaimChallenge(ctx, challenge.ID, time.Now().Add(time.Minute))
+			if err != nil {
+				t.Errorf("ClaimChallenge() error: %v", err)
+				return
+			}
+			if ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := claimed.Load(); got != 1 {
+		t.Fatalf("Expected exactly 1 successful claim, got %d", got)
+	}
+
+	retrieved, err := db.GetChallenge(ctx, challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to get challenge: %v", err)
+	}
+	if retrieved.Status != "processing" {
+		t.Errorf("Expected claimed challenge to be processing, got %s", retrieved.Status)
+	}
+	if pending, err := db.GetPendingChallenges(ctx, 10); err != nil || len(pending) != 0 {
+		t.Errorf("Expected a claimed challenge not to be pending, got %d, %v", len(pending), err)
+	}
+
+	// Once the lease has passed the challenge can be claimed again
+	if err := db.UpdateChallengeStatus(ctx, challenge.ID, "processing", 1, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Failed to expire lease: %v", err)
+	}
+	if ok, err := db.ClaimChallenge(ctx, challenge.ID, time.Now().Add(time.Minute)); err != nil || !ok {
+		t.Errorf("Expected an expired claim to be reclaimed, got %v, %v", ok, err)
+	}
+
+	if ok, err := db.ClaimChallenge(ctx, "missing", time.Now().Add(time.Minute)); err != nil || ok {
+		t.Errorf("Expected a missing challenge not to be claimed, got %v, %v", ok, err)
+	}
+}
+
 func TestSolverDB_UpdateNonExistentChallenge(t *testing.T) {
 	db, cleanup := createTestSolverDB(t)
 	defer cleanup()
 
 	// Try to update non-existent challenge - should not fail (UPDATE affects 0 rows)
-	err := db.UpdateChallengeStatus("non_existent", "processing", 1, time.Now())
+	err := db.UpdateChallengeStatus(context.Background(), "non_existent", "processing", 1, time.Now())
 	if err != nil {
 		t.Fatalf("Unexpected error when updating non-existent challenge: %v", err)
 	}
@@ -245,14 +306,14 @@ func TestSolverDB_GetPendingChallenges(t *testing.T) {
 
 	// Save all challenges
 	for _, challenge := range challenges {
-		err := db.SaveChallenge(challenge)
+		err := db.SaveChallenge(context.Background(), challenge)
 		if err != nil {
 			t.Fatalf("Failed to save challenge %s: %v", challenge.ID, err)
 		}
 	}
 
 	// Get pending challenges
-	pending, err := db.GetPendingChallenges(10)
+	pending, err := db.GetPendingChallenges(context.Background(), 10)
 	if err != nil {
 		t.Fatalf("Failed to get pending challenges: %v", err)
 	}
@@ -298,14 +359,14 @@ func TestSolverDB_GetPendingChallenges_WithLimit(t *testing.T) {
 			NextRetryTime: now.Add(-1 * time.Minute),
 		}
 
-		err := db.SaveChallenge(challenge)
+		err := db.SaveChallenge(context.Background(), challenge)
 		if err != nil {
 			t.Fatalf("Failed to save challenge %d: %v", i, err)
 		}
 	}
 
 	// Get with limit of 3
-	pending, err := db.GetPendingChallenges(3)
+	pending, err := db.GetPendingChallenges(context.Background(), 3)
 	if err != nil {
 		t.Fatalf("Failed to get pending challenges: %v", err)
 	}
@@ -322,19 +383,73 @@ func TestSolverDB_GetPendingChallenges_WithLimit(t *testing.T) {
 	}
 }
 
+func TestSolverDB_GetPendingChallenges_Priority(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now()
+	challenges := []struct {
+		id       string
+		age      time.Duration
+		priority int
+	}{
+		{"low_oldest", 3 * time.Hour, 0},
+		{"low_newer", 2 * time.Hour, 0},
+		{"high_newest", time.Minute, 10},
+		{"medium", time.Hour, 5},
+	}
+	for _, c := range challenges {
+		challenge := createTestPendingChallenge()
+		challenge.ID = c.id
+		challenge.ReceivedAt = now.Add(-c.age)
+		challenge.Priority = c.priority
+		if err := db.SaveChallenge(ctx, challenge); err != nil {
+			t.Fatalf("Failed to save challenge %s: %v", c.id, err)
+		}
+	}
+
+	pending, err := db.GetPendingChallenges(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+
+	want := []string{"high_newest", "medium", "low_oldest", "low_newer"}
+	if len(pending) != len(want) {
+		t.Fatalf("Expected %d pending challenges, got %d", len(want), len(pending))
+	}
+	for i, id := range want {
+		if pending[i].ID != id {
+			t.Errorf("Position %d: expected %s, got %s", i, id, pending[i].ID)
+		}
+	}
+	if pending[0].Priority != 10 {
+		t.Errorf("Expected priority 10 to round-trip, got %d", pending[0].Priority)
+	}
+
+	// A backlog larger than the limit still yields the urgent challenge
+	pending, err = db.GetPendingChallenges(ctx, 1)
+	if err != nil {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != "high_newest" {
+		t.Errorf("Expected the high-priority challenge first, got %+v", pending)
+	}
+}
+
 func TestSolverDB_DeleteChallenge(t *testing.T) {
 	db, cleanup := createTestSolverDB(t)
 	defer cleanup()
 
 	// Create challenge
 	challenge := createTestPendingChallenge()
-	err := db.SaveChallenge(challenge)
+	err := db.SaveChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to save challenge: %v", err)
 	}
 
 	// Verify it exists
-	retrieved, err := db.GetChallenge(challenge.ID)
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve challenge: %v", err)
 	}
@@ -343,13 +458,13 @@ func TestSolverDB_DeleteChallenge(t *testing.T) {
 	}
 
 	// Delete it
-	err = db.DeleteChallenge(challenge.ID)
+	err = db.DeleteChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Failed to delete challenge: %v", err)
 	}
 
 	// Verify it's gone
-	retrieved, err = db.GetChallenge(challenge.ID)
+	retrieved, err = db.GetChallenge(context.Background(), challenge.ID)
 	if err != nil {
 		t.Fatalf("Unexpected error when getting deleted challenge: %v", err)
 	}
@@ -363,12 +478,240 @@ func TestSolverDB_DeleteNonExistentChallenge(t *testing.T) {
 	defer cleanup()
 
 	// Try to delete non-existent challenge - should not fail
-	err := db.DeleteChallenge("non_existent")
+	err := db.DeleteChallenge(context.Background(), "non_existent")
 	if err != nil {
 		t.Fatalf("Unexpected error when deleting non-existent challenge: %v", err)
 	}
 }
 
+func TestSolverDB_TimeoutConstraintsRoundTrip(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+
+	challenge := createTestPendingChallenge()
+	challenge.TimeoutMs = 30000
+	challenge.DeadlineTs = time.Now().Add(5 * time.Minute).Unix()
+
+	if err := db.SaveChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("Failed to save challenge: %v", err)
+	}
+
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to get challenge: %v", err)
+	}
+	if retrieved.TimeoutMs != challenge.TimeoutMs || retrieved.DeadlineTs != challenge.DeadlineTs {
+		t.Errorf("Expected timeout %d and deadline %d, got %d and %d",
+			challenge.TimeoutMs, challenge.DeadlineTs, retrieved.TimeoutMs, retrieved.DeadlineTs)
+	}
+}
+
+func TestSolverDB_CallbackRequestIDRoundTrip(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+
+	challenge := createTestPendingChallenge()
+	challenge.CallbackRequestID = "req_stable_1"
+	if err := db.SaveChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("Failed to save challenge: %v", err)
+	}
+
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to get challenge: %v", err)
+	}
+	if retrieved.CallbackRequestID != "req_stable_1" {
+		t.Errorf("Expected callback request ID req_stable_1, got %q", retrieved.CallbackRequestID)
+	}
+
+	pending, err := db.GetPendingChallenges(context.Background(), 10)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+	if pending[0].CallbackRequestID != "req_stable_1" {
+		t.Errorf("Expected pending challenge to carry req_stable_1, got %q", pending[0].CallbackRequestID)
+	}
+}
+
+func TestSolverDB_APIVersionRoundTrip(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+
+	challenge := createTestPendingChallenge()
+	challenge.APIVersion = "v2.2"
+	if err := db.SaveChallenge(context.Background(), challenge); err != nil {
+		t.Fatalf("Failed to save challenge: %v", err)
+	}
+
+	retrieved, err := db.GetChallenge(context.Background(), challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to get challenge: %v", err)
+	}
+	if retrieved.APIVersion != "v2.2" {
+		t.Errorf("Expected API version v2.2, got %q", retrieved.APIVersion)
+	}
+
+	pending, err := db.GetPendingChallenges(context.Background(), 10)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+	if pending[0].APIVersion != "v2.2" {
+		t.Errorf("Expected pending challenge to carry v2.2, got %q", pending[0].APIVersion)
+	}
+}
+
+func TestSolverDB_MigratesLegacySchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy_solver.db")
+
+	// Create the table as it existed before timeout columns were added
+	legacy, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	if _, err := legacy.Exec(`CREATE TABLE pending_challenges (
+		id TEXT PRIMARY KEY,
+		problem TEXT NOT NULL,
+		output_spec TEXT NOT NULL,
+		callback_url TEXT NOT NULL,
+		received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempt_count INTEGER DEFAULT 0,
+		next_retry_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+	if _, err := legacy.Exec(`INSERT INTO pending_challenges (id, problem, output_spec, callback_url, received_at, next_retry_time)
+		VALUES ('legacy_1', '{}', '{}', 'http://localhost/callback', ?, ?)`, time.Now(), time.Now()); err != nil {
+		t.Fatalf("Failed to insert legacy row: %v", err)
+	}
+	legacy.Close()
+
+	db, err := NewSolverDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	defer db.Close()
+
+	retrieved, err := db.GetChallenge(context.Background(), "legacy_1")
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to read legacy row: %v", err)
+	}
+	if retrieved.TimeoutMs != 0 || retrieved.DeadlineTs != 0 {
+		t.Errorf("Expected legacy row to default to no limits, got %d and %d", retrieved.TimeoutMs, retrieved.DeadlineTs)
+	}
+	if retrieved.Priority != 0 {
+		t.Errorf("Expected legacy row to default to priority 0, got %d", retrieved.Priority)
+	}
+	if retrieved.CallbackRequestID != "" {
+		t.Errorf("Expected legacy row to have no callback request ID, got %q", retrieved.CallbackRequestID)
+	}
+	if retrieved.APIVersion != "" {
+		t.Errorf("Expected legacy row to have no API version, got %q", retrieved.APIVersion)
+	}
+
+	// Opening again must not try to re-add the columns
+	db.Close()
+	if reopened, err := NewSolverDB(dbPath); err != nil {
+		t.Errorf("Failed to reopen migrated database: %v", err)
+	} else {
+		reopened.Close()
+	}
+}
+
+func TestSolverDB_CountPendingChallenges(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i, status := range []string{"pending", "processing", "expired"} {
+		challenge := createTestPendingChallenge()
+		challenge.ID = fmt.Sprintf("count_%d", i)
+		challenge.Status = status
+		if err := db.SaveChallenge(ctx, challenge); err != nil {
+			t.Fatalf("Failed to save challenge: %v", err)
+		}
+	}
+
+	count, err := db.CountPendingChallenges(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count pending challenges: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected pending and processing challenges to be counted, got %d", count)
+	}
+}
+
+func TestSolverDB_MoveToDeadLetter(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := createTestPendingChallenge()
+	challenge.AttemptCount = 5
+	if err := db.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to save challenge: %v", err)
+	}
+
+	if err := db.MoveToDeadLetter(ctx, challenge.ID, "callback failed after 6 attempts"); err != nil {
+		t.Fatalf("Failed to dead-letter challenge: %v", err)
+	}
+
+	pending, err := db.GetPendingChallenges(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to get pending challenges: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected dead-lettered challenge to leave the queue, got %d pending", len(pending))
+	}
+	if stored, err := db.GetChallenge(ctx, challenge.ID); err != nil || stored != nil {
+		t.Errorf("Expected pending row to be deleted, got %+v (err %v)", stored, err)
+	}
+
+	failed, err := db.ListDeadLetter(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list dead letters: %v", err)
+	}
+	if len(failed) != 1 {
+		t.Fatalf("Expected 1 dead-lettered challenge, got %d", len(failed))
+	}
+	if failed[0].ID != challenge.ID || failed[0].Reason != "callback failed after 6 attempts" ||
+		failed[0].AttemptCount != 5 || failed[0].CallbackURL != challenge.CallbackURL {
+		t.Errorf("Unexpected dead-letter row: %+v", failed[0])
+	}
+	if string(failed[0].Problem) != string(challenge.Problem) {
+		t.Errorf("Expected problem %s, got %s", challenge.Problem, failed[0].Problem)
+	}
+
+	// Moving an unknown challenge is an error rather than a silent no-op
+	if err := db.MoveToDeadLetter(ctx, "missing", "whatever"); err == nil {
+		t.Error("Expected error when dead-lettering a missing challenge")
+	}
+}
+
+func TestSolverDB_ListDeadLetterLimit(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		challenge := createTestPendingChallenge()
+		challenge.ID = fmt.Sprintf("dead_%d", i)
+		if err := db.SaveChallenge(ctx, challenge); err != nil {
+			t.Fatalf("Failed to save challenge: %v", err)
+		}
+		if err := db.MoveToDeadLetter(ctx, challenge.ID, "gone"); err != nil {
+			t.Fatalf("Failed to dead-letter challenge: %v", err)
+		}
+	}
+
+	if failed, err := db.ListDeadLetter(ctx, 2); err != nil || len(failed) != 2 {
+		t.Errorf("Expected 2 dead letters with limit, got %d (err %v)", len(failed), err)
+	}
+	if failed, err := db.ListDeadLetter(ctx, 0); err != nil || len(failed) != 3 {
+		t.Errorf("Expected all 3 dead letters without limit, got %d (err %v)", len(failed), err)
+	}
+}
+
 func TestSolverDB_NonceOperations(t *testing.T) {
 	db, cleanup := createTestSolverDB(t)
 	defer cleanup()
@@ -376,7 +719,7 @@ func TestSolverDB_NonceOperations(t *testing.T) {
 	nonce := "solver_nonce_123"
 
 	// Check non-existent nonce
-	seen, err := db.HasSeenNonce(nonce)
+	seen, err := db.HasSeenNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to check nonce: %v", err)
 	}
@@ -385,13 +728,13 @@ func TestSolverDB_NonceOperations(t *testing.T) {
 	}
 
 	// Save nonce
-	err = db.SaveNonce(nonce)
+	err = db.SaveNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to save nonce: %v", err)
 	}
 
 	// Check nonce again - should be seen now
-	seen, err = db.HasSeenNonce(nonce)
+	seen, err = db.HasSeenNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to check nonce: %v", err)
 	}
@@ -400,32 +743,62 @@ func TestSolverDB_NonceOperations(t *testing.T) {
 	}
 }
 
+func TestSolverDB_ClaimNonceConcurrent(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+
+	var claimed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			first, err := db.ClaimNonce(context.Background(), "shared_nonce")
+			if err != nil {
+				t.Errorf("Failed to claim nonce: %v", err)
+				return
+			}
+			if first {
+				atomic.AddInt32(&claimed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claimed != 1 {
+		t.Errorf("Expected exactly one claim to succeed, got %d", claimed)
+	}
+	if seen, err := db.HasSeenNonce(context.Background(), "shared_nonce"); err != nil || !seen {
+		t.Errorf("Expected the claimed nonce to be seen (err %v)", err)
+	}
+}
+
 func TestSolverDB_CleanupOldNonces(t *testing.T) {
 	db, cleanup := createTestSolverDB(t)
 	defer cleanup()
 
 	// Save a nonce
 	nonce := "old_solver_nonce"
-	err := db.SaveNonce(nonce)
+	err := db.SaveNonce(context.Background(), nonce)
 	if err != nil {
 		t.Fatalf("Failed to save nonce: %v", err)
 	}
 
 	// Verify it exists
-	seen, _ := db.HasSeenNonce(nonce)
+	seen, _ := db.HasSeenNonce(context.Background(), nonce)
 	if !seen {
 		t.Fatal("Expected nonce to exist")
 	}
 
 	// Clean up nonces older than future time (should clean all)
 	futureTime := time.Now().Add(1 * time.Hour)
-	err = db.CleanupOldNonces(futureTime)
+	err = db.CleanupOldNonces(context.Background(), futureTime)
 	if err != nil {
 		t.Fatalf("Failed to cleanup old nonces: %v", err)
 	}
 
 	// Verify it's gone
-	seen, _ = db.HasSeenNonce(nonce)
+	seen, _ = db.HasSeenNonce(context.Background(), nonce)
 	if seen {
 		t.Error("Expected nonce to be cleaned up")
 	}
@@ -441,7 +814,7 @@ func TestSolverDB_Close(t *testing.T) {
 	}
 
 	// Trying to use closed database should fail
-	err = db.SaveNonce("test")
+	err = db.SaveNonce(context.Background(), "test")
 	if err == nil {
 		t.Error("Expected error when using closed database")
 	}
@@ -454,13 +827,13 @@ func TestSolverDB_SaveDuplicateChallenge(t *testing.T) {
 	challenge := createTestPendingChallenge()
 
 	// Save first time - should succeed
-	err := db.SaveChallenge(challenge)
+	err := db.SaveChallenge(context.Background(), challenge)
 	if err != nil {
 		t.Fatalf("Failed to save challenge first time: %v", err)
 	}
 
 	// Try to save again with same ID - should fail due to PRIMARY KEY constraint
-	err = db.SaveChallenge(challenge)
+	err = db.SaveChallenge(context.Background(), challenge)
 	if err == nil {
 		t.Error("Expected error when saving duplicate challenge")
 	}
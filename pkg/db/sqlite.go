@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// SQLiteOptions tunes the SQLite connections of the challenger and solver stores.
+type SQLiteOptions struct {
+	BusyTimeout  time.Duration // How long a statement waits for a lock before failing with "database is locked"
+	MaxOpenConns int           // Connections in the writer pool; 1 serializes writes in the process (0 is unbounded)
+}
+
+// DefaultSQLiteOptions waits up to 5s for locks and funnels writes through a single connection.
+func DefaultSQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{BusyTimeout: 5 * time.Second, MaxOpenConns: 1}
+}
+
+// sqliteDSN adds the busy timeout to dbPath as a driver parameter, so it applies to
+// every connection in the pool rather than only the one a PRAGMA happens to run on.
+func sqliteDSN(dbPath string, busyTimeout time.Duration) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_busy_timeout=%d", dbPath, sep, busyTimeout.Milliseconds())
+}
+
+// openSQLite opens the SQLite file for reads and writes in WAL mode.
+func openSQLite(dbPath string, opts SQLiteOptions) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", sqliteDSN(dbPath, opts.BusyTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+
+	// Enable WAL mode for better concurrent access; it is stored in the file
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
+	}
+
+	return db, nil
+}
+
+// openSQLiteReadOnly opens the SQLite file in read-only mode.
+// The primary connection keeps the database in WAL mode, so readers are not blocked by
+// writers and the pool is left unbounded.
+func openSQLiteReadOnly(dbPath string, opts SQLiteOptions) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", sqliteDSN("file:"+dbPath+"?mode=ro", opts.BusyTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read-only database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open read-only database: %w", err)
+	}
+
+	return db, nil
+}
+
+// Backup writes a consistent snapshot of the challenger database to destPath, which must not exist.
+func (c *ChallengerDB) Backup(ctx context.Context, destPath string) error {
+	return backupSQLite(ctx, c.db, destPath)
+}
+
+// Vacuum rebuilds the challenger database file and truncates its WAL.
+func (c *ChallengerDB) Vacuum(ctx context.Context) error {
+	return vacuumSQLite(ctx, c.db)
+}
+
+// Backup writes a consistent snapshot of the solver database to destPath, which must not exist.
+func (s *SolverDB) Backup(ctx context.Context, destPath string) error {
+	return backupSQLite(ctx, s.db, destPath)
+}
+
+// Vacuum rebuilds the solver database file and truncates its WAL.
+func (s *SolverDB) Vacuum(ctx context.Context) error {
+	return vacuumSQLite(ctx, s.db)
+}
+
+// backupSQLite copies the database with VACUUM INTO over a separate read-only connection.
+// The copy runs inside one read transaction, so it is consistent, and in WAL mode neither
+// readers nor writers on the store's own pool wait for it.
+func backupSQLite(ctx context.Context, db *sql.DB, destPath string) error {
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination %s already exists", destPath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check backup destination: %w", err)
+	}
+
+	srcPath, err := sqliteFilePath(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	src, err := openSQLiteReadOnly(srcPath, DefaultSQLiteOptions())
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if _, err := src.ExecContext(ctx, "VACUUM INTO ?", destPath); err != nil {
+		os.Remove(destPath)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+// vacuumSQLite reclaims free pages and checkpoints the WAL back to zero length.
+// VACUUM needs the database to itself, so it waits for writers in the busy timeout.
+func vacuumSQLite(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	return nil
+}
+
+// sqliteFilePath returns the file behind the main database, or an error for in-memory databases
+func sqliteFilePath(ctx context.Context, db *sql.DB) (string, error) {
+	var seq int
+	var name, file string
+	if err := db.QueryRowContext(ctx, "PRAGMA database_list").Scan(&seq, &name, &file); err != nil {
+		return "", fmt.Errorf("failed to locate database file: %w", err)
+	}
+	if file == "" {
+		return "", fmt.Errorf("in-memory databases cannot be backed up")
+	}
+	return file, nil
+}
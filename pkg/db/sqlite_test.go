@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+func TestChallengerDB_BackupRestores(t *testing.T) {
+	db, cleanup := createTestChallengerDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	challenge := createTestChallenge()
+	if err := db.CreateChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if err := db.SaveResult(ctx, &models.Result{
+			ChallengeID: challenge.ID,
+			RequestID:   fmt.Sprintf("req_%d", i),
+			Status:      "success",
+			CreatedAt:   time.Now(),
+		}); err != nil {
+			t.Fatalf("Failed to save result: %v", err)
+		}
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := db.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("Failed to back up database: %v", err)
+	}
+
+	// Writes after the snapshot are not in it
+	if err := db.SaveNonce(ctx, "after_backup"); err != nil {
+		t.Fatalf("Failed to save nonce: %v", err)
+	}
+
+	restored, err := NewChallengerDB(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	defer restored.Close()
+
+	var integrity string
+	if err := restored.db.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil || integrity != "ok" {
+		t.Fatalf("Expected the backup to pass the integrity check, got %q (err %v)", integrity, err)
+	}
+
+	got, err := restored.GetChallenge(ctx, challenge.ID)
+	if err != nil {
+		t.Fatalf("Failed to read challenge from backup: %v", err)
+	}
+	if !json.Valid(got.Problem) || got.Type != challenge.Type {
+		t.Errorf("Expected the backed up challenge to match, got %+v", got)
+	}
+	results, err := restored.ListResultsByChallenge(ctx, challenge.ID)
+	if err != nil || len(results) != 50 {
+		t.Errorf("Expected 50 results in the backup, got %d (err %v)", len(results), err)
+	}
+	if seen, err := restored.HasSeenNonce(ctx, "after_backup"); err != nil || seen {
+		t.Errorf("Expected writes after the backup to be missing from it (err %v)", err)
+	}
+}
+
+func TestSolverDB_BackupAndVacuum(t *testing.T) {
+	db, cleanup := createTestSolverDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		if err := db.SaveChallenge(ctx, &models.PendingChallenge{
+			ID:            fmt.Sprintf("ch_%d", i),
+			Problem:       json.RawMessage(`{}`),
+			OutputSpec:    json.RawMessage(`{}`),
+			CallbackURL:   "http://localhost/callback",
+			ReceivedAt:    time.Now(),
+			Status:        "pending",
+			NextRetryTime: time.Now(),
+		}); err != nil {
+			t.Fatalf("Failed to save challenge: %v", err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		if err := db.DeleteChallenge(ctx, fmt.Sprintf("ch_%d", i)); err != nil {
+			t.Fatalf("Failed to delete challenge: %v", err)
+		}
+	}
+
+	if err := db.Vacuum(ctx); err != nil {
+		t.Fatalf("Failed to vacuum database: %v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "solver_backup.db")
+	if err := db.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("Failed to back up database: %v", err)
+	}
+	if err := db.Backup(ctx, backupPath); err == nil {
+		t.Error("Expected backing up over an existing file to fail")
+	}
+
+	restored, err := NewSolverDB(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	defer restored.Close()
+
+	count, err := restored.CountPendingChallenges(ctx)
+	if err != nil || count != 10 {
+		t.Errorf("Expected 10 pending challenges in the backup, got %d (err %v)", count, err)
+	}
+}
+
+func TestBackup_InMemoryDatabaseFails(t *testing.T) {
+	db, err := NewChallengerDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := db.Backup(context.Background(), backupPath); err == nil {
+		t.Error("Expected backing up an in-memory database to fail")
+	}
+	if _, err := os.Stat(backupPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no backup file to be left behind (err %v)", err)
+	}
+}
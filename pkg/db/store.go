@@ -0,0 +1,253 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"reverse-challenge-system/pkg/models"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Supported values for the DB_DRIVER setting
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ErrDuplicateChallenge is returned by CreateChallenge when a challenge with the same ID is already stored
+var ErrDuplicateChallenge = errors.New("challenge already exists")
+
+// NonceStore tracks HMAC nonces for replay protection.
+// Implemented by every challenger and solver store.
+type NonceStore interface {
+	HasSeenNonce(ctx context.Context, nonce string) (bool, error)
+	SaveNonce(ctx context.Context, nonce string) error
+	// ClaimNonce atomically records nonce, reporting false when it was already recorded
+	ClaimNonce(ctx context.Context, nonce string) (bool, error)
+	CleanupOldNonces(ctx context.Context, olderThan time.Time) error
+}
+
+// HMACKeyStore persists HMAC keys added or revoked through the admin endpoint so the
+// changes survive restarts. Implemented by every challenger and solver store.
+type HMACKeyStore interface {
+	SaveHMACKey(ctx context.Context, key *models.HMACKey) error
+	ListHMACKeys(ctx context.Context) ([]*models.HMACKey, error)
+}
+
+// BackupStore snapshots and compacts a database while the service keeps running.
+// Implemented by the SQLite challenger and solver stores; Postgres is backed up with its own tools.
+type BackupStore interface {
+	Backup(ctx context.Context, destPath string) error
+	Vacuum(ctx context.Context) error
+}
+
+// ExportCursor marks the last record of an export page. Exports are ordered by creation
+// time with ties broken by ID; the zero ID at a time starts the page at that time, inclusive.
+type ExportCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// ExportStore pages through results and callback audits in creation order, for
+// POST /admin/export. Implemented by the challenger stores.
+type ExportStore interface {
+	// ListResultsAfter returns up to limit results after the cursor and created before until
+	ListResultsAfter(ctx context.Context, after ExportCursor, until time.Time, limit int) ([]*models.Result, error)
+	// ListWebhookAuditsAfter returns up to limit callback audits after the cursor and created before until
+	ListWebhookAuditsAfter(ctx context.Context, after ExportCursor, until time.Time, limit int) ([]*models.WebhookAudit, error)
+}
+
+// ChallengerStore is the storage used by the challenger service and its middleware.
+type ChallengerStore interface {
+	NonceStore
+	HMACKeyStore
+
+	CreateChallenge(ctx context.Context, challenge *models.Challenge) error
+	GetChallenge(ctx context.Context, id string) (*models.Challenge, error)
+	SetChallengeExpiry(ctx context.Context, id string, expiresAt time.Time) error
+	ListExpiredChallenges(ctx context.Context, now time.Time) ([]*models.Challenge, error)
+	MarkChallengeExpired(ctx context.Context, id string, expiredAt time.Time) (bool, error)
+	SetChallengeCompetitive(ctx context.Context, id string) error
+	CloseChallenge(ctx context.Context, id, requestID, solverAddress string, closedAt time.Time) (bool, error)
+	SaveResult(ctx context.Context, result *models.Result) error
+	SaveResultWithDuplicateCheck(ctx context.Context, result *models.Result) (bool, error)
+	GetResult(ctx context.Context, challengeID, requestID string) (*models.Result, error)
+	ListResultsByChallenge(ctx context.Context, challengeID string) ([]*models.Result, error)
+	ListResults(ctx context.Context, challengeID string, status string, limit, offset int) ([]*models.Result, int, error)
+	GetChallengeStats(ctx context.Context) (models.ChallengerStats, error)
+	GetLeaderboard(ctx context.Context, limit int) ([]models.SolverScore, error)
+	SaveDispatchedJob(ctx context.Context, challengeID, solverJobID string) error
+	HasDispatchedJob(ctx context.Context, challengeID, solverJobID string) (bool, error)
+	DeleteDispatchedJob(ctx context.Context, challengeID, solverJobID string) error
+	SaveCommitment(ctx context.Context, commitment *models.Commitment) error
+	ListCommitmentsBySolver(ctx context.Context, solverAddress string, limit, offset int) ([]*models.SolverCommitment, int, error)
+	SaveWebhookAudit(ctx context.Context, audit *models.WebhookAudit) error
+	ListWebhookAudits(ctx context.Context, challengeID string, limit int) ([]*models.WebhookAudit, error)
+	CleanupOldWebhookAudits(ctx context.Context, olderThan time.Time) error
+	SaveLogEntry(ctx context.Context, entry *models.LogEntry) error
+	GetLogEntry(ctx context.Context, id string) (*models.LogEntry, error)
+	EnqueueCommitmentJob(ctx context.Context, challengeID, requestID string) error
+	ListCommitmentJobs(ctx context.Context, maxAttempts int) ([]*models.CommitmentJob, error)
+	CompleteCommitmentJob(ctx context.Context, challengeID, requestID string) error
+	FailCommitmentJob(ctx context.Context, challengeID, requestID, errMsg string) error
+	EnqueueLogUpload(ctx context.Context, entry *models.LogEntry, errMsg string) error
+	ListLogUploads(ctx context.Context, limit int) ([]*models.PendingLogUpload, error)
+	CompleteLogUpload(ctx context.Context, logID string) error
+	FailLogUpload(ctx context.Context, logID, errMsg string) error
+	EnqueueDispatch(ctx context.Context, dispatch *models.PendingDispatch) error
+	ListDispatches(ctx context.Context, limit int) ([]*models.PendingDispatch, error)
+	CompleteDispatch(ctx context.Context, challengeID, solverURL string) error
+	FailDispatch(ctx context.Context, challengeID, solverURL, errMsg string) error
+	OpenSubmissionWindow(ctx context.Context, challengeID string, closesAt time.Time) (*models.SubmissionWindow, error)
+	GetSubmissionWindow(ctx context.Context, challengeID string) (*models.SubmissionWindow, error)
+	RecordSubmission(ctx context.Context, challengeID, requestID string, score uint64) (bool, error)
+	ListClosedSubmissionWindows(ctx context.Context, now time.Time) ([]*models.SubmissionWindow, error)
+	SettleSubmissionWindow(ctx context.Context, challengeID string, settledAt time.Time) (bool, error)
+	Close() error
+}
+
+// SolverStore is the storage used by the solver service, its workers and middleware.
+type SolverStore interface {
+	NonceStore
+	HMACKeyStore
+
+	SaveChallenge(ctx context.Context, challenge *models.PendingChallenge) error
+	GetChallenge(ctx context.Context, id string) (*models.PendingChallenge, error)
+	UpdateChallengeStatus(ctx context.Context, id, status string, attemptCount int, nextRetryTime time.Time) error
+	ClaimChallenge(ctx context.Context, id string, leaseUntil time.Time) (bool, error)
+	GetPendingChallenges(ctx context.Context, limit int) ([]*models.PendingChallenge, error)
+	CountPendingChallenges(ctx context.Context) (int, error)
+	DeleteChallenge(ctx context.Context, id string) error
+	MoveToDeadLetter(ctx context.Context, id, reason string) error
+	ListDeadLetter(ctx context.Context, limit int) ([]*models.FailedChallenge, error)
+	Close() error
+}
+
+// OpenChallengerStore opens the challenger store for the given driver.
+// For SQLite the dsn is a file path opened with sqliteOpts; for Postgres it is a connection string.
+func OpenChallengerStore(driver, dsn string, sqliteOpts SQLiteOptions) (ChallengerStore, error) {
+	switch driver {
+	case DriverSQLite, "":
+		return NewChallengerDBWithOptions(dsn, sqliteOpts)
+	case DriverPostgres:
+		if dsn == "" {
+			return nil, fmt.Errorf("postgres connection string is required")
+		}
+		return NewPostgresChallengerDB(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// OpenSolverStore opens the solver store for the given driver.
+// For SQLite the dsn is a file path opened with sqliteOpts; for Postgres it is a connection string.
+func OpenSolverStore(driver, dsn string, sqliteOpts SQLiteOptions) (SolverStore, error) {
+	switch driver {
+	case DriverSQLite, "":
+		return NewSolverDBWithOptions(dsn, sqliteOpts)
+	case DriverPostgres:
+		if dsn == "" {
+			return nil, fmt.Errorf("postgres connection string is required")
+		}
+		return NewPostgresSolverDB(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// OpenChallengerReadStore opens a read-only challenger store for reporting queries.
+// For SQLite the dsn is the primary database file, opened with sqliteOpts' busy timeout;
+// for Postgres it is a read replica connection string.
+func OpenChallengerReadStore(driver, dsn string, sqliteOpts SQLiteOptions) (ChallengerStore, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("read-only data source is required")
+	}
+
+	switch driver {
+	case DriverSQLite, "":
+		return NewChallengerReadOnlyDB(dsn, sqliteOpts)
+	case DriverPostgres:
+		return NewPostgresChallengerReadOnlyDB(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// OpenSolverReadStore opens a read-only solver store for reporting queries.
+// For SQLite the dsn is the primary database file, opened with sqliteOpts' busy timeout;
+// for Postgres it is a read replica connection string.
+func OpenSolverReadStore(driver, dsn string, sqliteOpts SQLiteOptions) (SolverStore, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("read-only data source is required")
+	}
+
+	switch driver {
+	case DriverSQLite, "":
+		return NewSolverReadOnlyDB(dsn, sqliteOpts)
+	case DriverPostgres:
+		return NewPostgresSolverReadOnlyDB(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// BountyLedger records the bounties the verifier has paid, so a commitment is only paid once.
+// Implemented by the SQLite challenger store the verifier shares with the initializer.
+type BountyLedger interface {
+	HasBountyTransfer(ctx context.Context, commitmentID, vaultID string) (bool, error)
+	RecordBountyTransfer(ctx context.Context, transfer *models.BountyTransfer) error
+}
+
+// Truncater is implemented by every store so tests can reset state between runs.
+type Truncater interface {
+	Truncate(ctx context.Context) error
+}
+
+// Ensure both backends implement the service store interfaces
+var (
+	_ ChallengerStore = (*ChallengerDB)(nil)
+	_ ChallengerStore = (*PostgresChallengerDB)(nil)
+	_ SolverStore     = (*SolverDB)(nil)
+	_ SolverStore     = (*PostgresSolverDB)(nil)
+
+	_ BountyLedger = (*ChallengerDB)(nil)
+
+	_ ExportStore = (*ChallengerDB)(nil)
+	_ ExportStore = (*PostgresChallengerDB)(nil)
+
+	_ Truncater = (*ChallengerDB)(nil)
+	_ Truncater = (*PostgresChallengerDB)(nil)
+	_ Truncater = (*SolverDB)(nil)
+	_ Truncater = (*PostgresSolverDB)(nil)
+)
+
+// isUniqueViolation reports whether err is a primary key or unique constraint failure of either driver
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// nullTime converts an optional time to a nullable column value, stored in UTC
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// timePtr converts a nullable column value back to an optional time
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,71 @@
+// Package events publishes challenger lifecycle events to a message bus
+// so dashboards and payout services can react without polling the database.
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Supported values for the EVENT_BUS_DRIVER setting
+const (
+	DriverNone = "none"
+	DriverNATS = "nats"
+)
+
+// Event types emitted by the challenger
+const (
+	ChallengeCreated    = "challenge.created"
+	ResultRecorded      = "result.recorded"
+	CommitmentUploaded  = "commitment.uploaded"
+	CommitmentConfirmed = "commitment.confirmed"
+	BountySettled       = "bounty.settled"
+	WindowSettled       = "window.settled"
+	ChallengeExpired    = "challenge.expired"
+)
+
+// Event is the envelope published for every lifecycle point.
+// Fields that do not apply to a given type are omitted from the JSON payload.
+type Event struct {
+	Type          string    `json:"type"`                     // One of the event type constants
+	ChallengeID   string    `json:"challenge_id"`             // Challenge the event belongs to
+	RequestID     string    `json:"request_id,omitempty"`     // Callback request that produced the result (the winner for window.settled)
+	ChallengeType string    `json:"challenge_type,omitempty"` // Challenge type (challenge.created, challenge.expired)
+	Status        string    `json:"status,omitempty"`         // Solver-reported status (result.recorded)
+	IsCorrect     *bool     `json:"is_correct,omitempty"`     // Validation outcome (result.recorded)
+	SolverAddress string    `json:"solver_address,omitempty"` // Sui address of the solver
+	ObjectID      string    `json:"object_id,omitempty"`      // Sui commitment object (commitment.uploaded, commitment.confirmed)
+	VaultID       string    `json:"vault_id,omitempty"`       // Vault credited with the bounty (bounty.settled)
+	OccurredAt    time.Time `json:"occurred_at"`              // When the lifecycle point was reached
+}
+
+// EventPublisher delivers lifecycle events to a message bus.
+// Publish must not block for long; callers treat failures as non-fatal.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+// NopPublisher discards every event. It is the default when no bus is configured.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, event Event) error { return nil }
+
+func (NopPublisher) Close() error { return nil }
+
+// OpenPublisher creates the publisher for the given driver.
+// Events are published on "<subjectPrefix>.<event type>".
+func OpenPublisher(driver, url, subjectPrefix string) (EventPublisher, error) {
+	switch driver {
+	case DriverNone, "":
+		return NopPublisher{}, nil
+	case DriverNATS:
+		if url == "" {
+			return nil, fmt.Errorf("event bus URL is required for nats")
+		}
+		return NewNATSPublisher(url, subjectPrefix)
+	default:
+		return nil, fmt.Errorf("unsupported event bus driver %q", driver)
+	}
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOpenPublisher(t *testing.T) {
+	for _, driver := range []string{"", DriverNone} {
+		publisher, err := OpenPublisher(driver, "", "")
+		if err != nil {
+			t.Fatalf("OpenPublisher(%q) failed: %v", driver, err)
+		}
+		if err := publisher.Publish(context.Background(), Event{Type: ChallengeCreated}); err != nil {
+			t.Errorf("no-op publish failed: %v", err)
+		}
+	}
+
+	if _, err := OpenPublisher(DriverNATS, "", "aibattle"); err == nil {
+		t.Error("expected error for nats without URL")
+	}
+	if _, err := OpenPublisher("kafka", "localhost:9092", "aibattle"); err == nil {
+		t.Error("expected error for unsupported driver")
+	}
+}
+
+func TestNATSPublisherSubject(t *testing.T) {
+	prefixed := &NATSPublisher{subjectPrefix: "aibattle"}
+	if got := prefixed.Subject(ResultRecorded); got != "aibattle.result.recorded" {
+		t.Errorf("expected aibattle.result.recorded, got %s", got)
+	}
+
+	bare := &NATSPublisher{}
+	if got := bare.Subject(BountySettled); got != BountySettled {
+		t.Errorf("expected %s, got %s", BountySettled, got)
+	}
+}
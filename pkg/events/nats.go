@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes events as JSON messages on a NATS server.
+type NATSPublisher struct {
+	conn          *nats.Conn
+	subjectPrefix string
+}
+
+// NewNATSPublisher connects to the NATS server at url.
+// The connection reconnects automatically; messages published while
+// disconnected are buffered by the client.
+func NewNATSPublisher(url, subjectPrefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("reverse-challenge-challenger"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &NATSPublisher{conn: conn, subjectPrefix: subjectPrefix}, nil
+}
+
+// Subject returns the NATS subject an event type is published on.
+func (p *NATSPublisher) Subject(eventType string) string {
+	if p.subjectPrefix == "" {
+		return eventType
+	}
+	return p.subjectPrefix + "." + eventType
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := p.conn.Publish(p.Subject(event.Type), data); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// Close flushes buffered messages and closes the connection.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,65 @@
+// Package httpclient builds the outbound HTTP clients shared by the services and tools.
+// Clients pool connections and bound each phase of a request (dial, TLS handshake,
+// response headers) as well as the request as a whole, from config.HTTPClientConfig.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/netutil"
+)
+
+// keepAlive is the TCP keep-alive period for pooled connections
+const keepAlive = 30 * time.Second
+
+// New returns a client with a transport built by NewTransport and cfg's overall timeout.
+func New(cfg config.HTTPClientConfig) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(cfg),
+		Timeout:   seconds(cfg.TimeoutSecs),
+	}
+}
+
+// NewCallbackClient returns a client for calling untrusted callback URLs. Every connection is
+// checked against policy when it is dialed, redirects are refused, and proxies from the
+// environment are not used, since a proxy would resolve the host itself.
+func NewCallbackClient(cfg config.HTTPClientConfig, policy netutil.CallbackPolicy) *http.Client {
+	transport := NewTransport(cfg)
+	transport.Proxy = nil
+	transport.DialContext = policy.DialContext(newDialer(cfg))
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       seconds(cfg.TimeoutSecs),
+		CheckRedirect: netutil.RefuseRedirect,
+	}
+}
+
+// NewTransport returns a transport configured from cfg. It starts from http.DefaultTransport,
+// so proxy settings from the environment and HTTP/2 are kept.
+func NewTransport(cfg config.HTTPClientConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.DialContext = newDialer(cfg).DialContext
+	transport.TLSHandshakeTimeout = seconds(cfg.TLSHandshakeTimeoutSecs)
+	transport.ResponseHeaderTimeout = seconds(cfg.ResponseHeaderTimeoutSecs)
+	transport.IdleConnTimeout = seconds(cfg.IdleConnTimeoutSecs)
+	transport.MaxIdleConns = cfg.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+
+	return transport
+}
+
+func newDialer(cfg config.HTTPClientConfig) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   seconds(cfg.DialTimeoutSecs),
+		KeepAlive: keepAlive,
+	}
+}
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/config"
+	"reverse-challenge-system/pkg/netutil"
+)
+
+func TestNew_AppliesConfig(t *testing.T) {
+	cfg := config.HTTPClientConfig{
+		TimeoutSecs:               45,
+		DialTimeoutSecs:           3,
+		TLSHandshakeTimeoutSecs:   4,
+		ResponseHeaderTimeoutSecs: 12,
+		IdleConnTimeoutSecs:       60,
+		MaxIdleConns:              50,
+		MaxIdleConnsPerHost:       8,
+	}
+
+	client := New(cfg)
+	if client.Timeout != 45*time.Second {
+		t.Errorf("Expected overall timeout 45s, got %v", client.Timeout)
+	}
+
+	transport := NewTransport(cfg)
+	if transport.TLSHandshakeTimeout != 4*time.Second {
+		t.Errorf("Expected TLS handshake timeout 4s, got %v", transport.TLSHandshakeTimeout)
+	}
+	if transport.ResponseHeaderTimeout != 12*time.Second {
+		t.Errorf("Expected response header timeout 12s, got %v", transport.ResponseHeaderTimeout)
+	}
+	if transport.IdleConnTimeout != 60*time.Second {
+		t.Errorf("Expected idle connection timeout 60s, got %v", transport.IdleConnTimeout)
+	}
+	if transport.MaxIdleConns != 50 || transport.MaxIdleConnsPerHost != 8 {
+		t.Errorf("Expected idle connection limits 50/8, got %d/%d", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.DialContext == nil {
+		t.Error("Expected a dialer with a timeout to be set")
+	}
+	if transport.Proxy == nil {
+		t.Error("Expected proxy settings from the environment to be kept")
+	}
+}
+
+func TestNew_FromLoadedConfig(t *testing.T) {
+	t.Setenv("SHARED_SECRET_KEY", "test-secret")
+	t.Setenv("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", "32")
+	t.Setenv("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT_SECONDS", "7")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	transport := NewTransport(cfg.HTTPClient)
+	if transport.MaxIdleConnsPerHost != 32 {
+		t.Errorf("Expected MaxIdleConnsPerHost 32, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.ResponseHeaderTimeout != 7*time.Second {
+		t.Errorf("Expected response header timeout 7s, got %v", transport.ResponseHeaderTimeout)
+	}
+	if client := New(cfg.HTTPClient); client.Timeout != 30*time.Second {
+		t.Errorf("Expected the default overall timeout 30s, got %v", client.Timeout)
+	}
+}
+
+func TestNewCallbackClient_RefusesRedirects(t *testing.T) {
+	reached := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer internal.Close()
+
+	// An allowed callback host bounces the request to a loopback address it is not allowed to reach
+	target := strings.Replace(internal.URL, "127.0.0.1", "localhost", 1) + "/latest/meta-data"
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target, http.StatusTemporaryRedirect)
+	}))
+	defer allowed.Close()
+
+	client := NewCallbackClient(config.HTTPClientConfig{TimeoutSecs: 5}, netutil.CallbackPolicy{AllowedHosts: []string{"127.0.0.1"}})
+	resp, err := client.Post(allowed.URL+"/callback/ch_1", "application/json", strings.NewReader("{}"))
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Expected the redirect to be refused")
+	}
+	if reached {
+		t.Error("Expected the redirect target never to be contacted")
+	}
+}
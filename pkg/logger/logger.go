@@ -2,6 +2,7 @@
 // Built on top of zerolog for high-performance structured logging with contextual fields.
 // Supports different log levels and provides convenience methods for common use cases.
 // Supports dual output to console and structured log files with timestamped naming.
+// Log files are rotated by size; see SetRotation.
 package logger
 
 import (
@@ -15,16 +16,120 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var (
 	// Global variables for file logging
 	logFileMutex        sync.Mutex
 	sequenceCounter     = make(map[string]int)
-	serviceLoggers      = make(map[ServiceType]*os.File)
+	serviceLoggers      = make(map[ServiceType]*lumberjack.Logger)
 	serviceMultiWriters = make(map[ServiceType]io.Writer)
+	rotation            = DefaultRotation
+	logDir              = DefaultLogDir
+	logFormat           = FormatConsole
+	consoleOut          io.Writer // nil writes to os.Stdout
 )
 
+// Output formats for stdout; log files are always JSON
+const (
+	FormatConsole = "console" // Pretty, colorized output for development
+	FormatJSON    = "json"    // One JSON object per line, for log collectors
+)
+
+// SetFormat selects how logs are written to stdout: FormatConsole or FormatJSON.
+// Call it before Init or InitWithFileLogging; unknown values fall back to FormatConsole.
+func SetFormat(format string) {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	if format != FormatJSON {
+		format = FormatConsole
+	}
+	logFormat = format
+}
+
+// SetConsoleOutput redirects the logs normally written to stdout, e.g. to os.Stderr when
+// stdout carries a command's own output. nil restores os.Stdout. Call it before
+// NewCategoryLogger; loggers that already exist keep their writer.
+func SetConsoleOutput(w io.Writer) {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	consoleOut = w
+}
+
+// consoleWriter returns the destination of console logs.
+// Note: This function assumes the logFileMutex is already locked by the caller
+func consoleWriter() io.Writer {
+	if consoleOut != nil {
+		return consoleOut
+	}
+	return os.Stdout
+}
+
+// stdoutWriter returns the stdout writer for the configured format.
+// Note: This function assumes the logFileMutex is already locked by the caller
+func stdoutWriter() io.Writer {
+	if logFormat == FormatJSON {
+		return consoleWriter()
+	}
+	return zerolog.ConsoleWriter{
+		Out:        consoleWriter(),
+		TimeFormat: time.RFC3339,
+	}
+}
+
+// DefaultLogDir is where log files are written until SetLogDir is called
+const DefaultLogDir = "logs"
+
+// SetLogDir sets the directory log files are written to, cleaned up from and counted in.
+// Call it before InitWithFileLogging; files that are already open stay where they are.
+// An empty path restores DefaultLogDir.
+func SetLogDir(path string) {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	if path == "" {
+		path = DefaultLogDir
+	}
+	logDir = path
+}
+
+// currentLogDir returns the configured log directory
+func currentLogDir() string {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	return logDir
+}
+
+// RotationConfig controls size-based rotation of service log files.
+// Rotated backups keep the .log suffix with a timestamp appended to the base name.
+type RotationConfig struct {
+	MaxSizeMB  int // Size in megabytes at which the active log file is rotated
+	MaxBackups int // Rotated files to keep per log file (0 keeps all)
+	MaxAgeDays int // Days to keep rotated files (0 keeps them regardless of age)
+}
+
+// DefaultRotation is used until SetRotation is called
+var DefaultRotation = RotationConfig{MaxSizeMB: 100, MaxBackups: 5, MaxAgeDays: 7}
+
+// SetRotation configures rotation for log files opened afterwards.
+// Call it before InitWithFileLogging; files that are already open keep their settings.
+func SetRotation(cfg RotationConfig) {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	rotation = cfg
+}
+
+// newRotatingFile returns a writer appending to path that rolls over once it reaches
+// the configured size. Note: This function assumes the logFileMutex is already locked by the caller
+func newRotatingFile(path string) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    rotation.MaxSizeMB,
+		MaxBackups: rotation.MaxBackups,
+		MaxAge:     rotation.MaxAgeDays,
+	}
+}
+
 // LogCategory represents different types of log events
 type LogCategory string
 
@@ -46,11 +151,9 @@ const (
 	Solver     ServiceType = "solver"
 )
 
-// Init initializes the global logger with the specified log level.
-// Sets up console output with pretty formatting for development use.
-// Defaults to info level if an invalid level is provided.
-func Init(level string) {
-	// Set global log level
+// SetLevel changes the level of every logger, including category loggers created earlier.
+// Defaults to info level if an invalid level is provided. Safe to call while logging.
+func SetLevel(level string) {
 	switch strings.ToLower(level) {
 	case "debug":
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
@@ -63,80 +166,98 @@ func Init(level string) {
 	default:
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
+}
 
-	// Configure pretty printing for development
-	log.Logger = log.Output(zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: time.RFC3339,
-	})
+// Init initializes the global logger with the specified log level.
+// Sets up stdout output in the format chosen with SetFormat.
+// Defaults to info level if an invalid level is provided.
+func Init(level string) {
+	SetLevel(level)
+
+	// Pretty printing for development, or raw JSON in production
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	log.Logger = log.Output(stdoutWriter())
 }
 
 // InitWithFileLogging initializes the logger with both console and file output.
-// Creates timestamped log files in the logs/ directory with service information.
+// Creates timestamped log files in the log directory (see SetLogDir) with service information.
 func InitWithFileLogging(level string, service ServiceType) {
-	// Set global log level
-	switch strings.ToLower(level) {
-	case "debug":
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	case "info":
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	case "warn":
-		zerolog.SetGlobalLevel(zerolog.WarnLevel)
-	case "error":
-		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
-	default:
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	}
+	SetLevel(level)
 
 	logFileMutex.Lock()
 	defer logFileMutex.Unlock()
 
-	// Check if we already have a multi-writer for this service
-	if multiWriter, exists := serviceMultiWriters[service]; exists {
-		// Configure global logger with existing multi-writer
-		log.Logger = zerolog.New(multiWriter).With().Timestamp().Logger()
+	multiWriter, err := serviceWriter(service)
+	if err != nil {
+		fmt.Printf("Failed to initialize file logging: %v\n", err)
 		return
 	}
 
+	// Configure logger with multi-writer
+	log.Logger = zerolog.New(multiWriter).With().Timestamp().Logger()
+}
+
+// serviceWriter returns the writer shared by every logger of service, opening its log file
+// on first use so a service never holds more than one file handle.
+// Note: This function assumes the logFileMutex is already locked by the caller
+func serviceWriter(service ServiceType) (io.Writer, error) {
+	// Reuse the existing multi-writer for this service
+	if multiWriter, exists := serviceMultiWriters[service]; exists {
+		return multiWriter, nil
+	}
+
 	// Create logs directory if it doesn't exist
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		fmt.Printf("Failed to create logs directory: %v\n", err)
-		return
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory %s: %w", logDir, err)
 	}
 
-	// Generate log file name
+	// Generate log file name for this service
 	logFileName := generateLogFileName(service)
-	logFilePath := filepath.Join("logs", logFileName)
+	logFilePath := filepath.Join(logDir, logFileName)
 
-	// Open log file
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		fmt.Printf("Failed to open log file %s: %v\n", logFilePath, err)
-		return
-	}
+	// Open log file, rotating by size
+	logFile := newRotatingFile(logFilePath)
 
 	// Store the file handle for this service
 	serviceLoggers[service] = logFile
 
-	// Create console writer for pretty output
-	consoleWriter := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: time.RFC3339,
-	}
-
-	// Create multi-writer: console gets pretty format, file gets JSON
+	// Create multi-writer: stdout gets the configured format, file gets JSON
 	multiWriter := zerolog.MultiLevelWriter(
-		consoleWriter,
+		stdoutWriter(),
 		logFile,
 	)
 
 	// Store the multi-writer for this service
 	serviceMultiWriters[service] = multiWriter
 
-	// Configure logger with multi-writer
-	log.Logger = zerolog.New(multiWriter).With().Timestamp().Logger()
+	fmt.Fprintf(consoleWriter(), "Logging for service %s to file: %s\n", service, logFilePath)
+
+	return multiWriter, nil
+}
+
+// CloseLoggers closes every service log file and forgets the cached writers.
+// The global logger falls back to stdout only; loggers created afterwards open new files.
+// Call it on graceful shutdown.
+func CloseLoggers() error {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+
+	var firstErr error
+	for service, logFile := range serviceLoggers {
+		if err := logFile.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close %s log file: %w", service, err)
+		}
+		delete(serviceLoggers, service)
+	}
+	for service := range serviceMultiWriters {
+		delete(serviceMultiWriters, service)
+	}
+
+	// Loggers still holding a closed file would silently reopen it on the next write
+	log.Logger = zerolog.New(stdoutWriter()).With().Timestamp().Logger()
 
-	fmt.Printf("Logging to file: %s\n", logFilePath)
+	return firstErr
 }
 
 // generateLogFileName creates a timestamped log file name with sequence number.
@@ -165,49 +286,12 @@ func NewCategoryLogger(level string, service ServiceType, category LogCategory)
 	logFileMutex.Lock()
 	defer logFileMutex.Unlock()
 
-	// Check if we already have a multi-writer for this service
-	if multiWriter, exists := serviceMultiWriters[service]; exists {
-		// Return new logger instance using existing multi-writer
-		return zerolog.New(multiWriter).With().Timestamp().Str("service", string(service)).Str("category", string(category)).Logger()
-	}
-
-	// Create logs directory if it doesn't exist
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		fmt.Printf("Failed to create logs directory: %v\n", err)
-		return log.Logger
-	}
-
-	// Generate log file name for this service
-	logFileName := generateLogFileName(service)
-	logFilePath := filepath.Join("logs", logFileName)
-
-	// Open log file
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	multiWriter, err := serviceWriter(service)
 	if err != nil {
-		fmt.Printf("Failed to open log file %s: %v\n", logFilePath, err)
+		fmt.Printf("Failed to initialize file logging: %v\n", err)
 		return log.Logger
 	}
 
-	// Store the file handle for this service
-	serviceLoggers[service] = logFile
-
-	// Create console writer for pretty output
-	consoleWriter := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: time.RFC3339,
-	}
-
-	// Create multi-writer: console gets pretty format, file gets JSON
-	multiWriter := zerolog.MultiLevelWriter(
-		consoleWriter,
-		logFile,
-	)
-
-	// Store the multi-writer for this service
-	serviceMultiWriters[service] = multiWriter
-
-	fmt.Printf("Logging for service %s to file: %s\n", service, logFilePath)
-
 	// Return new logger instance
 	return zerolog.New(multiWriter).With().Timestamp().Str("service", string(service)).Str("category", string(category)).Logger()
 }
@@ -241,9 +325,9 @@ func WithFields(fields map[string]interface{}) zerolog.Logger {
 }
 
 // CleanupOldLogs removes log files older than the specified number of days.
-// Helps prevent logs directory from growing indefinitely.
+// Helps prevent the log directory from growing indefinitely.
 func CleanupOldLogs(daysToKeep int) error {
-	logsDir := "logs"
+	logsDir := currentLogDir()
 	if _, err := os.Stat(logsDir); os.IsNotExist(err) {
 		return nil // No logs directory, nothing to clean
 	}
@@ -274,10 +358,10 @@ func CleanupOldLogs(daysToKeep int) error {
 	})
 }
 
-// GetLogStats returns statistics about log files in the logs directory.
+// GetLogStats returns statistics about log files in the log directory.
 func GetLogStats() (map[string]int, error) {
 	stats := make(map[string]int)
-	logsDir := "logs"
+	logsDir := currentLogDir()
 
 	if _, err := os.Stat(logsDir); os.IsNotExist(err) {
 		return stats, nil
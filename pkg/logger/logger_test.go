@@ -0,0 +1,360 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// quietStdout points os.Stdout at os.DevNull for the rest of the test, silencing the
+// console half of service multi-writers, which capture os.Stdout when created
+func quietStdout(t *testing.T) {
+	t.Helper()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+// forgetService closes and drops the cached writer for service so other tests start fresh
+func forgetService(service ServiceType) {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+	if file, ok := serviceLoggers[service]; ok {
+		file.Close()
+	}
+	delete(serviceLoggers, service)
+	delete(serviceMultiWriters, service)
+}
+
+func TestNewCategoryLogger_RotatesBySize(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	quietStdout(t)
+
+	previous := rotation
+	SetRotation(RotationConfig{MaxSizeMB: 1, MaxBackups: 2})
+	const service ServiceType = "rotation_test"
+	t.Cleanup(func() {
+		SetRotation(previous)
+		forgetService(service)
+	})
+
+	lg := NewCategoryLogger("info", service, General)
+
+	// Roughly 1.5MB of entries pushes the file past the 1MB limit
+	payload := strings.Repeat("x", 1024)
+	for i := 0; i < 1500; i++ {
+		lg.Info().Int("entry", i).Str("payload", payload).Msg("filling log file")
+	}
+
+	entries, err := os.ReadDir("logs")
+	if err != nil {
+		t.Fatalf("Failed to read logs directory: %v", err)
+	}
+
+	var active, backups int
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.Contains(name, string(service)) || filepath.Ext(name) != ".log" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", name, err)
+		}
+		if info.Size() > 1024*1024 {
+			t.Errorf("Expected %s to stay within 1MB, got %d bytes", name, info.Size())
+		}
+		// Backups carry a timestamp after the original base name
+		if strings.Contains(name, "-") {
+			backups++
+		} else {
+			active++
+		}
+	}
+
+	if active != 1 {
+		t.Errorf("Expected one active log file, got %d (%v)", active, entries)
+	}
+	if backups < 1 {
+		t.Errorf("Expected a rotated backup file, got %d (%v)", backups, entries)
+	}
+}
+
+func TestSetLogDir(t *testing.T) {
+	// The working directory must stay untouched
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	quietStdout(t)
+
+	dir := filepath.Join(t.TempDir(), "nested", "logs")
+	SetLogDir(dir)
+	const service ServiceType = "logdir_test"
+	t.Cleanup(func() {
+		SetLogDir("")
+		forgetService(service)
+	})
+
+	lg := NewCategoryLogger("info", service, General)
+	lg.Info().Msg("written to the configured directory")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Expected log directory %s to be created: %v", dir, err)
+	}
+	if len(entries) != 1 || !strings.Contains(entries[0].Name(), string(service)) {
+		t.Fatalf("Expected one %s log file in %s, got %v", service, dir, entries)
+	}
+	logPath := filepath.Join(dir, entries[0].Name())
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "written to the configured directory") {
+		t.Errorf("Expected the entry in %s, got %q", logPath, content)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, DefaultLogDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected no %s directory in the working directory, got %v", DefaultLogDir, err)
+	}
+
+	// GetLogStats and CleanupOldLogs read the same directory
+	stats, err := GetLogStats()
+	if err != nil {
+		t.Fatalf("GetLogStats() unexpected error: %v", err)
+	}
+	total := 0
+	for _, count := range stats {
+		total += count
+	}
+	if total != 1 {
+		t.Errorf("Expected GetLogStats to count 1 file, got %v", stats)
+	}
+
+	old := filepath.Join(dir, "20200101_000000_logdir_test_001.log")
+	if err := os.WriteFile(old, []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write old log: %v", err)
+	}
+	stale := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(old, stale, stale); err != nil {
+		t.Fatalf("Failed to age old log: %v", err)
+	}
+	if err := CleanupOldLogs(7); err != nil {
+		t.Fatalf("CleanupOldLogs() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed, got %v", old, err)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("Expected the current log file to remain: %v", err)
+	}
+}
+
+// captureStdout redirects os.Stdout to a pipe while fn runs and returns what was written
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestSetFormat(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		format  string
+		service ServiceType
+	}{
+		{format: FormatJSON, service: "format_json_test"},
+		{format: FormatConsole, service: "format_console_test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			SetFormat(tt.format)
+			t.Cleanup(func() {
+				SetFormat(FormatConsole)
+				forgetService(tt.service)
+			})
+
+			out := captureStdout(t, func() {
+				lg := NewCategoryLogger("info", tt.service, Request)
+				lg.Info().Str("challenge_id", "c-1").Msg("format check")
+			})
+
+			var logLine string
+			scanner := bufio.NewScanner(strings.NewReader(out))
+			for scanner.Scan() {
+				if strings.Contains(scanner.Text(), "format check") {
+					logLine = scanner.Text()
+				}
+			}
+			if logLine == "" {
+				t.Fatalf("Expected the entry on stdout, got %q", out)
+			}
+
+			if tt.format == FormatJSON {
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(logLine), &entry); err != nil {
+					t.Fatalf("Expected a JSON line, got %q: %v", logLine, err)
+				}
+				if entry["challenge_id"] != "c-1" || entry["category"] != string(Request) {
+					t.Errorf("Unexpected JSON entry: %v", entry)
+				}
+				if strings.Contains(out, "\x1b[") {
+					t.Errorf("Expected no ANSI escapes in json mode, got %q", out)
+				}
+				return
+			}
+
+			if json.Valid([]byte(logLine)) {
+				t.Errorf("Expected pretty output in console mode, got JSON %q", logLine)
+			}
+			if !strings.Contains(logLine, "\x1b[") || !strings.Contains(logLine, "challenge_id=") {
+				t.Errorf("Expected colorized key=value output, got %q", logLine)
+			}
+		})
+	}
+}
+
+func TestSetConsoleOutput(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	const service ServiceType = "console_output_test"
+	var buf bytes.Buffer
+	SetFormat(FormatJSON)
+	SetConsoleOutput(&buf)
+	t.Cleanup(func() {
+		SetFormat(FormatConsole)
+		SetConsoleOutput(nil)
+		forgetService(service)
+	})
+
+	out := captureStdout(t, func() {
+		lg := NewCategoryLogger("info", service, General)
+		lg.Info().Msg("redirected entry")
+	})
+
+	if out != "" {
+		t.Errorf("Expected nothing on stdout, got %q", out)
+	}
+	if !strings.Contains(buf.String(), "redirected entry") {
+		t.Errorf("Expected the entry in the redirected output, got %q", buf.String())
+	}
+}
+
+// openFDs counts the process's open file descriptors
+func openFDs(t *testing.T) int {
+	t.Helper()
+
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skipf("Cannot count file descriptors: %v", err)
+	}
+	return len(entries)
+}
+
+func TestCloseLoggers(t *testing.T) {
+	t.Chdir(t.TempDir())
+	quietStdout(t)
+	t.Cleanup(func() { CloseLoggers() })
+
+	services := []ServiceType{"close_test_a", "close_test_b"}
+	baseline := openFDs(t)
+
+	for round := 0; round < 3; round++ {
+		for _, service := range services {
+			// Both entry points share one writer per service
+			InitWithFileLogging("info", service)
+			for _, category := range []LogCategory{Startup, Request, General} {
+				lg := NewCategoryLogger("info", service, category)
+				lg.Info().Int("round", round).Msg("entry")
+			}
+		}
+
+		if open := openFDs(t); open != baseline+len(services) {
+			t.Errorf("Round %d: expected %d open descriptors with one file per service, got %d", round, baseline+len(services), open)
+		}
+
+		if err := CloseLoggers(); err != nil {
+			t.Fatalf("CloseLoggers() unexpected error: %v", err)
+		}
+		if open := openFDs(t); open != baseline {
+			t.Errorf("Round %d: expected descriptors back to %d after CloseLoggers, got %d", round, baseline, open)
+		}
+		if len(serviceLoggers) != 0 || len(serviceMultiWriters) != 0 {
+			t.Errorf("Round %d: expected cached writers to be cleared", round)
+		}
+	}
+
+	entries, err := os.ReadDir(DefaultLogDir)
+	if err != nil {
+		t.Fatalf("Failed to read logs directory: %v", err)
+	}
+	if len(entries) != 3*len(services) {
+		t.Errorf("Expected one file per service per round, got %d", len(entries))
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	original := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(original) })
+
+	tests := []struct {
+		level string
+		want  zerolog.Level
+	}{
+		{"debug", zerolog.DebugLevel},
+		{"WARN", zerolog.WarnLevel},
+		{"error", zerolog.ErrorLevel},
+		{"info", zerolog.InfoLevel},
+		{"verbose", zerolog.InfoLevel},
+	}
+	for _, tt := range tests {
+		SetLevel(tt.level)
+		if got := zerolog.GlobalLevel(); got != tt.want {
+			t.Errorf("SetLevel(%q): expected global level %v, got %v", tt.level, tt.want, got)
+		}
+	}
+
+	// Loggers created before the change follow the new level
+	var buf bytes.Buffer
+	existing := zerolog.New(&buf)
+	SetLevel("error")
+	existing.Info().Msg("hidden")
+	SetLevel("debug")
+	existing.Debug().Msg("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("Expected only the message logged after SetLevel(\"debug\"), got %q", buf.String())
+	}
+}
@@ -0,0 +1,115 @@
+// Package metrics defines the Prometheus metrics exported by the challenger and solver.
+// Metrics are registered on the default registry and served by Handler on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "aibattle"
+
+// Outcome labels for CallbacksReceived
+const (
+	OutcomeCorrect   = "correct"
+	OutcomeIncorrect = "incorrect"
+	OutcomeFailed    = "failed"
+	OutcomeDuplicate = "duplicate"
+)
+
+// Solver metrics
+var (
+	ChallengesReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "solver",
+		Name:      "challenges_received_total",
+		Help:      "Challenges accepted by the solver API.",
+	})
+
+	ChallengesSolved = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "solver",
+		Name:      "challenges_solved_total",
+		Help:      "Challenges the solver produced an answer for.",
+	})
+
+	ChallengesFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "solver",
+		Name:      "challenges_failed_total",
+		Help:      "Challenges the solver failed to answer, by error code.",
+	}, []string{"error_code"})
+
+	CallbacksSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "solver",
+		Name:      "callbacks_sent_total",
+		Help:      "Callback attempts by HTTP status code (\"error\" when no response was received).",
+	}, []string{"code"})
+
+	SolveDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "solver",
+		Name:      "solve_duration_seconds",
+		Help:      "Time spent solving a challenge.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	CallbackLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "solver",
+		Name:      "callback_latency_seconds",
+		Help:      "Round-trip time of callback requests to the challenger.",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
+
+// Challenger metrics
+var (
+	CallbacksReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "challenger",
+		Name:      "callbacks_received_total",
+		Help:      "Processed solver callbacks by outcome (correct, incorrect, failed, duplicate).",
+	}, []string{"outcome"})
+
+	CallbackHandlingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "challenger",
+		Name:      "callback_handling_seconds",
+		Help:      "Time spent validating and storing a solver callback.",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
+
+// StatusCodeLabel converts a callback status code into a label value.
+// A zero code means the request never got a response.
+func StatusCodeLabel(code int) string {
+	if code == 0 {
+		return "error"
+	}
+	return strconv.Itoa(code)
+}
+
+// CallbackOutcome returns the CallbacksReceived label for a processed callback.
+func CallbackOutcome(status string, isCorrect, isDuplicate bool) string {
+	switch {
+	case isDuplicate:
+		return OutcomeDuplicate
+	case status != "success":
+		return OutcomeFailed
+	case isCorrect:
+		return OutcomeCorrect
+	default:
+		return OutcomeIncorrect
+	}
+}
+
+// Handler serves all registered metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
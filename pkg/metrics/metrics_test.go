@@ -0,0 +1,32 @@
+package metrics
+
+import "testing"
+
+func TestCallbackOutcome(t *testing.T) {
+	tests := []struct {
+		status      string
+		isCorrect   bool
+		isDuplicate bool
+		want        string
+	}{
+		{"success", true, false, OutcomeCorrect},
+		{"success", false, false, OutcomeIncorrect},
+		{"failed", false, false, OutcomeFailed},
+		{"success", true, true, OutcomeDuplicate},
+	}
+
+	for _, tt := range tests {
+		if got := CallbackOutcome(tt.status, tt.isCorrect, tt.isDuplicate); got != tt.want {
+			t.Errorf("CallbackOutcome(%q, %v, %v) = %q, want %q", tt.status, tt.isCorrect, tt.isDuplicate, got, tt.want)
+		}
+	}
+}
+
+func TestStatusCodeLabel(t *testing.T) {
+	if got := StatusCodeLabel(0); got != "error" {
+		t.Errorf("expected \"error\" for a missing response, got %q", got)
+	}
+	if got := StatusCodeLabel(503); got != "503" {
+		t.Errorf("expected \"503\", got %q", got)
+	}
+}
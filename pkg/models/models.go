@@ -4,7 +4,9 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -13,12 +15,13 @@ import (
 // SolveRequest represents the request sent from challenger to solver to process a challenge.
 // Contains all necessary information for the solver to understand and execute the challenge.
 type SolveRequest struct {
-	APIVersion  string          `json:"api_version"`  // API version for compatibility checking
-	ChallengeID string          `json:"challenge_id"` // Unique identifier for the challenge
-	Problem     json.RawMessage `json:"problem"`      // Challenge-specific problem data (JSON)
-	OutputSpec  json.RawMessage `json:"output_spec"`  // Expected output format specification (JSON)
-	Constraints Constraints     `json:"constraints"`  // Execution constraints for the solver
-	CallbackURL string          `json:"callback_url"` // URL where solver should send results
+	APIVersion  string          `json:"api_version"`        // API version for compatibility checking
+	ChallengeID string          `json:"challenge_id"`       // Unique identifier for the challenge
+	Problem     json.RawMessage `json:"problem"`            // Challenge-specific problem data (JSON)
+	OutputSpec  json.RawMessage `json:"output_spec"`        // Expected output format specification (JSON)
+	Constraints Constraints     `json:"constraints"`        // Execution constraints for the solver
+	CallbackURL string          `json:"callback_url"`       // URL where solver should send results
+	Priority    int             `json:"priority,omitempty"` // Higher values are dispatched first by the solver (default 0)
 }
 
 // Constraints defines execution limits and deadlines for challenge processing.
@@ -30,8 +33,18 @@ type Constraints struct {
 // SolveResponse is the immediate response from solver when accepting a challenge.
 // Returns a job ID for tracking the asynchronous processing.
 type SolveResponse struct {
-	Message     string `json:"message"`       // Human-readable response message
-	SolverJobID string `json:"solver_job_id"` // Unique job identifier for tracking
+	Message     string `json:"message"`               // Human-readable response message
+	SolverJobID string `json:"solver_job_id"`         // Unique job identifier for tracking
+	APIVersion  string `json:"api_version,omitempty"` // API version the solver accepted the request under
+}
+
+// ChallengeStatusResponse reports where a challenge is in the solver's queue.
+// NextRetryTime is omitted once the challenge has left the queue.
+type ChallengeStatusResponse struct {
+	ChallengeID   string     `json:"challenge_id"`              // Challenge identifier
+	Status        string     `json:"status"`                    // "pending", "processing", "failed", "expired", or "completed"
+	AttemptCount  int        `json:"attempt_count"`             // Processing attempts made so far
+	NextRetryTime *time.Time `json:"next_retry_time,omitempty"` // When the challenge is next eligible for processing
 }
 
 // CallbackRequest represents the asynchronous result sent from solver to challenger.
@@ -50,9 +63,21 @@ type CallbackRequest struct {
 // CallbackResponse is the challenger's response to a callback request.
 // Confirms receipt and indicates if this was a duplicate submission.
 type CallbackResponse struct {
-	Received    bool   `json:"received"`     // Whether the callback was successfully received
-	ChallengeID string `json:"challenge_id"` // Echo back the challenge ID for confirmation
-	Duplicate   bool   `json:"duplicate"`    // True if this callback was already processed
+	Received    bool   `json:"received"`          // Whether the callback was successfully received
+	ChallengeID string `json:"challenge_id"`      // Echo back the challenge ID for confirmation
+	Duplicate   bool   `json:"duplicate"`         // True if this callback was already processed
+	Late        bool   `json:"late,omitempty"`    // True if another answer had already closed the challenge
+	Correct     *bool  `json:"correct,omitempty"` // Whether the answer was correct; only set when CALLBACK_CORRECTNESS_MODE is enabled
+}
+
+// SolverCommitmentsResponse is a page of on-chain commitments attributed to a solver.
+// Returned by GET /solvers/{address}/commitments.
+type SolverCommitmentsResponse struct {
+	SolverAddress string              `json:"solver_address"` // Solver the commitments belong to
+	Commitments   []*SolverCommitment `json:"commitments"`    // Commitments on this page, newest first
+	Total         int                 `json:"total"`          // Total commitments for the solver
+	Limit         int                 `json:"limit"`          // Page size used for this response
+	Offset        int                 `json:"offset"`         // Offset of the first commitment on this page
 }
 
 // Database Models
@@ -60,7 +85,7 @@ type CallbackResponse struct {
 // ValidationRule defines how to validate a solver's answer against the expected solution.
 // Contains the validation type, parameters, and the correct answer (stored only on challenger).
 type ValidationRule struct {
-	Type   string          `json:"type"`             // Validation type: "ExactMatch", "NumericTolerance", or "Regex"
+	Type   string          `json:"type"`             // Validation type: "ExactMatch", "NumericTolerance", "Regex", "FuzzyMatch", "SetMembership", "JSONSchema", "Composite", or "Pipeline"
 	Params json.RawMessage `json:"params,omitempty"` // Type-specific validation parameters (JSON)
 	Answer string          `json:"answer"`           // Correct answer - stored locally, never sent to solver
 }
@@ -74,6 +99,13 @@ type Challenge struct {
 	OutputSpec     json.RawMessage `json:"output_spec" db:"output_spec"`         // Expected output format (JSON)
 	ValidationRule ValidationRule  `json:"validation_rule" db:"validation_rule"` // How to validate answers
 	CreatedAt      time.Time       `json:"created_at" db:"created_at"`           // Challenge creation timestamp
+	ExpiresAt      *time.Time      `json:"expires_at,omitempty" db:"expires_at"` // Deadline sent to solvers; later callbacks are rejected (nil: never dispatched)
+	ExpiredAt      *time.Time      `json:"expired_at,omitempty" db:"expired_at"` // When the expiry sweep marked the challenge expired
+
+	Competitive         bool       `json:"competitive" db:"competitive"`                               // Sent to several solvers; only the winner is committed
+	WinnerRequestID     string     `json:"winner_request_id,omitempty" db:"winner_request_id"`         // Callback request of the first correct answer
+	WinnerSolverAddress string     `json:"winner_solver_address,omitempty" db:"winner_solver_address"` // Solver that gave the first correct answer
+	ClosedAt            *time.Time `json:"closed_at,omitempty" db:"closed_at"`                         // When the first correct answer closed the challenge
 }
 
 // Result stores the outcome of a challenge after receiving a solver's callback.
@@ -90,6 +122,80 @@ type Result struct {
 	ComputeTimeMs  int             `json:"compute_time_ms" db:"compute_time_ms"` // Solver-reported processing time
 	SolverMetadata json.RawMessage `json:"solver_metadata" db:"solver_metadata"` // Additional solver data (JSON)
 	CreatedAt      time.Time       `json:"created_at" db:"created_at"`           // Result creation timestamp
+	IsLate         bool            `json:"is_late" db:"is_late"`                 // Received after another answer closed the challenge; never committed
+}
+
+// Commitment records a challenge commitment uploaded to Sui for a callback result.
+// Used to attribute on-chain commitments to solvers for payouts and disputes.
+type Commitment struct {
+	ID               int64     `json:"id" db:"id"`                               // Auto-increment primary key
+	ChallengeID      string    `json:"challenge_id" db:"challenge_id"`           // Challenge the commitment belongs to
+	RequestID        string    `json:"request_id" db:"request_id"`               // Request ID of the committed result
+	ObjectID         string    `json:"object_id" db:"object_id"`                 // Sui object ID of the ChallengeCommitment
+	CommitmentHash   string    `json:"commitment_hash" db:"commitment_hash"`     // Hex-encoded commitment hash sent on-chain
+	SettlementStatus string    `json:"settlement_status" db:"settlement_status"` // Settlement state: "pending" or "settled"
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`               // Upload timestamp
+}
+
+// BountyTransfer records a vault bounty the verifier paid for a verified commitment.
+// The verifier checks it before paying so re-running over the same digest never pays twice.
+type BountyTransfer struct {
+	CommitmentID  string    `json:"commitment_id" db:"commitment_id"`   // Sui object ID of the verified ChallengeCommitment
+	VaultID       string    `json:"vault_id" db:"vault_id"`             // Vault the bounty was paid from
+	Digest        string    `json:"digest" db:"digest"`                 // Digest the commitment was verified from
+	SolverAddress string    `json:"solver_address" db:"solver_address"` // Address the bounty was paid to
+	TransferredAt time.Time `json:"transferred_at" db:"transferred_at"` // When the transfer succeeded
+}
+
+// CommitmentJob is a pending Sui commitment upload for a stored result.
+// Jobs are persisted before they are queued so uploads survive a challenger restart.
+type CommitmentJob struct {
+	ChallengeID string    `json:"challenge_id" db:"challenge_id"` // Challenge the result belongs to
+	RequestID   string    `json:"request_id" db:"request_id"`     // Callback request that produced the result
+	Attempts    int       `json:"attempts" db:"attempts"`         // Failed upload attempts so far
+	LastError   string    `json:"last_error" db:"last_error"`     // Error of the most recent failed attempt
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`     // When the job was queued
+}
+
+// SubmissionWindow tracks the acceptance window of a challenge when solvers may submit
+// successive answers. It holds the best-scoring correct answer so far; the winner is
+// committed and paid once, when the window is settled after it closes.
+type SubmissionWindow struct {
+	ChallengeID   string     `json:"challenge_id" db:"challenge_id"`       // Challenge the window belongs to
+	ClosesAt      time.Time  `json:"closes_at" db:"closes_at"`             // Answers are accepted until this time
+	BestRequestID string     `json:"best_request_id" db:"best_request_id"` // Request ID of the current best result (empty if none)
+	BestScore     uint64     `json:"best_score" db:"best_score"`           // Score of the current best result
+	SettledAt     *time.Time `json:"settled_at,omitempty" db:"settled_at"` // When the winner was committed (nil while open)
+}
+
+// SolverCommitment is a commitment joined with the result it was created from.
+// Carries the solver address and the verification outcome of the committed answer.
+type SolverCommitment struct {
+	Commitment
+	SolverAddress string `json:"solver_address"` // Sui address of the solver
+	ResultStatus  string `json:"result_status"`  // Solver status of the result: "success" or "failed"
+	IsCorrect     bool   `json:"is_correct"`     // Whether the committed answer passed validation
+}
+
+// ChallengerStats aggregates challenges and results for the challenger /stats endpoint.
+type ChallengerStats struct {
+	TotalChallenges  int            `json:"total_challenges"`    // Challenges created
+	SolvedChallenges int            `json:"solved_challenges"`   // Challenges with at least one correct result
+	TotalResults     int            `json:"total_results"`       // Callback results recorded
+	StatusBreakdown  map[string]int `json:"status_breakdown"`    // Result count per solver status
+	CorrectResults   int            `json:"correct_results"`     // Results that passed validation
+	IncorrectResults int            `json:"incorrect_results"`   // Results that failed validation
+	CorrectRatio     float64        `json:"correct_ratio"`       // Correct share of all results (0 when there are none)
+	AvgComputeTimeMs float64        `json:"avg_compute_time_ms"` // Mean solver-reported processing time
+}
+
+// SolverScore ranks a solver on the challenger GET /leaderboard endpoint.
+type SolverScore struct {
+	SolverAddress    string  `json:"solver_address"`      // Sui address the solver's callbacks were sent from
+	Attempts         int     `json:"attempts"`            // Results recorded for the solver
+	Correct          int     `json:"correct"`             // Results that passed validation, late ones included
+	AvgComputeTimeMs float64 `json:"avg_compute_time_ms"` // Mean solver-reported processing time
+	Score            uint64  `json:"score"`               // Full score for each correct answer that was not late
 }
 
 // WebhookAudit provides an audit trail of all callback requests received.
@@ -107,14 +213,63 @@ type WebhookAudit struct {
 // PendingChallenge represents a challenge queued for processing in the solver database.
 // Includes retry logic state and processing status tracking.
 type PendingChallenge struct {
-	ID            string          `json:"id" db:"id"`                           // Challenge identifier
-	Problem       json.RawMessage `json:"problem" db:"problem"`                 // Problem data to process (JSON)
-	OutputSpec    json.RawMessage `json:"output_spec" db:"output_spec"`         // Expected output format (JSON)
-	CallbackURL   string          `json:"callback_url" db:"callback_url"`       // URL to send results to
-	ReceivedAt    time.Time       `json:"received_at" db:"received_at"`         // When challenge was received
-	Status        string          `json:"status" db:"status"`                   // Processing status: "pending", "processing", "completed", or "failed"
-	AttemptCount  int             `json:"attempt_count" db:"attempt_count"`     // Number of processing attempts made
-	NextRetryTime time.Time       `json:"next_retry_time" db:"next_retry_time"` // When to retry if processing failed
+	ID                string          `json:"id" db:"id"`                                   // Challenge identifier
+	Problem           json.RawMessage `json:"problem" db:"problem"`                         // Problem data to process (JSON)
+	OutputSpec        json.RawMessage `json:"output_spec" db:"output_spec"`                 // Expected output format (JSON)
+	CallbackURL       string          `json:"callback_url" db:"callback_url"`               // URL to send results to
+	ReceivedAt        time.Time       `json:"received_at" db:"received_at"`                 // When challenge was received
+	Status            string          `json:"status" db:"status"`                           // Processing status: "pending", "processing", "completed", "failed", or "expired"
+	AttemptCount      int             `json:"attempt_count" db:"attempt_count"`             // Number of processing attempts made
+	NextRetryTime     time.Time       `json:"next_retry_time" db:"next_retry_time"`         // When to retry if processing failed
+	TimeoutMs         int             `json:"timeout_ms" db:"timeout_ms"`                   // Per-solve time limit from the request constraints (0 = none)
+	DeadlineTs        int64           `json:"deadline_ts" db:"deadline_ts"`                 // Unix deadline from the request constraints (0 = none)
+	Priority          int             `json:"priority" db:"priority"`                       // Dispatch priority from the request; higher runs first (default 0)
+	CallbackRequestID string          `json:"callback_request_id" db:"callback_request_id"` // X-Request-ID sent with every callback attempt so the challenger can dedup retries
+	APIVersion        string          `json:"api_version" db:"api_version"`                 // API version negotiated with the challenger, echoed in the callback (empty = current)
+}
+
+// LogEntry is a callback log record, uploaded to the external log service and
+// kept locally so verifiers can still fetch it when that service is unavailable.
+type LogEntry struct {
+	ID             string    `json:"id" db:"id"`                                     // Commitment object ID or "challenge_id:request_id"
+	Log            string    `json:"log" db:"log"`                                   // Serialized result (JSON)
+	ChallengerAddr string    `json:"challenger_addr,omitempty" db:"challenger_addr"` // Challenger Sui address
+	SolverAddr     string    `json:"solver_addr,omitempty" db:"solver_addr"`         // Solver Sui address
+	VerifierAddr   string    `json:"verifier_addr,omitempty" db:"verifier_addr"`     // Verifier Sui address
+	CreatedAt      time.Time `json:"-" db:"created_at"`                              // When the entry was stored locally (not part of the log service payload)
+}
+
+// PendingLogUpload is a log entry the log service did not accept after every upload retry.
+// It is kept in pending_log_uploads until the background flusher delivers it.
+type PendingLogUpload struct {
+	Entry     LogEntry  `json:"entry" db:"payload"`         // Entry to upload, stored as its JSON payload
+	Attempts  int       `json:"attempts" db:"attempts"`     // Failed flush attempts since it was queued
+	LastError string    `json:"last_error" db:"last_error"` // Error of the most recent failed attempt
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // When the entry was queued
+}
+
+// PendingDispatch is a challenge the solver did not accept after every dispatch retry.
+// It is kept in pending_dispatches until the background redispatch loop delivers it.
+type PendingDispatch struct {
+	ChallengeID string    `json:"challenge_id" db:"challenge_id"` // Challenge to send
+	SolverURL   string    `json:"solver_url" db:"solver_url"`     // Solver base URL the challenge was sent to
+	Priority    int       `json:"priority" db:"priority"`         // Dispatch priority sent with the challenge
+	Attempts    int       `json:"attempts" db:"attempts"`         // Failed redispatch attempts since it was queued
+	LastError   string    `json:"last_error" db:"last_error"`     // Error of the most recent failed attempt
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`     // When the dispatch was queued
+}
+
+// FailedChallenge is a solver challenge whose callback could not be delivered.
+// Rows are moved here from pending_challenges so the work queue stays small.
+type FailedChallenge struct {
+	ID           string          `json:"id" db:"id"`                       // Challenge identifier
+	Problem      json.RawMessage `json:"problem" db:"problem"`             // Problem data (JSON)
+	OutputSpec   json.RawMessage `json:"output_spec" db:"output_spec"`     // Expected output format (JSON)
+	CallbackURL  string          `json:"callback_url" db:"callback_url"`   // URL the callback was sent to
+	ReceivedAt   time.Time       `json:"received_at" db:"received_at"`     // When challenge was received
+	AttemptCount int             `json:"attempt_count" db:"attempt_count"` // Processing attempts made before giving up
+	Reason       string          `json:"reason" db:"reason"`               // Final error that made the failure permanent
+	FailedAt     time.Time       `json:"failed_at" db:"failed_at"`         // When the challenge was dead-lettered
 }
 
 // SeenNonce tracks used nonces to prevent replay attacks in HMAC authentication.
@@ -124,6 +279,18 @@ type SeenNonce struct {
 	SeenAt time.Time `json:"seen_at" db:"seen_at"` // When this nonce was first seen
 }
 
+// HMACKey is an HMAC key added or revoked at runtime through the admin endpoint.
+// Revoked keys keep their row without the secret so the revocation survives restarts,
+// including for keys that are also configured in the environment.
+type HMACKey struct {
+	KeyID     string     `json:"key_id" db:"key_id"`                   // Key identifier used in the Authorization header
+	Secret    string     `json:"-" db:"secret"`                        // Signing secret; empty once revoked
+	NotBefore *time.Time `json:"not_before,omitempty" db:"not_before"` // Key is rejected before this time (nil: no lower bound)
+	NotAfter  *time.Time `json:"not_after,omitempty" db:"not_after"`   // Key is rejected from this time on (nil: no upper bound)
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"` // When the key was revoked (nil: active)
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`           // Last add or revoke
+}
+
 // Contract represents a deployed smart contract in the database.
 // Tracks deployment information and metadata for idempotent deployments.
 type Contract struct {
@@ -156,15 +323,37 @@ type ErrorDetails struct {
 // Validation Rule Parameters
 
 // ExactMatchParams configures exact string matching validation.
-// Used when answers must match precisely with optional case sensitivity.
+// Used when answers must match precisely with optional case sensitivity and whitespace normalization.
 type ExactMatchParams struct {
-	CaseSensitive bool `json:"case_sensitive"` // Whether to perform case-sensitive comparison
+	CaseSensitive       bool `json:"case_sensitive"`                 // Whether to perform case-sensitive comparison
+	TrimSpace           bool `json:"trim_space,omitempty"`           // Strip leading and trailing whitespace before comparing
+	NormalizeWhitespace bool `json:"normalize_whitespace,omitempty"` // Collapse internal runs of whitespace to a single space
 }
 
 // NumericToleranceParams configures numeric validation with tolerance.
 // Used for mathematical answers that may have slight precision differences.
+// Without the optional fields answers must be plain numbers (scientific notation allowed).
 type NumericToleranceParams struct {
-	Tolerance float64 `json:"tolerance"` // Maximum allowed absolute difference from correct answer
+	Tolerance          float64  `json:"tolerance"`                     // Maximum allowed absolute difference from correct answer
+	Units              []string `json:"units,omitempty"`               // Unit suffixes stripped before parsing, e.g. "km" accepts "42km" and "42 km"
+	ThousandsSeparator bool     `json:"thousands_separator,omitempty"` // Accept commas grouping digits in threes, e.g. "1,000"
+	RequireInteger     bool     `json:"require_integer,omitempty"`     // Reject answers with a fractional part
+	Relative           bool     `json:"relative,omitempty"`            // Compare against a fraction of the expected value instead of an absolute delta
+	RelativeTolerance  float64  `json:"relative_tolerance,omitempty"`  // Fraction used when Relative is set, e.g. 0.01 for 1% (0 uses Tolerance)
+}
+
+// FuzzyMatchParams configures edit-distance matching validation.
+// Used for OCR and transcription answers where small typos are acceptable.
+type FuzzyMatchParams struct {
+	MaxDistance   int  `json:"max_distance"`   // Maximum Levenshtein distance still considered correct
+	CaseSensitive bool `json:"case_sensitive"` // Whether to perform case-sensitive comparison
+}
+
+// SetMembershipParams configures validation against a set of equally correct answers.
+// Used for challenges with synonyms or alternate formats.
+type SetMembershipParams struct {
+	Answers       []string `json:"answers"`        // Accepted answers; the set must not be empty
+	CaseSensitive bool     `json:"case_sensitive"` // Whether to perform case-sensitive comparison
 }
 
 // RegexParams configures regular expression pattern matching validation.
@@ -173,6 +362,33 @@ type RegexParams struct {
 	Pattern string `json:"pattern"` // Regular expression pattern to match against
 }
 
+// JSONSchemaParams configures JSON Schema validation of structured answers.
+// The received answer must be a JSON document that conforms to Schema.
+type JSONSchemaParams struct {
+	Schema json.RawMessage `json:"schema"` // JSON Schema document the answer must satisfy
+}
+
+// CompositeParams configures a rule that combines nested validation rules.
+// Operator "all" requires every rule to pass; "any" requires at least one.
+type CompositeParams struct {
+	Operator string           `json:"operator"` // Combination operator: "all" or "any"
+	Rules    []ValidationRule `json:"rules"`    // Nested rules; rules without an answer inherit the composite answer
+}
+
+// PipelineParams configures an ordered validation pipeline.
+// Stages run in sequence and the first failing rule stage short-circuits the pipeline.
+type PipelineParams struct {
+	Stages []PipelineStage `json:"stages"` // Ordered list of stages to execute
+}
+
+// PipelineStage is a single step in a validation pipeline.
+// A stage either transforms the answer passed to later stages or validates it with a rule.
+type PipelineStage struct {
+	Name      string          `json:"name"`                // Stage name reported when the stage fails
+	Transform string          `json:"transform,omitempty"` // Normalization to apply: "trim", "lowercase", "uppercase", "collapse_whitespace", "remove_whitespace"
+	Rule      *ValidationRule `json:"rule,omitempty"`      // Rule to validate the current answer; inherits the pipeline answer when empty
+}
+
 // Solver Metadata Examples
 
 // SolverMetadata represents additional information provided by solvers in callbacks.
@@ -184,3 +400,41 @@ type SolverMetadata struct {
 	AttemptCount  int                    `json:"attempt_count,omitempty"`   // Number of attempts made
 	Resource      map[string]interface{} `json:"resource,omitempty"`        // Resource usage information
 }
+
+// Validate checks that the reported values are in range: Confidence within 0.0-1.0,
+// ComputeTimeMs and AttemptCount not negative.
+func (m *SolverMetadata) Validate() error {
+	if m.Confidence < 0 || m.Confidence > 1 {
+		return fmt.Errorf("confidence must be between 0.0 and 1.0, got %g", m.Confidence)
+	}
+	if m.ComputeTimeMs < 0 {
+		return fmt.Errorf("compute_time_ms must not be negative, got %d", m.ComputeTimeMs)
+	}
+	if m.AttemptCount < 0 {
+		return fmt.Errorf("attempt_count must not be negative, got %d", m.AttemptCount)
+	}
+	return nil
+}
+
+// ParseSolverMetadata decodes and validates callback metadata. Missing metadata (empty or
+// null) returns nil without an error; fields outside SolverMetadata are ignored.
+func ParseSolverMetadata(raw json.RawMessage) (*SolverMetadata, error) {
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+
+	var metadata SolverMetadata
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// Metadata returns the structured solver metadata stored with the result, or nil if the
+// solver sent none.
+func (r *Result) Metadata() (*SolverMetadata, error) {
+	return ParseSolverMetadata(r.SolverMetadata)
+}
@@ -0,0 +1,193 @@
+// Package netutil provides network safety checks shared by the challenger and solver services.
+// It validates callback URLs against a host allowlist and guards against server-side request
+// forgery by refusing hosts that resolve to private, loopback or link-local addresses.
+package netutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxCallbackURLLength is the longest callback URL accepted
+const MaxCallbackURLLength = 2048
+
+// lookupTimeout bounds the DNS resolution done by the SSRF guard
+const lookupTimeout = 2 * time.Second
+
+// CallbackPolicy decides which callback URLs a service may call.
+//
+// A host in AllowedHosts is trusted explicitly: it may use plain HTTP and may resolve to a
+// private or loopback address. Entries match the URL hostname exactly (case-insensitive);
+// an entry starting with "." matches any subdomain of it, e.g. ".ngrok-free.app".
+// Any other host must use HTTPS and resolve only to public addresses.
+type CallbackPolicy struct {
+	AllowedHosts  []string // Hosts trusted explicitly
+	AllowlistOnly bool     // Reject hosts outside AllowedHosts and require HTTPS for all (USE_NGROK=true)
+
+	// LookupIP resolves hostnames for the SSRF guard; nil uses net.DefaultResolver
+	LookupIP func(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+// ValidateCallbackURL checks callbackURL against the policy and returns a descriptive error
+// when it must not be called.
+func (p CallbackPolicy) ValidateCallbackURL(ctx context.Context, callbackURL string) error {
+	if callbackURL == "" {
+		return fmt.Errorf("callback URL cannot be empty")
+	}
+	if len(callbackURL) > MaxCallbackURLLength {
+		return fmt.Errorf("callback URL too long")
+	}
+
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("callback URL must use HTTP or HTTPS")
+	}
+	if u.User != nil {
+		return fmt.Errorf("callback URL must not contain credentials")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("callback URL has no host")
+	}
+
+	if p.AllowlistOnly && u.Scheme != "https" {
+		return fmt.Errorf("callback URL must use HTTPS when USE_NGROK=true")
+	}
+
+	if p.hostAllowed(host) {
+		return nil
+	}
+	if p.AllowlistOnly {
+		return fmt.Errorf("callback URL host not in CALLBACK_ALLOWED_HOSTS: %s", host)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("HTTP callback URLs are only allowed for hosts in CALLBACK_ALLOWED_HOSTS")
+	}
+
+	return p.checkPublicHost(ctx, host)
+}
+
+// hostAllowed reports whether host matches an AllowedHosts entry
+func (p CallbackPolicy) hostAllowed(host string) bool {
+	for _, entry := range p.AllowedHosts {
+		entry = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(entry)), ".")
+		if entry == "" {
+			continue
+		}
+		// IPv6 entries may be written in brackets like in URLs
+		entry = strings.TrimSuffix(strings.TrimPrefix(entry, "["), "]")
+
+		if strings.HasPrefix(entry, ".") {
+			if strings.HasSuffix(host, entry) && len(host) > len(entry) {
+				return true
+			}
+			continue
+		}
+		if host == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPublicHost resolves host and rejects it if any address is not publicly routable
+func (p CallbackPolicy) checkPublicHost(ctx context.Context, host string) error {
+	_, err := p.publicAddrs(ctx, host)
+	return err
+}
+
+// publicAddrs resolves host and returns its addresses, or an error if any of them is not
+// publicly routable
+func (p CallbackPolicy) publicAddrs(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return nil, fmt.Errorf("callback URL host %s is a non-public address", host)
+		}
+		return []net.IP{ip}, nil
+	}
+
+	lookup := p.LookupIP
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupIP
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	ips, err := lookup(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve callback URL host %s: %w", host, err)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("callback URL host %s has no addresses", host)
+	}
+
+	// Every address must be public, or the client could be steered to an internal one
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return nil, fmt.Errorf("callback URL host %s resolves to non-public address %s", host, ip)
+		}
+	}
+	return ips, nil
+}
+
+// DialContext wraps dialer so every connection is checked against the policy when it is
+// made, not only when the callback URL was accepted. Hosts outside AllowedHosts are resolved
+// once and only the vetted public addresses are dialed, so a DNS answer that changes between
+// the check and the connection (DNS rebinding) cannot reach an internal address.
+func (p CallbackPolicy) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if p.hostAllowed(host) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		if p.AllowlistOnly {
+			return nil, fmt.Errorf("callback host not in CALLBACK_ALLOWED_HOSTS: %s", host)
+		}
+
+		ips, err := p.publicAddrs(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var dialErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		return nil, dialErr
+	}
+}
+
+// RefuseRedirect is an http.Client CheckRedirect that never follows redirects, so a callback
+// host cannot bounce the request to an address the policy would have refused.
+func RefuseRedirect(req *http.Request, via []*http.Request) error {
+	return fmt.Errorf("refusing to follow callback redirect to %s", req.URL.Redacted())
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address, i.e. not loopback,
+// private (RFC 1918, RFC 4193), link-local, multicast or unspecified.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
@@ -0,0 +1,84 @@
+package nonce
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps nonces in process memory. Expired nonces are evicted on every call,
+// so memory is bounded by the request rate over one ttl. Nonces are not shared between
+// replicas and are lost on restart, which is safe only while a restart takes longer than
+// the HMAC clock skew window.
+type MemoryStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time // Nonce -> when it may be accepted again
+	queue   expiryQueue          // Same entries ordered by expiry, for eviction
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{expires: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryStore) CheckAndSet(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, fmt.Errorf("nonce ttl must be positive, got %v", ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evict(now)
+
+	if _, seen := s.expires[nonce]; seen {
+		return false, nil
+	}
+	expiresAt := now.Add(ttl)
+	s.expires[nonce] = expiresAt
+	heap.Push(&s.queue, expiryEntry{nonce: nonce, expiresAt: expiresAt})
+	return true, nil
+}
+
+// Len returns the number of nonces currently remembered.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.expires)
+}
+
+// Close is a no-op for the in-memory store.
+func (s *MemoryStore) Close() error { return nil }
+
+// evict drops every nonce that expired at or before now
+func (s *MemoryStore) evict(now time.Time) {
+	for len(s.queue) > 0 && !s.queue[0].expiresAt.After(now) {
+		entry := heap.Pop(&s.queue).(expiryEntry)
+		delete(s.expires, entry.nonce)
+	}
+}
+
+type expiryEntry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+// expiryQueue is a min-heap of nonces by expiry time
+type expiryQueue []expiryEntry
+
+func (q expiryQueue) Len() int           { return len(q) }
+func (q expiryQueue) Less(i, j int) bool { return q[i].expiresAt.Before(q[j].expiresAt) }
+func (q expiryQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *expiryQueue) Push(x interface{}) { *q = append(*q, x.(expiryEntry)) }
+
+func (q *expiryQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,76 @@
+package nonce
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_DetectsReplay(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if first, err := store.CheckAndSet(ctx, "n1", time.Minute); err != nil || !first {
+		t.Fatalf("CheckAndSet() = %v, %v; want true, nil", first, err)
+	}
+	if first, err := store.CheckAndSet(ctx, "n1", time.Minute); err != nil || first {
+		t.Errorf("Expected a replayed nonce to be rejected, got %v, %v", first, err)
+	}
+	if first, _ := store.CheckAndSet(ctx, "n2", time.Minute); !first {
+		t.Error("Expected a different nonce to be accepted")
+	}
+}
+
+func TestMemoryStore_ExpiresAfterTTL(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.CheckAndSet(ctx, "old", time.Minute)
+	store.CheckAndSet(ctx, "newer", 2*time.Minute)
+
+	now = now.Add(time.Minute)
+	if first, _ := store.CheckAndSet(ctx, "old", time.Minute); !first {
+		t.Error("Expected the nonce to be accepted again after its ttl")
+	}
+	if first, _ := store.CheckAndSet(ctx, "newer", time.Minute); first {
+		t.Error("Expected a nonce within its ttl to still be rejected")
+	}
+
+	// Expired entries are evicted rather than kept until they are seen again
+	now = now.Add(5 * time.Minute)
+	store.CheckAndSet(ctx, "latest", time.Minute)
+	if got := store.Len(); got != 1 {
+		t.Errorf("Expected expired nonces to be evicted, %d remembered", got)
+	}
+}
+
+func TestMemoryStore_ConcurrentCheckAndSet(t *testing.T) {
+	store := NewMemoryStore()
+
+	var firsts int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if first, _ := store.CheckAndSet(context.Background(), "shared", time.Minute); first {
+				atomic.AddInt32(&firsts, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firsts != 1 {
+		t.Errorf("Expected exactly one caller to see the nonce first, got %d", firsts)
+	}
+}
+
+func TestMemoryStore_RejectsNonPositiveTTL(t *testing.T) {
+	if _, err := NewMemoryStore().CheckAndSet(context.Background(), "n1", 0); err == nil {
+		t.Error("Expected an error for a zero ttl")
+	}
+}
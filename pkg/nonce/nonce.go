@@ -0,0 +1,64 @@
+// Package nonce records HMAC request nonces for replay protection. Stores are
+// interchangeable: the service database, process memory, or a shared Redis server.
+package nonce
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"reverse-challenge-system/pkg/db"
+)
+
+// Supported values for the NONCE_STORE setting
+const (
+	DriverDB     = "db"
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// Store remembers nonces for at least ttl so a replayed request is detected.
+// CheckAndSet must be atomic: of two concurrent calls with the same nonce, only one sees it first.
+type Store interface {
+	CheckAndSet(ctx context.Context, nonce string, ttl time.Duration) (firstSeen bool, err error)
+	Close() error
+}
+
+// Open creates the store for the given driver. database backs the db driver and
+// redisURL (redis://[:password@]host:port[/db]) the redis driver.
+func Open(driver, redisURL string, database db.NonceStore) (Store, error) {
+	switch driver {
+	case DriverDB, "":
+		if database == nil {
+			return nil, fmt.Errorf("a database is required for the db nonce store")
+		}
+		return NewDBStore(database), nil
+	case DriverMemory:
+		return NewMemoryStore(), nil
+	case DriverRedis:
+		if redisURL == "" {
+			return nil, fmt.Errorf("redis URL is required for the redis nonce store")
+		}
+		return NewRedisStore(redisURL)
+	default:
+		return nil, fmt.Errorf("unsupported nonce store %q", driver)
+	}
+}
+
+// DBStore keeps nonces in the service database's seen_nonces table. Rows outlive
+// their ttl until the periodic CleanupOldNonces sweep removes them.
+type DBStore struct {
+	db db.NonceStore
+}
+
+// NewDBStore wraps a challenger or solver store.
+func NewDBStore(database db.NonceStore) *DBStore {
+	return &DBStore{db: database}
+}
+
+func (s *DBStore) CheckAndSet(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.db.ClaimNonce(ctx, nonce)
+}
+
+// Close is a no-op; the database is owned and closed by the service.
+func (s *DBStore) Close() error { return nil }
@@ -0,0 +1,73 @@
+package nonce
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reverse-challenge-system/pkg/db"
+)
+
+func TestOpen(t *testing.T) {
+	database, err := db.NewSolverDB(filepath.Join(t.TempDir(), "solver.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	tests := []struct {
+		driver   string
+		redisURL string
+		want     string
+		wantErr  bool
+	}{
+		{driver: "", want: "*nonce.DBStore"},
+		{driver: DriverDB, want: "*nonce.DBStore"},
+		{driver: DriverMemory, want: "*nonce.MemoryStore"},
+		{driver: DriverRedis, redisURL: "redis://localhost:6379", want: "*nonce.RedisStore"},
+		{driver: DriverRedis, wantErr: true},
+		{driver: "memcached", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			store, err := Open(tt.driver, tt.redisURL, database)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Open() unexpected error: %v", err)
+			}
+			defer store.Close()
+			if got := fmt.Sprintf("%T", store); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDBStore_DetectsReplay(t *testing.T) {
+	database, err := db.NewSolverDB(filepath.Join(t.TempDir(), "solver.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	store := NewDBStore(database)
+	ctx := context.Background()
+
+	if first, err := store.CheckAndSet(ctx, "n1", time.Minute); err != nil || !first {
+		t.Fatalf("CheckAndSet() = %v, %v; want true, nil", first, err)
+	}
+	if first, err := store.CheckAndSet(ctx, "n1", time.Minute); err != nil || first {
+		t.Errorf("Expected a replayed nonce to be rejected, got %v, %v", first, err)
+	}
+	if seen, err := database.HasSeenNonce(ctx, "n1"); err != nil || !seen {
+		t.Errorf("Expected the nonce to be stored in seen_nonces, got %v, %v", seen, err)
+	}
+}
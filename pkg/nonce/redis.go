@@ -0,0 +1,189 @@
+package nonce
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisKeyPrefix   = "aibattle:nonce:"
+	redisDialTimeout = 5 * time.Second
+	redisIOTimeout   = 2 * time.Second // Per command when ctx has no earlier deadline
+	redisMaxIdle     = 16              // Idle connections kept for reuse
+)
+
+// RedisStore keeps nonces in Redis so every replica of a service shares one replay window.
+// Each check is a single SET NX PX, which stores the nonce with its ttl only if it is new;
+// Redis expires the keys itself, so no sweep is needed.
+type RedisStore struct {
+	addr     string
+	password string
+	db       int
+	idle     chan *redisConn
+}
+
+// redisConn is one connection speaking RESP2
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewRedisStore creates a store for redis://[:password@]host:port[/db].
+// Connections are opened on first use.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("invalid redis URL %q: expected redis://host:port", rawURL)
+	}
+
+	store := &RedisStore{addr: u.Host, idle: make(chan *redisConn, redisMaxIdle)}
+	if u.Port() == "" {
+		store.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if password, ok := u.User.Password(); ok {
+		store.password = password
+	}
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		if store.db, err = strconv.Atoi(path); err != nil || store.db < 0 {
+			return nil, fmt.Errorf("invalid redis database %q", path)
+		}
+	}
+	return store, nil
+}
+
+func (s *RedisStore) CheckAndSet(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, fmt.Errorf("nonce ttl must be positive, got %v", ttl)
+	}
+	ttlMs := ttl.Milliseconds()
+	if ttlMs < 1 {
+		ttlMs = 1
+	}
+
+	conn, err := s.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	reply, err := conn.do(ctx, "SET", redisKeyPrefix+nonce, "1", "NX", "PX", strconv.FormatInt(ttlMs, 10))
+	if err != nil {
+		conn.conn.Close()
+		return false, fmt.Errorf("redis SET failed: %w", err)
+	}
+	s.put(conn)
+
+	// OK when the key was set, a nil reply when it already existed
+	return reply != nil, nil
+}
+
+// Close closes the idle connections. Connections in use are closed when returned.
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case conn := <-s.idle:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// get reuses an idle connection or dials a new one, authenticating and selecting the database
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: redisDialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	conn := &redisConn{conn: netConn, r: bufio.NewReader(netConn)}
+
+	if s.password != "" {
+		if _, err := conn.do(ctx, "AUTH", s.password); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("redis AUTH failed: %w", err)
+		}
+	}
+	if s.db != 0 {
+		if _, err := conn.do(ctx, "SELECT", strconv.Itoa(s.db)); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("redis SELECT failed: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// put returns a healthy connection to the idle pool, closing it when the pool is full
+func (s *RedisStore) put(conn *redisConn) {
+	select {
+	case s.idle <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+// do sends one command and reads its reply. A nil reply is returned as nil; error replies as errors.
+func (c *redisConn) do(ctx context.Context, args ...string) ([]byte, error) {
+	deadline := time.Now().Add(redisIOTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, cmd.String()); err != nil {
+		return nil, err
+	}
+
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("unexpected redis reply %q", line)
+	}
+}
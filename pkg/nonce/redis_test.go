@@ -0,0 +1,204 @@
+package nonce
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal RESP server supporting AUTH, SELECT and SET key value NX PX ms
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	keys     map[string]time.Time // Key -> expiry
+	commands []string             // Command names in the order received
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakeRedis{listener: listener, password: password, keys: make(map[string]time.Time)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.ToUpper(args[0]))
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			authed = args[1] == f.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "SELECT":
+			reply = "+OK\r\n"
+		case "SET":
+			ms, _ := strconv.Atoi(args[5])
+			if expiry, ok := f.keys[args[1]]; ok && time.Now().Before(expiry) {
+				reply = "$-1\r\n"
+			} else {
+				f.keys[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+				reply = "+OK\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if !authed && strings.ToUpper(args[0]) != "AUTH" {
+			reply = "-NOAUTH Authentication required\r\n"
+		}
+		f.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) url(password string, db int) string {
+	if password != "" {
+		return fmt.Sprintf("redis://:%s@%s/%d", password, f.listener.Addr(), db)
+	}
+	return fmt.Sprintf("redis://%s/%d", f.listener.Addr(), db)
+}
+
+func TestRedisStore_DetectsReplayAndExpires(t *testing.T) {
+	server := newFakeRedis(t, "")
+	store, err := NewRedisStore(server.url("", 0))
+	if err != nil {
+		t.Fatalf("NewRedisStore() unexpected error: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	if first, err := store.CheckAndSet(ctx, "n1", 50*time.Millisecond); err != nil || !first {
+		t.Fatalf("CheckAndSet() = %v, %v; want true, nil", first, err)
+	}
+	if first, err := store.CheckAndSet(ctx, "n1", 50*time.Millisecond); err != nil || first {
+		t.Errorf("Expected a replayed nonce to be rejected, got %v, %v", first, err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if first, err := store.CheckAndSet(ctx, "n1", 50*time.Millisecond); err != nil || !first {
+		t.Errorf("Expected the nonce to be accepted after its ttl, got %v, %v", first, err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if _, ok := server.keys[redisKeyPrefix+"n1"]; !ok {
+		t.Errorf("Expected the key to be stored under %q", redisKeyPrefix)
+	}
+	if got := strings.Join(server.commands, ","); got != "SET,SET,SET" {
+		t.Errorf("Expected one SET per check over a reused connection, got %s", got)
+	}
+}
+
+func TestRedisStore_AuthAndSelect(t *testing.T) {
+	server := newFakeRedis(t, "s3cret")
+
+	store, err := NewRedisStore(server.url("s3cret", 2))
+	if err != nil {
+		t.Fatalf("NewRedisStore() unexpected error: %v", err)
+	}
+	defer store.Close()
+	if first, err := store.CheckAndSet(context.Background(), "n1", time.Minute); err != nil || !first {
+		t.Fatalf("CheckAndSet() = %v, %v; want true, nil", first, err)
+	}
+
+	server.mu.Lock()
+	got := strings.Join(server.commands, ",")
+	server.mu.Unlock()
+	if got != "AUTH,SELECT,SET" {
+		t.Errorf("Expected AUTH and SELECT before SET, got %s", got)
+	}
+
+	bad, err := NewRedisStore(server.url("wrong", 0))
+	if err != nil {
+		t.Fatalf("NewRedisStore() unexpected error: %v", err)
+	}
+	defer bad.Close()
+	if _, err := bad.CheckAndSet(context.Background(), "n2", time.Minute); err == nil {
+		t.Error("Expected an error with the wrong password")
+	}
+}
+
+func TestNewRedisStore_InvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"localhost:6379", "http://localhost:6379", "redis://localhost:6379/x"} {
+		if _, err := NewRedisStore(rawURL); err == nil {
+			t.Errorf("NewRedisStore(%q) expected an error", rawURL)
+		}
+	}
+
+	store, err := NewRedisStore("redis://localhost")
+	if err != nil {
+		t.Fatalf("NewRedisStore() unexpected error: %v", err)
+	}
+	if store.addr != "localhost:6379" {
+		t.Errorf("Expected the default port, got %s", store.addr)
+	}
+}
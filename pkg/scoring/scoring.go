@@ -0,0 +1,26 @@
+// Package scoring computes the score a challenger commits on-chain for a result.
+// The challenger and the verifier share it so both sides agree on the expected value.
+package scoring
+
+import "reverse-challenge-system/pkg/models"
+
+// MaxScore is awarded to a fully correct answer.
+const MaxScore uint64 = 100
+
+// Scorer derives the on-chain score from a recorded result.
+type Scorer interface {
+	Score(result *models.Result) uint64
+}
+
+// CorrectnessScorer awards MaxScore to correct results and zero otherwise.
+type CorrectnessScorer struct{}
+
+func (CorrectnessScorer) Score(result *models.Result) uint64 {
+	if result.IsCorrect {
+		return MaxScore
+	}
+	return 0
+}
+
+// Default is the scorer used when committing and verifying results.
+var Default Scorer = CorrectnessScorer{}
@@ -0,0 +1,18 @@
+package scoring
+
+import (
+	"testing"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+func TestCorrectnessScorer(t *testing.T) {
+	scorer := CorrectnessScorer{}
+
+	if got := scorer.Score(&models.Result{IsCorrect: true}); got != MaxScore {
+		t.Errorf("expected correct result to score %d, got %d", MaxScore, got)
+	}
+	if got := scorer.Score(&models.Result{IsCorrect: false}); got != 0 {
+		t.Errorf("expected incorrect result to score 0, got %d", got)
+	}
+}
@@ -0,0 +1,63 @@
+package sui
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/suicrypto"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Headers carrying the solver's signature over a callback body
+const (
+	CallbackSignatureHeader = "X-Solver-Signature" // Base64 Ed25519 signature
+	CallbackPubkeyHeader    = "X-Solver-Pubkey"    // Base64 Ed25519 public key
+)
+
+// ErrCallbackSignature is returned by VerifyCallbackSignature for a signature that does not
+// verify or a key that does not belong to the claimed address
+var ErrCallbackSignature = errors.New("invalid callback signature")
+
+// SignCallback signs a callback body with the solver's Ed25519 key. The body is signed as a
+// Sui personal message, so the signature can also be checked with standard Sui tooling.
+// Returns the base64 signature and public key for the X-Solver-Signature and X-Solver-Pubkey headers.
+func SignCallback(signer *suisigner.Signer, body []byte) (signature, pubkey string, err error) {
+	if signer.KeypairEd25519 == nil {
+		return "", "", errors.New("callback signing requires an Ed25519 key")
+	}
+	digest := suisigner.SigningDigest(body, suisigner.IntentPersonalMessage())
+	sig, err := signer.KeypairEd25519.Sign(digest)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign callback: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), base64.StdEncoding.EncodeToString(signer.PublicKeyBytes()), nil
+}
+
+// VerifyCallbackSignature checks that signature is a valid SignCallback signature over body
+// made with pubkey, and that pubkey is the Ed25519 key of address.
+func VerifyCallbackSignature(body []byte, signature, pubkey string, address *sui.Address) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: %s is not a base64 Ed25519 signature", ErrCallbackSignature, CallbackSignatureHeader)
+	}
+	key, err := base64.StdEncoding.DecodeString(pubkey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: %s is not a base64 Ed25519 public key", ErrCallbackSignature, CallbackPubkeyHeader)
+	}
+
+	// A Sui address is the BLAKE2b-256 hash of the scheme flag followed by the public key
+	derived := sui.Address(blake2b.Sum256(append([]byte{suicrypto.KeySchemeFlagEd25519.Byte()}, key...)))
+	if address == nil || derived != *address {
+		return fmt.Errorf("%w: public key belongs to %s, not the solver address", ErrCallbackSignature, derived.String())
+	}
+
+	digest := suisigner.SigningDigest(body, suisigner.IntentPersonalMessage())
+	if !ed25519.Verify(key, digest, sig) {
+		return fmt.Errorf("%w: signature does not match the callback body", ErrCallbackSignature)
+	}
+	return nil
+}
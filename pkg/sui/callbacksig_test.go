@@ -0,0 +1,62 @@
+package sui
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/suicrypto"
+)
+
+func TestCallbackSignature(t *testing.T) {
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suicrypto.KeySchemeFlagEd25519)
+	other := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suicrypto.KeySchemeFlagEd25519)
+	body := []byte(`{"challenge_id":"ch_1","answer":"42"}`)
+
+	signature, pubkey, err := SignCallback(signer, body)
+	if err != nil {
+		t.Fatalf("SignCallback() unexpected error: %v", err)
+	}
+	otherSignature, otherPubkey, err := SignCallback(other, body)
+	if err != nil {
+		t.Fatalf("SignCallback() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		pubkey    string
+		wantErr   bool
+	}{
+		{name: "valid", body: body, signature: signature, pubkey: pubkey},
+		{name: "changed body", body: []byte(`{"challenge_id":"ch_1","answer":"43"}`), signature: signature, pubkey: pubkey, wantErr: true},
+		{name: "key of another address", body: body, signature: otherSignature, pubkey: otherPubkey, wantErr: true},
+		{name: "signature from another key", body: body, signature: otherSignature, pubkey: pubkey, wantErr: true},
+		{name: "signature not base64", body: body, signature: "not base64!", pubkey: pubkey, wantErr: true},
+		{name: "short public key", body: body, signature: signature, pubkey: "AAAA", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyCallbackSignature(tt.body, tt.signature, tt.pubkey, signer.Address)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrCallbackSignature) {
+				t.Errorf("Expected ErrCallbackSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSignCallback_RequiresEd25519(t *testing.T) {
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suicrypto.KeySchemeFlagSecp256k1)
+	if _, _, err := SignCallback(signer, []byte("{}")); err == nil {
+		t.Error("Expected an error for a secp256k1 signer")
+	}
+}
@@ -3,12 +3,27 @@ package sui
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/pattonkan/sui-go/sui"
 	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
 	"github.com/rs/zerolog"
 )
 
+// SuiClient is the subset of the Sui RPC client used by TransactionBuilder.
+// *suiclient.ClientImpl satisfies it; tests substitute MockSuiClient.
+type SuiClient interface {
+	GetObject(ctx context.Context, req *suiclient.GetObjectRequest) (*suiclient.SuiObjectResponse, error)
+	GetCoins(ctx context.Context, req *suiclient.GetCoinsRequest) (*suiclient.CoinPage, error)
+	ExecuteTransactionBlock(ctx context.Context, req *suiclient.ExecuteTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error)
+	SignAndExecuteTransaction(ctx context.Context, signer *suisigner.Signer, txBytes sui.Base64, options *suiclient.SuiTransactionBlockResponseOptions) (*suiclient.SuiTransactionBlockResponse, error)
+	GetTransactionBlock(ctx context.Context, req *suiclient.GetTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error)
+	GetChainIdentifier(ctx context.Context) (string, error)
+}
+
+var _ SuiClient = (*suiclient.ClientImpl)(nil)
+
 // GetTransactionBlock fetches transaction details by digest using the official Sui client
 func GetObject(ctx context.Context, rpcURL string, objIdRaw string, logger zerolog.Logger) (*suiclient.SuiObjectResponse, error) {
 	if objIdRaw == "" {
@@ -47,3 +62,106 @@ func GetObject(ctx context.Context, rpcURL string, objIdRaw string, logger zerol
 		},
 	)
 }
+
+// MockSuiClient is an in-memory SuiClient for tests.
+// It serves Objects by object ID and Coins for every owner, records the
+// transaction bytes of every execution, and answers with ExecuteErr or Response.
+// GetTransactionBlock serves the executed transaction once TransactionNotFound lookups have failed.
+type MockSuiClient struct {
+	mu         sync.Mutex
+	Objects    map[string]*suiclient.SuiObjectResponse // Keyed by ObjectId.String()
+	Coins      []*suiclient.Coin
+	Response   *suiclient.SuiTransactionBlockResponse // Defaults to a successful execution
+	ExecuteErr error
+	Executed   []sui.Base64
+
+	ChainID    string // Returned by GetChainIdentifier
+	ChainIDErr error  // Fails GetChainIdentifier when set
+
+	TransactionNotFound int // GetTransactionBlock lookups answered with not-found before the transaction is served
+	TransactionLookups  int // GetTransactionBlock calls so far
+}
+
+// GetObject returns the registered object or an error if it is unknown
+func (m *MockSuiClient) GetObject(ctx context.Context, req *suiclient.GetObjectRequest) (*suiclient.SuiObjectResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.Objects[req.ObjectId.String()]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", req.ObjectId)
+	}
+	return obj, nil
+}
+
+// GetCoins returns Coins regardless of owner
+func (m *MockSuiClient) GetCoins(ctx context.Context, req *suiclient.GetCoinsRequest) (*suiclient.CoinPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return &suiclient.CoinPage{Data: m.Coins}, nil
+}
+
+// GetChainIdentifier returns ChainID, or ChainIDErr when it is set
+func (m *MockSuiClient) GetChainIdentifier(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ChainIDErr != nil {
+		return "", m.ChainIDErr
+	}
+	return m.ChainID, nil
+}
+
+// GetTransactionBlock answers not-found for the first TransactionNotFound lookups, then
+// serves Response (or the default successful execution) under the requested digest
+func (m *MockSuiClient) GetTransactionBlock(ctx context.Context, req *suiclient.GetTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.TransactionLookups++
+	if m.TransactionLookups <= m.TransactionNotFound {
+		return nil, fmt.Errorf("Could not find the referenced transaction [TransactionDigest(%s)]", req.Digest)
+	}
+
+	resp := *m.response()
+	resp.Digest = *req.Digest
+	return &resp, nil
+}
+
+// ExecuteTransactionBlock records the transaction bytes and returns the configured outcome
+func (m *MockSuiClient) ExecuteTransactionBlock(ctx context.Context, req *suiclient.ExecuteTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error) {
+	return m.execute(req.TxDataBytes)
+}
+
+// SignAndExecuteTransaction records the transaction bytes and returns the configured outcome
+func (m *MockSuiClient) SignAndExecuteTransaction(ctx context.Context, signer *suisigner.Signer, txBytes sui.Base64, options *suiclient.SuiTransactionBlockResponseOptions) (*suiclient.SuiTransactionBlockResponse, error) {
+	return m.execute(txBytes)
+}
+
+func (m *MockSuiClient) execute(txBytes sui.Base64) (*suiclient.SuiTransactionBlockResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Executed = append(m.Executed, txBytes)
+	if m.ExecuteErr != nil {
+		return nil, m.ExecuteErr
+	}
+	return m.response(), nil
+}
+
+// response returns Response, or a successful execution when it is unset
+func (m *MockSuiClient) response() *suiclient.SuiTransactionBlockResponse {
+	if m.Response != nil {
+		return m.Response
+	}
+	return &suiclient.SuiTransactionBlockResponse{
+		Effects: &suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+			Data: suiclient.SuiTransactionBlockEffects{
+				V1: &suiclient.SuiTransactionBlockEffectsV1{
+					Status: suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package sui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// Errors returned by TransactionBuilder, matched with errors.Is. The returned errors keep
+// their detailed messages; these only classify them.
+var (
+	ErrNoCoins         = errors.New("no SUI coins")                 // The signer owns no (or too few) coins to pay for gas
+	ErrInsufficientGas = errors.New("insufficient gas")             // The gas coin cannot cover the gas budget
+	ErrTxFailed        = errors.New("transaction failed")           // The transaction executed with a failure status
+	ErrInvalidAddress  = errors.New("invalid address or object ID") // An address or object ID could not be parsed
+)
+
+// classifiedError tags err with one of the sentinel errors without changing its message
+type classifiedError struct {
+	sentinel error
+	err      error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() []error { return []error{e.sentinel, e.err} }
+
+// classify tags err so errors.Is(err, sentinel) reports true
+func classify(sentinel, err error) error {
+	return &classifiedError{sentinel: sentinel, err: err}
+}
+
+// invalidAddress reports that the named address or object ID failed to parse
+func invalidAddress(what string, err error) error {
+	return classify(ErrInvalidAddress, fmt.Errorf("invalid %s: %w", what, err))
+}
+
+// executionError tags a failed execution RPC whose node error says the gas coin is too small
+func executionError(err error) error {
+	if isInsufficientGas(err.Error()) {
+		return classify(ErrInsufficientGas, err)
+	}
+	return err
+}
+
+// txFailed reports a transaction that executed with a failure status
+func txFailed(resp *suiclient.SuiTransactionBlockResponse) error {
+	var status suiclient.ExecutionStatus
+	if resp.Effects != nil && resp.Effects.Data.V1 != nil {
+		status = resp.Effects.Data.V1.Status
+	}
+	err := fmt.Errorf("%w with status: %s %s", ErrTxFailed, status.Status, status.Error)
+	if isInsufficientGas(status.Error) {
+		return classify(ErrInsufficientGas, err)
+	}
+	return err
+}
+
+// isInsufficientGas matches the node's messages for gas it cannot charge: InsufficientGas
+// effects, and the GasBalanceTooLow check done before execution
+func isInsufficientGas(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, marker := range []string{"insufficientgas", "insufficient gas", "gasbalancetoolow", "lower than the needed amount"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
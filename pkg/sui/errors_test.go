@@ -0,0 +1,141 @@
+package sui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// failedResponse is an executed transaction whose effects report errMsg
+func failedResponse(errMsg string) *suiclient.SuiTransactionBlockResponse {
+	return &suiclient.SuiTransactionBlockResponse{
+		Effects: &suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+			Data: suiclient.SuiTransactionBlockEffects{
+				V1: &suiclient.SuiTransactionBlockEffectsV1{
+					Status: suiclient.ExecutionStatus{Status: "failure", Error: errMsg},
+				},
+			},
+		},
+	}
+}
+
+func TestTransactionBuilder_ErrorsMatchSentinels(t *testing.T) {
+	sentinels := []error{ErrNoCoins, ErrInsufficientGas, ErrTxFailed, ErrInvalidAddress}
+
+	tests := []struct {
+		name  string
+		setup func(client *MockSuiClient)
+		call  func(tb *TransactionBuilder) error
+		want  []error
+	}{
+		{
+			name:  "no gas coins",
+			setup: func(client *MockSuiClient) { client.Coins = nil },
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+			},
+			want: []error{ErrNoCoins},
+		},
+		{
+			name:  "too few coins for the bounty and gas",
+			setup: func(client *MockSuiClient) {},
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultAddBounty(context.Background(), testVaultID)
+			},
+			want: []error{ErrNoCoins},
+		},
+		{
+			name:  "no coins to select",
+			setup: func(client *MockSuiClient) { client.Coins = nil },
+			call: func(tb *TransactionBuilder) error {
+				_, err := tb.SelectGasObject(context.Background(), testSolverAddr)
+				return err
+			},
+			want: []error{ErrNoCoins},
+		},
+		{
+			name: "gas balance too low",
+			setup: func(client *MockSuiClient) {
+				client.ExecuteErr = errors.New("Balance of gas object 0xc0 is lower than the needed amount: 10000000")
+			},
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+			},
+			want: []error{ErrInsufficientGas},
+		},
+		{
+			name:  "out of gas during execution",
+			setup: func(client *MockSuiClient) { client.Response = failedResponse("InsufficientGas") },
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+			},
+			want: []error{ErrTxFailed, ErrInsufficientGas},
+		},
+		{
+			name:  "move abort",
+			setup: func(client *MockSuiClient) { client.Response = failedResponse("MoveAbort(ctf_registry, 3)") },
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+			},
+			want: []error{ErrTxFailed},
+		},
+		{
+			name:  "invalid solver address",
+			setup: func(client *MockSuiClient) {},
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, "not-hex")
+			},
+			want: []error{ErrInvalidAddress},
+		},
+		{
+			name:  "invalid vault object ID",
+			setup: func(client *MockSuiClient) {},
+			call: func(tb *TransactionBuilder) error {
+				return tb.VaultAddBounty(context.Background(), "not-hex")
+			},
+			want: []error{ErrInvalidAddress},
+		},
+		{
+			name:  "invalid commitment object ID",
+			setup: func(client *MockSuiClient) {},
+			call: func(tb *TransactionBuilder) error {
+				_, err := tb.GetChallengeCommitment(context.Background(), "not-hex")
+				return err
+			},
+			want: []error{ErrInvalidAddress},
+		},
+		{
+			name:  "invalid batch item address",
+			setup: func(client *MockSuiClient) {},
+			call: func(tb *TransactionBuilder) error {
+				_, err := tb.UploadChallengeCommitmentsBatch(context.Background(), []CommitmentItem{{RegistryID: testVaultID, ChallengerAddr: "0x1", SolverAddr: "not-hex"}})
+				return err
+			},
+			want: []error{ErrInvalidAddress},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb, client := newVaultTestBuilder(t)
+			tb.SetRPCMaxRetries(0)
+			tt.setup(client)
+
+			err := tt.call(tb)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, sentinel := range sentinels {
+				want := false
+				for _, w := range tt.want {
+					want = want || w == sentinel
+				}
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(%q, %v) = %v, want %v", err, sentinel, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package sui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// Move event emitted by ctf_registry::upload_challenge_commitment
+const (
+	commitmentEventModule = "ctf_events"
+	commitmentEventName   = "CommitmentUploaded"
+)
+
+// EventSubscriber is the Sui event subscription RPC (suix_subscribeEvent).
+// A *suiclient.ClientImpl dialed with DialEventSubscriber satisfies it; tests substitute MockEventSubscriber.
+type EventSubscriber interface {
+	SubscribeEvent(ctx context.Context, filter *suiclient.EventFilter, resultCh chan suiclient.Event) error
+}
+
+var _ EventSubscriber = (*suiclient.ClientImpl)(nil)
+
+// DialEventSubscriber opens a websocket connection to the Sui node for event subscriptions.
+func DialEventSubscriber(ctx context.Context, wsURL string) (client *suiclient.ClientImpl, err error) {
+	if wsURL == "" {
+		return nil, fmt.Errorf("wsURL cannot be empty")
+	}
+
+	// The websocket client panics when the dial fails
+	defer func() {
+		if r := recover(); r != nil {
+			client, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	return suiclient.NewSuiWebsocketClient(ctx, wsURL), nil
+}
+
+// CommitmentEvent is a CommitmentUploaded event confirming a ChallengeCommitment on chain.
+// The embedded payload's Id is the ChallengeCommitment object.
+type CommitmentEvent struct {
+	CommitmentPayload
+	TxDigest string // Transaction that emitted the event
+}
+
+// SetEventSubscriber enables SubscribeCommitmentEvents
+func (tb *TransactionBuilder) SetEventSubscriber(subscriber EventSubscriber) {
+	tb.subscriber = subscriber
+}
+
+// CommitmentEventType is the Move type of the commitment event of our package
+func (tb *TransactionBuilder) CommitmentEventType() *sui.StructTag {
+	return &sui.StructTag{
+		Address: tb.packageID,
+		Module:  commitmentEventModule,
+		Name:    commitmentEventName,
+	}
+}
+
+// SubscribeCommitmentEvents streams the commitment events emitted for registryID.
+// Events of other registries and events that fail to decode are dropped. The channel
+// is closed when ctx is cancelled.
+func (tb *TransactionBuilder) SubscribeCommitmentEvents(ctx context.Context, registryID string) (<-chan CommitmentEvent, error) {
+	if tb.subscriber == nil {
+		return nil, fmt.Errorf("event subscription not configured")
+	}
+	registry, err := sui.ObjectIdFromHex(registryID)
+	if err != nil {
+		return nil, classify(ErrInvalidAddress, fmt.Errorf("invalid registry ID %q: %w", registryID, err))
+	}
+
+	raw := make(chan suiclient.Event, 16)
+	if err := tb.subscriber.SubscribeEvent(ctx, &suiclient.EventFilter{MoveEventType: tb.CommitmentEventType()}, raw); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to commitment events: %w", err)
+	}
+
+	tb.logger.Info().
+		Str("registry_id", registryID).
+		Str("event_type", tb.CommitmentEventType().String()).
+		Msg("Subscribed to commitment events")
+
+	out := make(chan CommitmentEvent, 16)
+	go func() {
+		defer close(out)
+		for {
+			var ev suiclient.Event
+			select {
+			case <-ctx.Done():
+				return
+			case ev = <-raw:
+			}
+
+			event, err := DecodeCommitmentEvent(&ev)
+			if err != nil {
+				tb.logger.Warn().Err(err).Str("tx_digest", ev.Id.TxDigest.String()).Msg("Dropping undecodable commitment event")
+				continue
+			}
+			if event.RegistryId == nil || *event.RegistryId != *registry {
+				continue
+			}
+
+			select {
+			case out <- *event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// DecodeCommitmentEvent decodes the BCS contents of a CommitmentUploaded event.
+// The event has the same field layout as the ChallengeCommitment object, whose id it carries.
+func DecodeCommitmentEvent(ev *suiclient.Event) (*CommitmentEvent, error) {
+	if ev == nil || len(ev.Bcs) == 0 {
+		return nil, fmt.Errorf("event has no BCS contents")
+	}
+
+	var event CommitmentEvent
+	if _, err := bcs.Unmarshal(ev.Bcs, &event.CommitmentPayload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal to CommitmentEvent: %w", err)
+	}
+	event.TxDigest = ev.Id.TxDigest.String()
+	return &event, nil
+}
+
+// MockEventSubscriber is an in-memory EventSubscriber for tests.
+// Every subscription receives Events in order; SubscribeErr fails the subscription.
+type MockEventSubscriber struct {
+	mu           sync.Mutex
+	Events       []suiclient.Event
+	SubscribeErr error
+	Filters      []*suiclient.EventFilter // Filters of every subscription
+}
+
+// SubscribeEvent records the filter and delivers Events on resultCh until ctx is cancelled
+func (m *MockEventSubscriber) SubscribeEvent(ctx context.Context, filter *suiclient.EventFilter, resultCh chan suiclient.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Filters = append(m.Filters, filter)
+	if m.SubscribeErr != nil {
+		return m.SubscribeErr
+	}
+
+	events := append([]suiclient.Event(nil), m.Events...)
+	go func() {
+		for _, ev := range events {
+			select {
+			case resultCh <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
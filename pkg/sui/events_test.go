@@ -0,0 +1,131 @@
+package sui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	suiTypes "github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+const (
+	testEventRegistryID = "0x00000000000000000000000000000000000000000000000000000000000000a1"
+	testEventTxDigest   = "22222222222222222222222222222222"
+)
+
+// commitmentEvent wraps payload in a Sui event as the subscription RPC delivers it
+func commitmentEvent(t *testing.T, tb *TransactionBuilder, payload CommitmentPayload) suiclient.Event {
+	t.Helper()
+
+	b, err := bcs.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+	return suiclient.Event{
+		Id:                suiclient.EventId{TxDigest: *suiTypes.MustNewDigest(testEventTxDigest)},
+		PackageId:         tb.PackageId(),
+		TransactionModule: "ctf_registry",
+		Type:              tb.CommitmentEventType(),
+		Bcs:               b,
+	}
+}
+
+func TestSubscribeCommitmentEvents(t *testing.T) {
+	tb, _ := newVaultTestBuilder(t)
+
+	known := CommitmentPayload{
+		Id:             suiTypes.MustObjectIdFromHex("0xc1"),
+		RegistryId:     suiTypes.MustObjectIdFromHex(testEventRegistryID),
+		ChallengerAddr: tb.Signer().Address,
+		SolverAddr:     suiTypes.MustAddressFromHex(testSolverAddr),
+		Score:          42,
+		Timestamp:      1700000000,
+		Commitment:     []byte("commitment"),
+	}
+	otherRegistry := known
+	otherRegistry.Id = suiTypes.MustObjectIdFromHex("0xc2")
+	otherRegistry.RegistryId = suiTypes.MustObjectIdFromHex("0xa2")
+
+	subscriber := &MockEventSubscriber{Events: []suiclient.Event{
+		{Id: suiclient.EventId{TxDigest: *suiTypes.MustNewDigest(testEventTxDigest)}, Bcs: []byte{0x01}},
+		commitmentEvent(t, tb, otherRegistry),
+		commitmentEvent(t, tb, known),
+	}}
+	tb.SetEventSubscriber(subscriber)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := tb.SubscribeCommitmentEvents(ctx, testEventRegistryID)
+	if err != nil {
+		t.Fatalf("SubscribeCommitmentEvents() unexpected error: %v", err)
+	}
+
+	if len(subscriber.Filters) != 1 || subscriber.Filters[0].MoveEventType == nil {
+		t.Fatalf("expected one subscription filtered on the event type, got %+v", subscriber.Filters)
+	}
+	if got, want := subscriber.Filters[0].MoveEventType.String(), "0x0000000000000000000000001234567890abcdef1234567890abcdef12345678::ctf_events::CommitmentUploaded"; got != want {
+		t.Errorf("expected filter on %s, got %s", want, got)
+	}
+
+	// The undecodable event and the other registry's event are dropped
+	select {
+	case event := <-events:
+		if *event.Id != *known.Id || *event.RegistryId != *known.RegistryId {
+			t.Errorf("expected the commitment %s, got %s", known.Id, event.Id)
+		}
+		if *event.SolverAddr != *known.SolverAddr || event.Score != 42 || event.Timestamp != 1700000000 || !bytes.Equal(event.Commitment, known.Commitment) {
+			t.Errorf("expected the event payload to match, got %+v", event.CommitmentPayload)
+		}
+		if event.TxDigest != testEventTxDigest {
+			t.Errorf("expected tx digest %s, got %s", testEventTxDigest, event.TxDigest)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the known commitment event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected no further events")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the channel to close after cancellation")
+	}
+}
+
+func TestSubscribeCommitmentEvents_Errors(t *testing.T) {
+	tb, _ := newVaultTestBuilder(t)
+	ctx := context.Background()
+
+	if _, err := tb.SubscribeCommitmentEvents(ctx, testEventRegistryID); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("expected an error without a subscriber, got %v", err)
+	}
+
+	tb.SetEventSubscriber(&MockEventSubscriber{})
+	if _, err := tb.SubscribeCommitmentEvents(ctx, "not-hex"); err == nil || !strings.Contains(err.Error(), "invalid registry ID") {
+		t.Errorf("expected an invalid registry error, got %v", err)
+	}
+
+	tb.SetEventSubscriber(&MockEventSubscriber{SubscribeErr: errors.New("connection closed")})
+	if _, err := tb.SubscribeCommitmentEvents(ctx, testEventRegistryID); err == nil || !strings.Contains(err.Error(), "failed to subscribe") {
+		t.Errorf("expected a subscription error, got %v", err)
+	}
+}
+
+func TestDialEventSubscriber_Unreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := DialEventSubscriber(ctx, "ws://127.0.0.1:1"); err == nil {
+		t.Error("expected dialing an unreachable node to fail")
+	}
+	if _, err := DialEventSubscriber(ctx, ""); err == nil {
+		t.Error("expected an empty URL to fail")
+	}
+}
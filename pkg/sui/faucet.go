@@ -0,0 +1,250 @@
+package sui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultFaucetAttempts     = 3                // Default number of faucet requests before giving up
+	DefaultFaucetRetryDelay   = 2 * time.Second  // Default delay before the first retry, doubled per retry
+	DefaultFaucetBalanceWait  = 30 * time.Second // Default time to wait for funds to show up after a request
+	DefaultFaucetPollInterval = time.Second      // Default interval between balance reads while waiting
+
+	// DefaultFaucetMinBalance is the balance (in MIST) a funded wallet must reach: ten times the
+	// default gas budget, enough for a handful of transactions
+	DefaultFaucetMinBalance uint64 = 10 * suiclient.DefaultGasBudget
+)
+
+// Faucet requests test tokens for an address
+type Faucet interface {
+	Fund(ctx context.Context, address *sui.Address) error
+}
+
+// BalanceChecker reports the SUI balance of an address
+type BalanceChecker interface {
+	Balance(ctx context.Context, address *sui.Address) (uint64, error)
+}
+
+// HTTPFaucet funds addresses through a Sui faucet HTTP endpoint
+type HTTPFaucet struct {
+	url string
+}
+
+// NewHTTPFaucet creates a faucet backed by the given endpoint URL
+func NewHTTPFaucet(url string) *HTTPFaucet {
+	return &HTTPFaucet{url: url}
+}
+
+// Fund requests tokens for the address from the faucet endpoint
+func (f *HTTPFaucet) Fund(ctx context.Context, address *sui.Address) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := suiclient.RequestFundFromFaucet(address, f.url); err != nil {
+		return fmt.Errorf("faucet request to %s failed: %w", f.url, err)
+	}
+	return nil
+}
+
+// ClientBalanceChecker reads balances through a Sui RPC client
+type ClientBalanceChecker struct {
+	client *suiclient.ClientImpl
+}
+
+// NewClientBalanceChecker creates a balance checker using the given client
+func NewClientBalanceChecker(client *suiclient.ClientImpl) *ClientBalanceChecker {
+	return &ClientBalanceChecker{client: client}
+}
+
+// Balance returns the total SUI balance owned by the address
+func (c *ClientBalanceChecker) Balance(ctx context.Context, address *sui.Address) (uint64, error) {
+	balance, err := c.client.GetBalance(ctx, &suiclient.GetBalanceRequest{Owner: address})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if balance.TotalBalance == nil {
+		return 0, nil
+	}
+	return balance.TotalBalance.Uint64(), nil
+}
+
+// Funder coordinates faucet funding with an optional balance threshold and retries
+type Funder struct {
+	Faucet       Faucet         // Faucet used to request tokens
+	Balances     BalanceChecker // Optional balance source; funding always runs when nil
+	MinBalance   uint64         // Skip funding when the balance is at least this amount, and wait for it after a request
+	MaxAttempts  int            // Number of faucet requests before giving up
+	RetryDelay   time.Duration  // Delay before the first retry, doubled per retry
+	BalanceWait  time.Duration  // How long to poll for MinBalance after a request before asking again
+	PollInterval time.Duration  // Delay between balance reads while waiting
+	Logger       zerolog.Logger
+}
+
+// NewFunder creates a funder with default retry settings
+func NewFunder(faucet Faucet, balances BalanceChecker, minBalance uint64, logger zerolog.Logger) *Funder {
+	return &Funder{
+		Faucet:       faucet,
+		Balances:     balances,
+		MinBalance:   minBalance,
+		MaxAttempts:  DefaultFaucetAttempts,
+		RetryDelay:   DefaultFaucetRetryDelay,
+		BalanceWait:  DefaultFaucetBalanceWait,
+		PollInterval: DefaultFaucetPollInterval,
+		Logger:       logger,
+	}
+}
+
+// FundFromFaucetWithRetry funds address from the faucet at faucetURL unless it already holds
+// minBalance. Faucet requests are retried with backoff, and after each one the balance is
+// polled through balances until it reaches minBalance. It fails once the attempts are used
+// up or ctx expires.
+func FundFromFaucetWithRetry(ctx context.Context, address *sui.Address, faucetURL string, minBalance uint64, balances BalanceChecker, logger zerolog.Logger) error {
+	if faucetURL == "" {
+		return fmt.Errorf("faucetURL cannot be empty")
+	}
+	funder := NewFunder(NewHTTPFaucet(faucetURL), balances, minBalance, logger)
+	_, err := funder.EnsureFunded(ctx, address)
+	return err
+}
+
+// EnsureFunded requests faucet funds unless the address already holds MinBalance.
+// With a balance source and threshold, a request only counts once the balance reaches
+// MinBalance; faucets often answer before the coins land, or not at all when rate limited.
+// Returns true if the faucet was called successfully, false if funding was skipped.
+func (f *Funder) EnsureFunded(ctx context.Context, address *sui.Address) (bool, error) {
+	if f.Balances != nil && f.MinBalance > 0 {
+		balance, err := f.Balances.Balance(ctx, address)
+		if err != nil {
+			f.Logger.Warn().Err(err).Str("address", address.String()).Msg("Failed to read balance, requesting funds anyway")
+		} else if balance >= f.MinBalance {
+			f.Logger.Debug().
+				Str("address", address.String()).
+				Uint64("balance", balance).
+				Uint64("min_balance", f.MinBalance).
+				Msg("Balance above threshold, skipping faucet")
+			return false, nil
+		}
+	}
+
+	attempts := f.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var lastErr error
+	delay := f.RetryDelay
+	for attempt := 1; attempt <= attempts; attempt++ {
+		lastErr = f.Faucet.Fund(ctx, address)
+		if lastErr == nil {
+			lastErr = f.waitForBalance(ctx, address)
+		}
+		if lastErr == nil {
+			return true, nil
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		f.Logger.Warn().Err(lastErr).
+			Str("address", address.String()).
+			Int("attempt", attempt).
+			Int("max_attempts", attempts).
+			Msg("Faucet request failed")
+
+		if attempt == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	return false, fmt.Errorf("faucet funding failed after %d attempts: %w", attempts, lastErr)
+}
+
+// waitForBalance polls the balance until it reaches MinBalance. It returns nil right away
+// when there is no balance source or threshold, and an error once BalanceWait passes.
+func (f *Funder) waitForBalance(ctx context.Context, address *sui.Address) error {
+	if f.Balances == nil || f.MinBalance == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(f.BalanceWait)
+	var balance uint64
+	for {
+		var err error
+		balance, err = f.Balances.Balance(ctx, address)
+		if err == nil && balance >= f.MinBalance {
+			return nil
+		}
+		if err != nil {
+			f.Logger.Debug().Err(err).Str("address", address.String()).Msg("Failed to read balance while waiting for funds")
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.PollInterval):
+		}
+	}
+	return fmt.Errorf("balance %d still below %d after %s", balance, f.MinBalance, f.BalanceWait)
+}
+
+// MockFaucet is an in-memory Faucet for tests.
+// It fails the first FailTimes calls with Err and records every funded address.
+// It is also a BalanceChecker: the balance is StartBalance plus Amount per successful call.
+type MockFaucet struct {
+	mu           sync.Mutex
+	FailTimes    int
+	Err          error
+	Calls        int
+	Funded       []*sui.Address
+	StartBalance uint64
+	Amount       uint64
+}
+
+// Fund records the call and fails while FailTimes has not been exhausted
+func (m *MockFaucet) Fund(ctx context.Context, address *sui.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls++
+	if m.Calls <= m.FailTimes {
+		if m.Err != nil {
+			return m.Err
+		}
+		return fmt.Errorf("mock faucet failure %d", m.Calls)
+	}
+	m.Funded = append(m.Funded, address)
+	return nil
+}
+
+// Balance returns StartBalance plus Amount for every successful funding
+func (m *MockFaucet) Balance(ctx context.Context, address *sui.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.StartBalance + m.Amount*uint64(len(m.Funded)), nil
+}
+
+// StaticBalance is a BalanceChecker that always reports the same balance
+type StaticBalance uint64
+
+// Balance returns the static balance
+func (b StaticBalance) Balance(ctx context.Context, address *sui.Address) (uint64, error) {
+	return uint64(b), nil
+}
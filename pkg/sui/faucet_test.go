@@ -0,0 +1,150 @@
+package sui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	suiTypes "github.com/pattonkan/sui-go/sui"
+	"github.com/rs/zerolog"
+)
+
+func TestFunder_SkipsWhenBalanceAboveThreshold(t *testing.T) {
+	faucet := &MockFaucet{}
+	funder := NewFunder(faucet, StaticBalance(5_000_000_000), 1_000_000_000, zerolog.Nop())
+	address := suiTypes.MustAddressFromHex("0x1")
+
+	funded, err := funder.EnsureFunded(context.Background(), address)
+	if err != nil {
+		t.Fatalf("EnsureFunded() unexpected error: %v", err)
+	}
+
+	if funded {
+		t.Error("EnsureFunded() expected funding to be skipped")
+	}
+
+	if faucet.Calls != 0 {
+		t.Errorf("Expected 0 faucet calls, got %d", faucet.Calls)
+	}
+}
+
+func TestFunder_FundsWhenBalanceBelowThreshold(t *testing.T) {
+	faucet := &MockFaucet{StartBalance: 10, Amount: 1_000_000_000}
+	funder := NewFunder(faucet, faucet, 1_000_000_000, zerolog.Nop())
+	address := suiTypes.MustAddressFromHex("0x1")
+
+	funded, err := funder.EnsureFunded(context.Background(), address)
+	if err != nil {
+		t.Fatalf("EnsureFunded() unexpected error: %v", err)
+	}
+
+	if !funded {
+		t.Error("EnsureFunded() expected funding to happen")
+	}
+
+	if len(faucet.Funded) != 1 || faucet.Funded[0].String() != address.String() {
+		t.Errorf("Expected address %s to be funded once, got %v", address, faucet.Funded)
+	}
+}
+
+func TestFunder_RetriesUntilSuccess(t *testing.T) {
+	faucet := &MockFaucet{FailTimes: 2}
+	funder := NewFunder(faucet, nil, 0, zerolog.Nop())
+	funder.RetryDelay = 0
+
+	funded, err := funder.EnsureFunded(context.Background(), suiTypes.MustAddressFromHex("0x1"))
+	if err != nil {
+		t.Fatalf("EnsureFunded() unexpected error: %v", err)
+	}
+
+	if !funded {
+		t.Error("EnsureFunded() expected funding to succeed after retries")
+	}
+
+	if faucet.Calls != 3 {
+		t.Errorf("Expected 3 faucet calls, got %d", faucet.Calls)
+	}
+}
+
+func TestFunder_RetriesAndWaitsForBalance(t *testing.T) {
+	faucet := &MockFaucet{FailTimes: 1, Err: errors.New("429 Too Many Requests"), Amount: 1_000_000_000}
+	funder := NewFunder(faucet, faucet, 1_000_000_000, zerolog.Nop())
+	funder.RetryDelay = time.Millisecond
+	funder.PollInterval = time.Millisecond
+
+	funded, err := funder.EnsureFunded(context.Background(), suiTypes.MustAddressFromHex("0x1"))
+	if err != nil {
+		t.Fatalf("EnsureFunded() unexpected error: %v", err)
+	}
+
+	if !funded {
+		t.Error("EnsureFunded() expected funding to succeed on the second try")
+	}
+
+	if faucet.Calls != 2 {
+		t.Errorf("Expected 2 faucet calls, got %d", faucet.Calls)
+	}
+
+	if balance, _ := faucet.Balance(context.Background(), suiTypes.MustAddressFromHex("0x1")); balance < funder.MinBalance {
+		t.Errorf("Expected balance to reach %d, got %d", funder.MinBalance, balance)
+	}
+}
+
+func TestFunder_RetriesWhenBalanceNeverArrives(t *testing.T) {
+	faucet := &MockFaucet{}
+	funder := NewFunder(faucet, StaticBalance(10), 1_000_000_000, zerolog.Nop())
+	funder.MaxAttempts = 2
+	funder.RetryDelay = 0
+	funder.BalanceWait = 5 * time.Millisecond
+	funder.PollInterval = time.Millisecond
+
+	funded, err := funder.EnsureFunded(context.Background(), suiTypes.MustAddressFromHex("0x1"))
+	if err == nil || !strings.Contains(err.Error(), "still below") {
+		t.Fatalf("EnsureFunded() expected a balance error, got %v", err)
+	}
+
+	if funded {
+		t.Error("EnsureFunded() expected funding to fail")
+	}
+
+	if faucet.Calls != 2 {
+		t.Errorf("Expected 2 faucet calls, got %d", faucet.Calls)
+	}
+}
+
+func TestFundFromFaucetWithRetry_ContextExpires(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := FundFromFaucetWithRetry(ctx, suiTypes.MustAddressFromHex("0x1"), "http://127.0.0.1:1/gas", 1, nil, zerolog.Nop())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FundFromFaucetWithRetry() expected context.Canceled, got %v", err)
+	}
+
+	if err := FundFromFaucetWithRetry(context.Background(), suiTypes.MustAddressFromHex("0x1"), "", 1, nil, zerolog.Nop()); err == nil {
+		t.Error("FundFromFaucetWithRetry() expected an error for an empty faucet URL")
+	}
+}
+
+func TestFunder_GivesUpAfterMaxAttempts(t *testing.T) {
+	faucetErr := errors.New("faucet unavailable")
+	faucet := &MockFaucet{FailTimes: 10, Err: faucetErr}
+	funder := NewFunder(faucet, nil, 0, zerolog.Nop())
+	funder.MaxAttempts = 4
+	funder.RetryDelay = 0
+
+	funded, err := funder.EnsureFunded(context.Background(), suiTypes.MustAddressFromHex("0x1"))
+	if !errors.Is(err, faucetErr) {
+		t.Fatalf("EnsureFunded() expected wrapped faucet error, got %v", err)
+	}
+
+	if funded {
+		t.Error("EnsureFunded() expected funding to fail")
+	}
+
+	if faucet.Calls != 4 {
+		t.Errorf("Expected 4 faucet calls, got %d", faucet.Calls)
+	}
+}
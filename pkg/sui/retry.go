@@ -0,0 +1,83 @@
+package sui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pattonkan/sui-go/suiclient/conn"
+)
+
+// DefaultRPCMaxRetries is how many times a failed Sui RPC call is retried
+// when the builder is not configured otherwise.
+const DefaultRPCMaxRetries = 3
+
+const (
+	defaultRPCRetryBaseDelay = 200 * time.Millisecond
+	rpcRetryMaxDelay         = 5 * time.Second
+)
+
+// SetRPCMaxRetries sets how many times a retryable RPC failure is retried.
+// Zero disables retries; negative values are treated as zero.
+func (tb *TransactionBuilder) SetRPCMaxRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	tb.rpcMaxRetries = n
+}
+
+// retryRPC runs op, retrying retryable failures with exponential backoff and
+// jitter up to rpcMaxRetries times. Re-submitting identical signed transaction
+// bytes is safe: Sui deduplicates them by digest.
+func (tb *TransactionBuilder) retryRPC(ctx context.Context, op func() error) error {
+	delay := tb.rpcRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= tb.rpcMaxRetries || !isRetryableRPCError(err) {
+			return err
+		}
+
+		// Full jitter: sleep a random duration in [delay/2, delay)
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		tb.logger.Warn().Err(err).
+			Int("attempt", attempt+1).
+			Int("max_retries", tb.rpcMaxRetries).
+			Dur("backoff", wait).
+			Msg("Sui RPC call failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > rpcRetryMaxDelay {
+			delay = rpcRetryMaxDelay
+		}
+	}
+}
+
+// isRetryableRPCError reports whether err looks transient: rate limiting,
+// 5xx responses from the node, or a network-level failure.
+func isRetryableRPCError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr conn.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
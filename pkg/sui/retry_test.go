@@ -0,0 +1,123 @@
+package sui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suiclient/conn"
+)
+
+// flakyCoinsClient fails the first failures GetCoins calls with err before
+// delegating to the wrapped MockSuiClient.
+type flakyCoinsClient struct {
+	*MockSuiClient
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyCoinsClient) GetCoins(ctx context.Context, req *suiclient.GetCoinsRequest) (*suiclient.CoinPage, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return f.MockSuiClient.GetCoins(ctx, req)
+}
+
+func newFlakyTestBuilder(t *testing.T, failures int, err error) (*TransactionBuilder, *flakyCoinsClient) {
+	t.Helper()
+
+	base, mock := newVaultTestBuilder(t)
+	flaky := &flakyCoinsClient{MockSuiClient: mock, failures: failures, err: err}
+	base.client = flaky
+	base.rpcRetryBaseDelay = 0
+	return base, flaky
+}
+
+func TestRetryRPC_SucceedsAfterTransientFailures(t *testing.T) {
+	tb, client := newFlakyTestBuilder(t, 2, conn.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"})
+
+	if err := tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr); err != nil {
+		t.Fatalf("VaultTransferBounty() unexpected error: %v", err)
+	}
+	if client.calls != 3 {
+		t.Errorf("GetCoins called %d times, want 3", client.calls)
+	}
+	if len(client.Executed) != 1 {
+		t.Errorf("Executed %d transactions, want 1", len(client.Executed))
+	}
+}
+
+func TestRetryRPC_StopsAfterMaxRetries(t *testing.T) {
+	tb, client := newFlakyTestBuilder(t, 10, conn.HTTPError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"})
+	tb.SetRPCMaxRetries(2)
+
+	err := tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+	if err == nil {
+		t.Fatal("VaultTransferBounty() expected error after exhausting retries")
+	}
+	if client.calls != 3 {
+		t.Errorf("GetCoins called %d times, want 3 (1 attempt + 2 retries)", client.calls)
+	}
+	if len(client.Executed) != 0 {
+		t.Errorf("Executed %d transactions, want 0", len(client.Executed))
+	}
+}
+
+func TestRetryRPC_DoesNotRetryPermanentErrors(t *testing.T) {
+	tb, client := newFlakyTestBuilder(t, 1, errors.New("sui returned error: invalid params"))
+
+	if err := tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr); err == nil {
+		t.Fatal("VaultTransferBounty() expected error")
+	}
+	if client.calls != 1 {
+		t.Errorf("GetCoins called %d times, want 1", client.calls)
+	}
+}
+
+func TestRetryRPC_StopsWhenContextCanceled(t *testing.T) {
+	tb, _ := newVaultTestBuilder(t)
+	tb.rpcRetryBaseDelay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := tb.retryRPC(ctx, func() error {
+		calls++
+		cancel()
+		return conn.HTTPError{StatusCode: http.StatusServiceUnavailable}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("retryRPC() = %v after %d calls, want error after 1 call", err, calls)
+	}
+}
+
+func TestIsRetryableRPCError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", fmt.Errorf("failed to call doRequest:%w", conn.HTTPError{StatusCode: http.StatusTooManyRequests}), true},
+		{"server error", conn.HTTPError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"bad request", conn.HTTPError{StatusCode: http.StatusBadRequest}, false},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"unexpected EOF", fmt.Errorf("could not read response body: %w", io.ErrUnexpectedEOF), true},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+		{"rpc error", errors.New("sui returned error: object not found"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableRPCError(tt.err); got != tt.want {
+				t.Errorf("isRetryableRPCError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package sui
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/fardream/go-bcs/bcs"
 	"github.com/pattonkan/sui-go/sui"
@@ -24,10 +26,17 @@ type TypeArgs struct {
 
 // TransactionBuilder handles Sui blockchain interactions for the challenger service
 type TransactionBuilder struct {
-	client    *suiclient.ClientImpl
+	client    SuiClient
 	packageID *sui.PackageId
 	signer    *suisigner.Signer
 	logger    zerolog.Logger
+
+	rpcMaxRetries     int           // Retries for transient RPC failures
+	rpcRetryBaseDelay time.Duration // First backoff delay, doubled per retry
+
+	confirmPollInterval time.Duration // First delay between lookups while waiting for an executed transaction
+
+	subscriber EventSubscriber // Optional; needed by SubscribeCommitmentEvents
 }
 
 // NewTransactionBuilder creates a new TransactionBuilder instance
@@ -51,6 +60,18 @@ func NewTransactionBuilder(ctx context.Context, logger zerolog.Logger, rpcURL st
 		return nil, fmt.Errorf("failed to create signer from mnemonic: %w", err)
 	}
 
+	return NewTransactionBuilderWithClient(client, packageID, signer, logger)
+}
+
+// NewTransactionBuilderWithClient creates a TransactionBuilder on top of an existing client and signer
+func NewTransactionBuilderWithClient(client SuiClient, packageID string, signer *suisigner.Signer, logger zerolog.Logger) (*TransactionBuilder, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	if signer == nil {
+		return nil, fmt.Errorf("signer cannot be nil")
+	}
+
 	// Parse package ID
 	pkgID, err := sui.PackageIdFromHex(packageID)
 	if err != nil {
@@ -62,9 +83,23 @@ func NewTransactionBuilder(ctx context.Context, logger zerolog.Logger, rpcURL st
 		packageID: pkgID,
 		signer:    signer,
 		logger:    logger.With().Str("component", "sui_txbuilder").Logger(),
+
+		rpcMaxRetries:     DefaultRPCMaxRetries,
+		rpcRetryBaseDelay: defaultRPCRetryBaseDelay,
+
+		confirmPollInterval: defaultWaitPollInterval,
 	}, nil
 }
 
+// CheckRPC makes a cheap RPC call (the chain identifier) to confirm the Sui node is reachable.
+// It is not retried, so a readiness probe fails fast.
+func (tb *TransactionBuilder) CheckRPC(ctx context.Context) error {
+	if _, err := tb.client.GetChainIdentifier(ctx); err != nil {
+		return fmt.Errorf("sui rpc unreachable: %w", err)
+	}
+	return nil
+}
+
 func (tb *TransactionBuilder) Signer() *suisigner.Signer {
 	return tb.signer
 }
@@ -73,6 +108,17 @@ func (tb *TransactionBuilder) PackageId() *sui.PackageId {
 	return tb.packageID
 }
 
+// CommitmentItem is one upload_challenge_commitment call in a batched upload
+type CommitmentItem struct {
+	TypeArgs       TypeArgs
+	RegistryID     string
+	Commitment     []byte
+	ChallengerAddr string
+	SolverAddr     string
+	Score          uint64
+	Timestamp      uint64
+}
+
 // BuildUploadChallengeCommitment builds a Move call transaction for upload_challenge_commitment
 func (tb *TransactionBuilder) BuildUploadChallengeCommitment(
 	ctx context.Context,
@@ -84,38 +130,89 @@ func (tb *TransactionBuilder) BuildUploadChallengeCommitment(
 	score uint64,
 	timestamp uint64,
 ) (*suiptb.ProgrammableTransaction, error) {
-	// Parse addresses
-	challengerAddress, err := sui.AddressFromHex(challengerAddr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid challenger address: %w", err)
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	item := CommitmentItem{
+		TypeArgs:       typeArgs,
+		RegistryID:     registryId,
+		Commitment:     commitment,
+		ChallengerAddr: challengerAddr,
+		SolverAddr:     solverAddr,
+		Score:          score,
+		Timestamp:      timestamp,
+	}
+	if err := tb.addUploadChallengeCommitment(ctx, ptb, item, map[string]*sui.ObjectRef{}); err != nil {
+		return nil, err
 	}
 
-	solverAddress, err := sui.AddressFromHex(solverAddr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid solver address: %w", err)
+	pt := ptb.Finish()
+	return &pt, nil
+}
+
+// BuildUploadChallengeCommitmentsBatch builds one transaction holding an
+// upload_challenge_commitment call, and the transfer of its result, per item
+func (tb *TransactionBuilder) BuildUploadChallengeCommitmentsBatch(ctx context.Context, items []CommitmentItem) (*suiptb.ProgrammableTransaction, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no commitments to upload")
 	}
 
-	// Parse registry object ID
-	registryObjID, err := sui.ObjectIdFromHex(registryId)
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	registries := map[string]*sui.ObjectRef{}
+	for i, item := range items {
+		if err := tb.addUploadChallengeCommitment(ctx, ptb, item, registries); err != nil {
+			return nil, fmt.Errorf("commitment %d: %w", i, err)
+		}
+	}
+
+	pt := ptb.Finish()
+	return &pt, nil
+}
+
+// addUploadChallengeCommitment appends the upload and transfer commands for item to ptb.
+// Registry refs are cached in registries so a batch looks each registry up once.
+func (tb *TransactionBuilder) addUploadChallengeCommitment(
+	ctx context.Context,
+	ptb *suiptb.ProgrammableTransactionBuilder,
+	item CommitmentItem,
+	registries map[string]*sui.ObjectRef,
+) error {
+	// Parse addresses
+	challengerAddress, err := sui.AddressFromHex(item.ChallengerAddr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid registry object ID: %w", err)
+		return invalidAddress("challenger address", err)
 	}
 
-	registryGetObject, err := tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
-		ObjectId: registryObjID,
-		Options: &suiclient.SuiObjectDataOptions{
-			ShowContent: true,
-			ShowBcs:     true,
-			ShowOwner:   true,
-		},
-	})
+	solverAddress, err := sui.AddressFromHex(item.SolverAddr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get registry object object: %w", err)
+		return invalidAddress("solver address", err)
 	}
 
-	registryRef := registryGetObject.Data.RefSharedObject()
+	registryRef, ok := registries[item.RegistryID]
+	if !ok {
+		// Parse registry object ID
+		registryObjID, err := sui.ObjectIdFromHex(item.RegistryID)
+		if err != nil {
+			return invalidAddress("registry object ID", err)
+		}
 
-	ptb := suiptb.NewTransactionDataTransactionBuilder()
+		var registryGetObject *suiclient.SuiObjectResponse
+		err = tb.retryRPC(ctx, func() (err error) {
+			registryGetObject, err = tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
+				ObjectId: registryObjID,
+				Options: &suiclient.SuiObjectDataOptions{
+					ShowContent: true,
+					ShowBcs:     true,
+					ShowOwner:   true,
+				},
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get registry object object: %w", err)
+		}
+
+		registryRef = registryGetObject.Data.RefSharedObject()
+		registries[item.RegistryID] = registryRef
+	}
 
 	challengerArg := ptb.MustForceSeparatePure(challengerAddress)
 	commitmentArg := ptb.Command(suiptb.Command{
@@ -124,9 +221,9 @@ func (tb *TransactionBuilder) BuildUploadChallengeCommitment(
 			Module:   "ctf_registry",
 			Function: "upload_challenge_commitment",
 			TypeArguments: []sui.TypeTag{
-				*sui.MustNewTypeTag(typeArgs.TreasuryCapPositive),
-				*sui.MustNewTypeTag(typeArgs.TreasuryCapNegative),
-				*sui.MustNewTypeTag(typeArgs.CoinTypeCollateral),
+				*sui.MustNewTypeTag(item.TypeArgs.TreasuryCapPositive),
+				*sui.MustNewTypeTag(item.TypeArgs.TreasuryCapNegative),
+				*sui.MustNewTypeTag(item.TypeArgs.CoinTypeCollateral),
 			},
 			Arguments: []suiptb.Argument{
 				ptb.MustObj(suiptb.ObjectArg{SharedObject: &suiptb.SharedObjectArg{
@@ -134,11 +231,11 @@ func (tb *TransactionBuilder) BuildUploadChallengeCommitment(
 					InitialSharedVersion: registryRef.Version,
 					Mutable:              true,
 				}}),
-				ptb.MustPure(commitment),
+				ptb.MustPure(item.Commitment),
 				challengerArg,
 				ptb.MustPure(solverAddress),
-				ptb.MustPure(score),
-				ptb.MustPure(timestamp),
+				ptb.MustPure(item.Score),
+				ptb.MustPure(item.Timestamp),
 			},
 		},
 	})
@@ -150,15 +247,14 @@ func (tb *TransactionBuilder) BuildUploadChallengeCommitment(
 		},
 	})
 
-	pt := ptb.Finish()
-	return &pt, nil
+	return nil
 }
 
 // SelectGasObject selects the highest balance SUI coin for gas payment
 func (tb *TransactionBuilder) SelectGasObject(ctx context.Context, owner string) (*sui.ObjectId, error) {
 	ownerAddr, err := sui.AddressFromHex(owner)
 	if err != nil {
-		return nil, fmt.Errorf("invalid owner address: %w", err)
+		return nil, invalidAddress("owner address", err)
 	}
 
 	// Get owned SUI coins
@@ -173,7 +269,7 @@ func (tb *TransactionBuilder) SelectGasObject(ctx context.Context, owner string)
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("no SUI coins found for address %s", owner)
+		return nil, classify(ErrNoCoins, fmt.Errorf("no SUI coins found for address %s", owner))
 	}
 
 	// Sort coins by balance (descending) and select the highest one
@@ -213,7 +309,7 @@ func (tb *TransactionBuilder) SignAndExecute(ctx context.Context, txBytes *suicl
 		RequestType: suiclient.TxnRequestTypeWaitForLocalExecution,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to execute transaction: %w", err)
+		return "", fmt.Errorf("failed to execute transaction: %w", executionError(err))
 	}
 
 	digestStr := string(resp.Digest)
@@ -224,7 +320,7 @@ func (tb *TransactionBuilder) SignAndExecute(ctx context.Context, txBytes *suicl
 
 	// Check if transaction was successful
 	if resp.Effects.Data.V1.Status.Status != "success" {
-		return digestStr, fmt.Errorf("transaction failed with status: %v", resp.Effects.Data.V1.Status)
+		return digestStr, txFailed(resp)
 	}
 
 	return digestStr, nil
@@ -241,28 +337,53 @@ func (tb *TransactionBuilder) UploadChallengeCommitment(
 	score uint64,
 	timestamp uint64,
 ) (*sui.ObjectId, error) {
-	tb.logger.Debug().
-		Str("registry_id", registryId).
-		Str("challenger_addr", challengerAddr).
-		Str("solver_addr", solverAddr).
-		Uint64("score", score).
-		Uint64("timestamp", timestamp).
-		Str("commitment_hex", hex.EncodeToString(commitment)).
-		Msg("Building upload_challenge_commitment transaction")
+	objIds, err := tb.UploadChallengeCommitmentsBatch(ctx, []CommitmentItem{{
+		TypeArgs:       typeArgs,
+		RegistryID:     registryId,
+		Commitment:     commitment,
+		ChallengerAddr: challengerAddr,
+		SolverAddr:     solverAddr,
+		Score:          score,
+		Timestamp:      timestamp,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return objIds[0], nil
+}
+
+// UploadChallengeCommitmentsBatch uploads every item in a single transaction and
+// returns the created ChallengeCommitment object IDs in item order
+func (tb *TransactionBuilder) UploadChallengeCommitmentsBatch(ctx context.Context, items []CommitmentItem) ([]*sui.ObjectId, error) {
+	for i, item := range items {
+		tb.logger.Debug().
+			Int("index", i).
+			Str("registry_id", item.RegistryID).
+			Str("challenger_addr", item.ChallengerAddr).
+			Str("solver_addr", item.SolverAddr).
+			Uint64("score", item.Score).
+			Uint64("timestamp", item.Timestamp).
+			Str("commitment_hex", hex.EncodeToString(item.Commitment)).
+			Msg("Building upload_challenge_commitment transaction")
+	}
 
 	// Build the transaction
-	pt, err := tb.BuildUploadChallengeCommitment(
-		ctx, typeArgs, registryId, commitment,
-		challengerAddr, solverAddr, score, timestamp,
-	)
+	pt, err := tb.BuildUploadChallengeCommitmentsBatch(ctx, items)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build transaction: %w", err)
 	}
 
-	coinPage, err := tb.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: tb.signer.Address})
+	var coinPage *suiclient.CoinPage
+	err = tb.retryRPC(ctx, func() (err error) {
+		coinPage, err = tb.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: tb.signer.Address})
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get coins: %w", err)
 	}
+	if len(coinPage.Data) < 2 {
+		return nil, classify(ErrNoCoins, fmt.Errorf("need at least 2 SUI coins, found %d", len(coinPage.Data)))
+	}
 
 	tx := suiptb.NewTransactionData(
 		tb.signer.Address,
@@ -277,24 +398,33 @@ func (tb *TransactionBuilder) UploadChallengeCommitment(
 		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
-	txnResponse, err := tb.client.SignAndExecuteTransaction(
-		ctx,
-		tb.signer,
-		txBytes,
-		&suiclient.SuiTransactionBlockResponseOptions{
-			ShowEffects:       true,
-			ShowObjectChanges: true,
-		},
-	)
+	var txnResponse *suiclient.SuiTransactionBlockResponse
+	err = tb.retryRPC(ctx, func() (err error) {
+		txnResponse, err = tb.client.SignAndExecuteTransaction(
+			ctx,
+			tb.signer,
+			txBytes,
+			&suiclient.SuiTransactionBlockResponseOptions{
+				ShowEffects:       true,
+				ShowObjectChanges: true,
+			},
+		)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign and execute transaction: %w", err)
+		return nil, fmt.Errorf("failed to sign and execute transaction: %w", executionError(err))
 	}
 
 	if !txnResponse.Effects.Data.IsSuccess() {
-		return nil, fmt.Errorf("transaction failed")
+		return nil, txFailed(txnResponse)
+	}
+
+	// The verifier reads the commitment by object ID, so only report it once the node serves the transaction
+	if _, err := tb.WaitForTransaction(ctx, txnResponse.Digest.String(), &WaitOptions{PollInterval: tb.confirmPollInterval}); err != nil {
+		return nil, fmt.Errorf("failed to confirm transaction: %w", err)
 	}
 
-	var objId *sui.ObjectId
+	var created []*sui.ObjectId
 	for _, change := range txnResponse.ObjectChanges {
 		if change.Data.Created != nil {
 			resource, err := sui.NewResourceType(change.Data.Created.ObjectType)
@@ -303,17 +433,69 @@ func (tb *TransactionBuilder) UploadChallengeCommitment(
 			}
 
 			if resource.Contains(tb.packageID, "ctf_registry", "ChallengeCommitment") {
-				objId = &change.Data.Created.ObjectId
+				created = append(created, &change.Data.Created.ObjectId)
 			}
 		}
 	}
+	if len(created) != len(items) {
+		return nil, fmt.Errorf("transaction %s created %d ChallengeCommitment objects, want %d", txnResponse.Digest, len(created), len(items))
+	}
 
-	tb.logger.Info().
-		Str("digest", txnResponse.Digest.String()).
-		Str("objId", objId.String()).
-		Msg("Successfully uploaded challenge commitment to Sui")
+	objIds, err := tb.matchCreatedCommitments(ctx, items, created)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s: %w", txnResponse.Digest, err)
+	}
+
+	for _, objId := range objIds {
+		tb.logger.Info().
+			Str("digest", txnResponse.Digest.String()).
+			Str("objId", objId.String()).
+			Msg("Successfully uploaded challenge commitment to Sui")
+	}
+
+	return objIds, nil
+}
+
+// matchCreatedCommitments orders the created commitment objects like items. Object
+// changes come back in no particular order, so each object is read back and
+// matched on its contents.
+func (tb *TransactionBuilder) matchCreatedCommitments(ctx context.Context, items []CommitmentItem, created []*sui.ObjectId) ([]*sui.ObjectId, error) {
+	if len(items) == 1 {
+		return created, nil
+	}
+
+	objIds := make([]*sui.ObjectId, len(items))
+	for _, id := range created {
+		var payload *CommitmentPayload
+		err := tb.retryRPC(ctx, func() (err error) {
+			payload, err = tb.GetChallengeCommitment(ctx, id.String())
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		matched := false
+		for i, item := range items {
+			if objIds[i] == nil && commitmentMatches(item, payload) {
+				objIds[i] = id
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("commitment object %s matches no batch item", id)
+		}
+	}
+	return objIds, nil
+}
 
-	return objId, nil
+func commitmentMatches(item CommitmentItem, payload *CommitmentPayload) bool {
+	if !bytes.Equal(item.Commitment, payload.Commitment) || item.Score != payload.Score || item.Timestamp != payload.Timestamp {
+		return false
+	}
+	solver, err := sui.AddressFromHex(item.SolverAddr)
+	return err == nil && payload.SolverAddr != nil && *solver == *payload.SolverAddr
 }
 
 func (tb *TransactionBuilder) VaultAddBounty(
@@ -324,13 +506,22 @@ func (tb *TransactionBuilder) VaultAddBounty(
 		Str("vault_id", vaultId).
 		Msg("Building vault_add_bounty transaction")
 
-	vaultGetObject, err := tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
-		ObjectId: sui.MustAddressFromHex(vaultId),
-		Options: &suiclient.SuiObjectDataOptions{
-			ShowContent: true,
-			ShowBcs:     true,
-			ShowOwner:   true,
-		},
+	vaultObjID, err := sui.ObjectIdFromHex(vaultId)
+	if err != nil {
+		return invalidAddress("vault object ID", err)
+	}
+
+	var vaultGetObject *suiclient.SuiObjectResponse
+	err = tb.retryRPC(ctx, func() (err error) {
+		vaultGetObject, err = tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
+			ObjectId: vaultObjID,
+			Options: &suiclient.SuiObjectDataOptions{
+				ShowContent: true,
+				ShowBcs:     true,
+				ShowOwner:   true,
+			},
+		})
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("failed to get vault object object: %w", err)
@@ -338,11 +529,19 @@ func (tb *TransactionBuilder) VaultAddBounty(
 
 	vaultRef := vaultGetObject.Data.RefSharedObject()
 
-	coinPage, err := tb.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: tb.signer.Address})
+	var coinPage *suiclient.CoinPage
+	err = tb.retryRPC(ctx, func() (err error) {
+		coinPage, err = tb.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: tb.signer.Address})
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to get coins: %w", err)
 	}
 	coins := coinPage.Data
+	// One coin is added to the bounty and another pays for gas
+	if len(coins) < 2 {
+		return classify(ErrNoCoins, fmt.Errorf("need at least 2 SUI coins, found %d", len(coins)))
+	}
 
 	ptb := suiptb.NewTransactionDataTransactionBuilder()
 
@@ -378,21 +577,25 @@ func (tb *TransactionBuilder) VaultAddBounty(
 		return fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
-	txnResponse, err := tb.client.SignAndExecuteTransaction(
-		ctx,
-		tb.signer,
-		txBytes,
-		&suiclient.SuiTransactionBlockResponseOptions{
-			ShowEffects:       true,
-			ShowObjectChanges: true,
-		},
-	)
+	var txnResponse *suiclient.SuiTransactionBlockResponse
+	err = tb.retryRPC(ctx, func() (err error) {
+		txnResponse, err = tb.client.SignAndExecuteTransaction(
+			ctx,
+			tb.signer,
+			txBytes,
+			&suiclient.SuiTransactionBlockResponseOptions{
+				ShowEffects:       true,
+				ShowObjectChanges: true,
+			},
+		)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to sign and execute transaction: %w", err)
+		return fmt.Errorf("failed to sign and execute transaction: %w", executionError(err))
 	}
 
 	if !txnResponse.Effects.Data.IsSuccess() {
-		return fmt.Errorf("transaction failed")
+		return txFailed(txnResponse)
 	}
 
 	tb.logger.Info().
@@ -402,48 +605,62 @@ func (tb *TransactionBuilder) VaultAddBounty(
 	return nil
 }
 
-func (tb *TransactionBuilder) VaultTransferBounty(
+// BuildVaultTransferBounty builds a transaction that pays the vault bounty out to the solver:
+// vault_transfer_bounty(vault, admin_cap) followed by a transfer of the returned coin to solverAddr.
+func (tb *TransactionBuilder) BuildVaultTransferBounty(
 	ctx context.Context,
 	vaultId string,
 	vaultAdminCapId string,
 	solverAddr string,
-) error {
-	tb.logger.Debug().
-		Str("vault_id", vaultId).
-		Str("vault_admin_cap_id", vaultAdminCapId).
-		Msg("Building vault_transfer_bounty transaction")
+) (*suiptb.ProgrammableTransaction, error) {
+	solverAddress, err := sui.AddressFromHex(solverAddr)
+	if err != nil {
+		return nil, invalidAddress("solver address", err)
+	}
 
-	vaultGetObject, err := tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
-		ObjectId: sui.MustAddressFromHex(vaultId),
-		Options: &suiclient.SuiObjectDataOptions{
-			ShowContent: true,
-			ShowBcs:     true,
-			ShowOwner:   true,
-		},
-	})
+	vaultObjID, err := sui.ObjectIdFromHex(vaultId)
 	if err != nil {
-		return fmt.Errorf("failed to get vault object object: %w", err)
+		return nil, invalidAddress("vault object ID", err)
 	}
-	vaultRef := vaultGetObject.Data.RefSharedObject()
 
-	vaultAdminCapGetObject, err := tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
-		ObjectId: sui.MustAddressFromHex(vaultAdminCapId),
-		Options: &suiclient.SuiObjectDataOptions{
-			ShowContent: true,
-			ShowBcs:     true,
-			ShowOwner:   true,
-		},
+	vaultAdminCapObjID, err := sui.ObjectIdFromHex(vaultAdminCapId)
+	if err != nil {
+		return nil, invalidAddress("vault admin cap object ID", err)
+	}
+
+	var vaultGetObject *suiclient.SuiObjectResponse
+	err = tb.retryRPC(ctx, func() (err error) {
+		vaultGetObject, err = tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
+			ObjectId: vaultObjID,
+			Options: &suiclient.SuiObjectDataOptions{
+				ShowContent: true,
+				ShowBcs:     true,
+				ShowOwner:   true,
+			},
+		})
+		return err
 	})
 	if err != nil {
-		return fmt.Errorf("failed to get vault object object: %w", err)
+		return nil, fmt.Errorf("failed to get vault object: %w", err)
 	}
-	vaultAdminCapRef := vaultAdminCapGetObject.Data.Ref()
+	vaultRef := vaultGetObject.Data.RefSharedObject()
 
-	coinPage, err := tb.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: tb.signer.Address})
+	var vaultAdminCapGetObject *suiclient.SuiObjectResponse
+	err = tb.retryRPC(ctx, func() (err error) {
+		vaultAdminCapGetObject, err = tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
+			ObjectId: vaultAdminCapObjID,
+			Options: &suiclient.SuiObjectDataOptions{
+				ShowContent: true,
+				ShowBcs:     true,
+				ShowOwner:   true,
+			},
+		})
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get coins: %w", err)
+		return nil, fmt.Errorf("failed to get vault admin cap object: %w", err)
 	}
-	coins := coinPage.Data
+	vaultAdminCapRef := vaultAdminCapGetObject.Data.Ref()
 
 	ptb := suiptb.NewTransactionDataTransactionBuilder()
 
@@ -466,16 +683,48 @@ func (tb *TransactionBuilder) VaultTransferBounty(
 	ptb.Command(suiptb.Command{
 		TransferObjects: &suiptb.ProgrammableTransferObjects{
 			Objects: []suiptb.Argument{bountyArg},
-			Address: ptb.MustPure(sui.MustAddressFromHex(solverAddr)),
+			Address: ptb.MustPure(solverAddress),
 		},
 	})
 
 	pt := ptb.Finish()
+	return &pt, nil
+}
+
+// VaultTransferBounty builds, signs, and executes the bounty payout to solverAddr
+func (tb *TransactionBuilder) VaultTransferBounty(
+	ctx context.Context,
+	vaultId string,
+	vaultAdminCapId string,
+	solverAddr string,
+) error {
+	tb.logger.Debug().
+		Str("vault_id", vaultId).
+		Str("vault_admin_cap_id", vaultAdminCapId).
+		Str("solver_addr", solverAddr).
+		Msg("Building vault_transfer_bounty transaction")
+
+	pt, err := tb.BuildVaultTransferBounty(ctx, vaultId, vaultAdminCapId, solverAddr)
+	if err != nil {
+		return fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	var coinPage *suiclient.CoinPage
+	err = tb.retryRPC(ctx, func() (err error) {
+		coinPage, err = tb.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: tb.signer.Address})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get coins: %w", err)
+	}
+	if len(coinPage.Data) == 0 {
+		return classify(ErrNoCoins, fmt.Errorf("no SUI coins found for gas payment"))
+	}
 
 	tx := suiptb.NewTransactionData(
 		tb.signer.Address,
-		pt,
-		[]*sui.ObjectRef{coins[0].Ref()},
+		*pt,
+		[]*sui.ObjectRef{coinPage.Data[0].Ref()},
 		suiclient.DefaultGasBudget,
 		suiclient.DefaultGasPrice,
 	)
@@ -485,26 +734,83 @@ func (tb *TransactionBuilder) VaultTransferBounty(
 		return fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
-	txnResponse, err := tb.client.SignAndExecuteTransaction(
-		ctx,
-		tb.signer,
-		txBytes,
-		&suiclient.SuiTransactionBlockResponseOptions{
-			ShowEffects:       true,
-			ShowObjectChanges: true,
-		},
-	)
+	var txnResponse *suiclient.SuiTransactionBlockResponse
+	err = tb.retryRPC(ctx, func() (err error) {
+		txnResponse, err = tb.client.SignAndExecuteTransaction(
+			ctx,
+			tb.signer,
+			txBytes,
+			&suiclient.SuiTransactionBlockResponseOptions{
+				ShowEffects:       true,
+				ShowObjectChanges: true,
+			},
+		)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to sign and execute transaction: %w", err)
+		return fmt.Errorf("failed to sign and execute transaction: %w", executionError(err))
 	}
 
 	if !txnResponse.Effects.Data.IsSuccess() {
-		return fmt.Errorf("transaction failed")
+		return txFailed(txnResponse)
 	}
 
 	tb.logger.Info().
 		Str("digest", txnResponse.Digest.String()).
-		Msg("Successfully uploaded challenge commitment to Sui")
+		Str("solver_addr", solverAddr).
+		Msg("Successfully transferred bounty to solver")
 
 	return nil
 }
+
+// CommitmentPayload mirrors the ChallengeCommitment Move struct in
+// ctf_registry.move; field order must match for BCS decoding.
+type CommitmentPayload struct {
+	Id             *sui.ObjectId
+	RegistryId     *sui.ObjectId
+	ChallengerAddr *sui.Address
+	SolverAddr     *sui.Address
+	Score          uint64
+	Timestamp      uint64
+	Commitment     []byte
+}
+
+// DecodeCommitmentPayload decodes the BCS contents of a ChallengeCommitment object
+func DecodeCommitmentPayload(objRes *suiclient.SuiObjectResponse) (*CommitmentPayload, error) {
+	if objRes == nil || objRes.Data == nil {
+		return nil, fmt.Errorf("object response has no data")
+	}
+	if objRes.Data.Bcs == nil || objRes.Data.Bcs.Data.MoveObject == nil {
+		return nil, fmt.Errorf("object %s has no BCS move object", objRes.Data.ObjectId)
+	}
+
+	var payload CommitmentPayload
+	if _, err := bcs.Unmarshal(objRes.Data.Bcs.Data.MoveObject.BcsBytes, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal to CommitmentPayload: %w", err)
+	}
+	return &payload, nil
+}
+
+// GetChallengeCommitment fetches a ChallengeCommitment object and decodes its payload
+func (tb *TransactionBuilder) GetChallengeCommitment(ctx context.Context, objectID string) (*CommitmentPayload, error) {
+	objId, err := sui.ObjectIdFromHex(objectID)
+	if err != nil {
+		return nil, classify(ErrInvalidAddress, fmt.Errorf("invalid object ID %q: %w", objectID, err))
+	}
+
+	objRes, err := tb.client.GetObject(ctx, &suiclient.GetObjectRequest{
+		ObjectId: objId,
+		Options: &suiclient.SuiObjectDataOptions{
+			ShowType: true,
+			ShowBcs:  true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commitment object %s: %w", objectID, err)
+	}
+	if objRes.Error != nil {
+		return nil, fmt.Errorf("commitment object %s: %v", objectID, objRes.Error.Data)
+	}
+
+	return DecodeCommitmentPayload(objRes)
+}
@@ -2,9 +2,17 @@ package sui
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/fardream/go-bcs/bcs"
 	suiTypes "github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/suicrypto"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -213,3 +221,421 @@ func TestTransactionBuilder_MethodSignatures(t *testing.T) {
 		)
 	}
 }
+
+const (
+	testVaultID       = "0xa1"
+	testVaultAdminCap = "0xa2"
+	testGasCoinID     = "0xa3"
+	testSolverAddr    = "0xb0b"
+	testObjectDigest  = "11111111111111111111111111111111"
+)
+
+// newVaultTestBuilder returns a builder backed by a mock client holding a shared
+// vault (initial version 3), an owned admin cap (version 7), and one gas coin.
+func newVaultTestBuilder(t *testing.T) (*TransactionBuilder, *MockSuiClient) {
+	t.Helper()
+
+	client := &MockSuiClient{
+		Objects: map[string]*suiclient.SuiObjectResponse{
+			suiTypes.MustObjectIdFromHex(testVaultID).String(): mustObjectResponse(t,
+				`{"data":{"objectId":"`+testVaultID+`","version":"9","digest":"`+testObjectDigest+`","owner":{"Shared":{"initial_shared_version":3}}}}`),
+			suiTypes.MustObjectIdFromHex(testVaultAdminCap).String(): mustObjectResponse(t,
+				`{"data":{"objectId":"`+testVaultAdminCap+`","version":"7","digest":"`+testObjectDigest+`","owner":{"AddressOwner":"0x1"}}}`),
+		},
+		Coins: []*suiclient.Coin{{
+			CoinObjectId: suiTypes.MustObjectIdFromHex(testGasCoinID),
+			Version:      suiTypes.NewBigInt(1),
+			Digest:       suiTypes.MustNewDigest(testObjectDigest),
+			Balance:      suiTypes.NewBigInt(1_000_000_000),
+		}},
+	}
+
+	signer := suisigner.NewSigner(make([]byte, 32), suicrypto.KeySchemeFlagEd25519)
+	tb, err := NewTransactionBuilderWithClient(client, "0x1234567890abcdef1234567890abcdef12345678", signer, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewTransactionBuilderWithClient() unexpected error: %v", err)
+	}
+	return tb, client
+}
+
+func mustObjectResponse(t *testing.T, raw string) *suiclient.SuiObjectResponse {
+	t.Helper()
+
+	var resp suiclient.SuiObjectResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("failed to decode object response: %v", err)
+	}
+	return &resp
+}
+
+func TestCheckRPC(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+	client.ChainID = "4c78adac"
+
+	if err := tb.CheckRPC(context.Background()); err != nil {
+		t.Errorf("expected a reachable RPC to pass, got %v", err)
+	}
+
+	client.ChainIDErr = errors.New("connection refused")
+	err := tb.CheckRPC(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "sui rpc unreachable") {
+		t.Errorf("expected an unreachable RPC error, got %v", err)
+	}
+}
+
+func TestBuildVaultTransferBounty(t *testing.T) {
+	tb, _ := newVaultTestBuilder(t)
+
+	pt, err := tb.BuildVaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+	if err != nil {
+		t.Fatalf("BuildVaultTransferBounty() unexpected error: %v", err)
+	}
+
+	assertVaultTransferBountyPTB(t, pt)
+}
+
+func TestVaultTransferBounty_ExecutesWithGasCoin(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+
+	if err := tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr); err != nil {
+		t.Fatalf("VaultTransferBounty() unexpected error: %v", err)
+	}
+
+	if len(client.Executed) != 1 {
+		t.Fatalf("Expected 1 executed transaction, got %d", len(client.Executed))
+	}
+
+	var tx suiptb.TransactionData
+	if _, err := bcs.Unmarshal(client.Executed[0], &tx); err != nil {
+		t.Fatalf("failed to decode executed transaction: %v", err)
+	}
+
+	if tx.V1.Sender.String() != tb.Signer().Address.String() {
+		t.Errorf("Expected sender %s, got %s", tb.Signer().Address, tx.V1.Sender)
+	}
+	payment := tx.V1.GasData.Payment
+	if len(payment) != 1 || payment[0].ObjectId.String() != suiTypes.MustObjectIdFromHex(testGasCoinID).String() {
+		t.Errorf("Expected gas paid with coin %s, got %+v", testGasCoinID, payment)
+	}
+	if tx.V1.GasData.Budget != suiclient.DefaultGasBudget {
+		t.Errorf("Expected gas budget %d, got %d", suiclient.DefaultGasBudget, tx.V1.GasData.Budget)
+	}
+
+	assertVaultTransferBountyPTB(t, tx.V1.Kind.ProgrammableTransaction)
+}
+
+func TestVaultTransferBounty_Errors(t *testing.T) {
+	executionErr := errors.New("insufficient gas")
+
+	tests := []struct {
+		name    string
+		setup   func(client *MockSuiClient)
+		wantErr string
+	}{
+		{
+			name:    "execution error",
+			setup:   func(client *MockSuiClient) { client.ExecuteErr = executionErr },
+			wantErr: "insufficient gas",
+		},
+		{
+			name: "failed effects",
+			setup: func(client *MockSuiClient) {
+				client.Response = &suiclient.SuiTransactionBlockResponse{
+					Effects: &suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+						Data: suiclient.SuiTransactionBlockEffects{
+							V1: &suiclient.SuiTransactionBlockEffectsV1{
+								Status: suiclient.ExecutionStatus{Status: "failure", Error: "MoveAbort"},
+							},
+						},
+					},
+				}
+			},
+			wantErr: "transaction failed",
+		},
+		{
+			name:    "no gas coins",
+			setup:   func(client *MockSuiClient) { client.Coins = nil },
+			wantErr: "no SUI coins",
+		},
+		{
+			name: "missing vault",
+			setup: func(client *MockSuiClient) {
+				delete(client.Objects, suiTypes.MustObjectIdFromHex(testVaultID).String())
+			},
+			wantErr: "failed to get vault object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb, client := newVaultTestBuilder(t)
+			tt.setup(client)
+
+			err := tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VaultTransferBounty() expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	tb, client := newVaultTestBuilder(t)
+	client.ExecuteErr = executionErr
+	if err := tb.VaultTransferBounty(context.Background(), testVaultID, testVaultAdminCap, testSolverAddr); !errors.Is(err, executionErr) {
+		t.Errorf("Expected execution error to be wrapped, got %v", err)
+	}
+}
+
+// assertVaultTransferBountyPTB checks the vault shared-object input, the admin-cap
+// owned-object input, and the transfer of the bounty to the solver.
+func assertVaultTransferBountyPTB(t *testing.T, pt *suiptb.ProgrammableTransaction) {
+	t.Helper()
+
+	if pt == nil {
+		t.Fatal("Expected a programmable transaction")
+	}
+	if len(pt.Commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(pt.Commands))
+	}
+
+	call := pt.Commands[0].MoveCall
+	if call == nil || call.Module != "ctf_registry" || call.Function != "vault_transfer_bounty" {
+		t.Fatalf("Expected ctf_registry::vault_transfer_bounty call, got %+v", pt.Commands[0])
+	}
+	if len(call.Arguments) != 2 {
+		t.Fatalf("Expected 2 call arguments, got %d", len(call.Arguments))
+	}
+
+	vaultArg := inputArg(t, pt, call.Arguments[0])
+	if vaultArg.Object == nil || vaultArg.Object.SharedObject == nil {
+		t.Fatalf("Expected vault to be a shared object arg, got %+v", vaultArg)
+	}
+	shared := vaultArg.Object.SharedObject
+	if shared.Id.String() != suiTypes.MustObjectIdFromHex(testVaultID).String() || shared.InitialSharedVersion != 3 || !shared.Mutable {
+		t.Errorf("Unexpected vault shared object arg: %+v", shared)
+	}
+
+	capArg := inputArg(t, pt, call.Arguments[1])
+	if capArg.Object == nil || capArg.Object.ImmOrOwnedObject == nil {
+		t.Fatalf("Expected admin cap to be an owned object arg, got %+v", capArg)
+	}
+	owned := capArg.Object.ImmOrOwnedObject
+	if owned.ObjectId.String() != suiTypes.MustObjectIdFromHex(testVaultAdminCap).String() || owned.Version != 7 {
+		t.Errorf("Unexpected admin cap owned object arg: %+v", owned)
+	}
+
+	transfer := pt.Commands[1].TransferObjects
+	if transfer == nil {
+		t.Fatalf("Expected a transfer command, got %+v", pt.Commands[1])
+	}
+	if len(transfer.Objects) != 1 || transfer.Objects[0].Result == nil || *transfer.Objects[0].Result != 0 {
+		t.Errorf("Expected the bounty returned by the call to be transferred, got %+v", transfer.Objects)
+	}
+
+	recipient := inputArg(t, pt, transfer.Address)
+	want, err := bcs.Marshal(suiTypes.MustAddressFromHex(testSolverAddr))
+	if err != nil {
+		t.Fatalf("failed to encode solver address: %v", err)
+	}
+	if recipient.Pure == nil || string(*recipient.Pure) != string(want) {
+		t.Errorf("Expected transfer to solver %s, got %+v", testSolverAddr, recipient)
+	}
+}
+
+func inputArg(t *testing.T, pt *suiptb.ProgrammableTransaction, arg suiptb.Argument) suiptb.CallArg {
+	t.Helper()
+
+	if arg.Input == nil || int(*arg.Input) >= len(pt.Inputs) {
+		t.Fatalf("Expected an input argument, got %+v", arg)
+	}
+	return pt.Inputs[*arg.Input]
+}
+
+func TestGetChallengeCommitment(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+
+	const commitmentID = "0x00000000000000000000000000000000000000000000000000000000000000c1"
+	want := CommitmentPayload{
+		Id:             suiTypes.MustObjectIdFromHex(commitmentID),
+		RegistryId:     suiTypes.MustObjectIdFromHex("0x00000000000000000000000000000000000000000000000000000000000000a1"),
+		ChallengerAddr: suiTypes.MustAddressFromHex("0x00000000000000000000000000000000000000000000000000000000000000b1"),
+		SolverAddr:     suiTypes.MustAddressFromHex("0x00000000000000000000000000000000000000000000000000000000000000b2"),
+		Score:          42,
+		Timestamp:      1_700_000_000,
+		Commitment:     []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+	bcsBytes, err := bcs.Marshal(&want)
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	resp := mustObjectResponse(t, `{"data":{"objectId":"`+commitmentID+`","version":"2","digest":"`+testObjectDigest+`"}}`)
+	resp.Data.Bcs = &suiclient.WrapperTaggedJson[suiclient.SuiRawData]{
+		Data: suiclient.SuiRawData{MoveObject: &suiclient.SuiRawMoveObject{BcsBytes: bcsBytes}},
+	}
+	client.Objects[want.Id.String()] = resp
+
+	got, err := tb.GetChallengeCommitment(context.Background(), commitmentID)
+	if err != nil {
+		t.Fatalf("GetChallengeCommitment() unexpected error: %v", err)
+	}
+	if got.Id.String() != want.Id.String() || got.RegistryId.String() != want.RegistryId.String() {
+		t.Errorf("ids = (%s, %s), want (%s, %s)", got.Id, got.RegistryId, want.Id, want.RegistryId)
+	}
+	if got.ChallengerAddr.String() != want.ChallengerAddr.String() || got.SolverAddr.String() != want.SolverAddr.String() {
+		t.Errorf("addrs = (%s, %s), want (%s, %s)", got.ChallengerAddr, got.SolverAddr, want.ChallengerAddr, want.SolverAddr)
+	}
+	if got.Score != want.Score || got.Timestamp != want.Timestamp {
+		t.Errorf("score/timestamp = (%d, %d), want (%d, %d)", got.Score, got.Timestamp, want.Score, want.Timestamp)
+	}
+	if string(got.Commitment) != string(want.Commitment) {
+		t.Errorf("commitment = %x, want %x", got.Commitment, want.Commitment)
+	}
+}
+
+func TestGetChallengeCommitment_Errors(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+
+	const noBcsID = "0x00000000000000000000000000000000000000000000000000000000000000c2"
+	client.Objects[suiTypes.MustObjectIdFromHex(noBcsID).String()] = mustObjectResponse(t,
+		`{"data":{"objectId":"`+noBcsID+`","version":"2","digest":"`+testObjectDigest+`"}}`)
+
+	tests := []struct {
+		name     string
+		objectID string
+		wantErr  string
+	}{
+		{name: "invalid object ID", objectID: "not-hex", wantErr: "invalid object ID"},
+		{name: "unknown object", objectID: "0x00000000000000000000000000000000000000000000000000000000000000c3", wantErr: "not found"},
+		{name: "missing BCS", objectID: noBcsID, wantErr: "no BCS move object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tb.GetChallengeCommitment(context.Background(), tt.objectID)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GetChallengeCommitment() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// commitmentObjectResponse returns an object response whose BCS contents decode to payload.
+func commitmentObjectResponse(t *testing.T, payload CommitmentPayload) *suiclient.SuiObjectResponse {
+	t.Helper()
+
+	bcsBytes, err := bcs.Marshal(&payload)
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	resp := mustObjectResponse(t, `{"data":{"objectId":"`+payload.Id.String()+`","version":"2","digest":"`+testObjectDigest+`"}}`)
+	resp.Data.Bcs = &suiclient.WrapperTaggedJson[suiclient.SuiRawData]{
+		Data: suiclient.SuiRawData{MoveObject: &suiclient.SuiRawMoveObject{BcsBytes: bcsBytes}},
+	}
+	return resp
+}
+
+func TestUploadChallengeCommitmentsBatch(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+
+	const registryID = "0x00000000000000000000000000000000000000000000000000000000000000a1"
+	client.Objects[suiTypes.MustObjectIdFromHex(registryID).String()] = mustObjectResponse(t,
+		`{"data":{"objectId":"`+registryID+`","version":"4","digest":"`+testObjectDigest+`","owner":{"Shared":{"initial_shared_version":2}}}}`)
+	client.Coins = append(client.Coins, &suiclient.Coin{
+		CoinObjectId: suiTypes.MustObjectIdFromHex("0xc0c1"),
+		Version:      suiTypes.NewBigInt(1),
+		Digest:       suiTypes.MustNewDigest(testObjectDigest),
+		Balance:      suiTypes.NewBigInt(1_000_000_000),
+	})
+
+	typeArgs := TypeArgs{TreasuryCapPositive: "0x2::sui::SUI", TreasuryCapNegative: "0x2::sui::SUI", CoinTypeCollateral: "0x2::sui::SUI"}
+	challenger := tb.Signer().Address.String()
+	items := []CommitmentItem{
+		{TypeArgs: typeArgs, RegistryID: registryID, Commitment: []byte("first"), ChallengerAddr: challenger, SolverAddr: "0xb1", Score: 10, Timestamp: 100},
+		{TypeArgs: typeArgs, RegistryID: registryID, Commitment: []byte("second"), ChallengerAddr: challenger, SolverAddr: "0xb2", Score: 20, Timestamp: 200},
+		{TypeArgs: typeArgs, RegistryID: registryID, Commitment: []byte("third"), ChallengerAddr: challenger, SolverAddr: "0xb3", Score: 30, Timestamp: 300},
+	}
+	objectIDs := []string{
+		"0x00000000000000000000000000000000000000000000000000000000000000c1",
+		"0x00000000000000000000000000000000000000000000000000000000000000c2",
+		"0x00000000000000000000000000000000000000000000000000000000000000c3",
+	}
+	for i, item := range items {
+		client.Objects[suiTypes.MustObjectIdFromHex(objectIDs[i]).String()] = commitmentObjectResponse(t, CommitmentPayload{
+			Id:             suiTypes.MustObjectIdFromHex(objectIDs[i]),
+			RegistryId:     suiTypes.MustObjectIdFromHex(registryID),
+			ChallengerAddr: tb.Signer().Address,
+			SolverAddr:     suiTypes.MustAddressFromHex(item.SolverAddr),
+			Score:          item.Score,
+			Timestamp:      item.Timestamp,
+			Commitment:     item.Commitment,
+		})
+	}
+
+	// Object changes come back in a different order than the Move calls
+	commitmentType := tb.PackageId().String() + "::ctf_registry::ChallengeCommitment"
+	var changes []string
+	for _, i := range []int{2, 0, 1} {
+		changes = append(changes, `{"type":"created","sender":"`+challenger+`","owner":{"AddressOwner":"`+challenger+
+			`"},"objectType":"`+commitmentType+`","objectId":"`+objectIDs[i]+`","version":"2","digest":"`+testObjectDigest+`"}`)
+	}
+	var response suiclient.SuiTransactionBlockResponse
+	if err := json.Unmarshal([]byte(`{"digest":"`+testObjectDigest+`","objectChanges":[`+strings.Join(changes, ",")+`]}`), &response); err != nil {
+		t.Fatalf("failed to decode transaction response: %v", err)
+	}
+	response.Effects = &suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+		Data: suiclient.SuiTransactionBlockEffects{
+			V1: &suiclient.SuiTransactionBlockEffectsV1{
+				Status: suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess},
+			},
+		},
+	}
+	client.Response = &response
+
+	got, err := tb.UploadChallengeCommitmentsBatch(context.Background(), items)
+	if err != nil {
+		t.Fatalf("UploadChallengeCommitmentsBatch() unexpected error: %v", err)
+	}
+	if len(got) != len(items) {
+		t.Fatalf("Expected %d object IDs, got %d", len(items), len(got))
+	}
+	for i, id := range got {
+		if id.String() != suiTypes.MustObjectIdFromHex(objectIDs[i]).String() {
+			t.Errorf("item %d: expected object %s, got %s", i, objectIDs[i], id)
+		}
+	}
+
+	if len(client.Executed) != 1 {
+		t.Fatalf("Expected a single transaction, got %d", len(client.Executed))
+	}
+	var tx suiptb.TransactionData
+	if _, err := bcs.Unmarshal(client.Executed[0], &tx); err != nil {
+		t.Fatalf("failed to decode executed transaction: %v", err)
+	}
+	pt := tx.V1.Kind.ProgrammableTransaction
+	if pt == nil || len(pt.Commands) != 2*len(items) {
+		t.Fatalf("Expected %d commands, got %+v", 2*len(items), pt)
+	}
+	for i := range items {
+		call := pt.Commands[2*i].MoveCall
+		if call == nil || call.Function != "upload_challenge_commitment" {
+			t.Errorf("command %d: expected upload_challenge_commitment, got %+v", 2*i, pt.Commands[2*i])
+		}
+		if pt.Commands[2*i+1].TransferObjects == nil {
+			t.Errorf("command %d: expected TransferObjects, got %+v", 2*i+1, pt.Commands[2*i+1])
+		}
+	}
+}
+
+func TestUploadChallengeCommitmentsBatch_Errors(t *testing.T) {
+	tb, _ := newVaultTestBuilder(t)
+
+	if _, err := tb.UploadChallengeCommitmentsBatch(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "no commitments") {
+		t.Errorf("Expected empty batch error, got %v", err)
+	}
+
+	items := []CommitmentItem{{RegistryID: testVaultID, ChallengerAddr: "0x1", SolverAddr: "invalid-hex"}}
+	if _, err := tb.UploadChallengeCommitmentsBatch(context.Background(), items); err == nil || !strings.Contains(err.Error(), "commitment 0: invalid solver address") {
+		t.Errorf("Expected invalid solver error, got %v", err)
+	}
+}
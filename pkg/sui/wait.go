@@ -0,0 +1,90 @@
+package sui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+const (
+	defaultWaitPollInterval    = 200 * time.Millisecond
+	defaultWaitMaxPollInterval = 2 * time.Second
+)
+
+// WaitOptions tunes WaitForTransaction. Zero values use the defaults.
+type WaitOptions struct {
+	PollInterval    time.Duration                                 // First delay between lookups, doubled per attempt (default 200ms)
+	MaxPollInterval time.Duration                                 // Cap on the delay between lookups (default 2s)
+	ResponseOptions *suiclient.SuiTransactionBlockResponseOptions // Fields requested from GetTransactionBlock (default effects)
+}
+
+// WaitForTransaction polls GetTransactionBlock until the node serves the transaction, so
+// objects it created can be read by others, or ctx expires. Lookups that fail because the
+// transaction is not indexed yet, or with a transient RPC error, are retried with backoff.
+func (tb *TransactionBuilder) WaitForTransaction(ctx context.Context, digest string, opts *WaitOptions) (*suiclient.SuiTransactionBlockResponse, error) {
+	txDigest, err := sui.NewDigest(digest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction digest %q: %w", digest, err)
+	}
+	if len(*txDigest) != 32 {
+		return nil, fmt.Errorf("invalid transaction digest %q", digest)
+	}
+
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	delay := opts.PollInterval
+	if delay <= 0 {
+		delay = defaultWaitPollInterval
+	}
+	maxDelay := opts.MaxPollInterval
+	if maxDelay <= 0 {
+		maxDelay = defaultWaitMaxPollInterval
+	}
+	responseOptions := opts.ResponseOptions
+	if responseOptions == nil {
+		responseOptions = &suiclient.SuiTransactionBlockResponseOptions{ShowEffects: true}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := tb.client.GetTransactionBlock(ctx, &suiclient.GetTransactionBlockRequest{
+			Digest:  txDigest,
+			Options: responseOptions,
+		})
+		if err == nil {
+			if attempt > 1 {
+				tb.logger.Debug().Str("digest", digest).Int("attempts", attempt).Msg("Transaction available")
+			}
+			return resp, nil
+		}
+		if !isTransactionNotFound(err) && !isRetryableRPCError(err) {
+			return nil, fmt.Errorf("failed to get transaction %s: %w", digest, err)
+		}
+
+		tb.logger.Debug().Err(err).
+			Str("digest", digest).
+			Int("attempt", attempt).
+			Dur("backoff", delay).
+			Msg("Transaction not available yet, polling")
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s not available: %w", digest, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// isTransactionNotFound reports whether err is the node's answer for a digest it has not indexed yet
+func isTransactionNotFound(err error) bool {
+	return strings.Contains(err.Error(), "Could not find the referenced transaction")
+}
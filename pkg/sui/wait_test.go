@@ -0,0 +1,107 @@
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	suiTypes "github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+const testTxDigest = "4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"
+
+func TestWaitForTransaction(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+	client.TransactionNotFound = 2
+
+	resp, err := tb.WaitForTransaction(context.Background(), testTxDigest, &WaitOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForTransaction() unexpected error: %v", err)
+	}
+	if resp.Digest.String() != testTxDigest {
+		t.Errorf("expected transaction %s, got %s", testTxDigest, resp.Digest)
+	}
+	if client.TransactionLookups != 3 {
+		t.Errorf("expected 3 lookups (2 not found, then found), got %d", client.TransactionLookups)
+	}
+}
+
+func TestWaitForTransaction_ContextExpires(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+	client.TransactionNotFound = 1000
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := tb.WaitForTransaction(ctx, testTxDigest, &WaitOptions{PollInterval: time.Millisecond, MaxPollInterval: 5 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to end the wait, got %v", err)
+	}
+	if client.TransactionLookups < 2 {
+		t.Errorf("expected polling before the deadline, got %d lookups", client.TransactionLookups)
+	}
+}
+
+func TestWaitForTransaction_Errors(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+
+	if _, err := tb.WaitForTransaction(context.Background(), "not-a-digest", nil); err == nil || !strings.Contains(err.Error(), "invalid transaction digest") {
+		t.Errorf("expected an invalid digest error, got %v", err)
+	}
+	if client.TransactionLookups != 0 {
+		t.Errorf("expected no lookups for an invalid digest, got %d", client.TransactionLookups)
+	}
+
+	if isTransactionNotFound(errors.New("invalid params")) {
+		t.Error("expected other RPC errors not to count as not found")
+	}
+}
+
+func TestUploadChallengeCommitment_WaitsForTransaction(t *testing.T) {
+	tb, client := newVaultTestBuilder(t)
+	tb.confirmPollInterval = time.Millisecond
+
+	const registryID = "0x00000000000000000000000000000000000000000000000000000000000000a1"
+	const objectID = "0x00000000000000000000000000000000000000000000000000000000000000c1"
+	client.Objects[suiTypes.MustObjectIdFromHex(registryID).String()] = mustObjectResponse(t,
+		`{"data":{"objectId":"`+registryID+`","version":"4","digest":"`+testObjectDigest+`","owner":{"Shared":{"initial_shared_version":2}}}}`)
+	client.Coins = append(client.Coins, &suiclient.Coin{
+		CoinObjectId: suiTypes.MustObjectIdFromHex("0xc0c1"),
+		Version:      suiTypes.NewBigInt(1),
+		Digest:       suiTypes.MustNewDigest(testObjectDigest),
+		Balance:      suiTypes.NewBigInt(1_000_000_000),
+	})
+
+	challenger := tb.Signer().Address.String()
+	var response suiclient.SuiTransactionBlockResponse
+	if err := json.Unmarshal([]byte(`{"digest":"`+testTxDigest+`","objectChanges":[{"type":"created","sender":"`+challenger+
+		`","owner":{"AddressOwner":"`+challenger+`"},"objectType":"`+tb.PackageId().String()+`::ctf_registry::ChallengeCommitment","objectId":"`+
+		objectID+`","version":"2","digest":"`+testObjectDigest+`"}]}`), &response); err != nil {
+		t.Fatalf("failed to decode transaction response: %v", err)
+	}
+	response.Effects = &suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+		Data: suiclient.SuiTransactionBlockEffects{
+			V1: &suiclient.SuiTransactionBlockEffectsV1{
+				Status: suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess},
+			},
+		},
+	}
+	client.Response = &response
+	client.TransactionNotFound = 2
+
+	typeArgs := TypeArgs{TreasuryCapPositive: "0x2::sui::SUI", TreasuryCapNegative: "0x2::sui::SUI", CoinTypeCollateral: "0x2::sui::SUI"}
+	objID, err := tb.UploadChallengeCommitment(context.Background(), typeArgs, registryID, []byte("commitment"), challenger, testSolverAddr, 1, 1)
+	if err != nil {
+		t.Fatalf("UploadChallengeCommitment() unexpected error: %v", err)
+	}
+	if objID.String() != suiTypes.MustObjectIdFromHex(objectID).String() {
+		t.Errorf("expected object %s, got %s", objectID, objID)
+	}
+	if client.TransactionLookups != 3 {
+		t.Errorf("expected the upload to poll until the transaction was found, got %d lookups", client.TransactionLookups)
+	}
+}
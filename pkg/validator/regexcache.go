@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sync"
+)
+
+// Limits applied to Regex rule patterns. Go's regexp runs in linear time, so these bound
+// memory and per-answer cost rather than backtracking.
+const (
+	DefaultRegexCacheSize       = 256   // Compiled patterns kept by a validator
+	DefaultMaxRegexLength       = 1024  // Longest pattern accepted, in bytes
+	DefaultMaxRegexInstructions = 10000 // Largest compiled program accepted, e.g. from nested repetition
+)
+
+// regexCache is a least-recently-used cache of compiled patterns.
+// Only patterns that compiled and passed the limits are stored.
+type regexCache struct {
+	mu              sync.Mutex
+	capacity        int                      // Entries kept; 0 disables caching
+	maxLength       int                      // Longest pattern accepted
+	maxInstructions int                      // Largest compiled program accepted
+	order           *list.List               // Most recently used first; values are *regexEntry
+	entries         map[string]*list.Element // Pattern to its element in order
+}
+
+type regexEntry struct {
+	pattern string
+	regex   *regexp.Regexp
+}
+
+func newRegexCache(capacity int) *regexCache {
+	return &regexCache{
+		capacity:        capacity,
+		maxLength:       DefaultMaxRegexLength,
+		maxInstructions: DefaultMaxRegexInstructions,
+		order:           list.New(),
+		entries:         make(map[string]*list.Element),
+	}
+}
+
+// get returns the compiled pattern, compiling and caching it on first use
+func (c *regexCache) get(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*regexEntry).regex, nil
+	}
+	maxLength, maxInstructions := c.maxLength, c.maxInstructions
+	c.mu.Unlock()
+
+	// Compile outside the lock; two callers racing on a new pattern both compile it
+	regex, err := compileRegex(pattern, maxLength, maxInstructions)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity <= 0 {
+		return regex, nil
+	}
+	if elem, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*regexEntry).regex, nil
+	}
+	c.entries[pattern] = c.order.PushFront(&regexEntry{pattern: pattern, regex: regex})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexEntry).pattern)
+	}
+	return regex, nil
+}
+
+// len returns the number of cached patterns
+func (c *regexCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// compileRegex compiles pattern after checking it against the length and program size limits
+func compileRegex(pattern string, maxLength, maxInstructions int) (*regexp.Regexp, error) {
+	if maxLength > 0 && len(pattern) > maxLength {
+		return nil, fmt.Errorf("regex pattern is %d bytes, longer than the limit of %d", len(pattern), maxLength)
+	}
+
+	if maxInstructions > 0 {
+		parsed, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regex pattern: %w", err)
+		}
+		prog, err := syntax.Compile(parsed.Simplify())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regex pattern: %w", err)
+		}
+		if len(prog.Inst) > maxInstructions {
+			return nil, fmt.Errorf("regex pattern compiles to %d instructions, more than the limit of %d", len(prog.Inst), maxInstructions)
+		}
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile regex pattern: %w", err)
+	}
+	return regex, nil
+}
@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidator_RegexCache(t *testing.T) {
+	validator := NewValidator()
+	rule := CreateRegexRule(`^\d{3}-\d{4}$`)
+
+	for i := 0; i < 3; i++ {
+		ok, err := validator.ValidateAnswer(rule, "555-1234")
+		if err != nil || !ok {
+			t.Fatalf("ValidateAnswer() = %v, %v; want true, nil", ok, err)
+		}
+	}
+	if got := validator.regexes.len(); got != 1 {
+		t.Errorf("Expected the pattern to be compiled once and cached, got %d entries", got)
+	}
+
+	first, _ := validator.regexes.get(`^\d{3}-\d{4}$`)
+	second, _ := validator.regexes.get(`^\d{3}-\d{4}$`)
+	if first != second {
+		t.Error("Expected the cached *regexp.Regexp to be reused")
+	}
+}
+
+func TestValidator_RegexCache_InvalidPatternNotCached(t *testing.T) {
+	validator := NewValidator()
+
+	for i := 0; i < 2; i++ {
+		_, err := validator.ValidateAnswer(CreateRegexRule(`([a-z`), "abc")
+		if err == nil || !strings.Contains(err.Error(), "failed to compile regex pattern") {
+			t.Fatalf("Expected a compile error, got %v", err)
+		}
+	}
+	if got := validator.regexes.len(); got != 0 {
+		t.Errorf("Expected the invalid pattern not to be cached, got %d entries", got)
+	}
+}
+
+func TestValidator_RegexCache_Evicts(t *testing.T) {
+	validator := NewValidator()
+	validator.SetRegexCacheSize(2)
+
+	for _, pattern := range []string{"^a$", "^b$", "^a$", "^c$"} {
+		if _, err := validator.ValidateAnswer(CreateRegexRule(pattern), "a"); err != nil {
+			t.Fatalf("ValidateAnswer(%q) unexpected error: %v", pattern, err)
+		}
+	}
+
+	// "^b$" was the least recently used when "^c$" arrived
+	if got := validator.regexes.len(); got != 2 {
+		t.Fatalf("Expected 2 cached patterns, got %d", got)
+	}
+	if _, ok := validator.regexes.entries["^b$"]; ok {
+		t.Error("Expected the least recently used pattern to be evicted")
+	}
+	if _, ok := validator.regexes.entries["^a$"]; !ok {
+		t.Error("Expected the recently used pattern to stay cached")
+	}
+}
+
+func TestValidator_RegexLimits(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr string
+	}{
+		{name: "too long", pattern: strings.Repeat("a", DefaultMaxRegexLength+1), wantErr: "longer than the limit"},
+		{name: "large program", pattern: strings.Repeat(`[a-z]{1000}`, 11), wantErr: "instructions"},
+		{name: "within limits", pattern: `^[a-z]{1,20}@example\.com$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ValidateAnswer(CreateRegexRule(tt.pattern), "a")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Limits are configurable; zero disables them
+	validator.SetRegexLimits(0, 0)
+	if _, err := validator.ValidateAnswer(CreateRegexRule(strings.Repeat("a", DefaultMaxRegexLength+1)), "a"); err != nil {
+		t.Errorf("Expected no length limit after SetRegexLimits(0, 0), got %v", err)
+	}
+}
+
+func TestValidator_RegexCache_Concurrent(t *testing.T) {
+	validator := NewValidator()
+	validator.SetRegexCacheSize(4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pattern := fmt.Sprintf("^x%d$", i%8)
+			if ok, err := validator.ValidateAnswer(CreateRegexRule(pattern), fmt.Sprintf("x%d", i%8)); err != nil || !ok {
+				t.Errorf("ValidateAnswer(%q) = %v, %v", pattern, ok, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := validator.regexes.len(); got > 4 {
+		t.Errorf("Expected at most 4 cached patterns, got %d", got)
+	}
+}
+
+// BenchmarkValidator_Regex compares repeated validation of one rule with the cache and
+// with a fresh regexp.Compile per answer, as before the cache existed.
+func BenchmarkValidator_Regex(b *testing.B) {
+	rule := CreateRegexRule(`^[A-Z][a-z]+ [A-Z][a-z]+, \d{1,5} [A-Za-z ]+ (St|Ave|Rd)\.?$`)
+	answer := "Jane Doe, 1234 Elm Grove Ave."
+
+	b.Run("cached", func(b *testing.B) {
+		validator := NewValidator()
+		for i := 0; i < b.N; i++ {
+			if _, err := validator.ValidateAnswer(rule, answer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		validator := NewValidator()
+		validator.SetRegexCacheSize(0)
+		for i := 0; i < b.N; i++ {
+			if _, err := validator.ValidateAnswer(rule, answer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("regexp.Compile", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			regex, err := regexp.Compile(`^[A-Z][a-z]+ [A-Z][a-z]+, \d{1,5} [A-Za-z ]+ (St|Ave|Rd)\.?$`)
+			if err != nil {
+				b.Fatal(err)
+			}
+			regex.MatchString(answer)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"fmt"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+// ValidatorFunc validates receivedAnswer against a rule of a custom type, returning
+// whether the answer is valid. Errors are for malformed rules or answers, as with the
+// built-in types. It may be called concurrently.
+type ValidatorFunc func(rule models.ValidationRule, receivedAnswer string) (bool, error)
+
+// builtinTypes are the rule types ValidateAnswer handles itself
+var builtinTypes = map[string]bool{
+	"ExactMatch":       true,
+	"NumericTolerance": true,
+	"Regex":            true,
+	"FuzzyMatch":       true,
+	"SetMembership":    true,
+	"JSONSchema":       true,
+	"Composite":        true,
+	"Pipeline":         true,
+}
+
+// RegisterValidator adds a custom rule type, such as a proof-of-work or signature check.
+// Rules of that type, including ones nested in Composite and Pipeline rules, are passed
+// to fn. Registering a type again replaces its function. Built-in types cannot be replaced.
+func (v *Validator) RegisterValidator(typeName string, fn ValidatorFunc) error {
+	if typeName == "" {
+		return fmt.Errorf("validation rule type name is required")
+	}
+	if fn == nil {
+		return fmt.Errorf("validator function for type %s is nil", typeName)
+	}
+	if builtinTypes[typeName] {
+		return fmt.Errorf("validation rule type %s is built in and cannot be replaced", typeName)
+	}
+
+	v.customMu.Lock()
+	defer v.customMu.Unlock()
+	v.custom[typeName] = fn
+	return nil
+}
+
+// customValidator returns the function registered for typeName, if any
+func (v *Validator) customValidator(typeName string) (ValidatorFunc, bool) {
+	v.customMu.RLock()
+	defer v.customMu.RUnlock()
+	fn, ok := v.custom[typeName]
+	return fn, ok
+}
@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"reverse-challenge-system/pkg/models"
+)
+
+func TestValidator_RegisterValidator(t *testing.T) {
+	validator := NewValidator()
+	alwaysTrue := func(models.ValidationRule, string) (bool, error) { return true, nil }
+	alwaysFalse := func(models.ValidationRule, string) (bool, error) { return false, nil }
+	if err := validator.RegisterValidator("alwaysTrue", alwaysTrue); err != nil {
+		t.Fatalf("RegisterValidator(alwaysTrue) unexpected error: %v", err)
+	}
+	if err := validator.RegisterValidator("alwaysFalse", alwaysFalse); err != nil {
+		t.Fatalf("RegisterValidator(alwaysFalse) unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		rule           models.ValidationRule
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		{
+			name:           "always true",
+			rule:           models.ValidationRule{Type: "alwaysTrue"},
+			receivedAnswer: "anything",
+			expectedValid:  true,
+		},
+		{
+			name:           "always false",
+			rule:           models.ValidationRule{Type: "alwaysFalse"},
+			receivedAnswer: "anything",
+			expectedValid:  false,
+		},
+		{
+			name:           "built-in types still work",
+			rule:           CreateExactMatchRule("42", true),
+			receivedAnswer: "42",
+			expectedValid:  true,
+		},
+		{
+			name: "nested in a composite rule",
+			rule: CreateCompositeRule("all", "",
+				models.ValidationRule{Type: "alwaysTrue"},
+				CreateRegexRule(`^\d+$`),
+			),
+			receivedAnswer: "42",
+			expectedValid:  true,
+		},
+		{
+			name:           "unregistered type",
+			rule:           models.ValidationRule{Type: "alwaysMaybe"},
+			receivedAnswer: "anything",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := validator.ValidateAnswer(tt.rule, tt.receivedAnswer)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got %v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
+func TestValidator_RegisterValidator_ReceivesRule(t *testing.T) {
+	validator := NewValidator()
+	errBadProof := errors.New("bad proof")
+	// A toy proof-of-work check: the answer must start with the rule's answer
+	err := validator.RegisterValidator("Prefix", func(rule models.ValidationRule, receivedAnswer string) (bool, error) {
+		if rule.Answer == "" {
+			return false, errBadProof
+		}
+		return strings.HasPrefix(receivedAnswer, rule.Answer), nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterValidator() unexpected error: %v", err)
+	}
+
+	if ok, err := validator.ValidateAnswer(models.ValidationRule{Type: "Prefix", Answer: "000"}, "000abc"); err != nil || !ok {
+		t.Errorf("ValidateAnswer() = %v, %v; want true, nil", ok, err)
+	}
+	if ok, err := validator.ValidateAnswer(models.ValidationRule{Type: "Prefix", Answer: "000"}, "001abc"); err != nil || ok {
+		t.Errorf("ValidateAnswer() = %v, %v; want false, nil", ok, err)
+	}
+	if _, err := validator.ValidateAnswer(models.ValidationRule{Type: "Prefix"}, "000abc"); !errors.Is(err, errBadProof) {
+		t.Errorf("Expected the custom validator's error, got %v", err)
+	}
+	if err := validator.CheckRule(models.ValidationRule{Type: "Prefix"}); !errors.Is(err, errBadProof) {
+		t.Errorf("Expected CheckRule to use the custom validator, got %v", err)
+	}
+}
+
+func TestValidator_RegisterValidator_Rejects(t *testing.T) {
+	validator := NewValidator()
+	fn := func(models.ValidationRule, string) (bool, error) { return true, nil }
+
+	tests := []struct {
+		name     string
+		typeName string
+		fn       ValidatorFunc
+	}{
+		{name: "empty type name", typeName: "", fn: fn},
+		{name: "nil function", typeName: "Custom", fn: nil},
+		{name: "built-in type", typeName: "ExactMatch", fn: fn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validator.RegisterValidator(tt.typeName, tt.fn); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+
+	// The built-in type is untouched
+	if ok, err := validator.ValidateAnswer(CreateExactMatchRule("a", true), "b"); err != nil || ok {
+		t.Errorf("ValidateAnswer() = %v, %v; want false, nil", ok, err)
+	}
+}
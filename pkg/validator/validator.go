@@ -1,33 +1,118 @@
 // Package validator provides answer validation functionality for the Reverse Challenge System.
-// Supports multiple validation types including exact matching, numeric tolerance, and regex patterns.
+// Supports multiple validation types including exact and fuzzy matching, numeric tolerance, regex patterns,
+// JSON Schema validation of structured answers, and composite rules combining them.
 // Used by challengers to verify solver responses against expected answers.
 package validator
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
 
 	"reverse-challenge-system/pkg/models"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
+// PipelineStageError reports the pipeline stage that rejected an answer.
+// Err is set when the stage failed because of a configuration or parsing error
+// rather than a plain mismatch.
+type PipelineStageError struct {
+	Stage string // Name of the failing stage
+	Err   error  // Underlying error, if any
+}
+
+// Error implements the error interface.
+func (e *PipelineStageError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("pipeline stage %q failed: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("pipeline stage %q failed", e.Stage)
+}
+
+// Unwrap returns the underlying stage error.
+func (e *PipelineStageError) Unwrap() error {
+	return e.Err
+}
+
+// MaxRuleDepth is the maximum nesting depth of Composite and Pipeline rules.
+// Deeper rules are rejected to guard against runaway recursion.
+const MaxRuleDepth = 8
+
 // Validator provides methods for validating solver answers against challenge solutions.
 // Supports different validation strategies based on the challenge requirements.
-type Validator struct{}
+// Safe for concurrent use; compiled Regex patterns are cached across calls.
+type Validator struct {
+	regexes *regexCache // Compiled Regex rule patterns, least recently used evicted first
+
+	customMu sync.RWMutex
+	custom   map[string]ValidatorFunc // Rule types added with RegisterValidator
+}
 
 // NewValidator creates a new validator instance.
 // Returns a validator ready to process validation rules.
 func NewValidator() *Validator {
-	return &Validator{}
+	return &Validator{
+		regexes: newRegexCache(DefaultRegexCacheSize),
+		custom:  make(map[string]ValidatorFunc),
+	}
+}
+
+// SetRegexCacheSize changes how many compiled Regex patterns are kept (0 disables caching).
+// Must be called before the validator is used.
+func (v *Validator) SetRegexCacheSize(size int) {
+	v.regexes.capacity = size
+}
+
+// SetRegexLimits bounds the Regex patterns rules may use: their length in bytes and the
+// size of the compiled program. Patterns over a limit are reported as errors. A zero
+// value disables that limit. Must be called before the validator is used.
+func (v *Validator) SetRegexLimits(maxLength, maxInstructions int) {
+	v.regexes.maxLength = maxLength
+	v.regexes.maxInstructions = maxInstructions
 }
 
 // ValidateAnswer validates a solver's answer against the specified validation rule.
 // Routes to the appropriate validation method based on the rule type.
 // Returns true if the answer is valid, false otherwise, along with any validation errors.
 func (v *Validator) ValidateAnswer(rule models.ValidationRule, receivedAnswer string) (bool, error) {
+	return v.validate(rule, receivedAnswer, 0)
+}
+
+// CheckRule reports whether rule is well formed, so a broken rule is caught when a challenge
+// is created rather than when its first answer arrives. It validates a probe against the
+// rule: the rule's own answer, or "null" when it has none (JSONSchema rules usually don't).
+// Only errors count; whether the probe matches does not. Nested rules skipped by a
+// short-circuiting Composite or Pipeline are not checked.
+func (v *Validator) CheckRule(rule models.ValidationRule) error {
+	probe := rule.Answer
+	if probe == "" {
+		probe = "null"
+	}
+
+	_, err := v.validate(rule, probe, 0)
+	var stageErr *PipelineStageError
+	if errors.As(err, &stageErr) && stageErr.Err == nil {
+		// The probe was rejected by a stage, which says nothing about the rule
+		return nil
+	}
+	return err
+}
+
+// validate dispatches on the rule type, tracking how deeply rules are nested.
+// Types that are not built in go to the functions added with RegisterValidator.
+func (v *Validator) validate(rule models.ValidationRule, receivedAnswer string, depth int) (bool, error) {
+	if depth > MaxRuleDepth {
+		return false, fmt.Errorf("validation rule nesting exceeds maximum depth of %d", MaxRuleDepth)
+	}
+
 	switch rule.Type {
 	case "ExactMatch":
 		return v.validateExactMatch(rule, receivedAnswer)
@@ -35,13 +120,26 @@ func (v *Validator) ValidateAnswer(rule models.ValidationRule, receivedAnswer st
 		return v.validateNumericTolerance(rule, receivedAnswer)
 	case "Regex":
 		return v.validateRegex(rule, receivedAnswer)
+	case "FuzzyMatch":
+		return v.validateFuzzyMatch(rule, receivedAnswer)
+	case "SetMembership":
+		return v.validateSetMembership(rule, receivedAnswer)
+	case "JSONSchema":
+		return v.validateJSONSchema(rule, receivedAnswer)
+	case "Composite":
+		return v.validateComposite(rule, receivedAnswer, depth)
+	case "Pipeline":
+		return v.validatePipeline(rule, receivedAnswer, depth)
 	default:
+		if fn, ok := v.customValidator(rule.Type); ok {
+			return fn(rule, receivedAnswer)
+		}
 		return false, fmt.Errorf("unknown validation rule type: %s", rule.Type)
 	}
 }
 
 // validateExactMatch performs exact string matching validation with optional case sensitivity.
-// Compares the received answer directly with the expected answer.
+// Whitespace trimming and normalization, when enabled, apply to both answers before comparison.
 // Defaults to case-sensitive comparison if no parameters are provided.
 func (v *Validator) validateExactMatch(rule models.ValidationRule, receivedAnswer string) (bool, error) {
 	var params models.ExactMatchParams
@@ -55,16 +153,46 @@ func (v *Validator) validateExactMatch(rule models.ValidationRule, receivedAnswe
 		}
 	}
 
+	expected := rule.Answer
+	if params.TrimSpace {
+		expected = strings.TrimSpace(expected)
+		receivedAnswer = strings.TrimSpace(receivedAnswer)
+	}
+	if params.NormalizeWhitespace {
+		expected = normalizeWhitespace(expected)
+		receivedAnswer = normalizeWhitespace(receivedAnswer)
+	}
+
 	if params.CaseSensitive {
-		return rule.Answer == receivedAnswer, nil
+		return expected == receivedAnswer, nil
 	}
 
-	return strings.EqualFold(rule.Answer, receivedAnswer), nil
+	return strings.EqualFold(expected, receivedAnswer), nil
+}
+
+// normalizeWhitespace collapses each internal run of whitespace to a single space.
+// Leading and trailing whitespace is kept as a single space so trimming stays a separate option.
+func normalizeWhitespace(s string) string {
+	var b strings.Builder
+	inSpace := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				b.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
 // validateNumericTolerance performs numeric validation with a specified tolerance.
 // Parses both expected and received answers as floating-point numbers and checks
-// if the absolute difference is within the allowed tolerance.
+// if the absolute difference is within the allowed tolerance or, in relative mode,
+// within a fraction of the expected value: |received-expected| <= relTol * |expected|.
 func (v *Validator) validateNumericTolerance(rule models.ValidationRule, receivedAnswer string) (bool, error) {
 	var params models.NumericToleranceParams
 
@@ -76,22 +204,64 @@ func (v *Validator) validateNumericTolerance(rule models.ValidationRule, receive
 		return false, fmt.Errorf("failed to unmarshal NumericTolerance params: %w", err)
 	}
 
-	expectedValue, err := strconv.ParseFloat(rule.Answer, 64)
+	if params.RelativeTolerance < 0 {
+		return false, fmt.Errorf("NumericTolerance relative_tolerance must not be negative")
+	}
+
+	expectedValue, err := parseNumber(rule.Answer, params)
 	if err != nil {
 		return false, fmt.Errorf("failed to parse expected answer as float: %w", err)
 	}
 
-	receivedValue, err := strconv.ParseFloat(receivedAnswer, 64)
+	receivedValue, err := parseNumber(receivedAnswer, params)
 	if err != nil {
 		return false, fmt.Errorf("failed to parse received answer as float: %w", err)
 	}
 
+	if params.RequireInteger && receivedValue != math.Trunc(receivedValue) {
+		return false, nil
+	}
+
 	diff := math.Abs(expectedValue - receivedValue)
+	if params.Relative && expectedValue != 0 {
+		relTol := params.RelativeTolerance
+		if relTol == 0 {
+			relTol = params.Tolerance
+		}
+		return diff <= relTol*math.Abs(expectedValue), nil
+	}
+	// A relative bound around 0 would only accept exactly 0, so zero falls back to the absolute tolerance
 	return diff <= params.Tolerance, nil
 }
 
+// thousandsPattern matches numbers whose integer part is grouped in threes with commas
+var thousandsPattern = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d*)?([eE][+-]?\d+)?$`)
+
+// parseNumber parses a numeric answer with the formats params allow: a unit suffix is
+// stripped when Units lists it, and commas are removed when ThousandsSeparator is set and
+// they group the digits correctly. Everything else goes through a strict float parse.
+func parseNumber(answer string, params models.NumericToleranceParams) (float64, error) {
+	if len(params.Units) > 0 {
+		answer = strings.TrimSpace(answer)
+		// Prefer the longest suffix so "km" is not stripped as "m"
+		longest := ""
+		for _, unit := range params.Units {
+			if unit != "" && strings.HasSuffix(answer, unit) && len(unit) > len(longest) {
+				longest = unit
+			}
+		}
+		answer = strings.TrimSpace(strings.TrimSuffix(answer, longest))
+	}
+
+	if params.ThousandsSeparator && thousandsPattern.MatchString(answer) {
+		answer = strings.ReplaceAll(answer, ",", "")
+	}
+
+	return strconv.ParseFloat(answer, 64)
+}
+
 // validateRegex performs pattern matching validation using regular expressions.
-// Compiles the regex pattern from parameters and tests it against the received answer.
+// Compiles the regex pattern from parameters, once per distinct pattern, and tests it against the received answer.
 // Useful for flexible text validation where exact matches are not required.
 func (v *Validator) validateRegex(rule models.ValidationRule, receivedAnswer string) (bool, error) {
 	var params models.RegexParams
@@ -104,14 +274,273 @@ func (v *Validator) validateRegex(rule models.ValidationRule, receivedAnswer str
 		return false, fmt.Errorf("failed to unmarshal Regex params: %w", err)
 	}
 
-	regex, err := regexp.Compile(params.Pattern)
+	regex, err := v.regexes.get(params.Pattern)
 	if err != nil {
-		return false, fmt.Errorf("failed to compile regex pattern: %w", err)
+		return false, err
 	}
 
 	return regex.MatchString(receivedAnswer), nil
 }
 
+// validateFuzzyMatch accepts answers within a maximum Levenshtein distance of the expected answer.
+// Distance is measured in runes so multi-byte characters count as a single edit.
+func (v *Validator) validateFuzzyMatch(rule models.ValidationRule, receivedAnswer string) (bool, error) {
+	var params models.FuzzyMatchParams
+
+	if rule.Params == nil {
+		return false, fmt.Errorf("FuzzyMatch validation requires params")
+	}
+
+	if err := json.Unmarshal(rule.Params, &params); err != nil {
+		return false, fmt.Errorf("failed to unmarshal FuzzyMatch params: %w", err)
+	}
+
+	if params.MaxDistance < 0 {
+		return false, fmt.Errorf("FuzzyMatch max_distance must not be negative")
+	}
+
+	expected := rule.Answer
+	if !params.CaseSensitive {
+		expected = strings.ToLower(expected)
+		receivedAnswer = strings.ToLower(receivedAnswer)
+	}
+
+	return Levenshtein(expected, receivedAnswer) <= params.MaxDistance, nil
+}
+
+// validateSetMembership accepts answers equal to any entry of the accepted set.
+// An empty set is a misconfigured rule and reported as an error, not as a mismatch.
+func (v *Validator) validateSetMembership(rule models.ValidationRule, receivedAnswer string) (bool, error) {
+	var params models.SetMembershipParams
+
+	if rule.Params == nil {
+		return false, fmt.Errorf("SetMembership validation requires params")
+	}
+
+	if err := json.Unmarshal(rule.Params, &params); err != nil {
+		return false, fmt.Errorf("failed to unmarshal SetMembership params: %w", err)
+	}
+
+	if len(params.Answers) == 0 {
+		return false, fmt.Errorf("SetMembership validation requires at least one answer")
+	}
+
+	for _, answer := range params.Answers {
+		if params.CaseSensitive && answer == receivedAnswer {
+			return true, nil
+		}
+		if !params.CaseSensitive && strings.EqualFold(answer, receivedAnswer) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Levenshtein returns the minimum number of single-rune insertions, deletions,
+// or substitutions needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// Keep only the previous row of the distance matrix
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// validateJSONSchema validates a structured JSON answer against a JSON Schema document.
+// A malformed schema or an answer that is not valid JSON is reported as an error;
+// a well-formed answer that does not satisfy the schema is simply invalid.
+func (v *Validator) validateJSONSchema(rule models.ValidationRule, receivedAnswer string) (bool, error) {
+	var params models.JSONSchemaParams
+
+	if rule.Params == nil {
+		return false, fmt.Errorf("JSONSchema validation requires params")
+	}
+
+	if err := json.Unmarshal(rule.Params, &params); err != nil {
+		return false, fmt.Errorf("failed to unmarshal JSONSchema params: %w", err)
+	}
+
+	if len(params.Schema) == 0 {
+		return false, fmt.Errorf("JSONSchema validation requires a schema")
+	}
+
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(params.Schema))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("answer-schema.json", schemaDoc); err != nil {
+		return false, fmt.Errorf("failed to load JSON schema: %w", err)
+	}
+
+	schema, err := compiler.Compile("answer-schema.json")
+	if err != nil {
+		return false, fmt.Errorf("failed to compile JSON schema: %w", err)
+	}
+
+	answer, err := jsonschema.UnmarshalJSON(strings.NewReader(receivedAnswer))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse received answer as JSON: %w", err)
+	}
+
+	if err := schema.Validate(answer); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if errors.As(err, &validationErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to validate answer against JSON schema: %w", err)
+	}
+
+	return true, nil
+}
+
+// validateComposite combines nested rules with an "all" or "any" operator.
+// Nested rules without their own answer inherit the composite answer.
+// Errors from any nested rule are returned rather than treated as a mismatch.
+func (v *Validator) validateComposite(rule models.ValidationRule, receivedAnswer string, depth int) (bool, error) {
+	var params models.CompositeParams
+
+	if rule.Params == nil {
+		return false, fmt.Errorf("Composite validation requires params")
+	}
+
+	if err := json.Unmarshal(rule.Params, &params); err != nil {
+		return false, fmt.Errorf("failed to unmarshal Composite params: %w", err)
+	}
+
+	if params.Operator != "all" && params.Operator != "any" {
+		return false, fmt.Errorf("unknown Composite operator: %q", params.Operator)
+	}
+
+	if len(params.Rules) == 0 {
+		return false, fmt.Errorf("Composite validation requires at least one rule")
+	}
+
+	for _, child := range params.Rules {
+		if child.Answer == "" {
+			child.Answer = rule.Answer
+		}
+
+		ok, err := v.validate(child, receivedAnswer, depth+1)
+		var stageErr *PipelineStageError
+		if errors.As(err, &stageErr) && stageErr.Err == nil {
+			// A nested pipeline that rejected the answer is a mismatch, not an error
+			ok, err = false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if params.Operator == "all" && !ok {
+			return false, nil
+		}
+		if params.Operator == "any" && ok {
+			return true, nil
+		}
+	}
+
+	// Every rule passed for "all"; none passed for "any"
+	return params.Operator == "all", nil
+}
+
+// validatePipeline runs an ordered list of transform and rule stages.
+// Transform stages rewrite the answer seen by later stages; rule stages validate it.
+// The first failing rule stage short-circuits the pipeline and is reported via PipelineStageError.
+func (v *Validator) validatePipeline(rule models.ValidationRule, receivedAnswer string, depth int) (bool, error) {
+	var params models.PipelineParams
+
+	if rule.Params == nil {
+		return false, fmt.Errorf("Pipeline validation requires params")
+	}
+
+	if err := json.Unmarshal(rule.Params, &params); err != nil {
+		return false, fmt.Errorf("failed to unmarshal Pipeline params: %w", err)
+	}
+
+	if len(params.Stages) == 0 {
+		return false, fmt.Errorf("Pipeline validation requires at least one stage")
+	}
+
+	answer := receivedAnswer
+	for i, stage := range params.Stages {
+		name := stage.Name
+		if name == "" {
+			name = fmt.Sprintf("stage_%d", i)
+		}
+
+		switch {
+		case stage.Transform != "" && stage.Rule != nil:
+			return false, &PipelineStageError{Stage: name, Err: errors.New("stage cannot have both transform and rule")}
+		case stage.Transform != "":
+			transformed, err := applyTransform(stage.Transform, answer)
+			if err != nil {
+				return false, &PipelineStageError{Stage: name, Err: err}
+			}
+			answer = transformed
+		case stage.Rule != nil:
+			stageRule := *stage.Rule
+			if stageRule.Answer == "" {
+				stageRule.Answer = rule.Answer
+			}
+			ok, err := v.validate(stageRule, answer, depth+1)
+			if err != nil {
+				return false, &PipelineStageError{Stage: name, Err: err}
+			}
+			if !ok {
+				return false, &PipelineStageError{Stage: name}
+			}
+		default:
+			return false, &PipelineStageError{Stage: name, Err: errors.New("stage requires a transform or a rule")}
+		}
+	}
+
+	return true, nil
+}
+
+// applyTransform applies a named normalization to the answer.
+// Returns an error for unknown transform names.
+func applyTransform(transform, answer string) (string, error) {
+	switch transform {
+	case "trim":
+		return strings.TrimSpace(answer), nil
+	case "lowercase":
+		return strings.ToLower(answer), nil
+	case "uppercase":
+		return strings.ToUpper(answer), nil
+	case "collapse_whitespace":
+		return strings.Join(strings.Fields(answer), " "), nil
+	case "remove_whitespace":
+		return strings.Join(strings.Fields(answer), ""), nil
+	default:
+		return "", fmt.Errorf("unknown transform: %s", transform)
+	}
+}
+
 // Helper functions to create validation rules
 
 // CreateExactMatchRule creates a validation rule for exact string matching.
@@ -128,6 +557,19 @@ func CreateExactMatchRule(answer string, caseSensitive bool) models.ValidationRu
 	}
 }
 
+// CreateExactMatchRuleWithOptions creates an exact-match rule with full control over
+// case sensitivity and whitespace handling.
+// Useful when solvers may return answers with stray spaces or trailing newlines.
+func CreateExactMatchRuleWithOptions(answer string, options models.ExactMatchParams) models.ValidationRule {
+	paramsJSON, _ := json.Marshal(options)
+
+	return models.ValidationRule{
+		Type:   "ExactMatch",
+		Params: paramsJSON,
+		Answer: answer,
+	}
+}
+
 // CreateNumericToleranceRule creates a validation rule for numeric answers with tolerance.
 // Allows for small differences between expected and received numeric values.
 // Ideal for mathematical challenges where floating-point precision may vary.
@@ -142,6 +584,18 @@ func CreateNumericToleranceRule(answer string, tolerance float64) models.Validat
 	}
 }
 
+// CreateNumericToleranceRuleWithOptions creates a numeric validation rule with every
+// parsing option exposed: unit suffixes, thousands separators and integer-only answers.
+func CreateNumericToleranceRuleWithOptions(answer string, options models.NumericToleranceParams) models.ValidationRule {
+	paramsJSON, _ := json.Marshal(options)
+
+	return models.ValidationRule{
+		Type:   "NumericTolerance",
+		Params: paramsJSON,
+		Answer: answer,
+	}
+}
+
 // CreateRegexRule creates a validation rule for pattern-based matching.
 // Uses regular expressions to validate answers against flexible patterns.
 // Useful for challenges where multiple valid answer formats are acceptable.
@@ -155,3 +609,73 @@ func CreateRegexRule(pattern string) models.ValidationRule {
 		Answer: "", // For regex, we don't store a specific answer
 	}
 }
+
+// CreateFuzzyMatchRule creates a validation rule that tolerates small typos.
+// Answers within maxDistance Levenshtein edits of the expected answer are accepted.
+// Ideal for OCR and transcription challenges where near misses should still count.
+func CreateFuzzyMatchRule(answer string, maxDistance int, caseSensitive bool) models.ValidationRule {
+	params := models.FuzzyMatchParams{MaxDistance: maxDistance, CaseSensitive: caseSensitive}
+	paramsJSON, _ := json.Marshal(params)
+
+	return models.ValidationRule{
+		Type:   "FuzzyMatch",
+		Params: paramsJSON,
+		Answer: answer,
+	}
+}
+
+// CreateSetMembershipRule creates a validation rule accepting any of several answers.
+// The received answer must equal one entry of answers, ignoring case unless caseSensitive.
+// Useful for challenges with synonyms or alternate formats of the same answer.
+func CreateSetMembershipRule(answers []string, caseSensitive bool) models.ValidationRule {
+	params := models.SetMembershipParams{Answers: answers, CaseSensitive: caseSensitive}
+	paramsJSON, _ := json.Marshal(params)
+
+	return models.ValidationRule{
+		Type:   "SetMembership",
+		Params: paramsJSON,
+		Answer: "", // The accepted set is the expected answer
+	}
+}
+
+// CreateJSONSchemaRule creates a validation rule for structured JSON answers.
+// The answer must parse as JSON and satisfy the given JSON Schema document.
+// Useful for challenges that expect objects or arrays rather than plain text.
+func CreateJSONSchemaRule(schema json.RawMessage) models.ValidationRule {
+	params := models.JSONSchemaParams{Schema: schema}
+	paramsJSON, _ := json.Marshal(params)
+
+	return models.ValidationRule{
+		Type:   "JSONSchema",
+		Params: paramsJSON,
+		Answer: "", // The schema is the expected answer
+	}
+}
+
+// CreateCompositeRule creates a validation rule that combines nested rules.
+// The operator is "all" (every rule must pass) or "any" (one rule must pass).
+// Nested rules without their own answer are checked against the composite answer.
+func CreateCompositeRule(operator, answer string, rules ...models.ValidationRule) models.ValidationRule {
+	params := models.CompositeParams{Operator: operator, Rules: rules}
+	paramsJSON, _ := json.Marshal(params)
+
+	return models.ValidationRule{
+		Type:   "Composite",
+		Params: paramsJSON,
+		Answer: answer,
+	}
+}
+
+// CreatePipelineRule creates a validation rule that runs the given stages in order.
+// Rule stages without their own answer are checked against the pipeline answer.
+// Useful when answers need normalization before a final comparison.
+func CreatePipelineRule(answer string, stages ...models.PipelineStage) models.ValidationRule {
+	params := models.PipelineParams{Stages: stages}
+	paramsJSON, _ := json.Marshal(params)
+
+	return models.ValidationRule{
+		Type:   "Pipeline",
+		Params: paramsJSON,
+		Answer: answer,
+	}
+}
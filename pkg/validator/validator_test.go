@@ -2,6 +2,7 @@ package validator
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"reverse-challenge-system/pkg/models"
@@ -89,6 +90,69 @@ func TestValidator_ValidateExactMatch(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateExactMatch_Whitespace(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name           string
+		answer         string
+		options        models.ExactMatchParams
+		receivedAnswer string
+		expectedValid  bool
+	}{
+		{
+			name:           "NoTrim_Mismatch",
+			answer:         "foo",
+			options:        models.ExactMatchParams{CaseSensitive: true},
+			receivedAnswer: " foo \n",
+			expectedValid:  false,
+		},
+		{
+			name:           "Trim_Match",
+			answer:         "foo",
+			options:        models.ExactMatchParams{CaseSensitive: true, TrimSpace: true},
+			receivedAnswer: " foo \n",
+			expectedValid:  true,
+		},
+		{
+			name:           "NormalizeOnly_KeepsEdges",
+			answer:         "foo bar",
+			options:        models.ExactMatchParams{CaseSensitive: true, NormalizeWhitespace: true},
+			receivedAnswer: " foo \t bar",
+			expectedValid:  false,
+		},
+		{
+			name:           "Normalize_InternalRuns",
+			answer:         "foo bar",
+			options:        models.ExactMatchParams{CaseSensitive: true, NormalizeWhitespace: true},
+			receivedAnswer: "foo \t\n  bar",
+			expectedValid:  true,
+		},
+		{
+			name:           "TrimAndNormalize_CaseInsensitive",
+			answer:         "Hello World",
+			options:        models.ExactMatchParams{TrimSpace: true, NormalizeWhitespace: true},
+			receivedAnswer: "\n hello    world \n",
+			expectedValid:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := CreateExactMatchRuleWithOptions(tt.answer, tt.options)
+
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got valid=%v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
 func TestValidator_ValidateNumericTolerance(t *testing.T) {
 	validator := NewValidator()
 
@@ -187,6 +251,177 @@ func TestValidator_ValidateNumericTolerance(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateNumericToleranceOptions(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name           string
+		answer         string
+		options        models.NumericToleranceParams
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		{
+			name:           "ScientificNotation_WithinTolerance",
+			answer:         "1000",
+			options:        models.NumericToleranceParams{Tolerance: 0.5},
+			receivedAnswer: "1.0002e3",
+			expectedValid:  true,
+		},
+		{
+			name:           "ThousandsSeparator",
+			answer:         "1234567.5",
+			options:        models.NumericToleranceParams{Tolerance: 0.01, ThousandsSeparator: true},
+			receivedAnswer: "1,234,567.5",
+			expectedValid:  true,
+		},
+		{
+			name:           "ThousandsSeparator_Disabled",
+			answer:         "1000",
+			options:        models.NumericToleranceParams{Tolerance: 0.01},
+			receivedAnswer: "1,000",
+			expectError:    true,
+		},
+		{
+			name:           "ThousandsSeparator_MisplacedComma",
+			answer:         "1000",
+			options:        models.NumericToleranceParams{Tolerance: 0.01, ThousandsSeparator: true},
+			receivedAnswer: "10,00",
+			expectError:    true,
+		},
+		{
+			name:           "UnitSuffix",
+			answer:         "42",
+			options:        models.NumericToleranceParams{Tolerance: 0.1, Units: []string{"m", "km"}},
+			receivedAnswer: "42km",
+			expectedValid:  true,
+		},
+		{
+			name:           "UnitSuffix_WithSpace",
+			answer:         "42 km",
+			options:        models.NumericToleranceParams{Tolerance: 0.1, Units: []string{"km"}},
+			receivedAnswer: "42.05 km",
+			expectedValid:  true,
+		},
+		{
+			name:           "UnitSuffix_Disabled",
+			answer:         "42",
+			options:        models.NumericToleranceParams{Tolerance: 0.1},
+			receivedAnswer: "42km",
+			expectError:    true,
+		},
+		{
+			name:           "RequireInteger_Whole",
+			answer:         "7",
+			options:        models.NumericToleranceParams{Tolerance: 0.5, RequireInteger: true},
+			receivedAnswer: "7",
+			expectedValid:  true,
+		},
+		{
+			name:           "RequireInteger_Fractional",
+			answer:         "7",
+			options:        models.NumericToleranceParams{Tolerance: 0.5, RequireInteger: true},
+			receivedAnswer: "7.2",
+			expectedValid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := CreateNumericToleranceRuleWithOptions(tt.answer, tt.options)
+
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got %v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateNumericToleranceRelative(t *testing.T) {
+	validator := NewValidator()
+
+	absolute := func(tolerance float64) models.NumericToleranceParams {
+		return models.NumericToleranceParams{Tolerance: tolerance}
+	}
+	relative := func(fraction float64) models.NumericToleranceParams {
+		return models.NumericToleranceParams{Relative: true, RelativeTolerance: fraction}
+	}
+
+	tests := []struct {
+		name           string
+		answer         string
+		options        models.NumericToleranceParams
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		// Large magnitude: an absolute 0.1 rejects a 0.0001% error that 1% relative accepts
+		{name: "Large_Absolute_Rejects", answer: "1e9", options: absolute(0.1), receivedAnswer: "1000001000", expectedValid: false},
+		{name: "Large_Relative_Accepts", answer: "1e9", options: relative(0.01), receivedAnswer: "1000001000", expectedValid: true},
+		{name: "Large_Relative_Rejects", answer: "1e9", options: relative(0.01), receivedAnswer: "1.02e9", expectedValid: false},
+
+		// Small magnitude: an absolute 0.1 accepts a 50% error that 1% relative rejects
+		{name: "Small_Absolute_Accepts", answer: "0.1", options: absolute(0.1), receivedAnswer: "0.15", expectedValid: true},
+		{name: "Small_Relative_Rejects", answer: "0.1", options: relative(0.01), receivedAnswer: "0.15", expectedValid: false},
+		{name: "Small_Relative_Accepts", answer: "0.1", options: relative(0.01), receivedAnswer: "0.1005", expectedValid: true},
+
+		{name: "Negative_Relative", answer: "-200", options: relative(0.05), receivedAnswer: "-209", expectedValid: true},
+		{
+			name:           "Relative_FallsBackToTolerance",
+			answer:         "500",
+			options:        models.NumericToleranceParams{Relative: true, Tolerance: 0.1},
+			receivedAnswer: "549",
+			expectedValid:  true,
+		},
+		{
+			name:           "ExpectedZero_UsesAbsoluteTolerance",
+			answer:         "0",
+			options:        models.NumericToleranceParams{Relative: true, RelativeTolerance: 0.01, Tolerance: 0.001},
+			receivedAnswer: "0.0005",
+			expectedValid:  true,
+		},
+		{
+			name:           "ExpectedZero_WithoutTolerance",
+			answer:         "0",
+			options:        relative(0.01),
+			receivedAnswer: "0.0005",
+			expectedValid:  false,
+		},
+		{name: "NegativeRelativeTolerance", answer: "1", options: relative(-0.1), receivedAnswer: "1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := CreateNumericToleranceRuleWithOptions(tt.answer, tt.options)
+
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got %v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
 func TestValidator_ValidateRegex(t *testing.T) {
 	validator := NewValidator()
 
@@ -269,6 +504,400 @@ func TestValidator_ValidateRegex(t *testing.T) {
 	}
 }
 
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+		{"日本語", "日本人", 1},
+		{"😀😀", "😀", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidator_ValidateFuzzyMatch(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name           string
+		answer         string
+		maxDistance    int
+		caseSensitive  bool
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		{
+			name:           "ExactMatch_DistanceZero",
+			answer:         "transcript",
+			maxDistance:    0,
+			caseSensitive:  true,
+			receivedAnswer: "transcript",
+			expectedValid:  true,
+		},
+		{
+			name:           "OneTypo_WithinThreshold",
+			answer:         "transcript",
+			maxDistance:    1,
+			caseSensitive:  true,
+			receivedAnswer: "transcrlpt",
+			expectedValid:  true,
+		},
+		{
+			name:           "TwoTypos_OverThreshold",
+			answer:         "transcript",
+			maxDistance:    1,
+			caseSensitive:  true,
+			receivedAnswer: "tramscrlpt",
+			expectedValid:  false,
+		},
+		{
+			name:           "CaseSensitive_CountsCase",
+			answer:         "Hello",
+			maxDistance:    0,
+			caseSensitive:  true,
+			receivedAnswer: "hello",
+			expectedValid:  false,
+		},
+		{
+			name:           "CaseInsensitive_IgnoresCase",
+			answer:         "Hello",
+			maxDistance:    0,
+			caseSensitive:  false,
+			receivedAnswer: "hELLO",
+			expectedValid:  true,
+		},
+		{
+			name:           "Unicode_WithinThreshold",
+			answer:         "東京タワー",
+			maxDistance:    1,
+			caseSensitive:  true,
+			receivedAnswer: "東京タワ",
+			expectedValid:  true,
+		},
+		{
+			name:           "Unicode_OverThreshold",
+			answer:         "naïve café",
+			maxDistance:    1,
+			caseSensitive:  true,
+			receivedAnswer: "naive cafe",
+			expectedValid:  false,
+		},
+		{
+			name:           "NegativeDistance",
+			answer:         "abc",
+			maxDistance:    -1,
+			caseSensitive:  true,
+			receivedAnswer: "abc",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := CreateFuzzyMatchRule(tt.answer, tt.maxDistance, tt.caseSensitive)
+
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got valid=%v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateJSONSchema(t *testing.T) {
+	validator := NewValidator()
+
+	objectSchema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"label": {"type": "string"},
+			"count": {"type": "integer"}
+		},
+		"required": ["label", "count"]
+	}`)
+
+	tests := []struct {
+		name           string
+		schema         json.RawMessage
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		{
+			name:           "Object_Valid",
+			schema:         objectSchema,
+			receivedAnswer: `{"label": "cat", "count": 3}`,
+			expectedValid:  true,
+			expectError:    false,
+		},
+		{
+			name:           "Object_TypeMismatch",
+			schema:         objectSchema,
+			receivedAnswer: `{"label": "cat", "count": "three"}`,
+			expectedValid:  false,
+			expectError:    false,
+		},
+		{
+			name:           "Object_MissingRequired",
+			schema:         objectSchema,
+			receivedAnswer: `{"label": "cat"}`,
+			expectedValid:  false,
+			expectError:    false,
+		},
+		{
+			name:           "MalformedSchema",
+			schema:         json.RawMessage(`{"type": 42}`),
+			receivedAnswer: `{"label": "cat", "count": 3}`,
+			expectedValid:  false,
+			expectError:    true,
+		},
+		{
+			name:           "MalformedAnswer",
+			schema:         objectSchema,
+			receivedAnswer: `{"label": "cat",`,
+			expectedValid:  false,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := CreateJSONSchemaRule(tt.schema)
+
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got valid=%v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateComposite(t *testing.T) {
+	validator := NewValidator()
+
+	numericAndFormat := CreateCompositeRule("all", "3.14",
+		CreateRegexRule(`^\d+\.\d{2}$`),
+		CreateNumericToleranceRule("", 0.005),
+	)
+	anyGreeting := CreateCompositeRule("any", "",
+		CreateExactMatchRule("hello", true),
+		CreateExactMatchRule("hi", true),
+		CreateExactMatchRule("hey", true),
+	)
+	pipelineChild := CreatePipelineRule("", models.PipelineStage{Name: "lowercase", Transform: "lowercase"},
+		models.PipelineStage{Name: "final", Rule: &models.ValidationRule{Type: "ExactMatch"}})
+	anyWithPipeline := CreateCompositeRule("any", "ok", pipelineChild, CreateRegexRule(`^\d+$`))
+
+	tests := []struct {
+		name           string
+		rule           models.ValidationRule
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		{
+			name:           "All_EveryChildPasses",
+			rule:           numericAndFormat,
+			receivedAnswer: "3.14",
+			expectedValid:  true,
+		},
+		{
+			name:           "All_OneChildFails",
+			rule:           numericAndFormat,
+			receivedAnswer: "3.15", // matches the format but is outside tolerance
+			expectedValid:  false,
+		},
+		{
+			name:           "Any_OneChildPasses",
+			rule:           anyGreeting,
+			receivedAnswer: "hi",
+			expectedValid:  true,
+		},
+		{
+			name:           "Any_NoChildPasses",
+			rule:           anyGreeting,
+			receivedAnswer: "goodbye",
+			expectedValid:  false,
+		},
+		{
+			name:           "Any_FailedPipelineIsMismatch",
+			rule:           anyWithPipeline,
+			receivedAnswer: "42",
+			expectedValid:  true,
+		},
+		{
+			name:           "UnknownOperator",
+			rule:           CreateCompositeRule("xor", "", CreateExactMatchRule("a", true)),
+			receivedAnswer: "a",
+			expectError:    true,
+		},
+		{
+			name:           "NoRules",
+			rule:           CreateCompositeRule("all", ""),
+			receivedAnswer: "a",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := validator.ValidateAnswer(tt.rule, tt.receivedAnswer)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got valid=%v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateComposite_DepthLimit(t *testing.T) {
+	validator := NewValidator()
+
+	build := func(levels int) models.ValidationRule {
+		rule := CreateExactMatchRule("a", true)
+		for i := 0; i < levels; i++ {
+			rule = CreateCompositeRule("all", "", rule)
+		}
+		return rule
+	}
+
+	// MaxRuleDepth composites put the leaf rule exactly at the limit
+	if ok, err := validator.ValidateAnswer(build(MaxRuleDepth), "a"); err != nil || !ok {
+		t.Errorf("Expected rule at max depth to pass, got valid=%v err=%v", ok, err)
+	}
+
+	ok, err := validator.ValidateAnswer(build(MaxRuleDepth+1), "a")
+	if err == nil {
+		t.Fatal("Expected depth limit error")
+	}
+	if ok {
+		t.Error("Expected validation to fail past max depth")
+	}
+}
+
+func TestValidator_ValidatePipeline(t *testing.T) {
+	validator := NewValidator()
+
+	exact := CreateExactMatchRule("", true)
+	regex := CreateRegexRule(`^[a-z ]+$`)
+
+	rule := CreatePipelineRule("hello world",
+		models.PipelineStage{Name: "trim", Transform: "trim"},
+		models.PipelineStage{Name: "collapse", Transform: "collapse_whitespace"},
+		models.PipelineStage{Name: "lowercase", Transform: "lowercase"},
+		models.PipelineStage{Name: "charset", Rule: &regex},
+		models.PipelineStage{Name: "final", Rule: &exact},
+	)
+
+	tests := []struct {
+		name           string
+		receivedAnswer string
+		expectedValid  bool
+		failedStage    string
+	}{
+		{
+			name:           "Passes_AfterNormalization",
+			receivedAnswer: "  Hello   WORLD ",
+			expectedValid:  true,
+		},
+		{
+			name:           "Fails_AtCharsetStage",
+			receivedAnswer: "hello world!",
+			expectedValid:  false,
+			failedStage:    "charset",
+		},
+		{
+			name:           "Fails_AtFinalStage",
+			receivedAnswer: "goodbye world",
+			expectedValid:  false,
+			failedStage:    "final",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got valid=%v", tt.expectedValid, isValid)
+			}
+
+			if tt.failedStage == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var stageErr *PipelineStageError
+			if !errors.As(err, &stageErr) {
+				t.Fatalf("Expected PipelineStageError, got %v", err)
+			}
+
+			if stageErr.Stage != tt.failedStage {
+				t.Errorf("Expected failing stage '%s', got '%s'", tt.failedStage, stageErr.Stage)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidatePipeline_InvalidStage(t *testing.T) {
+	validator := NewValidator()
+
+	rule := CreatePipelineRule("test",
+		models.PipelineStage{Name: "bogus", Transform: "reverse"},
+	)
+
+	_, err := validator.ValidateAnswer(rule, "test")
+
+	var stageErr *PipelineStageError
+	if !errors.As(err, &stageErr) {
+		t.Fatalf("Expected PipelineStageError, got %v", err)
+	}
+
+	if stageErr.Stage != "bogus" || stageErr.Err == nil {
+		t.Errorf("Expected wrapped error for stage 'bogus', got %+v", stageErr)
+	}
+}
+
 func TestValidator_UnknownValidationType(t *testing.T) {
 	validator := NewValidator()
 
@@ -315,6 +944,30 @@ func TestValidator_MissingParams(t *testing.T) {
 				Params: nil,
 			},
 		},
+		{
+			name: "FuzzyMatch_MissingParams",
+			rule: models.ValidationRule{
+				Type:   "FuzzyMatch",
+				Answer: "test",
+				Params: nil,
+			},
+		},
+		{
+			name: "Composite_MissingParams",
+			rule: models.ValidationRule{
+				Type:   "Composite",
+				Answer: "",
+				Params: nil,
+			},
+		},
+		{
+			name: "JSONSchema_MissingParams",
+			rule: models.ValidationRule{
+				Type:   "JSONSchema",
+				Answer: "",
+				Params: nil,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -368,6 +1021,114 @@ func TestValidator_InvalidParams(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateSetMembership(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name           string
+		answers        []string
+		caseSensitive  bool
+		receivedAnswer string
+		expectedValid  bool
+		expectError    bool
+	}{
+		{
+			name:           "Match",
+			answers:        []string{"car", "automobile", "auto"},
+			caseSensitive:  true,
+			receivedAnswer: "automobile",
+			expectedValid:  true,
+		},
+		{
+			name:           "CaseInsensitive_Match",
+			answers:        []string{"car", "automobile"},
+			caseSensitive:  false,
+			receivedAnswer: "AutoMobile",
+			expectedValid:  true,
+		},
+		{
+			name:           "CaseSensitive_NoMatch",
+			answers:        []string{"car", "automobile"},
+			caseSensitive:  true,
+			receivedAnswer: "Car",
+			expectedValid:  false,
+		},
+		{
+			name:           "NoMatch",
+			answers:        []string{"car", "automobile"},
+			caseSensitive:  false,
+			receivedAnswer: "bicycle",
+			expectedValid:  false,
+		},
+		{
+			name:           "EmptySet",
+			answers:        []string{},
+			caseSensitive:  false,
+			receivedAnswer: "car",
+			expectError:    true,
+		},
+		{
+			name:           "NilSet",
+			answers:        nil,
+			caseSensitive:  true,
+			receivedAnswer: "",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := CreateSetMembershipRule(tt.answers, tt.caseSensitive)
+
+			isValid, err := validator.ValidateAnswer(rule, tt.receivedAnswer)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if isValid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got %v", tt.expectedValid, isValid)
+			}
+		})
+	}
+}
+
+func TestValidator_CheckRule(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name    string
+		rule    models.ValidationRule
+		wantErr bool
+	}{
+		{name: "exact match", rule: CreateExactMatchRule("42", true)},
+		{name: "numeric tolerance", rule: CreateNumericToleranceRule("3.14", 0.01)},
+		{name: "regex without answer", rule: CreateRegexRule(`^\d+$`)},
+		{name: "JSON schema without answer", rule: CreateJSONSchemaRule(json.RawMessage(`{"type":"array"}`))},
+		{name: "pipeline rejecting the probe", rule: CreatePipelineRule("hello",
+			models.PipelineStage{Transform: "uppercase"},
+			models.PipelineStage{Rule: &models.ValidationRule{Type: "ExactMatch"}})},
+		{name: "unknown type", rule: models.ValidationRule{Type: "Telepathy", Answer: "x"}, wantErr: true},
+		{name: "bad regex", rule: CreateRegexRule(`([`), wantErr: true},
+		{name: "non-numeric answer", rule: CreateNumericToleranceRule("many", 1), wantErr: true},
+		{name: "missing params", rule: models.ValidationRule{Type: "FuzzyMatch", Answer: "x"}, wantErr: true},
+		{name: "unknown transform", rule: CreatePipelineRule("x", models.PipelineStage{Transform: "reverse"}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.CheckRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestCreateExactMatchRule(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -459,3 +1220,23 @@ func TestCreateRegexRule(t *testing.T) {
 		t.Errorf("Expected pattern '%s', got '%s'", pattern, params.Pattern)
 	}
 }
+
+func TestCreateJSONSchemaRule(t *testing.T) {
+	schema := json.RawMessage(`{"type":"array","items":{"type":"number"}}`)
+
+	rule := CreateJSONSchemaRule(schema)
+
+	if rule.Type != "JSONSchema" {
+		t.Errorf("Expected type 'JSONSchema', got '%s'", rule.Type)
+	}
+
+	var params models.JSONSchemaParams
+	err := json.Unmarshal(rule.Params, &params)
+	if err != nil {
+		t.Errorf("Failed to unmarshal params: %v", err)
+	}
+
+	if string(params.Schema) != string(schema) {
+		t.Errorf("Expected schema '%s', got '%s'", schema, params.Schema)
+	}
+}
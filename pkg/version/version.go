@@ -0,0 +1,46 @@
+// Package version reports what a running service was built from and which API version it speaks.
+// Version, Commit and BuildDate are set at build time, e.g.
+//
+//	go build -ldflags "-X reverse-challenge-system/pkg/version.Version=v1.2.0 \
+//	  -X reverse-challenge-system/pkg/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X reverse-challenge-system/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// APIVersion is the challenger/solver protocol version sent in solve requests and callbacks
+const APIVersion = "v2.1"
+
+// Build information, overridden with -ldflags "-X"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info is the body served by GET /version
+type Info struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	BuildDate  string `json:"build_date"`
+	APIVersion string `json:"api_version"`
+}
+
+// Get returns the build information of the running binary
+func Get() Info {
+	return Info{
+		Version:    Version,
+		Commit:     Commit,
+		BuildDate:  BuildDate,
+		APIVersion: APIVersion,
+	}
+}
+
+// Handler serves GET /version
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Get())
+}
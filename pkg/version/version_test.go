@@ -0,0 +1,42 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	Version, Commit, BuildDate = "v1.2.0", "abc1234", "2026-01-02T03:04:05Z"
+	t.Cleanup(func() { Version, Commit, BuildDate = "dev", "unknown", "unknown" })
+
+	w := httptest.NewRecorder()
+	Handler(w, httptest.NewRequest("GET", "/version", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", w.Header().Get("Content-Type"))
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := map[string]string{
+		"version":     "v1.2.0",
+		"commit":      "abc1234",
+		"build_date":  "2026-01-02T03:04:05Z",
+		"api_version": "v2.1",
+	}
+	if len(body) != len(want) {
+		t.Errorf("Expected exactly %d fields, got %v", len(want), body)
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Errorf("Expected %s %q, got %q", key, value, body[key])
+		}
+	}
+}
@@ -0,0 +1,386 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v6.32.0
+// source: solver_api.proto
+
+package solverapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SolveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiVersion    string                 `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`    // API version for compatibility checking
+	ChallengeId   string                 `protobuf:"bytes,2,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"` // Required
+	Problem       string                 `protobuf:"bytes,3,opt,name=problem,proto3" json:"problem,omitempty"`                            // Challenge-specific problem data (JSON object)
+	OutputSpec    string                 `protobuf:"bytes,4,opt,name=output_spec,json=outputSpec,proto3" json:"output_spec,omitempty"`    // Expected output format specification (JSON object)
+	CallbackUrl   string                 `protobuf:"bytes,5,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"` // URL where the solver sends the result
+	TimeoutMs     int64                  `protobuf:"varint,6,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`      // Maximum processing time in milliseconds
+	DeadlineTs    int64                  `protobuf:"varint,7,opt,name=deadline_ts,json=deadlineTs,proto3" json:"deadline_ts,omitempty"`   // Unix timestamp deadline for completion
+	Priority      int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`                         // Higher values are dispatched first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveRequest) Reset() {
+	*x = SolveRequest{}
+	mi := &file_solver_api_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveRequest) ProtoMessage() {}
+
+func (x *SolveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_solver_api_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveRequest.ProtoReflect.Descriptor instead.
+func (*SolveRequest) Descriptor() ([]byte, []int) {
+	return file_solver_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SolveRequest) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *SolveRequest) GetChallengeId() string {
+	if x != nil {
+		return x.ChallengeId
+	}
+	return ""
+}
+
+func (x *SolveRequest) GetProblem() string {
+	if x != nil {
+		return x.Problem
+	}
+	return ""
+}
+
+func (x *SolveRequest) GetOutputSpec() string {
+	if x != nil {
+		return x.OutputSpec
+	}
+	return ""
+}
+
+func (x *SolveRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *SolveRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetDeadlineTs() int64 {
+	if x != nil {
+		return x.DeadlineTs
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type SolveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	SolverJobId   string                 `protobuf:"bytes,2,opt,name=solver_job_id,json=solverJobId,proto3" json:"solver_job_id,omitempty"`
+	ApiVersion    string                 `protobuf:"bytes,3,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"` // API version the solver accepted the request under
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveResponse) Reset() {
+	*x = SolveResponse{}
+	mi := &file_solver_api_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveResponse) ProtoMessage() {}
+
+func (x *SolveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_solver_api_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveResponse.ProtoReflect.Descriptor instead.
+func (*SolveResponse) Descriptor() ([]byte, []int) {
+	return file_solver_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SolveResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SolveResponse) GetSolverJobId() string {
+	if x != nil {
+		return x.SolverJobId
+	}
+	return ""
+}
+
+func (x *SolveResponse) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+type ChallengeId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeId   string                 `protobuf:"bytes,1,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChallengeId) Reset() {
+	*x = ChallengeId{}
+	mi := &file_solver_api_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengeId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengeId) ProtoMessage() {}
+
+func (x *ChallengeId) ProtoReflect() protoreflect.Message {
+	mi := &file_solver_api_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengeId.ProtoReflect.Descriptor instead.
+func (*ChallengeId) Descriptor() ([]byte, []int) {
+	return file_solver_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChallengeId) GetChallengeId() string {
+	if x != nil {
+		return x.ChallengeId
+	}
+	return ""
+}
+
+type Status struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeId   string                 `protobuf:"bytes,1,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                                      // "pending", "processing", "failed", "expired", or "completed"
+	AttemptCount  int32                  `protobuf:"varint,3,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`     // Processing attempts made so far
+	NextRetryTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=next_retry_time,json=nextRetryTime,proto3" json:"next_retry_time,omitempty"` // Unset once the challenge has left the queue
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_solver_api_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_solver_api_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_solver_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Status) GetChallengeId() string {
+	if x != nil {
+		return x.ChallengeId
+	}
+	return ""
+}
+
+func (x *Status) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Status) GetAttemptCount() int32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
+}
+
+func (x *Status) GetNextRetryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRetryTime
+	}
+	return nil
+}
+
+var File_solver_api_proto protoreflect.FileDescriptor
+
+const file_solver_api_proto_rawDesc = "" +
+	"\n" +
+	"\x10solver_api.proto\x12\tsolverapi\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8c\x02\n" +
+	"\fSolveRequest\x12\x1f\n" +
+	"\vapi_version\x18\x01 \x01(\tR\n" +
+	"apiVersion\x12!\n" +
+	"\fchallenge_id\x18\x02 \x01(\tR\vchallengeId\x12\x18\n" +
+	"\aproblem\x18\x03 \x01(\tR\aproblem\x12\x1f\n" +
+	"\voutput_spec\x18\x04 \x01(\tR\n" +
+	"outputSpec\x12!\n" +
+	"\fcallback_url\x18\x05 \x01(\tR\vcallbackUrl\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x06 \x01(\x03R\ttimeoutMs\x12\x1f\n" +
+	"\vdeadline_ts\x18\a \x01(\x03R\n" +
+	"deadlineTs\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\"n\n" +
+	"\rSolveResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\"\n" +
+	"\rsolver_job_id\x18\x02 \x01(\tR\vsolverJobId\x12\x1f\n" +
+	"\vapi_version\x18\x03 \x01(\tR\n" +
+	"apiVersion\"0\n" +
+	"\vChallengeId\x12!\n" +
+	"\fchallenge_id\x18\x01 \x01(\tR\vchallengeId\"\xac\x01\n" +
+	"\x06Status\x12!\n" +
+	"\fchallenge_id\x18\x01 \x01(\tR\vchallengeId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12#\n" +
+	"\rattempt_count\x18\x03 \x01(\x05R\fattemptCount\x12B\n" +
+	"\x0fnext_retry_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rnextRetryTime2}\n" +
+	"\x06Solver\x12;\n" +
+	"\x06Submit\x12\x17.solverapi.SolveRequest\x1a\x18.solverapi.SolveResponse\x126\n" +
+	"\tGetStatus\x12\x16.solverapi.ChallengeId\x1a\x11.solverapi.StatusB\x1bZ\x19proto/solverapi;solverapib\x06proto3"
+
+var (
+	file_solver_api_proto_rawDescOnce sync.Once
+	file_solver_api_proto_rawDescData []byte
+)
+
+func file_solver_api_proto_rawDescGZIP() []byte {
+	file_solver_api_proto_rawDescOnce.Do(func() {
+		file_solver_api_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_solver_api_proto_rawDesc), len(file_solver_api_proto_rawDesc)))
+	})
+	return file_solver_api_proto_rawDescData
+}
+
+var file_solver_api_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_solver_api_proto_goTypes = []any{
+	(*SolveRequest)(nil),          // 0: solverapi.SolveRequest
+	(*SolveResponse)(nil),         // 1: solverapi.SolveResponse
+	(*ChallengeId)(nil),           // 2: solverapi.ChallengeId
+	(*Status)(nil),                // 3: solverapi.Status
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_solver_api_proto_depIdxs = []int32{
+	4, // 0: solverapi.Status.next_retry_time:type_name -> google.protobuf.Timestamp
+	0, // 1: solverapi.Solver.Submit:input_type -> solverapi.SolveRequest
+	2, // 2: solverapi.Solver.GetStatus:input_type -> solverapi.ChallengeId
+	1, // 3: solverapi.Solver.Submit:output_type -> solverapi.SolveResponse
+	3, // 4: solverapi.Solver.GetStatus:output_type -> solverapi.Status
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_solver_api_proto_init() }
+func file_solver_api_proto_init() {
+	if File_solver_api_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_solver_api_proto_rawDesc), len(file_solver_api_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_solver_api_proto_goTypes,
+		DependencyIndexes: file_solver_api_proto_depIdxs,
+		MessageInfos:      file_solver_api_proto_msgTypes,
+	}.Build()
+	File_solver_api_proto = out.File
+	file_solver_api_proto_goTypes = nil
+	file_solver_api_proto_depIdxs = nil
+}